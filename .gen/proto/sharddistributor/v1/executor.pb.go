@@ -11,6 +11,7 @@ import (
 	math_bits "math/bits"
 
 	proto "github.com/gogo/protobuf/proto"
+	types "github.com/gogo/protobuf/types"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -55,6 +56,38 @@ func (ExecutorStatus) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_5aab034437d08cca, []int{0}
 }
 
+// Executors that do not report a role are workers.
+type ExecutorRole int32
+
+const (
+	ExecutorRole_EXECUTOR_ROLE_INVALID  ExecutorRole = 0
+	ExecutorRole_EXECUTOR_ROLE_WORKER   ExecutorRole = 1
+	ExecutorRole_EXECUTOR_ROLE_OBSERVER ExecutorRole = 2
+	ExecutorRole_EXECUTOR_ROLE_STANDBY  ExecutorRole = 3
+)
+
+var ExecutorRole_name = map[int32]string{
+	0: "EXECUTOR_ROLE_INVALID",
+	1: "EXECUTOR_ROLE_WORKER",
+	2: "EXECUTOR_ROLE_OBSERVER",
+	3: "EXECUTOR_ROLE_STANDBY",
+}
+
+var ExecutorRole_value = map[string]int32{
+	"EXECUTOR_ROLE_INVALID":  0,
+	"EXECUTOR_ROLE_WORKER":   1,
+	"EXECUTOR_ROLE_OBSERVER": 2,
+	"EXECUTOR_ROLE_STANDBY":  3,
+}
+
+func (x ExecutorRole) String() string {
+	return proto.EnumName(ExecutorRole_name, int32(x))
+}
+
+func (ExecutorRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5aab034437d08cca, []int{1}
+}
+
 // We only have one status for now, but when adding
 // graceful handover, we will need to add more statuses.
 // We do not need an "inactive" status, as we will not include
@@ -62,21 +95,27 @@ func (ExecutorStatus) EnumDescriptor() ([]byte, []int) {
 type ShardStatus int32
 
 const (
-	ShardStatus_SHARD_STATUS_INVALID ShardStatus = 0
-	ShardStatus_SHARD_STATUS_READY   ShardStatus = 1
-	ShardStatus_SHARD_STATUS_DONE    ShardStatus = 2
+	ShardStatus_SHARD_STATUS_INVALID   ShardStatus = 0
+	ShardStatus_SHARD_STATUS_READY     ShardStatus = 1
+	ShardStatus_SHARD_STATUS_DONE      ShardStatus = 2
+	ShardStatus_SHARD_STATUS_DRAINING  ShardStatus = 3
+	ShardStatus_SHARD_STATUS_PREPARING ShardStatus = 4
 )
 
 var ShardStatus_name = map[int32]string{
 	0: "SHARD_STATUS_INVALID",
 	1: "SHARD_STATUS_READY",
 	2: "SHARD_STATUS_DONE",
+	3: "SHARD_STATUS_DRAINING",
+	4: "SHARD_STATUS_PREPARING",
 }
 
 var ShardStatus_value = map[string]int32{
-	"SHARD_STATUS_INVALID": 0,
-	"SHARD_STATUS_READY":   1,
-	"SHARD_STATUS_DONE":    2,
+	"SHARD_STATUS_INVALID":   0,
+	"SHARD_STATUS_READY":     1,
+	"SHARD_STATUS_DONE":      2,
+	"SHARD_STATUS_DRAINING":  3,
+	"SHARD_STATUS_PREPARING": 4,
 }
 
 func (x ShardStatus) String() string {
@@ -84,7 +123,47 @@ func (x ShardStatus) String() string {
 }
 
 func (ShardStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_5aab034437d08cca, []int{1}
+	return fileDescriptor_5aab034437d08cca, []int{2}
+}
+
+type HeartbeatReasonCode int32
+
+const (
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_INVALID               HeartbeatReasonCode = 0
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_ACCEPTED              HeartbeatReasonCode = 1
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_THROTTLED             HeartbeatReasonCode = 2
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_STATUS_CHANGE_APPLIED HeartbeatReasonCode = 3
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_DUPLICATE             HeartbeatReasonCode = 4
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_PASSTHROUGH           HeartbeatReasonCode = 5
+	HeartbeatReasonCode_HEARTBEAT_REASON_CODE_READ_ONLY             HeartbeatReasonCode = 6
+)
+
+var HeartbeatReasonCode_name = map[int32]string{
+	0: "HEARTBEAT_REASON_CODE_INVALID",
+	1: "HEARTBEAT_REASON_CODE_ACCEPTED",
+	2: "HEARTBEAT_REASON_CODE_THROTTLED",
+	3: "HEARTBEAT_REASON_CODE_STATUS_CHANGE_APPLIED",
+	4: "HEARTBEAT_REASON_CODE_DUPLICATE",
+	5: "HEARTBEAT_REASON_CODE_PASSTHROUGH",
+	6: "HEARTBEAT_REASON_CODE_READ_ONLY",
+}
+
+var HeartbeatReasonCode_value = map[string]int32{
+	"HEARTBEAT_REASON_CODE_INVALID":               0,
+	"HEARTBEAT_REASON_CODE_ACCEPTED":              1,
+	"HEARTBEAT_REASON_CODE_THROTTLED":             2,
+	"HEARTBEAT_REASON_CODE_STATUS_CHANGE_APPLIED": 3,
+	"HEARTBEAT_REASON_CODE_DUPLICATE":             4,
+	"HEARTBEAT_REASON_CODE_PASSTHROUGH":           5,
+	"HEARTBEAT_REASON_CODE_READ_ONLY":             6,
+}
+
+func (x HeartbeatReasonCode) String() string {
+	return proto.EnumName(HeartbeatReasonCode_name, int32(x))
+}
+
+func (HeartbeatReasonCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5aab034437d08cca, []int{3}
 }
 
 // We only have one status for now, but when adding
@@ -94,18 +173,24 @@ func (ShardStatus) EnumDescriptor() ([]byte, []int) {
 type AssignmentStatus int32
 
 const (
-	AssignmentStatus_ASSIGNMENT_STATUS_INVALID AssignmentStatus = 0
-	AssignmentStatus_ASSIGNMENT_STATUS_READY   AssignmentStatus = 1
+	AssignmentStatus_ASSIGNMENT_STATUS_INVALID   AssignmentStatus = 0
+	AssignmentStatus_ASSIGNMENT_STATUS_READY     AssignmentStatus = 1
+	AssignmentStatus_ASSIGNMENT_STATUS_PREPARING AssignmentStatus = 2
+	AssignmentStatus_ASSIGNMENT_STATUS_DRAINING  AssignmentStatus = 3
 )
 
 var AssignmentStatus_name = map[int32]string{
 	0: "ASSIGNMENT_STATUS_INVALID",
 	1: "ASSIGNMENT_STATUS_READY",
+	2: "ASSIGNMENT_STATUS_PREPARING",
+	3: "ASSIGNMENT_STATUS_DRAINING",
 }
 
 var AssignmentStatus_value = map[string]int32{
-	"ASSIGNMENT_STATUS_INVALID": 0,
-	"ASSIGNMENT_STATUS_READY":   1,
+	"ASSIGNMENT_STATUS_INVALID":   0,
+	"ASSIGNMENT_STATUS_READY":     1,
+	"ASSIGNMENT_STATUS_PREPARING": 2,
+	"ASSIGNMENT_STATUS_DRAINING":  3,
 }
 
 func (x AssignmentStatus) String() string {
@@ -113,7 +198,7 @@ func (x AssignmentStatus) String() string {
 }
 
 func (AssignmentStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_5aab034437d08cca, []int{2}
+	return fileDescriptor_5aab034437d08cca, []int{4}
 }
 
 // We handle  the migration steps from SD side
@@ -142,18 +227,33 @@ func (x MigrationMode) String() string {
 }
 
 func (MigrationMode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_5aab034437d08cca, []int{3}
+	return fileDescriptor_5aab034437d08cca, []int{5}
 }
 
 type HeartbeatRequest struct {
-	Namespace            string                        `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	ExecutorId           string                        `protobuf:"bytes,2,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
-	Status               ExecutorStatus                `protobuf:"varint,3,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.ExecutorStatus" json:"status,omitempty"`
-	ShardStatusReports   map[string]*ShardStatusReport `protobuf:"bytes,4,rep,name=shard_status_reports,json=shardStatusReports,proto3" json:"shard_status_reports,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Metadata             map[string]string             `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Namespace          string                        `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	ExecutorId         string                        `protobuf:"bytes,2,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	Status             ExecutorStatus                `protobuf:"varint,3,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.ExecutorStatus" json:"status,omitempty"`
+	ShardStatusReports map[string]*ShardStatusReport `protobuf:"bytes,4,rep,name=shard_status_reports,json=shardStatusReports,proto3" json:"shard_status_reports,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Metadata           map[string]string             `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Spare capacity the executor reports, e.g. its free CPU. Zero means it is not reported.
+	Headroom float64 `protobuf:"fixed64,6,opt,name=headroom,proto3" json:"headroom,omitempty"`
+	// Marks shard_status_reports as holding only the shards whose report changed since the previous heartbeat.
+	IsDeltaReport bool         `protobuf:"varint,7,opt,name=is_delta_report,json=isDeltaReport,proto3" json:"is_delta_report,omitempty"`
+	Role          ExecutorRole `protobuf:"varint,8,opt,name=role,proto3,enum=uber.cadence.sharddistributor.v1.ExecutorRole" json:"role,omitempty"`
+	// Shard status reports in the compact binary encoding of the reportcodec package, used instead of shard_status_reports.
+	EncodedShardStatusReports []byte            `protobuf:"bytes,9,opt,name=encoded_shard_status_reports,json=encodedShardStatusReports,proto3" json:"encoded_shard_status_reports,omitempty"`
+	Labels                    map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Optional idempotency key that increases with every heartbeat of the executor incarnation.
+	SequenceNumber int64  `protobuf:"varint,11,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	IncarnationId  string `protobuf:"bytes,12,opt,name=incarnation_id,json=incarnationId,proto3" json:"incarnation_id,omitempty"`
+	// Total load of the executor, for executors that cannot attribute their load to shards.
+	ExecutorLoad *types.DoubleValue `protobuf:"bytes,13,opt,name=executor_load,json=executorLoad,proto3" json:"executor_load,omitempty"`
+	// Asks for shards_to_start and shards_to_stop in the response instead of the full shard assignment.
+	DeltaResponse        bool     `protobuf:"varint,14,opt,name=delta_response,json=deltaResponse,proto3" json:"delta_response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
@@ -224,12 +324,78 @@ func (m *HeartbeatRequest) GetMetadata() map[string]string {
 	return nil
 }
 
+func (m *HeartbeatRequest) GetHeadroom() float64 {
+	if m != nil {
+		return m.Headroom
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetIsDeltaReport() bool {
+	if m != nil {
+		return m.IsDeltaReport
+	}
+	return false
+}
+
+func (m *HeartbeatRequest) GetRole() ExecutorRole {
+	if m != nil {
+		return m.Role
+	}
+	return ExecutorRole_EXECUTOR_ROLE_INVALID
+}
+
+func (m *HeartbeatRequest) GetEncodedShardStatusReports() []byte {
+	if m != nil {
+		return m.EncodedShardStatusReports
+	}
+	return nil
+}
+
+func (m *HeartbeatRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *HeartbeatRequest) GetSequenceNumber() int64 {
+	if m != nil {
+		return m.SequenceNumber
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetIncarnationId() string {
+	if m != nil {
+		return m.IncarnationId
+	}
+	return ""
+}
+
+func (m *HeartbeatRequest) GetExecutorLoad() *types.DoubleValue {
+	if m != nil {
+		return m.ExecutorLoad
+	}
+	return nil
+}
+
+func (m *HeartbeatRequest) GetDeltaResponse() bool {
+	if m != nil {
+		return m.DeltaResponse
+	}
+	return false
+}
+
 type ShardStatusReport struct {
-	Status               ShardStatus `protobuf:"varint,1,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.ShardStatus" json:"status,omitempty"`
-	ShardLoad            float64     `protobuf:"fixed64,2,opt,name=shard_load,json=shardLoad,proto3" json:"shard_load,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Status    ShardStatus `protobuf:"varint,1,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.ShardStatus" json:"status,omitempty"`
+	ShardLoad float64     `protobuf:"fixed64,2,opt,name=shard_load,json=shardLoad,proto3" json:"shard_load,omitempty"`
+	// Load of the shard per named dimension (e.g. cpu, memory).
+	ShardLoads           map[string]float64 `protobuf:"bytes,3,rep,name=shard_loads,json=shardLoads,proto3" json:"shard_loads,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	ReportTime           *types.Timestamp   `protobuf:"bytes,4,opt,name=report_time,json=reportTime,proto3" json:"report_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *ShardStatusReport) Reset()         { *m = ShardStatusReport{} }
@@ -279,12 +445,30 @@ func (m *ShardStatusReport) GetShardLoad() float64 {
 	return 0
 }
 
+func (m *ShardStatusReport) GetShardLoads() map[string]float64 {
+	if m != nil {
+		return m.ShardLoads
+	}
+	return nil
+}
+
+func (m *ShardStatusReport) GetReportTime() *types.Timestamp {
+	if m != nil {
+		return m.ReportTime
+	}
+	return nil
+}
+
 type HeartbeatResponse struct {
-	ShardAssignments     map[string]*ShardAssignment `protobuf:"bytes,1,rep,name=shard_assignments,json=shardAssignments,proto3" json:"shard_assignments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	MigrationMode        MigrationMode               `protobuf:"varint,2,opt,name=migration_mode,json=migrationMode,proto3,enum=uber.cadence.sharddistributor.v1.MigrationMode" json:"migration_mode,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
-	XXX_unrecognized     []byte                      `json:"-"`
-	XXX_sizecache        int32                       `json:"-"`
+	ShardAssignments map[string]*ShardAssignment `protobuf:"bytes,1,rep,name=shard_assignments,json=shardAssignments,proto3" json:"shard_assignments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MigrationMode    MigrationMode               `protobuf:"varint,2,opt,name=migration_mode,json=migrationMode,proto3,enum=uber.cadence.sharddistributor.v1.MigrationMode" json:"migration_mode,omitempty"`
+	ReasonCode       HeartbeatReasonCode         `protobuf:"varint,3,opt,name=reason_code,json=reasonCode,proto3,enum=uber.cadence.sharddistributor.v1.HeartbeatReasonCode" json:"reason_code,omitempty"`
+	// Set instead of shard_assignments when the request asked for a delta response.
+	ShardsToStart        []string `protobuf:"bytes,4,rep,name=shards_to_start,json=shardsToStart,proto3" json:"shards_to_start,omitempty"`
+	ShardsToStop         []string `protobuf:"bytes,5,rep,name=shards_to_stop,json=shardsToStop,proto3" json:"shards_to_stop,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *HeartbeatResponse) Reset()         { *m = HeartbeatResponse{} }
@@ -334,8 +518,32 @@ func (m *HeartbeatResponse) GetMigrationMode() MigrationMode {
 	return MigrationMode_MIGRATION_MODE_INVALID
 }
 
+func (m *HeartbeatResponse) GetReasonCode() HeartbeatReasonCode {
+	if m != nil {
+		return m.ReasonCode
+	}
+	return HeartbeatReasonCode_HEARTBEAT_REASON_CODE_INVALID
+}
+
+func (m *HeartbeatResponse) GetShardsToStart() []string {
+	if m != nil {
+		return m.ShardsToStart
+	}
+	return nil
+}
+
+func (m *HeartbeatResponse) GetShardsToStop() []string {
+	if m != nil {
+		return m.ShardsToStop
+	}
+	return nil
+}
+
 type ShardAssignment struct {
-	Status               AssignmentStatus `protobuf:"varint,1,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.AssignmentStatus" json:"status,omitempty"`
+	Status     AssignmentStatus `protobuf:"varint,1,opt,name=status,proto3,enum=uber.cadence.sharddistributor.v1.AssignmentStatus" json:"status,omitempty"`
+	AssignedAt *types.Timestamp `protobuf:"bytes,2,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`
+	// Time after which the executor must stop processing the shard unless a later heartbeat renewed the lease.
+	LeaseExpiresAt       *types.Timestamp `protobuf:"bytes,3,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -381,15 +589,33 @@ func (m *ShardAssignment) GetStatus() AssignmentStatus {
 	return AssignmentStatus_ASSIGNMENT_STATUS_INVALID
 }
 
+func (m *ShardAssignment) GetAssignedAt() *types.Timestamp {
+	if m != nil {
+		return m.AssignedAt
+	}
+	return nil
+}
+
+func (m *ShardAssignment) GetLeaseExpiresAt() *types.Timestamp {
+	if m != nil {
+		return m.LeaseExpiresAt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("uber.cadence.sharddistributor.v1.ExecutorStatus", ExecutorStatus_name, ExecutorStatus_value)
+	proto.RegisterEnum("uber.cadence.sharddistributor.v1.ExecutorRole", ExecutorRole_name, ExecutorRole_value)
 	proto.RegisterEnum("uber.cadence.sharddistributor.v1.ShardStatus", ShardStatus_name, ShardStatus_value)
+	proto.RegisterEnum("uber.cadence.sharddistributor.v1.HeartbeatReasonCode", HeartbeatReasonCode_name, HeartbeatReasonCode_value)
 	proto.RegisterEnum("uber.cadence.sharddistributor.v1.AssignmentStatus", AssignmentStatus_name, AssignmentStatus_value)
 	proto.RegisterEnum("uber.cadence.sharddistributor.v1.MigrationMode", MigrationMode_name, MigrationMode_value)
 	proto.RegisterType((*HeartbeatRequest)(nil), "uber.cadence.sharddistributor.v1.HeartbeatRequest")
+	proto.RegisterMapType((map[string]string)(nil), "uber.cadence.sharddistributor.v1.HeartbeatRequest.LabelsEntry")
 	proto.RegisterMapType((map[string]string)(nil), "uber.cadence.sharddistributor.v1.HeartbeatRequest.MetadataEntry")
 	proto.RegisterMapType((map[string]*ShardStatusReport)(nil), "uber.cadence.sharddistributor.v1.HeartbeatRequest.ShardStatusReportsEntry")
 	proto.RegisterType((*ShardStatusReport)(nil), "uber.cadence.sharddistributor.v1.ShardStatusReport")
+	proto.RegisterMapType((map[string]float64)(nil), "uber.cadence.sharddistributor.v1.ShardStatusReport.ShardLoadsEntry")
 	proto.RegisterType((*HeartbeatResponse)(nil), "uber.cadence.sharddistributor.v1.HeartbeatResponse")
 	proto.RegisterMapType((map[string]*ShardAssignment)(nil), "uber.cadence.sharddistributor.v1.HeartbeatResponse.ShardAssignmentsEntry")
 	proto.RegisterType((*ShardAssignment)(nil), "uber.cadence.sharddistributor.v1.ShardAssignment")
@@ -400,55 +626,92 @@ func init() {
 }
 
 var fileDescriptor_5aab034437d08cca = []byte{
-	// 761 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xcd, 0x6e, 0xda, 0x58,
-	0x14, 0x1e, 0x03, 0x89, 0xc2, 0x41, 0x61, 0xcc, 0x55, 0x7e, 0x18, 0xf2, 0x33, 0x08, 0x8d, 0x66,
-	0x22, 0xa4, 0x31, 0x03, 0xd9, 0x8c, 0x26, 0x9b, 0x71, 0x62, 0x0b, 0x1c, 0x81, 0x1d, 0x5d, 0x1b,
-	0x32, 0x53, 0xb5, 0xb2, 0x2e, 0xf8, 0x8a, 0xa0, 0x06, 0x9b, 0xda, 0x17, 0xd4, 0x54, 0x5d, 0xf6,
-	0x0d, 0xfa, 0x22, 0x7d, 0x8c, 0x2e, 0xba, 0xe8, 0xbe, 0x9b, 0x2a, 0x4f, 0x52, 0x61, 0x43, 0xb0,
-	0x0d, 0x15, 0x4d, 0x76, 0xf6, 0x39, 0xe7, 0xfb, 0xbe, 0xe3, 0xf3, 0x9d, 0xeb, 0x0b, 0x95, 0x71,
-	0x97, 0xba, 0x95, 0x1e, 0xb1, 0xa8, 0xdd, 0xa3, 0x15, 0xef, 0x86, 0xb8, 0x96, 0x35, 0xf0, 0x98,
-	0x3b, 0xe8, 0x8e, 0x99, 0xe3, 0x56, 0x26, 0xd5, 0x0a, 0x7d, 0x4d, 0x7b, 0xd3, 0x67, 0x61, 0xe4,
-	0x3a, 0xcc, 0x41, 0xc5, 0x29, 0x40, 0x98, 0x01, 0x84, 0x38, 0x40, 0x98, 0x54, 0x4b, 0x1f, 0x52,
-	0xc0, 0x37, 0x28, 0x71, 0x59, 0x97, 0x12, 0x86, 0xe9, 0xab, 0x31, 0xf5, 0x18, 0x3a, 0x84, 0xb4,
-	0x4d, 0x86, 0xd4, 0x1b, 0x91, 0x1e, 0xcd, 0x73, 0x45, 0xee, 0x24, 0x8d, 0x17, 0x01, 0xf4, 0x2b,
-	0x64, 0xe6, 0x32, 0xe6, 0xc0, 0xca, 0x27, 0xfc, 0x3c, 0xcc, 0x43, 0x8a, 0x85, 0x1a, 0xb0, 0xe9,
-	0x31, 0xc2, 0xc6, 0x5e, 0x3e, 0x59, 0xe4, 0x4e, 0xb2, 0xb5, 0xbf, 0x84, 0x75, 0x6d, 0x08, 0xf2,
-	0x0c, 0xad, 0xfb, 0x38, 0x3c, 0xc3, 0xa3, 0xb7, 0xb0, 0xe3, 0x57, 0x9b, 0xc1, 0xbb, 0xe9, 0xd2,
-	0x91, 0xe3, 0x32, 0x2f, 0x9f, 0x2a, 0x26, 0x4f, 0x32, 0xb5, 0xcb, 0xf5, 0xbc, 0xf1, 0x4f, 0x13,
-	0xf4, 0x69, 0xd1, 0x4c, 0x25, 0x20, 0x93, 0x6d, 0xe6, 0xde, 0x61, 0xe4, 0x2d, 0x25, 0xd0, 0x73,
-	0xd8, 0x1a, 0x52, 0x46, 0x2c, 0xc2, 0x48, 0x7e, 0xc3, 0x57, 0xfc, 0xf7, 0x09, 0x8a, 0xad, 0x19,
-	0x45, 0xa0, 0xf3, 0xc0, 0x58, 0x78, 0x03, 0xfb, 0xdf, 0x69, 0x06, 0xf1, 0x90, 0x7c, 0x49, 0xef,
-	0x66, 0x93, 0x9f, 0x3e, 0x22, 0x05, 0x36, 0x26, 0xe4, 0x76, 0x4c, 0xfd, 0x69, 0x67, 0x6a, 0xa7,
-	0xeb, 0xfb, 0x58, 0xe2, 0xc6, 0x01, 0xc3, 0x3f, 0x89, 0xbf, 0xb9, 0xc2, 0x19, 0x6c, 0x47, 0xda,
-	0x5a, 0xa1, 0xb8, 0x13, 0x56, 0x4c, 0x87, 0xc0, 0xa5, 0x3b, 0xc8, 0x2d, 0x91, 0x23, 0xf9, 0xc1,
-	0x73, 0xce, 0xf7, 0xfc, 0xcf, 0xc7, 0x75, 0x38, 0x37, 0xfc, 0x08, 0x20, 0x30, 0xfc, 0xd6, 0x21,
-	0xc1, 0x6a, 0x71, 0x38, 0xed, 0x47, 0x9a, 0x0e, 0xb1, 0x4a, 0x5f, 0x12, 0x90, 0x0b, 0x0d, 0xd8,
-	0x1b, 0x39, 0xb6, 0x47, 0xd1, 0x04, 0x72, 0x01, 0x88, 0x78, 0xde, 0xa0, 0x6f, 0x0f, 0xa9, 0xcd,
-	0xa6, 0x6d, 0x4c, 0x0d, 0x53, 0x1e, 0x65, 0x58, 0xc0, 0x17, 0x34, 0x26, 0x2e, 0xb8, 0x02, 0xe7,
-	0x78, 0x2f, 0x16, 0x46, 0x1d, 0xc8, 0x0e, 0x07, 0x7d, 0x97, 0xb0, 0x81, 0x63, 0x9b, 0x43, 0xc7,
-	0x0a, 0x66, 0x95, 0xad, 0x55, 0xd6, 0x8b, 0xb6, 0xe6, 0xb8, 0x96, 0x63, 0x51, 0xbc, 0x3d, 0x0c,
-	0xbf, 0x16, 0x26, 0xb0, 0xbb, 0xb2, 0x85, 0x15, 0x2e, 0xd5, 0xa3, 0x7b, 0x51, 0xfd, 0xc1, 0xa9,
-	0x2f, 0x98, 0xc3, 0xc6, 0xbe, 0x80, 0x9f, 0x63, 0x59, 0x74, 0x19, 0xb3, 0xb5, 0xb6, 0x5e, 0x60,
-	0x81, 0x8e, 0x7a, 0x5b, 0x7e, 0xc7, 0x41, 0x36, 0x7a, 0xce, 0xd1, 0x01, 0xec, 0xcb, 0xff, 0xc9,
-	0x17, 0x6d, 0x43, 0xc3, 0xa6, 0x6e, 0x88, 0x46, 0x5b, 0x37, 0x15, 0xb5, 0x23, 0x36, 0x15, 0x89,
-	0xff, 0x09, 0x15, 0x60, 0x2f, 0x9e, 0x14, 0x2f, 0x0c, 0xa5, 0x23, 0xf3, 0x1c, 0x3a, 0x84, 0x7c,
-	0x3c, 0x27, 0x61, 0x51, 0x51, 0x15, 0xb5, 0xce, 0x27, 0x56, 0xd1, 0xfa, 0x59, 0x59, 0xe2, 0x93,
-	0xe5, 0x0e, 0x64, 0x42, 0x9b, 0x87, 0xf2, 0xb0, 0xa3, 0x37, 0x44, 0x2c, 0x2d, 0xeb, 0xef, 0x01,
-	0x8a, 0x64, 0xb0, 0x2c, 0x4a, 0xff, 0xf3, 0x1c, 0xda, 0x85, 0x5c, 0x24, 0x2e, 0x69, 0xaa, 0xcc,
-	0x27, 0xca, 0x2a, 0xf0, 0xf1, 0x4f, 0x47, 0x47, 0xf0, 0x8b, 0xa8, 0xeb, 0x4a, 0x5d, 0x6d, 0xc9,
-	0xaa, 0xb1, 0xac, 0x70, 0x00, 0xfb, 0xcb, 0xe9, 0x99, 0x4c, 0xf9, 0x13, 0x07, 0xdb, 0x91, 0x35,
-	0x99, 0x0e, 0xa4, 0xa5, 0xd4, 0xb1, 0x68, 0x28, 0x9a, 0x6a, 0xb6, 0x34, 0x49, 0x0e, 0x51, 0xfd,
-	0x06, 0xc5, 0x58, 0xae, 0xa9, 0x5d, 0x88, 0x4d, 0xf3, 0x4a, 0xd4, 0x75, 0xa3, 0x81, 0xb5, 0x76,
-	0xbd, 0x11, 0x8c, 0x2d, 0x56, 0xa5, 0xa9, 0xe7, 0x9a, 0x88, 0x25, 0x59, 0xe2, 0x53, 0xa5, 0xd4,
-	0x56, 0x82, 0x4f, 0x94, 0x52, 0x5b, 0x49, 0x3e, 0x59, 0xfe, 0x63, 0x1d, 0x9b, 0xa9, 0x37, 0x44,
-	0x49, 0xbb, 0x2e, 0xff, 0x1e, 0x2b, 0x94, 0x14, 0xdd, 0xc0, 0xca, 0x79, 0xdb, 0x90, 0xa5, 0x70,
-	0x79, 0xed, 0x3d, 0x07, 0x07, 0xfe, 0xdc, 0xa5, 0xc5, 0xba, 0xcc, 0xb7, 0x41, 0xbc, 0x52, 0x10,
-	0x83, 0xf4, 0xc3, 0x49, 0x44, 0xb5, 0xc7, 0xff, 0x67, 0x0b, 0xa7, 0x4f, 0x38, 0xea, 0xe7, 0xd7,
-	0x1f, 0xef, 0x8f, 0xb9, 0xcf, 0xf7, 0xc7, 0xdc, 0xd7, 0xfb, 0x63, 0xee, 0x99, 0xd2, 0x1f, 0xb0,
-	0x9b, 0x71, 0x57, 0xe8, 0x39, 0xc3, 0xe8, 0x55, 0x2b, 0xf4, 0xa9, 0x5d, 0xf1, 0xaf, 0xd4, 0x55,
-	0xb7, 0xee, 0x59, 0x3c, 0x36, 0xa9, 0x76, 0x37, 0xfd, 0xea, 0xd3, 0x6f, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0xb0, 0xe4, 0x70, 0x38, 0xb3, 0x07, 0x00, 0x00,
+	// 1347 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xdd, 0x6f, 0xda, 0x56,
+	0x14, 0x9f, 0x81, 0x66, 0xe1, 0x10, 0x88, 0x73, 0xd7, 0x0f, 0x97, 0xa4, 0x09, 0xcd, 0xfa, 0x81,
+	0x98, 0x66, 0x16, 0xaa, 0x49, 0xdb, 0xa2, 0x7d, 0x18, 0x6c, 0x05, 0x77, 0x04, 0xa3, 0x6b, 0x87,
+	0xae, 0xd3, 0x24, 0xcb, 0xe0, 0xbb, 0x14, 0x0d, 0x6c, 0x66, 0x9b, 0xac, 0xad, 0xf6, 0x32, 0x69,
+	0xaf, 0xd3, 0x1e, 0xf6, 0x27, 0xec, 0x7f, 0x99, 0xf6, 0xb0, 0x87, 0xbd, 0xef, 0xa5, 0xea, 0x5f,
+	0x32, 0x5d, 0x5f, 0x0c, 0xc6, 0x50, 0xd1, 0xe4, 0x0d, 0x9f, 0xf3, 0x3b, 0xbf, 0x73, 0xef, 0xef,
+	0x7c, 0x18, 0x43, 0x75, 0xd2, 0x23, 0x5e, 0xb5, 0x6f, 0xd9, 0xc4, 0xe9, 0x93, 0xaa, 0xff, 0xcc,
+	0xf2, 0x6c, 0x7b, 0xe0, 0x07, 0xde, 0xa0, 0x37, 0x09, 0x5c, 0xaf, 0x7a, 0x71, 0x54, 0x25, 0xcf,
+	0x49, 0x9f, 0xfe, 0x16, 0xc7, 0x9e, 0x1b, 0xb8, 0xa8, 0x44, 0x03, 0xc4, 0x69, 0x80, 0x98, 0x0c,
+	0x10, 0x2f, 0x8e, 0x8a, 0x07, 0xe7, 0xae, 0x7b, 0x3e, 0x24, 0xd5, 0x10, 0xdf, 0x9b, 0x7c, 0x5f,
+	0x0d, 0x06, 0x23, 0xe2, 0x07, 0xd6, 0x68, 0xcc, 0x28, 0x8a, 0xfb, 0x49, 0xc0, 0x4f, 0x9e, 0x35,
+	0x1e, 0x13, 0xcf, 0x67, 0xfe, 0xc3, 0xbf, 0x36, 0x81, 0x6f, 0x12, 0xcb, 0x0b, 0x7a, 0xc4, 0x0a,
+	0x30, 0xf9, 0x71, 0x42, 0xfc, 0x00, 0xed, 0x41, 0xd6, 0xb1, 0x46, 0xc4, 0x1f, 0x5b, 0x7d, 0x22,
+	0x70, 0x25, 0xae, 0x9c, 0xc5, 0x73, 0x03, 0x3a, 0x80, 0x5c, 0x74, 0x4e, 0x73, 0x60, 0x0b, 0xa9,
+	0xd0, 0x0f, 0x91, 0x49, 0xb5, 0x51, 0x13, 0x36, 0xfc, 0xc0, 0x0a, 0x26, 0xbe, 0x90, 0x2e, 0x71,
+	0xe5, 0x42, 0xed, 0x23, 0x71, 0xdd, 0x3d, 0x44, 0x65, 0x1a, 0xad, 0x87, 0x71, 0x78, 0x1a, 0x8f,
+	0x7e, 0x86, 0xeb, 0x21, 0xda, 0x64, 0xcf, 0xa6, 0x47, 0xc6, 0xae, 0x17, 0xf8, 0x42, 0xa6, 0x94,
+	0x2e, 0xe7, 0x6a, 0x8f, 0xd7, 0xf3, 0x26, 0xaf, 0x26, 0xea, 0x14, 0x34, 0xcd, 0xc2, 0xc8, 0x14,
+	0x27, 0xf0, 0x5e, 0x60, 0xe4, 0x2f, 0x39, 0xd0, 0x77, 0xb0, 0x39, 0x22, 0x81, 0x65, 0x5b, 0x81,
+	0x25, 0x5c, 0x0b, 0x33, 0x7e, 0x75, 0x85, 0x8c, 0xa7, 0x53, 0x0a, 0x96, 0x67, 0xc6, 0x88, 0x8a,
+	0xb0, 0xf9, 0x8c, 0x58, 0xb6, 0xe7, 0xba, 0x23, 0x61, 0xa3, 0xc4, 0x95, 0x39, 0x3c, 0x7b, 0x46,
+	0x0f, 0x60, 0x7b, 0xe0, 0x9b, 0x36, 0x19, 0x06, 0xd6, 0xf4, 0xce, 0xc2, 0xbb, 0x25, 0xae, 0xbc,
+	0x89, 0xf3, 0x03, 0x5f, 0xa6, 0x56, 0x76, 0x44, 0x54, 0x87, 0x8c, 0xe7, 0x0e, 0x89, 0xb0, 0x19,
+	0xea, 0x2c, 0xbe, 0xbd, 0xce, 0xd8, 0x1d, 0x12, 0x1c, 0xc6, 0xa2, 0x2f, 0x61, 0x8f, 0x38, 0x7d,
+	0xd7, 0x26, 0xb6, 0xb9, 0x52, 0xeb, 0x6c, 0x89, 0x2b, 0x6f, 0xe1, 0xdb, 0x53, 0xcc, 0xb2, 0x7e,
+	0xa8, 0x0b, 0x1b, 0x43, 0xab, 0x47, 0x86, 0xbe, 0x00, 0xa1, 0x48, 0x5f, 0x5c, 0x41, 0xa4, 0x56,
+	0x48, 0xc0, 0x24, 0x9a, 0xb2, 0xa1, 0x87, 0xb0, 0xed, 0x53, 0xb7, 0xd3, 0x27, 0xa6, 0x33, 0x19,
+	0xf5, 0x88, 0x27, 0xe4, 0x4a, 0x5c, 0x39, 0x8d, 0x0b, 0x91, 0xb9, 0x1d, 0x5a, 0xd1, 0x7d, 0x28,
+	0x0c, 0x9c, 0xbe, 0xe5, 0x39, 0x56, 0x30, 0x70, 0x1d, 0xda, 0x93, 0x5b, 0x61, 0x4f, 0xe6, 0x63,
+	0x56, 0xd5, 0x46, 0x12, 0xe4, 0x67, 0x7d, 0x3b, 0x74, 0x2d, 0x5b, 0xc8, 0x97, 0xb8, 0x72, 0xae,
+	0xb6, 0x27, 0xb2, 0x11, 0x11, 0xa3, 0x11, 0x11, 0x65, 0x77, 0xd2, 0x1b, 0x92, 0xae, 0x35, 0x9c,
+	0x10, 0xbc, 0x15, 0x85, 0xb4, 0x5c, 0xcb, 0xa6, 0x99, 0xa2, 0xa2, 0xf8, 0x63, 0xd7, 0xf1, 0x89,
+	0x50, 0x60, 0x65, 0xb1, 0x59, 0x51, 0x98, 0xb1, 0xf8, 0x12, 0x6e, 0xbd, 0xa1, 0xcf, 0x10, 0x0f,
+	0xe9, 0x1f, 0xc8, 0x8b, 0xe9, 0x50, 0xd1, 0x9f, 0x48, 0x85, 0x6b, 0x17, 0x34, 0x55, 0x38, 0x48,
+	0xb9, 0xda, 0xa3, 0xf5, 0xea, 0x2d, 0x71, 0x63, 0xc6, 0xf0, 0x59, 0xea, 0x13, 0xae, 0x78, 0x0c,
+	0xf9, 0x85, 0x8e, 0x5b, 0x91, 0xf1, 0x7a, 0x3c, 0x63, 0x36, 0x1e, 0xfc, 0x29, 0xe4, 0x62, 0x95,
+	0xb8, 0x4c, 0xe8, 0xe1, 0x7f, 0x29, 0xd8, 0x59, 0x3a, 0x18, 0x52, 0x66, 0xab, 0x80, 0x0b, 0x5b,
+	0xf4, 0xc3, 0xcb, 0xdd, 0x2e, 0xda, 0x03, 0x77, 0x00, 0x58, 0x6f, 0x86, 0x75, 0x4b, 0x85, 0xd3,
+	0x92, 0x0d, 0x2d, 0x61, 0x59, 0x6c, 0xc8, 0xcd, 0xdd, 0x74, 0xeb, 0xd0, 0x36, 0x6c, 0x5c, 0x41,
+	0x48, 0x51, 0x8f, 0x38, 0xa7, 0xbd, 0x08, 0xb3, 0x24, 0x3e, 0x3a, 0x86, 0x1c, 0x9b, 0x09, 0x93,
+	0x2e, 0x59, 0x21, 0x13, 0x96, 0xab, 0xb8, 0xd4, 0x3d, 0x46, 0xb4, 0x81, 0x31, 0x30, 0x38, 0x35,
+	0x14, 0x3f, 0x87, 0xed, 0x04, 0xf7, 0x3a, 0x75, 0xb9, 0xb8, 0xba, 0xbf, 0x64, 0x60, 0x27, 0x36,
+	0x34, 0xac, 0xcf, 0xd0, 0x05, 0xec, 0xb0, 0x7b, 0x5b, 0xbe, 0x3f, 0x38, 0x77, 0x46, 0xc4, 0x09,
+	0xa8, 0xd0, 0xf4, 0xf6, 0xea, 0xa5, 0x86, 0x90, 0xf1, 0xb1, 0xdb, 0x4b, 0x73, 0x2e, 0xa6, 0x01,
+	0xef, 0x27, 0xcc, 0xa8, 0x0b, 0x85, 0xd1, 0xe0, 0xdc, 0x63, 0xe3, 0x36, 0x72, 0x6d, 0x76, 0xe0,
+	0x42, 0xad, 0xba, 0x3e, 0xe9, 0x69, 0x14, 0x77, 0xea, 0xda, 0x04, 0xe7, 0x47, 0xf1, 0x47, 0xd4,
+	0xa5, 0x0a, 0x5b, 0xbe, 0xeb, 0x98, 0x74, 0xd7, 0x4c, 0xdf, 0x1e, 0x1f, 0x5f, 0xea, 0x26, 0x34,
+	0xba, 0x41, 0xa9, 0xc1, 0x9b, 0xfd, 0xa6, 0xeb, 0x34, 0x0c, 0xf3, 0xcd, 0xc0, 0xa5, 0xeb, 0xcd,
+	0x0b, 0xc2, 0x37, 0x48, 0x16, 0xe7, 0x99, 0xd9, 0x70, 0x75, 0x6a, 0x44, 0xf7, 0xa0, 0x10, 0xc7,
+	0xb9, 0xe3, 0x70, 0xed, 0x67, 0xf1, 0xd6, 0x1c, 0xe6, 0x8e, 0x8b, 0x17, 0x70, 0x63, 0xa5, 0x50,
+	0x2b, 0x0a, 0x7a, 0xb2, 0x38, 0xdb, 0x47, 0x6f, 0xd9, 0x92, 0x73, 0xe6, 0x78, 0x0f, 0xbc, 0xe2,
+	0x60, 0x3b, 0xe1, 0x46, 0x8f, 0x13, 0xf3, 0x55, 0x5b, 0x9f, 0x61, 0x1e, 0x9d, 0x18, 0xb2, 0x63,
+	0xc8, 0xb1, 0x3e, 0x22, 0xb6, 0x69, 0x05, 0x42, 0x6a, 0x7d, 0x7f, 0x47, 0x70, 0x29, 0x40, 0x32,
+	0xf0, 0x43, 0x62, 0xf9, 0xc4, 0x24, 0xcf, 0xc7, 0x03, 0x8f, 0xf8, 0x94, 0x21, 0xbd, 0x96, 0xa1,
+	0x10, 0xc6, 0x28, 0x2c, 0x44, 0x0a, 0x2a, 0xbf, 0x72, 0x50, 0x58, 0xfc, 0x2b, 0x80, 0x76, 0xe1,
+	0x96, 0xf2, 0x8d, 0xd2, 0x38, 0x33, 0x34, 0x6c, 0xea, 0x86, 0x64, 0x9c, 0xe9, 0xa6, 0xda, 0xee,
+	0x4a, 0x2d, 0x55, 0xe6, 0xdf, 0x41, 0x45, 0xb8, 0x99, 0x74, 0x4a, 0x0d, 0x43, 0xed, 0x2a, 0x3c,
+	0x87, 0xf6, 0x40, 0x48, 0xfa, 0x64, 0x2c, 0xa9, 0x6d, 0xb5, 0x7d, 0xc2, 0xa7, 0x56, 0xd1, 0x86,
+	0x5e, 0x45, 0xe6, 0xd3, 0x95, 0x97, 0xb0, 0x15, 0x7f, 0x51, 0xa2, 0xdb, 0x70, 0x63, 0x06, 0xc6,
+	0x5a, 0x4b, 0x89, 0x9d, 0x40, 0x80, 0xeb, 0x8b, 0xae, 0x27, 0x1a, 0xfe, 0x5a, 0xc1, 0x3c, 0xb7,
+	0x70, 0xb6, 0xd0, 0xa3, 0xd5, 0x75, 0x05, 0x77, 0x15, 0xcc, 0xa7, 0x96, 0x09, 0x75, 0x43, 0x6a,
+	0xcb, 0xf5, 0xa7, 0x7c, 0xba, 0xf2, 0x1b, 0x07, 0xb9, 0xd8, 0x5e, 0xa2, 0x09, 0xf4, 0xa6, 0x84,
+	0xe5, 0xe5, 0xcb, 0xdf, 0x04, 0xb4, 0xe0, 0xc1, 0x8a, 0x24, 0x3f, 0xe5, 0x39, 0x74, 0x03, 0x76,
+	0x16, 0xec, 0xb2, 0xd6, 0x56, 0x58, 0xce, 0x45, 0x73, 0x24, 0x46, 0x9a, 0x1e, 0x75, 0xc1, 0xd5,
+	0xc1, 0x4a, 0x47, 0xc2, 0xd4, 0x97, 0xa9, 0xfc, 0x99, 0x82, 0xf7, 0x56, 0xcc, 0x17, 0xba, 0x0b,
+	0x77, 0x9a, 0x8a, 0x84, 0x8d, 0xba, 0x22, 0x19, 0x34, 0xb5, 0xae, 0xb5, 0xcd, 0x86, 0x26, 0xc7,
+	0xb5, 0x39, 0x84, 0xfd, 0xd5, 0x10, 0xa9, 0xd1, 0x50, 0x3a, 0x86, 0x22, 0xf3, 0x1c, 0x7a, 0x1f,
+	0x0e, 0x56, 0x63, 0x8c, 0x26, 0xd6, 0x0c, 0xa3, 0xa5, 0xc8, 0x7c, 0x0a, 0x55, 0xe1, 0x83, 0xd5,
+	0xa0, 0xe9, 0x79, 0x1b, 0x4d, 0xa9, 0x7d, 0xa2, 0x98, 0x52, 0xa7, 0xd3, 0x52, 0x69, 0x01, 0xdf,
+	0xcc, 0x2a, 0x9f, 0x75, 0x5a, 0x6a, 0x43, 0x32, 0x14, 0x3e, 0x83, 0xee, 0xc3, 0xdd, 0xd5, 0xa0,
+	0x8e, 0xa4, 0xeb, 0x34, 0xfd, 0xd9, 0x49, 0x93, 0xbf, 0xf6, 0x66, 0x2e, 0xaa, 0xb7, 0xa9, 0xb5,
+	0x5b, 0x4f, 0xf9, 0x8d, 0xca, 0xef, 0x1c, 0xf0, 0xc9, 0xc1, 0x42, 0x77, 0xe0, 0xb6, 0xa4, 0xeb,
+	0xea, 0x49, 0xfb, 0x54, 0x69, 0x1b, 0xcb, 0xf5, 0xdb, 0x85, 0x5b, 0xcb, 0xee, 0xa8, 0x88, 0x07,
+	0xb0, 0xbb, 0xec, 0x9c, 0xd7, 0x25, 0x85, 0xf6, 0xa1, 0xb8, 0x0c, 0x98, 0xd7, 0xb4, 0xf2, 0x0f,
+	0x07, 0xf9, 0x85, 0x65, 0x4b, 0xab, 0x7c, 0xaa, 0x9e, 0x60, 0xc9, 0x50, 0xb5, 0xb6, 0x79, 0xba,
+	0x58, 0xaa, 0x7b, 0x50, 0x4a, 0xf8, 0x5a, 0x5a, 0x43, 0x6a, 0x2d, 0x48, 0x11, 0x8e, 0x54, 0x02,
+	0xa5, 0xb5, 0xeb, 0x9a, 0x84, 0x65, 0x45, 0xe6, 0x33, 0x87, 0x99, 0xcd, 0x14, 0x9f, 0x3a, 0xcc,
+	0x6c, 0xa6, 0xf9, 0x74, 0xe5, 0xe1, 0x3a, 0x36, 0x53, 0x6f, 0x4a, 0xb2, 0xf6, 0xa4, 0xf2, 0x20,
+	0x01, 0x94, 0x55, 0xdd, 0xc0, 0x6a, 0xfd, 0xcc, 0x50, 0xe4, 0x38, 0xbc, 0xf6, 0x07, 0x07, 0xbb,
+	0xe1, 0x58, 0xc8, 0xf3, 0x6d, 0x16, 0xcd, 0xa8, 0xd4, 0x51, 0x51, 0x00, 0xd9, 0x59, 0x97, 0xa2,
+	0xda, 0xe5, 0xff, 0x81, 0x16, 0x1f, 0x5d, 0xe1, 0x85, 0x59, 0x7f, 0xf2, 0xf7, 0xeb, 0x7d, 0xee,
+	0xdf, 0xd7, 0xfb, 0xdc, 0xab, 0xd7, 0xfb, 0xdc, 0xb7, 0xea, 0xf9, 0x20, 0x78, 0x36, 0xe9, 0x89,
+	0x7d, 0x77, 0xb4, 0xf8, 0xa9, 0x27, 0x9e, 0x13, 0x87, 0x7d, 0x81, 0xad, 0xfa, 0xea, 0x3b, 0x4e,
+	0xda, 0x2e, 0x8e, 0x7a, 0x1b, 0x21, 0xfa, 0xd1, 0xff, 0x03, 0x00, 0x54, 0x6c, 0x5a, 0xbd, 0x33,
+	0x0e, 0x00, 0x00,
 }
 
 func (m *HeartbeatRequest) Marshal() (dAtA []byte, err error) {
@@ -475,6 +738,87 @@ func (m *HeartbeatRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.DeltaResponse {
+		i--
+		if m.DeltaResponse {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.ExecutorLoad != nil {
+		{
+			size, err := m.ExecutorLoad.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExecutor(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	if len(m.IncarnationId) > 0 {
+		i -= len(m.IncarnationId)
+		copy(dAtA[i:], m.IncarnationId)
+		i = encodeVarintExecutor(dAtA, i, uint64(len(m.IncarnationId)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.SequenceNumber != 0 {
+		i = encodeVarintExecutor(dAtA, i, uint64(m.SequenceNumber))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintExecutor(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.EncodedShardStatusReports) > 0 {
+		i -= len(m.EncodedShardStatusReports)
+		copy(dAtA[i:], m.EncodedShardStatusReports)
+		i = encodeVarintExecutor(dAtA, i, uint64(len(m.EncodedShardStatusReports)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Role != 0 {
+		i = encodeVarintExecutor(dAtA, i, uint64(m.Role))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.IsDeltaReport {
+		i--
+		if m.IsDeltaReport {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Headroom != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Headroom))))
+		i--
+		dAtA[i] = 0x31
+	}
 	if len(m.Metadata) > 0 {
 		for k := range m.Metadata {
 			v := m.Metadata[k]
@@ -566,6 +910,36 @@ func (m *ShardStatusReport) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.ReportTime != nil {
+		{
+			size, err := m.ReportTime.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExecutor(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.ShardLoads) > 0 {
+		for k := range m.ShardLoads {
+			v := m.ShardLoads[k]
+			baseI := i
+			i -= 8
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(v))))
+			i--
+			dAtA[i] = 0x11
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintExecutor(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.ShardLoad != 0 {
 		i -= 8
 		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ShardLoad))))
@@ -604,6 +978,29 @@ func (m *HeartbeatResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ShardsToStop) > 0 {
+		for iNdEx := len(m.ShardsToStop) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ShardsToStop[iNdEx])
+			copy(dAtA[i:], m.ShardsToStop[iNdEx])
+			i = encodeVarintExecutor(dAtA, i, uint64(len(m.ShardsToStop[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.ShardsToStart) > 0 {
+		for iNdEx := len(m.ShardsToStart) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ShardsToStart[iNdEx])
+			copy(dAtA[i:], m.ShardsToStart[iNdEx])
+			i = encodeVarintExecutor(dAtA, i, uint64(len(m.ShardsToStart[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.ReasonCode != 0 {
+		i = encodeVarintExecutor(dAtA, i, uint64(m.ReasonCode))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.MigrationMode != 0 {
 		i = encodeVarintExecutor(dAtA, i, uint64(m.MigrationMode))
 		i--
@@ -662,6 +1059,30 @@ func (m *ShardAssignment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.LeaseExpiresAt != nil {
+		{
+			size, err := m.LeaseExpiresAt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExecutor(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.AssignedAt != nil {
+		{
+			size, err := m.AssignedAt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExecutor(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Status != 0 {
 		i = encodeVarintExecutor(dAtA, i, uint64(m.Status))
 		i--
@@ -719,6 +1140,41 @@ func (m *HeartbeatRequest) Size() (n int) {
 			n += mapEntrySize + 1 + sovExecutor(uint64(mapEntrySize))
 		}
 	}
+	if m.Headroom != 0 {
+		n += 9
+	}
+	if m.IsDeltaReport {
+		n += 2
+	}
+	if m.Role != 0 {
+		n += 1 + sovExecutor(uint64(m.Role))
+	}
+	l = len(m.EncodedShardStatusReports)
+	if l > 0 {
+		n += 1 + l + sovExecutor(uint64(l))
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovExecutor(uint64(len(k))) + 1 + len(v) + sovExecutor(uint64(len(v)))
+			n += mapEntrySize + 1 + sovExecutor(uint64(mapEntrySize))
+		}
+	}
+	if m.SequenceNumber != 0 {
+		n += 1 + sovExecutor(uint64(m.SequenceNumber))
+	}
+	l = len(m.IncarnationId)
+	if l > 0 {
+		n += 1 + l + sovExecutor(uint64(l))
+	}
+	if m.ExecutorLoad != nil {
+		l = m.ExecutorLoad.Size()
+		n += 1 + l + sovExecutor(uint64(l))
+	}
+	if m.DeltaResponse {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -737,6 +1193,18 @@ func (m *ShardStatusReport) Size() (n int) {
 	if m.ShardLoad != 0 {
 		n += 9
 	}
+	if len(m.ShardLoads) > 0 {
+		for k, v := range m.ShardLoads {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovExecutor(uint64(len(k))) + 1 + 8
+			n += mapEntrySize + 1 + sovExecutor(uint64(mapEntrySize))
+		}
+	}
+	if m.ReportTime != nil {
+		l = m.ReportTime.Size()
+		n += 1 + l + sovExecutor(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -765,6 +1233,21 @@ func (m *HeartbeatResponse) Size() (n int) {
 	if m.MigrationMode != 0 {
 		n += 1 + sovExecutor(uint64(m.MigrationMode))
 	}
+	if m.ReasonCode != 0 {
+		n += 1 + sovExecutor(uint64(m.ReasonCode))
+	}
+	if len(m.ShardsToStart) > 0 {
+		for _, s := range m.ShardsToStart {
+			l = len(s)
+			n += 1 + l + sovExecutor(uint64(l))
+		}
+	}
+	if len(m.ShardsToStop) > 0 {
+		for _, s := range m.ShardsToStop {
+			l = len(s)
+			n += 1 + l + sovExecutor(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -780,6 +1263,14 @@ func (m *ShardAssignment) Size() (n int) {
 	if m.Status != 0 {
 		n += 1 + sovExecutor(uint64(m.Status))
 	}
+	if m.AssignedAt != nil {
+		l = m.AssignedAt.Size()
+		n += 1 + l + sovExecutor(uint64(l))
+	}
+	if m.LeaseExpiresAt != nil {
+		l = m.LeaseExpiresAt.Size()
+		n += 1 + l + sovExecutor(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1160,40 +1651,358 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Metadata[mapkey] = mapvalue
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipExecutor(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthExecutor
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headroom", wireType)
 			}
-			if (iNdEx + skippy) > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ShardStatusReport) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowExecutor
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Headroom = float64(math.Float64frombits(v))
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsDeltaReport", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsDeltaReport = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			m.Role = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Role |= ExecutorRole(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodedShardStatusReports", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EncodedShardStatusReports = append(m.EncodedShardStatusReports[:0], dAtA[iNdEx:postIndex]...)
+			if m.EncodedShardStatusReports == nil {
+				m.EncodedShardStatusReports = []byte{}
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowExecutor
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipExecutor(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SequenceNumber", wireType)
+			}
+			m.SequenceNumber = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SequenceNumber |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncarnationId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IncarnationId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorLoad", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ExecutorLoad == nil {
+				m.ExecutorLoad = &types.DoubleValue{}
+			}
+			if err := m.ExecutorLoad.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeltaResponse", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DeltaResponse = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExecutor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShardStatusReport) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExecutor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
@@ -1201,21 +2010,157 @@ func (m *ShardStatusReport) Unmarshal(dAtA []byte) error {
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ShardStatusReport: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ShardStatusReport: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShardStatusReport: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShardStatusReport: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= ShardStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardLoad", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ShardLoad = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardLoads", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
 			}
-			m.Status = 0
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ShardLoads == nil {
+				m.ShardLoads = make(map[string]float64)
+			}
+			var mapkey string
+			var mapvalue float64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowExecutor
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					mapvalue = math.Float64frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipExecutor(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ShardLoads[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReportTime", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExecutor
@@ -1225,22 +2170,28 @@ func (m *ShardStatusReport) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= ShardStatus(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ShardLoad", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.ShardLoad = float64(math.Float64frombits(v))
+			if m.ReportTime == nil {
+				m.ReportTime = &types.Timestamp{}
+			}
+			if err := m.ReportTime.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutor(dAtA[iNdEx:])
@@ -1440,6 +2391,89 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReasonCode", wireType)
+			}
+			m.ReasonCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReasonCode |= HeartbeatReasonCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardsToStart", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ShardsToStart = append(m.ShardsToStart, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardsToStop", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ShardsToStop = append(m.ShardsToStop, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutor(dAtA[iNdEx:])
@@ -1510,6 +2544,78 @@ func (m *ShardAssignment) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AssignedAt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AssignedAt == nil {
+				m.AssignedAt = &types.Timestamp{}
+			}
+			if err := m.AssignedAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseExpiresAt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LeaseExpiresAt == nil {
+				m.LeaseExpiresAt = &types.Timestamp{}
+			}
+			if err := m.LeaseExpiresAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutor(dAtA[iNdEx:])
@@ -233,53 +233,128 @@ var (
 var yarpcFileDescriptorClosure5aab034437d08cca = [][]byte{
 	// uber/cadence/sharddistributor/v1/executor.proto
 	[]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x6d, 0x6f, 0xd2, 0x50,
-		0x14, 0xb6, 0xc0, 0x96, 0x71, 0xc8, 0xb0, 0xdc, 0xec, 0x05, 0xd9, 0x16, 0x09, 0x31, 0xba, 0x90,
-		0x58, 0x84, 0x7d, 0x31, 0xee, 0x8b, 0xdd, 0xda, 0x40, 0x27, 0xb4, 0xcb, 0x6d, 0xc1, 0x97, 0x68,
-		0x9a, 0x0b, 0xbd, 0x61, 0xc4, 0xd1, 0x62, 0x7b, 0x21, 0xce, 0xf8, 0xd1, 0x7f, 0xe0, 0x1f, 0xf1,
-		0x87, 0xf8, 0x0f, 0xfc, 0x33, 0x86, 0x16, 0x46, 0x5b, 0x30, 0xb8, 0x7d, 0x6b, 0xcf, 0x39, 0xcf,
-		0xf3, 0x9c, 0x9e, 0xe7, 0xdc, 0x5e, 0xa8, 0x8c, 0xbb, 0xd4, 0xad, 0xf4, 0x88, 0x45, 0xed, 0x1e,
-		0xad, 0x78, 0x57, 0xc4, 0xb5, 0xac, 0x81, 0xc7, 0xdc, 0x41, 0x77, 0xcc, 0x1c, 0xb7, 0x32, 0xa9,
-		0x56, 0xe8, 0x57, 0xda, 0x9b, 0x3e, 0x0b, 0x23, 0xd7, 0x61, 0x0e, 0x2a, 0x4e, 0x01, 0xc2, 0x0c,
-		0x20, 0xc4, 0x01, 0xc2, 0xa4, 0x5a, 0xfa, 0x95, 0x02, 0xbe, 0x41, 0x89, 0xcb, 0xba, 0x94, 0x30,
-		0x4c, 0xbf, 0x8c, 0xa9, 0xc7, 0xd0, 0x21, 0xa4, 0x6d, 0x32, 0xa4, 0xde, 0x88, 0xf4, 0x68, 0x9e,
-		0x2b, 0x72, 0xc7, 0x69, 0xbc, 0x08, 0xa0, 0xc7, 0x90, 0x99, 0xcb, 0x98, 0x03, 0x2b, 0x9f, 0xf0,
-		0xf3, 0x30, 0x0f, 0x29, 0x16, 0x6a, 0xc0, 0xa6, 0xc7, 0x08, 0x1b, 0x7b, 0xf9, 0x64, 0x91, 0x3b,
-		0xce, 0xd6, 0x5e, 0x08, 0xeb, 0xda, 0x10, 0xe4, 0x19, 0x5a, 0xf7, 0x71, 0x78, 0x86, 0x47, 0xdf,
-		0x61, 0xc7, 0xaf, 0x36, 0x83, 0x77, 0xd3, 0xa5, 0x23, 0xc7, 0x65, 0x5e, 0x3e, 0x55, 0x4c, 0x1e,
-		0x67, 0x6a, 0x17, 0xeb, 0x79, 0xe3, 0x9f, 0x26, 0xe8, 0xd3, 0xa2, 0x99, 0x4a, 0x40, 0x26, 0xdb,
-		0xcc, 0xbd, 0xc1, 0xc8, 0x5b, 0x4a, 0xa0, 0x8f, 0xb0, 0x35, 0xa4, 0x8c, 0x58, 0x84, 0x91, 0xfc,
-		0x86, 0xaf, 0xf8, 0xfa, 0x1e, 0x8a, 0xad, 0x19, 0x45, 0xa0, 0x73, 0xcb, 0x58, 0xf8, 0x06, 0xfb,
-		0xff, 0x68, 0x06, 0xf1, 0x90, 0xfc, 0x4c, 0x6f, 0x66, 0x93, 0x9f, 0x3e, 0x22, 0x05, 0x36, 0x26,
-		0xe4, 0x7a, 0x4c, 0xfd, 0x69, 0x67, 0x6a, 0x27, 0xeb, 0xfb, 0x58, 0xe2, 0xc6, 0x01, 0xc3, 0xab,
-		0xc4, 0x4b, 0xae, 0x70, 0x0a, 0xdb, 0x91, 0xb6, 0x56, 0x28, 0xee, 0x84, 0x15, 0xd3, 0x21, 0x70,
-		0xe9, 0x06, 0x72, 0x4b, 0xe4, 0x48, 0xbe, 0xf5, 0x9c, 0xf3, 0x3d, 0x7f, 0x7e, 0xb7, 0x0e, 0xe7,
-		0x86, 0x1f, 0x01, 0x04, 0x86, 0x5f, 0x3b, 0x24, 0x58, 0x2d, 0x0e, 0xa7, 0xfd, 0x48, 0xd3, 0x21,
-		0x56, 0xe9, 0x4f, 0x02, 0x72, 0xa1, 0x01, 0x7b, 0x23, 0xc7, 0xf6, 0x28, 0x9a, 0x40, 0x2e, 0x00,
-		0x11, 0xcf, 0x1b, 0xf4, 0xed, 0x21, 0xb5, 0xd9, 0xb4, 0x8d, 0xa9, 0x61, 0xca, 0x9d, 0x0c, 0x0b,
-		0xf8, 0x82, 0xc6, 0xc4, 0x05, 0x57, 0xe0, 0x1c, 0xef, 0xc5, 0xc2, 0xa8, 0x03, 0xd9, 0xe1, 0xa0,
-		0xef, 0x12, 0x36, 0x70, 0x6c, 0x73, 0xe8, 0x58, 0xc1, 0xac, 0xb2, 0xb5, 0xca, 0x7a, 0xd1, 0xd6,
-		0x1c, 0xd7, 0x72, 0x2c, 0x8a, 0xb7, 0x87, 0xe1, 0xd7, 0xc2, 0x04, 0x76, 0x57, 0xb6, 0xb0, 0xc2,
-		0xa5, 0x7a, 0x74, 0x2f, 0xaa, 0xff, 0x39, 0xf5, 0x05, 0x73, 0xd8, 0xd8, 0x4f, 0xf0, 0x30, 0x96,
-		0x45, 0x17, 0x31, 0x5b, 0x6b, 0xeb, 0x05, 0x16, 0xe8, 0xa8, 0xb7, 0xe5, 0x1f, 0x1c, 0x64, 0xa3,
-		0xe7, 0x1c, 0x1d, 0xc0, 0xbe, 0xfc, 0x4e, 0x3e, 0x6f, 0x1b, 0x1a, 0x36, 0x75, 0x43, 0x34, 0xda,
-		0xba, 0xa9, 0xa8, 0x1d, 0xb1, 0xa9, 0x48, 0xfc, 0x03, 0x54, 0x80, 0xbd, 0x78, 0x52, 0x3c, 0x37,
-		0x94, 0x8e, 0xcc, 0x73, 0xe8, 0x10, 0xf2, 0xf1, 0x9c, 0x84, 0x45, 0x45, 0x55, 0xd4, 0x3a, 0x9f,
-		0x58, 0x45, 0xeb, 0x67, 0x65, 0x89, 0x4f, 0x96, 0x3b, 0x90, 0x09, 0x6d, 0x1e, 0xca, 0xc3, 0x8e,
-		0xde, 0x10, 0xb1, 0xb4, 0xac, 0xbf, 0x07, 0x28, 0x92, 0xc1, 0xb2, 0x28, 0xbd, 0xe7, 0x39, 0xb4,
-		0x0b, 0xb9, 0x48, 0x5c, 0xd2, 0x54, 0x99, 0x4f, 0x94, 0x55, 0xe0, 0xe3, 0x9f, 0x8e, 0x8e, 0xe0,
-		0x91, 0xa8, 0xeb, 0x4a, 0x5d, 0x6d, 0xc9, 0xaa, 0xb1, 0xac, 0x70, 0x00, 0xfb, 0xcb, 0xe9, 0x99,
-		0x4c, 0xf9, 0x37, 0x07, 0xdb, 0x91, 0x35, 0x99, 0x0e, 0xa4, 0xa5, 0xd4, 0xb1, 0x68, 0x28, 0x9a,
-		0x6a, 0xb6, 0x34, 0x49, 0x0e, 0x51, 0x3d, 0x81, 0x62, 0x2c, 0xd7, 0xd4, 0xce, 0xc5, 0xa6, 0x79,
-		0x29, 0xea, 0xba, 0xd1, 0xc0, 0x5a, 0xbb, 0xde, 0x08, 0xc6, 0x16, 0xab, 0xd2, 0xd4, 0x33, 0x4d,
-		0xc4, 0x92, 0x2c, 0xf1, 0xa9, 0x52, 0x6a, 0x2b, 0xc1, 0x27, 0x4a, 0xa9, 0xad, 0x24, 0x9f, 0x2c,
-		0x3f, 0x5b, 0xc7, 0x66, 0xea, 0x0d, 0x51, 0xd2, 0xde, 0x96, 0x9f, 0xc6, 0x0a, 0x25, 0x45, 0x37,
-		0xb0, 0x72, 0xd6, 0x36, 0x64, 0x29, 0x5c, 0x5e, 0xfb, 0xc9, 0xc1, 0x81, 0x3f, 0x77, 0x69, 0xb1,
-		0x2e, 0xf3, 0x6d, 0x10, 0x2f, 0x15, 0xc4, 0x20, 0x7d, 0x7b, 0x12, 0x51, 0xed, 0xee, 0xff, 0xd9,
-		0xc2, 0xc9, 0x3d, 0x8e, 0xfa, 0xd9, 0x9b, 0x0f, 0x4a, 0x7f, 0xc0, 0xae, 0xc6, 0x5d, 0xa1, 0xe7,
-		0x0c, 0xa3, 0xd7, 0xab, 0xd0, 0xa7, 0x76, 0xc5, 0xbf, 0x46, 0x57, 0xdd, 0xb4, 0xa7, 0xf1, 0xd8,
-		0xa4, 0xda, 0xdd, 0xf4, 0xab, 0x4f, 0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0x19, 0xca, 0x25, 0x17,
-		0xa7, 0x07, 0x00, 0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x5d, 0x6f, 0xda, 0x56,
+		0x18, 0x9e, 0x81, 0x66, 0xf0, 0x12, 0x88, 0x73, 0xd6, 0x0f, 0x97, 0xa4, 0x0d, 0xcd, 0xfa, 0x81,
+		0x98, 0x66, 0x16, 0xaa, 0x49, 0xdb, 0xa2, 0x7d, 0x18, 0x6c, 0x05, 0xb7, 0x04, 0xa3, 0x63, 0x87,
+		0xae, 0xd3, 0x24, 0xcb, 0xe0, 0xb3, 0x14, 0x0d, 0x6c, 0x66, 0x9b, 0xac, 0xad, 0x76, 0x33, 0x69,
+		0xb7, 0xd3, 0x2e, 0xf6, 0x13, 0xf6, 0x5f, 0x76, 0xb5, 0x7f, 0xb0, 0x9b, 0xfd, 0x94, 0xe9, 0xf8,
+		0x60, 0x30, 0x86, 0x88, 0x26, 0x77, 0xf8, 0x7d, 0x9f, 0xf7, 0x79, 0xcf, 0x79, 0xde, 0x0f, 0x63,
+		0xa8, 0x4d, 0xfb, 0xc4, 0xab, 0x0d, 0x2c, 0x9b, 0x38, 0x03, 0x52, 0xf3, 0x5f, 0x59, 0x9e, 0x6d,
+		0x0f, 0xfd, 0xc0, 0x1b, 0xf6, 0xa7, 0x81, 0xeb, 0xd5, 0x2e, 0x8e, 0x6a, 0xe4, 0x35, 0x19, 0xd0,
+		0xdf, 0xe2, 0xc4, 0x73, 0x03, 0x17, 0x95, 0x69, 0x80, 0x38, 0x0b, 0x10, 0x93, 0x01, 0xe2, 0xc5,
+		0x51, 0xe9, 0xe0, 0xdc, 0x75, 0xcf, 0x47, 0xa4, 0x16, 0xe2, 0xfb, 0xd3, 0x1f, 0x6a, 0xc1, 0x70,
+		0x4c, 0xfc, 0xc0, 0x1a, 0x4f, 0x18, 0x45, 0xe9, 0x7e, 0x12, 0xf0, 0xb3, 0x67, 0x4d, 0x26, 0xc4,
+		0xf3, 0x99, 0xff, 0xf0, 0xef, 0x2c, 0xf0, 0x2d, 0x62, 0x79, 0x41, 0x9f, 0x58, 0x01, 0x26, 0x3f,
+		0x4d, 0x89, 0x1f, 0xa0, 0x7d, 0xc8, 0x39, 0xd6, 0x98, 0xf8, 0x13, 0x6b, 0x40, 0x04, 0xae, 0xcc,
+		0x55, 0x72, 0x78, 0x61, 0x40, 0x07, 0x90, 0x8f, 0xce, 0x69, 0x0e, 0x6d, 0x21, 0x15, 0xfa, 0x21,
+		0x32, 0xa9, 0x36, 0x6a, 0xc1, 0x96, 0x1f, 0x58, 0xc1, 0xd4, 0x17, 0xd2, 0x65, 0xae, 0x52, 0xac,
+		0x7f, 0x22, 0x6e, 0xba, 0x87, 0xa8, 0xcc, 0xa2, 0xf5, 0x30, 0x0e, 0xcf, 0xe2, 0xd1, 0x2f, 0x70,
+		0x33, 0x44, 0x9b, 0xec, 0xd9, 0xf4, 0xc8, 0xc4, 0xf5, 0x02, 0x5f, 0xc8, 0x94, 0xd3, 0x95, 0x7c,
+		0xfd, 0xd9, 0x66, 0xde, 0xe4, 0xd5, 0x44, 0x9d, 0x82, 0x66, 0x59, 0x18, 0x99, 0xe2, 0x04, 0xde,
+		0x1b, 0x8c, 0xfc, 0x15, 0x07, 0xfa, 0x1e, 0xb2, 0x63, 0x12, 0x58, 0xb6, 0x15, 0x58, 0xc2, 0x8d,
+		0x30, 0xe3, 0x37, 0xd7, 0xc8, 0x78, 0x3a, 0xa3, 0x60, 0x79, 0xe6, 0x8c, 0xa8, 0x04, 0xd9, 0x57,
+		0xc4, 0xb2, 0x3d, 0xd7, 0x1d, 0x0b, 0x5b, 0x65, 0xae, 0xc2, 0xe1, 0xf9, 0x33, 0x7a, 0x0c, 0x3b,
+		0x43, 0xdf, 0xb4, 0xc9, 0x28, 0xb0, 0x66, 0x77, 0x16, 0xde, 0x2f, 0x73, 0x95, 0x2c, 0x2e, 0x0c,
+		0x7d, 0x99, 0x5a, 0xd9, 0x11, 0x51, 0x03, 0x32, 0x9e, 0x3b, 0x22, 0x42, 0x36, 0xd4, 0x59, 0x7c,
+		0x77, 0x9d, 0xb1, 0x3b, 0x22, 0x38, 0x8c, 0x45, 0x5f, 0xc3, 0x3e, 0x71, 0x06, 0xae, 0x4d, 0x6c,
+		0x73, 0xad, 0xd6, 0xb9, 0x32, 0x57, 0xd9, 0xc6, 0x77, 0x67, 0x98, 0x55, 0xfd, 0x50, 0x0f, 0xb6,
+		0x46, 0x56, 0x9f, 0x8c, 0x7c, 0x01, 0x42, 0x91, 0xbe, 0xba, 0x86, 0x48, 0xed, 0x90, 0x80, 0x49,
+		0x34, 0x63, 0x43, 0x4f, 0x60, 0xc7, 0xa7, 0x6e, 0x67, 0x40, 0x4c, 0x67, 0x3a, 0xee, 0x13, 0x4f,
+		0xc8, 0x97, 0xb9, 0x4a, 0x1a, 0x17, 0x23, 0x73, 0x27, 0xb4, 0xa2, 0x47, 0x50, 0x1c, 0x3a, 0x03,
+		0xcb, 0x73, 0xac, 0x60, 0xe8, 0x3a, 0xb4, 0x27, 0xb7, 0xc3, 0x9e, 0x2c, 0xc4, 0xac, 0xaa, 0x8d,
+		0x24, 0x28, 0xcc, 0xfb, 0x76, 0xe4, 0x5a, 0xb6, 0x50, 0x28, 0x73, 0x95, 0x7c, 0x7d, 0x5f, 0x64,
+		0x23, 0x22, 0x46, 0x23, 0x22, 0xca, 0xee, 0xb4, 0x3f, 0x22, 0x3d, 0x6b, 0x34, 0x25, 0x78, 0x3b,
+		0x0a, 0x69, 0xbb, 0x96, 0x4d, 0x33, 0x45, 0x45, 0xf1, 0x27, 0xae, 0xe3, 0x13, 0xa1, 0xc8, 0xca,
+		0x62, 0xb3, 0xa2, 0x30, 0x63, 0xe9, 0x2d, 0xdc, 0xb9, 0xa4, 0xcf, 0x10, 0x0f, 0xe9, 0x1f, 0xc9,
+		0x9b, 0xd9, 0x50, 0xd1, 0x9f, 0x48, 0x85, 0x1b, 0x17, 0x34, 0x55, 0x38, 0x48, 0xf9, 0xfa, 0xd3,
+		0xcd, 0xea, 0xad, 0x70, 0x63, 0xc6, 0xf0, 0x45, 0xea, 0x33, 0xae, 0x74, 0x0c, 0x85, 0xa5, 0x8e,
+		0x5b, 0x93, 0xf1, 0x66, 0x3c, 0x63, 0x2e, 0x1e, 0xfc, 0x39, 0xe4, 0x63, 0x95, 0xb8, 0x4a, 0xe8,
+		0xe1, 0xbf, 0x29, 0xd8, 0x5d, 0x39, 0x18, 0x52, 0xe6, 0xab, 0x80, 0x0b, 0x5b, 0xf4, 0xe3, 0xab,
+		0xdd, 0x2e, 0xda, 0x03, 0xf7, 0x00, 0x58, 0x6f, 0x86, 0x75, 0x4b, 0x85, 0xd3, 0x92, 0x0b, 0x2d,
+		0x61, 0x59, 0x6c, 0xc8, 0x2f, 0xdc, 0x74, 0xeb, 0xd0, 0x36, 0x6c, 0x5e, 0x43, 0x48, 0x51, 0x8f,
+		0x38, 0x67, 0xbd, 0x08, 0xf3, 0x24, 0x3e, 0x3a, 0x86, 0x3c, 0x9b, 0x09, 0x93, 0x2e, 0x59, 0x21,
+		0x13, 0x96, 0xab, 0xb4, 0xd2, 0x3d, 0x46, 0xb4, 0x81, 0x31, 0x30, 0x38, 0x35, 0x94, 0xbe, 0x84,
+		0x9d, 0x04, 0xf7, 0x26, 0x75, 0xb9, 0xb8, 0xba, 0xbf, 0x66, 0x60, 0x37, 0x36, 0x34, 0xac, 0xcf,
+		0xd0, 0x05, 0xec, 0xb2, 0x7b, 0x5b, 0xbe, 0x3f, 0x3c, 0x77, 0xc6, 0xc4, 0x09, 0xa8, 0xd0, 0xf4,
+		0xf6, 0xea, 0x95, 0x86, 0x90, 0xf1, 0xb1, 0xdb, 0x4b, 0x0b, 0x2e, 0xa6, 0x01, 0xef, 0x27, 0xcc,
+		0xa8, 0x07, 0xc5, 0xf1, 0xf0, 0xdc, 0x63, 0xe3, 0x36, 0x76, 0x6d, 0x76, 0xe0, 0x62, 0xbd, 0xb6,
+		0x39, 0xe9, 0x69, 0x14, 0x77, 0xea, 0xda, 0x04, 0x17, 0xc6, 0xf1, 0x47, 0xd4, 0xa3, 0x0a, 0x5b,
+		0xbe, 0xeb, 0x98, 0x74, 0xd7, 0xcc, 0xde, 0x1e, 0x9f, 0x5e, 0xe9, 0x26, 0x34, 0xba, 0x49, 0xa9,
+		0xc1, 0x9b, 0xff, 0xa6, 0xeb, 0x34, 0x0c, 0xf3, 0xcd, 0xc0, 0xa5, 0xeb, 0xcd, 0x0b, 0xc2, 0x37,
+		0x48, 0x0e, 0x17, 0x98, 0xd9, 0x70, 0x75, 0x6a, 0x44, 0x0f, 0xa1, 0x18, 0xc7, 0xb9, 0x93, 0x70,
+		0xed, 0xe7, 0xf0, 0xf6, 0x02, 0xe6, 0x4e, 0x4a, 0x17, 0x70, 0x6b, 0xad, 0x50, 0x6b, 0x0a, 0x7a,
+		0xb2, 0x3c, 0xdb, 0x47, 0xef, 0xd8, 0x92, 0x0b, 0xe6, 0x78, 0x0f, 0xfc, 0xc7, 0xc1, 0x4e, 0xc2,
+		0x8d, 0x9e, 0x25, 0xe6, 0xab, 0xbe, 0x39, 0xc3, 0x22, 0x3a, 0x31, 0x64, 0xc7, 0x90, 0x67, 0x7d,
+		0x44, 0x6c, 0xd3, 0x0a, 0x84, 0xd4, 0xe6, 0xfe, 0x8e, 0xe0, 0x52, 0x80, 0x64, 0xe0, 0x47, 0xc4,
+		0xf2, 0x89, 0x49, 0x5e, 0x4f, 0x86, 0x1e, 0xf1, 0x29, 0x43, 0x7a, 0x23, 0x43, 0x31, 0x8c, 0x51,
+		0x58, 0x88, 0x14, 0x54, 0x7f, 0xe3, 0xa0, 0xb8, 0xfc, 0x57, 0x00, 0xed, 0xc1, 0x1d, 0xe5, 0x5b,
+		0xa5, 0x79, 0x66, 0x68, 0xd8, 0xd4, 0x0d, 0xc9, 0x38, 0xd3, 0x4d, 0xb5, 0xd3, 0x93, 0xda, 0xaa,
+		0xcc, 0xbf, 0x87, 0x4a, 0x70, 0x3b, 0xe9, 0x94, 0x9a, 0x86, 0xda, 0x53, 0x78, 0x0e, 0xed, 0x83,
+		0x90, 0xf4, 0xc9, 0x58, 0x52, 0x3b, 0x6a, 0xe7, 0x84, 0x4f, 0xad, 0xa3, 0x0d, 0xbd, 0x8a, 0xcc,
+		0xa7, 0xab, 0x6f, 0x61, 0x3b, 0xfe, 0xa2, 0x44, 0x77, 0xe1, 0xd6, 0x1c, 0x8c, 0xb5, 0xb6, 0x12,
+		0x3b, 0x81, 0x00, 0x37, 0x97, 0x5d, 0x2f, 0x34, 0xfc, 0x5c, 0xc1, 0x3c, 0xb7, 0x74, 0xb6, 0xd0,
+		0xa3, 0x35, 0x74, 0x05, 0xf7, 0x14, 0xcc, 0xa7, 0x56, 0x09, 0x75, 0x43, 0xea, 0xc8, 0x8d, 0x97,
+		0x7c, 0xba, 0xfa, 0x3b, 0x07, 0xf9, 0xd8, 0x5e, 0xa2, 0x09, 0xf4, 0x96, 0x84, 0xe5, 0xd5, 0xcb,
+		0xdf, 0x06, 0xb4, 0xe4, 0xc1, 0x8a, 0x24, 0xbf, 0xe4, 0x39, 0x74, 0x0b, 0x76, 0x97, 0xec, 0xb2,
+		0xd6, 0x51, 0x58, 0xce, 0x65, 0x73, 0x24, 0x46, 0x9a, 0x1e, 0x75, 0xc9, 0xd5, 0xc5, 0x4a, 0x57,
+		0xc2, 0xd4, 0x97, 0xa9, 0xfe, 0x95, 0x82, 0x0f, 0xd6, 0xcc, 0x17, 0x7a, 0x00, 0xf7, 0x5a, 0x8a,
+		0x84, 0x8d, 0x86, 0x22, 0x19, 0x34, 0xb5, 0xae, 0x75, 0xcc, 0xa6, 0x26, 0xc7, 0xb5, 0x39, 0x84,
+		0xfb, 0xeb, 0x21, 0x52, 0xb3, 0xa9, 0x74, 0x0d, 0x45, 0xe6, 0x39, 0xf4, 0x21, 0x1c, 0xac, 0xc7,
+		0x18, 0x2d, 0xac, 0x19, 0x46, 0x5b, 0x91, 0xf9, 0x14, 0xaa, 0xc1, 0x47, 0xeb, 0x41, 0xb3, 0xf3,
+		0x36, 0x5b, 0x52, 0xe7, 0x44, 0x31, 0xa5, 0x6e, 0xb7, 0xad, 0xd2, 0x02, 0x5e, 0xce, 0x2a, 0x9f,
+		0x75, 0xdb, 0x6a, 0x53, 0x32, 0x14, 0x3e, 0x83, 0x1e, 0xc1, 0x83, 0xf5, 0xa0, 0xae, 0xa4, 0xeb,
+		0x34, 0xfd, 0xd9, 0x49, 0x8b, 0xbf, 0x71, 0x39, 0x17, 0xd5, 0xdb, 0xd4, 0x3a, 0xed, 0x97, 0xfc,
+		0x56, 0xf5, 0x0f, 0x0e, 0xf8, 0xe4, 0x60, 0xa1, 0x7b, 0x70, 0x57, 0xd2, 0x75, 0xf5, 0xa4, 0x73,
+		0xaa, 0x74, 0x8c, 0xd5, 0xfa, 0xed, 0xc1, 0x9d, 0x55, 0x77, 0x54, 0xc4, 0x03, 0xd8, 0x5b, 0x75,
+		0x2e, 0xea, 0x92, 0x42, 0xf7, 0xa1, 0xb4, 0x0a, 0x58, 0xd4, 0xb4, 0xfa, 0x0f, 0x07, 0x85, 0xa5,
+		0x65, 0x4b, 0xab, 0x7c, 0xaa, 0x9e, 0x60, 0xc9, 0x50, 0xb5, 0x8e, 0x79, 0xba, 0x5c, 0xaa, 0x87,
+		0x50, 0x4e, 0xf8, 0xda, 0x5a, 0x53, 0x6a, 0x2f, 0x49, 0x11, 0x8e, 0x54, 0x02, 0xa5, 0x75, 0x1a,
+		0x9a, 0x84, 0x65, 0x45, 0xe6, 0x33, 0x87, 0x99, 0x6c, 0x8a, 0x4f, 0x1d, 0x66, 0xb2, 0x69, 0x3e,
+		0x5d, 0x7d, 0xb2, 0x89, 0xcd, 0xd4, 0x5b, 0x92, 0xac, 0xbd, 0xa8, 0x3e, 0x4e, 0x00, 0x65, 0x55,
+		0x37, 0xb0, 0xda, 0x38, 0x33, 0x14, 0x39, 0x0e, 0xaf, 0xff, 0xc9, 0xc1, 0x5e, 0x38, 0x16, 0xf2,
+		0x62, 0x9b, 0x45, 0x33, 0x2a, 0x75, 0x55, 0x14, 0x40, 0x6e, 0xde, 0xa5, 0xa8, 0x7e, 0xf5, 0x7f,
+		0xa0, 0xa5, 0xa7, 0xd7, 0x78, 0x61, 0x36, 0x9e, 0x7f, 0xa7, 0x9e, 0x0f, 0x83, 0x57, 0xd3, 0xbe,
+		0x38, 0x70, 0xc7, 0xcb, 0x9f, 0x77, 0xe2, 0x39, 0x71, 0xd8, 0x57, 0xd7, 0xba, 0x2f, 0xbd, 0xe3,
+		0xa4, 0xed, 0xe2, 0xa8, 0xbf, 0x15, 0xa2, 0x9f, 0xfe, 0x3f, 0x00, 0x27, 0xc1, 0xec, 0x18, 0x27,
+		0x0e, 0x00, 0x00,
+	},
+	// google/protobuf/timestamp.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x4f, 0xcf, 0xcf, 0x4f,
+		0xcf, 0x49, 0xd5, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x4f, 0x2a, 0x4d, 0xd3, 0x2f, 0xc9, 0xcc, 0x4d,
+		0x2d, 0x2e, 0x49, 0xcc, 0x2d, 0xd0, 0x03, 0x0b, 0x09, 0xf1, 0x43, 0x14, 0xe8, 0xc1, 0x14, 0x28,
+		0x59, 0x73, 0x71, 0x86, 0xc0, 0xd4, 0x08, 0x49, 0x70, 0xb1, 0x17, 0xa7, 0x26, 0xe7, 0xe7, 0xa5,
+		0x14, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x07, 0xc1, 0xb8, 0x42, 0x22, 0x5c, 0xac, 0x79, 0x89,
+		0x79, 0xf9, 0xc5, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0xac, 0x41, 0x10, 0x8e, 0x53, 0x03, 0xe3, 0x8d,
+		0x87, 0x72, 0x0c, 0x1f, 0x1e, 0xca, 0x31, 0xae, 0x78, 0x24, 0xc7, 0x78, 0xe2, 0x91, 0x1c, 0xe3,
+		0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0xbe, 0x78, 0x24, 0xc7, 0xf0, 0xe1, 0x91, 0x1c,
+		0xe3, 0x8a, 0xc7, 0x72, 0x8c, 0x27, 0x1e, 0xcb, 0x31, 0x72, 0x09, 0x27, 0xe7, 0xe7, 0xea, 0xa1,
+		0x59, 0xee, 0xc4, 0x07, 0xb7, 0x3a, 0x00, 0x24, 0x14, 0xc0, 0x18, 0xc5, 0x5a, 0x52, 0x59, 0x90,
+		0x5a, 0xfc, 0x83, 0x91, 0x71, 0x11, 0x13, 0xb3, 0x7b, 0x80, 0xd3, 0x2a, 0x26, 0x39, 0x77, 0x88,
+		0x9e, 0x00, 0xa8, 0x1e, 0xbd, 0xf0, 0xd4, 0x9c, 0x1c, 0xef, 0xbc, 0xfc, 0xf2, 0xbc, 0x10, 0x90,
+		0xca, 0x24, 0x36, 0xb0, 0x61, 0xc6, 0x80, 0x01, 0x00, 0x0b, 0x23, 0x83, 0xdd, 0xfa, 0x00, 0x00,
+		0x00,
+	},
+	// google/protobuf/wrappers.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x4b, 0xcf, 0xcf, 0x4f,
+		0xcf, 0x49, 0xd5, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x4f, 0x2a, 0x4d, 0xd3, 0x2f, 0x2f, 0x4a, 0x2c,
+		0x28, 0x48, 0x2d, 0x2a, 0xd6, 0x03, 0x8b, 0x08, 0xf1, 0x43, 0xe4, 0xf5, 0x60, 0xf2, 0x4a, 0xca,
+		0x5c, 0xdc, 0x2e, 0xf9, 0xa5, 0x49, 0x39, 0xa9, 0x61, 0x89, 0x39, 0xa5, 0xa9, 0x42, 0x22, 0x5c,
+		0xac, 0x65, 0x20, 0x86, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x63, 0x10, 0x84, 0xa3, 0xa4, 0xc4, 0xc5,
+		0xe5, 0x96, 0x93, 0x9f, 0x58, 0x82, 0x45, 0x0d, 0x13, 0x92, 0x1a, 0xcf, 0xbc, 0x12, 0x33, 0x13,
+		0x2c, 0x6a, 0x98, 0x61, 0x6a, 0x94, 0xb9, 0xb8, 0x43, 0x71, 0x29, 0x62, 0x41, 0x35, 0xc8, 0xd8,
+		0x08, 0x8b, 0x1a, 0x56, 0x34, 0x83, 0xb0, 0x2a, 0xe2, 0x85, 0x29, 0x52, 0xe4, 0xe2, 0x74, 0xca,
+		0xcf, 0xcf, 0xc1, 0xa2, 0x84, 0x03, 0xc9, 0x9c, 0xe0, 0x92, 0xa2, 0xcc, 0xbc, 0x74, 0x2c, 0x8a,
+		0x38, 0x91, 0x1c, 0xe4, 0x54, 0x59, 0x92, 0x5a, 0x8c, 0x45, 0x0d, 0x0f, 0x54, 0x8d, 0x53, 0x3b,
+		0xe3, 0x8d, 0x87, 0x72, 0x0c, 0x1f, 0x1e, 0xca, 0x31, 0xfe, 0x78, 0x28, 0xc7, 0xd8, 0xf0, 0x48,
+		0x8e, 0x71, 0xc5, 0x23, 0x39, 0xc6, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c, 0xf0,
+		0x48, 0x8e, 0xf1, 0xc5, 0x23, 0x39, 0x86, 0x0f, 0x20, 0xf1, 0xc7, 0x72, 0x8c, 0x27, 0x1e, 0xcb,
+		0x31, 0x72, 0x09, 0x27, 0xe7, 0xe7, 0xea, 0xa1, 0x45, 0x87, 0x13, 0x6f, 0x38, 0x34, 0xbe, 0x02,
+		0x40, 0x22, 0x01, 0x8c, 0x51, 0xac, 0x25, 0x95, 0x05, 0xa9, 0xc5, 0x3f, 0x18, 0x19, 0x17, 0x31,
+		0x31, 0xbb, 0x07, 0x38, 0xad, 0x62, 0x92, 0x73, 0x87, 0x68, 0x09, 0x80, 0x6a, 0xd1, 0x0b, 0x4f,
+		0xcd, 0xc9, 0xf1, 0xce, 0xcb, 0x2f, 0xcf, 0x0b, 0x01, 0xa9, 0x4c, 0x62, 0x03, 0x9b, 0x65, 0x0c,
+		0x18, 0x00, 0x31, 0x55, 0x64, 0x90, 0x0a, 0x02, 0x00, 0x00,
 	},
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/service/sharddistributor/canary"
 	canaryConfig "github.com/uber/cadence/service/sharddistributor/canary/config"
+	"github.com/uber/cadence/service/sharddistributor/canary/executors"
 	"github.com/uber/cadence/service/sharddistributor/client/clientcommon"
 	"github.com/uber/cadence/service/sharddistributor/client/executorclient"
 	"github.com/uber/cadence/service/sharddistributor/client/spectatorclient"
@@ -53,10 +54,22 @@ func runApp(c *cli.Context) {
 
 	}
 
-	fx.New(opts(fixedNamespace, ephemeralNamespace, endpoint, canaryGRPCPort, numFixedExecutors, numEphemeralExecutors)).Run()
+	canaryCfg := canaryConfig.CanaryConfig{
+		NumFixedExecutors:       numFixedExecutors,
+		NumEphemeralExecutors:   numEphemeralExecutors,
+		ExecutorFailureRate:     c.Float64("executor-failure-rate"),
+		ExecutorFailureInterval: c.Duration("executor-failure-interval"),
+		ExecutorFailureMode:     c.String("executor-failure-mode"),
+		EphemeralLoadModel: canaryConfig.EphemeralLoadModel{
+			MaxWeight:             c.Int("ephemeral-max-shard-weight"),
+			ScaleLifetimeByWeight: c.Bool("ephemeral-scale-lifetime-by-weight"),
+		},
+	}
+
+	fx.New(opts(fixedNamespace, ephemeralNamespace, endpoint, canaryGRPCPort, canaryCfg)).Run()
 }
 
-func opts(fixedNamespace, ephemeralNamespace, endpoint string, canaryGRPCPort int, numFixedExecutors, numEphemeral int) fx.Option {
+func opts(fixedNamespace, ephemeralNamespace, endpoint string, canaryGRPCPort int, canaryCfg canaryConfig.CanaryConfig) fx.Option {
 	configuration := clientcommon.Config{
 		Namespaces: []clientcommon.NamespaceConfig{
 			{Namespace: fixedNamespace, HeartBeatInterval: 1 * time.Second, MigrationMode: config.MigrationModeONBOARDED},
@@ -142,10 +155,7 @@ func opts(fixedNamespace, ephemeralNamespace, endpoint string, canaryGRPCPort in
 			EphemeralNamespace:          ephemeralNamespace,
 			SharddistributorServiceName: shardDistributorServiceName,
 			Config: canaryConfig.Config{
-				Canary: canaryConfig.CanaryConfig{
-					NumFixedExecutors:     numFixedExecutors,
-					NumEphemeralExecutors: numEphemeral,
-				},
+				Canary: canaryCfg,
 			},
 		}),
 	)
@@ -198,6 +208,32 @@ func buildCLI() *cli.App {
 					Value: defaultNumExecutors,
 					Usage: "number of executors of ephemeral namespace to start. Don't use with num-executors",
 				},
+				&cli.Float64Flag{
+					Name:    "executor-failure-rate",
+					EnvVars: []string{"CANARY_EXECUTOR_FAILURE_RATE"},
+					Usage:   "probability that an executor fails at every executor-failure-interval. 0 disables failure injection",
+				},
+				&cli.DurationFlag{
+					Name:    "executor-failure-interval",
+					EnvVars: []string{"CANARY_EXECUTOR_FAILURE_INTERVAL"},
+					Value:   time.Minute,
+					Usage:   "how often the executor failure rate is applied",
+				},
+				&cli.StringFlag{
+					Name:    "executor-failure-mode",
+					EnvVars: []string{"CANARY_EXECUTOR_FAILURE_MODE"},
+					Value:   executors.FailureModeStopHeartbeat,
+					Usage:   "how an executor fails: stop-heartbeat stops the executor, exit terminates the canary process",
+				},
+				&cli.IntFlag{
+					Name:  "ephemeral-max-shard-weight",
+					Value: 1,
+					Usage: "heaviest weight an ephemeral shard can get, shards report their weight as their load",
+				},
+				&cli.BoolFlag{
+					Name:  "ephemeral-scale-lifetime-by-weight",
+					Usage: "make heavier ephemeral shards live proportionally longer",
+				},
 			},
 			Action: func(c *cli.Context) error {
 				runApp(c)
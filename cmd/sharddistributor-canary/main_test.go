@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/fx"
+
+	canaryConfig "github.com/uber/cadence/service/sharddistributor/canary/config"
 )
 
 func TestDependenciesAreSatisfied(t *testing.T) {
@@ -13,7 +15,9 @@ func TestDependenciesAreSatisfied(t *testing.T) {
 		defaultEphemeralNamespace,
 		defaultShardDistributorEndpoint,
 		defaultCanaryGRPCPort,
-		defaultNumExecutors,
-		defaultNumExecutors,
+		canaryConfig.CanaryConfig{
+			NumFixedExecutors:     defaultNumExecutors,
+			NumEphemeralExecutors: defaultNumExecutors,
+		},
 	)))
 }
@@ -122,6 +122,22 @@ func (c *Collection) GetIntPropertyFilteredByDomain(key dynamicproperties.IntKey
 	}
 }
 
+// GetIntPropertyFilteredByNamespace gets property with namespace filter and asserts that it's an integer
+func (c *Collection) GetIntPropertyFilteredByNamespace(key dynamicproperties.IntKey) dynamicproperties.IntPropertyFnWithNamespaceFilters {
+	return func(namespace string) int {
+		filters := c.toFilterMap(dynamicproperties.NamespaceFilter(namespace))
+		val, err := c.client.GetIntValue(
+			key,
+			filters,
+		)
+		if err != nil {
+			c.logError(key, filters, err)
+			return key.DefaultInt()
+		}
+		return val
+	}
+}
+
 // GetIntPropertyFilteredByWorkflowType gets property with workflow type filter and asserts that it's an integer
 func (c *Collection) GetIntPropertyFilteredByWorkflowType(key dynamicproperties.IntKey) dynamicproperties.IntPropertyFnWithWorkflowTypeFilter {
 	return func(domainName string, workflowType string) int {
@@ -463,6 +479,22 @@ func (c *Collection) GetMapPropertyFilteredByDomain(key dynamicproperties.MapKey
 	}
 }
 
+// GetMapPropertyFilteredByNamespace gets property with namespace filter and asserts that it's a map
+func (c *Collection) GetMapPropertyFilteredByNamespace(key dynamicproperties.MapKey) dynamicproperties.MapPropertyFnWithNamespaceFilters {
+	return func(namespace string) map[string]interface{} {
+		filters := c.toFilterMap(dynamicproperties.NamespaceFilter(namespace))
+		val, err := c.client.GetMapValue(
+			key,
+			filters,
+		)
+		if err != nil {
+			c.logError(key, filters, err)
+			return key.DefaultMap()
+		}
+		return val
+	}
+}
+
 // GetStringPropertyFilteredByDomain gets property with domain filter and asserts that it's a string
 func (c *Collection) GetStringPropertyFilteredByDomain(key dynamicproperties.StringKey) dynamicproperties.StringPropertyFnWithDomainFilter {
 	return func(domain string) string {
@@ -530,6 +562,22 @@ func (c *Collection) GetBoolPropertyFilteredByDomain(key dynamicproperties.BoolK
 	}
 }
 
+// GetBoolPropertyFilteredByNamespace gets property with namespace filter and asserts that it's a bool
+func (c *Collection) GetBoolPropertyFilteredByNamespace(key dynamicproperties.BoolKey) dynamicproperties.BoolPropertyFnWithNamespaceFilters {
+	return func(namespace string) bool {
+		filters := c.toFilterMap(dynamicproperties.NamespaceFilter(namespace))
+		val, err := c.client.GetBoolValue(
+			key,
+			filters,
+		)
+		if err != nil {
+			c.logError(key, filters, err)
+			return key.DefaultBool()
+		}
+		return val
+	}
+}
+
 // GetBoolPropertyFilteredByDomainID gets property with domainID filter and asserts that it's a bool
 func (c *Collection) GetBoolPropertyFilteredByDomainID(key dynamicproperties.BoolKey) dynamicproperties.BoolPropertyFnWithDomainIDFilter {
 	return func(domainID string) bool {
@@ -81,6 +81,15 @@ func (s *configSuite) TestGetIntPropertyFilteredByDomain() {
 	s.Equal(50, value(domain))
 }
 
+func (s *configSuite) TestGetIntPropertyFilteredByNamespace() {
+	key := dynamicproperties.TestGetIntPropertyKey
+	namespace := "testNamespace"
+	value := s.cln.GetIntPropertyFilteredByNamespace(key)
+	s.Equal(key.DefaultInt(), value(namespace))
+	s.client.SetValue(key, 50)
+	s.Equal(50, value(namespace))
+}
+
 func (s *configSuite) TestGetIntPropertyFilteredByWorkflowType() {
 	key := dynamicproperties.TestGetIntPropertyFilteredByWorkflowTypeKey
 	domain := "testDomain"
@@ -202,6 +211,15 @@ func (s *configSuite) TestGetBoolPropertyFilteredByDomain() {
 	s.Equal(true, value(domain))
 }
 
+func (s *configSuite) TestGetBoolPropertyFilteredByNamespace() {
+	key := dynamicproperties.TestGetBoolPropertyKey
+	namespace := "testNamespace"
+	value := s.cln.GetBoolPropertyFilteredByNamespace(key)
+	s.Equal(key.DefaultBool(), value(namespace))
+	s.client.SetValue(key, true)
+	s.Equal(true, value(namespace))
+}
+
 func (s *configSuite) TestGetBoolPropertyFilteredByDomainIDAndWorkflowID() {
 	key := dynamicproperties.TestGetBoolPropertyFilteredByDomainIDAndWorkflowIDKey
 	domainID := "testDomainID"
@@ -315,6 +333,18 @@ func (s *configSuite) TestGetMapPropertyFilteredByDomain() {
 	s.Equal("321", value(domain)["testKey"])
 }
 
+func (s *configSuite) TestGetMapPropertyFilteredByNamespace() {
+	key := dynamicproperties.TestGetMapPropertyKey
+	namespace := "testNamespace"
+	val := map[string]interface{}{
+		"testKey": 123,
+	}
+	value := s.cln.GetMapPropertyFilteredByNamespace(key)
+	s.Equal(key.DefaultMap(), value(namespace))
+	s.client.SetValue(key, val)
+	s.Equal(val, value(namespace))
+}
+
 func (s *configSuite) TestGetListProperty() {
 	key := dynamicproperties.TestGetListPropertyKey
 	arr := []interface{}{}
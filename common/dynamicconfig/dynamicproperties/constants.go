@@ -1634,6 +1634,57 @@ const (
 	// Allowed filters: N/A
 	ShardDistributorMaxEtcdTxnOps
 
+	// ShardDistributorStoreRetryMaxAttempts is the maximum number of attempts for a store write that fails with a transient error.
+	// KeyName: shardDistributor.storeRetryMaxAttempts
+	// Value type: Int
+	// Default value: 3
+	// Allowed filters: N/A
+	ShardDistributorStoreRetryMaxAttempts
+
+	// ShardDistributorMaxGroupShardsPerZone is the maximum number of shards of the same shard group that
+	// are assigned to executors in one failure zone. Zero disables the limit.
+	// KeyName: shardDistributor.maxGroupShardsPerZone
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorMaxGroupShardsPerZone
+
+	// ShardDistributorMinActiveExecutors is the minimum number of executors that keep owning shards.
+	// Load balancing and consolidation never empty an executor if that would leave fewer executors owning shards.
+	// Zero disables the guard.
+	// KeyName: shardDistributor.minActiveExecutors
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorMinActiveExecutors
+
+	// ShardDistributorAssignmentRampCap is the maximum number of shards one executor newly receives in a single
+	// rebalance cycle, so a freshly added executor fills up over several cycles instead of all at once.
+	// Zero disables the cap.
+	// KeyName: shardDistributor.assignmentRampCap
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorAssignmentRampCap
+
+	// ShardDistributorMaxMovesPerCycle is the maximum number of shards moved between executors in a single
+	// rebalance cycle, counting both the shards given to empty executors and the load balancing moves.
+	// Moves over the cap are deferred to the next cycle. Zero disables the cap.
+	// KeyName: shardDistributor.maxMovesPerCycle
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorMaxMovesPerCycle
+
+	// ShardDistributorRebalanceApplyWorkers is the number of concurrent store writes used to apply the moves of a
+	// rebalance cycle that only load balances. Each move is written separately, guarded by the leadership of the
+	// namespace. Zero writes the whole plan in a single transaction.
+	// KeyName: shardDistributor.rebalanceApplyWorkers
+	// Value type: Int
+	// Default value: 4
+	// Allowed filters: namespace
+	ShardDistributorRebalanceApplyWorkers
+
 	// HistoryTaskListNiceValue is the nice value for task processing priority per domain and task list.
 	// KeyName: history.taskListNiceValue
 	// Value type: Int
@@ -2400,6 +2451,48 @@ const (
 	// Default value: false
 	HistoryTaskDLQProcessorEnabled
 
+	// ShardDistributorRejectUnknownShardReports makes the shard distributor reject executor heartbeats
+	// that report shards unknown to the namespace state, instead of only counting and logging them.
+	// KeyName: shardDistributor.rejectUnknownShardReports
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: namespace
+	ShardDistributorRejectUnknownShardReports
+
+	// ShardDistributorRebalancePaused stops the leader from moving or assigning shards of a namespace, e.g. during
+	// an incident. Heartbeats and shard statistics are still recorded, and rebalancing resumes once unset.
+	// KeyName: shardDistributor.rebalancePaused
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: namespace
+	ShardDistributorRebalancePaused
+
+	// ShardDistributorStaticHashFallback makes the leader assign the shards of a namespace with the consistent-hash
+	// mode whatever the load balancing mode is, e.g. when the load-based balancer misbehaves. Shard statistics are
+	// still recorded, so the load balancing mode takes over again once unset.
+	// KeyName: shardDistributor.staticHashFallback
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: namespace
+	ShardDistributorStaticHashFallback
+
+	// ShardDistributorControlledHandoff makes heartbeat responses hand a moved shard over in steps: the new owner
+	// prepares the shard while the previous owner keeps serving it, and the previous owner only drains it once
+	// the new owner reported it ready, so the shard always has an executor serving it.
+	// KeyName: shardDistributor.controlledHandoff
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: namespace
+	ShardDistributorControlledHandoff
+
+	// ShardDistributorLoadBalancingGreedyExcludeDrainingShardLoad keeps the load reported for draining shards out
+	// of the smoothed shard load, and makes the greedy balancer move draining shards off their executor first.
+	// KeyName: shardDistributor.loadBalancingGreedy.excludeDrainingShardLoad
+	// Value type: Bool
+	// Default value: true
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyExcludeDrainingShardLoad
+
 	// LastBoolKey must be the last one in this const group
 	LastBoolKey
 )
@@ -2650,6 +2743,109 @@ const (
 	// Allowed filters: namespace
 	ShardDistributorLoadBalancingGreedySevereImbalanceRatio
 
+	// ShardDistributorLoadBalancingGreedyShardLoadFloor is the minimum load assumed for a shard when
+	// placing shards, so shards without statistics carry a nominal weight instead of zero.
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.shardLoadFloor
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyShardLoadFloor
+
+	// ShardDistributorLoadBalancingGreedyShardOverhead is a fixed load added to every shard when placing and
+	// rebalancing shards. It stands for the baseline cost of an idle shard, so shard count contributes to executor load.
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.shardOverhead
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyShardOverhead
+
+	// ShardDistributorLoadBalancingGreedyStatisticsFlushJitterCoefficient stretches or shrinks the statistics flush
+	// interval of each executor by up to +/- coefficient * interval, derived from the executor ID, so executors
+	// started together do not write their statistics at the same time.
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.statisticsFlushJitterCoefficient
+	// Value type: Float64
+	// Default value: 0.1
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyStatisticsFlushJitterCoefficient
+
+	// ShardDistributorExecutorLoadCapacity is the amount of shard load a single executor can serve.
+	// It is used to compute the capacity of a namespace when consolidating shards onto fewer executors,
+	// and new shards are not placed on executors whose shards already carry that much load.
+	//
+	// KeyName: shardDistributor.executorLoadCapacity
+	// Value type: Float64
+	// Default value: 0 (unknown capacity, consolidation disabled)
+	// Allowed filters: namespace
+	ShardDistributorExecutorLoadCapacity
+
+	// ShardDistributorConsolidationLoadThreshold is the fraction of the namespace capacity below which
+	// shards are packed onto the minimum number of executors so the emptied executors can be scaled down.
+	//
+	// KeyName: shardDistributor.consolidationLoadThreshold
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorConsolidationLoadThreshold
+
+	// ShardDistributorShardOvercommitFactor bounds the number of shards assigned to one executor to
+	// ceil(totalShards / activeExecutors * factor) when placing unassigned shards, so the limit follows the
+	// number of executors. Values below 1 are treated as 1.
+	//
+	// KeyName: shardDistributor.shardOvercommitFactor
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorShardOvercommitFactor
+
+	// ShardDistributorRebalanceJitterCoefficient randomizes the delay between rebalance cycles of a namespace
+	// by up to +/- coefficient * interval, so namespaces do not rebalance in lockstep. Clamped to [0, 1].
+	//
+	// KeyName: shardDistributor.rebalanceJitterCoefficient
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorRebalanceJitterCoefficient
+
+	// ShardDistributorMaxShardLoad is the highest load accepted from a shard report. Higher loads, e.g. from
+	// a buggy executor, are clamped to it before smoothing so a single report cannot dominate balancing.
+	//
+	// KeyName: shardDistributor.maxShardLoad
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorMaxShardLoad
+
+	// ShardDistributorHealthScoreLoadWeight is the weight of the load coefficient of variation in the
+	// assignment health score of a namespace.
+	//
+	// KeyName: shardDistributor.healthScoreLoadWeight
+	// Value type: Float64
+	// Default value: 1
+	// Allowed filters: namespace
+	ShardDistributorHealthScoreLoadWeight
+
+	// ShardDistributorHealthScoreShardCountWeight is the weight of the shard count coefficient of variation
+	// in the assignment health score of a namespace. An executor owning disproportionately many shards is at
+	// risk even when the load is balanced.
+	//
+	// KeyName: shardDistributor.healthScoreShardCountWeight
+	// Value type: Float64
+	// Default value: 1
+	// Allowed filters: namespace
+	ShardDistributorHealthScoreShardCountWeight
+
+	// ShardDistributorShardLoadBudget is the highest smoothed load a single shard is expected to carry. The
+	// leader reports the shards above it as hot shards, candidates for splitting upstream.
+	//
+	// KeyName: shardDistributor.shardLoadBudget
+	// Value type: Float64
+	// Default value: 0 (disabled)
+	// Allowed filters: namespace
+	ShardDistributorShardLoadBudget
+
 	// LastFloatKey must be the last one in this const group
 	LastFloatKey
 )
@@ -2780,6 +2976,7 @@ const (
 	//
 	// * "naive" 	- mode assigns shards to the least loaded hosts without considering the existing shard distribution
 	// * "greedy" 	- mode balances the load across all hosts while minimizing shard movements and uses shard statistics to make better decisions
+	// * "consistent-hash" 	- mode places shards on a hash ring of the executors, so only the shards of an executor that leaves move, without using load
 	//
 	// KeyName: shardDistributor.loadBalancingMode
 	// Value type: String
@@ -2787,6 +2984,83 @@ const (
 	// Allowed filters: namespace
 	ShardDistributorLoadBalancingMode
 
+	// ShardDistributorEmptyExecutorDonorSelection is the strategy used to pick which shard to take from a donor executor
+	// when shards are moved onto executors that currently own no shards
+	//
+	// * "heaviest-first" 		- takes the shard with the highest smoothed load from the donor
+	// * "lightest-first" 		- takes the shard with the lowest smoothed load from the donor
+	// * "closest-to-target" 	- takes the shard whose load is closest to what the empty executor still needs to reach the average load
+	//
+	// KeyName: shardDistributor.emptyExecutorDonorSelection
+	// Value type: String
+	// Default value: "heaviest-first"
+	// Allowed filters: namespace
+	ShardDistributorEmptyExecutorDonorSelection
+
+	// ShardDistributorLoadBalancingGreedyLoadAggregationMode is how the loads an executor reports for a shard within
+	// one statistics flush interval are combined before smoothing. It only applies when statistics are coalesced.
+	//
+	// * "last" 	- every report is smoothed in turn
+	// * "mean" 	- the mean of the reports in the interval is smoothed once
+	// * "max" 		- the highest report in the interval is smoothed once
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.loadAggregationMode
+	// Value type: String
+	// Default value: "last"
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyLoadAggregationMode
+
+	// ShardDistributorLoadBalancingGreedyShedSelectionMode is how the greedy load balancer picks the shard to shed
+	// from an overloaded executor.
+	//
+	// * "heaviest" 		- the shard whose move improves the balance the most
+	// * "weighted-random" 	- a random shard among those whose move improves the balance, weighted by shard load
+	// * "least-headroom" 	- like "heaviest", relieving the overloaded executors with the least headroom first
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.shedSelectionMode
+	// Value type: String
+	// Default value: "heaviest"
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyShedSelectionMode
+
+	// ShardDistributorLoadBalancingGreedyPlacementTieBreak is how the greedy load balancer chooses between executors
+	// with the same load when placing shards.
+	//
+	// * "shard-count" 			- the executor with the fewest shards, then the lowest executor ID
+	// * "random" 				- a random executor
+	// * "most-recently-emptied" 	- an executor without shards whose assignment changed most recently, then by shard count
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.placementTieBreak
+	// Value type: String
+	// Default value: "shard-count"
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyPlacementTieBreak
+
+	// ShardDistributorLoadBalancingGreedyRebalanceStrategy is how the greedy load balancer plans the moves of a
+	// rebalance.
+	//
+	// * "incremental" 	- one move at a time, each the one that improves the balance the most
+	// * "min-movement" 	- the fewest moves that bring every executor within the hysteresis bands
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.rebalanceStrategy
+	// Value type: String
+	// Default value: "incremental"
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyRebalanceStrategy
+
+	// ShardDistributorLoadBalancingGreedyMoveLoadReset is how much of the smoothed load of a shard is reset when
+	// it moves to another executor, so the reports of its new owner dominate it sooner.
+	//
+	// * "none" 	- the smoothed load is kept
+	// * "partial" 	- the smoothed load is halved
+	// * "full" 	- the smoothed load is dropped and the first report of the new owner is taken as is
+	//
+	// KeyName: shardDistributor.loadBalancingGreedy.moveLoadReset
+	// Value type: String
+	// Default value: "none"
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyMoveLoadReset
+
 	// HistoryTaskDLQMode enables writing tasks to the History Task Dead Letter Queue rather than discarding them.
 	// To enable this key, HistoryTaskDLQProcessorEnabled must be enabled.
 	//
@@ -3383,6 +3657,104 @@ const (
 	// Allowed filters: namespace
 	ShardDistributorLoadBalancingGreedyLoadSmoothingTimeConstant
 
+	// ShardDistributorLoadBalancingGreedyLoadHighWatermarkDecay is the time constant with which the
+	// load high watermark of a shard decays towards its current load.
+	// KeyName: shardDistributor.loadBalancingGreedy.loadHighWatermarkDecay
+	// Value type: Duration
+	// Default value: 5 minutes
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyLoadHighWatermarkDecay
+
+	// ShardDistributorLoadBalancingGreedyStatisticsFlushInterval is the minimum interval between two shard
+	// statistics writes for the same executor. Statistics computed in between are kept in memory. 0 disables coalescing.
+	// KeyName: shardDistributor.loadBalancingGreedy.statisticsFlushInterval
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyStatisticsFlushInterval
+
+	// ShardDistributorLoadBalancingGreedyLoadWindow is the window over which the recent reported loads of a shard
+	// are averaged into its windowed load. 0 disables the windowed load.
+	// KeyName: shardDistributor.loadBalancingGreedy.loadWindow
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyLoadWindow
+
+	// ShardDistributorLoadBalancingGreedyLoadHistoryRetention is how long the downsampled smoothed load history
+	// of a shard is kept for capacity planning. 0 disables the load history.
+	// KeyName: shardDistributor.loadBalancingGreedy.loadHistoryRetention
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyLoadHistoryRetention
+
+	// ShardDistributorLoadBalancingGreedyLoadHistoryResolution is the interval the smoothed load history of a shard
+	// is downsampled to, one sample is kept per interval.
+	// KeyName: shardDistributor.loadBalancingGreedy.loadHistoryResolution
+	// Value type: Duration
+	// Default value: 1m
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyLoadHistoryResolution
+
+	// ShardDistributorStoreRetryInitialInterval is the initial backoff between attempts of a store write
+	// that fails with a transient error. The backoff grows exponentially between attempts.
+	// KeyName: shardDistributor.storeRetryInitialInterval
+	// Value type: Duration
+	// Default value: 50ms
+	// Allowed filters: N/A
+	ShardDistributorStoreRetryInitialInterval
+
+	// ShardDistributorShardLeaseDuration is how long an executor may keep processing a shard after the last
+	// heartbeat response that renewed its lease. Executors stop shards whose lease expired. Zero disables leases.
+	// KeyName: shardDistributor.shardLeaseDuration
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorShardLeaseDuration
+
+	// ShardDistributorRebalanceInterval is the delay between periodic rebalance cycles of a namespace.
+	// Zero uses the process period from the static configuration.
+	// KeyName: shardDistributor.rebalanceInterval
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorRebalanceInterval
+
+	// ShardDistributorZombieShardAge is how long an assigned shard may go without a load report before the
+	// leader reports it as a zombie shard. Only applies to namespaces that keep shard statistics. Zero disables the detection.
+	// KeyName: shardDistributor.zombieShardAge
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorZombieShardAge
+
+	// ShardDistributorLoadBalancingGreedyMoveAgingWindow is the time after a move over which the willingness to
+	// move a shard again grows from none to full in greedy load balancing mode, so shards that have been stable
+	// longer are preferred when shedding load. Zero keeps the per-shard cooldown as the only restriction.
+	// KeyName: shardDistributor.loadBalancingGreedy.moveAgingWindow
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyMoveAgingWindow
+
+	// ShardDistributorLoadBalancingGreedyColdStartGracePeriod is how long after its first heartbeat the load of an
+	// executor is provisional in greedy load balancing mode. Cold caches make its reports unreliable, so it is
+	// balanced with the average shard load of the other executors and its shards are not shed. Zero disables it.
+	// KeyName: shardDistributor.loadBalancingGreedy.coldStartGracePeriod
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: namespace
+	ShardDistributorLoadBalancingGreedyColdStartGracePeriod
+
+	// ShardDistributorStabilityWindow is the sliding window over which the leader reports the average number of
+	// shards moved per rebalance cycle and the fraction of shards that did not move. Zero disables the reporting.
+	// KeyName: shardDistributor.stabilityWindow
+	// Value type: Duration
+	// Default value: 1h
+	// Allowed filters: namespace
+	ShardDistributorStabilityWindow
+
 	// LastDurationKey must be the last one in this const group
 	LastDurationKey
 )
@@ -3452,6 +3824,47 @@ const (
 	// Allowed filters: N/A
 	SearchAttributesHiddenValueKeys
 
+	// ShardDistributorLoadDimensionWeights is the weight of each shard load dimension when combining them into a single load
+	// Dimensions without a weight are combined with a weight of 1
+	// KeyName: shardDistributor.loadDimensionWeights
+	// Value type: Map
+	// Default value: empty map
+	// Allowed filters: namespace
+	ShardDistributorLoadDimensionWeights
+
+	// ShardDistributorShardGroups maps shard IDs to the group they belong to. Shards of the same group
+	// are spread across failure zones, see ShardDistributorMaxGroupShardsPerZone
+	// KeyName: shardDistributor.shardGroups
+	// Value type: Map
+	// Default value: empty map
+	// Allowed filters: namespace
+	ShardDistributorShardGroups
+
+	// ShardDistributorPinnedShards maps the IDs of shards that must not be moved automatically to the executor
+	// they are required on. An empty executor ID pins the shard to its current executor
+	// KeyName: shardDistributor.pinnedShards
+	// Value type: Map
+	// Default value: empty map
+	// Allowed filters: namespace
+	ShardDistributorPinnedShards
+
+	// ShardDistributorShardLabelSelectors maps shard IDs to the executor labels they require, written as
+	// comma separated key=value pairs, e.g. "gpu=true,region=us-east". A shard is only placed on executors
+	// reporting every required label
+	// KeyName: shardDistributor.shardLabelSelectors
+	// Value type: Map
+	// Default value: empty map
+	// Allowed filters: namespace
+	ShardDistributorShardLabelSelectors
+
+	// ShardDistributorShardSLATiers maps shard IDs to their SLA tier, "strict" or "best-effort". Strict shards
+	// are placed before best-effort shards, so they get the least loaded executors. Shards without a tier are best-effort
+	// KeyName: shardDistributor.shardSLATiers
+	// Value type: Map
+	// Default value: empty map
+	// Allowed filters: namespace
+	ShardDistributorShardSLATiers
+
 	// LastMapKey must be the last one in this const group
 	LastMapKey
 )
@@ -4580,6 +4993,41 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "ShardDistributorMaxEtcdTxnOps is the maximum number of operations per etcd transaction, must not exceed the etcd cluster's configured --max-txn-ops limit",
 		DefaultValue: 128,
 	},
+	ShardDistributorStoreRetryMaxAttempts: {
+		KeyName:      "shardDistributor.storeRetryMaxAttempts",
+		Description:  "ShardDistributorStoreRetryMaxAttempts is the maximum number of attempts for a store write that fails with a transient error",
+		DefaultValue: 3,
+	},
+	ShardDistributorMaxGroupShardsPerZone: {
+		KeyName:      "shardDistributor.maxGroupShardsPerZone",
+		Description:  "ShardDistributorMaxGroupShardsPerZone is the maximum number of shards of the same shard group assigned to executors in one failure zone",
+		DefaultValue: 0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorMinActiveExecutors: {
+		KeyName:      "shardDistributor.minActiveExecutors",
+		Description:  "ShardDistributorMinActiveExecutors is the minimum number of executors that keep owning shards when load balancing and consolidating",
+		DefaultValue: 0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorAssignmentRampCap: {
+		KeyName:      "shardDistributor.assignmentRampCap",
+		Description:  "ShardDistributorAssignmentRampCap is the maximum number of shards one executor newly receives in a single rebalance cycle",
+		DefaultValue: 0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorMaxMovesPerCycle: {
+		KeyName:      "shardDistributor.maxMovesPerCycle",
+		Description:  "ShardDistributorMaxMovesPerCycle is the maximum number of shards moved between executors in a single rebalance cycle, moves over the cap are deferred to the next cycle",
+		DefaultValue: 0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorRebalanceApplyWorkers: {
+		KeyName:      "shardDistributor.rebalanceApplyWorkers",
+		Description:  "ShardDistributorRebalanceApplyWorkers is the number of concurrent store writes used to apply the moves of a rebalance cycle that only load balances",
+		DefaultValue: 4,
+		Filters:      []Filter{Namespace},
+	},
 	HistoryTaskListNiceValue: {
 		KeyName:      "history.taskListNiceValue",
 		Description:  "HistoryTaskListNiceValue is the nice value for task processing priority per domain and task list",
@@ -5252,6 +5700,36 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "HistoryTaskDLQProcessorEnabled enables processing HistoryTaskDLQ messages",
 		DefaultValue: false,
 	},
+	ShardDistributorRejectUnknownShardReports: {
+		KeyName:      "shardDistributor.rejectUnknownShardReports",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorRejectUnknownShardReports makes the shard distributor reject executor heartbeats that report shards unknown to the namespace state",
+		DefaultValue: false,
+	},
+	ShardDistributorRebalancePaused: {
+		KeyName:      "shardDistributor.rebalancePaused",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorRebalancePaused stops the leader from moving or assigning shards of a namespace while heartbeats are still recorded",
+		DefaultValue: false,
+	},
+	ShardDistributorStaticHashFallback: {
+		KeyName:      "shardDistributor.staticHashFallback",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorStaticHashFallback makes the leader assign the shards of a namespace with the consistent-hash mode whatever the load balancing mode is",
+		DefaultValue: false,
+	},
+	ShardDistributorControlledHandoff: {
+		KeyName:      "shardDistributor.controlledHandoff",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorControlledHandoff makes heartbeat responses hand a moved shard over in steps, so the shard always has an executor serving it",
+		DefaultValue: false,
+	},
+	ShardDistributorLoadBalancingGreedyExcludeDrainingShardLoad: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.excludeDrainingShardLoad",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyExcludeDrainingShardLoad keeps the load of draining shards out of the smoothed load and moves draining shards first",
+		DefaultValue: true,
+	},
 }
 
 var FloatKeys = map[FloatKey]DynamicFloat{
@@ -5451,6 +5929,72 @@ var FloatKeys = map[FloatKey]DynamicFloat{
 		DefaultValue: 1.3,
 		Filters:      []Filter{Namespace},
 	},
+	ShardDistributorLoadBalancingGreedyShardLoadFloor: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.shardLoadFloor",
+		Description:  "ShardDistributorLoadBalancingGreedyShardLoadFloor is the minimum load assumed for a shard when placing shards",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyShardOverhead: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.shardOverhead",
+		Description:  "ShardDistributorLoadBalancingGreedyShardOverhead is a fixed load added to every shard when placing and rebalancing shards",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyStatisticsFlushJitterCoefficient: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.statisticsFlushJitterCoefficient",
+		Description:  "ShardDistributorLoadBalancingGreedyStatisticsFlushJitterCoefficient stretches or shrinks the statistics flush interval of each executor by up to +/- coefficient * interval, derived from the executor ID",
+		DefaultValue: 0.1,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorExecutorLoadCapacity: {
+		KeyName:      "shardDistributor.executorLoadCapacity",
+		Description:  "ShardDistributorExecutorLoadCapacity is the amount of shard load a single executor can serve",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorConsolidationLoadThreshold: {
+		KeyName:      "shardDistributor.consolidationLoadThreshold",
+		Description:  "ShardDistributorConsolidationLoadThreshold is the fraction of the namespace capacity below which shards are packed onto fewer executors",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorShardOvercommitFactor: {
+		KeyName:      "shardDistributor.shardOvercommitFactor",
+		Description:  "ShardDistributorShardOvercommitFactor bounds the shards placed on one executor to ceil(totalShards / activeExecutors * factor)",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorRebalanceJitterCoefficient: {
+		KeyName:      "shardDistributor.rebalanceJitterCoefficient",
+		Description:  "ShardDistributorRebalanceJitterCoefficient randomizes the delay between rebalance cycles by up to +/- coefficient * interval",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorMaxShardLoad: {
+		KeyName:      "shardDistributor.maxShardLoad",
+		Description:  "ShardDistributorMaxShardLoad is the highest load accepted from a shard report, higher loads are clamped to it",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorHealthScoreLoadWeight: {
+		KeyName:      "shardDistributor.healthScoreLoadWeight",
+		Description:  "ShardDistributorHealthScoreLoadWeight is the weight of the load coefficient of variation in the assignment health score",
+		DefaultValue: 1.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorHealthScoreShardCountWeight: {
+		KeyName:      "shardDistributor.healthScoreShardCountWeight",
+		Description:  "ShardDistributorHealthScoreShardCountWeight is the weight of the shard count coefficient of variation in the assignment health score",
+		DefaultValue: 1.0,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorShardLoadBudget: {
+		KeyName:      "shardDistributor.shardLoadBudget",
+		Description:  "ShardDistributorShardLoadBudget is the highest smoothed load a single shard is expected to carry, shards above it are reported as hot shards; zero disables the reporting",
+		DefaultValue: 0.0,
+		Filters:      []Filter{Namespace},
+	},
 }
 
 var StringKeys = map[StringKey]DynamicString{
@@ -5554,6 +6098,42 @@ var StringKeys = map[StringKey]DynamicString{
 		Description:  "ShardDistributorLoadBalancingMode is the load balancing mode for the shard distributor. Depending on the mode, the shard distributor will use different ways to distribute the shards",
 		DefaultValue: "naive",
 	},
+	ShardDistributorEmptyExecutorDonorSelection: {
+		KeyName:      "shardDistributor.emptyExecutorDonorSelection",
+		Description:  "ShardDistributorEmptyExecutorDonorSelection is the strategy used to pick which shard to take from a donor executor when filling empty executors",
+		DefaultValue: "heaviest-first",
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyLoadAggregationMode: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.loadAggregationMode",
+		Description:  "ShardDistributorLoadBalancingGreedyLoadAggregationMode is how the loads reported for a shard within one statistics flush interval are combined before smoothing",
+		DefaultValue: "last",
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyShedSelectionMode: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.shedSelectionMode",
+		Description:  "ShardDistributorLoadBalancingGreedyShedSelectionMode is how the greedy load balancer picks the shard to shed from an overloaded executor",
+		DefaultValue: "heaviest",
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyPlacementTieBreak: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.placementTieBreak",
+		Description:  "ShardDistributorLoadBalancingGreedyPlacementTieBreak is how the greedy load balancer chooses between executors with the same load when placing shards",
+		DefaultValue: "shard-count",
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyRebalanceStrategy: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.rebalanceStrategy",
+		Description:  "ShardDistributorLoadBalancingGreedyRebalanceStrategy is how the greedy load balancer plans the moves of a rebalance",
+		DefaultValue: "incremental",
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorLoadBalancingGreedyMoveLoadReset: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.moveLoadReset",
+		Description:  "ShardDistributorLoadBalancingGreedyMoveLoadReset is how much of the smoothed load of a shard is reset when it moves to another executor",
+		DefaultValue: "none",
+		Filters:      []Filter{Namespace},
+	},
 	HistoryTaskDLQMode: {
 		KeyName:      "history.historyTaskDLQMode",
 		Description:  "HistoryTaskDLQMode is the key to enable history task dead letter queue. When enabled, the history task will be sent to a dead letter queue if it fails to be processed after a certain number of retries.",
@@ -6092,6 +6672,77 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "ShardDistributorLoadBalancingGreedyLoadSmoothingTimeConstant is the time constant for exponential smoothing of shard load in greedy load balancing mode",
 		DefaultValue: time.Minute,
 	},
+	ShardDistributorLoadBalancingGreedyLoadHighWatermarkDecay: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.loadHighWatermarkDecay",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyLoadHighWatermarkDecay is the time constant with which the load high watermark of a shard decays towards its current load",
+		DefaultValue: 5 * time.Minute,
+	},
+	ShardDistributorLoadBalancingGreedyStatisticsFlushInterval: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.statisticsFlushInterval",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyStatisticsFlushInterval is the minimum interval between two shard statistics writes for the same executor. 0 disables coalescing",
+		DefaultValue: 0,
+	},
+	ShardDistributorLoadBalancingGreedyLoadWindow: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.loadWindow",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyLoadWindow is the window over which the recent reported loads of a shard are averaged into its windowed load. 0 disables the windowed load",
+		DefaultValue: 0,
+	},
+	ShardDistributorLoadBalancingGreedyLoadHistoryRetention: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.loadHistoryRetention",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyLoadHistoryRetention is how long the downsampled smoothed load history of a shard is kept. 0 disables the load history",
+		DefaultValue: 0,
+	},
+	ShardDistributorLoadBalancingGreedyLoadHistoryResolution: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.loadHistoryResolution",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyLoadHistoryResolution is the interval the smoothed load history of a shard is downsampled to",
+		DefaultValue: time.Minute,
+	},
+	ShardDistributorStoreRetryInitialInterval: {
+		KeyName:      "shardDistributor.storeRetryInitialInterval",
+		Description:  "ShardDistributorStoreRetryInitialInterval is the initial backoff between attempts of a store write that fails with a transient error",
+		DefaultValue: 50 * time.Millisecond,
+	},
+	ShardDistributorShardLeaseDuration: {
+		KeyName:      "shardDistributor.shardLeaseDuration",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorShardLeaseDuration is how long an executor may keep processing a shard without a heartbeat response renewing its lease",
+		DefaultValue: time.Duration(0),
+	},
+	ShardDistributorRebalanceInterval: {
+		KeyName:      "shardDistributor.rebalanceInterval",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorRebalanceInterval is the delay between periodic rebalance cycles of a namespace; zero uses the process period",
+		DefaultValue: time.Duration(0),
+	},
+	ShardDistributorZombieShardAge: {
+		KeyName:      "shardDistributor.zombieShardAge",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorZombieShardAge is how long an assigned shard may go without a load report before it is reported as a zombie shard; zero disables the detection",
+		DefaultValue: time.Duration(0),
+	},
+	ShardDistributorLoadBalancingGreedyMoveAgingWindow: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.moveAgingWindow",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyMoveAgingWindow is the time after a move over which the willingness to move a shard again grows from none to full; zero disables the aging",
+		DefaultValue: time.Duration(0),
+	},
+	ShardDistributorLoadBalancingGreedyColdStartGracePeriod: {
+		KeyName:      "shardDistributor.loadBalancingGreedy.coldStartGracePeriod",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorLoadBalancingGreedyColdStartGracePeriod is how long after its first heartbeat the reported load of an executor is replaced by a neutral estimate when rebalancing; zero disables it",
+		DefaultValue: time.Duration(0),
+	},
+	ShardDistributorStabilityWindow: {
+		KeyName:      "shardDistributor.stabilityWindow",
+		Filters:      []Filter{Namespace},
+		Description:  "ShardDistributorStabilityWindow is the sliding window over which shard moves per rebalance cycle and the fraction of stable shards are reported; zero disables the reporting",
+		DefaultValue: time.Hour,
+	},
 }
 
 var MapKeys = map[MapKey]DynamicMap{
@@ -6141,6 +6792,36 @@ var MapKeys = map[MapKey]DynamicMap{
 		Description:  "SearchAttributesHiddenValueKeys is the list of search attributes that values should be hidden",
 		DefaultValue: map[string]interface{}{},
 	},
+	ShardDistributorLoadDimensionWeights: {
+		KeyName:      "shardDistributor.loadDimensionWeights",
+		Description:  "ShardDistributorLoadDimensionWeights is the weight of each shard load dimension when combining them into a single load",
+		DefaultValue: nil,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorShardGroups: {
+		KeyName:      "shardDistributor.shardGroups",
+		Description:  "ShardDistributorShardGroups maps shard IDs to the group they belong to, shards of the same group are spread across failure zones",
+		DefaultValue: nil,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorPinnedShards: {
+		KeyName:      "shardDistributor.pinnedShards",
+		Description:  "ShardDistributorPinnedShards maps the IDs of shards that must not be moved automatically to the executor they are required on, an empty executor ID pins the shard to its current executor",
+		DefaultValue: nil,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorShardLabelSelectors: {
+		KeyName:      "shardDistributor.shardLabelSelectors",
+		Description:  "ShardDistributorShardLabelSelectors maps shard IDs to the executor labels they require as comma separated key=value pairs, a shard is only placed on executors reporting every required label",
+		DefaultValue: nil,
+		Filters:      []Filter{Namespace},
+	},
+	ShardDistributorShardSLATiers: {
+		KeyName:      "shardDistributor.shardSLATiers",
+		Description:  "ShardDistributorShardSLATiers maps shard IDs to their SLA tier, strict or best-effort, strict shards are placed before best-effort shards so they get the least loaded executors",
+		DefaultValue: nil,
+		Filters:      []Filter{Namespace},
+	},
 }
 
 var ListKeys = map[ListKey]DynamicList{
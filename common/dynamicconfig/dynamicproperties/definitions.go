@@ -38,6 +38,9 @@ type IntPropertyFn func(opts ...FilterOption) int
 // IntPropertyFnWithDomainFilter is a wrapper to get int property from dynamic config with domain as filter
 type IntPropertyFnWithDomainFilter func(domain string) int
 
+// IntPropertyFnWithNamespaceFilters is a wrapper to get int property from dynamic config with namespace as filter
+type IntPropertyFnWithNamespaceFilters func(namespace string) int
+
 // IntPropertyFnWithTaskListInfoFilters is a wrapper to get int property from dynamic config with three filters: domain, taskList, taskType
 type IntPropertyFnWithTaskListInfoFilters func(domain string, taskList string, taskType int) int
 
@@ -89,6 +92,9 @@ type MapPropertyFn func(opts ...FilterOption) map[string]interface{}
 // MapPropertyFnWithDomainFilter is a wrapper to get map property from dynamic config with domainName as filter
 type MapPropertyFnWithDomainFilter func(domain string) map[string]interface{}
 
+// MapPropertyFnWithNamespaceFilters is a wrapper to get map property from dynamic config with namespace as filter
+type MapPropertyFnWithNamespaceFilters func(namespace string) map[string]interface{}
+
 // StringPropertyFnWithDomainFilter is a wrapper to get string property from dynamic config
 type StringPropertyFnWithDomainFilter func(domain string) string
 
@@ -107,6 +113,9 @@ type BoolPropertyFnWithDomainIDFilter func(domainID string) bool
 // BoolPropertyFnWithDomainIDAndWorkflowIDFilter is a wrapper to get bool property from dynamic config with domainID and workflowID as filter
 type BoolPropertyFnWithDomainIDAndWorkflowIDFilter func(domainID string, workflowID string) bool
 
+// BoolPropertyFnWithNamespaceFilters is a wrapper to get bool property from dynamic config with namespace as filter
+type BoolPropertyFnWithNamespaceFilters func(namespace string) bool
+
 // BoolPropertyFnWithTaskListInfoFilters is a wrapper to get bool property from dynamic config with three filters: domain, taskList, taskType
 type BoolPropertyFnWithTaskListInfoFilters func(domain string, taskList string, taskType int) bool
 
@@ -1532,15 +1532,23 @@ const (
 	// ShardDistributorGetShardOwnerScope tracks GetShardOwner API calls received by service
 	ShardDistributorGetShardOwnerScope = iota + NumWorkerScopes
 	ShardDistributorWatchNamespaceStateScope
+	ShardDistributorPlanExecutorRemovalScope
+	ShardDistributorForceAssignScope
+	ShardDistributorGetShardLoadHistoryScope
+	ShardDistributorExplainUnassignedScope
 	ShardDistributorHeartbeatScope
 	ShardDistributorAssignLoopScope
 
 	ShardDistributorStoreGetShardOwnerScope
+	ShardDistributorStoreGetShardOwnersScope
 	ShardDistributorStoreAssignShardScope
 	ShardDistributorStoreAssignShardsScope
+	ShardDistributorStoreReleaseShardsScope
 	ShardDistributorStoreDeleteExecutorsScope
 	ShardDistributorStoreGetShardStatsScope
 	ShardDistributorStoreDeleteShardStatsScope
+	ShardDistributorStoreSetShardWeightOverrideScope
+	ShardDistributorStoreSetShardPinScope
 	ShardDistributorStoreGetHeartbeatScope
 	ShardDistributorStoreGetExecutorScope
 	ShardDistributorStoreGetStateScope
@@ -2259,15 +2267,23 @@ var ScopeDefs = map[ServiceIdx]map[ScopeIdx]scopeDefinition{
 	ShardDistributor: {
 		ShardDistributorGetShardOwnerScope:                         {operation: "GetShardOwner"},
 		ShardDistributorWatchNamespaceStateScope:                   {operation: "WatchNamespaceState"},
+		ShardDistributorPlanExecutorRemovalScope:                   {operation: "PlanExecutorRemoval"},
+		ShardDistributorForceAssignScope:                           {operation: "ForceAssign"},
+		ShardDistributorGetShardLoadHistoryScope:                   {operation: "GetShardLoadHistory"},
+		ShardDistributorExplainUnassignedScope:                     {operation: "ExplainUnassigned"},
 		ShardDistributorHeartbeatScope:                             {operation: "ExecutorHeartbeat"},
 		ShardDistributorAssignLoopScope:                            {operation: "ShardAssignLoop"},
 		ShardDistributorExecutorScope:                              {operation: "Executor"},
 		ShardDistributorStoreGetShardOwnerScope:                    {operation: "StoreGetShardOwner"},
+		ShardDistributorStoreGetShardOwnersScope:                   {operation: "StoreGetShardOwners"},
 		ShardDistributorStoreAssignShardScope:                      {operation: "StoreAssignShard"},
 		ShardDistributorStoreAssignShardsScope:                     {operation: "StoreAssignShards"},
+		ShardDistributorStoreReleaseShardsScope:                    {operation: "StoreReleaseShards"},
 		ShardDistributorStoreDeleteExecutorsScope:                  {operation: "StoreDeleteExecutors"},
 		ShardDistributorStoreGetShardStatsScope:                    {operation: "StoreGetShardStats"},
 		ShardDistributorStoreDeleteShardStatsScope:                 {operation: "StoreDeleteShardStats"},
+		ShardDistributorStoreSetShardWeightOverrideScope:           {operation: "StoreSetShardWeightOverride"},
+		ShardDistributorStoreSetShardPinScope:                      {operation: "StoreSetShardPin"},
 		ShardDistributorStoreGetHeartbeatScope:                     {operation: "StoreGetHeartbeat"},
 		ShardDistributorStoreGetExecutorScope:                      {operation: "StoreGetExecutor"},
 		ShardDistributorStoreGetStateScope:                         {operation: "StoreGetState"},
@@ -3259,6 +3275,16 @@ const (
 	ShardDistributorAssignLoopDeletedShards
 	// ShardDistributorAssignLoopMovedShardLoad tracks the load of a shard that was moved due to load rebalancing
 	ShardDistributorAssignLoopMovedShardLoad
+	// ShardDistributorAssignLoopAllExecutorsDraining counts the rebalance cycles held because every executor is draining
+	ShardDistributorAssignLoopAllExecutorsDraining
+	// ShardDistributorAssignLoopPaused counts the rebalance cycles skipped because rebalancing of the namespace is paused
+	ShardDistributorAssignLoopPaused
+	// ShardDistributorAssignLoopZombieShards tracks the assigned shards without a load report for longer than the zombie shard age
+	ShardDistributorAssignLoopZombieShards
+	// ShardDistributorAssignLoopOverBudgetShards tracks the shards whose smoothed load exceeds the shard load budget
+	ShardDistributorAssignLoopOverBudgetShards
+	// ShardDistributorAssignLoopPendingShards tracks the shards left unassigned because the namespace has no executors
+	ShardDistributorAssignLoopPendingShards
 
 	// ShardDistributorAssignmentLoadMaxOverMean measures max/mean across executor reported loads
 	ShardDistributorAssignmentLoadMaxOverMean
@@ -3270,9 +3296,40 @@ const (
 	ShardDistributorAssignmentSmoothedLoadCV
 	// ShardDistributorAssignmentSmoothedLoadMissingRatio measures the fraction of assigned shards with no smoothed load
 	ShardDistributorAssignmentSmoothedLoadMissingRatio
+	// ShardDistributorAssignmentShardCountCV measures coefficient of variation across executor shard counts
+	ShardDistributorAssignmentShardCountCV
+	// ShardDistributorAssignmentHealthScore measures the assignment health in (0, 1], blending load and shard count CV
+	ShardDistributorAssignmentHealthScore
+	// ShardDistributorAssignmentMovesPerCycle measures the average number of shards moved per rebalance cycle over the stability window
+	ShardDistributorAssignmentMovesPerCycle
+	// ShardDistributorAssignmentStableShardRatio measures the fraction of shards that did not move within the stability window
+	ShardDistributorAssignmentStableShardRatio
 	// ShardDistributorIsLeader reports whether this instance is currently the leader (1) or not (0) for a namespace
 	ShardDistributorIsLeader
 
+	// ShardDistributorHeartbeatReceived counts the executor heartbeats received by the handler
+	ShardDistributorHeartbeatReceived
+	// ShardDistributorHeartbeatWriteSkipped counts the heartbeats that were not written to the store
+	ShardDistributorHeartbeatWriteSkipped
+	// ShardDistributorVersionConflicts counts the store writes that failed due to a concurrent update
+	ShardDistributorVersionConflicts
+	// ShardDistributorHeartbeatUnknownShardReports counts heartbeat shard reports for shards unknown to the namespace
+	ShardDistributorHeartbeatUnknownShardReports
+	// ShardDistributorHeartbeatReleasedShards counts assigned shards released because the executor reported them as done
+	ShardDistributorHeartbeatReleasedShards
+	// ShardDistributorHeartbeatClampedShardLoads counts shard reports whose load was clamped to the configured maximum
+	ShardDistributorHeartbeatClampedShardLoads
+	// ShardDistributorShardStatisticsUpdateLatency measures how long it takes to update shard statistics on heartbeat
+	ShardDistributorShardStatisticsUpdateLatency
+	// ShardDistributorHeartbeatDeduplicated counts the heartbeats that joined an in-flight heartbeat of the same executor
+	ShardDistributorHeartbeatDeduplicated
+	// ShardDistributorHeartbeatDuplicateSequence counts the heartbeats skipped because their sequence number was already processed
+	ShardDistributorHeartbeatDuplicateSequence
+	// ShardDistributorHeartbeatReadOnlyStore counts the heartbeats served without being recorded because the store was read-only
+	ShardDistributorHeartbeatReadOnlyStore
+	// ShardDistributorAssignLoopDuplicateShards counts the duplicate shard owners dropped by the rebalance cycle
+	ShardDistributorAssignLoopDuplicateShards
+
 	NumShardDistributorMetrics
 )
 
@@ -4217,9 +4274,14 @@ var MetricDefs = map[ServiceIdx]map[MetricIdx]metricDefinition{
 		ShardDistributorWatchProcessingLatency: {metricName: "shard_distributor_watch_processing_latency", metricType: Histogram, buckets: Default1ms100s.buckets()},
 		ShardDistributorWatchEventsReceived:    {metricName: "shard_distributor_watch_events_received", metricType: Counter},
 
-		ShardDistributorAssignLoopLoadBasedMoves: {metricName: "shard_distributor_shard_assign_load_based_moves", metricType: Counter},
-		ShardDistributorAssignLoopDeletedShards:  {metricName: "shard_distributor_shard_assign_deleted_shards", metricType: Gauge},
-		ShardDistributorAssignLoopMovedShardLoad: {metricName: "shard_distributor_shard_assign_moved_shard_load", metricType: Gauge},
+		ShardDistributorAssignLoopLoadBasedMoves:       {metricName: "shard_distributor_shard_assign_load_based_moves", metricType: Counter},
+		ShardDistributorAssignLoopDeletedShards:        {metricName: "shard_distributor_shard_assign_deleted_shards", metricType: Gauge},
+		ShardDistributorAssignLoopMovedShardLoad:       {metricName: "shard_distributor_shard_assign_moved_shard_load", metricType: Gauge},
+		ShardDistributorAssignLoopAllExecutorsDraining: {metricName: "shard_distributor_shard_assign_all_executors_draining", metricType: Counter},
+		ShardDistributorAssignLoopPaused:               {metricName: "shard_distributor_shard_assign_paused", metricType: Counter},
+		ShardDistributorAssignLoopZombieShards:         {metricName: "shard_distributor_shard_assign_zombie_shards", metricType: Gauge},
+		ShardDistributorAssignLoopOverBudgetShards:     {metricName: "shard_distributor_shard_assign_over_budget_shards", metricType: Gauge},
+		ShardDistributorAssignLoopPendingShards:        {metricName: "shard_distributor_shard_assign_pending_shards", metricType: Gauge},
 
 		ShardDistributorAssignmentLoadMaxOverMean:         {metricName: "shard_distributor_assignment_load_max_over_mean", metricType: Gauge},
 		ShardDistributorAssignmentLoadCV:                  {metricName: "shard_distributor_assignment_load_cv", metricType: Gauge},
@@ -4229,7 +4291,23 @@ var MetricDefs = map[ServiceIdx]map[MetricIdx]metricDefinition{
 			metricName: "shard_distributor_assignment_smoothed_load_missing_ratio",
 			metricType: Gauge,
 		},
-		ShardDistributorIsLeader: {metricName: "shard_distributor_is_leader", metricType: Gauge},
+		ShardDistributorAssignmentShardCountCV:     {metricName: "shard_distributor_assignment_shard_count_cv", metricType: Gauge},
+		ShardDistributorAssignmentHealthScore:      {metricName: "shard_distributor_assignment_health_score", metricType: Gauge},
+		ShardDistributorAssignmentMovesPerCycle:    {metricName: "shard_distributor_assignment_moves_per_cycle", metricType: Gauge},
+		ShardDistributorAssignmentStableShardRatio: {metricName: "shard_distributor_assignment_stable_shard_ratio", metricType: Gauge},
+		ShardDistributorIsLeader:                   {metricName: "shard_distributor_is_leader", metricType: Gauge},
+
+		ShardDistributorHeartbeatReceived:            {metricName: "shard_distributor_heartbeat_received", metricType: Counter},
+		ShardDistributorHeartbeatWriteSkipped:        {metricName: "shard_distributor_heartbeat_write_skipped", metricType: Counter},
+		ShardDistributorVersionConflicts:             {metricName: "shard_distributor_version_conflicts", metricType: Counter},
+		ShardDistributorHeartbeatUnknownShardReports: {metricName: "shard_distributor_heartbeat_unknown_shard_reports", metricType: Counter},
+		ShardDistributorHeartbeatReleasedShards:      {metricName: "shard_distributor_heartbeat_released_shards", metricType: Counter},
+		ShardDistributorHeartbeatClampedShardLoads:   {metricName: "shard_distributor_heartbeat_clamped_shard_loads", metricType: Counter},
+		ShardDistributorShardStatisticsUpdateLatency: {metricName: "shard_distributor_shard_statistics_update_latency", metricType: Histogram, buckets: Default1ms100s.buckets()},
+		ShardDistributorHeartbeatDeduplicated:        {metricName: "shard_distributor_heartbeat_deduplicated", metricType: Counter},
+		ShardDistributorHeartbeatDuplicateSequence:   {metricName: "shard_distributor_heartbeat_duplicate_sequence", metricType: Counter},
+		ShardDistributorHeartbeatReadOnlyStore:       {metricName: "shard_distributor_heartbeat_read_only_store", metricType: Counter},
+		ShardDistributorAssignLoopDuplicateShards:    {metricName: "shard_distributor_shard_assign_duplicate_shards", metricType: Counter},
 	},
 }
 
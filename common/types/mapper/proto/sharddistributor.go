@@ -108,22 +108,38 @@ func FromShardDistributorExecutorHeartbeatRequest(t *types.ExecutorHeartbeatRequ
 				status = sharddistributorv1.ShardStatus_SHARD_STATUS_READY
 			case types.ShardStatusDONE:
 				status = sharddistributorv1.ShardStatus_SHARD_STATUS_DONE
+			case types.ShardStatusDRAINING:
+				status = sharddistributorv1.ShardStatus_SHARD_STATUS_DRAINING
+			case types.ShardStatusPREPARING:
+				status = sharddistributorv1.ShardStatus_SHARD_STATUS_PREPARING
 			default:
 				status = sharddistributorv1.ShardStatus_SHARD_STATUS_INVALID
 			}
 
+			reportTime := shardStatusReport.GetReportTime()
 			shardStatusReports[shardKey] = &sharddistributorv1.ShardStatusReport{
-				Status:    status,
-				ShardLoad: shardStatusReport.GetShardLoad(),
+				Status:     status,
+				ShardLoad:  shardStatusReport.GetShardLoad(),
+				ShardLoads: shardStatusReport.GetShardLoads(),
+				ReportTime: timeToTimestamp(&reportTime),
 			}
 		}
 	}
 	return &sharddistributorv1.HeartbeatRequest{
-		Namespace:          t.GetNamespace(),
-		ExecutorId:         t.GetExecutorID(),
-		Status:             status,
-		ShardStatusReports: shardStatusReports,
-		Metadata:           t.GetMetadata(),
+		Namespace:                 t.GetNamespace(),
+		ExecutorId:                t.GetExecutorID(),
+		Status:                    status,
+		ShardStatusReports:        shardStatusReports,
+		Metadata:                  t.GetMetadata(),
+		Headroom:                  t.GetHeadroom(),
+		IsDeltaReport:             t.GetIsDeltaReport(),
+		Role:                      toExecutorRole(t.GetRole()),
+		EncodedShardStatusReports: t.EncodedShardStatusReports,
+		Labels:                    t.GetLabels(),
+		SequenceNumber:            t.GetSequenceNumber(),
+		IncarnationId:             t.GetIncarnationID(),
+		ExecutorLoad:              fromDoubleValue(t.ExecutorLoad),
+		DeltaResponse:             t.GetDeltaResponse(),
 	}
 }
 
@@ -162,21 +178,36 @@ func ToShardDistributorExecutorHeartbeatRequest(t *sharddistributorv1.HeartbeatR
 				status = types.ShardStatusREADY
 			case sharddistributorv1.ShardStatus_SHARD_STATUS_DONE:
 				status = types.ShardStatusDONE
+			case sharddistributorv1.ShardStatus_SHARD_STATUS_DRAINING:
+				status = types.ShardStatusDRAINING
+			case sharddistributorv1.ShardStatus_SHARD_STATUS_PREPARING:
+				status = types.ShardStatusPREPARING
 			}
 
 			shardStatusReports[shardKey] = &types.ShardStatusReport{
-				Status:    status,
-				ShardLoad: shardStatusReport.GetShardLoad(),
+				Status:     status,
+				ShardLoad:  shardStatusReport.GetShardLoad(),
+				ShardLoads: shardStatusReport.GetShardLoads(),
+				ReportTime: timestampToTimeVal(shardStatusReport.GetReportTime()),
 			}
 		}
 	}
 
 	return &types.ExecutorHeartbeatRequest{
-		Namespace:          t.GetNamespace(),
-		ExecutorID:         t.GetExecutorId(),
-		Status:             status,
-		ShardStatusReports: shardStatusReports,
-		Metadata:           t.GetMetadata(),
+		Namespace:                 t.GetNamespace(),
+		ExecutorID:                t.GetExecutorId(),
+		Status:                    status,
+		ShardStatusReports:        shardStatusReports,
+		Metadata:                  t.GetMetadata(),
+		Headroom:                  t.GetHeadroom(),
+		IsDeltaReport:             t.GetIsDeltaReport(),
+		Role:                      getExecutorRoleFromProto(t.GetRole()),
+		EncodedShardStatusReports: t.GetEncodedShardStatusReports(),
+		Labels:                    t.GetLabels(),
+		SequenceNumber:            t.GetSequenceNumber(),
+		IncarnationID:             t.GetIncarnationId(),
+		ExecutorLoad:              toDoubleValue(t.GetExecutorLoad()),
+		DeltaResponse:             t.GetDeltaResponse(),
 	}
 }
 
@@ -198,9 +229,17 @@ func FromShardDistributorExecutorHeartbeatResponse(t *types.ExecutorHeartbeatRes
 				status = sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_INVALID
 			case types.AssignmentStatusREADY:
 				status = sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_READY
+			case types.AssignmentStatusPREPARING:
+				status = sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_PREPARING
+			case types.AssignmentStatusDRAINING:
+				status = sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_DRAINING
 			}
+			assignedAt := shardAssignment.GetAssignedAt()
+			leaseExpiresAt := shardAssignment.GetLeaseExpiresAt()
 			shardAssignments[shardKey] = &sharddistributorv1.ShardAssignment{
-				Status: status,
+				Status:         status,
+				AssignedAt:     timeToTimestamp(&assignedAt),
+				LeaseExpiresAt: timeToTimestamp(&leaseExpiresAt),
 			}
 		}
 	}
@@ -208,6 +247,9 @@ func FromShardDistributorExecutorHeartbeatResponse(t *types.ExecutorHeartbeatRes
 	return &sharddistributorv1.HeartbeatResponse{
 		ShardAssignments: shardAssignments,
 		MigrationMode:    migrationMode,
+		ReasonCode:       toHeartbeatReasonCode(t.GetReasonCode()),
+		ShardsToStart:    t.GetShardsToStart(),
+		ShardsToStop:     t.GetShardsToStop(),
 	}
 }
 
@@ -229,9 +271,15 @@ func ToShardDistributorExecutorHeartbeatResponse(t *sharddistributorv1.Heartbeat
 				status = types.AssignmentStatusINVALID
 			case sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_READY:
 				status = types.AssignmentStatusREADY
+			case sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_PREPARING:
+				status = types.AssignmentStatusPREPARING
+			case sharddistributorv1.AssignmentStatus_ASSIGNMENT_STATUS_DRAINING:
+				status = types.AssignmentStatusDRAINING
 			}
 			shardAssignments[shardKey] = &types.ShardAssignment{
-				Status: status,
+				Status:         status,
+				AssignedAt:     timestampToTimeVal(shardAssignment.GetAssignedAt()),
+				LeaseExpiresAt: timestampToTimeVal(shardAssignment.GetLeaseExpiresAt()),
 			}
 		}
 	}
@@ -240,6 +288,9 @@ func ToShardDistributorExecutorHeartbeatResponse(t *sharddistributorv1.Heartbeat
 	return &types.ExecutorHeartbeatResponse{
 		ShardAssignments: shardAssignments,
 		MigrationMode:    migrationMode,
+		ReasonCode:       getHeartbeatReasonCodeFromProto(t.GetReasonCode()),
+		ShardsToStart:    t.GetShardsToStart(),
+		ShardsToStop:     t.GetShardsToStop(),
 	}
 }
 
@@ -271,6 +322,77 @@ func toMigrationMode(modeSD types.MigrationMode) sharddistributorv1.MigrationMod
 	return mode
 }
 
+func getExecutorRoleFromProto(protoRole sharddistributorv1.ExecutorRole) types.ExecutorRole {
+	var role types.ExecutorRole
+	switch protoRole {
+	case sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_OBSERVER:
+		role = types.ExecutorRoleOBSERVER
+	case sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_STANDBY:
+		role = types.ExecutorRoleSTANDBY
+	default:
+		// Executors that do not report a role are workers.
+		role = types.ExecutorRoleWORKER
+	}
+	return role
+}
+
+func toExecutorRole(roleSD types.ExecutorRole) sharddistributorv1.ExecutorRole {
+	var role sharddistributorv1.ExecutorRole
+	switch roleSD {
+	case types.ExecutorRoleWORKER:
+		role = sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_WORKER
+	case types.ExecutorRoleOBSERVER:
+		role = sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_OBSERVER
+	case types.ExecutorRoleSTANDBY:
+		role = sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_STANDBY
+	default:
+		role = sharddistributorv1.ExecutorRole_EXECUTOR_ROLE_INVALID
+	}
+	return role
+}
+
+func getHeartbeatReasonCodeFromProto(protoReasonCode sharddistributorv1.HeartbeatReasonCode) types.HeartbeatReasonCode {
+	var reasonCode types.HeartbeatReasonCode
+	switch protoReasonCode {
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_ACCEPTED:
+		reasonCode = types.HeartbeatReasonCodeACCEPTED
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_THROTTLED:
+		reasonCode = types.HeartbeatReasonCodeTHROTTLED
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_STATUS_CHANGE_APPLIED:
+		reasonCode = types.HeartbeatReasonCodeSTATUSCHANGEAPPLIED
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_DUPLICATE:
+		reasonCode = types.HeartbeatReasonCodeDUPLICATE
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_PASSTHROUGH:
+		reasonCode = types.HeartbeatReasonCodePASSTHROUGH
+	case sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_READ_ONLY:
+		reasonCode = types.HeartbeatReasonCodeREADONLY
+	default:
+		reasonCode = types.HeartbeatReasonCodeINVALID
+	}
+	return reasonCode
+}
+
+func toHeartbeatReasonCode(reasonCodeSD types.HeartbeatReasonCode) sharddistributorv1.HeartbeatReasonCode {
+	var reasonCode sharddistributorv1.HeartbeatReasonCode
+	switch reasonCodeSD {
+	case types.HeartbeatReasonCodeACCEPTED:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_ACCEPTED
+	case types.HeartbeatReasonCodeTHROTTLED:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_THROTTLED
+	case types.HeartbeatReasonCodeSTATUSCHANGEAPPLIED:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_STATUS_CHANGE_APPLIED
+	case types.HeartbeatReasonCodeDUPLICATE:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_DUPLICATE
+	case types.HeartbeatReasonCodePASSTHROUGH:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_PASSTHROUGH
+	case types.HeartbeatReasonCodeREADONLY:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_READ_ONLY
+	default:
+		reasonCode = sharddistributorv1.HeartbeatReasonCode_HEARTBEAT_REASON_CODE_INVALID
+	}
+	return reasonCode
+}
+
 // FromShardDistributorWatchNamespaceStateRequest converts a types.WatchNamespaceStateRequest to a sharddistributor.WatchNamespaceStateRequest
 func FromShardDistributorWatchNamespaceStateRequest(t *types.WatchNamespaceStateRequest) *sharddistributorv1.WatchNamespaceStateRequest {
 	if t == nil {
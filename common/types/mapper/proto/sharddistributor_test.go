@@ -24,6 +24,7 @@ package proto
 
 import (
 	"testing"
+	"time"
 
 	fuzz "github.com/google/gofuzz"
 	"github.com/stretchr/testify/assert"
@@ -57,6 +58,35 @@ func TestToShardDistributorExecutorHeartbeatResponse(t *testing.T) {
 	}
 }
 
+func TestShardDistributorExecutorHeartbeatResponseLease(t *testing.T) {
+	assignedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	leaseExpiresAt := time.Date(2026, 3, 1, 10, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		assignment *types.ShardAssignment
+	}{
+		{
+			name:       "lease",
+			assignment: &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: assignedAt, LeaseExpiresAt: leaseExpiresAt},
+		},
+		{
+			name:       "no lease",
+			assignment: &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: assignedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &types.ExecutorHeartbeatResponse{
+				ShardAssignments: map[string]*types.ShardAssignment{"shard-1": tt.assignment},
+			}
+			protoResponse := FromShardDistributorExecutorHeartbeatResponse(item)
+			assert.Equal(t, tt.assignment.LeaseExpiresAt.IsZero(), protoResponse.GetShardAssignments()["shard-1"].GetLeaseExpiresAt() == nil)
+			assert.Equal(t, item, ToShardDistributorExecutorHeartbeatResponse(protoResponse))
+		})
+	}
+}
+
 func TestFromShardDistributorWatchNamespaceStateRequest(t *testing.T) {
 	for _, item := range []*types.WatchNamespaceStateRequest{nil, {}, &testdata.ShardDistributorWatchNamespaceStateRequest} {
 		assert.Equal(t, item, ToShardDistributorWatchNamespaceStateRequest(FromShardDistributorWatchNamespaceStateRequest(item)))
@@ -76,14 +106,25 @@ func ExecutorStatusFuzzer(e *types.ExecutorStatus, c fuzz.Continue) {
 	*e = types.ExecutorStatus(c.Intn(4)) // 0-3
 }
 
-// ShardStatusFuzzer generates valid ShardStatus enum values (0-2: INVALID, READY, DONE).
+// ShardStatusFuzzer generates valid ShardStatus enum values (0-4: INVALID, READY, DONE, DRAINING, PREPARING).
 func ShardStatusFuzzer(e *types.ShardStatus, c fuzz.Continue) {
-	*e = types.ShardStatus(c.Intn(3)) // 0-2
+	*e = types.ShardStatus(c.Intn(5)) // 0-4
 }
 
-// AssignmentStatusFuzzer generates valid AssignmentStatus enum values (0-1: INVALID, READY).
+// AssignmentStatusFuzzer generates valid AssignmentStatus enum values (0-3: INVALID, READY, PREPARING, DRAINING).
 func AssignmentStatusFuzzer(e *types.AssignmentStatus, c fuzz.Continue) {
-	*e = types.AssignmentStatus(c.Intn(2)) // 0-1
+	*e = types.AssignmentStatus(c.Intn(4)) // 0-3
+}
+
+// ExecutorRoleFuzzer generates valid ExecutorRole enum values (0-2: WORKER, OBSERVER, STANDBY).
+func ExecutorRoleFuzzer(e *types.ExecutorRole, c fuzz.Continue) {
+	*e = types.ExecutorRole(c.Intn(3)) // 0-2
+}
+
+// HeartbeatReasonCodeFuzzer generates valid HeartbeatReasonCode enum values
+// (0-6: INVALID, ACCEPTED, THROTTLED, STATUSCHANGEAPPLIED, DUPLICATE, PASSTHROUGH, READONLY).
+func HeartbeatReasonCodeFuzzer(e *types.HeartbeatReasonCode, c fuzz.Continue) {
+	*e = types.HeartbeatReasonCode(c.Intn(7)) // 0-6
 }
 
 // MigrationModeFuzzer generates valid MigrationMode enum values
@@ -159,12 +200,12 @@ func TestWatchNamespaceStateResponseFuzz(t *testing.T) {
 
 func TestExecutorHeartbeatRequestFuzz(t *testing.T) {
 	testutils.RunMapperFuzzTest(t, FromShardDistributorExecutorHeartbeatRequest, ToShardDistributorExecutorHeartbeatRequest,
-		testutils.WithCustomFuncs(ExecutorStatusFuzzer, ShardStatusFuzzer, ExecutorHeartbeatRequestFuzzer),
+		testutils.WithCustomFuncs(ExecutorStatusFuzzer, ShardStatusFuzzer, ExecutorRoleFuzzer, ExecutorHeartbeatRequestFuzzer),
 	)
 }
 
 func TestExecutorHeartbeatResponseFuzz(t *testing.T) {
 	testutils.RunMapperFuzzTest(t, FromShardDistributorExecutorHeartbeatResponse, ToShardDistributorExecutorHeartbeatResponse,
-		testutils.WithCustomFuncs(AssignmentStatusFuzzer, MigrationModeFuzzer, ExecutorHeartbeatResponseFuzzer),
+		testutils.WithCustomFuncs(AssignmentStatusFuzzer, MigrationModeFuzzer, HeartbeatReasonCodeFuzzer, ExecutorHeartbeatResponseFuzzer),
 	)
 }
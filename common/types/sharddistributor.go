@@ -24,9 +24,10 @@ package types
 
 import (
 	"fmt"
+	"time"
 )
 
-//go:generate enumer -type=ExecutorStatus,ShardStatus,AssignmentStatus,MigrationMode,HandoverType,LoadBalancingMode -json -output sharddistributor_statuses_enumer_generated.go
+//go:generate enumer -type=ExecutorStatus,ShardStatus,AssignmentStatus,MigrationMode,HandoverType,LoadBalancingMode,ExecutorRole,HeartbeatReasonCode -json -output sharddistributor_statuses_enumer_generated.go
 
 type GetShardOwnerRequest struct {
 	ShardKey  string
@@ -103,6 +104,36 @@ type ExecutorHeartbeatRequest struct {
 	Status             ExecutorStatus
 	ShardStatusReports map[string]*ShardStatusReport
 	Metadata           map[string]string
+	// Headroom is the spare capacity the executor reports, e.g. its free CPU.
+	// Zero means the executor does not report headroom.
+	Headroom float64 `json:",omitempty"`
+	// IsDeltaReport marks ShardStatusReports as holding only the shards whose report changed since the
+	// previous heartbeat. The other shards keep their previously recorded reports.
+	IsDeltaReport bool `json:",omitempty"`
+	// Role is the role of the executor, executors are workers unless they report otherwise.
+	Role ExecutorRole `json:",omitempty"`
+	// EncodedShardStatusReports holds the shard status reports in the compact binary encoding of the
+	// reportcodec package, for executors whose ShardStatusReports map would make the request too large.
+	// It is used instead of ShardStatusReports, a request may not set both.
+	EncodedShardStatusReports []byte `json:",omitempty"`
+	// Labels are key-value pairs describing the executor, e.g. its version, region or instance type.
+	// They are persisted with the executor so assignment policies can select executors by label.
+	Labels map[string]string `json:",omitempty"`
+	// SequenceNumber is an optional idempotency key that increases with every heartbeat the executor sends.
+	// A heartbeat whose sequence number is not newer than the last processed one is not processed again,
+	// unless it comes from a new IncarnationID or the last processed one is older than the heartbeat TTL,
+	// so an executor that restarts may reset its counter. Zero means the executor does not send sequence numbers.
+	SequenceNumber int64 `json:",omitempty"`
+	// IncarnationID identifies the run of the executor process, e.g. a UUID generated on startup. It changes
+	// when the executor restarts and starts its SequenceNumber over.
+	IncarnationID string `json:",omitempty"`
+	// ExecutorLoad is the total load of the executor, for executors that cannot attribute their load to shards.
+	// When set, it is used as the load of the executor instead of the sum of its shard loads. Nil means the
+	// executor does not report it.
+	ExecutorLoad *float64 `json:",omitempty"`
+	// DeltaResponse asks for a response that holds the shards to start and to stop compared to the shards
+	// this heartbeat reports, instead of the full shard assignment.
+	DeltaResponse bool `json:",omitempty"`
 }
 
 func (v *ExecutorHeartbeatRequest) GetNamespace() (o string) {
@@ -140,6 +171,62 @@ func (v *ExecutorHeartbeatRequest) GetMetadata() (o map[string]string) {
 	return
 }
 
+func (v *ExecutorHeartbeatRequest) GetHeadroom() (o float64) {
+	if v != nil {
+		return v.Headroom
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetIsDeltaReport() (o bool) {
+	if v != nil {
+		return v.IsDeltaReport
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetRole() (o ExecutorRole) {
+	if v != nil {
+		return v.Role
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetLabels() (o map[string]string) {
+	if v != nil {
+		return v.Labels
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetSequenceNumber() (o int64) {
+	if v != nil {
+		return v.SequenceNumber
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetIncarnationID() (o string) {
+	if v != nil {
+		return v.IncarnationID
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetExecutorLoad() (o float64) {
+	if v != nil && v.ExecutorLoad != nil {
+		return *v.ExecutorLoad
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatRequest) GetDeltaResponse() (o bool) {
+	if v != nil {
+		return v.DeltaResponse
+	}
+	return
+}
+
 // ExecutorStatus is persisted to the DB with a string value mapping.
 // Beware - if we want to change the name - it should be backward compatible and should be done in two steps.
 type ExecutorStatus int32
@@ -154,6 +241,12 @@ const (
 type ShardStatusReport struct {
 	Status    ShardStatus
 	ShardLoad float64
+	// ShardLoads is the load of the shard per named dimension (e.g. cpu, memory).
+	// When empty, ShardLoad is the load of the default dimension.
+	ShardLoads map[string]float64 `json:",omitempty"`
+	// ReportTime is when the executor took the report. When set, a report older than the last
+	// processed report of the shard is stale and does not update its statistics.
+	ReportTime time.Time `json:",omitzero"`
 }
 
 func (v *ShardStatusReport) GetStatus() (o ShardStatus) {
@@ -170,6 +263,20 @@ func (v *ShardStatusReport) GetShardLoad() (o float64) {
 	return
 }
 
+func (v *ShardStatusReport) GetShardLoads() (o map[string]float64) {
+	if v != nil && v.ShardLoads != nil {
+		return v.ShardLoads
+	}
+	return
+}
+
+func (v *ShardStatusReport) GetReportTime() (o time.Time) {
+	if v != nil {
+		return v.ReportTime
+	}
+	return
+}
+
 // ShardStatus is persisted to the DB with a string value mapping.
 // Beware - if we want to change the name - it should be backward compatible and should be done in two steps.
 type ShardStatus int32
@@ -178,11 +285,25 @@ const (
 	ShardStatusINVALID ShardStatus = 0
 	ShardStatusREADY   ShardStatus = 1
 	ShardStatusDONE    ShardStatus = 2
+	// ShardStatusDRAINING is reported by executors for shards they are handing off. It is not part of
+	// the IDL yet, so it is only produced by executors using the Go types directly.
+	ShardStatusDRAINING ShardStatus = 3
+	// ShardStatusPREPARING is reported by executors for shards they are taking over in a controlled handoff,
+	// while they load the shard before serving it. It is not part of the IDL yet either.
+	ShardStatusPREPARING ShardStatus = 4
 )
 
 type ExecutorHeartbeatResponse struct {
 	ShardAssignments map[string]*ShardAssignment
 	MigrationMode    MigrationMode
+	// ReasonCode tells the executor whether and how its heartbeat was persisted.
+	// It is HeartbeatReasonCodeINVALID when the server does not set it.
+	ReasonCode HeartbeatReasonCode `json:",omitempty"`
+	// ShardsToStart and ShardsToStop are set instead of ShardAssignments when the request asked for a delta
+	// response. They hold the assigned shards the executor did not report, and the reported shards that are
+	// not assigned to it.
+	ShardsToStart []string `json:",omitempty"`
+	ShardsToStop  []string `json:",omitempty"`
 }
 
 func (v *ExecutorHeartbeatResponse) GetShardAssignments() (o map[string]*ShardAssignment) {
@@ -199,9 +320,36 @@ func (v *ExecutorHeartbeatResponse) GetMigrationMode() (o MigrationMode) {
 	return
 }
 
+func (v *ExecutorHeartbeatResponse) GetReasonCode() (o HeartbeatReasonCode) {
+	if v != nil {
+		return v.ReasonCode
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatResponse) GetShardsToStart() (o []string) {
+	if v != nil {
+		return v.ShardsToStart
+	}
+	return
+}
+
+func (v *ExecutorHeartbeatResponse) GetShardsToStop() (o []string) {
+	if v != nil {
+		return v.ShardsToStop
+	}
+	return
+}
+
 type ShardAssignment struct {
 	// Status indicates the current assignment status of the shard.
 	Status AssignmentStatus `json:"status"`
+	// AssignedAt is the time the shard was assigned to its current executor.
+	// It is preserved across rebalances as long as the shard stays on the same executor.
+	AssignedAt time.Time `json:"assigned_at,omitzero"`
+	// LeaseExpiresAt is the time after which the executor must stop processing the shard
+	// unless the lease was renewed by a later heartbeat. A zero value means no lease.
+	LeaseExpiresAt time.Time `json:"lease_expires_at,omitzero"`
 }
 
 func (v *ShardAssignment) GetStatus() (o AssignmentStatus) {
@@ -211,6 +359,20 @@ func (v *ShardAssignment) GetStatus() (o AssignmentStatus) {
 	return
 }
 
+func (v *ShardAssignment) GetAssignedAt() (o time.Time) {
+	if v != nil {
+		return v.AssignedAt
+	}
+	return
+}
+
+func (v *ShardAssignment) GetLeaseExpiresAt() (o time.Time) {
+	if v != nil {
+		return v.LeaseExpiresAt
+	}
+	return
+}
+
 // AssignmentStatus is persisted to the DB with a string value mapping.
 // Beware - if we want to change the name - it should be backward compatible and should be done in two steps.
 type AssignmentStatus int32
@@ -218,6 +380,12 @@ type AssignmentStatus int32
 const (
 	AssignmentStatusINVALID AssignmentStatus = 0
 	AssignmentStatusREADY   AssignmentStatus = 1
+	// AssignmentStatusPREPARING and AssignmentStatusDRAINING are only set in heartbeat responses during a
+	// controlled handoff. PREPARING tells the new owner to load the shard without serving it yet, and the
+	// previous owner to keep serving it until the new owner is ready. DRAINING tells the previous owner to
+	// wind the shard down and report it DONE. They are not part of the IDL yet.
+	AssignmentStatusPREPARING AssignmentStatus = 2
+	AssignmentStatusDRAINING  AssignmentStatus = 3
 )
 
 // HandoverType is used to indicate the type of handover that occurred during shard reassignment.
@@ -255,9 +423,42 @@ const (
 type LoadBalancingMode int32
 
 const (
-	LoadBalancingModeINVALID LoadBalancingMode = 0
-	LoadBalancingModeNAIVE   LoadBalancingMode = 1
-	LoadBalancingModeGREEDY  LoadBalancingMode = 2
+	LoadBalancingModeINVALID        LoadBalancingMode = 0
+	LoadBalancingModeNAIVE          LoadBalancingMode = 1
+	LoadBalancingModeGREEDY         LoadBalancingMode = 2
+	LoadBalancingModeCONSISTENTHASH LoadBalancingMode = 3
+)
+
+// ExecutorRole is the role an executor heartbeats with.
+type ExecutorRole int32
+
+const (
+	// ExecutorRoleWORKER executors are assigned shards.
+	ExecutorRoleWORKER ExecutorRole = 0
+	// ExecutorRoleOBSERVER executors only observe and report, e.g. canary inspectors, and are never assigned shards.
+	ExecutorRoleOBSERVER ExecutorRole = 1
+	// ExecutorRoleSTANDBY executors are warm standbys. They are kept empty during normal operation and only
+	// receive shards when shards are reassigned from an executor that is gone.
+	ExecutorRoleSTANDBY ExecutorRole = 2
+)
+
+// HeartbeatReasonCode is why a heartbeat was or was not persisted.
+type HeartbeatReasonCode int32
+
+const (
+	HeartbeatReasonCodeINVALID HeartbeatReasonCode = 0
+	// HeartbeatReasonCodeACCEPTED heartbeats were persisted.
+	HeartbeatReasonCodeACCEPTED HeartbeatReasonCode = 1
+	// HeartbeatReasonCodeTHROTTLED heartbeats were not persisted.
+	HeartbeatReasonCodeTHROTTLED HeartbeatReasonCode = 2
+	// HeartbeatReasonCodeSTATUSCHANGEAPPLIED heartbeats were persisted and changed the executor status.
+	HeartbeatReasonCodeSTATUSCHANGEAPPLIED HeartbeatReasonCode = 3
+	// HeartbeatReasonCodeDUPLICATE heartbeats carried a sequence number that was already processed and were not persisted.
+	HeartbeatReasonCodeDUPLICATE HeartbeatReasonCode = 4
+	// HeartbeatReasonCodePASSTHROUGH heartbeats were not persisted because the namespace is in local passthrough mode.
+	HeartbeatReasonCodePASSTHROUGH HeartbeatReasonCode = 5
+	// HeartbeatReasonCodeREADONLY heartbeats were not persisted because the store only serves reads.
+	HeartbeatReasonCodeREADONLY HeartbeatReasonCode = 6
 )
 
 type WatchNamespaceStateRequest struct {
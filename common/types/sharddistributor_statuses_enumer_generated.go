@@ -1,4 +1,4 @@
-// Code generated by "enumer -type=ExecutorStatus,ShardStatus,AssignmentStatus,MigrationMode,HandoverType,LoadBalancingMode -json -output sharddistributor_statuses_enumer_generated.go"; DO NOT EDIT.
+// Code generated by "enumer -type=ExecutorStatus,ShardStatus,AssignmentStatus,MigrationMode,HandoverType,LoadBalancingMode,ExecutorRole,HeartbeatReasonCode -json -output sharddistributor_statuses_enumer_generated.go"; DO NOT EDIT.
 
 package types
 
@@ -103,11 +103,11 @@ func (i *ExecutorStatus) UnmarshalJSON(data []byte) error {
 	return err
 }
 
-const _ShardStatusName = "ShardStatusINVALIDShardStatusREADYShardStatusDONE"
+const _ShardStatusName = "ShardStatusINVALIDShardStatusREADYShardStatusDONEShardStatusDRAININGShardStatusPREPARING"
 
-var _ShardStatusIndex = [...]uint8{0, 18, 34, 49}
+var _ShardStatusIndex = [...]uint8{0, 18, 34, 49, 68, 88}
 
-const _ShardStatusLowerName = "shardstatusinvalidshardstatusreadyshardstatusdone"
+const _ShardStatusLowerName = "shardstatusinvalidshardstatusreadyshardstatusdoneshardstatusdrainingshardstatuspreparing"
 
 func (i ShardStatus) String() string {
 	if i < 0 || i >= ShardStatus(len(_ShardStatusIndex)-1) {
@@ -123,9 +123,11 @@ func _ShardStatusNoOp() {
 	_ = x[ShardStatusINVALID-(0)]
 	_ = x[ShardStatusREADY-(1)]
 	_ = x[ShardStatusDONE-(2)]
+	_ = x[ShardStatusDRAINING-(3)]
+	_ = x[ShardStatusPREPARING-(4)]
 }
 
-var _ShardStatusValues = []ShardStatus{ShardStatusINVALID, ShardStatusREADY, ShardStatusDONE}
+var _ShardStatusValues = []ShardStatus{ShardStatusINVALID, ShardStatusREADY, ShardStatusDONE, ShardStatusDRAINING, ShardStatusPREPARING}
 
 var _ShardStatusNameToValueMap = map[string]ShardStatus{
 	_ShardStatusName[0:18]:       ShardStatusINVALID,
@@ -134,12 +136,18 @@ var _ShardStatusNameToValueMap = map[string]ShardStatus{
 	_ShardStatusLowerName[18:34]: ShardStatusREADY,
 	_ShardStatusName[34:49]:      ShardStatusDONE,
 	_ShardStatusLowerName[34:49]: ShardStatusDONE,
+	_ShardStatusName[49:68]:      ShardStatusDRAINING,
+	_ShardStatusLowerName[49:68]: ShardStatusDRAINING,
+	_ShardStatusName[68:88]:      ShardStatusPREPARING,
+	_ShardStatusLowerName[68:88]: ShardStatusPREPARING,
 }
 
 var _ShardStatusNames = []string{
 	_ShardStatusName[0:18],
 	_ShardStatusName[18:34],
 	_ShardStatusName[34:49],
+	_ShardStatusName[49:68],
+	_ShardStatusName[68:88],
 }
 
 // ShardStatusString retrieves an enum value from the enum constants string name.
@@ -194,11 +202,11 @@ func (i *ShardStatus) UnmarshalJSON(data []byte) error {
 	return err
 }
 
-const _AssignmentStatusName = "AssignmentStatusINVALIDAssignmentStatusREADY"
+const _AssignmentStatusName = "AssignmentStatusINVALIDAssignmentStatusREADYAssignmentStatusPREPARINGAssignmentStatusDRAINING"
 
-var _AssignmentStatusIndex = [...]uint8{0, 23, 44}
+var _AssignmentStatusIndex = [...]uint8{0, 23, 44, 69, 93}
 
-const _AssignmentStatusLowerName = "assignmentstatusinvalidassignmentstatusready"
+const _AssignmentStatusLowerName = "assignmentstatusinvalidassignmentstatusreadyassignmentstatuspreparingassignmentstatusdraining"
 
 func (i AssignmentStatus) String() string {
 	if i < 0 || i >= AssignmentStatus(len(_AssignmentStatusIndex)-1) {
@@ -213,20 +221,28 @@ func _AssignmentStatusNoOp() {
 	var x [1]struct{}
 	_ = x[AssignmentStatusINVALID-(0)]
 	_ = x[AssignmentStatusREADY-(1)]
+	_ = x[AssignmentStatusPREPARING-(2)]
+	_ = x[AssignmentStatusDRAINING-(3)]
 }
 
-var _AssignmentStatusValues = []AssignmentStatus{AssignmentStatusINVALID, AssignmentStatusREADY}
+var _AssignmentStatusValues = []AssignmentStatus{AssignmentStatusINVALID, AssignmentStatusREADY, AssignmentStatusPREPARING, AssignmentStatusDRAINING}
 
 var _AssignmentStatusNameToValueMap = map[string]AssignmentStatus{
 	_AssignmentStatusName[0:23]:       AssignmentStatusINVALID,
 	_AssignmentStatusLowerName[0:23]:  AssignmentStatusINVALID,
 	_AssignmentStatusName[23:44]:      AssignmentStatusREADY,
 	_AssignmentStatusLowerName[23:44]: AssignmentStatusREADY,
+	_AssignmentStatusName[44:69]:      AssignmentStatusPREPARING,
+	_AssignmentStatusLowerName[44:69]: AssignmentStatusPREPARING,
+	_AssignmentStatusName[69:93]:      AssignmentStatusDRAINING,
+	_AssignmentStatusLowerName[69:93]: AssignmentStatusDRAINING,
 }
 
 var _AssignmentStatusNames = []string{
 	_AssignmentStatusName[0:23],
 	_AssignmentStatusName[23:44],
+	_AssignmentStatusName[44:69],
+	_AssignmentStatusName[69:93],
 }
 
 // AssignmentStatusString retrieves an enum value from the enum constants string name.
@@ -463,11 +479,11 @@ func (i *HandoverType) UnmarshalJSON(data []byte) error {
 	return err
 }
 
-const _LoadBalancingModeName = "LoadBalancingModeINVALIDLoadBalancingModeNAIVELoadBalancingModeGREEDY"
+const _LoadBalancingModeName = "LoadBalancingModeINVALIDLoadBalancingModeNAIVELoadBalancingModeGREEDYLoadBalancingModeCONSISTENTHASH"
 
-var _LoadBalancingModeIndex = [...]uint8{0, 24, 46, 69}
+var _LoadBalancingModeIndex = [...]uint8{0, 24, 46, 69, 100}
 
-const _LoadBalancingModeLowerName = "loadbalancingmodeinvalidloadbalancingmodenaiveloadbalancingmodegreedy"
+const _LoadBalancingModeLowerName = "loadbalancingmodeinvalidloadbalancingmodenaiveloadbalancingmodegreedyloadbalancingmodeconsistenthash"
 
 func (i LoadBalancingMode) String() string {
 	if i < 0 || i >= LoadBalancingMode(len(_LoadBalancingModeIndex)-1) {
@@ -483,23 +499,27 @@ func _LoadBalancingModeNoOp() {
 	_ = x[LoadBalancingModeINVALID-(0)]
 	_ = x[LoadBalancingModeNAIVE-(1)]
 	_ = x[LoadBalancingModeGREEDY-(2)]
+	_ = x[LoadBalancingModeCONSISTENTHASH-(3)]
 }
 
-var _LoadBalancingModeValues = []LoadBalancingMode{LoadBalancingModeINVALID, LoadBalancingModeNAIVE, LoadBalancingModeGREEDY}
+var _LoadBalancingModeValues = []LoadBalancingMode{LoadBalancingModeINVALID, LoadBalancingModeNAIVE, LoadBalancingModeGREEDY, LoadBalancingModeCONSISTENTHASH}
 
 var _LoadBalancingModeNameToValueMap = map[string]LoadBalancingMode{
-	_LoadBalancingModeName[0:24]:       LoadBalancingModeINVALID,
-	_LoadBalancingModeLowerName[0:24]:  LoadBalancingModeINVALID,
-	_LoadBalancingModeName[24:46]:      LoadBalancingModeNAIVE,
-	_LoadBalancingModeLowerName[24:46]: LoadBalancingModeNAIVE,
-	_LoadBalancingModeName[46:69]:      LoadBalancingModeGREEDY,
-	_LoadBalancingModeLowerName[46:69]: LoadBalancingModeGREEDY,
+	_LoadBalancingModeName[0:24]:        LoadBalancingModeINVALID,
+	_LoadBalancingModeLowerName[0:24]:   LoadBalancingModeINVALID,
+	_LoadBalancingModeName[24:46]:       LoadBalancingModeNAIVE,
+	_LoadBalancingModeLowerName[24:46]:  LoadBalancingModeNAIVE,
+	_LoadBalancingModeName[46:69]:       LoadBalancingModeGREEDY,
+	_LoadBalancingModeLowerName[46:69]:  LoadBalancingModeGREEDY,
+	_LoadBalancingModeName[69:100]:      LoadBalancingModeCONSISTENTHASH,
+	_LoadBalancingModeLowerName[69:100]: LoadBalancingModeCONSISTENTHASH,
 }
 
 var _LoadBalancingModeNames = []string{
 	_LoadBalancingModeName[0:24],
 	_LoadBalancingModeName[24:46],
 	_LoadBalancingModeName[46:69],
+	_LoadBalancingModeName[69:100],
 }
 
 // LoadBalancingModeString retrieves an enum value from the enum constants string name.
@@ -553,3 +573,201 @@ func (i *LoadBalancingMode) UnmarshalJSON(data []byte) error {
 	*i, err = LoadBalancingModeString(s)
 	return err
 }
+
+const _ExecutorRoleName = "ExecutorRoleWORKERExecutorRoleOBSERVERExecutorRoleSTANDBY"
+
+var _ExecutorRoleIndex = [...]uint8{0, 18, 38, 57}
+
+const _ExecutorRoleLowerName = "executorroleworkerexecutorroleobserverexecutorrolestandby"
+
+func (i ExecutorRole) String() string {
+	if i < 0 || i >= ExecutorRole(len(_ExecutorRoleIndex)-1) {
+		return fmt.Sprintf("ExecutorRole(%d)", i)
+	}
+	return _ExecutorRoleName[_ExecutorRoleIndex[i]:_ExecutorRoleIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _ExecutorRoleNoOp() {
+	var x [1]struct{}
+	_ = x[ExecutorRoleWORKER-(0)]
+	_ = x[ExecutorRoleOBSERVER-(1)]
+	_ = x[ExecutorRoleSTANDBY-(2)]
+}
+
+var _ExecutorRoleValues = []ExecutorRole{ExecutorRoleWORKER, ExecutorRoleOBSERVER, ExecutorRoleSTANDBY}
+
+var _ExecutorRoleNameToValueMap = map[string]ExecutorRole{
+	_ExecutorRoleName[0:18]:       ExecutorRoleWORKER,
+	_ExecutorRoleLowerName[0:18]:  ExecutorRoleWORKER,
+	_ExecutorRoleName[18:38]:      ExecutorRoleOBSERVER,
+	_ExecutorRoleLowerName[18:38]: ExecutorRoleOBSERVER,
+	_ExecutorRoleName[38:57]:      ExecutorRoleSTANDBY,
+	_ExecutorRoleLowerName[38:57]: ExecutorRoleSTANDBY,
+}
+
+var _ExecutorRoleNames = []string{
+	_ExecutorRoleName[0:18],
+	_ExecutorRoleName[18:38],
+	_ExecutorRoleName[38:57],
+}
+
+// ExecutorRoleString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func ExecutorRoleString(s string) (ExecutorRole, error) {
+	if val, ok := _ExecutorRoleNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _ExecutorRoleNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to ExecutorRole values", s)
+}
+
+// ExecutorRoleValues returns all values of the enum
+func ExecutorRoleValues() []ExecutorRole {
+	return _ExecutorRoleValues
+}
+
+// ExecutorRoleStrings returns a slice of all String values of the enum
+func ExecutorRoleStrings() []string {
+	strs := make([]string, len(_ExecutorRoleNames))
+	copy(strs, _ExecutorRoleNames)
+	return strs
+}
+
+// IsAExecutorRole returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i ExecutorRole) IsAExecutorRole() bool {
+	for _, v := range _ExecutorRoleValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for ExecutorRole
+func (i ExecutorRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ExecutorRole
+func (i *ExecutorRole) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("ExecutorRole should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = ExecutorRoleString(s)
+	return err
+}
+
+const _HeartbeatReasonCodeName = "HeartbeatReasonCodeINVALIDHeartbeatReasonCodeACCEPTEDHeartbeatReasonCodeTHROTTLEDHeartbeatReasonCodeSTATUSCHANGEAPPLIEDHeartbeatReasonCodeDUPLICATEHeartbeatReasonCodePASSTHROUGHHeartbeatReasonCodeREADONLY"
+
+var _HeartbeatReasonCodeIndex = [...]uint8{0, 26, 53, 81, 119, 147, 177, 204}
+
+const _HeartbeatReasonCodeLowerName = "heartbeatreasoncodeinvalidheartbeatreasoncodeacceptedheartbeatreasoncodethrottledheartbeatreasoncodestatuschangeappliedheartbeatreasoncodeduplicateheartbeatreasoncodepassthroughheartbeatreasoncodereadonly"
+
+func (i HeartbeatReasonCode) String() string {
+	if i < 0 || i >= HeartbeatReasonCode(len(_HeartbeatReasonCodeIndex)-1) {
+		return fmt.Sprintf("HeartbeatReasonCode(%d)", i)
+	}
+	return _HeartbeatReasonCodeName[_HeartbeatReasonCodeIndex[i]:_HeartbeatReasonCodeIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _HeartbeatReasonCodeNoOp() {
+	var x [1]struct{}
+	_ = x[HeartbeatReasonCodeINVALID-(0)]
+	_ = x[HeartbeatReasonCodeACCEPTED-(1)]
+	_ = x[HeartbeatReasonCodeTHROTTLED-(2)]
+	_ = x[HeartbeatReasonCodeSTATUSCHANGEAPPLIED-(3)]
+	_ = x[HeartbeatReasonCodeDUPLICATE-(4)]
+	_ = x[HeartbeatReasonCodePASSTHROUGH-(5)]
+	_ = x[HeartbeatReasonCodeREADONLY-(6)]
+}
+
+var _HeartbeatReasonCodeValues = []HeartbeatReasonCode{HeartbeatReasonCodeINVALID, HeartbeatReasonCodeACCEPTED, HeartbeatReasonCodeTHROTTLED, HeartbeatReasonCodeSTATUSCHANGEAPPLIED, HeartbeatReasonCodeDUPLICATE, HeartbeatReasonCodePASSTHROUGH, HeartbeatReasonCodeREADONLY}
+
+var _HeartbeatReasonCodeNameToValueMap = map[string]HeartbeatReasonCode{
+	_HeartbeatReasonCodeName[0:26]:         HeartbeatReasonCodeINVALID,
+	_HeartbeatReasonCodeLowerName[0:26]:    HeartbeatReasonCodeINVALID,
+	_HeartbeatReasonCodeName[26:53]:        HeartbeatReasonCodeACCEPTED,
+	_HeartbeatReasonCodeLowerName[26:53]:   HeartbeatReasonCodeACCEPTED,
+	_HeartbeatReasonCodeName[53:81]:        HeartbeatReasonCodeTHROTTLED,
+	_HeartbeatReasonCodeLowerName[53:81]:   HeartbeatReasonCodeTHROTTLED,
+	_HeartbeatReasonCodeName[81:119]:       HeartbeatReasonCodeSTATUSCHANGEAPPLIED,
+	_HeartbeatReasonCodeLowerName[81:119]:  HeartbeatReasonCodeSTATUSCHANGEAPPLIED,
+	_HeartbeatReasonCodeName[119:147]:      HeartbeatReasonCodeDUPLICATE,
+	_HeartbeatReasonCodeLowerName[119:147]: HeartbeatReasonCodeDUPLICATE,
+	_HeartbeatReasonCodeName[147:177]:      HeartbeatReasonCodePASSTHROUGH,
+	_HeartbeatReasonCodeLowerName[147:177]: HeartbeatReasonCodePASSTHROUGH,
+	_HeartbeatReasonCodeName[177:204]:      HeartbeatReasonCodeREADONLY,
+	_HeartbeatReasonCodeLowerName[177:204]: HeartbeatReasonCodeREADONLY,
+}
+
+var _HeartbeatReasonCodeNames = []string{
+	_HeartbeatReasonCodeName[0:26],
+	_HeartbeatReasonCodeName[26:53],
+	_HeartbeatReasonCodeName[53:81],
+	_HeartbeatReasonCodeName[81:119],
+	_HeartbeatReasonCodeName[119:147],
+	_HeartbeatReasonCodeName[147:177],
+	_HeartbeatReasonCodeName[177:204],
+}
+
+// HeartbeatReasonCodeString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func HeartbeatReasonCodeString(s string) (HeartbeatReasonCode, error) {
+	if val, ok := _HeartbeatReasonCodeNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _HeartbeatReasonCodeNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to HeartbeatReasonCode values", s)
+}
+
+// HeartbeatReasonCodeValues returns all values of the enum
+func HeartbeatReasonCodeValues() []HeartbeatReasonCode {
+	return _HeartbeatReasonCodeValues
+}
+
+// HeartbeatReasonCodeStrings returns a slice of all String values of the enum
+func HeartbeatReasonCodeStrings() []string {
+	strs := make([]string, len(_HeartbeatReasonCodeNames))
+	copy(strs, _HeartbeatReasonCodeNames)
+	return strs
+}
+
+// IsAHeartbeatReasonCode returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i HeartbeatReasonCode) IsAHeartbeatReasonCode() bool {
+	for _, v := range _HeartbeatReasonCodeValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for HeartbeatReasonCode
+func (i HeartbeatReasonCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for HeartbeatReasonCode
+func (i *HeartbeatReasonCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("HeartbeatReasonCode should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = HeartbeatReasonCodeString(s)
+	return err
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config is the configuration for the shard distributor canary
 type Config struct {
 	Canary CanaryConfig `yaml:"canary"`
@@ -15,4 +17,37 @@ type CanaryConfig struct {
 	// Values more than 1 will create multiple executors processing the same ephemeral namespace
 	// Default: 1
 	NumEphemeralExecutors int `yaml:"numEphemeralExecutors"`
+
+	// EphemeralLoadModel describes the load and lifetime of the shards of the ephemeral namespace
+	// Default: every shard has a load of 1 and the same completion chance
+	EphemeralLoadModel EphemeralLoadModel `yaml:"ephemeralLoadModel"`
+
+	// ShardWarmUp is how long a shard of either namespace takes to ramp its reported load
+	// from a fraction of its full load up to the full load after it starts.
+	// Default: 0, shards report their full load right away
+	ShardWarmUp time.Duration `yaml:"shardWarmUp"`
+
+	// ExecutorFailureRate is the probability that an executor fails at every
+	// ExecutorFailureInterval, so the shard distributor's failover is exercised.
+	// Default: 0, no failures are injected
+	ExecutorFailureRate float64 `yaml:"executorFailureRate"`
+
+	// ExecutorFailureInterval is how often the failure rate is applied to each running executor.
+	// Default: 1m
+	ExecutorFailureInterval time.Duration `yaml:"executorFailureInterval"`
+
+	// ExecutorFailureMode is how an executor fails: "stop-heartbeat" stops the executor,
+	// "exit" terminates the canary process without any cleanup.
+	// Default: "stop-heartbeat"
+	ExecutorFailureMode string `yaml:"executorFailureMode"`
+}
+
+type EphemeralLoadModel struct {
+	// MaxWeight is the heaviest weight an ephemeral shard can get, shards report their weight as their load
+	// Default: 1
+	MaxWeight int `yaml:"maxWeight"`
+
+	// ScaleLifetimeByWeight makes heavier shards live longer, a shard of weight w completes w times less often
+	// Default: false
+	ScaleLifetimeByWeight bool `yaml:"scaleLifetimeByWeight"`
 }
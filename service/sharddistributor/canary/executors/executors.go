@@ -1,7 +1,13 @@
 package executors
 
 import (
+	"sync"
+
+	"github.com/uber-go/tally"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/uber/cadence/common/clock"
 
 	"github.com/uber/cadence/service/sharddistributor/canary/config"
 	"github.com/uber/cadence/service/sharddistributor/canary/processor"
@@ -80,14 +86,37 @@ type ExecutorsParams struct {
 	Lc                 fx.Lifecycle
 	ExecutorsFixed     []executorclient.Executor[*processor.ShardProcessor]          `group:"executor-fixed-proc"`
 	Executorsephemeral []executorclient.Executor[*processorephemeral.ShardProcessor] `group:"executor-ephemeral-proc"`
+
+	// The failure injection dependencies are only used when ExecutorFailureRate is set.
+	Config       config.Config    `optional:"true"`
+	Logger       *zap.Logger      `optional:"true"`
+	TimeSource   clock.TimeSource `optional:"true"`
+	MetricsScope tally.Scope      `optional:"true"`
 }
 
 func NewExecutorsModule(params ExecutorsParams) {
+	injectFailures := params.Config.Canary.ExecutorFailureRate > 0
+
+	var injectable []injectableExecutor
 	for _, e := range params.ExecutorsFixed {
-		params.Lc.Append(fx.StartStopHook(e.Start, e.Stop))
+		// Stop closes the executor's stop channel, so it must only run once even when a failure was injected.
+		stop := sync.OnceFunc(e.Stop)
+		params.Lc.Append(fx.StartStopHook(e.Start, stop))
+		if injectFailures {
+			injectable = append(injectable, injectableExecutor{namespace: e.GetNamespace(), stop: stop})
+		}
 	}
 	for _, e := range params.Executorsephemeral {
-		params.Lc.Append(fx.StartStopHook(e.Start, e.Stop))
+		stop := sync.OnceFunc(e.Stop)
+		params.Lc.Append(fx.StartStopHook(e.Start, stop))
+		if injectFailures {
+			injectable = append(injectable, injectableExecutor{namespace: e.GetNamespace(), stop: stop})
+		}
+	}
+
+	if len(injectable) > 0 {
+		injector := newFailureInjector(params.Config.Canary, injectable, params.TimeSource, params.Logger, params.MetricsScope)
+		params.Lc.Append(fx.StartStopHook(injector.Start, injector.Stop))
 	}
 }
 
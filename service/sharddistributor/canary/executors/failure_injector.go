@@ -0,0 +1,135 @@
+package executors
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/service/sharddistributor/canary/config"
+	canarymetrics "github.com/uber/cadence/service/sharddistributor/canary/metrics"
+)
+
+const (
+	// FailureModeStopHeartbeat stops the executor, so it stops heartbeating and its shards are reassigned.
+	FailureModeStopHeartbeat = "stop-heartbeat"
+	// FailureModeExit terminates the canary process abruptly, without draining any executor.
+	FailureModeExit = "exit"
+
+	defaultFailureInterval = time.Minute
+)
+
+// injectableExecutor is an executor the failure injector can stop. stop must be safe to call more
+// than once, since the executor is stopped again when the canary shuts down.
+type injectableExecutor struct {
+	namespace string
+	stop      func()
+}
+
+// failureInjector makes executors fail at a configured rate, so the shard distributor's handling of
+// executors that stop heartbeating can be exercised end to end.
+type failureInjector struct {
+	logger       *zap.Logger
+	timeSource   clock.TimeSource
+	metricsScope tally.Scope
+	executors    []injectableExecutor
+	rate         float64
+	interval     time.Duration
+	mode         string
+	randFloat    func() float64
+	exit         func(code int)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFailureInjector(
+	cfg config.CanaryConfig,
+	executors []injectableExecutor,
+	timeSource clock.TimeSource,
+	logger *zap.Logger,
+	metricsScope tally.Scope,
+) *failureInjector {
+	interval := cfg.ExecutorFailureInterval
+	if interval <= 0 {
+		interval = defaultFailureInterval
+	}
+	mode := cfg.ExecutorFailureMode
+	if mode != FailureModeExit {
+		mode = FailureModeStopHeartbeat
+	}
+	return &failureInjector{
+		logger:       logger,
+		timeSource:   timeSource,
+		metricsScope: metricsScope,
+		executors:    executors,
+		rate:         cfg.ExecutorFailureRate,
+		interval:     interval,
+		mode:         mode,
+		randFloat:    rand.Float64,
+		exit:         os.Exit,
+	}
+}
+
+// Start begins the failure injection loop.
+func (f *failureInjector) Start(ctx context.Context) {
+	f.logger.Info("Starting canary executor failure injection",
+		zap.Float64("rate", f.rate), zap.Duration("interval", f.interval), zap.String("mode", f.mode))
+	f.ctx, f.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	f.wg.Add(1)
+	go f.injectLoop()
+}
+
+// Stop stops the failure injection loop.
+func (f *failureInjector) Stop() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.wg.Wait()
+}
+
+func (f *failureInjector) injectLoop() {
+	defer f.wg.Done()
+
+	ticker := f.timeSource.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.Chan():
+			f.injectFailures()
+		}
+	}
+}
+
+// injectFailures makes every running executor fail with probability rate. Failed executors are not
+// restarted, the canary has to be restarted to bring them back.
+func (f *failureInjector) injectFailures() {
+	var running []injectableExecutor
+	for _, executor := range f.executors {
+		if f.randFloat() >= f.rate {
+			running = append(running, executor)
+			continue
+		}
+
+		f.metricsScope.Tagged(map[string]string{"mode": f.mode}).
+			Counter(canarymetrics.CanaryExecutorFailureInjected).Inc(1)
+		f.logger.Warn("Injecting canary executor failure",
+			zap.String("namespace", executor.namespace), zap.String("mode", f.mode))
+
+		if f.mode == FailureModeExit {
+			f.exit(1)
+			return
+		}
+		executor.stop()
+	}
+	f.executors = running
+}
@@ -0,0 +1,114 @@
+package executors
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/fx"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/service/sharddistributor/canary/config"
+	"github.com/uber/cadence/service/sharddistributor/canary/processor"
+	"github.com/uber/cadence/service/sharddistributor/client/executorclient"
+)
+
+func TestFailureInjector_StopsFailedExecutors(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	scope := tally.NewTestScope("", nil)
+
+	var stoppedA, stoppedB atomic.Int32
+	injector := newFailureInjector(
+		config.CanaryConfig{ExecutorFailureRate: 0.5, ExecutorFailureInterval: time.Minute},
+		[]injectableExecutor{
+			{namespace: "ns-a", stop: func() { stoppedA.Add(1) }},
+			{namespace: "ns-b", stop: func() { stoppedB.Add(1) }},
+		},
+		timeSource,
+		zap.NewNop(),
+		scope,
+	)
+	// The first executor draws below the rate and fails, the second never does.
+	draws := []float64{0.1, 0.9, 0.9}
+	var drawn atomic.Int32
+	injector.randFloat = func() float64 {
+		return draws[drawn.Add(1)-1]
+	}
+	injector.exit = func(int) { t.Fatal("exit must not be called in stop-heartbeat mode") }
+
+	injector.Start(context.Background())
+	defer injector.Stop()
+
+	timeSource.BlockUntil(1)
+	timeSource.Advance(time.Minute)
+	require.Eventually(t, func() bool { return stoppedA.Load() == 1 }, time.Second, time.Millisecond)
+
+	// A failed executor is not drawn for again.
+	timeSource.Advance(time.Minute)
+	require.Eventually(t, func() bool { return int(drawn.Load()) == len(draws) }, time.Second, time.Millisecond)
+
+	injector.Stop()
+	assert.Equal(t, int32(1), stoppedA.Load())
+	assert.Zero(t, stoppedB.Load())
+	assert.Equal(t, int64(1), scope.Snapshot().Counters()["canary_executor_failure_injected+mode=stop-heartbeat"].Value())
+}
+
+func TestFailureInjector_ExitMode(t *testing.T) {
+	injector := newFailureInjector(
+		config.CanaryConfig{ExecutorFailureRate: 1, ExecutorFailureMode: FailureModeExit},
+		[]injectableExecutor{{namespace: "ns-a", stop: func() { t.Fatal("stop must not be called in exit mode") }}},
+		clock.NewMockedTimeSource(),
+		zap.NewNop(),
+		tally.NoopScope,
+	)
+	exitCode := -1
+	injector.exit = func(code int) { exitCode = code }
+
+	injector.injectFailures()
+
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, defaultFailureInterval, injector.interval)
+}
+
+// recordingLifecycle keeps the hooks appended to it, so tests can run them.
+type recordingLifecycle struct {
+	hooks []fx.Hook
+}
+
+func (r *recordingLifecycle) Append(hook fx.Hook) {
+	r.hooks = append(r.hooks, hook)
+}
+
+func TestNewExecutorsModule_FailureInjection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	executor := executorclient.NewMockExecutor[*processor.ShardProcessor](ctrl)
+	executor.EXPECT().GetNamespace().Return("ns-a")
+	// The executor is stopped once even though both the injector and the shutdown stop it.
+	executor.EXPECT().Stop().Times(1)
+
+	timeSource := clock.NewMockedTimeSource()
+	lifecycle := &recordingLifecycle{}
+	NewExecutorsModule(ExecutorsParams{
+		Lc:             lifecycle,
+		ExecutorsFixed: []executorclient.Executor[*processor.ShardProcessor]{executor},
+		Config:         config.Config{Canary: config.CanaryConfig{ExecutorFailureRate: 1}},
+		Logger:         zap.NewNop(),
+		TimeSource:     timeSource,
+		MetricsScope:   tally.NoopScope,
+	})
+	require.Len(t, lifecycle.hooks, 2, "expected a hook for the executor and one for the failure injector")
+
+	injectorHook := lifecycle.hooks[1]
+	require.NoError(t, injectorHook.OnStart(context.Background()))
+	timeSource.BlockUntil(1)
+	timeSource.Advance(defaultFailureInterval)
+	require.NoError(t, injectorHook.OnStop(context.Background()))
+
+	require.NoError(t, lifecycle.hooks[0].OnStop(context.Background()))
+}
@@ -91,9 +91,28 @@ func (k Kind) StopDelay() time.Duration {
 	}
 }
 
+// HashFunc maps a shard ID to the value used for kind selection. Only its
+// value modulo 100 matters: below 4 selects one of the injected kinds, so a
+// custom HashFunc can force a known set of shards onto a specific kind.
+type HashFunc func(shardID string) uint32
+
+// DefaultHash is the HashFunc used by ShardIDToKind.
+func DefaultHash(shardID string) uint32 {
+	return farm.Fingerprint32([]byte(shardID))
+}
+
 // ShardIDToKind deterministically maps a shard ID to a Kind.
 func ShardIDToKind(shardID string) Kind {
-	n := farm.Fingerprint32([]byte(shardID)) % distributionMod
+	return ShardIDToKindWithHash(shardID, DefaultHash)
+}
+
+// ShardIDToKindWithHash maps a shard ID to a Kind using the given hash
+// function, falling back to DefaultHash when hash is nil.
+func ShardIDToKindWithHash(shardID string, hash HashFunc) Kind {
+	if hash == nil {
+		hash = DefaultHash
+	}
+	n := hash(shardID) % distributionMod
 
 	switch {
 	case n < 1:
@@ -92,3 +92,28 @@ func TestShardIDToKind_FixedNamespaceCoverage(t *testing.T) {
 	}
 	assert.Truef(t, len(saw) > 1, "expected at least one non-normal kind across 32 fixed shards, got only %v", saw)
 }
+
+func TestShardIDToKindWithHash_CustomHash(t *testing.T) {
+	stuck := map[string]bool{"7": true, "13": true}
+	hash := func(shardID string) uint32 {
+		if stuck[shardID] {
+			return 2 // StuckStart
+		}
+		return 50 // Normal
+	}
+
+	for i := 0; i < 20; i++ {
+		shardID := strconv.Itoa(i)
+		want := Normal
+		if stuck[shardID] {
+			want = StuckStart
+		}
+		assert.Equal(t, want, ShardIDToKindWithHash(shardID, hash), "shardID %q", shardID)
+	}
+}
+
+func TestShardIDToKindWithHash_NilHashUsesDefault(t *testing.T) {
+	for _, id := range []string{"0", "1", "shard-foo", uuid.New().String()} {
+		assert.Equal(t, ShardIDToKind(id), ShardIDToKindWithHash(id, nil))
+	}
+}
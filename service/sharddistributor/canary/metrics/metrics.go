@@ -21,6 +21,9 @@ const (
 	// "stop") and bucket ("slow_start", "stuck_stop", ...). Use it to confirm
 	// the canary is actually exercising the slow paths.
 	CanaryShardLifecycleInjected = "canary_shard_lifecycle_injected"
+	// CanaryExecutorFailureInjected counts the executors the canary made fail
+	// on purpose, tagged by failure mode ("stop-heartbeat" or "exit").
+	CanaryExecutorFailureInjected = "canary_executor_failure_injected"
 
 	// Histogram metrics
 	CanaryPingLatency = "canary_ping_latency"
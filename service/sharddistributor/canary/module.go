@@ -44,10 +44,15 @@ func opts(names NamespacesNames) fx.Option {
 		// Modules for the shard distributor canary
 		fx.Provide(
 			func(params factory.Params) executorclient.ShardProcessorFactory[*processor.ShardProcessor] {
-				return factory.NewShardProcessorFactory(params, processor.NewShardProcessor)
+				return factory.NewShardProcessorFactory(params, processor.NewShardProcessorWithWarmUp(names.Config.Canary.ShardWarmUp))
 			},
 			func(params factory.Params) executorclient.ShardProcessorFactory[*processorephemeral.ShardProcessor] {
-				return factory.NewShardProcessorFactory(params, processorephemeral.NewShardProcessor)
+				loadModel := names.Config.Canary.EphemeralLoadModel
+				return factory.NewShardProcessorFactory(params, processorephemeral.NewShardProcessorWithLoadModel(processorephemeral.LoadModel{
+					MaxWeight:             loadModel.MaxWeight,
+					ScaleLifetimeByWeight: loadModel.ScaleLifetimeByWeight,
+					WarmUp:                names.Config.Canary.ShardWarmUp,
+				}))
 			},
 		),
 
@@ -14,6 +14,7 @@ import (
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/canary/latencykind"
 	canarymetrics "github.com/uber/cadence/service/sharddistributor/canary/metrics"
+	"github.com/uber/cadence/service/sharddistributor/canary/warmup"
 	"github.com/uber/cadence/service/sharddistributor/client/executorclient"
 )
 
@@ -25,11 +26,24 @@ const (
 
 // NewShardProcessor creates a new ShardProcessor.
 func NewShardProcessor(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+	return newShardProcessor(shardID, 0, timeSource, logger, metricsScope)
+}
+
+// NewShardProcessorWithWarmUp returns a ShardProcessor constructor whose shards ramp their load
+// up to the full load over warmUp after they start.
+func NewShardProcessorWithWarmUp(warmUp time.Duration) func(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+	return func(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+		return newShardProcessor(shardID, warmUp, timeSource, logger, metricsScope)
+	}
+}
+
+func newShardProcessor(shardID string, warmUp time.Duration, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
 	kind := latencykind.ShardIDToKind(shardID)
 	scope := metricsScope.Tagged(map[string]string{"latency_kind": kind.String()})
 	p := &ShardProcessor{
 		shardID:      shardID,
 		shardLoad:    shardLoadFromID(shardID),
+		warmUp:       warmup.NewRamp(warmUp, timeSource),
 		kind:         kind,
 		timeSource:   timeSource,
 		logger:       logger,
@@ -44,6 +58,7 @@ func NewShardProcessor(shardID string, timeSource clock.TimeSource, logger *zap.
 type ShardProcessor struct {
 	shardID      string
 	shardLoad    float64
+	warmUp       *warmup.Ramp
 	kind         latencykind.Kind
 	timeSource   clock.TimeSource
 	logger       *zap.Logger
@@ -60,7 +75,7 @@ var _ executorclient.ShardProcessor = (*ShardProcessor)(nil)
 // GetShardReport implements executorclient.ShardProcessor.
 func (p *ShardProcessor) GetShardReport() executorclient.ShardReport {
 	return executorclient.ShardReport{
-		ShardLoad: p.shardLoad,                        // We return a load from shardID
+		ShardLoad: p.warmUp.Load(p.shardLoad),         // We return a load from shardID, ramped up while warming up
 		Status:    types.ShardStatus(p.status.Load()), // Report the shard as ready since it's actively processing
 	}
 }
@@ -81,6 +96,7 @@ func (p *ShardProcessor) Start(_ context.Context) error {
 
 	p.metricsScope.Counter(canarymetrics.CanaryShardStarted).Inc(1)
 	p.logger.Debug("Starting shard processor", zap.String("shardID", p.shardID))
+	p.warmUp.Start()
 	p.goRoutineWg.Add(1)
 	go p.process()
 	return nil
@@ -40,6 +40,24 @@ func TestShardProcessor_GetShardReport(t *testing.T) {
 	assert.Equal(t, types.ShardStatusREADY, report.Status)
 }
 
+func TestShardProcessor_GetShardReport_WarmUp(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	processor := NewShardProcessorWithWarmUp(time.Minute)("10", timeSource, zaptest.NewLogger(t), tally.NoopScope)
+
+	require.NoError(t, processor.Start(context.Background()))
+	defer processor.Stop()
+
+	previous := processor.GetShardReport().ShardLoad
+	assert.Less(t, previous, 10.0)
+	for range 3 {
+		timeSource.Advance(20 * time.Second)
+		load := processor.GetShardReport().ShardLoad
+		assert.Greater(t, load, previous)
+		previous = load
+	}
+	assert.Equal(t, 10.0, previous)
+}
+
 func TestShardProcessor_Start_Process_Stop(t *testing.T) {
 	// Verify that after stopping the processor, there are no goroutines left
 	goleak.VerifyNone(t)
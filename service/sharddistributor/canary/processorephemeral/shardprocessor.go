@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	farm "github.com/dgryski/go-farm"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/canary/latencykind"
 	canarymetrics "github.com/uber/cadence/service/sharddistributor/canary/metrics"
+	"github.com/uber/cadence/service/sharddistributor/canary/warmup"
 	"github.com/uber/cadence/service/sharddistributor/client/executorclient"
 )
 
@@ -27,12 +29,54 @@ const (
 	shardProcessorDoneChance = 60
 )
 
+// LoadModel describes the load and lifetime of the ephemeral shards.
+// The zero value gives every shard a weight of 1 and the same completion chance.
+type LoadModel struct {
+	// MaxWeight is the heaviest weight a shard can get. Shards get a weight in [1, MaxWeight]
+	// from a hash of their ID, and report it as their load.
+	MaxWeight int
+	// ScaleLifetimeByWeight divides the completion chance of a shard by its weight,
+	// so a shard of weight w lives w times longer on average.
+	ScaleLifetimeByWeight bool
+	// WarmUp is how long a shard takes to ramp its load up to its weight after it starts.
+	// Shards report their full weight right away when it is not positive.
+	WarmUp time.Duration
+}
+
+// weight returns the weight of the shard under the load model.
+func (m LoadModel) weight(shardID string) int {
+	if m.MaxWeight <= 1 {
+		return 1
+	}
+	return int(farm.Fingerprint32([]byte(shardID))%uint32(m.MaxWeight)) + 1
+}
+
 // NewShardProcessor creates a new ShardProcessor.
 func NewShardProcessor(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+	return newShardProcessor(shardID, LoadModel{}, timeSource, logger, metricsScope)
+}
+
+// NewShardProcessorWithLoadModel returns a ShardProcessor constructor that uses the given load model.
+func NewShardProcessorWithLoadModel(model LoadModel) func(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+	return func(shardID string, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
+		return newShardProcessor(shardID, model, timeSource, logger, metricsScope)
+	}
+}
+
+func newShardProcessor(shardID string, model LoadModel, timeSource clock.TimeSource, logger *zap.Logger, metricsScope tally.Scope) *ShardProcessor {
 	kind := latencykind.ShardIDToKind(shardID)
 	scope := metricsScope.Tagged(map[string]string{"latency_kind": kind.String()})
+	doneChance := shardProcessorDoneChance
+	weight := model.weight(shardID)
+	if model.ScaleLifetimeByWeight {
+		doneChance *= weight
+	}
 	p := &ShardProcessor{
 		shardID:      shardID,
+		weight:       weight,
+		warmUp:       warmup.NewRamp(model.WarmUp, timeSource),
+		doneChance:   doneChance,
+		randIntn:     rand.Intn,
 		kind:         kind,
 		timeSource:   timeSource,
 		logger:       logger,
@@ -46,6 +90,10 @@ func NewShardProcessor(shardID string, timeSource clock.TimeSource, logger *zap.
 // ShardProcessor is a processor for a shard.
 type ShardProcessor struct {
 	shardID      string
+	weight       int
+	warmUp       *warmup.Ramp
+	doneChance   int
+	randIntn     func(n int) int
 	kind         latencykind.Kind
 	timeSource   clock.TimeSource
 	logger       *zap.Logger
@@ -62,7 +110,7 @@ var _ executorclient.ShardProcessor = (*ShardProcessor)(nil)
 // GetShardReport implements executorclient.ShardProcessor.
 func (p *ShardProcessor) GetShardReport() executorclient.ShardReport {
 	return executorclient.ShardReport{
-		ShardLoad: 1.0,                                // We return 1.0 for all shards for now.
+		ShardLoad: p.warmUp.Load(float64(p.weight)),   // The weight from the load model, 1.0 by default
 		Status:    types.ShardStatus(p.status.Load()), // Report the status of the shard
 	}
 }
@@ -83,6 +131,7 @@ func (p *ShardProcessor) Start(_ context.Context) error {
 
 	p.metricsScope.Counter(canarymetrics.CanaryShardStarted).Inc(1)
 	p.logger.Debug("Starting shard processor", zap.String("shardID", p.shardID))
+	p.warmUp.Start()
 	p.goRoutineWg.Add(1)
 	go p.process()
 	return nil
@@ -125,7 +174,7 @@ func (p *ShardProcessor) process() {
 		case <-ticker.Chan():
 			p.processSteps++
 			p.metricsScope.Counter(canarymetrics.CanaryShardProcessStep).Inc(1)
-			if rand.Intn(shardProcessorDoneChance) == 0 {
+			if p.completesThisStep() {
 				p.logger.Debug("Setting shard processor to done", zap.String("shardID", p.shardID), zap.Int("steps", p.processSteps), zap.String("status", types.ShardStatus(p.status.Load()).String()))
 				p.metricsScope.Counter(canarymetrics.CanaryShardDone).Inc(1)
 				p.SetShardStatus(types.ShardStatusDONE)
@@ -133,3 +182,9 @@ func (p *ShardProcessor) process() {
 		}
 	}
 }
+
+// completesThisStep reports whether the shard is done after the current step,
+// which happens with a chance of 1 in doneChance.
+func (p *ShardProcessor) completesThisStep() bool {
+	return p.randIntn(p.doneChance) == 0
+}
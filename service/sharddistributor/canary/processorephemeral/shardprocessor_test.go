@@ -2,6 +2,8 @@ package processorephemeral
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -40,6 +42,49 @@ func TestShardProcessor_GetShardReport(t *testing.T) {
 	assert.Equal(t, types.ShardStatusREADY, report.Status)
 }
 
+func TestShardProcessor_LoadModel(t *testing.T) {
+	newProcessor := NewShardProcessorWithLoadModel(LoadModel{MaxWeight: 4})
+
+	weights := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		processor := newProcessor(fmt.Sprintf("shard-%d", i), clock.NewRealTimeSource(), zaptest.NewLogger(t), tally.NoopScope)
+		require.GreaterOrEqual(t, processor.weight, 1)
+		require.LessOrEqual(t, processor.weight, 4)
+		assert.Equal(t, float64(processor.weight), processor.GetShardReport().ShardLoad)
+		// Without lifetime scaling every shard has the same completion chance.
+		assert.Equal(t, shardProcessorDoneChance, processor.doneChance)
+		weights[processor.weight]++
+	}
+	assert.Len(t, weights, 4, "expected shards of every weight")
+}
+
+func TestShardProcessor_HeavyShardsLiveLonger(t *testing.T) {
+	newProcessor := NewShardProcessorWithLoadModel(LoadModel{MaxWeight: 4, ScaleLifetimeByWeight: true})
+	rng := rand.New(rand.NewSource(42))
+
+	totalSteps := make(map[int]int)
+	shards := make(map[int]int)
+	for i := 0; i < 400; i++ {
+		processor := newProcessor(fmt.Sprintf("shard-%d", i), clock.NewRealTimeSource(), zaptest.NewLogger(t), tally.NoopScope)
+		processor.randIntn = rng.Intn
+
+		steps := 1
+		for !processor.completesThisStep() {
+			steps++
+		}
+		totalSteps[processor.weight] += steps
+		shards[processor.weight]++
+	}
+
+	meanLifetime := func(weight int) float64 {
+		require.NotZero(t, shards[weight], "no shards of weight %d", weight)
+		return float64(totalSteps[weight]) / float64(shards[weight])
+	}
+	assert.Greater(t, meanLifetime(4), meanLifetime(1))
+	assert.Greater(t, meanLifetime(4), 2*float64(shardProcessorDoneChance))
+	assert.Less(t, meanLifetime(1), 2*float64(shardProcessorDoneChance))
+}
+
 func TestShardProcessor_Start_Process_Stop(t *testing.T) {
 	// Verify that after stopping the processor, there are no goroutines left
 	goleak.VerifyNone(t)
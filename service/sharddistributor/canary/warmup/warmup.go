@@ -0,0 +1,58 @@
+// Package warmup ramps the load canary shards report after they start.
+//
+// A real shard takes a while to reach its full load after it starts, e.g. while
+// caches fill up. Canary shards report a synthetic load, so without a warm-up
+// they report their full load right away. Ramping it up lets the shard
+// distributor's handling of new shards be exercised with realistic load curves.
+package warmup
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+)
+
+// initialFraction is the fraction of its full load a shard reports when it starts warming up.
+const initialFraction = 0.1
+
+// Ramp tracks the warm-up of a single shard. The zero value, or a Ramp with a
+// non-positive duration, reports the full load right away. It is safe for
+// concurrent use, so the shard report can be read while the shard starts.
+type Ramp struct {
+	duration   time.Duration
+	timeSource clock.TimeSource
+	startedAt  atomic.Int64
+}
+
+// NewRamp returns a Ramp that takes duration to reach the full load, measured with timeSource.
+func NewRamp(duration time.Duration, timeSource clock.TimeSource) *Ramp {
+	return &Ramp{duration: duration, timeSource: timeSource}
+}
+
+// Start marks the shard as started, the warm-up begins from now.
+func (r *Ramp) Start() {
+	if r == nil || r.duration <= 0 {
+		return
+	}
+	r.startedAt.Store(r.timeSource.Now().UnixNano())
+}
+
+// Load returns the load to report for a shard of fullLoad. It grows linearly from
+// a fraction of fullLoad when the shard starts to fullLoad once the warm-up passed.
+// A shard that is not started yet reports the initial fraction.
+func (r *Ramp) Load(fullLoad float64) float64 {
+	if r == nil || r.duration <= 0 {
+		return fullLoad
+	}
+	startedAt := r.startedAt.Load()
+	if startedAt == 0 {
+		return fullLoad * initialFraction
+	}
+	elapsed := r.timeSource.Now().Sub(time.Unix(0, startedAt))
+	if elapsed >= r.duration {
+		return fullLoad
+	}
+	progress := max(float64(elapsed)/float64(r.duration), 0)
+	return fullLoad * (initialFraction + (1-initialFraction)*progress)
+}
@@ -0,0 +1,41 @@
+package warmup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+)
+
+func TestRamp_LoadIncreasesMonotonicallyDuringWarmUp(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	ramp := NewRamp(time.Minute, timeSource)
+
+	assert.Equal(t, 10*initialFraction, ramp.Load(10), "a shard that is not started reports the initial fraction")
+
+	ramp.Start()
+	previous := ramp.Load(10)
+	assert.Equal(t, 10*initialFraction, previous)
+	for range 6 {
+		timeSource.Advance(10 * time.Second)
+		load := ramp.Load(10)
+		assert.Greater(t, load, previous)
+		previous = load
+	}
+	assert.Equal(t, 10.0, previous, "the shard reports its full load once the warm-up passed")
+
+	timeSource.Advance(time.Hour)
+	assert.Equal(t, 10.0, ramp.Load(10))
+}
+
+func TestRamp_NoWarmUpReportsFullLoad(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+
+	assert.Equal(t, 10.0, NewRamp(0, timeSource).Load(10))
+
+	var ramp *Ramp
+	ramp.Start()
+	assert.Equal(t, 10.0, ramp.Load(10))
+}
@@ -35,7 +35,9 @@ type ExecutorMetadata map[string]string
 
 type ShardReport struct {
 	ShardLoad float64
-	Status    types.ShardStatus
+	// ShardLoads optionally reports the load per named dimension (e.g. cpu, memory).
+	ShardLoads map[string]float64
+	Status     types.ShardStatus
 }
 
 type ShardProcessor interface {
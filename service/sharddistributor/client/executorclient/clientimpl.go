@@ -99,6 +99,8 @@ type executorImpl[SP ShardProcessor] struct {
 	ttlShard               time.Duration
 	managedProcessors      syncgeneric.Map[string, *managedProcessor[SP]]
 	processorsToLastUse    syncgeneric.Map[string, time.Time]
+	shardLeases            syncgeneric.Map[string, time.Time]
+	handedOffShards        syncgeneric.Map[string, struct{}]
 	executorID             string
 	timeSource             clock.TimeSource
 	processLoopWG          sync.WaitGroup
@@ -248,6 +250,8 @@ func (e *executorImpl[SP]) heartbeatloop(ctx context.Context) {
 				e.logger.Info("local passthrough mode: stopping heartbeat loop")
 				return
 			}
+			// Runs after failed heartbeats too: that is exactly when leases stop being renewed.
+			e.stopShardsWithExpiredLease()
 			if err != nil {
 				e.logger.Error("failed to heartbeat and assign shards", tag.Error(err))
 				continue
@@ -337,12 +341,20 @@ func (e *executorImpl[SP]) sendHeartbeat(ctx context.Context, status types.Execu
 			shardStatus := managedProcessor.processor.GetShardReport()
 
 			shardStatusReports[shardID] = &types.ShardStatusReport{
-				ShardLoad: shardStatus.ShardLoad,
-				Status:    shardStatus.Status,
+				ShardLoad:  shardStatus.ShardLoad,
+				ShardLoads: shardStatus.ShardLoads,
+				Status:     shardStatus.Status,
 			}
 		}
 		return true
 	})
+	// A shard stopped after draining is reported DONE, so the shard distributor completes its handoff.
+	e.handedOffShards.Range(func(shardID string, _ struct{}) bool {
+		if _, ok := shardStatusReports[shardID]; !ok {
+			shardStatusReports[shardID] = &types.ShardStatusReport{Status: types.ShardStatusDONE}
+		}
+		return true
+	})
 
 	e.hostMetrics.Gauge(metricsconstants.ShardDistributorExecutorOwnedShards).Update(float64(len(shardStatusReports)))
 
@@ -389,19 +401,81 @@ func (e *executorImpl[SP]) updateShardAssignment(ctx context.Context, shardAssig
 	// Stop shards no longer assigned. Each call fires 2 goroutines: one for the
 	// Stop() call and one per-shard timeout watcher.
 	e.managedProcessors.Range(func(shardID string, managedProcessor *managedProcessor[SP]) bool {
-		if assignment, ok := shardAssignments[shardID]; !ok || assignment.Status != types.AssignmentStatusREADY {
+		if assignment, ok := shardAssignments[shardID]; !ok || !isServedAssignment(assignment.Status) {
 			e.stopManagerProcessor(shardID)
 		}
 		return true
 	})
 
+	// A handed off shard is reported DONE until the shard distributor stops returning it,
+	// or assigns it back to this executor.
+	e.handedOffShards.Range(func(shardID string, _ struct{}) bool {
+		if assignment, ok := shardAssignments[shardID]; !ok || assignment.Status != types.AssignmentStatusDRAINING {
+			e.handedOffShards.Delete(shardID)
+		}
+		return true
+	})
+
 	// Start newly assigned shards. Each call fires 2 goroutines: one for the
 	// Start() call and one per-shard timeout watcher.
 	for shardID, assignment := range shardAssignments {
-		if assignment.Status == types.AssignmentStatusREADY {
+		switch {
+		case isServedAssignment(assignment.Status):
 			e.addManagerProcessor(ctx, shardID)
+		case assignment.Status == types.AssignmentStatusDRAINING:
+			e.handedOffShards.Store(shardID, struct{}{})
 		}
 	}
+
+	e.renewShardLeases(shardAssignments)
+}
+
+// isServedAssignment returns whether a shard assigned with the given status is served by the executor.
+// During a controlled handoff both the previous and the new owner serve a PREPARING shard, the previous
+// owner stops serving it once it is DRAINING.
+func isServedAssignment(status types.AssignmentStatus) bool {
+	return status == types.AssignmentStatusREADY || status == types.AssignmentStatusPREPARING
+}
+
+// renewShardLeases records the lease expiry of every served shard in the assignment.
+// Shards without a lease, or no longer assigned, are forgotten.
+func (e *executorImpl[SP]) renewShardLeases(shardAssignments map[string]*types.ShardAssignment) {
+	e.shardLeases.Range(func(shardID string, _ time.Time) bool {
+		if _, ok := shardAssignments[shardID]; !ok {
+			e.shardLeases.Delete(shardID)
+		}
+		return true
+	})
+
+	for shardID, assignment := range shardAssignments {
+		if !isServedAssignment(assignment.Status) || assignment.LeaseExpiresAt.IsZero() {
+			e.shardLeases.Delete(shardID)
+			continue
+		}
+		e.shardLeases.Store(shardID, assignment.LeaseExpiresAt)
+	}
+}
+
+// stopShardsWithExpiredLease stops the processors of shards whose lease expired without being
+// renewed, so an executor that lost contact with the shard distributor does not keep processing
+// shards that may already be owned elsewhere. A later successful heartbeat restarts them.
+func (e *executorImpl[SP]) stopShardsWithExpiredLease() {
+	e.assignmentMutex.Lock()
+	defer e.assignmentMutex.Unlock()
+
+	now := e.timeSource.Now()
+	e.shardLeases.Range(func(shardID string, leaseExpiresAt time.Time) bool {
+		if now.Before(leaseExpiresAt) {
+			return true
+		}
+		e.logger.Warn("shard lease expired without renewal, stopping shard processor",
+			tag.ShardKey(shardID),
+			tag.Dynamic("lease_expires_at", leaseExpiresAt))
+		e.metrics.Counter(metricsconstants.ShardDistributorExecutorShardLeasesExpired).Inc(1)
+		e.stopManagerProcessor(shardID)
+		e.shardLeases.Delete(shardID)
+		return true
+	})
 }
 
 func (e *executorImpl[SP]) addNewShards(ctx context.Context, shardAssignments map[string]*types.ShardAssignment) {
@@ -3,11 +3,13 @@ package executorclient
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
@@ -15,9 +17,17 @@ import (
 
 	"github.com/uber/cadence/client/sharddistributorexecutor"
 	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/dynamicconfig/dynamicproperties"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/testlogger"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/client/executorclient/syncgeneric"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
+	"github.com/uber/cadence/service/sharddistributor/handler"
+	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
 // closeDrainObserver is a test helper that implements DrainSignalObserver
@@ -1317,3 +1327,280 @@ func TestHeartbeatLoop_DrainSignal(t *testing.T) {
 		})
 	}
 }
+
+func TestShardLease_RenewalAndExpiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockTimeSource := clock.NewMockedTimeSource()
+
+	shardProcessorMock := NewMockShardProcessor(ctrl)
+	shardProcessorMock.EXPECT().Start(gomock.Any()).Return(nil)
+	shardProcessorFactory := NewMockShardProcessorFactory[*MockShardProcessor](ctrl)
+	shardProcessorFactory.EXPECT().NewShardProcessor("test-shard-id1").Return(shardProcessorMock, nil)
+
+	executor := newTestExecutor(nil, shardProcessorFactory, mockTimeSource)
+
+	// The first assignment grants a lease of 30 seconds
+	executor.updateShardAssignment(context.Background(), map[string]*types.ShardAssignment{
+		"test-shard-id1": {Status: types.AssignmentStatusREADY, LeaseExpiresAt: mockTimeSource.Now().Add(30 * time.Second)},
+	})
+	time.Sleep(10 * time.Millisecond) // Force the updateShardAssignment goroutines to run
+
+	// Before the lease expires the shard keeps running
+	mockTimeSource.Advance(20 * time.Second)
+	executor.stopShardsWithExpiredLease()
+	_, ok := executor.managedProcessors.Load("test-shard-id1")
+	assert.True(t, ok)
+
+	// A heartbeat renews the lease, so passing the original expiry does not stop the shard
+	renewedLease := mockTimeSource.Now().Add(30 * time.Second)
+	executor.updateShardAssignment(context.Background(), map[string]*types.ShardAssignment{
+		"test-shard-id1": {Status: types.AssignmentStatusREADY, LeaseExpiresAt: renewedLease},
+	})
+	mockTimeSource.Advance(20 * time.Second)
+	executor.stopShardsWithExpiredLease()
+	_, ok = executor.managedProcessors.Load("test-shard-id1")
+	assert.True(t, ok)
+	lease, ok := executor.shardLeases.Load("test-shard-id1")
+	assert.True(t, ok)
+	assert.Equal(t, renewedLease, lease)
+
+	// Without further renewal the lease expires and the shard is stopped
+	shardProcessorMock.EXPECT().Stop()
+	mockTimeSource.Advance(20 * time.Second)
+	executor.stopShardsWithExpiredLease()
+	time.Sleep(10 * time.Millisecond) // Force the stop goroutine to run
+
+	_, ok = executor.managedProcessors.Load("test-shard-id1")
+	assert.False(t, ok)
+	_, ok = executor.shardLeases.Load("test-shard-id1")
+	assert.False(t, ok)
+}
+
+func TestShardLease_NoLeaseNeverExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockTimeSource := clock.NewMockedTimeSource()
+
+	shardProcessorMock := NewMockShardProcessor(ctrl)
+	shardProcessorMock.EXPECT().Start(gomock.Any()).Return(nil)
+	shardProcessorFactory := NewMockShardProcessorFactory[*MockShardProcessor](ctrl)
+	shardProcessorFactory.EXPECT().NewShardProcessor("test-shard-id1").Return(shardProcessorMock, nil)
+
+	executor := newTestExecutor(nil, shardProcessorFactory, mockTimeSource)
+
+	executor.updateShardAssignment(context.Background(), map[string]*types.ShardAssignment{
+		"test-shard-id1": {Status: types.AssignmentStatusREADY},
+	})
+	time.Sleep(10 * time.Millisecond) // Force the updateShardAssignment goroutines to run
+
+	mockTimeSource.Advance(time.Hour)
+	executor.stopShardsWithExpiredLease()
+
+	_, ok := executor.managedProcessors.Load("test-shard-id1")
+	assert.True(t, ok)
+}
+
+func TestUpdateShardAssignment_ControlledHandoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	preparingProcessor := NewMockShardProcessor(ctrl)
+	preparingProcessor.EXPECT().Start(gomock.Any()).Return(nil)
+	drainingProcessor := NewMockShardProcessor(ctrl)
+	shardProcessorFactory := NewMockShardProcessorFactory[*MockShardProcessor](ctrl)
+	shardProcessorFactory.EXPECT().NewShardProcessor("shard-taken-over").Return(preparingProcessor, nil)
+
+	shardDistributorClient := sharddistributorexecutor.NewMockClient(ctrl)
+	executor := newTestExecutor(shardDistributorClient, shardProcessorFactory, nil)
+	executor.managedProcessors.Store("shard-handed-off", newManagedProcessor(drainingProcessor, processorStateStarted))
+
+	// A preparing shard is started, and keeps serving while the new owner prepares it
+	executor.updateShardAssignment(context.Background(), map[string]*types.ShardAssignment{
+		"shard-taken-over": {Status: types.AssignmentStatusPREPARING},
+		"shard-handed-off": {Status: types.AssignmentStatusPREPARING},
+	})
+	time.Sleep(10 * time.Millisecond) // Force the updateShardAssignment goroutines to run
+	_, ok := executor.managedProcessors.Load("shard-taken-over")
+	assert.True(t, ok)
+	_, ok = executor.managedProcessors.Load("shard-handed-off")
+	assert.True(t, ok)
+
+	// A draining shard is stopped and reported DONE
+	drainingProcessor.EXPECT().Stop()
+	executor.updateShardAssignment(context.Background(), map[string]*types.ShardAssignment{
+		"shard-taken-over": {Status: types.AssignmentStatusREADY},
+		"shard-handed-off": {Status: types.AssignmentStatusDRAINING},
+	})
+	time.Sleep(10 * time.Millisecond) // Force the updateShardAssignment goroutines to run
+	_, ok = executor.managedProcessors.Load("shard-handed-off")
+	assert.False(t, ok)
+
+	preparingProcessor.EXPECT().GetShardReport().Return(ShardReport{Status: types.ShardStatusREADY}).Times(2)
+	shardDistributorClient.EXPECT().Heartbeat(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *types.ExecutorHeartbeatRequest, _ ...yarpc.CallOption) (*types.ExecutorHeartbeatResponse, error) {
+			assert.Equal(t, map[string]*types.ShardStatusReport{
+				"shard-taken-over": {Status: types.ShardStatusREADY},
+				"shard-handed-off": {Status: types.ShardStatusDONE},
+			}, req.ShardStatusReports)
+			return &types.ExecutorHeartbeatResponse{ShardAssignments: map[string]*types.ShardAssignment{
+				"shard-taken-over": {Status: types.AssignmentStatusREADY},
+			}}, nil
+		})
+	shardAssignments, _, err := executor.heartbeat(context.Background())
+	assert.NoError(t, err)
+
+	// Once the handoff completed the shard is no longer reported
+	executor.updateShardAssignment(context.Background(), shardAssignments)
+	shardDistributorClient.EXPECT().Heartbeat(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *types.ExecutorHeartbeatRequest, _ ...yarpc.CallOption) (*types.ExecutorHeartbeatResponse, error) {
+			assert.Equal(t, map[string]*types.ShardStatusReport{
+				"shard-taken-over": {Status: types.ShardStatusREADY},
+			}, req.ShardStatusReports)
+			return &types.ExecutorHeartbeatResponse{}, nil
+		})
+	_, _, err = executor.heartbeat(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestControlledHandoff_EndToEnd(t *testing.T) {
+	ctx := context.Background()
+	const (
+		namespace     = "test-namespace"
+		shardID       = "shard-1"
+		previousOwner = "exec-old"
+		newOwner      = "exec-new"
+	)
+
+	ctrl := gomock.NewController(t)
+	var mu sync.Mutex
+	heartbeats := map[string]store.HeartbeatState{}
+	assigned := map[string]*store.AssignedState{
+		previousOwner: {AssignedShards: map[string]*types.ShardAssignment{shardID: {Status: types.AssignmentStatusREADY}}},
+		newOwner:      {AssignedShards: map[string]*types.ShardAssignment{}},
+	}
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, executorID string) (*store.HeartbeatState, *store.AssignedState, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			heartbeat, ok := heartbeats[executorID]
+			if !ok {
+				return nil, nil, store.ErrExecutorNotFound
+			}
+			assignedState := store.AssignedState{AssignedShards: maps.Clone(assigned[executorID].AssignedShards)}
+			return &heartbeat, &assignedState, nil
+		}).AnyTimes()
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, executorID string, state store.HeartbeatState) error {
+			mu.Lock()
+			defer mu.Unlock()
+			heartbeats[executorID] = state
+			return nil
+		}).AnyTimes()
+	// ownerOf must be called with mu held.
+	ownerOf := func(shardID string) *store.ShardOwner {
+		for executorID, assignedState := range assigned {
+			if _, ok := assignedState.AssignedShards[shardID]; ok {
+				return &store.ShardOwner{ExecutorID: executorID}
+			}
+		}
+		return nil
+	}
+	mockStore.EXPECT().GetShardOwner(gomock.Any(), namespace, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, shardID string) (*store.ShardOwner, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if owner := ownerOf(shardID); owner != nil {
+				return owner, nil
+			}
+			return nil, store.ErrShardNotFound
+		}).AnyTimes()
+	mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, shardIDs []string) (map[string]*store.ShardOwner, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			owners := make(map[string]*store.ShardOwner, len(shardIDs))
+			for _, shardID := range shardIDs {
+				if owner := ownerOf(shardID); owner != nil {
+					owners[shardID] = owner
+				}
+			}
+			return owners, nil
+		}).AnyTimes()
+
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED))
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorControlledHandoff, true))
+	shardDistribution := config.ShardDistribution{Namespaces: []config.Namespace{{Name: namespace, Type: config.NamespaceTypeEphemeral}}}
+	executorHandler := handler.NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), shardDistribution,
+		config.NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t))), metrics.NoopClient, events.NewNoop())
+
+	newExecutor := func(executorID string) *executorImpl[*MockShardProcessor] {
+		shardDistributorClient := sharddistributorexecutor.NewMockClient(ctrl)
+		shardDistributorClient.EXPECT().Heartbeat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *types.ExecutorHeartbeatRequest, _ ...yarpc.CallOption) (*types.ExecutorHeartbeatResponse, error) {
+				return executorHandler.Heartbeat(ctx, req)
+			}).AnyTimes()
+		shardProcessorFactory := NewMockShardProcessorFactory[*MockShardProcessor](ctrl)
+		shardProcessorFactory.EXPECT().NewShardProcessor(shardID).DoAndReturn(func(string) (*MockShardProcessor, error) {
+			processor := NewMockShardProcessor(ctrl)
+			processor.EXPECT().Start(gomock.Any()).Return(nil)
+			processor.EXPECT().Stop().AnyTimes()
+			processor.EXPECT().GetShardReport().Return(ShardReport{Status: types.ShardStatusREADY}).AnyTimes()
+			return processor, nil
+		}).AnyTimes()
+
+		executor := newTestExecutor(shardDistributorClient, shardProcessorFactory, nil)
+		executor.executorID = executorID
+		return executor
+	}
+	executors := map[string]*executorImpl[*MockShardProcessor]{
+		previousOwner: newExecutor(previousOwner),
+		newOwner:      newExecutor(newOwner),
+	}
+	serves := func(executorID string) bool {
+		managedProcessor, ok := executors[executorID].managedProcessors.Load(shardID)
+		return ok && managedProcessor.getState() == processorStateStarted
+	}
+	heartbeat := func(executorID string) {
+		executor := executors[executorID]
+		shardAssignments, _, err := executor.heartbeat(ctx)
+		require.NoError(t, err)
+		executor.updateShardAssignment(ctx, shardAssignments)
+		time.Sleep(10 * time.Millisecond) // Force the updateShardAssignment goroutines to run
+	}
+	deletedShards := func() map[string]store.ShardState {
+		mu.Lock()
+		defer mu.Unlock()
+		state := &store.NamespaceState{Executors: heartbeats, ShardAssignments: make(map[string]store.AssignedState)}
+		for executorID, assignedState := range assigned {
+			state.ShardAssignments[executorID] = *assignedState
+		}
+		return state.DeletedShards()
+	}
+
+	// The first heartbeat registers the executors, the second one returns their assignment.
+	for range 2 {
+		heartbeat(previousOwner)
+		heartbeat(newOwner)
+	}
+	require.True(t, serves(previousOwner))
+
+	// The leader moves the shard to the new owner.
+	mu.Lock()
+	assigned[previousOwner] = &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{}}
+	assigned[newOwner] = &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{shardID: {Status: types.AssignmentStatusREADY}}}
+	mu.Unlock()
+
+	for step := 0; step < 5; step++ {
+		for _, executorID := range []string{newOwner, previousOwner} {
+			heartbeat(executorID)
+			require.True(t, serves(previousOwner) || serves(newOwner), "step %d: shard not served after heartbeat of %s", step, executorID)
+			require.NotContains(t, deletedShards(), shardID, "step %d: shard deleted after heartbeat of %s", step, executorID)
+		}
+	}
+
+	assert.True(t, serves(newOwner))
+	assert.False(t, serves(previousOwner))
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotContains(t, heartbeats[previousOwner].ReportedShards, shardID, "the previous owner stops reporting the handed off shard")
+}
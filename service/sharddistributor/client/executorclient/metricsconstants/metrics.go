@@ -21,6 +21,7 @@ const (
 	ShardDistributorExecutorProcessorStartTimeout     = "shard_distributor_executor_processor_start_timeout"
 	ShardDistributorExecutorProcessorStopTimeout      = "shard_distributor_executor_processor_stop_timeout"
 	ShardDistributorExecutorShardsCleanedUpDone       = "shard_distributor_executor_shards_cleaned_up_done"
+	ShardDistributorExecutorShardLeasesExpired        = "shard_distributor_executor_shard_leases_expired"
 
 	// Gauge metrics
 	ShardDistributorExecutorOwnedShards = "shard_distributor_executor_owned_shards"
@@ -23,6 +23,8 @@
 package config
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -39,6 +41,40 @@ type (
 		MigrationMode     dynamicproperties.StringPropertyFnWithNamespaceFilters
 		MaxEtcdTxnOps     dynamicproperties.IntPropertyFn
 
+		StoreRetryMaxAttempts     dynamicproperties.IntPropertyFn
+		StoreRetryInitialInterval dynamicproperties.DurationPropertyFn
+
+		EmptyExecutorDonorSelection dynamicproperties.StringPropertyFnWithNamespaceFilters
+		ShardLeaseDuration          dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		RejectUnknownShardReports   dynamicproperties.BoolPropertyFnWithNamespaceFilters
+		RebalancePaused             dynamicproperties.BoolPropertyFnWithNamespaceFilters
+		StaticHashFallback          dynamicproperties.BoolPropertyFnWithNamespaceFilters
+		ControlledHandoff           dynamicproperties.BoolPropertyFnWithNamespaceFilters
+
+		ShardGroups           dynamicproperties.MapPropertyFnWithNamespaceFilters
+		MaxGroupShardsPerZone dynamicproperties.IntPropertyFnWithNamespaceFilters
+		MinActiveExecutors    dynamicproperties.IntPropertyFnWithNamespaceFilters
+		AssignmentRampCap     dynamicproperties.IntPropertyFnWithNamespaceFilters
+		MaxMovesPerCycle      dynamicproperties.IntPropertyFnWithNamespaceFilters
+		RebalanceApplyWorkers dynamicproperties.IntPropertyFnWithNamespaceFilters
+		PinnedShards          dynamicproperties.MapPropertyFnWithNamespaceFilters
+		ShardLabelSelectors   dynamicproperties.MapPropertyFnWithNamespaceFilters
+		ShardSLATiers         dynamicproperties.MapPropertyFnWithNamespaceFilters
+
+		ExecutorLoadCapacity       dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ConsolidationLoadThreshold dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ShardOvercommitFactor      dynamicproperties.Float64PropertyFnWithNamespaceFilters
+
+		RebalanceInterval          dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		RebalanceJitterCoefficient dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		MaxShardLoad               dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ShardLoadBudget            dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ZombieShardAge             dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		StabilityWindow            dynamicproperties.DurationPropertyFnWithNamespaceFilters
+
+		HealthScoreLoadWeight       dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		HealthScoreShardCountWeight dynamicproperties.Float64PropertyFnWithNamespaceFilters
+
 		LoadBalancingNaive  LoadBalancingNaiveConfig
 		LoadBalancingGreedy LoadBalancingGreedyConfig
 	}
@@ -50,10 +86,27 @@ type (
 	LoadBalancingGreedyConfig struct {
 		PerShardCooldown          dynamicproperties.DurationPropertyFnWithNamespaceFilters
 		LoadSmoothingTimeConstant dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		LoadHighWatermarkDecay    dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		StatisticsFlushInterval   dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		StatisticsFlushJitter     dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		LoadWindow                dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		LoadHistoryRetention      dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		LoadHistoryResolution     dynamicproperties.DurationPropertyFnWithNamespaceFilters
 		MoveBudgetProportion      dynamicproperties.Float64PropertyFnWithNamespaceFilters
 		HysteresisUpperBand       dynamicproperties.Float64PropertyFnWithNamespaceFilters
 		HysteresisLowerBand       dynamicproperties.Float64PropertyFnWithNamespaceFilters
 		SevereImbalanceRatio      dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ShardLoadFloor            dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		ShardOverhead             dynamicproperties.Float64PropertyFnWithNamespaceFilters
+		LoadDimensionWeights      dynamicproperties.MapPropertyFnWithNamespaceFilters
+		LoadAggregationMode       dynamicproperties.StringPropertyFnWithNamespaceFilters
+		ShedSelectionMode         dynamicproperties.StringPropertyFnWithNamespaceFilters
+		PlacementTieBreak         dynamicproperties.StringPropertyFnWithNamespaceFilters
+		RebalanceStrategy         dynamicproperties.StringPropertyFnWithNamespaceFilters
+		MoveLoadReset             dynamicproperties.StringPropertyFnWithNamespaceFilters
+		MoveAgingWindow           dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		ColdStartGracePeriod      dynamicproperties.DurationPropertyFnWithNamespaceFilters
+		ExcludeDrainingShardLoad  dynamicproperties.BoolPropertyFnWithNamespaceFilters
 	}
 
 	StaticConfig struct {
@@ -120,6 +173,9 @@ const (
 	NamespaceTypeEphemeral = "ephemeral"
 )
 
+// DefaultHeartbeatTTL is the heartbeat TTL used when LeaderProcess.HeartbeatTTL is not set.
+const DefaultHeartbeatTTL = 10 * time.Second
+
 const (
 	MigrationModeINVALID          = "invalid"
 	MigrationModeLOCALPASSTHROUGH = "local_pass"
@@ -140,16 +196,67 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		MigrationMode:     dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorMigrationMode),
 		MaxEtcdTxnOps:     dc.GetIntProperty(dynamicproperties.ShardDistributorMaxEtcdTxnOps),
 
+		StoreRetryMaxAttempts:     dc.GetIntProperty(dynamicproperties.ShardDistributorStoreRetryMaxAttempts),
+		StoreRetryInitialInterval: dc.GetDurationProperty(dynamicproperties.ShardDistributorStoreRetryInitialInterval),
+
+		EmptyExecutorDonorSelection: dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorEmptyExecutorDonorSelection),
+		ShardLeaseDuration:          dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardLeaseDuration),
+		RejectUnknownShardReports:   dc.GetBoolPropertyFilteredByNamespace(dynamicproperties.ShardDistributorRejectUnknownShardReports),
+		RebalancePaused:             dc.GetBoolPropertyFilteredByNamespace(dynamicproperties.ShardDistributorRebalancePaused),
+		StaticHashFallback:          dc.GetBoolPropertyFilteredByNamespace(dynamicproperties.ShardDistributorStaticHashFallback),
+		ControlledHandoff:           dc.GetBoolPropertyFilteredByNamespace(dynamicproperties.ShardDistributorControlledHandoff),
+
+		ShardGroups:           dc.GetMapPropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardGroups),
+		MaxGroupShardsPerZone: dc.GetIntPropertyFilteredByNamespace(dynamicproperties.ShardDistributorMaxGroupShardsPerZone),
+		MinActiveExecutors:    dc.GetIntPropertyFilteredByNamespace(dynamicproperties.ShardDistributorMinActiveExecutors),
+		AssignmentRampCap:     dc.GetIntPropertyFilteredByNamespace(dynamicproperties.ShardDistributorAssignmentRampCap),
+		MaxMovesPerCycle:      dc.GetIntPropertyFilteredByNamespace(dynamicproperties.ShardDistributorMaxMovesPerCycle),
+		RebalanceApplyWorkers: dc.GetIntPropertyFilteredByNamespace(dynamicproperties.ShardDistributorRebalanceApplyWorkers),
+		PinnedShards:          dc.GetMapPropertyFilteredByNamespace(dynamicproperties.ShardDistributorPinnedShards),
+		ShardLabelSelectors:   dc.GetMapPropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardLabelSelectors),
+		ShardSLATiers:         dc.GetMapPropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardSLATiers),
+
+		ExecutorLoadCapacity:       dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorExecutorLoadCapacity),
+		ConsolidationLoadThreshold: dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorConsolidationLoadThreshold),
+		ShardOvercommitFactor:      dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardOvercommitFactor),
+
+		RebalanceInterval:          dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorRebalanceInterval),
+		RebalanceJitterCoefficient: dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorRebalanceJitterCoefficient),
+		MaxShardLoad:               dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorMaxShardLoad),
+		ShardLoadBudget:            dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorShardLoadBudget),
+		ZombieShardAge:             dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorZombieShardAge),
+		StabilityWindow:            dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorStabilityWindow),
+
+		HealthScoreLoadWeight:       dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorHealthScoreLoadWeight),
+		HealthScoreShardCountWeight: dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorHealthScoreShardCountWeight),
+
 		LoadBalancingNaive: LoadBalancingNaiveConfig{
 			MaxDeviation: dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingNaiveMaxDeviation),
 		},
 		LoadBalancingGreedy: LoadBalancingGreedyConfig{
 			PerShardCooldown:          dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyPerShardCooldown),
 			LoadSmoothingTimeConstant: dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadSmoothingTimeConstant),
+			LoadHighWatermarkDecay:    dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadHighWatermarkDecay),
+			StatisticsFlushInterval:   dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyStatisticsFlushInterval),
+			StatisticsFlushJitter:     dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyStatisticsFlushJitterCoefficient),
+			LoadWindow:                dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadWindow),
+			LoadHistoryRetention:      dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadHistoryRetention),
+			LoadHistoryResolution:     dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadHistoryResolution),
 			MoveBudgetProportion:      dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyMoveBudgetProportion),
 			HysteresisUpperBand:       dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyHysteresisUpperBand),
 			HysteresisLowerBand:       dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyHysteresisLowerBand),
 			SevereImbalanceRatio:      dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedySevereImbalanceRatio),
+			ShardLoadFloor:            dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyShardLoadFloor),
+			ShardOverhead:             dc.GetFloat64PropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyShardOverhead),
+			LoadDimensionWeights:      dc.GetMapPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadDimensionWeights),
+			LoadAggregationMode:       dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadAggregationMode),
+			ShedSelectionMode:         dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyShedSelectionMode),
+			PlacementTieBreak:         dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyPlacementTieBreak),
+			RebalanceStrategy:         dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyRebalanceStrategy),
+			MoveLoadReset:             dc.GetStringPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyMoveLoadReset),
+			MoveAgingWindow:           dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyMoveAgingWindow),
+			ColdStartGracePeriod:      dc.GetDurationPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyColdStartGracePeriod),
+			ExcludeDrainingShardLoad:  dc.GetBoolPropertyFilteredByNamespace(dynamicproperties.ShardDistributorLoadBalancingGreedyExcludeDrainingShardLoad),
 		},
 	}
 }
@@ -165,16 +272,18 @@ func (c *Config) GetMigrationMode(namespace string) types.MigrationMode {
 }
 
 const (
-	LoadBalancingModeINVALID = "invalid"
-	LoadBalancingModeNAIVE   = "naive"
-	LoadBalancingModeGREEDY  = "greedy"
+	LoadBalancingModeINVALID        = "invalid"
+	LoadBalancingModeNAIVE          = "naive"
+	LoadBalancingModeGREEDY         = "greedy"
+	LoadBalancingModeCONSISTENTHASH = "consistent-hash"
 )
 
 // LoadBalancingMode maps string migration mode values to types.LoadBalancingMode
 var LoadBalancingMode = map[string]types.LoadBalancingMode{
-	LoadBalancingModeINVALID: types.LoadBalancingModeINVALID,
-	LoadBalancingModeNAIVE:   types.LoadBalancingModeNAIVE,
-	LoadBalancingModeGREEDY:  types.LoadBalancingModeGREEDY,
+	LoadBalancingModeINVALID:        types.LoadBalancingModeINVALID,
+	LoadBalancingModeNAIVE:          types.LoadBalancingModeNAIVE,
+	LoadBalancingModeGREEDY:         types.LoadBalancingModeGREEDY,
+	LoadBalancingModeCONSISTENTHASH: types.LoadBalancingModeCONSISTENTHASH,
 }
 
 // GetLoadBalancingMode gets the load balancing mode for a given namespace
@@ -188,6 +297,501 @@ func (c *Config) GetLoadBalancingMode(namespace string) types.LoadBalancingMode
 	return mode
 }
 
+// GetAssignmentMode gets the load balancing mode the leader assigns the shards of a given namespace with.
+// While the static hash fallback is set it is types.LoadBalancingModeCONSISTENTHASH, so no load is used,
+// otherwise it is the load balancing mode.
+func (c *Config) GetAssignmentMode(namespace string) types.LoadBalancingMode {
+	if c.StaticHashFallback != nil && c.StaticHashFallback(namespace) {
+		return types.LoadBalancingModeCONSISTENTHASH
+	}
+	return c.GetLoadBalancingMode(namespace)
+}
+
+const (
+	DonorSelectionHeaviestFirst   = "heaviest-first"
+	DonorSelectionLightestFirst   = "lightest-first"
+	DonorSelectionClosestToTarget = "closest-to-target"
+)
+
+// GetEmptyExecutorDonorSelection gets the strategy used to pick shards from donor executors
+// when filling empty executors. Unset or unknown values fall back to DonorSelectionHeaviestFirst.
+func (c *Config) GetEmptyExecutorDonorSelection(namespace string) string {
+	if c == nil || c.EmptyExecutorDonorSelection == nil {
+		return DonorSelectionHeaviestFirst
+	}
+
+	switch strategy := c.EmptyExecutorDonorSelection(namespace); strategy {
+	case DonorSelectionHeaviestFirst, DonorSelectionLightestFirst, DonorSelectionClosestToTarget:
+		return strategy
+	default:
+		return DonorSelectionHeaviestFirst
+	}
+}
+
+const (
+	LoadAggregationLast = "last"
+	LoadAggregationMean = "mean"
+	LoadAggregationMax  = "max"
+)
+
+// GetLoadAggregationMode gets how the loads reported for a shard within one statistics flush interval
+// are combined before smoothing. Unset or unknown values fall back to LoadAggregationLast.
+func (c *Config) GetLoadAggregationMode(namespace string) string {
+	if c == nil || c.LoadBalancingGreedy.LoadAggregationMode == nil {
+		return LoadAggregationLast
+	}
+
+	switch mode := c.LoadBalancingGreedy.LoadAggregationMode(namespace); mode {
+	case LoadAggregationLast, LoadAggregationMean, LoadAggregationMax:
+		return mode
+	default:
+		return LoadAggregationLast
+	}
+}
+
+const (
+	ShedSelectionHeaviest       = "heaviest"
+	ShedSelectionWeightedRandom = "weighted-random"
+	// ShedSelectionLeastHeadroom sheds the heaviest shards, relieving the overloaded executors that report the
+	// least headroom first, since an executor close to its limit is more urgent to relieve than a loaded one
+	// that still has room.
+	ShedSelectionLeastHeadroom = "least-headroom"
+)
+
+// GetShedSelectionMode gets how the greedy load balancer picks the shard to shed from an overloaded executor.
+// Unset or unknown values fall back to ShedSelectionHeaviest.
+func (c *Config) GetShedSelectionMode(namespace string) string {
+	if c == nil || c.LoadBalancingGreedy.ShedSelectionMode == nil {
+		return ShedSelectionHeaviest
+	}
+
+	switch mode := c.LoadBalancingGreedy.ShedSelectionMode(namespace); mode {
+	case ShedSelectionHeaviest, ShedSelectionWeightedRandom, ShedSelectionLeastHeadroom:
+		return mode
+	default:
+		return ShedSelectionHeaviest
+	}
+}
+
+const (
+	PlacementTieBreakShardCount          = "shard-count"
+	PlacementTieBreakRandom              = "random"
+	PlacementTieBreakMostRecentlyEmptied = "most-recently-emptied"
+)
+
+// GetPlacementTieBreak gets how the greedy load balancer chooses between executors with the same load when
+// placing shards. Unset or unknown values fall back to PlacementTieBreakShardCount.
+func (c *Config) GetPlacementTieBreak(namespace string) string {
+	if c == nil || c.LoadBalancingGreedy.PlacementTieBreak == nil {
+		return PlacementTieBreakShardCount
+	}
+
+	switch strategy := c.LoadBalancingGreedy.PlacementTieBreak(namespace); strategy {
+	case PlacementTieBreakShardCount, PlacementTieBreakRandom, PlacementTieBreakMostRecentlyEmptied:
+		return strategy
+	default:
+		return PlacementTieBreakShardCount
+	}
+}
+
+const (
+	RebalanceStrategyIncremental = "incremental"
+	// RebalanceStrategyMinMovement plans the fewest moves that bring every executor within the hysteresis
+	// bands, keeping as many shards in place as possible instead of moving towards the best balance.
+	RebalanceStrategyMinMovement = "min-movement"
+)
+
+// GetRebalanceStrategy gets how the greedy load balancer plans the moves of a rebalance.
+// Unset or unknown values fall back to RebalanceStrategyIncremental.
+func (c *Config) GetRebalanceStrategy(namespace string) string {
+	if c == nil || c.LoadBalancingGreedy.RebalanceStrategy == nil {
+		return RebalanceStrategyIncremental
+	}
+
+	switch strategy := c.LoadBalancingGreedy.RebalanceStrategy(namespace); strategy {
+	case RebalanceStrategyIncremental, RebalanceStrategyMinMovement:
+		return strategy
+	default:
+		return RebalanceStrategyIncremental
+	}
+}
+
+const (
+	MoveLoadResetNone    = "none"
+	MoveLoadResetPartial = "partial"
+	MoveLoadResetFull    = "full"
+)
+
+// GetMoveLoadReset gets how much of the smoothed load of a shard is reset when it moves to another executor.
+// Unset or unknown values fall back to MoveLoadResetNone.
+func (c *Config) GetMoveLoadReset(namespace string) string {
+	if c == nil || c.LoadBalancingGreedy.MoveLoadReset == nil {
+		return MoveLoadResetNone
+	}
+
+	switch reset := c.LoadBalancingGreedy.MoveLoadReset(namespace); reset {
+	case MoveLoadResetNone, MoveLoadResetPartial, MoveLoadResetFull:
+		return reset
+	default:
+		return MoveLoadResetNone
+	}
+}
+
+// GetShardLeaseExpiry returns when a shard lease granted at now expires for a given namespace.
+// It returns the zero time if leases are disabled.
+func (c *Config) GetShardLeaseExpiry(namespace string, now time.Time) time.Time {
+	if c == nil || c.ShardLeaseDuration == nil {
+		return time.Time{}
+	}
+
+	leaseDuration := c.ShardLeaseDuration(namespace)
+	if leaseDuration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(leaseDuration)
+}
+
+// ShouldRejectUnknownShardReports reports whether heartbeats reporting shards unknown to the
+// namespace state are rejected for a given namespace.
+func (c *Config) ShouldRejectUnknownShardReports(namespace string) bool {
+	if c == nil || c.RejectUnknownShardReports == nil {
+		return false
+	}
+	return c.RejectUnknownShardReports(namespace)
+}
+
+// IsRebalancePaused reports whether the leader should leave the shard assignments of the namespace untouched.
+func (c *Config) IsRebalancePaused(namespace string) bool {
+	if c == nil || c.RebalancePaused == nil {
+		return false
+	}
+	return c.RebalancePaused(namespace)
+}
+
+// IsControlledHandoffEnabled reports whether moved shards of the namespace are handed over in steps.
+func (c *Config) IsControlledHandoffEnabled(namespace string) bool {
+	if c == nil || c.ControlledHandoff == nil {
+		return false
+	}
+	return c.ControlledHandoff(namespace)
+}
+
+// GetZoneSpread returns the group of each grouped shard and the maximum number of shards of one group
+// that may be assigned to a single failure zone. It returns no groups when the limit is disabled.
+// Group names that are not strings are ignored.
+func (c *Config) GetZoneSpread(namespace string) (shardGroups map[string]string, maxGroupShardsPerZone int) {
+	if c == nil || c.ShardGroups == nil || c.MaxGroupShardsPerZone == nil {
+		return nil, 0
+	}
+
+	maxGroupShardsPerZone = c.MaxGroupShardsPerZone(namespace)
+	if maxGroupShardsPerZone <= 0 {
+		return nil, 0
+	}
+
+	shardGroups = make(map[string]string)
+	for shardID, value := range c.ShardGroups(namespace) {
+		if group, ok := value.(string); ok && group != "" {
+			shardGroups[shardID] = group
+		}
+	}
+	return shardGroups, maxGroupShardsPerZone
+}
+
+// GetMinActiveExecutors returns the minimum number of executors that must keep owning shards, or 0 when the guard is disabled.
+func (c *Config) GetMinActiveExecutors(namespace string) int {
+	if c == nil || c.MinActiveExecutors == nil {
+		return 0
+	}
+	return max(0, c.MinActiveExecutors(namespace))
+}
+
+// GetAssignmentRampCap returns the maximum number of shards one executor newly receives per rebalance cycle,
+// or 0 when the ramp is disabled.
+func (c *Config) GetAssignmentRampCap(namespace string) int {
+	if c == nil || c.AssignmentRampCap == nil {
+		return 0
+	}
+	return max(0, c.AssignmentRampCap(namespace))
+}
+
+// GetMaxMovesPerCycle returns the maximum number of shards moved between executors per rebalance cycle,
+// or 0 when moves are not capped.
+func (c *Config) GetMaxMovesPerCycle(namespace string) int {
+	if c == nil || c.MaxMovesPerCycle == nil {
+		return 0
+	}
+	return max(0, c.MaxMovesPerCycle(namespace))
+}
+
+// GetRebalanceApplyWorkers returns the number of concurrent store writes used to apply the moves of a rebalance
+// cycle that only load balances, or 0 when the whole plan is written in a single transaction.
+func (c *Config) GetRebalanceApplyWorkers(namespace string) int {
+	if c == nil || c.RebalanceApplyWorkers == nil {
+		return 0
+	}
+	return max(0, c.RebalanceApplyWorkers(namespace))
+}
+
+// GetPinnedShards returns the shards that must not be moved automatically, mapped to the executor they are
+// required on. An empty executor ID pins the shard to wherever it is. Values that are not strings are ignored.
+func (c *Config) GetPinnedShards(namespace string) map[string]string {
+	if c == nil || c.PinnedShards == nil {
+		return nil
+	}
+
+	pinnedShards := make(map[string]string)
+	for shardID, value := range c.PinnedShards(namespace) {
+		if executorID, ok := value.(string); ok {
+			pinnedShards[shardID] = executorID
+		}
+	}
+	return pinnedShards
+}
+
+// GetShardLabelSelectors returns the executor labels each shard requires. Selectors that are not strings
+// of comma separated key=value pairs are ignored.
+func (c *Config) GetShardLabelSelectors(namespace string) map[string]map[string]string {
+	if c == nil || c.ShardLabelSelectors == nil {
+		return nil
+	}
+
+	selectors := make(map[string]map[string]string)
+	for shardID, value := range c.ShardLabelSelectors(namespace) {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if selector, ok := parseLabelSelector(raw); ok {
+			selectors[shardID] = selector
+		}
+	}
+	return selectors
+}
+
+// SLA tiers of shards, see GetShardSLATiers.
+const (
+	SLATierStrict     = "strict"
+	SLATierBestEffort = "best-effort"
+)
+
+// GetShardSLATiers returns the SLA tier of the shards that have one. Values other than SLATierStrict and
+// SLATierBestEffort are ignored, shards without a tier are best-effort.
+func (c *Config) GetShardSLATiers(namespace string) map[string]string {
+	if c == nil || c.ShardSLATiers == nil {
+		return nil
+	}
+
+	tiers := make(map[string]string)
+	for shardID, value := range c.ShardSLATiers(namespace) {
+		if tier, ok := value.(string); ok && (tier == SLATierStrict || tier == SLATierBestEffort) {
+			tiers[shardID] = tier
+		}
+	}
+	return tiers
+}
+
+func parseLabelSelector(raw string) (map[string]string, bool) {
+	selector := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, false
+		}
+		selector[key] = value
+	}
+	return selector, true
+}
+
+// GetConsolidationSettings returns the per-executor load capacity and the fraction of the namespace
+// capacity below which shards are consolidated. ok is false when consolidation is disabled.
+func (c *Config) GetConsolidationSettings(namespace string) (executorCapacity, threshold float64, ok bool) {
+	if c == nil || c.ExecutorLoadCapacity == nil || c.ConsolidationLoadThreshold == nil {
+		return 0, 0, false
+	}
+
+	executorCapacity = c.ExecutorLoadCapacity(namespace)
+	threshold = c.ConsolidationLoadThreshold(namespace)
+	if executorCapacity <= 0 || threshold <= 0 {
+		return 0, 0, false
+	}
+	return executorCapacity, threshold, true
+}
+
+// GetExecutorLoadCapacity returns the amount of shard load a single executor can serve.
+// It returns 0, meaning unknown capacity, when not configured.
+func (c *Config) GetExecutorLoadCapacity(namespace string) float64 {
+	if c == nil || c.ExecutorLoadCapacity == nil {
+		return 0
+	}
+	return math.Max(c.ExecutorLoadCapacity(namespace), 0)
+}
+
+// GetExecutorShardCap returns the maximum number of shards one executor should hold when placing shards,
+// ceil(totalShards / activeExecutors * overcommitFactor), so the cap follows executors joining and leaving.
+// It returns 0, meaning no cap, when the overcommit factor is not set or there are no active executors.
+func (c *Config) GetExecutorShardCap(namespace string, totalShards, activeExecutors int) int {
+	if c == nil || c.ShardOvercommitFactor == nil || activeExecutors <= 0 {
+		return 0
+	}
+
+	overcommitFactor := c.ShardOvercommitFactor(namespace)
+	if overcommitFactor <= 0 {
+		return 0
+	}
+	// A factor below 1 would leave the executors unable to hold every shard.
+	overcommitFactor = math.Max(overcommitFactor, 1)
+	return int(math.Ceil(float64(totalShards) / float64(activeExecutors) * overcommitFactor))
+}
+
+// GetRebalanceSchedule returns the interval between periodic rebalance cycles of a namespace and the jitter
+// coefficient applied to it. A zero interval means the caller should use its static process period.
+// The jitter coefficient is clamped to [0, 1].
+func (c *Config) GetRebalanceSchedule(namespace string) (interval time.Duration, jitterCoefficient float64) {
+	if c == nil {
+		return 0, 0
+	}
+	if c.RebalanceInterval != nil {
+		interval = max(c.RebalanceInterval(namespace), 0)
+	}
+	if c.RebalanceJitterCoefficient != nil {
+		jitterCoefficient = math.Min(math.Max(c.RebalanceJitterCoefficient(namespace), 0), 1)
+	}
+	return interval, jitterCoefficient
+}
+
+// GetMaxShardLoad returns the highest load accepted from a shard report.
+// It returns 0, meaning reports are not clamped, when not configured.
+func (c *Config) GetMaxShardLoad(namespace string) float64 {
+	if c == nil || c.MaxShardLoad == nil {
+		return 0
+	}
+	return math.Max(c.MaxShardLoad(namespace), 0)
+}
+
+// GetHealthScoreWeights returns the weights of the load and the shard count coefficients of variation in
+// the assignment health score. Negative weights are treated as 0, unconfigured weights default to 1.
+func (c *Config) GetHealthScoreWeights(namespace string) (loadWeight, shardCountWeight float64) {
+	loadWeight, shardCountWeight = 1, 1
+	if c == nil {
+		return loadWeight, shardCountWeight
+	}
+	if c.HealthScoreLoadWeight != nil {
+		loadWeight = math.Max(c.HealthScoreLoadWeight(namespace), 0)
+	}
+	if c.HealthScoreShardCountWeight != nil {
+		shardCountWeight = math.Max(c.HealthScoreShardCountWeight(namespace), 0)
+	}
+	return loadWeight, shardCountWeight
+}
+
+// GetZombieShardAge returns how long an assigned shard may go without a load report before it is
+// considered a zombie shard. It returns 0, meaning the detection is disabled, when not configured.
+func (c *Config) GetZombieShardAge(namespace string) time.Duration {
+	if c == nil || c.ZombieShardAge == nil {
+		return 0
+	}
+	return max(c.ZombieShardAge(namespace), 0)
+}
+
+// GetShardLoadBudget returns the highest smoothed load a single shard is expected to carry.
+// It returns 0, meaning hot shards are not reported, when not configured.
+func (c *Config) GetShardLoadBudget(namespace string) float64 {
+	if c == nil || c.ShardLoadBudget == nil {
+		return 0
+	}
+	return math.Max(c.ShardLoadBudget(namespace), 0)
+}
+
+// GetStabilityWindow returns the sliding window over which assignment stability is reported.
+// It returns 0, meaning the reporting is disabled, when not configured.
+func (c *Config) GetStabilityWindow(namespace string) time.Duration {
+	if c == nil || c.StabilityWindow == nil {
+		return 0
+	}
+	return max(c.StabilityWindow(namespace), 0)
+}
+
+// GetShardLoadFloor returns the minimum load assumed for a shard when placing shards.
+// It returns 0, meaning no floor, when not configured.
+func (c *Config) GetShardLoadFloor(namespace string) float64 {
+	if c == nil || c.LoadBalancingGreedy.ShardLoadFloor == nil {
+		return 0
+	}
+	return math.Max(c.LoadBalancingGreedy.ShardLoadFloor(namespace), 0)
+}
+
+// GetShardOverhead returns the fixed load added to every shard when placing and rebalancing shards.
+// It returns 0, meaning no overhead, when not configured.
+func (c *Config) GetShardOverhead(namespace string) float64 {
+	if c == nil || c.LoadBalancingGreedy.ShardOverhead == nil {
+		return 0
+	}
+	return math.Max(c.LoadBalancingGreedy.ShardOverhead(namespace), 0)
+}
+
+// GetPerShardCooldown gets the minimum time between moves of the same shard for a given namespace.
+func (c *Config) GetPerShardCooldown(namespace string) time.Duration {
+	if c == nil || c.LoadBalancingGreedy.PerShardCooldown == nil {
+		return 0
+	}
+	return c.LoadBalancingGreedy.PerShardCooldown(namespace)
+}
+
+// GetColdStartGracePeriod returns how long after its first heartbeat the load of an executor is provisional,
+// or zero when there is no grace period.
+func (c *Config) GetColdStartGracePeriod(namespace string) time.Duration {
+	if c == nil || c.LoadBalancingGreedy.ColdStartGracePeriod == nil {
+		return 0
+	}
+	return max(c.LoadBalancingGreedy.ColdStartGracePeriod(namespace), 0)
+}
+
+// ShouldExcludeDrainingShardLoad reports whether the load reported for draining shards is kept out of the
+// smoothed shard load. It defaults to true.
+func (c *Config) ShouldExcludeDrainingShardLoad(namespace string) bool {
+	if c == nil || c.LoadBalancingGreedy.ExcludeDrainingShardLoad == nil {
+		return true
+	}
+	return c.LoadBalancingGreedy.ExcludeDrainingShardLoad(namespace)
+}
+
+// GetLoadHistorySettings returns the interval the load history of shards is downsampled to and how long it
+// is kept. ok is false when the load history is disabled. A resolution that is not positive falls back to a minute.
+func (c *Config) GetLoadHistorySettings(namespace string) (resolution, retention time.Duration, ok bool) {
+	if c == nil || c.LoadBalancingGreedy.LoadHistoryRetention == nil {
+		return 0, 0, false
+	}
+	retention = c.LoadBalancingGreedy.LoadHistoryRetention(namespace)
+	if retention <= 0 {
+		return 0, 0, false
+	}
+	resolution = time.Minute
+	if c.LoadBalancingGreedy.LoadHistoryResolution != nil && c.LoadBalancingGreedy.LoadHistoryResolution(namespace) > 0 {
+		resolution = c.LoadBalancingGreedy.LoadHistoryResolution(namespace)
+	}
+	return resolution, retention, true
+}
+
+// GetLoadDimensionWeights gets the weight of each shard load dimension for a given namespace.
+// Values that are not numbers are ignored.
+func (c *Config) GetLoadDimensionWeights(namespace string) map[string]float64 {
+	if c == nil || c.LoadBalancingGreedy.LoadDimensionWeights == nil {
+		return nil
+	}
+
+	weights := make(map[string]float64)
+	for dimension, value := range c.LoadBalancingGreedy.LoadDimensionWeights(namespace) {
+		switch weight := value.(type) {
+		case float64:
+			weights[dimension] = weight
+		case int:
+			weights[dimension] = float64(weight)
+		}
+	}
+	return weights
+}
+
 func (s *ShardDistribution) GetMigrationMode(namespace string) types.MigrationMode {
 	for _, ns := range s.Namespaces {
 		if ns.Name == namespace {
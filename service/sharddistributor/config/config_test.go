@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,13 +21,58 @@ func TestNewDynamicConfigCreatesInstanceWithProperties(t *testing.T) {
 	assert.NotNil(t, config)
 	assert.NotNil(t, config.LoadBalancingMode)
 	assert.NotNil(t, config.MigrationMode)
+	assert.NotNil(t, config.EmptyExecutorDonorSelection)
+	assert.NotNil(t, config.ShardLeaseDuration)
+	assert.NotNil(t, config.RejectUnknownShardReports)
+	assert.NotNil(t, config.RebalancePaused)
+	assert.NotNil(t, config.StaticHashFallback)
+	assert.NotNil(t, config.ControlledHandoff)
+	assert.NotNil(t, config.ShardGroups)
+	assert.NotNil(t, config.PinnedShards)
+	assert.NotNil(t, config.ShardLabelSelectors)
+	assert.NotNil(t, config.ShardSLATiers)
+	assert.NotNil(t, config.MaxGroupShardsPerZone)
+	assert.NotNil(t, config.MinActiveExecutors)
+	assert.NotNil(t, config.AssignmentRampCap)
+	assert.NotNil(t, config.MaxMovesPerCycle)
+	assert.NotNil(t, config.RebalanceApplyWorkers)
+	assert.NotNil(t, config.ExecutorLoadCapacity)
+	assert.NotNil(t, config.ConsolidationLoadThreshold)
+	assert.NotNil(t, config.ShardOvercommitFactor)
+	assert.NotNil(t, config.RebalanceInterval)
+	assert.NotNil(t, config.RebalanceJitterCoefficient)
+	assert.NotNil(t, config.MaxShardLoad)
+	assert.NotNil(t, config.ShardLoadBudget)
+	assert.NotNil(t, config.HealthScoreLoadWeight)
+	assert.NotNil(t, config.HealthScoreShardCountWeight)
+	assert.NotNil(t, config.ZombieShardAge)
+	assert.NotNil(t, config.StabilityWindow)
+	assert.NotNil(t, config.StoreRetryMaxAttempts)
+	assert.NotNil(t, config.StoreRetryInitialInterval)
 	assert.NotNil(t, config.LoadBalancingNaive.MaxDeviation)
 	assert.NotNil(t, config.LoadBalancingGreedy.PerShardCooldown)
 	assert.NotNil(t, config.LoadBalancingGreedy.LoadSmoothingTimeConstant)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadHighWatermarkDecay)
+	assert.NotNil(t, config.LoadBalancingGreedy.StatisticsFlushInterval)
+	assert.NotNil(t, config.LoadBalancingGreedy.StatisticsFlushJitter)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadWindow)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadHistoryRetention)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadHistoryResolution)
 	assert.NotNil(t, config.LoadBalancingGreedy.MoveBudgetProportion)
 	assert.NotNil(t, config.LoadBalancingGreedy.HysteresisUpperBand)
 	assert.NotNil(t, config.LoadBalancingGreedy.HysteresisLowerBand)
 	assert.NotNil(t, config.LoadBalancingGreedy.SevereImbalanceRatio)
+	assert.NotNil(t, config.LoadBalancingGreedy.ShardLoadFloor)
+	assert.NotNil(t, config.LoadBalancingGreedy.ShardOverhead)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadAggregationMode)
+	assert.NotNil(t, config.LoadBalancingGreedy.ShedSelectionMode)
+	assert.NotNil(t, config.LoadBalancingGreedy.RebalanceStrategy)
+	assert.NotNil(t, config.LoadBalancingGreedy.MoveLoadReset)
+	assert.NotNil(t, config.LoadBalancingGreedy.PlacementTieBreak)
+	assert.NotNil(t, config.LoadBalancingGreedy.MoveAgingWindow)
+	assert.NotNil(t, config.LoadBalancingGreedy.ColdStartGracePeriod)
+	assert.NotNil(t, config.LoadBalancingGreedy.ExcludeDrainingShardLoad)
+	assert.NotNil(t, config.LoadBalancingGreedy.LoadDimensionWeights)
 }
 
 func TestGetMigrationMode(t *testing.T) {
@@ -87,6 +133,11 @@ func TestGetLoadBalancingMode(t *testing.T) {
 			configValue:  "greedy",
 			expectedMode: types.LoadBalancingModeGREEDY,
 		},
+		{
+			name:         "ConsistentHash",
+			configValue:  "consistent-hash",
+			expectedMode: types.LoadBalancingModeCONSISTENTHASH,
+		},
 		{
 			name:         "Invalid",
 			configValue:  "invalid",
@@ -112,3 +163,553 @@ func TestGetLoadBalancingMode(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAssignmentMode(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingMode, "greedy"))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	assert.Equal(t, types.LoadBalancingModeGREEDY, config.GetAssignmentMode("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorStaticHashFallback, true))
+	assert.Equal(t, types.LoadBalancingModeCONSISTENTHASH, config.GetAssignmentMode("test-namespace"))
+	assert.Equal(t, types.LoadBalancingModeGREEDY, config.GetLoadBalancingMode("test-namespace"), "the load balancing mode is kept")
+}
+
+func TestGetEmptyExecutorDonorSelection(t *testing.T) {
+	tests := []struct {
+		name             string
+		configValue      string
+		expectedStrategy string
+	}{
+		{
+			name:             "Heaviest first",
+			configValue:      "heaviest-first",
+			expectedStrategy: DonorSelectionHeaviestFirst,
+		},
+		{
+			name:             "Lightest first",
+			configValue:      "lightest-first",
+			expectedStrategy: DonorSelectionLightestFirst,
+		},
+		{
+			name:             "Closest to target",
+			configValue:      "closest-to-target",
+			expectedStrategy: DonorSelectionClosestToTarget,
+		},
+		{
+			name:             "Unknown falls back to heaviest first",
+			configValue:      "random",
+			expectedStrategy: DonorSelectionHeaviestFirst,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			err := client.UpdateValue(dynamicproperties.ShardDistributorEmptyExecutorDonorSelection, tt.configValue)
+			require.NoError(t, err)
+			dc := dynamicconfig.NewCollection(client, testlogger.New(t))
+			config := NewConfig(dc)
+
+			assert.Equal(t, tt.expectedStrategy, config.GetEmptyExecutorDonorSelection("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to heaviest first", func(t *testing.T) {
+		assert.Equal(t, DonorSelectionHeaviestFirst, (&Config{}).GetEmptyExecutorDonorSelection("test-namespace"))
+	})
+}
+
+func TestGetLoadAggregationMode(t *testing.T) {
+	tests := []struct {
+		configValue  string
+		expectedMode string
+	}{
+		{configValue: "last", expectedMode: LoadAggregationLast},
+		{configValue: "mean", expectedMode: LoadAggregationMean},
+		{configValue: "max", expectedMode: LoadAggregationMax},
+		{configValue: "median", expectedMode: LoadAggregationLast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadAggregationMode, tt.configValue))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedMode, config.GetLoadAggregationMode("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to last", func(t *testing.T) {
+		assert.Equal(t, LoadAggregationLast, (&Config{}).GetLoadAggregationMode("test-namespace"))
+	})
+}
+
+func TestGetShedSelectionMode(t *testing.T) {
+	tests := []struct {
+		configValue  string
+		expectedMode string
+	}{
+		{configValue: "heaviest", expectedMode: ShedSelectionHeaviest},
+		{configValue: "weighted-random", expectedMode: ShedSelectionWeightedRandom},
+		{configValue: "least-headroom", expectedMode: ShedSelectionLeastHeadroom},
+		{configValue: "random", expectedMode: ShedSelectionHeaviest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyShedSelectionMode, tt.configValue))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedMode, config.GetShedSelectionMode("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to heaviest", func(t *testing.T) {
+		assert.Equal(t, ShedSelectionHeaviest, (&Config{}).GetShedSelectionMode("test-namespace"))
+	})
+}
+
+func TestGetRebalanceStrategy(t *testing.T) {
+	tests := []struct {
+		configValue      string
+		expectedStrategy string
+	}{
+		{configValue: "incremental", expectedStrategy: RebalanceStrategyIncremental},
+		{configValue: "min-movement", expectedStrategy: RebalanceStrategyMinMovement},
+		{configValue: "optimal", expectedStrategy: RebalanceStrategyIncremental},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyRebalanceStrategy, tt.configValue))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedStrategy, config.GetRebalanceStrategy("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to incremental", func(t *testing.T) {
+		assert.Equal(t, RebalanceStrategyIncremental, (&Config{}).GetRebalanceStrategy("test-namespace"))
+	})
+}
+
+func TestGetMoveLoadReset(t *testing.T) {
+	tests := []struct {
+		configValue   string
+		expectedReset string
+	}{
+		{configValue: "none", expectedReset: MoveLoadResetNone},
+		{configValue: "partial", expectedReset: MoveLoadResetPartial},
+		{configValue: "full", expectedReset: MoveLoadResetFull},
+		{configValue: "half", expectedReset: MoveLoadResetNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyMoveLoadReset, tt.configValue))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedReset, config.GetMoveLoadReset("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to none", func(t *testing.T) {
+		assert.Equal(t, MoveLoadResetNone, (&Config{}).GetMoveLoadReset("test-namespace"))
+	})
+}
+
+func TestGetPlacementTieBreak(t *testing.T) {
+	tests := []struct {
+		configValue      string
+		expectedStrategy string
+	}{
+		{configValue: "shard-count", expectedStrategy: PlacementTieBreakShardCount},
+		{configValue: "random", expectedStrategy: PlacementTieBreakRandom},
+		{configValue: "most-recently-emptied", expectedStrategy: PlacementTieBreakMostRecentlyEmptied},
+		{configValue: "unknown", expectedStrategy: PlacementTieBreakShardCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyPlacementTieBreak, tt.configValue))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedStrategy, config.GetPlacementTieBreak("test-namespace"))
+		})
+	}
+
+	t.Run("Unset function falls back to shard count", func(t *testing.T) {
+		assert.Equal(t, PlacementTieBreakShardCount, (&Config{}).GetPlacementTieBreak("test-namespace"))
+	})
+}
+
+func TestGetLoadDimensionWeights(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	err := client.UpdateValue(dynamicproperties.ShardDistributorLoadDimensionWeights, map[string]interface{}{
+		"cpu":     2.5,
+		"memory":  1,
+		"invalid": "not-a-number",
+	})
+	require.NoError(t, err)
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	assert.Equal(t, map[string]float64{"cpu": 2.5, "memory": 1}, config.GetLoadDimensionWeights("test-namespace"))
+	assert.Nil(t, (&Config{}).GetLoadDimensionWeights("test-namespace"))
+}
+
+func TestGetShardLeaseExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		leaseDuration  time.Duration
+		expectedExpiry time.Time
+	}{
+		{
+			name:           "Lease enabled",
+			leaseDuration:  30 * time.Second,
+			expectedExpiry: now.Add(30 * time.Second),
+		},
+		{
+			name:           "Zero duration disables leases",
+			leaseDuration:  0,
+			expectedExpiry: time.Time{},
+		},
+		{
+			name:           "Negative duration disables leases",
+			leaseDuration:  -time.Second,
+			expectedExpiry: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			err := client.UpdateValue(dynamicproperties.ShardDistributorShardLeaseDuration, tt.leaseDuration)
+			require.NoError(t, err)
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedExpiry, config.GetShardLeaseExpiry("test-namespace", now))
+		})
+	}
+
+	t.Run("Unset function disables leases", func(t *testing.T) {
+		assert.True(t, (&Config{}).GetShardLeaseExpiry("test-namespace", now).IsZero())
+	})
+}
+
+func TestGetConsolidationSettings(t *testing.T) {
+	tests := []struct {
+		name              string
+		executorCapacity  float64
+		threshold         float64
+		expectedCapacity  float64
+		expectedThreshold float64
+		expectedOK        bool
+	}{
+		{
+			name:              "Enabled",
+			executorCapacity:  100,
+			threshold:         0.3,
+			expectedCapacity:  100,
+			expectedThreshold: 0.3,
+			expectedOK:        true,
+		},
+		{
+			name:             "Unknown capacity disables consolidation",
+			executorCapacity: 0,
+			threshold:        0.3,
+		},
+		{
+			name:             "Zero threshold disables consolidation",
+			executorCapacity: 100,
+			threshold:        0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorExecutorLoadCapacity, tt.executorCapacity))
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorConsolidationLoadThreshold, tt.threshold))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			capacity, threshold, ok := config.GetConsolidationSettings("test-namespace")
+			assert.Equal(t, tt.expectedCapacity, capacity)
+			assert.Equal(t, tt.expectedThreshold, threshold)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+
+	t.Run("Unset functions disable consolidation", func(t *testing.T) {
+		_, _, ok := (&Config{}).GetConsolidationSettings("test-namespace")
+		assert.False(t, ok)
+	})
+}
+
+func TestGetExecutorLoadCapacity(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+	assert.Zero(t, config.GetExecutorLoadCapacity("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorExecutorLoadCapacity, 100.0))
+	assert.Equal(t, 100.0, config.GetExecutorLoadCapacity("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorExecutorLoadCapacity, -1.0))
+	assert.Zero(t, config.GetExecutorLoadCapacity("test-namespace"))
+
+	assert.Zero(t, (&Config{}).GetExecutorLoadCapacity("test-namespace"))
+}
+
+func TestGetExecutorShardCap(t *testing.T) {
+	tests := []struct {
+		name             string
+		overcommitFactor float64
+		totalShards      int
+		activeExecutors  int
+		expectedCap      int
+	}{
+		{
+			name:             "Even split",
+			overcommitFactor: 1,
+			totalShards:      10,
+			activeExecutors:  5,
+			expectedCap:      2,
+		},
+		{
+			name:             "Rounds up",
+			overcommitFactor: 1.2,
+			totalShards:      10,
+			activeExecutors:  3,
+			expectedCap:      4,
+		},
+		{
+			name:             "Factor below one is treated as one",
+			overcommitFactor: 0.5,
+			totalShards:      10,
+			activeExecutors:  5,
+			expectedCap:      2,
+		},
+		{
+			name:             "Zero factor disables the cap",
+			overcommitFactor: 0,
+			totalShards:      10,
+			activeExecutors:  5,
+			expectedCap:      0,
+		},
+		{
+			name:             "No active executors",
+			overcommitFactor: 1.5,
+			totalShards:      10,
+			activeExecutors:  0,
+			expectedCap:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorShardOvercommitFactor, tt.overcommitFactor))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			assert.Equal(t, tt.expectedCap, config.GetExecutorShardCap("test-namespace", tt.totalShards, tt.activeExecutors))
+		})
+	}
+
+	t.Run("Adding an executor lowers the cap", func(t *testing.T) {
+		client := dynamicconfig.NewInMemoryClient()
+		require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorShardOvercommitFactor, 1.25))
+		config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+		before := config.GetExecutorShardCap("test-namespace", 100, 4)
+		after := config.GetExecutorShardCap("test-namespace", 100, 5)
+		assert.Equal(t, 32, before)
+		assert.Equal(t, 25, after)
+	})
+
+	t.Run("Unset function disables the cap", func(t *testing.T) {
+		assert.Zero(t, (&Config{}).GetExecutorShardCap("test-namespace", 10, 2))
+	})
+}
+
+func TestGetRebalanceSchedule(t *testing.T) {
+	tests := []struct {
+		name                      string
+		interval                  time.Duration
+		jitterCoefficient         float64
+		expectedInterval          time.Duration
+		expectedJitterCoefficient float64
+	}{
+		{
+			name:                      "Defaults",
+			expectedInterval:          0,
+			expectedJitterCoefficient: 0,
+		},
+		{
+			name:                      "Configured",
+			interval:                  5 * time.Second,
+			jitterCoefficient:         0.2,
+			expectedInterval:          5 * time.Second,
+			expectedJitterCoefficient: 0.2,
+		},
+		{
+			name:                      "Negative values are clamped to zero",
+			interval:                  -time.Second,
+			jitterCoefficient:         -0.5,
+			expectedInterval:          0,
+			expectedJitterCoefficient: 0,
+		},
+		{
+			name:                      "Jitter coefficient is clamped to one",
+			interval:                  time.Second,
+			jitterCoefficient:         3,
+			expectedInterval:          time.Second,
+			expectedJitterCoefficient: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorRebalanceInterval, tt.interval))
+			require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorRebalanceJitterCoefficient, tt.jitterCoefficient))
+			config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+			interval, jitterCoefficient := config.GetRebalanceSchedule("test-namespace")
+			assert.Equal(t, tt.expectedInterval, interval)
+			assert.Equal(t, tt.expectedJitterCoefficient, jitterCoefficient)
+		})
+	}
+
+	t.Run("Nil config", func(t *testing.T) {
+		var config *Config
+		interval, jitterCoefficient := config.GetRebalanceSchedule("test-namespace")
+		assert.Zero(t, interval)
+		assert.Zero(t, jitterCoefficient)
+	})
+}
+
+func TestGetShardLoadFloor(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+	assert.Zero(t, config.GetShardLoadFloor("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyShardLoadFloor, 0.5))
+	assert.Equal(t, 0.5, config.GetShardLoadFloor("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyShardLoadFloor, -1.0))
+	assert.Zero(t, config.GetShardLoadFloor("test-namespace"))
+
+	assert.Zero(t, (&Config{}).GetShardLoadFloor("test-namespace"))
+}
+
+func TestGetShardOverhead(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+	assert.Zero(t, config.GetShardOverhead("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyShardOverhead, 0.25))
+	assert.Equal(t, 0.25, config.GetShardOverhead("test-namespace"))
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyShardOverhead, -1.0))
+	assert.Zero(t, config.GetShardOverhead("test-namespace"))
+
+	assert.Zero(t, (&Config{}).GetShardOverhead("test-namespace"))
+}
+
+func TestGetZoneSpread(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorShardGroups, map[string]interface{}{
+		"shard-1": "group-a",
+		"shard-2": "group-a",
+		"shard-3": 7,
+	}))
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMaxGroupShardsPerZone, 1))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	shardGroups, maxPerZone := config.GetZoneSpread("test-namespace")
+	assert.Equal(t, map[string]string{"shard-1": "group-a", "shard-2": "group-a"}, shardGroups)
+	assert.Equal(t, 1, maxPerZone)
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMaxGroupShardsPerZone, 0))
+	shardGroups, maxPerZone = config.GetZoneSpread("test-namespace")
+	assert.Nil(t, shardGroups)
+	assert.Equal(t, 0, maxPerZone)
+
+	shardGroups, _ = (&Config{}).GetZoneSpread("test-namespace")
+	assert.Nil(t, shardGroups)
+}
+
+func TestGetPinnedShards(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorPinnedShards, map[string]interface{}{
+		"shard-1": "executor-a",
+		"shard-2": "",
+		"shard-3": 7,
+	}))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	assert.Equal(t, map[string]string{"shard-1": "executor-a", "shard-2": ""}, config.GetPinnedShards("test-namespace"))
+	assert.Nil(t, (&Config{}).GetPinnedShards("test-namespace"))
+}
+
+func TestGetShardLabelSelectors(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorShardLabelSelectors, map[string]interface{}{
+		"shard-1": "gpu=true",
+		"shard-2": "gpu=true, region=us-east",
+		"shard-3": "gpu",
+		"shard-4": 7,
+	}))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	assert.Equal(t, map[string]map[string]string{
+		"shard-1": {"gpu": "true"},
+		"shard-2": {"gpu": "true", "region": "us-east"},
+	}, config.GetShardLabelSelectors("test-namespace"))
+	assert.Nil(t, (&Config{}).GetShardLabelSelectors("test-namespace"))
+}
+
+func TestGetShardSLATiers(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorShardSLATiers, map[string]interface{}{
+		"shard-1": SLATierStrict,
+		"shard-2": SLATierBestEffort,
+		"shard-3": "gold",
+		"shard-4": 7,
+	}))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	assert.Equal(t, map[string]string{
+		"shard-1": SLATierStrict,
+		"shard-2": SLATierBestEffort,
+	}, config.GetShardSLATiers("test-namespace"))
+	assert.Nil(t, (&Config{}).GetShardSLATiers("test-namespace"))
+}
+
+func TestGetLoadHistorySettings(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+	_, _, ok := config.GetLoadHistorySettings("test-namespace")
+	assert.False(t, ok, "disabled by default")
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadHistoryRetention, 24*time.Hour))
+	resolution, retention, ok := config.GetLoadHistorySettings("test-namespace")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, resolution)
+	assert.Equal(t, 24*time.Hour, retention)
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorLoadBalancingGreedyLoadHistoryResolution, time.Duration(0)))
+	resolution, _, _ = config.GetLoadHistorySettings("test-namespace")
+	assert.Equal(t, time.Minute, resolution)
+
+	_, _, ok = (&Config{}).GetLoadHistorySettings("test-namespace")
+	assert.False(t, ok)
+}
@@ -0,0 +1,95 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import "time"
+
+// NamespaceConfigProvider returns the configuration of a namespace. Implementations read the dynamic
+// configuration on every call, so a change takes effect the next time a snapshot is taken.
+type NamespaceConfigProvider interface {
+	NamespaceConfig(namespace string) NamespaceConfig
+}
+
+// NamespaceConfig is a snapshot of the tunables of a namespace. Callers take one snapshot per rebalance
+// cycle or heartbeat so all decisions of that cycle see the same values.
+type NamespaceConfig struct {
+	// PerShardCooldown is the minimum time between moves of the same shard.
+	PerShardCooldown time.Duration
+	// LoadSmoothingTimeConstant is the time constant of the load smoothing, or zero for the default.
+	LoadSmoothingTimeConstant time.Duration
+
+	// RebalanceInterval is the interval between periodic rebalance cycles, or zero for the process period.
+	RebalanceInterval time.Duration
+	// RebalanceJitterCoefficient is the jitter applied to RebalanceInterval, in [0, 1].
+	RebalanceJitterCoefficient float64
+
+	HysteresisUpperBand  float64
+	HysteresisLowerBand  float64
+	SevereImbalanceRatio float64
+
+	// MaxMovesPerCycle caps the shards moved per rebalance cycle, 0 means no cap.
+	MaxMovesPerCycle int
+	// RebalanceApplyWorkers is the number of concurrent writes applying load balancing moves, 0 writes the plan at once.
+	RebalanceApplyWorkers int
+	// AssignmentRampCap caps the shards one executor newly receives per cycle, 0 means no cap.
+	AssignmentRampCap int
+	// MinActiveExecutors is the number of executors that must keep owning shards, 0 disables the guard.
+	MinActiveExecutors int
+	// MaxShardLoad is the highest load accepted from a shard report, 0 means reports are not clamped.
+	MaxShardLoad float64
+}
+
+var _ NamespaceConfigProvider = (*Config)(nil)
+
+// NamespaceConfig returns a snapshot of the current configuration of the namespace.
+func (c *Config) NamespaceConfig(namespace string) NamespaceConfig {
+	interval, jitterCoefficient := c.GetRebalanceSchedule(namespace)
+	nsConfig := NamespaceConfig{
+		PerShardCooldown:           c.GetPerShardCooldown(namespace),
+		RebalanceInterval:          interval,
+		RebalanceJitterCoefficient: jitterCoefficient,
+		MaxMovesPerCycle:           c.GetMaxMovesPerCycle(namespace),
+		RebalanceApplyWorkers:      c.GetRebalanceApplyWorkers(namespace),
+		AssignmentRampCap:          c.GetAssignmentRampCap(namespace),
+		MinActiveExecutors:         c.GetMinActiveExecutors(namespace),
+		MaxShardLoad:               c.GetMaxShardLoad(namespace),
+	}
+	if c == nil {
+		return nsConfig
+	}
+
+	greedy := c.LoadBalancingGreedy
+	if greedy.LoadSmoothingTimeConstant != nil {
+		nsConfig.LoadSmoothingTimeConstant = max(greedy.LoadSmoothingTimeConstant(namespace), 0)
+	}
+	if greedy.HysteresisUpperBand != nil {
+		nsConfig.HysteresisUpperBand = greedy.HysteresisUpperBand(namespace)
+	}
+	if greedy.HysteresisLowerBand != nil {
+		nsConfig.HysteresisLowerBand = greedy.HysteresisLowerBand(namespace)
+	}
+	if greedy.SevereImbalanceRatio != nil {
+		nsConfig.SevereImbalanceRatio = greedy.SevereImbalanceRatio(namespace)
+	}
+	return nsConfig
+}
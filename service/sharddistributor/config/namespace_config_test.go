@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/dynamicconfig/dynamicproperties"
+	"github.com/uber/cadence/common/log/testlogger"
+)
+
+func TestNamespaceConfig_ReflectsDynamicConfigChanges(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMaxMovesPerCycle, 5))
+	config := NewConfig(dynamicconfig.NewCollection(client, testlogger.New(t)))
+
+	nsConfig := config.NamespaceConfig("test-namespace")
+	assert.Equal(t, 5, nsConfig.MaxMovesPerCycle)
+	assert.Zero(t, nsConfig.RebalanceInterval)
+	assert.Equal(t, 4, nsConfig.RebalanceApplyWorkers)
+
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMaxMovesPerCycle, 2))
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorRebalanceInterval, 30*time.Second))
+	require.NoError(t, client.UpdateValue(dynamicproperties.ShardDistributorMaxShardLoad, 100.0))
+
+	nsConfig = config.NamespaceConfig("test-namespace")
+	assert.Equal(t, 2, nsConfig.MaxMovesPerCycle)
+	assert.Equal(t, 30*time.Second, nsConfig.RebalanceInterval)
+	assert.Equal(t, 100.0, nsConfig.MaxShardLoad)
+}
+
+func TestNamespaceConfig_NilConfig(t *testing.T) {
+	var config *Config
+	assert.Equal(t, NamespaceConfig{}, config.NamespaceConfig("test-namespace"))
+	assert.Equal(t, NamespaceConfig{}, (&Config{}).NamespaceConfig("test-namespace"))
+}
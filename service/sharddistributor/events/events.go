@@ -0,0 +1,44 @@
+package events
+
+import (
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Module provides the no-op executor events sink. Applications that want to react to executor
+// membership changes replace it with fx.Decorate.
+var Module = fx.Module(
+	"sharddistributor-events",
+	fx.Provide(NewNoop),
+)
+
+// ExecutorEvent describes a change in the membership of an executor in a namespace.
+type ExecutorEvent struct {
+	Namespace  string
+	ExecutorID string
+	// Time is when the shard distributor observed the change.
+	Time time.Time
+	// Metadata is the metadata the executor reported with its heartbeat, if known.
+	Metadata map[string]string
+}
+
+// ExecutorEvents receives executor registration and deregistration events.
+// Implementations are called synchronously on the heartbeat and rebalance paths, so they must not block.
+type ExecutorEvents interface {
+	// ExecutorRegistered is called when the first heartbeat of an executor is recorded.
+	ExecutorRegistered(event ExecutorEvent)
+	// ExecutorDeregistered is called when the leader removes a stale executor.
+	ExecutorDeregistered(event ExecutorEvent)
+}
+
+type noop struct{}
+
+// NewNoop returns an ExecutorEvents that drops all events.
+func NewNoop() ExecutorEvents {
+	return noop{}
+}
+
+func (noop) ExecutorRegistered(ExecutorEvent) {}
+
+func (noop) ExecutorDeregistered(ExecutorEvent) {}
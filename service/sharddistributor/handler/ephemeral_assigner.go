@@ -26,7 +26,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
@@ -52,16 +54,23 @@ func (h *handlerImpl) assignEphemeralBatch(ctx context.Context, namespace string
 	if err != nil {
 		return nil, &types.InternalServiceError{Message: fmt.Sprintf("get namespace state: %v", err)}
 	}
+	if state == nil {
+		// No executor has heartbeated for this namespace yet.
+		state = &store.NamespaceState{}
+	}
 
-	placements, err := loadbalancer.PlanInitialPlacement(h.cfg, namespace, state, shardKeys)
+	placements, _, err := loadbalancer.PlanInitialPlacement(h.cfg, namespace, state, shardKeys, h.timeSource.Now())
 	if err != nil {
 		return nil, &types.InternalServiceError{Message: fmt.Sprintf("plan initial placement: %v", err)}
 	}
 
-	mergePlacements(state, placements)
+	mergePlacements(state, placements, h.timeSource.Now().UTC())
 
 	if err := h.storage.AssignShards(ctx, namespace, store.AssignShardsRequest{NewState: state}, store.NopGuard()); err != nil {
 		if errors.Is(err, store.ErrVersionConflict) {
+			h.metricsClient.Scope(metrics.ShardDistributorGetShardOwnerScope).
+				Tagged(metrics.NamespaceTag(namespace)).
+				IncCounter(metrics.ShardDistributorVersionConflicts)
 			// Return the version-conflict sentinel unwrapped so callers can
 			// detect it with errors.Is and decide whether to retry.
 			return nil, fmt.Errorf("assign ephemeral shards: %w", err)
@@ -77,10 +86,11 @@ func (h *handlerImpl) assignEphemeralBatch(ctx context.Context, namespace string
 	return buildResults(namespace, shardKeys, placements, executorOwners), nil
 }
 
-// mergePlacements folds the planned shard→executor placements back into state.
+// mergePlacements folds the planned shard→executor placements back into state,
+// stamping new assignments with assignedAt.
 // The AssignedShards maps are copied to avoid mutating the object returned by
 // GetState.
-func mergePlacements(state *store.NamespaceState, placements []plan.Placement) {
+func mergePlacements(state *store.NamespaceState, placements []plan.Placement, assignedAt time.Time) {
 	if state.ShardAssignments == nil {
 		state.ShardAssignments = make(map[string]store.AssignedState)
 	}
@@ -91,7 +101,7 @@ func mergePlacements(state *store.NamespaceState, placements []plan.Placement) {
 			newShards[k] = v
 		}
 		for _, shardKey := range shardsForExecutor {
-			newShards[shardKey] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+			newShards[shardKey] = &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: assignedAt}
 		}
 		existing.AssignedShards = newShards
 		state.ShardAssignments[executorID] = existing
@@ -123,7 +133,11 @@ func buildResults(namespace string, shardKeys []string, placements []plan.Placem
 	executorByShard := placementsByShard(placements)
 	results := make(map[string]*types.GetShardOwnerResponse, len(shardKeys))
 	for _, shardKey := range shardKeys {
-		executorID := executorByShard[shardKey]
+		executorID, ok := executorByShard[shardKey]
+		if !ok {
+			// No executor can own the shard, e.g. none matches its label selector.
+			continue
+		}
 		owner := executorOwners[executorID]
 		results[shardKey] = &types.GetShardOwnerResponse{
 			Owner:     owner.ExecutorID,
@@ -26,13 +26,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 	"go.uber.org/mock/gomock"
 
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log/testlogger"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -133,9 +138,11 @@ func TestAssignEphemeralBatch(t *testing.T) {
 			mockStorage := store.NewMockStore(ctrl)
 
 			h := &handlerImpl{
-				logger:  testlogger.New(t),
-				storage: mockStorage,
-				cfg:     newTestShardDistributorConfig(config.LoadBalancingModeNAIVE),
+				logger:        testlogger.New(t),
+				timeSource:    clock.NewMockedTimeSource(),
+				storage:       mockStorage,
+				cfg:           newTestShardDistributorConfig(config.LoadBalancingModeNAIVE),
+				metricsClient: metrics.NoopClient,
 			}
 
 			tt.setupMocks(mockStorage)
@@ -166,9 +173,11 @@ func TestAssignEphemeralBatch_InvalidLoadBalancingMode(t *testing.T) {
 
 	mockStorage := store.NewMockStore(ctrl)
 	h := &handlerImpl{
-		logger:  testlogger.New(t),
-		storage: mockStorage,
-		cfg:     newTestShardDistributorConfig("not-a-valid-mode"),
+		logger:        testlogger.New(t),
+		timeSource:    clock.NewMockedTimeSource(),
+		storage:       mockStorage,
+		cfg:           newTestShardDistributorConfig("not-a-valid-mode"),
+		metricsClient: metrics.NoopClient,
 	}
 
 	mockStorage.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(&store.NamespaceState{
@@ -181,3 +190,85 @@ func TestAssignEphemeralBatch_InvalidLoadBalancingMode(t *testing.T) {
 	require.Contains(t, err.Error(), "unsupported load balancing mode")
 	require.Nil(t, results)
 }
+
+// A namespace where no executor heartbeated yet may have no state at all; the
+// assignment fails because there is no executor rather than panicking.
+func TestAssignEphemeralBatch_NilState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStorage := store.NewMockStore(ctrl)
+	h := &handlerImpl{
+		logger:        testlogger.New(t),
+		timeSource:    clock.NewMockedTimeSource(),
+		storage:       mockStorage,
+		cfg:           newTestShardDistributorConfig(config.LoadBalancingModeNAIVE),
+		metricsClient: metrics.NoopClient,
+	}
+
+	mockStorage.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(nil, nil)
+
+	results, err := h.assignEphemeralBatch(context.Background(), _testNamespaceEphemeral, []string{"new-shard-1"})
+	require.ErrorContains(t, err, plan.ErrNoActiveExecutors.Error())
+	require.Nil(t, results)
+}
+
+func TestAssignEphemeralBatch_VersionConflictMetric(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStorage := store.NewMockStore(ctrl)
+
+	testScope := tally.NewTestScope("test", nil)
+	h := &handlerImpl{
+		logger:        testlogger.New(t),
+		timeSource:    clock.NewMockedTimeSource(),
+		storage:       mockStorage,
+		cfg:           newTestShardDistributorConfig(config.LoadBalancingModeNAIVE),
+		metricsClient: metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}),
+	}
+
+	mockStorage.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(&store.NamespaceState{
+		Executors:        map[string]store.HeartbeatState{"owner1": {Status: types.ExecutorStatusACTIVE}},
+		ShardAssignments: map[string]store.AssignedState{"owner1": {AssignedShards: map[string]*types.ShardAssignment{}}},
+	}, nil)
+	mockStorage.EXPECT().AssignShards(gomock.Any(), _testNamespaceEphemeral, gomock.Any(), gomock.Any()).Return(store.ErrVersionConflict)
+
+	_, err := h.assignEphemeralBatch(context.Background(), _testNamespaceEphemeral, []string{"CONCURRENT-SHARD"})
+	require.ErrorIs(t, err, store.ErrVersionConflict)
+
+	counterName := "test.shard_distributor_version_conflicts+namespace=test-ephemeral,operation=GetShardOwner"
+	counters := testScope.Snapshot().Counters()
+	require.Contains(t, counters, counterName)
+	require.Equal(t, int64(1), counters[counterName].Value())
+}
+
+func TestMergePlacements_StampsAssignedAt(t *testing.T) {
+	previouslyAssignedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := previouslyAssignedAt.Add(time.Hour)
+
+	state := &store.NamespaceState{
+		ShardAssignments: map[string]store.AssignedState{
+			"owner1": {AssignedShards: map[string]*types.ShardAssignment{
+				"shard1": {Status: types.AssignmentStatusREADY, AssignedAt: previouslyAssignedAt},
+			}},
+		},
+	}
+
+	mergePlacements(state, []plan.Placement{
+		{ShardID: "shard2", ExecutorID: "owner1"},
+		{ShardID: "shard3", ExecutorID: "owner2"},
+	}, now)
+
+	require.Equal(t, previouslyAssignedAt, state.ShardAssignments["owner1"].AssignedShards["shard1"].AssignedAt)
+	require.Equal(t, now, state.ShardAssignments["owner1"].AssignedShards["shard2"].AssignedAt)
+	require.Equal(t, now, state.ShardAssignments["owner2"].AssignedShards["shard3"].AssignedAt)
+}
+
+func TestBuildResults_SkipsUnplacedShards(t *testing.T) {
+	results := buildResults("ns", []string{"shard1", "shard2"}, []plan.Placement{
+		{ShardID: "shard1", ExecutorID: "owner1"},
+	}, map[string]*store.ShardOwner{
+		"owner1": {ExecutorID: "owner1"},
+	})
+
+	require.Equal(t, map[string]*types.GetShardOwnerResponse{
+		"shard1": {Owner: "owner1", Namespace: "ns"},
+	}, results)
+}
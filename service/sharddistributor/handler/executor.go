@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/uber/cadence/common/clock"
@@ -12,6 +17,8 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
+	"github.com/uber/cadence/service/sharddistributor/reportcodec"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -19,6 +26,9 @@ const (
 	_maxMetadataKeys      = 32
 	_maxMetadataKeyLength = 128
 	_maxMetadataValueSize = 512 * 1024 // 512KB
+
+	// _maxUnknownShardReportLookups bounds the reported shards looked up per heartbeat to find unknown ones.
+	_maxUnknownShardReportLookups = 1000
 )
 
 type executor struct {
@@ -28,6 +38,8 @@ type executor struct {
 	shardDistributionCfg config.ShardDistribution
 	cfg                  *config.Config
 	metricsClient        metrics.Client
+	executorEvents       events.ExecutorEvents
+	inflightHeartbeats   *inflightHeartbeats
 }
 
 func NewExecutorHandler(
@@ -37,6 +49,7 @@ func NewExecutorHandler(
 	shardDistributionCfg config.ShardDistribution,
 	cfg *config.Config,
 	metricsClient metrics.Client,
+	executorEvents events.ExecutorEvents,
 ) Executor {
 	return &executor{
 		logger:               logger,
@@ -45,13 +58,40 @@ func NewExecutorHandler(
 		shardDistributionCfg: shardDistributionCfg,
 		cfg:                  cfg,
 		metricsClient:        metricsClient,
+		executorEvents:       executorEvents,
+		inflightHeartbeats:   newInflightHeartbeats(),
 	}
 }
 
 func (h *executor) Heartbeat(ctx context.Context, request *types.ExecutorHeartbeatRequest) (*types.ExecutorHeartbeatResponse, error) {
+	metricsScope := h.metricsClient.Scope(metrics.ShardDistributorHeartbeatScope).
+		Tagged(metrics.NamespaceTag(request.Namespace))
+	metricsScope.IncCounter(metrics.ShardDistributorHeartbeatReceived)
+
+	// Overlapping identical heartbeats of the same executor (e.g. client retries) are collapsed into one
+	// store interaction, so the same report is not written and smoothed twice.
+	key, ok := heartbeatKey(request)
+	if !ok {
+		return h.heartbeat(ctx, request, metricsScope)
+	}
+	return h.inflightHeartbeats.do(ctx, key,
+		func() { metricsScope.IncCounter(metrics.ShardDistributorHeartbeatDeduplicated) },
+		func(ctx context.Context) (*types.ExecutorHeartbeatResponse, error) {
+			return h.heartbeat(ctx, request, metricsScope)
+		},
+	)
+}
+
+func (h *executor) heartbeat(ctx context.Context, request *types.ExecutorHeartbeatRequest, metricsScope metrics.Scope) (*types.ExecutorHeartbeatResponse, error) {
+	request, err := decodeShardStatusReports(request)
+	if err != nil {
+		return nil, types.BadRequestError{Message: fmt.Sprintf("invalid encoded shard status reports: %s", err)}
+	}
+
 	previousHeartbeat, assignedShards, err := h.storage.GetHeartbeat(ctx, request.Namespace, request.ExecutorID)
 	// We ignore Executor not found errors, since it just means that this executor heartbeat the first time.
-	if err != nil && !errors.Is(err, store.ErrExecutorNotFound) {
+	firstHeartbeat := errors.Is(err, store.ErrExecutorNotFound)
+	if err != nil && !firstHeartbeat {
 		return nil, &types.InternalServiceError{Message: fmt.Sprintf("failed to get heartbeat: %v", err)}
 	}
 
@@ -63,7 +103,23 @@ func (h *executor) Heartbeat(ctx context.Context, request *types.ExecutorHeartbe
 		return nil, &types.InternalServiceError{Message: fmt.Sprintf("namespace's migration mode is invalid: %v", err)}
 	case types.MigrationModeLOCALPASSTHROUGH:
 		h.logger.Info("Migration mode is local passthrough, no calls to heartbeat should be allowed", tag.ShardNamespace(request.Namespace), tag.ShardExecutor(request.ExecutorID))
-		return _convertResponse(nil, mode), nil
+		metricsScope.IncCounter(metrics.ShardDistributorHeartbeatWriteSkipped)
+		res := _convertResponse(nil, mode, time.Time{})
+		res.ReasonCode = types.HeartbeatReasonCodePASSTHROUGH
+		return res, nil
+	}
+
+	// A retried heartbeat whose original attempt already succeeded must not smooth the same report twice.
+	sequence := request.GetSequenceNumber()
+	if sequence > 0 && h.isProcessedSequence(previousHeartbeat, request.GetIncarnationID(), sequence, heartbeatTime) {
+		metricsScope.IncCounter(metrics.ShardDistributorHeartbeatDuplicateSequence)
+		res := _convertResponse(assignedShards, mode, h.cfg.GetShardLeaseExpiry(request.Namespace, heartbeatTime))
+		res.ReasonCode = types.HeartbeatReasonCodeDUPLICATE
+		res = h.withHandoffs(ctx, request.Namespace, request.ExecutorID, previousHeartbeat.ReportedShards, res)
+		if request.GetDeltaResponse() {
+			res = _toDeltaResponse(res, previousHeartbeat.ReportedShards)
+		}
+		return res, nil
 	}
 
 	newHeartbeat := store.HeartbeatState{
@@ -72,15 +128,112 @@ func (h *executor) Heartbeat(ctx context.Context, request *types.ExecutorHeartbe
 		ReportedShards: request.ShardStatusReports,
 		Metadata:       request.GetMetadata(),
 	}
+	if request.GetIsDeltaReport() {
+		newHeartbeat.ReportedShards = mergeShardStatusReports(previousHeartbeat, request.ShardStatusReports)
+	}
 
+	if key, ok := findReservedMetadataKey(newHeartbeat.Metadata); ok {
+		return nil, types.BadRequestError{Message: fmt.Sprintf("invalid metadata: key %q is reserved", key)}
+	}
+	for key, value := range request.GetLabels() {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataLabelPrefix+key, value)
+	}
+
+	if err := validateShardLoads(request.ShardStatusReports); err != nil {
+		return nil, types.BadRequestError{Message: fmt.Sprintf("invalid shard load: %s", err)}
+	}
+	if load := request.GetExecutorLoad(); load < 0 || math.IsNaN(load) || math.IsInf(load, 0) {
+		return nil, types.BadRequestError{Message: fmt.Sprintf("invalid executor load: %v", load)}
+	}
+	if maxShardLoad := h.cfg.GetMaxShardLoad(request.Namespace); maxShardLoad > 0 {
+		var clamped int
+		newHeartbeat.ReportedShards, clamped = clampShardLoads(newHeartbeat.ReportedShards, maxShardLoad)
+		if clamped > 0 {
+			metricsScope.AddCounter(metrics.ShardDistributorHeartbeatClampedShardLoads, int64(clamped))
+		}
+	}
+	if headroom := request.GetHeadroom(); headroom > 0 {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataHeadroomKey, strconv.FormatFloat(headroom, 'g', -1, 64))
+	}
+	if request.ExecutorLoad != nil {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataLoadKey, strconv.FormatFloat(request.GetExecutorLoad(), 'g', -1, 64))
+	}
+	if role := request.GetRole(); role != types.ExecutorRoleWORKER {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataRoleKey, role.String())
+	}
+	if sequence > 0 {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataSequenceKey, strconv.FormatInt(sequence, 10))
+	}
+	if incarnationID := request.GetIncarnationID(); incarnationID != "" {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataIncarnationKey, incarnationID)
+	}
+	if registeredAt := h.registrationTime(request.Namespace, previousHeartbeat, firstHeartbeat, heartbeatTime); !registeredAt.IsZero() {
+		newHeartbeat.Metadata = withMetadataValue(newHeartbeat.Metadata, store.ExecutorMetadataRegisteredAtKey, registeredAt.Format(time.RFC3339Nano))
+	}
+	// The keys derived from the heartbeat count towards the limit, since they are stored with the metadata.
 	if err := validateMetadata(newHeartbeat.Metadata); err != nil {
 		return nil, types.BadRequestError{Message: fmt.Sprintf("invalid metadata: %s", err)}
 	}
 
-	err = h.storage.RecordHeartbeat(ctx, request.Namespace, request.ExecutorID, newHeartbeat)
+	if unknownShards := h.findUnknownShardReports(ctx, request, assignedShards); len(unknownShards) > 0 {
+		metricsScope.AddCounter(metrics.ShardDistributorHeartbeatUnknownShardReports, int64(len(unknownShards)))
+		h.logger.Warn("Executor reported shards unknown to the namespace",
+			tag.ShardNamespace(request.Namespace),
+			tag.ShardExecutor(request.ExecutorID),
+			tag.Dynamic("unknown-shards", unknownShards))
+		if h.cfg.ShouldRejectUnknownShardReports(request.Namespace) {
+			return nil, types.BadRequestError{Message: fmt.Sprintf("shard reports for unknown shards: %v", unknownShards)}
+		}
+	}
+
+	var token store.ConsistencyToken
+	err = withRetry(ctx, h.timeSource, storeRetryPolicy(h.cfg), func(ctx context.Context) error {
+		token, err = h.recordHeartbeat(ctx, request.Namespace, request.ExecutorID, newHeartbeat)
+		return err
+	})
+	if errors.Is(err, store.ErrReadOnly) {
+		// While the store only serves reads, e.g. during maintenance, the executor keeps the assignment
+		// read above until writes resume instead of failing every heartbeat.
+		metricsScope.IncCounter(metrics.ShardDistributorHeartbeatReadOnlyStore)
+		h.logger.Warn("Store is read-only, serving the last known assignment without recording the heartbeat",
+			tag.ShardNamespace(request.Namespace),
+			tag.ShardExecutor(request.ExecutorID),
+			tag.Error(err))
+		res := _convertResponse(assignedShards, mode, h.cfg.GetShardLeaseExpiry(request.Namespace, heartbeatTime))
+		res.ReasonCode = types.HeartbeatReasonCodeREADONLY
+		res = h.withHandoffs(ctx, request.Namespace, request.ExecutorID, newHeartbeat.ReportedShards, res)
+		if request.GetDeltaResponse() {
+			res = _toDeltaResponse(res, newHeartbeat.ReportedShards)
+		}
+		return res, nil
+	}
+	var partiallyApplied *store.ErrHeartbeatPartiallyApplied
+	if errors.As(err, &partiallyApplied) {
+		// The heartbeat is stored, but the shard statistics still reflect the previous one.
+		h.logger.Warn("Heartbeat recorded without updating shard statistics",
+			tag.ShardNamespace(request.Namespace),
+			tag.ShardExecutor(request.ExecutorID),
+			tag.Error(err))
+		return nil, &types.InternalDataInconsistencyError{Message: fmt.Sprintf("heartbeat partially applied: %v", err)}
+	}
 	if err != nil {
 		return nil, &types.InternalServiceError{Message: fmt.Sprintf("failed to record heartbeat: %v", err)}
 	}
+	// The store reads made for the rest of the heartbeat reflect the heartbeat just recorded.
+	ctx = store.WithConsistencyToken(ctx, token)
+
+	if firstHeartbeat {
+		h.executorEvents.ExecutorRegistered(events.ExecutorEvent{
+			Namespace:  request.Namespace,
+			ExecutorID: request.ExecutorID,
+			Time:       heartbeatTime,
+			Metadata:   newHeartbeat.Metadata,
+		})
+	}
+
+	if h.isEphemeralNamespace(request.Namespace) {
+		h.releaseDoneShards(ctx, request.Namespace, request.ExecutorID, newHeartbeat.ReportedShards, assignedShards, metricsScope)
+	}
 
 	// emit shard assignment metrics only if shards are assigned in the background
 	// shard assignment in heartbeat doesn't involve any assignment changes happening in the background
@@ -88,7 +241,125 @@ func (h *executor) Heartbeat(ctx context.Context, request *types.ExecutorHeartbe
 	// to measure, so don't need to emit metrics in that case
 	h.emitShardAssignmentMetrics(request.Namespace, heartbeatTime, previousHeartbeat, assignedShards)
 
-	return _convertResponse(assignedShards, mode), nil
+	res := _convertResponse(assignedShards, mode, h.cfg.GetShardLeaseExpiry(request.Namespace, heartbeatTime))
+	res.ReasonCode = types.HeartbeatReasonCodeACCEPTED
+	if previousHeartbeat != nil && previousHeartbeat.Status != newHeartbeat.Status {
+		res.ReasonCode = types.HeartbeatReasonCodeSTATUSCHANGEAPPLIED
+	}
+	res = h.withHandoffs(ctx, request.Namespace, request.ExecutorID, newHeartbeat.ReportedShards, res)
+	if request.GetDeltaResponse() {
+		res = _toDeltaResponse(res, newHeartbeat.ReportedShards)
+	}
+	return res, nil
+}
+
+// decodeShardStatusReports returns the request with its encoded shard status reports decoded into
+// ShardStatusReports. The request is returned as is when it carries no encoded reports.
+func decodeShardStatusReports(request *types.ExecutorHeartbeatRequest) (*types.ExecutorHeartbeatRequest, error) {
+	if len(request.EncodedShardStatusReports) == 0 {
+		return request, nil
+	}
+	if len(request.ShardStatusReports) > 0 {
+		return nil, errors.New("both encoded and plain shard status reports are set")
+	}
+
+	reports, err := reportcodec.Decode(request.EncodedShardStatusReports)
+	if err != nil {
+		return nil, err
+	}
+	decoded := *request
+	decoded.ShardStatusReports = reports
+	decoded.EncodedShardStatusReports = nil
+	return &decoded, nil
+}
+
+// recordHeartbeat records the heartbeat and returns its consistency token when the store provides one.
+func (h *executor) recordHeartbeat(ctx context.Context, namespace, executorID string, state store.HeartbeatState) (store.ConsistencyToken, error) {
+	if recorder, ok := h.storage.(store.HeartbeatTokenRecorder); ok {
+		return recorder.RecordHeartbeatWithToken(ctx, namespace, executorID, state)
+	}
+	return "", h.storage.RecordHeartbeat(ctx, namespace, executorID, state)
+}
+
+// mergeShardStatusReports applies the delta reports of a heartbeat on top of the reports recorded
+// with the previous heartbeat. Without a previous heartbeat the delta is all that is known.
+func mergeShardStatusReports(previousHeartbeat *store.HeartbeatState, delta map[string]*types.ShardStatusReport) map[string]*types.ShardStatusReport {
+	var previous map[string]*types.ShardStatusReport
+	if previousHeartbeat != nil {
+		previous = previousHeartbeat.ReportedShards
+	}
+
+	merged := make(map[string]*types.ShardStatusReport, len(previous)+len(delta))
+	for shardID, report := range previous {
+		merged[shardID] = report
+	}
+	for shardID, report := range delta {
+		merged[shardID] = report
+	}
+	return merged
+}
+
+// releaseDoneShards removes the assigned shards the executor reported as DONE from its assigned state,
+// so the leader retires them instead of keeping them on the executor. Released shards are also dropped
+// from assignedShards so they are not returned in the heartbeat response. Shards that are no longer
+// assigned are skipped, so repeated DONE reports release a shard only once. A failed release is logged
+// and retried on the next heartbeat.
+func (h *executor) releaseDoneShards(
+	ctx context.Context,
+	namespace, executorID string,
+	reportedShards map[string]*types.ShardStatusReport,
+	assignedShards *store.AssignedState,
+	metricsScope metrics.Scope,
+) {
+	if assignedShards == nil {
+		return
+	}
+
+	var doneShards []string
+	for shardID, report := range reportedShards {
+		if report.GetStatus() != types.ShardStatusDONE {
+			continue
+		}
+		if _, ok := assignedShards.AssignedShards[shardID]; ok {
+			doneShards = append(doneShards, shardID)
+		}
+	}
+	if len(doneShards) == 0 {
+		return
+	}
+	slices.Sort(doneShards)
+
+	err := withRetry(ctx, h.timeSource, storeRetryPolicy(h.cfg), func(ctx context.Context) error {
+		return h.storage.ReleaseShards(ctx, namespace, executorID, doneShards)
+	})
+	if err != nil {
+		h.logger.Warn("Failed to release shards reported as done",
+			tag.ShardNamespace(namespace),
+			tag.ShardExecutor(executorID),
+			tag.Dynamic("done-shards", doneShards),
+			tag.Error(err))
+		return
+	}
+
+	for _, shardID := range doneShards {
+		delete(assignedShards.AssignedShards, shardID)
+	}
+	metricsScope.AddCounter(metrics.ShardDistributorHeartbeatReleasedShards, int64(len(doneShards)))
+	h.logger.Info("Released shards reported as done",
+		tag.ShardNamespace(namespace),
+		tag.ShardExecutor(executorID),
+		tag.Dynamic("done-shards", doneShards))
+}
+
+// isEphemeralNamespace reports whether the namespace is configured as ephemeral. Only ephemeral
+// shards can be retired, a fixed namespace would assign a released shard again.
+func (h *executor) isEphemeralNamespace(namespace string) bool {
+	for _, namespaceCfg := range h.shardDistributionCfg.Namespaces {
+		if namespaceCfg.Name == namespace {
+			return namespaceCfg.Type == config.NamespaceTypeEphemeral
+		}
+	}
+	return false
 }
 
 // emitShardAssignmentMetrics emits the following metrics for newly assigned shards:
@@ -123,16 +394,128 @@ func (h *executor) emitShardAssignmentMetrics(namespace string, heartbeatTime ti
 	}
 }
 
-func _convertResponse(shards *store.AssignedState, mode types.MigrationMode) *types.ExecutorHeartbeatResponse {
+// _convertResponse builds the heartbeat response. When leaseExpiresAt is set, every returned
+// assignment carries the renewed lease; the assignments are copied so the stored state is not mutated.
+func _convertResponse(shards *store.AssignedState, mode types.MigrationMode, leaseExpiresAt time.Time) *types.ExecutorHeartbeatResponse {
 	res := &types.ExecutorHeartbeatResponse{}
 	res.MigrationMode = mode
 	if shards == nil {
 		return res
 	}
-	res.ShardAssignments = shards.AssignedShards
+	if leaseExpiresAt.IsZero() {
+		res.ShardAssignments = shards.AssignedShards
+		return res
+	}
+
+	res.ShardAssignments = make(map[string]*types.ShardAssignment, len(shards.AssignedShards))
+	for shardID, assignment := range shards.AssignedShards {
+		if assignment == nil {
+			continue
+		}
+		leased := *assignment
+		leased.LeaseExpiresAt = leaseExpiresAt
+		res.ShardAssignments[shardID] = &leased
+	}
 	return res
 }
 
+// _toDeltaResponse returns res with its shard assignments replaced by the sorted shards the executor has to start,
+// the assigned shards it did not report, and the sorted shards it has to stop, the reported shards that are not
+// assigned to it. Reports in any status count, the executor still holds shards it reported DONE.
+func _toDeltaResponse(res *types.ExecutorHeartbeatResponse, reportedShards map[string]*types.ShardStatusReport) *types.ExecutorHeartbeatResponse {
+	delta := *res
+	delta.ShardAssignments = nil
+	delta.ShardsToStart = nil
+	delta.ShardsToStop = nil
+	for _, shardID := range slices.Sorted(maps.Keys(res.ShardAssignments)) {
+		if _, ok := reportedShards[shardID]; !ok {
+			delta.ShardsToStart = append(delta.ShardsToStart, shardID)
+		}
+	}
+	for _, shardID := range slices.Sorted(maps.Keys(reportedShards)) {
+		if _, ok := res.ShardAssignments[shardID]; !ok {
+			delta.ShardsToStop = append(delta.ShardsToStop, shardID)
+		}
+	}
+	return &delta
+}
+
+// findUnknownShardReports returns the sorted IDs of reported shards that are not assigned to any
+// executor of the namespace. Shards assigned to the reporting executor are known without a lookup,
+// the others are looked up in one batched read, at most _maxUnknownShardReportLookups of them in
+// shard ID order. A failed lookup is logged and the shards are treated as known.
+func (h *executor) findUnknownShardReports(ctx context.Context, request *types.ExecutorHeartbeatRequest, assignedShards *store.AssignedState) []string {
+	var candidates []string
+	for shardID := range request.ShardStatusReports {
+		if assignedShards != nil {
+			if _, ok := assignedShards.AssignedShards[shardID]; ok {
+				continue
+			}
+		}
+		candidates = append(candidates, shardID)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	slices.Sort(candidates)
+	if len(candidates) > _maxUnknownShardReportLookups {
+		candidates = candidates[:_maxUnknownShardReportLookups]
+	}
+
+	owners, err := h.storage.GetShardOwners(ctx, request.Namespace, candidates)
+	if err != nil {
+		h.logger.Warn("Failed to look up owners of reported shards", tag.ShardNamespace(request.Namespace), tag.ShardExecutor(request.ExecutorID), tag.Error(err))
+		return nil
+	}
+
+	var unknownShards []string
+	for _, shardID := range candidates {
+		if _, ok := owners[shardID]; !ok {
+			unknownShards = append(unknownShards, shardID)
+		}
+	}
+	return unknownShards
+}
+
+// isProcessedSequence reports whether a heartbeat with sequence was already processed, that is the last
+// processed heartbeat is of the same incarnation with a sequence number at least as high. An executor that
+// restarts starts its sequence over, so a lower sequence number of a new incarnation, or after the last
+// processed heartbeat expired with the heartbeat TTL, is a new heartbeat.
+func (h *executor) isProcessedSequence(previousHeartbeat *store.HeartbeatState, incarnationID string, sequence int64, now time.Time) bool {
+	if previousHeartbeat == nil || sequence > previousHeartbeat.SequenceNumber() {
+		return false
+	}
+	if incarnationID != previousHeartbeat.IncarnationID() {
+		return false
+	}
+	ttl := h.shardDistributionCfg.Process.HeartbeatTTL
+	if ttl <= 0 {
+		ttl = config.DefaultHeartbeatTTL
+	}
+	return now.Sub(previousHeartbeat.LastHeartbeat) <= ttl
+}
+
+// findReservedMetadataKey returns a key of the metadata reported by an executor that is reserved for the
+// values the shard distributor derives from the heartbeat, such as the headroom or the labels. Reporting
+// them as metadata would override the derived values.
+func findReservedMetadataKey(metadata map[string]string) (string, bool) {
+	for key := range metadata {
+		switch key {
+		case store.ExecutorMetadataHeadroomKey,
+			store.ExecutorMetadataLoadKey,
+			store.ExecutorMetadataRoleKey,
+			store.ExecutorMetadataSequenceKey,
+			store.ExecutorMetadataIncarnationKey,
+			store.ExecutorMetadataRegisteredAtKey:
+			return key, true
+		}
+		if strings.HasPrefix(key, store.ExecutorMetadataLabelPrefix) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
 func validateMetadata(metadata map[string]string) error {
 	if len(metadata) > _maxMetadataKeys {
 		return fmt.Errorf("metadata has %d keys, which exceeds the maximum of %d", len(metadata), _maxMetadataKeys)
@@ -151,6 +534,79 @@ func validateMetadata(metadata map[string]string) error {
 	return nil
 }
 
+// validateShardLoads rejects reports with a negative, NaN or infinite load, in total or in any dimension.
+func validateShardLoads(reports map[string]*types.ShardStatusReport) error {
+	invalid := func(load float64) bool { return load < 0 || math.IsNaN(load) || math.IsInf(load, 0) }
+	for shardID, report := range reports {
+		if invalid(report.GetShardLoad()) {
+			return fmt.Errorf("shard %s reported load %v", shardID, report.GetShardLoad())
+		}
+		for dimension, load := range report.GetShardLoads() {
+			if invalid(load) {
+				return fmt.Errorf("shard %s reported load %v for dimension %s", shardID, load, dimension)
+			}
+		}
+	}
+	return nil
+}
+
+// clampShardLoads returns reports with every load above maxShardLoad lowered to it, and the number
+// of reports that were clamped. Clamped reports are copied, so the given reports are not modified.
+func clampShardLoads(reports map[string]*types.ShardStatusReport, maxShardLoad float64) (map[string]*types.ShardStatusReport, int) {
+	clampedReports := 0
+	result := make(map[string]*types.ShardStatusReport, len(reports))
+	for shardID, report := range reports {
+		result[shardID] = report
+		if report == nil {
+			continue
+		}
+
+		overMax := report.ShardLoad > maxShardLoad
+		for _, load := range report.ShardLoads {
+			overMax = overMax || load > maxShardLoad
+		}
+		if !overMax {
+			continue
+		}
+
+		clamped := *report
+		clamped.ShardLoad = math.Min(report.ShardLoad, maxShardLoad)
+		if len(report.ShardLoads) > 0 {
+			clamped.ShardLoads = make(map[string]float64, len(report.ShardLoads))
+			for dimension, load := range report.ShardLoads {
+				clamped.ShardLoads[dimension] = math.Min(load, maxShardLoad)
+			}
+		}
+		result[shardID] = &clamped
+		clampedReports++
+	}
+	return result, clampedReports
+}
+
+// registrationTime returns the time the executor registered: the time of this heartbeat for its first heartbeat,
+// the recorded time otherwise. The time is only recorded while the namespace has a cold start grace period, so
+// it is zero for executors that registered before.
+func (h *executor) registrationTime(namespace string, previousHeartbeat *store.HeartbeatState, firstHeartbeat bool, heartbeatTime time.Time) time.Time {
+	if previousHeartbeat != nil && !firstHeartbeat {
+		return previousHeartbeat.RegisteredAt()
+	}
+	if h.cfg.GetColdStartGracePeriod(namespace) > 0 {
+		return heartbeatTime
+	}
+	return time.Time{}
+}
+
+// withMetadataValue returns a copy of metadata with value stored under key, so values reported outside
+// of the metadata, e.g. the headroom, are persisted together with the rest of the executor metadata.
+func withMetadataValue(metadata map[string]string, key, value string) map[string]string {
+	withValue := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		withValue[k] = v
+	}
+	withValue[key] = value
+	return withValue
+}
+
 func filterNewlyAssignedShardIDs(previousHeartbeat *store.HeartbeatState, assignedState *store.AssignedState) []string {
 	// if assignedState is nil, no shards are assigned
 	if assignedState == nil {
@@ -3,10 +3,15 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 	"go.uber.org/mock/gomock"
 
 	"github.com/uber/cadence/common"
@@ -18,6 +23,8 @@ import (
 	metricmocks "github.com/uber/cadence/common/metrics/mocks"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
+	"github.com/uber/cadence/service/sharddistributor/reportcodec"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -34,7 +41,7 @@ func TestHeartbeat(t *testing.T) {
 		mockTimeSource := clock.NewMockedTimeSourceAt(now)
 		shardDistributionCfg := config.ShardDistribution{}
 		cfg := newConfig(t, []configEntry{})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -59,7 +66,7 @@ func TestHeartbeat(t *testing.T) {
 		mockTimeSource := clock.NewMockedTimeSourceAt(now)
 		shardDistributionCfg := config.ShardDistribution{}
 		cfg := newConfig(t, []configEntry{})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -89,7 +96,7 @@ func TestHeartbeat(t *testing.T) {
 		mockTimeSource := clock.NewMockedTimeSourceAt(now)
 		shardDistributionCfg := config.ShardDistribution{}
 		cfg := newConfig(t, []configEntry{})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -119,7 +126,7 @@ func TestHeartbeat(t *testing.T) {
 		mockTimeSource := clock.NewMockedTimeSource()
 		shardDistributionCfg := config.ShardDistribution{}
 		cfg := newConfig(t, []configEntry{})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -144,7 +151,7 @@ func TestHeartbeat(t *testing.T) {
 			Namespaces: []config.Namespace{{Name: namespace, Mode: config.MigrationModeINVALID}},
 		}
 		cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeINVALID}})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -173,7 +180,7 @@ func TestHeartbeat(t *testing.T) {
 			Namespaces: []config.Namespace{{Name: namespace, Mode: config.MigrationModeLOCALPASSTHROUGH}},
 		}
 		cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeLOCALPASSTHROUGH}})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		req := &types.ExecutorHeartbeatRequest{
 			Namespace:  namespace,
@@ -190,6 +197,7 @@ func TestHeartbeat(t *testing.T) {
 		resp, err := handler.Heartbeat(ctx, req)
 		require.NoError(t, err)
 		require.Equal(t, types.MigrationModeLOCALPASSTHROUGH, resp.MigrationMode)
+		require.Equal(t, types.HeartbeatReasonCodePASSTHROUGH, resp.ReasonCode)
 	})
 
 	// Test Case 9: Heartbeat with metadata validation failure - too many keys
@@ -199,7 +207,7 @@ func TestHeartbeat(t *testing.T) {
 		mockTimeSource := clock.NewMockedTimeSourceAt(now)
 		shardDistributionCfg := config.ShardDistribution{}
 		cfg := newConfig(t, []configEntry{})
-		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
 
 		// Create metadata with more than max allowed keys
 		metadata := make(map[string]string)
@@ -221,6 +229,1286 @@ func TestHeartbeat(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid metadata: metadata has 33 keys, which exceeds the maximum of 32")
 	})
 
+	// Test Case 10: Labels and values derived from the heartbeat count towards the metadata key limit
+	t.Run("MetadataValidationTooManyKeysWithLabels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+		mockTimeSource := clock.NewMockedTimeSourceAt(now)
+		cfg := newConfig(t, []configEntry{})
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+		// Together with the label and the headroom the metadata has one key too many
+		metadata := make(map[string]string)
+		for i := 0; i < _maxMetadataKeys-1; i++ {
+			metadata[string(rune('a'+i))] = "value"
+		}
+
+		req := &types.ExecutorHeartbeatRequest{
+			Namespace:  namespace,
+			ExecutorID: executorID,
+			Status:     types.ExecutorStatusACTIVE,
+			Metadata:   metadata,
+			Labels:     map[string]string{"tier": "gold"},
+			Headroom:   0.5,
+		}
+
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+
+		_, err := handler.Heartbeat(ctx, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid metadata: metadata has 33 keys, which exceeds the maximum of 32")
+	})
+}
+
+func TestHeartbeat_RejectsReservedMetadataKeys(t *testing.T) {
+	for _, key := range []string{
+		store.ExecutorMetadataHeadroomKey,
+		store.ExecutorMetadataLoadKey,
+		store.ExecutorMetadataRoleKey,
+		store.ExecutorMetadataSequenceKey,
+		store.ExecutorMetadataIncarnationKey,
+		store.ExecutorMetadataRegisteredAtKey,
+		store.ExecutorMetadataLabelPrefix + "tier",
+	} {
+		t.Run(key, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			cfg := newConfig(t, []configEntry{})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), "test-namespace", "test-executor").Return(nil, nil, store.ErrExecutorNotFound)
+
+			_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:  "test-namespace",
+				ExecutorID: "test-executor",
+				Status:     types.ExecutorStatusACTIVE,
+				Metadata:   map[string]string{store.ExecutorMetadataZoneKey: "zone-a", key: "spoofed"},
+			})
+			var badRequest types.BadRequestError
+			require.ErrorAs(t, err, &badRequest)
+			require.Contains(t, badRequest.Message, fmt.Sprintf("key %q is reserved", key))
+		})
+	}
+}
+
+func TestHeartbeat_RetriesTransientRecordHeartbeatErrors(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	mockTimeSource := clock.NewMockedTimeSource()
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorStoreRetryInitialInterval, time.Second}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	gomock.InOrder(
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(errors.New("etcd unavailable")),
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil),
+	)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+			Namespace:  namespace,
+			ExecutorID: executorID,
+			Status:     types.ExecutorStatusACTIVE,
+		})
+		result <- err
+	}()
+
+	mockTimeSource.BlockUntil(1)
+	mockTimeSource.Advance(time.Second)
+	require.NoError(t, <-result)
+}
+
+func TestHeartbeat_PartiallyAppliedHeartbeat(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	mockTimeSource := clock.NewMockedTimeSource()
+	cfg := newConfig(t, []configEntry{
+		{dynamicproperties.ShardDistributorStoreRetryMaxAttempts, 2},
+		{dynamicproperties.ShardDistributorStoreRetryInitialInterval, time.Second},
+	})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, mockTimeSource, config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	// The heartbeat is recorded on every attempt, while its statistics never are.
+	partiallyApplied := &store.ErrHeartbeatPartiallyApplied{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Err:        fmt.Errorf("apply shard statistics updates: %w", store.ErrVersionConflict),
+	}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(partiallyApplied).Times(2)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+			Namespace:  namespace,
+			ExecutorID: executorID,
+			Status:     types.ExecutorStatusACTIVE,
+		})
+		result <- err
+	}()
+
+	mockTimeSource.BlockUntil(1)
+	mockTimeSource.Advance(time.Second)
+	err := <-result
+	var inconsistency *types.InternalDataInconsistencyError
+	require.ErrorAs(t, err, &inconsistency)
+	require.Contains(t, inconsistency.Message, "partially applied")
+}
+
+func TestHeartbeat_Metrics(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	receivedCounterName := "test.shard_distributor_heartbeat_received+namespace=test-namespace,operation=ExecutorHeartbeat"
+	skippedCounterName := "test.shard_distributor_heartbeat_write_skipped+namespace=test-namespace,operation=ExecutorHeartbeat"
+
+	tests := []struct {
+		name            string
+		migrationMode   string
+		setupMocks      func(mockStore *store.MockStore)
+		expectedSkipped bool
+	}{
+		{
+			name:          "Recorded heartbeat",
+			migrationMode: config.MigrationModeONBOARDED,
+			setupMocks: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+				mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+			},
+			expectedSkipped: false,
+		},
+		{
+			name:          "Local passthrough skips the write",
+			migrationMode: config.MigrationModeLOCALPASSTHROUGH,
+			setupMocks: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+			},
+			expectedSkipped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			tt.setupMocks(mockStore)
+
+			testScope := tally.NewTestScope("test", nil)
+			metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+			cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, tt.migrationMode}})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metricsClient, events.NewNoop())
+
+			_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:  namespace,
+				ExecutorID: executorID,
+				Status:     types.ExecutorStatusACTIVE,
+			})
+			require.NoError(t, err)
+
+			counters := testScope.Snapshot().Counters()
+			require.Contains(t, counters, receivedCounterName)
+			require.Equal(t, int64(1), counters[receivedCounterName].Value())
+			if tt.expectedSkipped {
+				require.Contains(t, counters, skippedCounterName)
+				require.Equal(t, int64(1), counters[skippedCounterName].Value())
+			} else {
+				require.NotContains(t, counters, skippedCounterName)
+			}
+		})
+	}
+}
+
+func TestHeartbeat_UnknownShardReports(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+	unknownCounterName := "test.shard_distributor_heartbeat_unknown_shard_reports+namespace=test-namespace,operation=ExecutorHeartbeat"
+
+	tests := []struct {
+		name          string
+		reject        bool
+		expectedError bool
+	}{
+		{
+			name:   "Unknown shard reports are counted",
+			reject: false,
+		},
+		{
+			name:          "Unknown shard reports are rejected when configured",
+			reject:        true,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+
+			assignedState := &store.AssignedState{AssignedShards: makeReadyAssignedShards("owned-shard")}
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, assignedState, nil)
+			// A shard owned by another executor is known, a shard without an owner is not.
+			mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, []string{"other-executor-shard", "unknown-shard"}).
+				Return(map[string]*store.ShardOwner{"other-executor-shard": {ExecutorID: "other-executor"}}, nil)
+			if !tt.expectedError {
+				mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+			}
+
+			testScope := tally.NewTestScope("test", nil)
+			metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+			cfg := newConfig(t, []configEntry{
+				{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED},
+				{dynamicproperties.ShardDistributorRejectUnknownShardReports, tt.reject},
+			})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metricsClient, events.NewNoop())
+
+			_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:  namespace,
+				ExecutorID: executorID,
+				Status:     types.ExecutorStatusACTIVE,
+				ShardStatusReports: map[string]*types.ShardStatusReport{
+					"owned-shard":          {Status: types.ShardStatusREADY},
+					"other-executor-shard": {Status: types.ShardStatusREADY},
+					"unknown-shard":        {Status: types.ShardStatusREADY},
+				},
+			})
+			if tt.expectedError {
+				require.ErrorAs(t, err, &types.BadRequestError{})
+				require.ErrorContains(t, err, "unknown-shard")
+			} else {
+				require.NoError(t, err)
+			}
+
+			counters := testScope.Snapshot().Counters()
+			require.Contains(t, counters, unknownCounterName)
+			require.Equal(t, int64(1), counters[unknownCounterName].Value())
+		})
+	}
+}
+
+func TestHeartbeat_UnknownShardReportsLookup(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+	unknownCounterName := "test.shard_distributor_heartbeat_unknown_shard_reports+namespace=test-namespace,operation=ExecutorHeartbeat"
+
+	// One more reported shard than is looked up, the last shard ID in order is never looked up.
+	reports := make(map[string]*types.ShardStatusReport, _maxUnknownShardReportLookups+1)
+	var lookedUp []string
+	for i := 0; i <= _maxUnknownShardReportLookups; i++ {
+		shardID := fmt.Sprintf("shard-%05d", i)
+		reports[shardID] = &types.ShardStatusReport{Status: types.ShardStatusREADY}
+		if i < _maxUnknownShardReportLookups {
+			lookedUp = append(lookedUp, shardID)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		owners          map[string]*store.ShardOwner
+		lookupErr       error
+		expectedUnknown int64
+	}{
+		{
+			name:            "Lookups are capped",
+			owners:          map[string]*store.ShardOwner{},
+			expectedUnknown: _maxUnknownShardReportLookups,
+		},
+		{
+			name:      "Failed lookup treats the shards as known",
+			lookupErr: errors.New("lookup failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, &store.AssignedState{}, nil)
+			mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, lookedUp).Return(tt.owners, tt.lookupErr)
+			mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+
+			testScope := tally.NewTestScope("test", nil)
+			metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+			cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metricsClient, events.NewNoop())
+
+			_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:          namespace,
+				ExecutorID:         executorID,
+				Status:             types.ExecutorStatusACTIVE,
+				ShardStatusReports: reports,
+			})
+			require.NoError(t, err)
+
+			counters := testScope.Snapshot().Counters()
+			if tt.expectedUnknown == 0 {
+				require.NotContains(t, counters, unknownCounterName)
+			} else {
+				require.Equal(t, tt.expectedUnknown, counters[unknownCounterName].Value())
+			}
+		})
+	}
+}
+
+func TestHeartbeat_ClampsShardLoads(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+	clampedCounterName := "test.shard_distributor_heartbeat_clamped_shard_loads+namespace=test-namespace,operation=ExecutorHeartbeat"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	assigned := &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+		"over-max":           {Status: types.AssignmentStatusREADY},
+		"in-range":           {Status: types.AssignmentStatusREADY},
+		"dimension-over-max": {Status: types.AssignmentStatusREADY},
+	}}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, assigned, nil)
+	var recorded store.HeartbeatState
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			recorded = state
+			return nil
+		})
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+	cfg := newConfig(t, []configEntry{
+		{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED},
+		{dynamicproperties.ShardDistributorMaxShardLoad, 100.0},
+	})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metricsClient, events.NewNoop())
+
+	inRange := &types.ShardStatusReport{Status: types.ShardStatusREADY, ShardLoad: 42}
+	request := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{
+			"over-max":           {Status: types.ShardStatusREADY, ShardLoad: 1e30},
+			"in-range":           inRange,
+			"dimension-over-max": {Status: types.ShardStatusREADY, ShardLoads: map[string]float64{"cpu": 1e9, "memory": 2}},
+		},
+	}
+	_, err := handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Equal(t, 100.0, recorded.ReportedShards["over-max"].ShardLoad)
+	require.Same(t, inRange, recorded.ReportedShards["in-range"], "in-range reports pass through unchanged")
+	require.Equal(t, map[string]float64{"cpu": 100, "memory": 2}, recorded.ReportedShards["dimension-over-max"].ShardLoads)
+	require.Equal(t, 1e30, request.ShardStatusReports["over-max"].ShardLoad, "the request is not modified")
+
+	counters := testScope.Snapshot().Counters()
+	require.Contains(t, counters, clampedCounterName)
+	require.Equal(t, int64(2), counters[clampedCounterName].Value())
+}
+
+func TestHeartbeat_RejectsInvalidShardLoads(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	for name, report := range map[string]*types.ShardStatusReport{
+		"negative load":           {Status: types.ShardStatusREADY, ShardLoad: -1},
+		"NaN load":                {Status: types.ShardStatusREADY, ShardLoad: math.NaN()},
+		"infinite load":           {Status: types.ShardStatusREADY, ShardLoad: math.Inf(1)},
+		"negative dimension load": {Status: types.ShardStatusREADY, ShardLoads: map[string]float64{"cpu": -0.5}},
+		"infinite dimension load": {Status: types.ShardStatusREADY, ShardLoads: map[string]float64{"cpu": math.Inf(1)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+
+			cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+			_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:          namespace,
+				ExecutorID:         executorID,
+				Status:             types.ExecutorStatusACTIVE,
+				ShardStatusReports: map[string]*types.ShardStatusReport{"shard-1": report},
+			})
+			require.ErrorAs(t, err, &types.BadRequestError{})
+		})
+	}
+}
+
+func TestHeartbeat_PersistsHeadroom(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			require.Equal(t, map[string]string{"zone": "zone-a", store.ExecutorMetadataHeadroomKey: "0.75"}, state.Metadata)
+			require.Equal(t, 0.75, state.Headroom())
+			return nil
+		})
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	metadata := map[string]string{"zone": "zone-a"}
+	_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		Metadata:   metadata,
+		Headroom:   0.75,
+	})
+	require.NoError(t, err)
+	// The request metadata is not modified.
+	require.Equal(t, map[string]string{"zone": "zone-a"}, metadata)
+}
+
+func TestHeartbeat_PersistsExecutorLoad(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound).Times(2)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			load, ok := state.ExecutorLoad()
+			require.True(t, ok)
+			require.Equal(t, 42.5, load)
+			return nil
+		})
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:    namespace,
+		ExecutorID:   executorID,
+		Status:       types.ExecutorStatusACTIVE,
+		ExecutorLoad: common.Float64Ptr(42.5),
+	})
+	require.NoError(t, err)
+
+	_, err = handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:    namespace,
+		ExecutorID:   executorID,
+		Status:       types.ExecutorStatusACTIVE,
+		ExecutorLoad: common.Float64Ptr(-1),
+	})
+	require.ErrorAs(t, err, &types.BadRequestError{})
+}
+
+func TestHeartbeat_RecordsRegistrationTime(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	timeSource := clock.NewMockedTimeSource()
+	registeredAt := timeSource.Now().UTC()
+
+	var recorded store.HeartbeatState
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			recorded = state
+			return nil
+		}).Times(2)
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).DoAndReturn(
+		func(context.Context, string, string) (*store.HeartbeatState, *store.AssignedState, error) {
+			return &recorded, nil, nil
+		})
+
+	cfg := newConfig(t, []configEntry{
+		{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED},
+		{dynamicproperties.ShardDistributorLoadBalancingGreedyColdStartGracePeriod, 5 * time.Minute},
+	})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, timeSource, config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	request := &types.ExecutorHeartbeatRequest{Namespace: namespace, ExecutorID: executorID, Status: types.ExecutorStatusACTIVE}
+	_, err := handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, registeredAt, recorded.RegisteredAt())
+
+	// Later heartbeats keep the time of the first one.
+	timeSource.Advance(time.Minute)
+	_, err = handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, registeredAt, recorded.RegisteredAt())
+	require.True(t, recorded.InColdStart(timeSource.Now(), 5*time.Minute))
+}
+
+func TestHeartbeat_PersistsRole(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound).Times(2)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			require.Equal(t, types.ExecutorRoleOBSERVER, state.Role())
+			require.False(t, state.CanOwnShards())
+			return nil
+		})
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			// Workers do not store their role.
+			require.NotContains(t, state.Metadata, store.ExecutorMetadataRoleKey)
+			return nil
+		})
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	for _, role := range []types.ExecutorRole{types.ExecutorRoleOBSERVER, types.ExecutorRoleWORKER} {
+		_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+			Namespace:  namespace,
+			ExecutorID: executorID,
+			Status:     types.ExecutorStatusACTIVE,
+			Role:       role,
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestHeartbeat_PersistsLabels(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	labels := map[string]string{"version": "v2", "region": "us-east"}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			require.Equal(t, labels, state.Labels())
+			require.Equal(t, "value-1", state.Metadata["key-1"])
+			return nil
+		})
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	request := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		Metadata:   map[string]string{"key-1": "value-1"},
+		Labels:     labels,
+	}
+	_, err := handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key-1": "value-1"}, request.Metadata, "the request metadata is not modified")
+}
+
+func TestHeartbeat_SkipsProcessedSequenceNumbers(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	assigned := &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+		"shard-1": {Status: types.AssignmentStatusREADY},
+	}}
+	shardDistributionCfg := config.ShardDistribution{Process: config.LeaderProcess{HeartbeatTTL: 10 * time.Second}}
+
+	tests := []struct {
+		name               string
+		sequence           int64
+		incarnationID      string
+		previousAge        time.Duration
+		expectRecorded     bool
+		expectedReasonCode types.HeartbeatReasonCode
+	}{
+		{name: "duplicate sequence is ignored", sequence: 10, incarnationID: "run-1", expectedReasonCode: types.HeartbeatReasonCodeDUPLICATE},
+		{name: "out of order sequence is ignored", sequence: 9, incarnationID: "run-1", expectedReasonCode: types.HeartbeatReasonCodeDUPLICATE},
+		{name: "newer sequence is processed", sequence: 11, incarnationID: "run-1", expectRecorded: true, expectedReasonCode: types.HeartbeatReasonCodeACCEPTED},
+		{name: "no sequence is processed", sequence: 0, incarnationID: "run-1", expectRecorded: true, expectedReasonCode: types.HeartbeatReasonCodeACCEPTED},
+		{name: "reset sequence of a restarted executor is processed", sequence: 1, incarnationID: "run-2", expectRecorded: true, expectedReasonCode: types.HeartbeatReasonCodeACCEPTED},
+		{name: "reset sequence after the heartbeat TTL is processed", sequence: 1, incarnationID: "run-1", previousAge: 11 * time.Second, expectRecorded: true, expectedReasonCode: types.HeartbeatReasonCodeACCEPTED},
+		{name: "out of order sequence within the heartbeat TTL is ignored", sequence: 9, incarnationID: "run-1", previousAge: 10 * time.Second, expectedReasonCode: types.HeartbeatReasonCodeDUPLICATE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			timeSource := clock.NewMockedTimeSource()
+
+			previous := &store.HeartbeatState{
+				LastHeartbeat: timeSource.Now().UTC().Add(-tt.previousAge),
+				Status:        types.ExecutorStatusACTIVE,
+				Metadata: map[string]string{
+					store.ExecutorMetadataSequenceKey:    "10",
+					store.ExecutorMetadataIncarnationKey: "run-1",
+				},
+			}
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(previous, assigned, nil)
+			if tt.expectRecorded {
+				mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+						require.Equal(t, tt.sequence, state.SequenceNumber())
+						require.Equal(t, tt.incarnationID, state.IncarnationID())
+						return nil
+					})
+			}
+
+			cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, timeSource, shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
+
+			resp, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:      namespace,
+				ExecutorID:     executorID,
+				Status:         types.ExecutorStatusACTIVE,
+				SequenceNumber: tt.sequence,
+				IncarnationID:  tt.incarnationID,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedReasonCode, resp.ReasonCode)
+			require.Contains(t, resp.ShardAssignments, "shard-1", "the current assignment is returned either way")
+		})
+	}
+}
+
+func TestHeartbeat_ReadOnlyStoreServesLastKnownAssignment(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	assigned := &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+		"shard-1": {Status: types.AssignmentStatusREADY},
+		"shard-2": {Status: types.AssignmentStatusREADY},
+	}}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).
+		Return(&store.HeartbeatState{Status: types.ExecutorStatusACTIVE}, assigned, nil)
+	// A read-only store is not retried, the write is attempted once.
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		Return(fmt.Errorf("record heartbeat: %w", store.ErrReadOnly)).Times(1)
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	resp, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.HeartbeatReasonCodeREADONLY, resp.ReasonCode)
+	require.Len(t, resp.ShardAssignments, 2)
+	require.Contains(t, resp.ShardAssignments, "shard-1")
+	require.Contains(t, resp.ShardAssignments, "shard-2")
+}
+
+func TestHeartbeat_MergesDeltaReports(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	// The store returns whatever the previous heartbeat recorded.
+	recorded := &store.HeartbeatState{
+		Status: types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 1},
+			"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 2},
+		},
+	}
+	assigned := &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+		"shard-1": {Status: types.AssignmentStatusREADY},
+		"shard-2": {Status: types.AssignmentStatusREADY},
+	}}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).
+		DoAndReturn(func(context.Context, string, string) (*store.HeartbeatState, *store.AssignedState, error) {
+			return recorded, assigned, nil
+		}).Times(2)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, state store.HeartbeatState) error {
+			recorded = &state
+			return nil
+		}).Times(2)
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	// A delta only carries the changed shard, the other keeps its previous report.
+	_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{
+			"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 5},
+		},
+		IsDeltaReport: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]*types.ShardStatusReport{
+		"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 1},
+		"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 5},
+	}, recorded.ReportedShards)
+
+	// A full report resyncs the recorded reports, dropping shards the executor no longer reports.
+	_, err = handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{
+			"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 4},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]*types.ShardStatusReport{
+		"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 4},
+	}, recorded.ReportedShards)
+}
+
+type recordingExecutorEvents struct {
+	registered   []events.ExecutorEvent
+	deregistered []events.ExecutorEvent
+}
+
+func (r *recordingExecutorEvents) ExecutorRegistered(event events.ExecutorEvent) {
+	r.registered = append(r.registered, event)
+}
+
+func (r *recordingExecutorEvents) ExecutorDeregistered(event events.ExecutorEvent) {
+	r.deregistered = append(r.deregistered, event)
+}
+
+func TestHeartbeat_ReasonCode(t *testing.T) {
+	namespace := "test-namespace"
+	executorID := "test-executor"
+	now := time.Now().UTC()
+
+	tests := map[string]struct {
+		previous *store.HeartbeatState
+		status   types.ExecutorStatus
+		expected types.HeartbeatReasonCode
+	}{
+		"first heartbeat": {
+			status:   types.ExecutorStatusACTIVE,
+			expected: types.HeartbeatReasonCodeACCEPTED,
+		},
+		"same status": {
+			previous: &store.HeartbeatState{Status: types.ExecutorStatusACTIVE},
+			status:   types.ExecutorStatusACTIVE,
+			expected: types.HeartbeatReasonCodeACCEPTED,
+		},
+		"status change": {
+			previous: &store.HeartbeatState{Status: types.ExecutorStatusACTIVE},
+			status:   types.ExecutorStatusDRAINING,
+			expected: types.HeartbeatReasonCodeSTATUSCHANGEAPPLIED,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			cfg := newConfig(t, []configEntry{})
+			handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSourceAt(now), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+			var getErr error
+			if tc.previous == nil {
+				getErr = store.ErrExecutorNotFound
+			}
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(tc.previous, nil, getErr)
+			mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+
+			resp, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+				Namespace:  namespace,
+				ExecutorID: executorID,
+				Status:     tc.status,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resp.ReasonCode)
+		})
+	}
+}
+
+func TestHeartbeat_EmitsRegistrationEventOnFirstHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-namespace"
+	executorID := "test-executor"
+	now := time.Now().UTC()
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	recorder := &recordingExecutorEvents{}
+	cfg := newConfig(t, []configEntry{})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSourceAt(now), config.ShardDistribution{}, cfg, metrics.NoopClient, recorder)
+
+	req := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		Metadata:   map[string]string{"region": "us-east"},
+	}
+	recorded := store.HeartbeatState{
+		LastHeartbeat: now,
+		Status:        types.ExecutorStatusACTIVE,
+		Metadata:      map[string]string{"region": "us-east"},
+	}
+
+	gomock.InOrder(
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound),
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, recorded).Return(nil),
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(&recorded, nil, nil),
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, recorded).Return(nil),
+	)
+
+	_, err := handler.Heartbeat(ctx, req)
+	require.NoError(t, err)
+	_, err = handler.Heartbeat(ctx, req)
+	require.NoError(t, err)
+
+	require.Equal(t, []events.ExecutorEvent{{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Time:       now,
+		Metadata:   map[string]string{"region": "us-east"},
+	}}, recorder.registered)
+	require.Empty(t, recorder.deregistered)
+}
+
+func TestHeartbeat_NoRegistrationEventWhenRecordFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	recorder := &recordingExecutorEvents{}
+	cfg := newConfig(t, []configEntry{})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, recorder)
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), "test-namespace", "test-executor").Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), "test-namespace", "test-executor", gomock.Any()).Return(store.ErrVersionConflict)
+
+	_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  "test-namespace",
+		ExecutorID: "test-executor",
+		Status:     types.ExecutorStatusACTIVE,
+	})
+	require.Error(t, err)
+	require.Empty(t, recorder.registered)
+}
+
+// tokenStore is a store that returns consistency tokens for recorded heartbeats.
+type tokenStore struct {
+	*store.MockStore
+	token store.ConsistencyToken
+}
+
+func (s *tokenStore) RecordHeartbeatWithToken(ctx context.Context, namespace, executorID string, state store.HeartbeatState) (store.ConsistencyToken, error) {
+	if err := s.RecordHeartbeat(ctx, namespace, executorID, state); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+func TestHeartbeat_ForwardsConsistencyToken(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	request := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusDONE},
+		},
+	}
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	shardDistributionCfg := config.ShardDistribution{
+		Namespaces: []config.Namespace{{Name: namespace, Type: config.NamespaceTypeEphemeral}},
+	}
+
+	tests := []struct {
+		name          string
+		newStore      func(*store.MockStore) store.Store
+		expectedToken store.ConsistencyToken
+		expectToken   bool
+	}{
+		{
+			name: "store returns a token",
+			newStore: func(mockStore *store.MockStore) store.Store {
+				return &tokenStore{MockStore: mockStore, token: "revision-42"}
+			},
+			expectedToken: "revision-42",
+			expectToken:   true,
+		},
+		{
+			name:     "store returns an empty token",
+			newStore: func(mockStore *store.MockStore) store.Store { return &tokenStore{MockStore: mockStore} },
+		},
+		{
+			name:     "store without tokens",
+			newStore: func(mockStore *store.MockStore) store.Store { return mockStore },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, &store.AssignedState{
+				AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}},
+			}, nil)
+			mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+			mockStore.EXPECT().ReleaseShards(gomock.Any(), namespace, executorID, []string{"shard-1"}).
+				DoAndReturn(func(ctx context.Context, _, _ string, _ []string) error {
+					token, ok := store.ConsistencyTokenFromContext(ctx)
+					require.Equal(t, tt.expectToken, ok)
+					require.Equal(t, tt.expectedToken, token)
+					return nil
+				})
+
+			handler := NewExecutorHandler(testlogger.New(t), tt.newStore(mockStore), clock.NewMockedTimeSource(), shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
+			_, err := handler.Heartbeat(context.Background(), request)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHeartbeat_EncodedShardStatusReports(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+	now := time.Now().UTC()
+	reports := map[string]*types.ShardStatusReport{
+		"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 1.5},
+		"shard-2": {Status: types.ShardStatusREADY, ShardLoad: 3},
+	}
+	assigned := &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+		"shard-1": {Status: types.AssignmentStatusREADY},
+		"shard-2": {Status: types.AssignmentStatusREADY},
+	}}
+	cfg := newConfig(t, []configEntry{})
+
+	t.Run("encoded reports are decoded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSourceAt(now), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, assigned, nil)
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, store.HeartbeatState{
+			LastHeartbeat:  now,
+			Status:         types.ExecutorStatusACTIVE,
+			ReportedShards: reports,
+		})
+
+		_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+			Namespace:                 namespace,
+			ExecutorID:                executorID,
+			Status:                    types.ExecutorStatusACTIVE,
+			EncodedShardStatusReports: reportcodec.Encode(reports),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid encoded reports are rejected", func(t *testing.T) {
+		for name, request := range map[string]*types.ExecutorHeartbeatRequest{
+			"corrupt": {
+				Namespace:                 namespace,
+				ExecutorID:                executorID,
+				EncodedShardStatusReports: []byte{1, 5},
+			},
+			"both encodings": {
+				Namespace:                 namespace,
+				ExecutorID:                executorID,
+				ShardStatusReports:        reports,
+				EncodedShardStatusReports: reportcodec.Encode(reports),
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				handler := NewExecutorHandler(testlogger.New(t), store.NewMockStore(ctrl), clock.NewMockedTimeSourceAt(now), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+				_, err := handler.Heartbeat(context.Background(), request)
+				var badRequest types.BadRequestError
+				require.ErrorAs(t, err, &badRequest)
+			})
+		}
+	})
+}
+
+func TestHeartbeat_ReleasesDoneShards(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	newAssignedState := func() *store.AssignedState {
+		return &store.AssignedState{AssignedShards: map[string]*types.ShardAssignment{
+			"shard-1": {Status: types.AssignmentStatusREADY},
+			"shard-2": {Status: types.AssignmentStatusREADY},
+		}}
+	}
+	doneRequest := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusDONE},
+			"shard-2": {Status: types.ShardStatusREADY},
+		},
+	}
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+
+	t.Run("ephemeral namespace releases done shards once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+
+		assigned := newAssignedState()
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).
+			DoAndReturn(func(context.Context, string, string) (*store.HeartbeatState, *store.AssignedState, error) {
+				// The store returns a fresh copy of the assigned state on every read.
+				copied := &store.AssignedState{AssignedShards: maps.Clone(assigned.AssignedShards)}
+				return nil, copied, nil
+			}).Times(2)
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil).Times(2)
+		mockStore.EXPECT().ReleaseShards(gomock.Any(), namespace, executorID, []string{"shard-1"}).
+			DoAndReturn(func(_ context.Context, _, _ string, shardIDs []string) error {
+				for _, shardID := range shardIDs {
+					delete(assigned.AssignedShards, shardID)
+				}
+				return nil
+			}).Times(1)
+		// Once released, shard-1 is no longer assigned and is looked up as a reported shard.
+		mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, []string{"shard-1"}).Return(map[string]*store.ShardOwner{}, nil)
+
+		shardDistributionCfg := config.ShardDistribution{
+			Namespaces: []config.Namespace{{Name: namespace, Type: config.NamespaceTypeEphemeral}},
+		}
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
+
+		resp, err := handler.Heartbeat(context.Background(), doneRequest)
+		require.NoError(t, err)
+		require.NotContains(t, resp.ShardAssignments, "shard-1")
+		require.Contains(t, resp.ShardAssignments, "shard-2")
+		require.NotContains(t, assigned.AssignedShards, "shard-1")
+
+		// Reporting the shard as done again does not release it again.
+		resp, err = handler.Heartbeat(context.Background(), doneRequest)
+		require.NoError(t, err)
+		require.NotContains(t, resp.ShardAssignments, "shard-1")
+	})
+
+	t.Run("failed release keeps the shard assigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, newAssignedState(), nil)
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+		mockStore.EXPECT().ReleaseShards(gomock.Any(), namespace, executorID, []string{"shard-1"}).Return(store.ErrVersionConflict)
+
+		shardDistributionCfg := config.ShardDistribution{
+			Namespaces: []config.Namespace{{Name: namespace, Type: config.NamespaceTypeEphemeral}},
+		}
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
+
+		resp, err := handler.Heartbeat(context.Background(), doneRequest)
+		require.NoError(t, err)
+		require.Contains(t, resp.ShardAssignments, "shard-1")
+	})
+
+	t.Run("fixed namespace keeps done shards assigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, newAssignedState(), nil)
+		mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+
+		shardDistributionCfg := config.ShardDistribution{
+			Namespaces: []config.Namespace{{Name: namespace, Type: config.NamespaceTypeFixed}},
+		}
+		handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), shardDistributionCfg, cfg, metrics.NoopClient, events.NewNoop())
+
+		resp, err := handler.Heartbeat(context.Background(), doneRequest)
+		require.NoError(t, err)
+		require.Contains(t, resp.ShardAssignments, "shard-1")
+	})
+}
+
+func TestMergeShardStatusReports(t *testing.T) {
+	delta := map[string]*types.ShardStatusReport{"shard-1": {ShardLoad: 1}}
+	require.Equal(t, delta, mergeShardStatusReports(nil, delta), "no previous heartbeat")
+
+	previous := &store.HeartbeatState{ReportedShards: map[string]*types.ShardStatusReport{"shard-1": {ShardLoad: 3}, "shard-2": {ShardLoad: 2}}}
+	require.Equal(t, map[string]*types.ShardStatusReport{"shard-1": {ShardLoad: 1}, "shard-2": {ShardLoad: 2}}, mergeShardStatusReports(previous, delta))
+	require.Equal(t, 3.0, previous.ReportedShards["shard-1"].ShardLoad, "the previous reports are not modified")
+}
+
+func TestHeartbeat_DeduplicatesConcurrentHeartbeats(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+	dedupCounterName := "test.shard_distributor_heartbeat_deduplicated+namespace=test-namespace,operation=ExecutorHeartbeat"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).
+		DoAndReturn(func(context.Context, string, string) (*store.HeartbeatState, *store.AssignedState, error) {
+			close(started)
+			<-release
+			return nil, nil, store.ErrExecutorNotFound
+		}).Times(1)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil).Times(1)
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metricsClient, events.NewNoop())
+
+	req := &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusACTIVE,
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]*types.ExecutorHeartbeatResponse, 2)
+	errs := make([]error, 2)
+	heartbeat := func(i int) {
+		defer wg.Done()
+		responses[i], errs[i] = handler.Heartbeat(context.Background(), req)
+	}
+
+	wg.Add(2)
+	go heartbeat(0)
+	<-started
+	go heartbeat(1)
+
+	// Only release the store once the second heartbeat joined the first one.
+	require.Eventually(t, func() bool {
+		counter, ok := testScope.Snapshot().Counters()[dedupCounterName]
+		return ok && counter.Value() == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.Same(t, responses[0], responses[1])
+
+	// Once the first heartbeat completed, the next one is processed again.
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+	_, err := handler.Heartbeat(context.Background(), req)
+	require.NoError(t, err)
+}
+
+func TestHeartbeat_DoesNotCollapseDifferentHeartbeats(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	gomock.InOrder(
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).
+			DoAndReturn(func(context.Context, string, string) (*store.HeartbeatState, *store.AssignedState, error) {
+				close(started)
+				<-release
+				return nil, nil, store.ErrExecutorNotFound
+			}),
+		mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, store.ErrExecutorNotFound),
+	)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil).Times(2)
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	firstDone := make(chan error)
+	go func() {
+		_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+			Namespace:  namespace,
+			ExecutorID: executorID,
+			Status:     types.ExecutorStatusACTIVE,
+		})
+		firstDone <- err
+	}()
+	<-started
+
+	// The executor started draining, so this heartbeat carries a different report and is processed on its own.
+	_, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: executorID,
+		Status:     types.ExecutorStatusDRAINING,
+	})
+	require.NoError(t, err)
+
+	close(release)
+	require.NoError(t, <-firstDone)
+}
+
+func TestHeartbeat_NilStoredState(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	// The first heartbeat of a namespace finds no stored heartbeat nor assignment.
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, nil, nil)
+	mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, []string{"shard-1"}).Return(map[string]*store.ShardOwner{}, nil)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	resp, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:          namespace,
+		ExecutorID:         executorID,
+		Status:             types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{"shard-1": {Status: types.ShardStatusREADY}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.ShardAssignments)
+}
+
+func TestHeartbeat_RenewsShardLease(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	timeSource := clock.NewMockedTimeSource()
+
+	assignedState := &store.AssignedState{AssignedShards: makeReadyAssignedShards("shard-1")}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(nil, assignedState, nil).Times(2)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil).Times(2)
+
+	cfg := newConfig(t, []configEntry{
+		{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED},
+		{dynamicproperties.ShardDistributorShardLeaseDuration, 30 * time.Second},
+	})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, timeSource, config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+	request := &types.ExecutorHeartbeatRequest{Namespace: namespace, ExecutorID: executorID, Status: types.ExecutorStatusACTIVE}
+
+	resp, err := handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+	firstLease := resp.ShardAssignments["shard-1"].LeaseExpiresAt
+	require.Equal(t, timeSource.Now().UTC().Add(30*time.Second), firstLease)
+
+	timeSource.Advance(10 * time.Second)
+	resp, err = handler.Heartbeat(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, firstLease.Add(10*time.Second), resp.ShardAssignments["shard-1"].LeaseExpiresAt)
+
+	// The stored assignment must not be mutated by the lease renewal.
+	require.True(t, assignedState.AssignedShards["shard-1"].LeaseExpiresAt.IsZero())
 }
 
 func TestValidateMetadata(t *testing.T) {
@@ -325,10 +1613,13 @@ func TestValidateMetadata(t *testing.T) {
 }
 
 func TestConvertResponse(t *testing.T) {
+	leaseExpiresAt := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
 	testCases := []struct {
-		name         string
-		input        *store.AssignedState
-		expectedResp *types.ExecutorHeartbeatResponse
+		name           string
+		input          *store.AssignedState
+		leaseExpiresAt time.Time
+		expectedResp   *types.ExecutorHeartbeatResponse
 	}{
 		{
 			name:  "Nil input",
@@ -358,6 +1649,39 @@ func TestConvertResponse(t *testing.T) {
 				MigrationMode:    types.MigrationModeONBOARDED,
 			},
 		},
+		{
+			name: "Preserves assigned at",
+			input: &store.AssignedState{
+				AssignedShards: map[string]*types.ShardAssignment{
+					"shard-1": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					"shard-2": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			expectedResp: &types.ExecutorHeartbeatResponse{
+				ShardAssignments: map[string]*types.ShardAssignment{
+					"shard-1": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					"shard-2": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+				},
+				MigrationMode: types.MigrationModeONBOARDED,
+			},
+		},
+		{
+			name: "Sets lease expiry",
+			input: &store.AssignedState{
+				AssignedShards: map[string]*types.ShardAssignment{
+					"shard-1": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					"shard-2": {Status: types.AssignmentStatusREADY, LeaseExpiresAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			leaseExpiresAt: leaseExpiresAt,
+			expectedResp: &types.ExecutorHeartbeatResponse{
+				ShardAssignments: map[string]*types.ShardAssignment{
+					"shard-1": {Status: types.AssignmentStatusREADY, AssignedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), LeaseExpiresAt: leaseExpiresAt},
+					"shard-2": {Status: types.AssignmentStatusREADY, LeaseExpiresAt: leaseExpiresAt},
+				},
+				MigrationMode: types.MigrationModeONBOARDED,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -367,7 +1691,7 @@ func TestConvertResponse(t *testing.T) {
 			if tc.expectedResp.ShardAssignments == nil {
 				tc.expectedResp.ShardAssignments = make(map[string]*types.ShardAssignment)
 			}
-			res := _convertResponse(tc.input, types.MigrationModeONBOARDED)
+			res := _convertResponse(tc.input, types.MigrationModeONBOARDED, tc.leaseExpiresAt)
 
 			// Ensure ShardAssignments is not nil for comparison purposes
 			if res.ShardAssignments == nil {
@@ -378,6 +1702,68 @@ func TestConvertResponse(t *testing.T) {
 	}
 }
 
+func TestToDeltaResponse(t *testing.T) {
+	res := &types.ExecutorHeartbeatResponse{
+		ShardAssignments: makeReadyAssignedShards("shard-1", "shard-2", "shard-3"),
+		MigrationMode:    types.MigrationModeONBOARDED,
+		ReasonCode:       types.HeartbeatReasonCodeACCEPTED,
+	}
+	// The executor runs shard-1 and shard-4, shard-4 has moved away and is reported DONE.
+	reported := map[string]*types.ShardStatusReport{
+		"shard-1": {Status: types.ShardStatusREADY},
+		"shard-4": {Status: types.ShardStatusDONE},
+	}
+
+	delta := _toDeltaResponse(res, reported)
+
+	require.Equal(t, &types.ExecutorHeartbeatResponse{
+		MigrationMode: types.MigrationModeONBOARDED,
+		ReasonCode:    types.HeartbeatReasonCodeACCEPTED,
+		ShardsToStart: []string{"shard-2", "shard-3"},
+		ShardsToStop:  []string{"shard-4"},
+	}, delta)
+	require.Len(t, res.ShardAssignments, 3, "the full response is not modified")
+
+	require.Empty(t, _toDeltaResponse(res, map[string]*types.ShardStatusReport{
+		"shard-1": {}, "shard-2": {}, "shard-3": {},
+	}).ShardsToStart, "nothing to start when the executor runs its assignment")
+}
+
+func TestHeartbeat_DeltaResponse(t *testing.T) {
+	const namespace = "test-namespace"
+	const executorID = "test-executor"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+
+	previousHeartbeat := store.HeartbeatState{
+		Status:         types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{"shard-1": {Status: types.ShardStatusREADY}},
+	}
+	assignedState := store.AssignedState{AssignedShards: makeReadyAssignedShards("shard-2")}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, executorID).Return(&previousHeartbeat, &assignedState, nil)
+	mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, []string{"shard-1"}).
+		Return(map[string]*store.ShardOwner{"shard-1": {ExecutorID: "other-executor"}}, nil)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, executorID, gomock.Any()).Return(nil)
+
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSource(), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	// shard-1 was moved to another executor and shard-2 assigned instead.
+	res, err := handler.Heartbeat(context.Background(), &types.ExecutorHeartbeatRequest{
+		Namespace:          namespace,
+		ExecutorID:         executorID,
+		Status:             types.ExecutorStatusACTIVE,
+		ShardStatusReports: map[string]*types.ShardStatusReport{"shard-1": {Status: types.ShardStatusREADY}},
+		DeltaResponse:      true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, res.ShardAssignments)
+	require.Equal(t, []string{"shard-2"}, res.ShardsToStart)
+	require.Equal(t, []string{"shard-1"}, res.ShardsToStop)
+	require.Equal(t, types.HeartbeatReasonCodeACCEPTED, res.ReasonCode)
+}
+
 type configEntry struct {
 	key   dynamicproperties.Key
 	value interface{}
@@ -26,15 +26,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -59,12 +65,17 @@ func NewHandler(
 	shardDistributionCfg config.ShardDistribution,
 	cfg *config.Config,
 	storage store.Store,
+	metricsClient metrics.Client,
+	leaders Leaders,
 ) Handler {
 	handler := &handlerImpl{
 		logger:               logger,
+		timeSource:           timeSource,
 		shardDistributionCfg: shardDistributionCfg,
 		cfg:                  cfg,
 		storage:              storage,
+		metricsClient:        metricsClient,
+		leaders:              leaders,
 	}
 
 	handler.batcher = newShardBatcher(timeSource, ephemeralBatchInterval, handler.assignEphemeralBatch)
@@ -75,13 +86,16 @@ func NewHandler(
 }
 
 type handlerImpl struct {
-	logger log.Logger
+	logger     log.Logger
+	timeSource clock.TimeSource
 
 	startWG sync.WaitGroup
 
 	storage              store.Store
 	shardDistributionCfg config.ShardDistribution
 	cfg                  *config.Config
+	metricsClient        metrics.Client
+	leaders              Leaders
 
 	batcher *shardBatcher
 }
@@ -187,6 +201,184 @@ func (h *handlerImpl) getOrAssignEphemeralShard(ctx context.Context, request *ty
 	return resp, nil
 }
 
+// PlanExecutorRemoval previews the decommission of an executor: it returns where each of the executor's
+// shards would be placed if it were removed, without applying anything. It is not exposed over RPC.
+func (h *handlerImpl) PlanExecutorRemoval(ctx context.Context, namespace, executorID string) ([]plan.Move, error) {
+	state, err := h.storage.GetState(ctx, namespace)
+	if err != nil {
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("get namespace state: %v", err)}
+	}
+
+	moves, err := loadbalancer.PlanExecutorRemoval(h.cfg, namespace, state, executorID, h.timeSource.Now())
+	if errors.Is(err, store.ErrExecutorNotFound) {
+		return nil, &types.BadRequestError{Message: fmt.Sprintf("executor %q not found in namespace %q", executorID, namespace)}
+	}
+	if err != nil {
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("plan executor removal: %v", err)}
+	}
+	return moves, nil
+}
+
+// ForceAssign moves shards to the executors operators chose, overriding the balancer. assignments maps
+// shard IDs to their target executor. Every target must be a known executor that can own shards, and in a
+// fixed namespace every shard one of its configured shards, otherwise nothing is assigned. Only the leader of the namespace can force assignments: the assignments are written
+// in one store operation fenced by its election, and the store records the move of each shard so the
+// balancer leaves it alone for the per-shard cooldown. It is not exposed over RPC.
+func (h *handlerImpl) ForceAssign(ctx context.Context, namespace string, assignments map[string]string) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	namespaceIdx := slices.IndexFunc(h.shardDistributionCfg.Namespaces, func(namespaceCfg config.Namespace) bool {
+		return namespaceCfg.Name == namespace
+	})
+	if namespaceIdx != -1 && h.shardDistributionCfg.Namespaces[namespaceIdx].Type == config.NamespaceTypeFixed {
+		shardNum := h.shardDistributionCfg.Namespaces[namespaceIdx].ShardNum
+		var unknown []string
+		for _, shardID := range slices.Sorted(maps.Keys(assignments)) {
+			if !isFixedShardID(shardID, shardNum) {
+				unknown = append(unknown, shardID)
+			}
+		}
+		if len(unknown) > 0 {
+			return &types.BadRequestError{Message: fmt.Sprintf("invalid forced assignment in namespace %q: shards %q are not among its %d shards", namespace, unknown, shardNum)}
+		}
+	}
+
+	guard, ok := h.leaders.LeaderGuard(namespace)
+	if !ok {
+		return &types.ServiceBusyError{Message: fmt.Sprintf("this host does not lead namespace %q", namespace)}
+	}
+
+	state, err := h.storage.GetState(ctx, namespace)
+	if err != nil {
+		return &types.InternalServiceError{Message: fmt.Sprintf("get namespace state: %v", err)}
+	}
+	if state == nil {
+		state = &store.NamespaceState{}
+	}
+
+	var invalid []string
+	for _, shardID := range slices.Sorted(maps.Keys(assignments)) {
+		executorID := assignments[shardID]
+		executor, ok := state.Executors[executorID]
+		switch {
+		case !ok:
+			invalid = append(invalid, fmt.Sprintf("shard %q: executor %q not found", shardID, executorID))
+		case !executor.CanOwnShards():
+			invalid = append(invalid, fmt.Sprintf("shard %q: executor %q cannot own shards in status %s", shardID, executorID, executor.Status))
+		}
+	}
+	if len(invalid) > 0 {
+		return &types.BadRequestError{Message: fmt.Sprintf("invalid forced assignment in namespace %q: %s", namespace, strings.Join(invalid, "; "))}
+	}
+
+	newState := withForcedAssignments(state, assignments, h.timeSource.Now().UTC())
+	if err := h.storage.AssignShards(ctx, namespace, store.AssignShardsRequest{NewState: newState}, guard); err != nil {
+		return &types.InternalServiceError{Message: fmt.Sprintf("force assign shards: %v", err)}
+	}
+	return nil
+}
+
+// GetShardLoadHistory returns the downsampled smoothed load history of a shard, oldest first, for capacity
+// planning. The history is only recorded when a load history retention is configured for the namespace, and
+// is empty for shards without statistics. It is not exposed over RPC.
+func (h *handlerImpl) GetShardLoadHistory(ctx context.Context, namespace, shardID string) (store.LoadSamples, error) {
+	shardStats, err := h.storage.GetShardStats(ctx, namespace, []string{shardID})
+	if err != nil {
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("get shard statistics: %v", err)}
+	}
+	return shardStats[shardID].LoadHistory, nil
+}
+
+// ExplainUnassigned returns why a shard of the namespace is not assigned to any executor. For a fixed namespace
+// it is the reason the leader recorded for the shard in its last rebalance pass, or for the pass as a whole, so
+// only the host leading the namespace answers. For an ephemeral namespace it is the reason placement gives for the shard against the current
+// state. Shards that are assigned are rejected. It is not exposed over RPC.
+func (h *handlerImpl) ExplainUnassigned(ctx context.Context, namespace, shardID string) (plan.UnassignedReason, error) {
+	namespaceIdx := slices.IndexFunc(h.shardDistributionCfg.Namespaces, func(namespaceCfg config.Namespace) bool {
+		return namespaceCfg.Name == namespace
+	})
+	if namespaceIdx == -1 {
+		return "", &types.NamespaceNotFoundError{Namespace: namespace}
+	}
+
+	owner, err := h.storage.GetShardOwner(ctx, namespace, shardID)
+	if err == nil {
+		return "", &types.BadRequestError{Message: fmt.Sprintf("shard %q is assigned to executor %q", shardID, owner.ExecutorID)}
+	}
+	if !errors.Is(err, store.ErrShardNotFound) {
+		return "", &types.InternalServiceError{Message: fmt.Sprintf("get shard owner: %v", err)}
+	}
+
+	if h.shardDistributionCfg.Namespaces[namespaceIdx].Type != config.NamespaceTypeEphemeral {
+		reason, ok := h.leaders.UnassignedReason(namespace, shardID)
+		if !ok {
+			return "", &types.ServiceBusyError{Message: fmt.Sprintf("this host does not lead namespace %q", namespace)}
+		}
+		return reason, nil
+	}
+
+	state, err := h.storage.GetState(ctx, namespace)
+	if err != nil {
+		return "", &types.InternalServiceError{Message: fmt.Sprintf("get namespace state: %v", err)}
+	}
+	if state == nil {
+		state = &store.NamespaceState{}
+	}
+	_, unplaced, err := loadbalancer.PlanInitialPlacement(h.cfg, namespace, state, []string{shardID}, h.timeSource.Now())
+	if errors.Is(err, plan.ErrNoActiveExecutors) {
+		return plan.UnassignedReasonNoActiveExecutors, nil
+	}
+	if err != nil {
+		return "", &types.InternalServiceError{Message: fmt.Sprintf("plan initial placement: %v", err)}
+	}
+	if reason, ok := unplaced[shardID]; ok {
+		return reason, nil
+	}
+	return plan.UnassignedReasonPending, nil
+}
+
+// isFixedShardID reports whether shardID is one of the shardNum shards of a fixed namespace, which the leader
+// names "0" to shardNum-1.
+func isFixedShardID(shardID string, shardNum int64) bool {
+	id, err := strconv.ParseInt(shardID, 10, 64)
+	return err == nil && id >= 0 && id < shardNum && strconv.FormatInt(id, 10) == shardID
+}
+
+// withForcedAssignments returns a copy of state with every shard in assignments owned by its target executor.
+// Shards that change owner are stamped with now as their assignment time. state is not modified.
+func withForcedAssignments(state *store.NamespaceState, assignments map[string]string, now time.Time) *store.NamespaceState {
+	newState := *state
+	newState.ShardAssignments = make(map[string]store.AssignedState, len(state.ShardAssignments))
+	owners := make(map[string]string)
+	for executorID, assigned := range state.ShardAssignments {
+		assigned.AssignedShards = maps.Clone(assigned.AssignedShards)
+		newState.ShardAssignments[executorID] = assigned
+		for shardID := range assigned.AssignedShards {
+			owners[shardID] = executorID
+		}
+	}
+
+	for shardID, executorID := range assignments {
+		owner, assigned := owners[shardID]
+		if assigned && owner == executorID {
+			continue
+		}
+		if assigned {
+			delete(newState.ShardAssignments[owner].AssignedShards, shardID)
+		}
+
+		target := newState.ShardAssignments[executorID]
+		if target.AssignedShards == nil {
+			target.AssignedShards = make(map[string]*types.ShardAssignment)
+		}
+		target.AssignedShards[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: now}
+		newState.ShardAssignments[executorID] = target
+	}
+	return &newState
+}
+
 func (h *handlerImpl) WatchNamespaceState(request *types.WatchNamespaceStateRequest, server WatchNamespaceStateServer) error {
 	h.startWG.Wait()
 
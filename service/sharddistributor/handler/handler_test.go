@@ -35,8 +35,10 @@ import (
 
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log/testlogger"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -50,13 +52,16 @@ const (
 // ready to use; callers should call Stop() when done.
 func newTestHandler(t *testing.T, cfg config.ShardDistribution, mockStore *store.MockStore) *handlerImpl {
 	t.Helper()
+	timeSource := clock.NewRealTimeSource()
 	handler := &handlerImpl{
 		logger:               testlogger.New(t),
+		timeSource:           timeSource,
 		shardDistributionCfg: cfg,
 		cfg:                  newTestShardDistributorConfig(config.LoadBalancingModeNAIVE),
 		storage:              mockStore,
+		metricsClient:        metrics.NoopClient,
 	}
-	handler.batcher = newShardBatcher(clock.NewRealTimeSource(), 10*time.Millisecond, handler.assignEphemeralBatch)
+	handler.batcher = newShardBatcher(timeSource, 10*time.Millisecond, handler.assignEphemeralBatch)
 	handler.batcher.Start()
 	t.Cleanup(handler.batcher.Stop)
 	return handler
@@ -329,3 +334,409 @@ func TestWatchNamespaceState(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorIs(t, err, context.Canceled)
 }
+
+func TestPlanExecutorRemoval(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{
+				"shard-1": {Status: types.AssignmentStatusREADY},
+				"shard-2": {Status: types.AssignmentStatusREADY},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		executorID    string
+		getStateErr   error
+		expectedMoves []plan.Move
+		expectedErr   error
+	}{
+		{
+			name:       "Success",
+			executorID: "exec-1",
+			expectedMoves: []plan.Move{
+				{ShardID: "shard-1", From: "exec-1", To: "exec-2"},
+				{ShardID: "shard-2", From: "exec-1", To: "exec-2"},
+			},
+		},
+		{
+			name:        "UnknownExecutor",
+			executorID:  "exec-3",
+			expectedErr: &types.BadRequestError{},
+		},
+		{
+			name:        "GetStateError",
+			executorID:  "exec-1",
+			getStateErr: errors.New("storage down"),
+			expectedErr: &types.InternalServiceError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			handler := newTestHandler(t, config.ShardDistribution{}, mockStore)
+
+			mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceFixed).Return(state, tt.getStateErr)
+
+			moves, err := handler.PlanExecutorRemoval(context.Background(), _testNamespaceFixed, tt.executorID)
+			if tt.expectedErr != nil {
+				require.IsType(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedMoves, moves)
+		})
+	}
+}
+
+func TestForceAssign(t *testing.T) {
+	newState := func() *store.NamespaceState {
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"exec-1": {Status: types.ExecutorStatusACTIVE},
+				"exec-2": {Status: types.ExecutorStatusACTIVE},
+				"exec-3": {Status: types.ExecutorStatusDRAINING},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"exec-1": {AssignedShards: map[string]*types.ShardAssignment{
+					"shard-1": {Status: types.AssignmentStatusREADY},
+					"shard-2": {Status: types.AssignmentStatusREADY},
+				}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"shard-1": {SmoothedLoad: 3},
+			},
+		}
+	}
+
+	// errLeaderGuard tells the guard of the leader apart from any other guard.
+	errLeaderGuard := errors.New("leader guard")
+	leaderGuard := func(store.Txn) (store.Txn, error) { return nil, errLeaderGuard }
+
+	tests := []struct {
+		name        string
+		assignments map[string]string
+		notLeader   bool
+		getStateErr error
+		assignErr   error
+		expectedErr error
+	}{
+		{
+			name:        "NotLeader",
+			assignments: map[string]string{"shard-1": "exec-2"},
+			notLeader:   true,
+			expectedErr: &types.ServiceBusyError{},
+		},
+		{
+			name:        "UnknownExecutor",
+			assignments: map[string]string{"shard-1": "exec-4"},
+			expectedErr: &types.BadRequestError{},
+		},
+		{
+			name:        "DrainingExecutor",
+			assignments: map[string]string{"shard-1": "exec-2", "shard-2": "exec-3"},
+			expectedErr: &types.BadRequestError{},
+		},
+		{
+			name:        "GetStateError",
+			assignments: map[string]string{"shard-1": "exec-2"},
+			getStateErr: errors.New("storage down"),
+			expectedErr: &types.InternalServiceError{},
+		},
+		{
+			name:        "AssignShardsError",
+			assignments: map[string]string{"shard-1": "exec-2"},
+			assignErr:   errors.New("version conflict"),
+			expectedErr: &types.InternalServiceError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			leaders := NewMockLeaders(ctrl)
+			handler := newTestHandler(t, config.ShardDistribution{}, mockStore)
+			handler.leaders = leaders
+
+			leaders.EXPECT().LeaderGuard(_testNamespaceFixed).Return(store.GuardFunc(leaderGuard), !tt.notLeader)
+			if tt.notLeader {
+				err := handler.ForceAssign(context.Background(), _testNamespaceFixed, tt.assignments)
+				require.IsType(t, tt.expectedErr, err)
+				return
+			}
+
+			mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceFixed).Return(newState(), tt.getStateErr)
+			if tt.assignErr != nil {
+				mockStore.EXPECT().AssignShards(gomock.Any(), _testNamespaceFixed, gomock.Any(), gomock.Any()).Return(tt.assignErr)
+			}
+
+			err := handler.ForceAssign(context.Background(), _testNamespaceFixed, tt.assignments)
+			require.IsType(t, tt.expectedErr, err)
+		})
+	}
+
+	t.Run("MovesShardUnderLeaderGuard", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+		leaders := NewMockLeaders(ctrl)
+		handler := newTestHandler(t, config.ShardDistribution{}, mockStore)
+		handler.leaders = leaders
+		state := newState()
+
+		leaders.EXPECT().LeaderGuard(_testNamespaceFixed).Return(store.GuardFunc(leaderGuard), true)
+		mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceFixed).Return(state, nil)
+		var request store.AssignShardsRequest
+		mockStore.EXPECT().AssignShards(gomock.Any(), _testNamespaceFixed, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, req store.AssignShardsRequest, guard store.GuardFunc) error {
+				_, err := guard(nil)
+				require.ErrorIs(t, err, errLeaderGuard, "the assignments must be fenced by the leader guard")
+				request = req
+				return nil
+			})
+
+		err := handler.ForceAssign(context.Background(), _testNamespaceFixed, map[string]string{"shard-1": "exec-2", "shard-2": "exec-1"})
+		require.NoError(t, err)
+
+		assignments := request.NewState.ShardAssignments
+		require.Contains(t, assignments["exec-2"].AssignedShards, "shard-1")
+		require.NotContains(t, assignments["exec-1"].AssignedShards, "shard-1")
+		require.Contains(t, assignments["exec-1"].AssignedShards, "shard-2")
+
+		require.Contains(t, state.ShardAssignments["exec-1"].AssignedShards, "shard-1", "the read state must not be modified")
+	})
+
+	t.Run("RejectsUnknownShardsOfFixedNamespace", func(t *testing.T) {
+		cfg := config.ShardDistribution{
+			Namespaces: []config.Namespace{{Name: _testNamespaceFixed, Type: config.NamespaceTypeFixed, ShardNum: 4}},
+		}
+		for _, shardID := range []string{"shard-1", "4", "-1", "01", ""} {
+			ctrl := gomock.NewController(t)
+			handler := newTestHandler(t, cfg, store.NewMockStore(ctrl))
+			handler.leaders = NewMockLeaders(ctrl)
+
+			err := handler.ForceAssign(context.Background(), _testNamespaceFixed, map[string]string{"0": "exec-1", shardID: "exec-2"})
+			require.IsType(t, &types.BadRequestError{}, err, "shard %q", shardID)
+			require.ErrorContains(t, err, fmt.Sprintf("%q", shardID))
+		}
+	})
+
+	t.Run("AcceptsShardsOfFixedNamespace", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := store.NewMockStore(ctrl)
+		leaders := NewMockLeaders(ctrl)
+		cfg := config.ShardDistribution{
+			Namespaces: []config.Namespace{{Name: _testNamespaceFixed, Type: config.NamespaceTypeFixed, ShardNum: 4}},
+		}
+		handler := newTestHandler(t, cfg, mockStore)
+		handler.leaders = leaders
+
+		leaders.EXPECT().LeaderGuard(_testNamespaceFixed).Return(store.GuardFunc(leaderGuard), true)
+		mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceFixed).Return(newState(), nil)
+		mockStore.EXPECT().AssignShards(gomock.Any(), _testNamespaceFixed, gomock.Any(), gomock.Any()).Return(nil)
+
+		err := handler.ForceAssign(context.Background(), _testNamespaceFixed, map[string]string{"0": "exec-1", "3": "exec-2"})
+		require.NoError(t, err)
+	})
+}
+
+func TestGetShardLoadHistory(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := store.LoadSamples{
+		{Load: 2, ReportedAt: now},
+		{Load: 3, ReportedAt: now.Add(time.Minute)},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	handler := newTestHandler(t, config.ShardDistribution{}, mockStore)
+
+	// Only the statistics of the requested shard are read, not the whole namespace state.
+	mockStore.EXPECT().GetShardStats(gomock.Any(), _testNamespaceFixed, []string{"shard-1"}).Return(map[string]store.ShardStatistics{
+		"shard-1": {SmoothedLoad: 3, LoadHistory: history},
+	}, nil)
+	mockStore.EXPECT().GetShardStats(gomock.Any(), _testNamespaceFixed, []string{"shard-2"}).Return(map[string]store.ShardStatistics{}, nil)
+
+	got, err := handler.GetShardLoadHistory(context.Background(), _testNamespaceFixed, "shard-1")
+	require.NoError(t, err)
+	require.Equal(t, history, got)
+
+	got, err = handler.GetShardLoadHistory(context.Background(), _testNamespaceFixed, "shard-2")
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	mockStore.EXPECT().GetShardStats(gomock.Any(), _testNamespaceFixed, []string{"shard-1"}).Return(nil, errors.New("storage down"))
+	_, err = handler.GetShardLoadHistory(context.Background(), _testNamespaceFixed, "shard-1")
+	require.IsType(t, &types.InternalServiceError{}, err)
+}
+
+func TestExplainUnassigned(t *testing.T) {
+	cfg := config.ShardDistribution{
+		Namespaces: []config.Namespace{
+			{Name: _testNamespaceFixed, Type: config.NamespaceTypeFixed},
+			{Name: _testNamespaceEphemeral, Type: config.NamespaceTypeEphemeral},
+		},
+	}
+	activeExecutor := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": {Status: types.ExecutorStatusACTIVE}},
+	}
+	fullExecutor := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": {Status: types.ExecutorStatusACTIVE}},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"shard-0": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{"shard-0": {SmoothedLoad: 12}},
+	}
+
+	type testCase struct {
+		name                 string
+		namespace            string
+		pinnedShards         map[string]interface{}
+		labelSelectors       map[string]interface{}
+		executorLoadCapacity float64
+		setupMocks           func(mockStore *store.MockStore, leaders *MockLeaders)
+		expectedReason       plan.UnassignedReason
+		expectedErr          error
+	}
+	tests := []testCase{
+		{
+			name:        "NamespaceNotFound",
+			namespace:   "unknown",
+			setupMocks:  func(mockStore *store.MockStore, leaders *MockLeaders) {},
+			expectedErr: &types.NamespaceNotFoundError{},
+		},
+		{
+			name:      "ShardAssigned",
+			namespace: _testNamespaceFixed,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceFixed, "shard-1").Return(&store.ShardOwner{ExecutorID: "exec-1"}, nil)
+			},
+			expectedErr: &types.BadRequestError{},
+		},
+		{
+			name:      "GetShardOwnerError",
+			namespace: _testNamespaceFixed,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceFixed, "shard-1").Return(nil, errors.New("storage down"))
+			},
+			expectedErr: &types.InternalServiceError{},
+		},
+		{
+			name:      "FixedNotLeader",
+			namespace: _testNamespaceFixed,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceFixed, "shard-1").Return(nil, store.ErrShardNotFound)
+				leaders.EXPECT().UnassignedReason(_testNamespaceFixed, "shard-1").Return(plan.UnassignedReason(""), false)
+			},
+			expectedErr: &types.ServiceBusyError{},
+		},
+		{
+			name:      "GetStateError",
+			namespace: _testNamespaceEphemeral,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(nil, errors.New("storage down"))
+			},
+			expectedErr: &types.InternalServiceError{},
+		},
+		{
+			name:      "EphemeralNoActiveExecutors",
+			namespace: _testNamespaceEphemeral,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(nil, nil)
+			},
+			expectedReason: plan.UnassignedReasonNoActiveExecutors,
+		},
+		{
+			name:         "EphemeralPinned",
+			namespace:    _testNamespaceEphemeral,
+			pinnedShards: map[string]interface{}{"shard-1": "exec-2"},
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(activeExecutor, nil)
+			},
+			expectedReason: plan.UnassignedReasonPinned,
+		},
+		{
+			name:           "EphemeralNoMatchingExecutor",
+			namespace:      _testNamespaceEphemeral,
+			labelSelectors: map[string]interface{}{"shard-1": "gpu=true"},
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(activeExecutor, nil)
+			},
+			expectedReason: plan.UnassignedReasonNoMatchingExecutor,
+		},
+		{
+			name:                 "EphemeralAtCapacity",
+			namespace:            _testNamespaceEphemeral,
+			executorLoadCapacity: 10,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(fullExecutor, nil)
+			},
+			expectedReason: plan.UnassignedReasonAtCapacity,
+		},
+		{
+			name:      "EphemeralPending",
+			namespace: _testNamespaceEphemeral,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceEphemeral, "shard-1").Return(nil, store.ErrShardNotFound)
+				mockStore.EXPECT().GetState(gomock.Any(), _testNamespaceEphemeral).Return(activeExecutor, nil)
+			},
+			expectedReason: plan.UnassignedReasonPending,
+		},
+	}
+	// The leader of a fixed namespace records the reason of each shard it leaves out of a rebalance pass, and
+	// the outcome of the pass for the other shards.
+	for _, reason := range []plan.UnassignedReason{
+		plan.UnassignedReasonRebalancePaused,
+		plan.UnassignedReasonNoExecutors,
+		plan.UnassignedReasonAllExecutorsDraining,
+		plan.UnassignedReasonNoActiveExecutors,
+		plan.UnassignedReasonNoMatchingExecutor,
+		plan.UnassignedReasonInCooldown,
+		plan.UnassignedReasonShadowMode,
+		plan.UnassignedReasonPending,
+	} {
+		tests = append(tests, testCase{
+			name:      "Fixed " + string(reason),
+			namespace: _testNamespaceFixed,
+			setupMocks: func(mockStore *store.MockStore, leaders *MockLeaders) {
+				mockStore.EXPECT().GetShardOwner(gomock.Any(), _testNamespaceFixed, "shard-1").Return(nil, store.ErrShardNotFound)
+				leaders.EXPECT().UnassignedReason(_testNamespaceFixed, "shard-1").Return(reason, true)
+			},
+			expectedReason: reason,
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := store.NewMockStore(ctrl)
+			leaders := NewMockLeaders(ctrl)
+			handler := newTestHandler(t, cfg, mockStore)
+			handler.leaders = leaders
+			handler.cfg.PinnedShards = func(string) map[string]interface{} { return tt.pinnedShards }
+			handler.cfg.ShardLabelSelectors = func(string) map[string]interface{} { return tt.labelSelectors }
+			handler.cfg.ExecutorLoadCapacity = func(string) float64 { return tt.executorLoadCapacity }
+			tt.setupMocks(mockStore, leaders)
+
+			reason, err := handler.ExplainUnassigned(context.Background(), tt.namespace, "shard-1")
+			if tt.expectedErr != nil {
+				require.IsType(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedReason, reason)
+		})
+	}
+}
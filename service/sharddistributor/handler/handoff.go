@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// A controlled handoff moves a shard from its previous owner to its new owner over several heartbeats,
+// so that at every step at least one of them serves the shard:
+//
+//	step | previous owner                       | new owner
+//	1    | PREPARING, keeps serving             | PREPARING, loads the shard
+//	2    | PREPARING, keeps serving             | READY once it reported PREPARING
+//	3    | DRAINING once the new owner is READY | READY, serves the shard
+//	4    | stopped once it reported DONE        | READY
+//
+// The leader already assigned the shard to the new owner, so the previous owner is only known from its
+// own reports of a shard it is no longer assigned.
+
+// newOwnerAssignmentStatus returns the status of a shard assigned to an executor that reported it as
+// given. A shard the executor has not reported yet is prepared before it is served.
+func newOwnerAssignmentStatus(report *types.ShardStatusReport) types.AssignmentStatus {
+	if report.GetStatus() == types.ShardStatusINVALID {
+		return types.AssignmentStatusPREPARING
+	}
+	return types.AssignmentStatusREADY
+}
+
+// previousOwnerAssignmentStatus returns the status of a shard its previous owner reported as given, while
+// the new owner reported it as newOwnerReport. It returns false once the previous owner reported the shard
+// DONE, the shard is then no longer returned to it.
+func previousOwnerAssignmentStatus(report, newOwnerReport *types.ShardStatusReport) (types.AssignmentStatus, bool) {
+	if report.GetStatus() == types.ShardStatusDONE {
+		return types.AssignmentStatusINVALID, false
+	}
+	if newOwnerReport.GetStatus() == types.ShardStatusREADY {
+		return types.AssignmentStatusDRAINING, true
+	}
+	return types.AssignmentStatusPREPARING, true
+}
+
+// withHandoffs returns res with the shards the executor takes over or hands off set to their step of the
+// controlled handoff, when it is enabled for the namespace. The assignments of res are not modified.
+func (h *executor) withHandoffs(ctx context.Context, namespace, executorID string, reportedShards map[string]*types.ShardStatusReport, res *types.ExecutorHeartbeatResponse) *types.ExecutorHeartbeatResponse {
+	if !h.cfg.IsControlledHandoffEnabled(namespace) {
+		return res
+	}
+
+	assignments := make(map[string]*types.ShardAssignment, len(res.ShardAssignments))
+	for shardID, assignment := range res.ShardAssignments {
+		if assignment == nil {
+			continue
+		}
+		takeover := *assignment
+		takeover.Status = newOwnerAssignmentStatus(reportedShards[shardID])
+		assignments[shardID] = &takeover
+	}
+	for shardID, report := range reportedShards {
+		if _, ok := res.ShardAssignments[shardID]; ok {
+			continue
+		}
+		newOwnerReport, ok := h.newOwnerReport(ctx, namespace, executorID, shardID)
+		if !ok {
+			continue
+		}
+		if status, ok := previousOwnerAssignmentStatus(report, newOwnerReport); ok {
+			assignments[shardID] = &types.ShardAssignment{Status: status}
+		}
+	}
+
+	coordinated := *res
+	coordinated.ShardAssignments = assignments
+	return &coordinated
+}
+
+// newOwnerReport returns the last report of the shard by the executor it is now assigned to, which is nil
+// while that executor did not report it. It returns false when the shard is not handed off by executorID,
+// because it is not assigned to another executor. A failed lookup keeps the handoff going, so the shard
+// keeps being served by executorID.
+func (h *executor) newOwnerReport(ctx context.Context, namespace, executorID, shardID string) (*types.ShardStatusReport, bool) {
+	owner, err := h.storage.GetShardOwner(ctx, namespace, shardID)
+	if errors.Is(err, store.ErrShardNotFound) {
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Warn("Failed to look up new owner of handed off shard", tag.ShardNamespace(namespace), tag.ShardKey(shardID), tag.Error(err))
+		return nil, true
+	}
+	if owner.ExecutorID == executorID {
+		return nil, false
+	}
+
+	heartbeat, _, err := h.storage.GetHeartbeat(ctx, namespace, owner.ExecutorID)
+	if err != nil {
+		if !errors.Is(err, store.ErrExecutorNotFound) {
+			h.logger.Warn("Failed to read heartbeat of new owner of handed off shard", tag.ShardNamespace(namespace), tag.ShardKey(shardID), tag.Error(err))
+		}
+		return nil, true
+	}
+	if heartbeat == nil {
+		return nil, true
+	}
+	return heartbeat.ReportedShards[shardID], true
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig/dynamicproperties"
+	"github.com/uber/cadence/common/log/testlogger"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestNewOwnerAssignmentStatus(t *testing.T) {
+	assert.Equal(t, types.AssignmentStatusPREPARING, newOwnerAssignmentStatus(nil))
+	assert.Equal(t, types.AssignmentStatusREADY, newOwnerAssignmentStatus(&types.ShardStatusReport{Status: types.ShardStatusPREPARING}))
+	assert.Equal(t, types.AssignmentStatusREADY, newOwnerAssignmentStatus(&types.ShardStatusReport{Status: types.ShardStatusREADY}))
+}
+
+func TestPreviousOwnerAssignmentStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         *types.ShardStatusReport
+		newOwnerReport *types.ShardStatusReport
+		wantStatus     types.AssignmentStatus
+		wantOK         bool
+	}{
+		{
+			name:       "new owner did not report",
+			report:     &types.ShardStatusReport{Status: types.ShardStatusREADY},
+			wantStatus: types.AssignmentStatusPREPARING,
+			wantOK:     true,
+		},
+		{
+			name:           "new owner preparing",
+			report:         &types.ShardStatusReport{Status: types.ShardStatusREADY},
+			newOwnerReport: &types.ShardStatusReport{Status: types.ShardStatusPREPARING},
+			wantStatus:     types.AssignmentStatusPREPARING,
+			wantOK:         true,
+		},
+		{
+			name:           "new owner ready",
+			report:         &types.ShardStatusReport{Status: types.ShardStatusREADY},
+			newOwnerReport: &types.ShardStatusReport{Status: types.ShardStatusREADY},
+			wantStatus:     types.AssignmentStatusDRAINING,
+			wantOK:         true,
+		},
+		{
+			name:           "previous owner done",
+			report:         &types.ShardStatusReport{Status: types.ShardStatusDONE},
+			newOwnerReport: &types.ShardStatusReport{Status: types.ShardStatusREADY},
+			wantStatus:     types.AssignmentStatusINVALID,
+			wantOK:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ok := previousOwnerAssignmentStatus(tt.report, tt.newOwnerReport)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestHeartbeat_ControlledHandoff(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-namespace"
+	shardID := "shard-1"
+	previousOwner := "exec-old"
+	newOwner := "exec-new"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	heartbeats := map[string]store.HeartbeatState{
+		previousOwner: {Status: types.ExecutorStatusACTIVE},
+		newOwner:      {Status: types.ExecutorStatusACTIVE},
+	}
+	assigned := map[string]*store.AssignedState{
+		previousOwner: {AssignedShards: map[string]*types.ShardAssignment{}},
+		newOwner: {AssignedShards: map[string]*types.ShardAssignment{
+			shardID: {Status: types.AssignmentStatusREADY},
+		}},
+	}
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, executorID string) (*store.HeartbeatState, *store.AssignedState, error) {
+			heartbeat := heartbeats[executorID]
+			return &heartbeat, assigned[executorID], nil
+		}).AnyTimes()
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, executorID string, state store.HeartbeatState) error {
+			heartbeats[executorID] = state
+			return nil
+		}).AnyTimes()
+	mockStore.EXPECT().GetShardOwner(gomock.Any(), namespace, shardID).Return(&store.ShardOwner{ExecutorID: newOwner}, nil).AnyTimes()
+	mockStore.EXPECT().GetShardOwners(gomock.Any(), namespace, []string{shardID}).
+		Return(map[string]*store.ShardOwner{shardID: {ExecutorID: newOwner}}, nil).AnyTimes()
+
+	cfg := newConfig(t, []configEntry{
+		{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED},
+		{dynamicproperties.ShardDistributorControlledHandoff, true},
+	})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSourceAt(time.Now()), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	// Both executors start from their state before the move: the previous owner serves the shard,
+	// the new owner has not seen it yet.
+	reports := map[string]types.ShardStatus{previousOwner: types.ShardStatusREADY}
+	serving := func() bool {
+		previous := reports[previousOwner]
+		return previous == types.ShardStatusREADY || previous == types.ShardStatusDRAINING || reports[newOwner] == types.ShardStatusREADY
+	}
+	heartbeat := func(executorID string) *types.ShardAssignment {
+		shardReports := map[string]*types.ShardStatusReport{}
+		if status, ok := reports[executorID]; ok {
+			shardReports[shardID] = &types.ShardStatusReport{Status: status}
+		}
+		res, err := handler.Heartbeat(ctx, &types.ExecutorHeartbeatRequest{
+			Namespace:          namespace,
+			ExecutorID:         executorID,
+			Status:             types.ExecutorStatusACTIVE,
+			ShardStatusReports: shardReports,
+		})
+		require.NoError(t, err)
+		return res.ShardAssignments[shardID]
+	}
+
+	for step := 0; step < 5; step++ {
+		switch assignment := heartbeat(newOwner); assignment.GetStatus() {
+		case types.AssignmentStatusPREPARING:
+			reports[newOwner] = types.ShardStatusPREPARING
+		case types.AssignmentStatusREADY:
+			reports[newOwner] = types.ShardStatusREADY
+		default:
+			t.Fatalf("step %d: unexpected assignment of the new owner: %v", step, assignment)
+		}
+		require.True(t, serving(), "step %d: shard not served after heartbeat of the new owner", step)
+
+		switch assignment := heartbeat(previousOwner); {
+		case assignment == nil:
+			delete(reports, previousOwner)
+		case assignment.Status == types.AssignmentStatusPREPARING:
+			reports[previousOwner] = types.ShardStatusREADY
+		case assignment.Status == types.AssignmentStatusDRAINING && reports[previousOwner] == types.ShardStatusDRAINING:
+			reports[previousOwner] = types.ShardStatusDONE
+		case assignment.Status == types.AssignmentStatusDRAINING:
+			reports[previousOwner] = types.ShardStatusDRAINING
+		default:
+			t.Fatalf("step %d: unexpected assignment of the previous owner: %v", step, assignment)
+		}
+		require.True(t, serving(), "step %d: shard not served after heartbeat of the previous owner", step)
+	}
+
+	assert.NotContains(t, reports, previousOwner)
+	assert.Equal(t, types.ShardStatusREADY, reports[newOwner])
+}
+
+func TestHeartbeat_ControlledHandoffDisabled(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-namespace"
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	cfg := newConfig(t, []configEntry{{dynamicproperties.ShardDistributorMigrationMode, config.MigrationModeONBOARDED}})
+	handler := NewExecutorHandler(testlogger.New(t), mockStore, clock.NewMockedTimeSourceAt(time.Now()), config.ShardDistribution{}, cfg, metrics.NoopClient, events.NewNoop())
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), namespace, "exec-new").Return(&store.HeartbeatState{}, &store.AssignedState{
+		AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}},
+	}, nil)
+	mockStore.EXPECT().RecordHeartbeat(gomock.Any(), namespace, "exec-new", gomock.Any()).Return(nil)
+
+	res, err := handler.Heartbeat(ctx, &types.ExecutorHeartbeatRequest{
+		Namespace:  namespace,
+		ExecutorID: "exec-new",
+		Status:     types.ExecutorStatusACTIVE,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.AssignmentStatusREADY, res.ShardAssignments["shard-1"].GetStatus())
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/uber/cadence/common/types"
+)
+
+// inflightHeartbeats collapses concurrent identical heartbeats of an executor into a single
+// store interaction. Callers that arrive while a heartbeat for the same key is being
+// processed wait for it and share its result.
+type inflightHeartbeats struct {
+	mu    sync.Mutex
+	calls map[string]*inflightHeartbeat
+}
+
+type inflightHeartbeat struct {
+	done chan struct{}
+	resp *types.ExecutorHeartbeatResponse
+	err  error
+}
+
+func newInflightHeartbeats() *inflightHeartbeats {
+	return &inflightHeartbeats{calls: make(map[string]*inflightHeartbeat)}
+}
+
+// heartbeatKey returns the in-flight key of request. The key holds a hash of the whole request, so
+// only byte-identical requests, e.g. client retries, share a result. ok is false when the request
+// cannot be encoded, such a request is not collapsed.
+func heartbeatKey(request *types.ExecutorHeartbeatRequest) (string, bool) {
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(encoded)
+	return request.Namespace + "/" + request.ExecutorID + "/" + hex.EncodeToString(sum[:]), true
+}
+
+// do runs fn unless a call for key is already in flight, in which case onJoin is called and
+// the result of the in-flight call is returned. A joined caller stops waiting when its own
+// context is done. fn gets a context that is not canceled with ctx, so callers that joined
+// are not failed when the caller that started the call gives up.
+func (f *inflightHeartbeats) do(
+	ctx context.Context,
+	key string,
+	onJoin func(),
+	fn func(ctx context.Context) (*types.ExecutorHeartbeatResponse, error),
+) (*types.ExecutorHeartbeatResponse, error) {
+	f.mu.Lock()
+	if call, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		onJoin()
+		select {
+		case <-call.done:
+			return call.resp, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &inflightHeartbeat{done: make(chan struct{})}
+	f.calls[key] = call
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.resp, call.err = fn(context.WithoutCancel(ctx))
+	return call.resp, call.err
+}
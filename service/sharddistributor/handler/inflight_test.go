@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestInflightHeartbeats_JoinedCallerStopsOnContextDone(t *testing.T) {
+	inflight := newInflightHeartbeats()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan error)
+	go func() {
+		_, err := inflight.do(context.Background(), "key", func() {}, func(context.Context) (*types.ExecutorHeartbeatResponse, error) {
+			close(started)
+			<-release
+			return nil, errors.New("store error")
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	joined := false
+	cancel()
+	_, err := inflight.do(ctx, "key", func() { joined = true }, func(context.Context) (*types.ExecutorHeartbeatResponse, error) {
+		t.Fatal("joined caller must not run its own heartbeat")
+		return nil, nil
+	})
+	require.True(t, joined)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	require.EqualError(t, <-leaderDone, "store error")
+
+	// A call for a key that is no longer in flight runs again.
+	resp := &types.ExecutorHeartbeatResponse{}
+	got, err := inflight.do(context.Background(), "key", func() { t.Fatal("unexpected join") }, func(context.Context) (*types.ExecutorHeartbeatResponse, error) {
+		return resp, nil
+	})
+	require.NoError(t, err)
+	require.Same(t, resp, got)
+}
+
+func TestInflightHeartbeats_CallIsNotCanceledWithItsCaller(t *testing.T) {
+	inflight := newInflightHeartbeats()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := inflight.do(ctx, "key", func() {}, func(ctx context.Context) (*types.ExecutorHeartbeatResponse, error) {
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+}
+
+func TestHeartbeatKey(t *testing.T) {
+	request := func(status types.ExecutorStatus) *types.ExecutorHeartbeatRequest {
+		return &types.ExecutorHeartbeatRequest{
+			Namespace:  "test-namespace",
+			ExecutorID: "test-executor",
+			Status:     status,
+			Metadata:   map[string]string{"a": "1", "b": "2"},
+		}
+	}
+
+	active, ok := heartbeatKey(request(types.ExecutorStatusACTIVE))
+	require.True(t, ok)
+	retried, ok := heartbeatKey(request(types.ExecutorStatusACTIVE))
+	require.True(t, ok)
+	draining, ok := heartbeatKey(request(types.ExecutorStatusDRAINING))
+	require.True(t, ok)
+
+	require.Equal(t, active, retried)
+	require.NotEqual(t, active, draining)
+
+	// A request that cannot be encoded is not collapsed.
+	invalid := request(types.ExecutorStatusACTIVE)
+	nan := math.NaN()
+	invalid.ExecutorLoad = &nan
+	_, ok = heartbeatKey(invalid)
+	require.False(t, ok)
+}
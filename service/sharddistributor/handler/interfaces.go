@@ -27,6 +27,8 @@ import (
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
 //go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination interfaces_mock.go
@@ -44,6 +46,25 @@ type Handler interface {
 	GetShardOwner(context.Context, *types.GetShardOwnerRequest) (*types.GetShardOwnerResponse, error)
 
 	WatchNamespaceState(*types.WatchNamespaceStateRequest, WatchNamespaceStateServer) error
+
+	// The operator methods below are not exposed over RPC.
+
+	PlanExecutorRemoval(ctx context.Context, namespace, executorID string) ([]plan.Move, error)
+
+	ForceAssign(ctx context.Context, namespace string, assignments map[string]string) error
+
+	GetShardLoadHistory(ctx context.Context, namespace, shardID string) (store.LoadSamples, error)
+
+	ExplainUnassigned(ctx context.Context, namespace, shardID string) (plan.UnassignedReason, error)
+}
+
+// Leaders gives what the leader of a namespace knows: the guard of its leadership, for the writes only the
+// leader may make, and why its rebalance passes leave shards unassigned.
+type Leaders interface {
+	// LeaderGuard returns false when this host does not lead the namespace.
+	LeaderGuard(namespace string) (store.GuardFunc, bool)
+	// UnassignedReason returns false when this host does not lead the namespace.
+	UnassignedReason(namespace, shardID string) (plan.UnassignedReason, bool)
 }
 
 type Executor interface {
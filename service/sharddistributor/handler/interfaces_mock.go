@@ -16,6 +16,8 @@ import (
 	gomock "go.uber.org/mock/gomock"
 
 	types "github.com/uber/cadence/common/types"
+	plan "github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	store "github.com/uber/cadence/service/sharddistributor/store"
 )
 
 // MockHandler is a mock of Handler interface.
@@ -42,6 +44,50 @@ func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
 	return m.recorder
 }
 
+// ExplainUnassigned mocks base method.
+func (m *MockHandler) ExplainUnassigned(ctx context.Context, namespace, shardID string) (plan.UnassignedReason, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainUnassigned", ctx, namespace, shardID)
+	ret0, _ := ret[0].(plan.UnassignedReason)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainUnassigned indicates an expected call of ExplainUnassigned.
+func (mr *MockHandlerMockRecorder) ExplainUnassigned(ctx, namespace, shardID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainUnassigned", reflect.TypeOf((*MockHandler)(nil).ExplainUnassigned), ctx, namespace, shardID)
+}
+
+// ForceAssign mocks base method.
+func (m *MockHandler) ForceAssign(ctx context.Context, namespace string, assignments map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceAssign", ctx, namespace, assignments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceAssign indicates an expected call of ForceAssign.
+func (mr *MockHandlerMockRecorder) ForceAssign(ctx, namespace, assignments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceAssign", reflect.TypeOf((*MockHandler)(nil).ForceAssign), ctx, namespace, assignments)
+}
+
+// GetShardLoadHistory mocks base method.
+func (m *MockHandler) GetShardLoadHistory(ctx context.Context, namespace, shardID string) (store.LoadSamples, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardLoadHistory", ctx, namespace, shardID)
+	ret0, _ := ret[0].(store.LoadSamples)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShardLoadHistory indicates an expected call of GetShardLoadHistory.
+func (mr *MockHandlerMockRecorder) GetShardLoadHistory(ctx, namespace, shardID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardLoadHistory", reflect.TypeOf((*MockHandler)(nil).GetShardLoadHistory), ctx, namespace, shardID)
+}
+
 // GetShardOwner mocks base method.
 func (m *MockHandler) GetShardOwner(arg0 context.Context, arg1 *types.GetShardOwnerRequest) (*types.GetShardOwnerResponse, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +118,21 @@ func (mr *MockHandlerMockRecorder) Health(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHandler)(nil).Health), arg0)
 }
 
+// PlanExecutorRemoval mocks base method.
+func (m *MockHandler) PlanExecutorRemoval(ctx context.Context, namespace, executorID string) ([]plan.Move, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanExecutorRemoval", ctx, namespace, executorID)
+	ret0, _ := ret[0].([]plan.Move)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanExecutorRemoval indicates an expected call of PlanExecutorRemoval.
+func (mr *MockHandlerMockRecorder) PlanExecutorRemoval(ctx, namespace, executorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanExecutorRemoval", reflect.TypeOf((*MockHandler)(nil).PlanExecutorRemoval), ctx, namespace, executorID)
+}
+
 // Start mocks base method.
 func (m *MockHandler) Start() {
 	m.ctrl.T.Helper()
@@ -110,6 +171,60 @@ func (mr *MockHandlerMockRecorder) WatchNamespaceState(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchNamespaceState", reflect.TypeOf((*MockHandler)(nil).WatchNamespaceState), arg0, arg1)
 }
 
+// MockLeaders is a mock of Leaders interface.
+type MockLeaders struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadersMockRecorder
+	isgomock struct{}
+}
+
+// MockLeadersMockRecorder is the mock recorder for MockLeaders.
+type MockLeadersMockRecorder struct {
+	mock *MockLeaders
+}
+
+// NewMockLeaders creates a new mock instance.
+func NewMockLeaders(ctrl *gomock.Controller) *MockLeaders {
+	mock := &MockLeaders{ctrl: ctrl}
+	mock.recorder = &MockLeadersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaders) EXPECT() *MockLeadersMockRecorder {
+	return m.recorder
+}
+
+// LeaderGuard mocks base method.
+func (m *MockLeaders) LeaderGuard(namespace string) (store.GuardFunc, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaderGuard", namespace)
+	ret0, _ := ret[0].(store.GuardFunc)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// LeaderGuard indicates an expected call of LeaderGuard.
+func (mr *MockLeadersMockRecorder) LeaderGuard(namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaderGuard", reflect.TypeOf((*MockLeaders)(nil).LeaderGuard), namespace)
+}
+
+// UnassignedReason mocks base method.
+func (m *MockLeaders) UnassignedReason(namespace, shardID string) (plan.UnassignedReason, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignedReason", namespace, shardID)
+	ret0, _ := ret[0].(plan.UnassignedReason)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// UnassignedReason indicates an expected call of UnassignedReason.
+func (mr *MockLeadersMockRecorder) UnassignedReason(namespace, shardID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignedReason", reflect.TypeOf((*MockLeaders)(nil).UnassignedReason), namespace, shardID)
+}
+
 // MockExecutor is a mock of Executor interface.
 type MockExecutor struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+const (
+	_defaultStoreRetryMaxAttempts     = 3
+	_defaultStoreRetryInitialInterval = 50 * time.Millisecond
+	_storeRetryMaxInterval            = time.Second
+)
+
+// retryPolicy controls how store writes that fail with a transient error are retried.
+type retryPolicy struct {
+	// maxAttempts is the total number of attempts, including the first one.
+	maxAttempts int
+	// initialInterval is the backoff before the first retry, it doubles on every further retry.
+	initialInterval time.Duration
+}
+
+// storeRetryPolicy returns the retry policy for store writes from dynamic config.
+func storeRetryPolicy(cfg *config.Config) retryPolicy {
+	policy := retryPolicy{
+		maxAttempts:     _defaultStoreRetryMaxAttempts,
+		initialInterval: _defaultStoreRetryInitialInterval,
+	}
+	if cfg == nil {
+		return policy
+	}
+	if cfg.StoreRetryMaxAttempts != nil {
+		policy.maxAttempts = cfg.StoreRetryMaxAttempts()
+	}
+	if cfg.StoreRetryInitialInterval != nil {
+		policy.initialInterval = cfg.StoreRetryInitialInterval()
+	}
+	return policy
+}
+
+// withRetry calls fn and retries it with exponential backoff while it fails with a
+// transient store error. Backoff sleeps use timeSource so tests can control them.
+func withRetry(ctx context.Context, timeSource clock.TimeSource, policy retryPolicy, fn func(ctx context.Context) error) error {
+	if policy.maxAttempts <= 1 {
+		return fn(ctx)
+	}
+
+	exponentialPolicy := backoff.NewExponentialRetryPolicy(policy.initialInterval)
+	exponentialPolicy.SetMaximumInterval(_storeRetryMaxInterval)
+	// The backoff package counts retries, not attempts.
+	exponentialPolicy.SetMaximumAttempts(policy.maxAttempts - 1)
+
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(exponentialPolicy),
+		backoff.WithRetryableError(isTransientStoreError),
+		backoff.WithClock(timeSource),
+	)
+	return throttleRetry.Do(ctx, fn)
+}
+
+// isTransientStoreError reports whether a store error may succeed on retry.
+// Errors describing the stored state, such as version conflicts, are not transient, neither is a
+// read-only store, which stays read-only for the duration of a maintenance window, nor are errors
+// caused by the caller's context ending. A partially applied heartbeat is retried whatever failed, as
+// recording the heartbeat again brings the statistics in line with it.
+func isTransientStoreError(err error) bool {
+	var alreadyAssigned *store.ErrShardAlreadyAssigned
+	var partiallyApplied *store.ErrHeartbeatPartiallyApplied
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &partiallyApplied):
+		return true
+	case errors.Is(err, store.ErrExecutorNotFound),
+		errors.Is(err, store.ErrExecutorNotFound),
+		errors.Is(err, store.ErrShardNotFound),
+		errors.Is(err, store.ErrVersionConflict),
+		errors.Is(err, store.ErrExecutorNotRunning),
+		errors.Is(err, store.ErrReadOnly),
+		errors.As(err, &alreadyAssigned):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestWithRetry(t *testing.T) {
+	errTransient := errors.New("etcd unavailable")
+	policy := retryPolicy{maxAttempts: 3, initialInterval: 10 * time.Millisecond}
+
+	tests := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{
+			name:             "succeeds after two transient failures",
+			errs:             []error{errTransient, errTransient, nil},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "non-transient error is not retried",
+			errs:             []error{fmt.Errorf("assign: %w", store.ErrVersionConflict)},
+			expectedAttempts: 1,
+			expectedErr:      store.ErrVersionConflict,
+		},
+		{
+			name:             "gives up after max attempts",
+			errs:             []error{errTransient, errTransient, errTransient},
+			expectedAttempts: 3,
+			expectedErr:      errTransient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeSource := clock.NewMockedTimeSource()
+			attempts := 0
+			fn := func(ctx context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			}
+
+			result := make(chan error, 1)
+			go func() {
+				result <- withRetry(context.Background(), timeSource, policy, fn)
+			}()
+
+			// Every retry waits on the time source, advance it past the exponential backoff.
+			for retry := 0; retry < tt.expectedAttempts-1; retry++ {
+				timeSource.BlockUntil(1)
+				timeSource.Advance(policy.initialInterval << retry)
+			}
+
+			select {
+			case err := <-result:
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("withRetry did not return")
+			}
+			assert.Equal(t, tt.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestWithRetry_SingleAttempt(t *testing.T) {
+	errTransient := errors.New("etcd unavailable")
+	attempts := 0
+
+	err := withRetry(context.Background(), clock.NewMockedTimeSource(), retryPolicy{maxAttempts: 1}, func(ctx context.Context) error {
+		attempts++
+		return errTransient
+	})
+
+	require.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestIsTransientStoreError(t *testing.T) {
+	assert.True(t, isTransientStoreError(errors.New("connection reset")))
+	assert.False(t, isTransientStoreError(nil))
+	assert.False(t, isTransientStoreError(context.Canceled))
+	assert.False(t, isTransientStoreError(fmt.Errorf("get: %w", context.DeadlineExceeded)))
+	assert.False(t, isTransientStoreError(store.ErrExecutorNotFound))
+	assert.False(t, isTransientStoreError(store.ErrVersionConflict))
+	assert.False(t, isTransientStoreError(fmt.Errorf("record heartbeat: %w", store.ErrReadOnly)))
+	assert.False(t, isTransientStoreError(&store.ErrShardAlreadyAssigned{ShardID: "shard-1"}))
+	assert.True(t, isTransientStoreError(&store.ErrHeartbeatPartiallyApplied{Err: store.ErrVersionConflict}))
+}
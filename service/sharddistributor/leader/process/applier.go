@@ -0,0 +1,181 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// MoveFailure is a move of a rebalance plan whose write failed.
+type MoveFailure struct {
+	Move plan.Move
+	Err  error
+}
+
+// ApplySummary is the outcome of applying the moves of a rebalance plan. Every move is in exactly one of
+// Applied, Failed and Skipped, in the order of the plan.
+type ApplySummary struct {
+	Applied []plan.Move
+	Failed  []MoveFailure
+	// Skipped holds the moves that were not written, because leadership was lost or the context was done first.
+	Skipped []plan.Move
+	// Fenced is set when a write was rejected because leadership changed.
+	Fenced bool
+}
+
+// moveWriter writes a single move of a rebalance plan to the store.
+type moveWriter func(ctx context.Context, move plan.Move) error
+
+// ApplyMoves writes moves to the store one move at a time, with up to workers writes in flight. A failed move
+// is collected in the summary and the other moves are still applied, except once a write is rejected by guard:
+// leadership changed, so no further moves are written. Each move is written guarded, as a read-modify-write of
+// the assigned states of its two executors, so moves sharing an executor are never written concurrently.
+// The moved shard gets the assignment and handover statistics planned for it in newState.
+func ApplyMoves(
+	ctx context.Context,
+	shardStore store.Store,
+	namespace string,
+	moves []plan.Move,
+	newState map[string]store.AssignedState,
+	workers int,
+	guard store.GuardFunc,
+) ApplySummary {
+	return dispatchMoves(ctx, moves, workers, func(ctx context.Context, move plan.Move) error {
+		return writeMove(ctx, shardStore, namespace, move, newState[move.To], guard)
+	})
+}
+
+// dispatchMoves dispatches moves in plan order to at most workers concurrent writes. A move waits while a
+// move sharing one of its executors is in flight. Dispatching stops once a write returns store.ErrLeadershipLost
+// or ctx is done; the writes in flight are still awaited.
+func dispatchMoves(ctx context.Context, moves []plan.Move, workers int, write moveWriter) ApplySummary {
+	workers = max(workers, 1)
+	errs := make([]error, len(moves))
+	dispatched := make([]bool, len(moves))
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		fenced bool
+	)
+	released := sync.NewCond(&mu)
+	busy := make(map[string]struct{})
+	inFlight := 0
+	stopped := func() bool { return fenced || ctx.Err() != nil }
+
+	mu.Lock()
+	for i, move := range moves {
+		for !stopped() && (inFlight >= workers || isBusy(busy, move)) {
+			released.Wait()
+		}
+		if stopped() {
+			break
+		}
+		busy[move.From], busy[move.To] = struct{}{}, struct{}{}
+		inFlight++
+		dispatched[i] = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := write(ctx, move)
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs[i] = err
+			fenced = fenced || errors.Is(err, store.ErrLeadershipLost)
+			delete(busy, move.From)
+			delete(busy, move.To)
+			inFlight--
+			released.Broadcast()
+		}()
+	}
+	mu.Unlock()
+	wg.Wait()
+
+	summary := ApplySummary{Fenced: fenced}
+	for i, move := range moves {
+		switch {
+		case !dispatched[i]:
+			summary.Skipped = append(summary.Skipped, move)
+		case errs[i] != nil:
+			summary.Failed = append(summary.Failed, MoveFailure{Move: move, Err: errs[i]})
+		default:
+			summary.Applied = append(summary.Applied, move)
+		}
+	}
+	return summary
+}
+
+func isBusy(busy map[string]struct{}, move plan.Move) bool {
+	_, fromBusy := busy[move.From]
+	_, toBusy := busy[move.To]
+	return fromBusy || toBusy
+}
+
+// writeMove moves the shard between the assigned states of its executors in one guarded store operation.
+// The assigned states are read first; their revisions make the write fail if either changed meanwhile.
+func writeMove(ctx context.Context, shardStore store.Store, namespace string, move plan.Move, planned store.AssignedState, guard store.GuardFunc) error {
+	assignment, ok := planned.AssignedShards[move.ShardID]
+	if !ok {
+		return fmt.Errorf("shard %s not planned on target executor %s", move.ShardID, move.To)
+	}
+	from, err := readAssignedState(ctx, shardStore, namespace, move.From)
+	if err != nil {
+		return err
+	}
+	if _, ok := from.AssignedShards[move.ShardID]; !ok {
+		return fmt.Errorf("shard %s not found in source executor %s", move.ShardID, move.From)
+	}
+	to, err := readAssignedState(ctx, shardStore, namespace, move.To)
+	if err != nil {
+		return err
+	}
+
+	delete(from.AssignedShards, move.ShardID)
+	delete(from.ShardHandoverStats, move.ShardID)
+	from.LastUpdated = planned.LastUpdated
+	to.AssignedShards[move.ShardID] = assignment
+	if handoverStats, ok := planned.ShardHandoverStats[move.ShardID]; ok {
+		to.ShardHandoverStats[move.ShardID] = handoverStats
+	}
+	to.LastUpdated = planned.LastUpdated
+
+	err = shardStore.AssignShards(ctx, namespace, store.AssignShardsRequest{
+		NewState: &store.NamespaceState{
+			ShardAssignments: map[string]store.AssignedState{move.From: from, move.To: to},
+		},
+	}, guard)
+	if err != nil {
+		return fmt.Errorf("write move of shard %s: %w", move.ShardID, err)
+	}
+	return nil
+}
+
+// readAssignedState returns a copy of the assigned state of the executor, which is empty if it has none yet.
+func readAssignedState(ctx context.Context, shardStore store.Store, namespace, executorID string) (store.AssignedState, error) {
+	_, assigned, err := shardStore.GetHeartbeat(ctx, namespace, executorID)
+	if err != nil {
+		return store.AssignedState{}, fmt.Errorf("get assigned state of executor %s: %w", executorID, err)
+	}
+
+	var state store.AssignedState
+	if assigned != nil {
+		state = *assigned
+	}
+	state.AssignedShards = maps.Clone(state.AssignedShards)
+	if state.AssignedShards == nil {
+		state.AssignedShards = make(map[string]*types.ShardAssignment)
+	}
+	state.ShardHandoverStats = maps.Clone(state.ShardHandoverStats)
+	if state.ShardHandoverStats == nil {
+		state.ShardHandoverStats = make(map[string]store.ShardHandoverStats)
+	}
+	return state, nil
+}
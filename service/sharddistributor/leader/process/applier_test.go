@@ -0,0 +1,170 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestDispatchMoves_CollectsPartialFailures(t *testing.T) {
+	moves := []plan.Move{
+		{ShardID: "shard-1", From: "exec-1", To: "exec-2"},
+		{ShardID: "shard-2", From: "exec-3", To: "exec-4"},
+		{ShardID: "shard-3", From: "exec-5", To: "exec-6"},
+	}
+	writeErr := errors.New("version conflict")
+
+	summary := dispatchMoves(context.Background(), moves, 2, func(_ context.Context, move plan.Move) error {
+		if move.ShardID == "shard-2" {
+			return writeErr
+		}
+		return nil
+	})
+
+	assert.Equal(t, []plan.Move{moves[0], moves[2]}, summary.Applied)
+	assert.Equal(t, []MoveFailure{{Move: moves[1], Err: writeErr}}, summary.Failed)
+	assert.Empty(t, summary.Skipped)
+	assert.False(t, summary.Fenced)
+}
+
+func TestDispatchMoves_StopsOnFencingRejection(t *testing.T) {
+	moves := []plan.Move{
+		{ShardID: "shard-1", From: "exec-1", To: "exec-2"},
+		{ShardID: "shard-2", From: "exec-3", To: "exec-4"},
+		{ShardID: "shard-3", From: "exec-5", To: "exec-6"},
+		{ShardID: "shard-4", From: "exec-7", To: "exec-8"},
+	}
+	fencedErr := fmt.Errorf("write move: %w", store.ErrLeadershipLost)
+
+	var written []string
+	summary := dispatchMoves(context.Background(), moves, 1, func(_ context.Context, move plan.Move) error {
+		written = append(written, move.ShardID)
+		if move.ShardID == "shard-2" {
+			return fencedErr
+		}
+		return nil
+	})
+
+	assert.Equal(t, []string{"shard-1", "shard-2"}, written, "no move is written after the fencing rejection")
+	assert.Equal(t, []plan.Move{moves[0]}, summary.Applied)
+	assert.Equal(t, []MoveFailure{{Move: moves[1], Err: fencedErr}}, summary.Failed)
+	assert.Equal(t, []plan.Move{moves[2], moves[3]}, summary.Skipped)
+	assert.True(t, summary.Fenced)
+}
+
+func TestDispatchMoves_BoundsConcurrency(t *testing.T) {
+	var moves []plan.Move
+	for i := 0; i < 12; i++ {
+		// Consecutive pairs of moves share their source executor.
+		moves = append(moves, plan.Move{ShardID: fmt.Sprintf("shard-%d", i), From: fmt.Sprintf("exec-%d", i/2), To: fmt.Sprintf("target-%d", i)})
+	}
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+		busy     = make(map[string]bool)
+	)
+	summary := dispatchMoves(context.Background(), moves, 3, func(_ context.Context, move plan.Move) error {
+		mu.Lock()
+		assert.False(t, busy[move.From], "executor %s written concurrently", move.From)
+		busy[move.From] = true
+		inFlight++
+		maxSeen = max(maxSeen, inFlight)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		busy[move.From] = false
+		inFlight--
+		mu.Unlock()
+		return nil
+	})
+
+	assert.Equal(t, moves, summary.Applied)
+	assert.LessOrEqual(t, maxSeen, 3)
+}
+
+func TestDispatchMoves_CanceledContextSkipsMoves(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	moves := []plan.Move{{ShardID: "shard-1", From: "exec-1", To: "exec-2"}}
+
+	summary := dispatchMoves(ctx, moves, 2, func(context.Context, plan.Move) error {
+		t.Error("no move is written once the context is done")
+		return nil
+	})
+
+	assert.Equal(t, moves, summary.Skipped)
+}
+
+func TestApplyMoves_WritesGuardedMove(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockStore(ctrl)
+	move := plan.Move{ShardID: "shard-1", From: "exec-1", To: "exec-2"}
+	plannedAssignment := &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: now, LeaseExpiresAt: now.Add(time.Minute)}
+	handoverStats := store.ShardHandoverStats{HandoverType: types.HandoverTypeEMERGENCY, PreviousExecutorLastHeartbeatTime: now}
+	newState := map[string]store.AssignedState{
+		"exec-2": {
+			AssignedShards:     map[string]*types.ShardAssignment{"shard-1": plannedAssignment},
+			ShardHandoverStats: map[string]store.ShardHandoverStats{"shard-1": handoverStats},
+			LastUpdated:        now,
+		},
+	}
+
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), "test-namespace", "exec-1").Return(&store.HeartbeatState{}, &store.AssignedState{
+		AssignedShards: map[string]*types.ShardAssignment{
+			"shard-1": {Status: types.AssignmentStatusREADY},
+			"shard-2": {Status: types.AssignmentStatusREADY},
+		},
+		ShardHandoverStats: map[string]store.ShardHandoverStats{"shard-1": {HandoverType: types.HandoverTypeGRACEFUL}},
+		ModRevision:        5,
+	}, nil)
+	mockStore.EXPECT().GetHeartbeat(gomock.Any(), "test-namespace", "exec-2").Return(&store.HeartbeatState{}, nil, nil)
+	mockStore.EXPECT().AssignShards(gomock.Any(), "test-namespace", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Equal(t, map[string]store.AssignedState{
+				"exec-1": {
+					AssignedShards:     map[string]*types.ShardAssignment{"shard-2": {Status: types.AssignmentStatusREADY}},
+					ShardHandoverStats: map[string]store.ShardHandoverStats{},
+					LastUpdated:        now,
+					ModRevision:        5,
+				},
+				"exec-2": {
+					AssignedShards:     map[string]*types.ShardAssignment{"shard-1": plannedAssignment},
+					ShardHandoverStats: map[string]store.ShardHandoverStats{"shard-1": handoverStats},
+					LastUpdated:        now,
+				},
+			}, request.NewState.ShardAssignments)
+			return fmt.Errorf("%w: transaction failed", store.ErrLeadershipLost)
+		})
+
+	summary := ApplyMoves(context.Background(), mockStore, "test-namespace", []plan.Move{move}, newState, 4, store.NopGuard())
+
+	require.Len(t, summary.Failed, 1)
+	assert.ErrorIs(t, summary.Failed[0].Err, store.ErrLeadershipLost)
+	assert.True(t, summary.Fenced)
+}
+
+func TestApplyMoves_RejectsUnplannedMove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	move := plan.Move{ShardID: "shard-1", From: "exec-1", To: "exec-2"}
+
+	summary := ApplyMoves(context.Background(), store.NewMockStore(ctrl), "test-namespace", []plan.Move{move}, nil, 4, store.NopGuard())
+
+	require.Len(t, summary.Failed, 1)
+	assert.ErrorContains(t, summary.Failed[0].Err, "shard shard-1 not planned on target executor exec-2")
+}
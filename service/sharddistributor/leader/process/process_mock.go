@@ -16,6 +16,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 
 	config "github.com/uber/cadence/service/sharddistributor/config"
+	plan "github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	store "github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -43,6 +44,20 @@ func (m *MockProcessor) EXPECT() *MockProcessorMockRecorder {
 	return m.recorder
 }
 
+// Config mocks base method.
+func (m *MockProcessor) Config() *config.Config {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Config")
+	ret0, _ := ret[0].(*config.Config)
+	return ret0
+}
+
+// Config indicates an expected call of Config.
+func (mr *MockProcessorMockRecorder) Config() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockProcessor)(nil).Config))
+}
+
 // Run mocks base method.
 func (m *MockProcessor) Run(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -57,6 +72,18 @@ func (mr *MockProcessorMockRecorder) Run(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockProcessor)(nil).Run), ctx)
 }
 
+// SetConfig mocks base method.
+func (m *MockProcessor) SetConfig(sdConfig *config.Config) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConfig", sdConfig)
+}
+
+// SetConfig indicates an expected call of SetConfig.
+func (mr *MockProcessorMockRecorder) SetConfig(sdConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfig", reflect.TypeOf((*MockProcessor)(nil).SetConfig), sdConfig)
+}
+
 // Terminate mocks base method.
 func (m *MockProcessor) Terminate(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -108,3 +135,33 @@ func (mr *MockFactoryMockRecorder) CreateProcessor(cfg, storage, election any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessor", reflect.TypeOf((*MockFactory)(nil).CreateProcessor), cfg, storage, election)
 }
+
+// LeaderGuard mocks base method.
+func (m *MockFactory) LeaderGuard(namespace string) (store.GuardFunc, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaderGuard", namespace)
+	ret0, _ := ret[0].(store.GuardFunc)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// LeaderGuard indicates an expected call of LeaderGuard.
+func (mr *MockFactoryMockRecorder) LeaderGuard(namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaderGuard", reflect.TypeOf((*MockFactory)(nil).LeaderGuard), namespace)
+}
+
+// UnassignedReason mocks base method.
+func (m *MockFactory) UnassignedReason(namespace, shardID string) (plan.UnassignedReason, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignedReason", namespace, shardID)
+	ret0, _ := ret[0].(plan.UnassignedReason)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// UnassignedReason indicates an expected call of UnassignedReason.
+func (mr *MockFactoryMockRecorder) UnassignedReason(namespace, shardID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignedReason", reflect.TypeOf((*MockFactory)(nil).UnassignedReason), namespace, shardID)
+}
@@ -5,23 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"math"
 	"math/rand"
 	"slices"
-	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/fx"
 
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/rebalancetrace"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -37,6 +42,11 @@ var Module = fx.Module(
 type Processor interface {
 	Run(ctx context.Context) error
 	Terminate(ctx context.Context) error
+	// Config returns the configuration the next rebalance uses.
+	Config() *config.Config
+	// SetConfig replaces the configuration. A rebalance that is already running keeps using the
+	// configuration it started with.
+	SetConfig(sdConfig *config.Config)
 }
 
 // Factory creates processor instances
@@ -44,35 +54,125 @@ type Factory interface {
 	// CreateProcessor creates a new processor, it takes the generic store
 	// and the election object which provides the transactional guard.
 	CreateProcessor(cfg config.Namespace, storage store.Store, election store.Election) Processor
+	// LeaderGuard returns the guard of the election the running processor of the namespace holds.
+	// It returns false when no processor of the namespace is running on this host.
+	LeaderGuard(namespace string) (store.GuardFunc, bool)
+	// UnassignedReason returns why the running processor of the namespace leaves the shard unassigned: the
+	// reason its last rebalance pass gave for the shard, else the outcome of that pass, or
+	// plan.UnassignedReasonInCooldown while it waits out the rebalance cooldown. It returns false when no
+	// processor of the namespace is running on this host.
+	UnassignedReason(namespace, shardID string) (plan.UnassignedReason, bool)
 }
 
 const (
 	_defaultPeriod       = time.Second
-	_defaultHeartbeatTTL = 10 * time.Second
+	_defaultHeartbeatTTL = config.DefaultHeartbeatTTL
 	_defaultTimeout      = 1 * time.Second
 	_defaultCooldown     = 250 * time.Millisecond
 )
 
 type processorFactory struct {
-	logger        log.Logger
-	timeSource    clock.TimeSource
-	cfg           config.LeaderProcess
-	metricsClient metrics.Client
-	sdConfig      *config.Config
+	logger         log.Logger
+	timeSource     clock.TimeSource
+	cfg            config.LeaderProcess
+	metricsClient  metrics.Client
+	sdConfig       *config.Config
+	executorEvents events.ExecutorEvents
+	traces         rebalancetrace.Sink
+	leaders        *leaderElections
+}
+
+// leaderElections tracks the elections held by the processors running on this host, and why each
+// processor leaves shards unassigned: for the whole namespace, and for the shards its last rebalance
+// pass left out of the plan.
+type leaderElections struct {
+	mu                sync.Mutex
+	elections         map[string]store.Election
+	unassignedReasons map[string]plan.UnassignedReason
+	unplacedShards    map[string]map[string]plan.UnassignedReason
+}
+
+func (l *leaderElections) add(namespace string, election store.Election) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.elections[namespace] = election
+}
+
+// remove only drops the election if it is still the one registered, so a processor that stops
+// late cannot drop the election of the processor that replaced it.
+func (l *leaderElections) remove(namespace string, election store.Election) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.elections[namespace] == election {
+		delete(l.elections, namespace)
+		delete(l.unassignedReasons, namespace)
+		delete(l.unplacedShards, namespace)
+	}
+}
+
+func (l *leaderElections) get(namespace string) (store.Election, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	election, ok := l.elections[namespace]
+	return election, ok
+}
+
+// setUnassignedReason records reason for the namespace, if election is still the one registered.
+func (l *leaderElections) setUnassignedReason(namespace string, election store.Election, reason plan.UnassignedReason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.elections[namespace] == election {
+		l.unassignedReasons[namespace] = reason
+	}
+}
+
+// setUnplacedShards records the shards the last rebalance pass of the namespace left out of its plan and why,
+// replacing the ones of the previous pass, if election is still the one registered.
+func (l *leaderElections) setUnplacedShards(namespace string, election store.Election, unplaced map[string]plan.UnassignedReason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.elections[namespace] == election {
+		l.unplacedShards[namespace] = unplaced
+	}
+}
+
+// unassignedReason returns the reason recorded for the shard, else the one recorded for the namespace. Until
+// its processor records one, the first rebalance pass is still to come, so the shards are pending.
+func (l *leaderElections) unassignedReason(namespace, shardID string) (plan.UnassignedReason, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.elections[namespace]; !ok {
+		return "", false
+	}
+	if reason, ok := l.unplacedShards[namespace][shardID]; ok {
+		return reason, true
+	}
+	if reason, ok := l.unassignedReasons[namespace]; ok {
+		return reason, true
+	}
+	return plan.UnassignedReasonPending, true
 }
 
 type namespaceProcessor struct {
-	namespaceCfg  config.Namespace
-	logger        log.Logger
-	metricsClient metrics.Client
-	timeSource    clock.TimeSource
-	running       bool
-	cancel        context.CancelFunc
-	sdConfig      *config.Config
-	cfg           config.LeaderProcess
-	wg            sync.WaitGroup
-	shardStore    store.Store
-	election      store.Election
+	namespaceCfg   config.Namespace
+	logger         log.Logger
+	metricsClient  metrics.Client
+	timeSource     clock.TimeSource
+	running        bool
+	cancel         context.CancelFunc
+	sdConfig       atomic.Pointer[config.Config]
+	cfg            config.LeaderProcess
+	wg             sync.WaitGroup
+	shardStore     store.Store
+	election       store.Election
+	leaders        *leaderElections
+	executorEvents events.ExecutorEvents
+	traces         rebalancetrace.Sink
+	// epoch, rng and stability are only used by the rebalance loop, which runs on a single goroutine.
+	// rng is reseeded from the namespace and epoch on every pass, so a pass can be replayed from its trace.
+	epoch     int64
+	rng       *rand.Rand
+	stability *stabilityWindow
 }
 
 // NewProcessorFactory creates a new processor factory
@@ -82,6 +182,8 @@ func NewProcessorFactory(
 	timeSource clock.TimeSource,
 	cfg config.ShardDistribution,
 	sdConfig *config.Config,
+	executorEvents events.ExecutorEvents,
+	traces rebalancetrace.Sink,
 ) Factory {
 	if cfg.Process.Period <= 0 {
 		cfg.Process.Period = _defaultPeriod
@@ -97,26 +199,64 @@ func NewProcessorFactory(
 	}
 
 	return &processorFactory{
-		logger:        logger,
-		timeSource:    timeSource,
-		cfg:           cfg.Process,
-		metricsClient: metricsClient,
-		sdConfig:      sdConfig,
+		logger:         logger,
+		timeSource:     timeSource,
+		cfg:            cfg.Process,
+		metricsClient:  metricsClient,
+		sdConfig:       sdConfig,
+		executorEvents: executorEvents,
+		traces:         traces,
+		leaders: &leaderElections{
+			elections:         make(map[string]store.Election),
+			unassignedReasons: make(map[string]plan.UnassignedReason),
+			unplacedShards:    make(map[string]map[string]plan.UnassignedReason),
+		},
 	}
 }
 
 // CreateProcessor creates a new processor for the given namespace
 func (f *processorFactory) CreateProcessor(cfg config.Namespace, shardStore store.Store, election store.Election) Processor {
-	return &namespaceProcessor{
-		namespaceCfg:  cfg,
-		logger:        f.logger.WithTags(tag.ComponentLeaderProcessor, tag.ShardNamespace(cfg.Name)),
-		timeSource:    f.timeSource,
-		cfg:           f.cfg,
-		shardStore:    shardStore,
-		election:      election, // Store the election object
-		metricsClient: f.metricsClient,
-		sdConfig:      f.sdConfig,
+	processor := &namespaceProcessor{
+		namespaceCfg:   cfg,
+		logger:         f.logger.WithTags(tag.ComponentLeaderProcessor, tag.ShardNamespace(cfg.Name)),
+		timeSource:     f.timeSource,
+		cfg:            f.cfg,
+		shardStore:     shardStore,
+		election:       election, // Store the election object
+		leaders:        f.leaders,
+		metricsClient:  f.metricsClient,
+		executorEvents: f.executorEvents,
+		traces:         f.traces,
+		rng:            loadbalancer.NewRand(cfg.Name, 0),
+		stability:      newStabilityWindow(),
 	}
+	processor.sdConfig.Store(f.sdConfig)
+	return processor
+}
+
+// LeaderGuard returns the guard of the election the running processor of the namespace holds.
+func (f *processorFactory) LeaderGuard(namespace string) (store.GuardFunc, bool) {
+	election, ok := f.leaders.get(namespace)
+	if !ok {
+		return nil, false
+	}
+	return election.Guard(), true
+}
+
+// UnassignedReason returns why the running processor of the namespace leaves the shard unassigned.
+func (f *processorFactory) UnassignedReason(namespace, shardID string) (plan.UnassignedReason, bool) {
+	return f.leaders.unassignedReason(namespace, shardID)
+}
+
+// Config returns the configuration the next rebalance uses.
+func (p *namespaceProcessor) Config() *config.Config {
+	return p.sdConfig.Load()
+}
+
+// SetConfig replaces the configuration. Each rebalance reads the configuration once when it starts,
+// so a rebalance that is already running keeps using the configuration it started with.
+func (p *namespaceProcessor) SetConfig(sdConfig *config.Config) {
+	p.sdConfig.Store(sdConfig)
 }
 
 // Run begins processing for this namespace
@@ -128,6 +268,7 @@ func (p *namespaceProcessor) Run(ctx context.Context) error {
 	pCtx, cancel := context.WithCancel(ctx)
 	p.cancel = cancel
 	p.running = true
+	p.leaders.add(p.namespaceCfg.Name, p.election)
 
 	p.logger.Info("Starting")
 
@@ -146,6 +287,7 @@ func (p *namespaceProcessor) Terminate(ctx context.Context) error {
 
 	p.logger.Info("Stopping")
 
+	p.leaders.remove(p.namespaceCfg.Name, p.election)
 	if p.cancel != nil {
 		p.cancel()
 		p.cancel = nil
@@ -210,7 +352,10 @@ func (p *namespaceProcessor) runRebalancingLoop(ctx context.Context) {
 			// If an update comes in before the cooldown has expired,
 			// we wait until the cooldown has passed since the last rebalance before processing it.
 			// This ensures that we don't rebalance too frequently in response to a flurry of updates
-			p.timeSource.Sleep(nextRebalanceAllowedAt.Sub(p.timeSource.Now()))
+			if wait := nextRebalanceAllowedAt.Sub(p.timeSource.Now()); wait > 0 {
+				p.leaders.setUnassignedReason(p.namespaceCfg.Name, p.election, plan.UnassignedReasonInCooldown)
+				p.timeSource.Sleep(wait)
+			}
 			nextRebalanceAllowedAt = p.timeSource.Now().Add(p.cfg.RebalanceCooldown)
 
 			p.logger.Info("Rebalancing triggered", tag.Dynamic("triggerReason", triggerReason))
@@ -243,7 +388,7 @@ func (p *namespaceProcessor) runRebalanceTriggeringLoop(ctx context.Context, tri
 }
 
 func (p *namespaceProcessor) rebalanceTriggeringLoop(ctx context.Context, updateChan <-chan int64, triggerChan chan<- string) {
-	ticker := p.timeSource.NewTicker(p.cfg.Period)
+	ticker := p.timeSource.NewTicker(p.nextRebalanceDelay())
 	defer ticker.Stop()
 
 	tryTriggerRebalancing := func(reason string) {
@@ -262,6 +407,7 @@ func (p *namespaceProcessor) rebalanceTriggeringLoop(ctx context.Context, update
 
 		case <-ticker.Chan():
 			tryTriggerRebalancing("Periodic reconciliation triggered")
+			ticker.Reset(p.nextRebalanceDelay())
 
 		case _, ok := <-updateChan:
 			if !ok {
@@ -274,6 +420,25 @@ func (p *namespaceProcessor) rebalanceTriggeringLoop(ctx context.Context, update
 	}
 }
 
+// nextRebalanceDelay returns the delay until the next periodic rebalance of the namespace,
+// read from the current configuration so interval changes apply from the next cycle.
+func (p *namespaceProcessor) nextRebalanceDelay() time.Duration {
+	interval, jitterCoefficient := p.Config().GetRebalanceSchedule(p.namespaceCfg.Name)
+	return computeRebalanceDelay(p.cfg.Period, interval, jitterCoefficient)
+}
+
+// computeRebalanceDelay returns interval randomized by +/- jitterCoefficient * interval,
+// falling back to the process period when no interval is configured.
+func computeRebalanceDelay(period, interval time.Duration, jitterCoefficient float64) time.Duration {
+	if interval <= 0 {
+		interval = period
+	}
+	if jitterCoefficient <= 0 {
+		return interval
+	}
+	return backoff.JitDuration(interval, math.Min(jitterCoefficient, 1))
+}
+
 // runShardStatsCleanupLoop periodically removes stale shard statistics.
 func (p *namespaceProcessor) runShardStatsCleanupLoop(ctx context.Context) {
 	ticker := p.timeSource.NewTicker(p.cfg.HeartbeatTTL)
@@ -287,7 +452,7 @@ func (p *namespaceProcessor) runShardStatsCleanupLoop(ctx context.Context) {
 		case <-ticker.Chan():
 			// Only perform shard stats cleanup in GREEDY load balancing mode
 			// TODO: refactor this to not have this loop for non-GREEDY modes
-			if p.sdConfig.GetLoadBalancingMode(p.namespaceCfg.Name) != types.LoadBalancingModeGREEDY {
+			if p.Config().GetLoadBalancingMode(p.namespaceCfg.Name) != types.LoadBalancingModeGREEDY {
 				p.logger.Debug("Load balancing mode is not GREEDY, skipping shard stats cleanup.", tag.ShardNamespace(p.namespaceCfg.Name))
 				continue
 			}
@@ -298,6 +463,10 @@ func (p *namespaceProcessor) runShardStatsCleanupLoop(ctx context.Context) {
 				p.logger.Error("Failed to get state for shard stats cleanup", tag.Error(err))
 				continue
 			}
+			if namespaceState == nil {
+				// No executor has heartbeated for this namespace yet, so there are no stats to clean up.
+				continue
+			}
 			staleShardStats := p.identifyStaleShardStats(namespaceState)
 			if len(staleShardStats) == 0 {
 				// No stale shard stats to delete
@@ -404,13 +573,46 @@ func (p *namespaceProcessor) rebalanceShards(ctx context.Context) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
 	defer cancel()
 
-	return p.rebalanceShardsImpl(ctx, metricsLoopScope)
+	unassignedReason, unplaced, err := p.rebalanceShardsImpl(ctx, metricsLoopScope)
+	if err != nil {
+		return err
+	}
+	p.leaders.setUnassignedReason(p.namespaceCfg.Name, p.election, unassignedReason)
+	p.leaders.setUnplacedShards(p.namespaceCfg.Name, p.election, unplaced)
+	return nil
 }
 
-func (p *namespaceProcessor) rebalanceShardsImpl(ctx context.Context, metricsLoopScope metrics.Scope) (err error) {
+// rebalanceShardsImpl runs a rebalance pass. It returns why the pass leaves shards unassigned,
+// plan.UnassignedReasonPending when it does not hold any back, and the shards it left out of the plan.
+func (p *namespaceProcessor) rebalanceShardsImpl(
+	ctx context.Context,
+	metricsLoopScope metrics.Scope,
+) (_ plan.UnassignedReason, unplaced map[string]plan.UnassignedReason, err error) {
+	// Read the configuration once so the whole pass sees a consistent snapshot, even if it is swapped meanwhile.
+	sdConfig := p.Config()
+	if sdConfig.IsRebalancePaused(p.namespaceCfg.Name) {
+		// Operators pause rebalancing to freeze shard movement, e.g. during an incident. Heartbeats are
+		// still recorded by the handler, so the next cycle after unpausing works from current state.
+		p.logger.Warn("Rebalancing is paused for the namespace, skipping shard assignment")
+		metricsLoopScope.AddCounter(metrics.ShardDistributorAssignLoopPaused, 1)
+		return plan.UnassignedReasonRebalancePaused, nil, nil
+	}
+
 	namespaceState, err := p.shardStore.GetState(ctx, p.namespaceCfg.Name)
 	if err != nil {
-		return fmt.Errorf("get state: %w", err)
+		return "", nil, fmt.Errorf("get state: %w", err)
+	}
+	if namespaceState == nil {
+		// No executor has heartbeated for this namespace yet.
+		namespaceState = &store.NamespaceState{}
+	}
+	if len(namespaceState.Executors) == 0 {
+		// Nothing can own the shards and nothing needs cleaning up, so the cycle is a no-op:
+		// the shards stay unassigned until the first executor heartbeats.
+		pendingShards := len(getShards(p.namespaceCfg, namespaceState, nil))
+		p.logger.Warn("Namespace has no executors, shards remain unassigned", tag.Counter(pendingShards))
+		metricsLoopScope.UpdateGauge(metrics.ShardDistributorAssignLoopPendingShards, float64(pendingShards))
+		return plan.UnassignedReasonNoExecutors, nil, nil
 	}
 
 	// Identify stale executors that need to be removed
@@ -421,64 +623,171 @@ func (p *namespaceProcessor) rebalanceShardsImpl(ctx context.Context, metricsLoo
 
 	activeExecutors := p.getActiveExecutors(namespaceState, staleExecutors)
 	if len(activeExecutors) == 0 {
-		p.logger.Error("No active executors found. Cannot assign shards.")
+		unassignedReason := plan.UnassignedReasonNoActiveExecutors
+		if allExecutorsDraining(namespaceState, staleExecutors) {
+			// E.g. a full rolling restart: there is nowhere to move shards, so hold the current
+			// assignments until at least one executor becomes active again.
+			unassignedReason = plan.UnassignedReasonAllExecutorsDraining
+			p.logger.Warn("All executors are draining, holding shard assignments until an executor is active")
+			metricsLoopScope.AddCounter(metrics.ShardDistributorAssignLoopAllExecutorsDraining, 1)
+		} else {
+			p.logger.Error("No active executors found. Cannot assign shards.")
+		}
 
 		// Cleanup stale executors even if no active executors remain
 		if len(staleExecutors) > 0 {
 			p.logger.Info("Cleaning up stale executors (no active executors)", tag.ShardExecutors(slices.Collect(maps.Keys(staleExecutors))))
 			if err := p.shardStore.DeleteExecutors(ctx, p.namespaceCfg.Name, slices.Collect(maps.Keys(staleExecutors)), p.election.Guard()); err != nil {
 				p.logger.Error("Failed to delete stale executors", tag.Error(err))
+			} else {
+				p.emitExecutorsDeregistered(namespaceState, staleExecutors)
 			}
 		}
-		return nil
+		return unassignedReason, nil, nil
 	}
 	p.logger.Info("Active executors", tag.ShardExecutors(activeExecutors))
+	p.emitZombieShards(sdConfig, namespaceState, metricsLoopScope)
+	p.emitOverBudgetShards(sdConfig, namespaceState, metricsLoopScope)
 
-	deletedShards := p.findDeletedShards(namespaceState)
+	deletedShards := namespaceState.DeletedShards()
 	if len(deletedShards) > 0 {
 		p.logger.Info("Identified deleted shards", tag.ShardExecutors(slices.Collect(maps.Keys(deletedShards))))
 	}
 	metricsLoopScope.AddCounter(metrics.ShardDistributorAssignLoopDeletedShards, int64(len(deletedShards)))
 
-	shardsToReassign, currentAssignments := p.findShardsToReassign(activeExecutors, namespaceState, deletedShards, staleExecutors)
+	// The stored assignments are the previous plan; they are the warm start, so only shards that lost their owner are placed.
+	previousAssignments, repairedDuplicates := p.repairDuplicateAssignments(namespaceState, staleExecutors, metricsLoopScope)
+	shardsToReassign, currentAssignments := p.findShardsToReassign(activeExecutors, namespaceState, previousAssignments, deletedShards, staleExecutors)
+	p.epoch++
+	p.rng = loadbalancer.NewRand(p.namespaceCfg.Name, p.epoch)
+	trace := newRebalanceTrace(p.namespaceCfg.Name, p.epoch, p.timeSource.Now().UTC(), namespaceState)
 
 	metricsLoopScope.AddCounter(metrics.ShardDistributorAssignLoopNumRebalancedShards, int64(len(shardsToReassign)))
 
+	// Consolidation, moves filling empty executors and load balancing moves share the per-cycle move budget.
+	// Empty executors are filled before load balancing, an idle executor is the largest imbalance there is.
+	nsConfig := sdConfig.NamespaceConfig(p.namespaceCfg.Name)
+	maxMoves := nsConfig.MaxMovesPerCycle
+
+	// At low load the shards are packed onto fewer executors. The executors consolidation empties are left out of
+	// every placement below, so the fill and the load balancer do not undo it while the load stays low.
+	consolidation := p.consolidate(sdConfig, nsConfig, namespaceState, currentAssignments)
+	consolidationMoves := consolidation.Moves
+	if err := applyMoves(currentAssignments, consolidationMoves); err != nil {
+		return "", nil, fmt.Errorf("apply consolidation moves: %w", err)
+	}
+	for _, move := range consolidationMoves {
+		trace.AddStep(rebalancetrace.Step{
+			Phase:      rebalancetrace.PhaseConsolidate,
+			ShardID:    move.ShardID,
+			From:       move.From,
+			Candidates: consolidation.TargetExecutors,
+			To:         move.To,
+			Reason:     "namespace load is below the consolidation threshold",
+		})
+	}
+	if len(consolidation.ScaleDown) > 0 {
+		p.logger.Info("Consolidating shards, executors are eligible for scale-down", tag.ShardExecutors(consolidation.ScaleDown))
+	}
+	placementExecutors := slices.DeleteFunc(slices.Clone(activeExecutors), func(executorID string) bool {
+		return slices.Contains(consolidation.Emptying, executorID)
+	})
+	notFilled := standbyExecutors(namespaceState)
+	for _, executorID := range consolidation.Emptying {
+		notFilled[executorID] = struct{}{}
+	}
+
 	// If there are deleted shards or stale executors, the distribution has changed.
-	assignedToEmptyExecutors := assignShardsToEmptyExecutors(currentAssignments)
-	updatedAssignments := p.updateAssignments(shardsToReassign, activeExecutors, currentAssignments)
-
-	loadBalanceMoves, err := loadbalancer.PlanRebalance(
-		p.sdConfig,
-		p.namespaceCfg.Name,
-		namespaceState,
-		currentAssignments,
-		p.timeSource.Now(),
-		p.logger,
-		metricsLoopScope,
-	)
-	if err != nil {
-		return fmt.Errorf("load balance: %w", err)
+	beforeFill := cloneAssignments(currentAssignments)
+	assignedToEmptyExecutors := false
+	if maxMoves == 0 || len(consolidationMoves) < maxMoves {
+		assignedToEmptyExecutors = assignShardsToEmptyExecutors(
+			currentAssignments,
+			shardLoadsFromStats(namespaceState),
+			sdConfig.GetEmptyExecutorDonorSelection(p.namespaceCfg.Name),
+			loadbalancer.PinnedShards(sdConfig, p.namespaceCfg.Name, namespaceState, p.timeSource.Now()),
+			sdConfig.GetShardLabelSelectors(p.namespaceCfg.Name),
+			executorLabels(namespaceState),
+			notFilled,
+			nsConfig.AssignmentRampCap,
+			maxMoves-len(consolidationMoves),
+		)
+	}
+	emptyExecutors := executorsWithoutShards(beforeFill)
+	fillMoves := movesBetween(beforeFill, currentAssignments)
+	for _, move := range fillMoves {
+		trace.AddStep(rebalancetrace.Step{
+			Phase:      rebalancetrace.PhaseFillEmptyExecutor,
+			ShardID:    move.ShardID,
+			From:       move.From,
+			Candidates: emptyExecutors,
+			To:         move.To,
+			Reason:     "executor owned no shards",
+		})
+	}
+	updatedAssignments, unplaced := p.updateAssignments(sdConfig, nsConfig, namespaceState, shardsToReassign, placementExecutors, currentAssignments, trace)
+	if len(unplaced) > 0 {
+		p.logger.Warn("No executor matches the label selector of shards, they remain unassigned", tag.ShardExecutors(slices.Sorted(maps.Keys(unplaced))))
+	}
+
+	// Without a cap maxMoves is 0, and so is the number of load balancing moves left to plan.
+	var loadBalanceMoves []plan.Move
+	if usedMoves := len(consolidationMoves) + len(fillMoves); maxMoves == 0 || usedMoves < maxMoves {
+		loadBalanceMoves, err = loadbalancer.PlanRebalance(
+			sdConfig,
+			p.namespaceCfg.Name,
+			namespaceState,
+			currentAssignments,
+			maxMoves-usedMoves,
+			p.rng,
+			p.timeSource.Now(),
+			p.logger,
+			metricsLoopScope,
+		)
+		if err != nil {
+			return "", nil, fmt.Errorf("load balance: %w", err)
+		}
+		loadBalanceMoves = slices.DeleteFunc(loadBalanceMoves, func(move plan.Move) bool {
+			return slices.Contains(consolidation.Emptying, move.To)
+		})
 	}
 	if err := applyMoves(currentAssignments, loadBalanceMoves); err != nil {
-		return fmt.Errorf("apply load balance moves: %w", err)
+		return "", nil, fmt.Errorf("apply load balance moves: %w", err)
 	}
 	isRebalancedByShardLoad := len(loadBalanceMoves) > 0
+	for _, move := range loadBalanceMoves {
+		trace.AddStep(rebalancetrace.Step{
+			Phase:   rebalancetrace.PhaseLoadBalance,
+			ShardID: move.ShardID,
+			From:    move.From,
+			To:      move.To,
+			Reason:  "planned by the load balancing mode of the namespace",
+		})
+	}
+	trace.Plan = cloneAssignments(currentAssignments)
+	p.traces.RecordRebalance(*trace)
 
 	p.emitExecutorMetric(namespaceState, metricsLoopScope)
-	loadbalancer.EmitAssignmentImbalanceMetrics(p.sdConfig, p.namespaceCfg.Name, metricsLoopScope, currentAssignments, namespaceState)
+	loadbalancer.EmitAssignmentImbalanceMetrics(sdConfig, p.namespaceCfg.Name, metricsLoopScope, currentAssignments, namespaceState)
+	p.emitAssignmentStability(sdConfig, previousAssignments, currentAssignments, metricsLoopScope)
 
-	distributionChanged := len(deletedShards) > 0 || len(staleExecutors) > 0 || assignedToEmptyExecutors || updatedAssignments || isRebalancedByShardLoad
+	consolidated := len(consolidationMoves) > 0
+	distributionChanged := len(deletedShards) > 0 || len(staleExecutors) > 0 || repairedDuplicates || consolidated || assignedToEmptyExecutors || updatedAssignments || isRebalancedByShardLoad
 	if !distributionChanged {
 		p.logger.Info("No changes to distribution detected. Skipping rebalance.")
-		return nil
+		return plan.UnassignedReasonPending, unplaced, nil
 	}
 
-	newState := p.getNewAssignmentsState(namespaceState, currentAssignments)
+	// Fail closed: a plan that loses or duplicates shards is never written.
+	if err := plan.ValidateAssignment(currentAssignments, getShards(p.namespaceCfg, namespaceState, deletedShards), unplaced); err != nil {
+		return "", nil, fmt.Errorf("reject assignment plan: %w", err)
+	}
+
+	newState := p.getNewAssignmentsState(sdConfig, namespaceState, currentAssignments)
 
 	p.emitOldestExecutorHeartbeatLag(namespaceState, metricsLoopScope)
 
-	if p.sdConfig.GetMigrationMode(p.namespaceCfg.Name) != types.MigrationModeONBOARDED {
+	if sdConfig.GetMigrationMode(p.namespaceCfg.Name) != types.MigrationModeONBOARDED {
 		p.logger.Info("Running rebalancing in shadow mode", tag.Dynamic("old_assignments", namespaceState.ShardAssignments), tag.Dynamic("new_assignments", newState))
 		p.emitActiveShardMetric(namespaceState.ShardAssignments, metricsLoopScope)
 
@@ -487,9 +796,18 @@ func (p *namespaceProcessor) rebalanceShardsImpl(ctx context.Context, metricsLoo
 			if err := p.shardStore.DeleteExecutors(ctx, p.namespaceCfg.Name, slices.Collect(maps.Keys(staleExecutors)), p.election.Guard()); err != nil {
 				p.logger.Error("Failed to delete stale executors in shadow mode", tag.Error(err))
 				// Non-blocking: stale executors in shadow mode will be cleaned up the next cycle
+			} else {
+				p.emitExecutorsDeregistered(namespaceState, staleExecutors)
 			}
 		}
-		return nil
+		return plan.UnassignedReasonShadowMode, unplaced, nil
+	}
+
+	// A cycle that only load balances is applied move by move, other changes such as removing executors must
+	// land together with the moves they cause, so they are written in a single transaction.
+	onlyLoadBalanced := len(deletedShards) == 0 && len(staleExecutors) == 0 && !repairedDuplicates && !consolidated && !assignedToEmptyExecutors && !updatedAssignments
+	if onlyLoadBalanced && nsConfig.RebalanceApplyWorkers > 0 {
+		return plan.UnassignedReasonPending, unplaced, p.applyLoadBalanceMoves(ctx, loadBalanceMoves, newState, nsConfig.RebalanceApplyWorkers, metricsLoopScope)
 	}
 
 	namespaceState.ShardAssignments = newState
@@ -501,13 +819,91 @@ func (p *namespaceProcessor) rebalanceShardsImpl(ctx context.Context, metricsLoo
 		ExecutorsToDelete: staleExecutors,
 	}, p.election.Guard())
 	if err != nil {
-		return fmt.Errorf("assign shards: %w", err)
+		return "", nil, fmt.Errorf("assign shards: %w", err)
 	}
+	p.emitExecutorsDeregistered(namespaceState, staleExecutors)
 
 	p.emitActiveShardMetric(namespaceState.ShardAssignments, metricsLoopScope)
+	return plan.UnassignedReasonPending, unplaced, nil
+}
+
+// applyLoadBalanceMoves writes the load balancing moves of a cycle with up to workers concurrent writes, each
+// guarded by the leadership of the namespace. Moves that are not applied are planned again by the next cycle.
+func (p *namespaceProcessor) applyLoadBalanceMoves(
+	ctx context.Context,
+	moves []plan.Move,
+	newState map[string]store.AssignedState,
+	workers int,
+	metricsLoopScope metrics.Scope,
+) error {
+	p.logger.Info("Applying load balancing moves.", tag.Counter(len(moves)))
+	summary := ApplyMoves(ctx, p.shardStore, p.namespaceCfg.Name, moves, newState, workers, p.election.Guard())
+
+	var errs []error
+	for _, failure := range summary.Failed {
+		p.logger.Warn("Failed to apply load balancing move",
+			tag.ShardKey(failure.Move.ShardID),
+			tag.ShardExecutor(failure.Move.To),
+			tag.Error(failure.Err),
+		)
+		errs = append(errs, failure.Err)
+	}
+	if len(summary.Skipped) > 0 && ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("apply load balancing moves: %d of %d moves not applied: %w", len(moves)-len(summary.Applied), len(moves), err)
+	}
+
+	p.emitActiveShardMetric(newState, metricsLoopScope)
 	return nil
 }
 
+// emitZombieShards reports the assigned shards no executor reported a load for within the zombie shard age.
+func (p *namespaceProcessor) emitZombieShards(sdConfig *config.Config, namespaceState *store.NamespaceState, metricsLoopScope metrics.Scope) {
+	maxAge := sdConfig.GetZombieShardAge(p.namespaceCfg.Name)
+	if maxAge <= 0 {
+		return
+	}
+
+	zombieShards := namespaceState.ZombieShards(p.timeSource.Now(), maxAge)
+	metricsLoopScope.UpdateGauge(metrics.ShardDistributorAssignLoopZombieShards, float64(len(zombieShards)))
+	if len(zombieShards) > 0 {
+		p.logger.Warn("Assigned shards without a recent load report",
+			tag.Dynamic("zombie-shards", zombieShards),
+			tag.Dynamic("zombie-shard-age", maxAge))
+	}
+}
+
+// emitOverBudgetShards reports the shards whose smoothed load exceeds the shard load budget.
+func (p *namespaceProcessor) emitOverBudgetShards(sdConfig *config.Config, namespaceState *store.NamespaceState, metricsLoopScope metrics.Scope) {
+	budget := sdConfig.GetShardLoadBudget(p.namespaceCfg.Name)
+	if budget <= 0 {
+		return
+	}
+
+	overBudgetShards := store.OverBudgetShards(namespaceState.ShardStats, budget)
+	metricsLoopScope.UpdateGauge(metrics.ShardDistributorAssignLoopOverBudgetShards, float64(len(overBudgetShards)))
+	if len(overBudgetShards) > 0 {
+		p.logger.Warn("Shards over the shard load budget",
+			tag.Dynamic("over-budget-shards", overBudgetShards),
+			tag.Dynamic("shard-load-budget", budget))
+	}
+}
+
+// emitExecutorsDeregistered reports the stale executors that were removed from the store.
+func (p *namespaceProcessor) emitExecutorsDeregistered(namespaceState *store.NamespaceState, staleExecutors map[string]int64) {
+	now := p.timeSource.Now().UTC()
+	for _, executorID := range slices.Sorted(maps.Keys(staleExecutors)) {
+		p.executorEvents.ExecutorDeregistered(events.ExecutorEvent{
+			Namespace:  p.namespaceCfg.Name,
+			ExecutorID: executorID,
+			Time:       now,
+			Metadata:   namespaceState.Executors[executorID].Metadata,
+		})
+	}
+}
+
 func (p *namespaceProcessor) emitActiveShardMetric(shardAssignments map[string]store.AssignedState, metricsLoopScope metrics.Scope) {
 	totalActiveShards := 0
 	for _, assignedState := range shardAssignments {
@@ -538,24 +934,67 @@ func (p *namespaceProcessor) emitOldestExecutorHeartbeatLag(namespaceState *stor
 	metricsLoopScope.UpdateGauge(metrics.ShardDistributorOldestExecutorHeartbeatLag, float64(lag.Milliseconds()))
 }
 
-func (p *namespaceProcessor) findDeletedShards(namespaceState *store.NamespaceState) map[string]store.ShardState {
-	deletedShards := make(map[string]store.ShardState)
+// repairDuplicateAssignments returns the stored assignments with every shard owned by a single executor, and
+// whether a duplicate owner was dropped. Of the owners of a duplicated shard, an active executor reporting the
+// shard READY is kept first, then the least loaded one. The stored assignments are not modified, the repair is
+// persisted with the new assignments.
+func (p *namespaceProcessor) repairDuplicateAssignments(
+	namespaceState *store.NamespaceState,
+	staleExecutors map[string]int64,
+	metricsLoopScope metrics.Scope,
+) (map[string]store.AssignedState, bool) {
+	assignments := make(map[string][]string, len(namespaceState.ShardAssignments))
+	for executorID, assignedState := range namespaceState.ShardAssignments {
+		assignments[executorID] = slices.Collect(maps.Keys(assignedState.AssignedShards))
+	}
 
-	for executorID, executor := range namespaceState.Executors {
-		for shardID, shardState := range executor.ReportedShards {
-			if shardState.Status == types.ShardStatusDONE {
-				deletedShards[shardID] = store.ShardState{
-					ExecutorID: executorID,
-				}
-			}
+	shardLoads := shardLoadsFromStats(namespaceState)
+	executorLoads := make(map[string]float64, len(assignments))
+	for executorID, shardIDs := range assignments {
+		for _, shardID := range shardIDs {
+			executorLoads[executorID] += shardLoads[shardID]
+		}
+	}
+
+	isHealthy := func(executorID, shardID string) bool {
+		executor, ok := namespaceState.Executors[executorID]
+		if _, isStale := staleExecutors[executorID]; !ok || isStale || !executor.CanOwnShards() {
+			return false
 		}
+		report, ok := executor.ReportedShards[shardID]
+		return ok && report != nil && report.Status == types.ShardStatusREADY
+	}
+
+	_, moves := plan.RepairDuplicateAssignments(assignments, isHealthy, executorLoads)
+	if len(moves) == 0 {
+		return namespaceState.ShardAssignments, false
 	}
-	return deletedShards
+	metricsLoopScope.AddCounter(metrics.ShardDistributorAssignLoopDuplicateShards, int64(len(moves)))
+
+	repaired := make(map[string]store.AssignedState, len(namespaceState.ShardAssignments))
+	for executorID, assignedState := range namespaceState.ShardAssignments {
+		assignedState.AssignedShards = maps.Clone(assignedState.AssignedShards)
+		repaired[executorID] = assignedState
+	}
+	for _, move := range moves {
+		p.logger.Warn("Dropping duplicate shard owner",
+			tag.ShardKey(move.ShardID),
+			tag.ShardExecutor(move.From),
+			tag.Dynamic("kept-executor", move.To),
+		)
+		delete(repaired[move.From].AssignedShards, move.ShardID)
+	}
+	return repaired, true
 }
 
+// findShardsToReassign starts from previousAssignments and keeps every placement whose executor is still active.
+// It returns the shards that have no usable owner, and the kept placements of the active executors.
+// Every shard of an inactive, stale or unknown executor is returned regardless of its load, so shards
+// without statistics are never stranded on an executor that is gone.
 func (p *namespaceProcessor) findShardsToReassign(
 	activeExecutors []string,
 	namespaceState *store.NamespaceState,
+	previousAssignments map[string]store.AssignedState,
 	deletedShards map[string]store.ShardState,
 	staleExecutors map[string]int64,
 ) ([]string, map[string][]string) {
@@ -571,11 +1010,17 @@ func (p *namespaceProcessor) findShardsToReassign(
 		currentAssignments[executorID] = []string{}
 	}
 
-	for executorID, state := range namespaceState.ShardAssignments {
-		isActive := namespaceState.Executors[executorID].Status == types.ExecutorStatusACTIVE
+	var unknownExecutors []string
+	for _, executorID := range plan.SortedExecutorIDs(previousAssignments) {
+		executor, isKnown := namespaceState.Executors[executorID]
+		isActive := executor.CanOwnShards()
 		_, isStale := staleExecutors[executorID]
+		if !isKnown && len(previousAssignments[executorID].AssignedShards) > 0 {
+			// The executor was deleted or never heartbeated, its shards are treated as unassigned.
+			unknownExecutors = append(unknownExecutors, executorID)
+		}
 
-		for shardID := range state.AssignedShards {
+		for _, shardID := range slices.Sorted(maps.Keys(previousAssignments[executorID].AssignedShards)) {
 			if _, ok := allShards[shardID]; ok {
 				delete(allShards, shardID)
 				// If executor is active AND not stale, keep the assignment
@@ -589,25 +1034,240 @@ func (p *namespaceProcessor) findShardsToReassign(
 		}
 	}
 
-	for shardID := range allShards {
+	if len(unknownExecutors) > 0 {
+		p.logger.Warn("Reassigning shards assigned to unknown executors", tag.ShardExecutors(unknownExecutors))
+	}
+
+	for _, shardID := range slices.Sorted(maps.Keys(allShards)) {
 		shardsToReassign = append(shardsToReassign, shardID)
 	}
 	return shardsToReassign, currentAssignments
 }
 
-func (*namespaceProcessor) updateAssignments(shardsToReassign []string, activeExecutors []string, currentAssignments map[string][]string) (distributionChanged bool) {
+// updateAssignments distributes shardsToReassign round robin over the active executors, starting at a random one.
+// Executors in a failure zone that already holds the maximum number of shards of a shard's group are skipped,
+// unless every executor is in such a zone. Executors holding the executor shard cap, or that already received the
+// assignment ramp cap of new shards in this cycle, are skipped the same way. A shard with a label selector only goes
+// to executors whose labels match it. When no active executor does, the shard is left unassigned and returned in
+// unplaced with plan.UnassignedReasonNoMatchingExecutor.
+// Pinned shards are placed on their required executor when it is active. Shards that had an owner are failed over
+// to eligible warm standbys first, other shards never go to a standby unless no other executor is eligible.
+// Every placement is added to trace, which may be nil.
+func (p *namespaceProcessor) updateAssignments(
+	sdConfig *config.Config,
+	nsConfig config.NamespaceConfig,
+	namespaceState *store.NamespaceState,
+	shardsToReassign []string,
+	activeExecutors []string,
+	currentAssignments map[string][]string,
+	trace *rebalancetrace.Trace,
+) (distributionChanged bool, unplaced map[string]plan.UnassignedReason) {
+	unplaced = make(map[string]plan.UnassignedReason)
 	if len(shardsToReassign) == 0 {
-		return false
+		return false, unplaced
+	}
+
+	shardGroups, maxGroupShardsPerZone := sdConfig.GetZoneSpread(p.namespaceCfg.Name)
+	spread := newZoneSpread(namespaceState, currentAssignments, shardGroups, maxGroupShardsPerZone)
+	pinnedShards := loadbalancer.PinnedShards(sdConfig, p.namespaceCfg.Name, namespaceState, p.timeSource.Now())
+	selectors := sdConfig.GetShardLabelSelectors(p.namespaceCfg.Name)
+	labels := executorLabels(namespaceState)
+
+	totalShards := len(shardsToReassign)
+	for _, shards := range currentAssignments {
+		totalShards += len(shards)
+	}
+	shardCap := sdConfig.GetExecutorShardCap(p.namespaceCfg.Name, totalShards, len(activeExecutors))
+	belowCap := func(executorID string) bool {
+		return shardCap <= 0 || len(currentAssignments[executorID]) < shardCap
+	}
+	rampCap := nsConfig.AssignmentRampCap
+	newShards := newShardCounts(namespaceState, currentAssignments)
+	belowRamp := func(executorID string) bool {
+		return rampCap <= 0 || newShards[executorID] < rampCap
 	}
 
-	i := rand.Intn(len(activeExecutors))
+	// Shards of executors that are gone are failed over to warm standbys first. Standbys are not given
+	// other shards, e.g. new ones, so they stay empty during normal operation.
+	standbys := standbyExecutors(namespaceState)
+	failedOver := assignedShards(namespaceState)
+
+	i := p.rng.Intn(len(activeExecutors))
 	for _, shardID := range shardsToReassign {
-		executorID := activeExecutors[i%len(activeExecutors)]
+		_, isFailover := failedOver[shardID]
+		if required := pinnedShards[shardID]; required != "" && slices.Contains(activeExecutors, required) {
+			spread.record(shardID, required)
+			currentAssignments[required] = append(currentAssignments[required], shardID)
+			newShards[required]++
+			distributionChanged = true
+			trace.AddStep(rebalancetrace.Step{
+				Phase:      rebalancetrace.PhaseReassign,
+				ShardID:    shardID,
+				Candidates: []string{required},
+				To:         required,
+				Reason:     "pinned to executor",
+			})
+			continue
+		}
+		var candidates, matching []string
+		for offset := range activeExecutors {
+			candidate := activeExecutors[(i+offset)%len(activeExecutors)]
+			if !plan.MatchesSelector(labels[candidate], selectors[shardID]) {
+				continue
+			}
+			matching = append(matching, candidate)
+			_, isStandby := standbys[candidate]
+			if spread.allows(shardID, candidate) && belowCap(candidate) && belowRamp(candidate) && (isFailover || !isStandby) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if isFailover {
+			// Standbys and the other executors each keep their round robin order.
+			var standbyCandidates, otherCandidates []string
+			for _, candidate := range candidates {
+				if _, isStandby := standbys[candidate]; isStandby {
+					standbyCandidates = append(standbyCandidates, candidate)
+				} else {
+					otherCandidates = append(otherCandidates, candidate)
+				}
+			}
+			candidates = append(standbyCandidates, otherCandidates...)
+		}
+		executorID, reason := activeExecutors[i%len(activeExecutors)], "no executor within the zone spread, shard cap and ramp cap"
+		switch {
+		case len(candidates) > 0:
+			executorID, reason = candidates[0], "next eligible executor in round robin order"
+			if _, isStandby := standbys[executorID]; isStandby {
+				reason = "warm standby taking over a shard of an executor that is gone"
+			}
+		case len(matching) > 0:
+			// The label selector is a hard requirement, unlike the caps and the zone spread.
+			executorID, reason = matching[0], "no executor matching the label selector within the zone spread, shard cap and ramp cap"
+		case len(selectors[shardID]) > 0:
+			// The shard stays unassigned until an executor matching its selector is active.
+			unplaced[shardID] = plan.UnassignedReasonNoMatchingExecutor
+			// Dropping a shard from an executor that is gone still changes the distribution.
+			distributionChanged = distributionChanged || isFailover
+			trace.AddStep(rebalancetrace.Step{
+				Phase:   rebalancetrace.PhaseReassign,
+				ShardID: shardID,
+				Reason:  "no executor matches the label selector",
+			})
+			continue
+		}
+		spread.record(shardID, executorID)
 		currentAssignments[executorID] = append(currentAssignments[executorID], shardID)
+		newShards[executorID]++
+		distributionChanged = true
 		i++
+		trace.AddStep(rebalancetrace.Step{
+			Phase:      rebalancetrace.PhaseReassign,
+			ShardID:    shardID,
+			Candidates: candidates,
+			To:         executorID,
+			Reason:     reason,
+		})
 	}
 
-	return true
+	return distributionChanged, unplaced
+}
+
+// standbyExecutors returns the IDs of the warm standby executors of the namespace.
+func standbyExecutors(namespaceState *store.NamespaceState) map[string]struct{} {
+	standbys := make(map[string]struct{})
+	for executorID, executor := range namespaceState.Executors {
+		if executor.IsStandby() {
+			standbys[executorID] = struct{}{}
+		}
+	}
+	return standbys
+}
+
+// executorLabels returns the labels each executor of the namespace reported, keyed by executor ID.
+func executorLabels(namespaceState *store.NamespaceState) map[string]map[string]string {
+	labels := make(map[string]map[string]string, len(namespaceState.Executors))
+	for executorID, executor := range namespaceState.Executors {
+		labels[executorID] = executor.Labels()
+	}
+	return labels
+}
+
+// assignedShards returns the IDs of the shards assigned to any executor in namespaceState.
+func assignedShards(namespaceState *store.NamespaceState) map[string]struct{} {
+	shards := make(map[string]struct{})
+	for _, assignedState := range namespaceState.ShardAssignments {
+		for shardID := range assignedState.AssignedShards {
+			shards[shardID] = struct{}{}
+		}
+	}
+	return shards
+}
+
+// newShardCounts counts, per executor, the shards in currentAssignments that the executor did not own in namespaceState.
+func newShardCounts(namespaceState *store.NamespaceState, currentAssignments map[string][]string) map[string]int {
+	counts := make(map[string]int, len(currentAssignments))
+	for executorID, shards := range currentAssignments {
+		previousShards := namespaceState.ShardAssignments[executorID].AssignedShards
+		for _, shardID := range shards {
+			if _, ok := previousShards[shardID]; !ok {
+				counts[executorID]++
+			}
+		}
+	}
+	return counts
+}
+
+// zoneSpread limits how many shards of the same group are assigned to executors in one failure zone,
+// so losing a zone does not take out a whole group. Ungrouped shards and executors without a zone are not limited.
+type zoneSpread struct {
+	shardGroups           map[string]string
+	maxGroupShardsPerZone int
+	executorZones         map[string]string
+	// groupShardsPerZone counts the assigned shards of each group per zone, keyed by group and then zone.
+	groupShardsPerZone map[string]map[string]int
+}
+
+func newZoneSpread(namespaceState *store.NamespaceState, currentAssignments map[string][]string, shardGroups map[string]string, maxGroupShardsPerZone int) *zoneSpread {
+	spread := &zoneSpread{
+		shardGroups:           shardGroups,
+		maxGroupShardsPerZone: maxGroupShardsPerZone,
+		executorZones:         make(map[string]string, len(namespaceState.Executors)),
+		groupShardsPerZone:    make(map[string]map[string]int),
+	}
+	if len(shardGroups) == 0 {
+		return spread
+	}
+
+	for executorID, executor := range namespaceState.Executors {
+		spread.executorZones[executorID] = executor.Zone()
+	}
+	for executorID, shards := range currentAssignments {
+		for _, shardID := range shards {
+			spread.record(shardID, executorID)
+		}
+	}
+	return spread
+}
+
+func (z *zoneSpread) allows(shardID, executorID string) bool {
+	group, ok := z.shardGroups[shardID]
+	zone := z.executorZones[executorID]
+	if !ok || zone == "" {
+		return true
+	}
+	return z.groupShardsPerZone[group][zone] < z.maxGroupShardsPerZone
+}
+
+func (z *zoneSpread) record(shardID, executorID string) {
+	group, ok := z.shardGroups[shardID]
+	zone := z.executorZones[executorID]
+	if !ok || zone == "" {
+		return
+	}
+	if z.groupShardsPerZone[group] == nil {
+		z.groupShardsPerZone[group] = make(map[string]int)
+	}
+	z.groupShardsPerZone[group][zone]++
 }
 
 func applyMoves(currentAssignments map[string][]string, moves []plan.Move) error {
@@ -624,14 +1284,93 @@ func applyMoves(currentAssignments map[string][]string, moves []plan.Move) error
 	return nil
 }
 
-func (p *namespaceProcessor) getNewAssignmentsState(namespaceState *store.NamespaceState, currentAssignments map[string][]string) map[string]store.AssignedState {
+// newRebalanceTrace returns the trace of a rebalance pass over namespaceState, holding the inputs of the pass.
+func newRebalanceTrace(namespace string, epoch int64, now time.Time, namespaceState *store.NamespaceState) *rebalancetrace.Trace {
+	trace := &rebalancetrace.Trace{
+		Namespace:           namespace,
+		Epoch:               epoch,
+		Time:                now,
+		ExecutorLoads:       make(map[string]float64, len(namespaceState.ShardAssignments)),
+		ShardStatsUpdated:   make(map[string]time.Time, len(namespaceState.ShardStats)),
+		AssignmentRevisions: make(map[string]int64, len(namespaceState.ShardAssignments)),
+	}
+	for executorID, assigned := range namespaceState.ShardAssignments {
+		trace.AssignmentRevisions[executorID] = assigned.ModRevision
+		for shardID := range assigned.AssignedShards {
+			trace.ExecutorLoads[executorID] += namespaceState.ShardStats[shardID].Load()
+		}
+	}
+	for shardID, stats := range namespaceState.ShardStats {
+		trace.ShardStatsUpdated[shardID] = stats.LastUpdateTime
+	}
+	return trace
+}
+
+// movesBetween returns the moves that turn the before assignments into the after assignments, ordered by shard ID.
+// Shards that are not assigned in both are ignored.
+func movesBetween(before, after map[string][]string) []plan.Move {
+	owners := make(map[string]string)
+	for executorID, shards := range before {
+		for _, shardID := range shards {
+			owners[shardID] = executorID
+		}
+	}
+
+	var moves []plan.Move
+	for executorID, shards := range after {
+		for _, shardID := range shards {
+			if from, ok := owners[shardID]; ok && from != executorID {
+				moves = append(moves, plan.Move{ShardID: shardID, From: from, To: executorID})
+			}
+		}
+	}
+	slices.SortFunc(moves, func(a, b plan.Move) int { return strings.Compare(a.ShardID, b.ShardID) })
+	return moves
+}
+
+func executorsWithoutShards(assignments map[string][]string) []string {
+	var executors []string
+	for _, executorID := range plan.SortedExecutorIDs(assignments) {
+		if len(assignments[executorID]) == 0 {
+			executors = append(executors, executorID)
+		}
+	}
+	return executors
+}
+
+func cloneAssignments(assignments map[string][]string) map[string][]string {
+	cloned := make(map[string][]string, len(assignments))
+	for executorID, shards := range assignments {
+		cloned[executorID] = slices.Clone(shards)
+	}
+	return cloned
+}
+
+// getNewAssignmentsState builds the assigned states to write from currentAssignments.
+// The result shares no maps, slices or assignments with namespaceState or currentAssignments,
+// so callers may modify it without affecting the state it was built from.
+func (p *namespaceProcessor) getNewAssignmentsState(sdConfig *config.Config, namespaceState *store.NamespaceState, currentAssignments map[string][]string) map[string]store.AssignedState {
 	newState := make(map[string]store.AssignedState, len(currentAssignments))
+	now := p.timeSource.Now().UTC()
+	// The lease is renewed on every heartbeat; stamping it here bounds how long a
+	// newly placed shard may run before its owner heartbeats for the first time.
+	leaseExpiresAt := sdConfig.GetShardLeaseExpiry(p.namespaceCfg.Name, now)
 
 	for executorID, shards := range currentAssignments {
 		assignedShardsMap := make(map[string]*types.ShardAssignment)
+		previousShards := namespaceState.ShardAssignments[executorID].AssignedShards
 
 		for _, shardID := range shards {
-			assignedShardsMap[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+			// Keep the original assignment time if the shard stays on the same executor.
+			assignedAt := now
+			if previous, ok := previousShards[shardID]; ok && previous != nil && !previous.AssignedAt.IsZero() {
+				assignedAt = previous.AssignedAt
+			}
+			assignedShardsMap[shardID] = &types.ShardAssignment{
+				Status:         types.AssignmentStatusREADY,
+				AssignedAt:     assignedAt,
+				LeaseExpiresAt: leaseExpiresAt,
+			}
 		}
 
 		modRevision := int64(0) // Should be 0 if we have not seen it yet
@@ -641,7 +1380,7 @@ func (p *namespaceProcessor) getNewAssignmentsState(namespaceState *store.Namesp
 
 		newState[executorID] = store.AssignedState{
 			AssignedShards:     assignedShardsMap,
-			LastUpdated:        p.timeSource.Now().UTC(),
+			LastUpdated:        now,
 			ModRevision:        modRevision,
 			ShardHandoverStats: p.addHandoverStatsToExecutorAssignedState(namespaceState, executorID, shards),
 		}
@@ -726,32 +1465,58 @@ func (p *namespaceProcessor) newHandoverStats(
 
 func (*namespaceProcessor) getActiveExecutors(namespaceState *store.NamespaceState, staleExecutors map[string]int64) []string {
 	var activeExecutors []string
-	for id, state := range namespaceState.Executors {
-		// Executor must be ACTIVE and not stale
-		if state.Status == types.ExecutorStatusACTIVE {
+	for _, id := range plan.SortedExecutorIDs(namespaceState.Executors) {
+		// Executor must be ACTIVE, not an observer and not stale
+		if namespaceState.Executors[id].CanOwnShards() {
 			if _, ok := staleExecutors[id]; !ok {
 				activeExecutors = append(activeExecutors, id)
 			}
 		}
 	}
 
-	sort.Strings(activeExecutors)
 	return activeExecutors
 }
 
-func assignShardsToEmptyExecutors(currentAssignments map[string][]string) bool {
+// allExecutorsDraining reports whether the namespace has executors that are not stale and all of them are draining.
+func allExecutorsDraining(namespaceState *store.NamespaceState, staleExecutors map[string]int64) bool {
+	draining := 0
+	for executorID, executor := range namespaceState.Executors {
+		if _, stale := staleExecutors[executorID]; stale {
+			continue
+		}
+		if executor.Status != types.ExecutorStatusDRAINING {
+			return false
+		}
+		draining++
+	}
+	return draining > 0
+}
+
+// assignShardsToEmptyExecutors moves shards from executors that own shards onto executors that own none.
+// The donorSelection strategy decides which of a donor's shards is taken, based on shardLoads.
+// Shards without a known load are treated as having zero load. Pinned shards are never taken, and a shard
+// with a label selector is only given to an empty executor whose labels in executorLabels match it.
+// A positive rampCap limits how many shards each empty executor receives, so it fills up over several cycles.
+// A positive maxMoves limits how many shards are moved in total. Empty executors in notFilled, such as warm
+// standbys, are not filled.
+func assignShardsToEmptyExecutors(
+	currentAssignments map[string][]string,
+	shardLoads map[string]float64,
+	donorSelection string,
+	pinnedShards map[string]string,
+	selectors map[string]map[string]string,
+	executorLabels map[string]map[string]string,
+	notFilled map[string]struct{},
+	rampCap, maxMoves int,
+) bool {
 	emptyExecutors := make([]string, 0)
 	executorsWithShards := make([]string, 0)
 	minShardsCurrentlyAssigned := 0
 
-	// Ensure the iteration is deterministic.
-	executors := make([]string, 0, len(currentAssignments))
-	for executorID := range currentAssignments {
-		executors = append(executors, executorID)
-	}
-	slices.Sort(executors)
-
-	for _, executorID := range executors {
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		if _, skip := notFilled[executorID]; skip && len(currentAssignments[executorID]) == 0 {
+			continue
+		}
 		if len(currentAssignments[executorID]) == 0 {
 			emptyExecutors = append(emptyExecutors, executorID)
 		} else {
@@ -773,24 +1538,249 @@ func assignShardsToEmptyExecutors(currentAssignments map[string][]string) bool {
 	// We then calculate the total number of assumed shards `minShardsCurrentlyAssigned * len(executorsWithShards)` and divide it by the
 	// number of current executors. This gives us the number of shards per executor, thus the number of shards to assign to each of the
 	// empty executors.
-	numShardsToAssignEmptyExecutors := minShardsCurrentlyAssigned * len(executorsWithShards) / len(currentAssignments)
+	numShardsToAssignEmptyExecutors := minShardsCurrentlyAssigned * len(executorsWithShards) / (len(emptyExecutors) + len(executorsWithShards))
+	if rampCap > 0 {
+		numShardsToAssignEmptyExecutors = min(numShardsToAssignEmptyExecutors, rampCap)
+	}
+
+	// The target load is the average load per executor, used by the closest-to-target strategy.
+	totalLoad := 0.0
+	for _, executorID := range executorsWithShards {
+		for _, shardID := range currentAssignments[executorID] {
+			totalLoad += shardLoads[shardID]
+		}
+	}
+	targetLoad := plan.SafeDivide(totalLoad, float64(len(currentAssignments)), 0)
+	emptyExecutorLoads := make(map[string]float64, len(emptyExecutors))
 
 	stealRound := 0
+	moved := 0
 	for i := 0; i < numShardsToAssignEmptyExecutors; i++ {
 		for _, emptyExecutor := range emptyExecutors {
+			if maxMoves > 0 && moved >= maxMoves {
+				return true
+			}
 			executorToSteelFrom := executorsWithShards[stealRound%len(executorsWithShards)]
 			stealRound++
 
-			stolenShard := currentAssignments[executorToSteelFrom][0]
+			donorShards := currentAssignments[executorToSteelFrom]
+			stolenIdx := selectShardToSteal(
+				donorShards,
+				shardLoads,
+				donorSelection,
+				targetLoad-emptyExecutorLoads[emptyExecutor],
+				pinnedShards,
+				selectors,
+				executorLabels[emptyExecutor],
+			)
+			if stolenIdx < 0 {
+				continue
+			}
+			stolenShard := donorShards[stolenIdx]
 
-			currentAssignments[executorToSteelFrom] = currentAssignments[executorToSteelFrom][1:]
+			currentAssignments[executorToSteelFrom] = slices.Delete(donorShards, stolenIdx, stolenIdx+1)
 			currentAssignments[emptyExecutor] = append(currentAssignments[emptyExecutor], stolenShard)
+			emptyExecutorLoads[emptyExecutor] += shardLoads[stolenShard]
+			moved++
 		}
 	}
 
 	return true
 }
 
+// selectShardToSteal returns the index of the shard to take from the donor shards, or -1 when all of them are pinned
+// or have a label selector the receiving executor's labels do not match.
+// Ties are resolved in favour of the earliest shard, so without load information the
+// first shard is taken regardless of the strategy.
+func selectShardToSteal(
+	donorShards []string,
+	shardLoads map[string]float64,
+	donorSelection string,
+	remainingLoad float64,
+	pinnedShards map[string]string,
+	selectors map[string]map[string]string,
+	receiverLabels map[string]string,
+) int {
+	score := func(shardID string) float64 {
+		load := shardLoads[shardID]
+		switch donorSelection {
+		case config.DonorSelectionLightestFirst:
+			return load
+		case config.DonorSelectionClosestToTarget:
+			return math.Abs(remainingLoad - load)
+		default:
+			return -load
+		}
+	}
+
+	bestIdx := -1
+	bestScore := 0.0
+	for idx, shardID := range donorShards {
+		if _, pinned := pinnedShards[shardID]; pinned {
+			continue
+		}
+		if !plan.MatchesSelector(receiverLabels, selectors[shardID]) {
+			continue
+		}
+		if s := score(shardID); bestIdx < 0 || s < bestScore {
+			bestIdx, bestScore = idx, s
+		}
+	}
+	return bestIdx
+}
+
+// consolidationPlan is the outcome of consolidate.
+type consolidationPlan struct {
+	// TargetExecutors are the executors that keep owning shards after consolidation.
+	TargetExecutors []string
+	// Moves are the shard moves that pack the shards onto TargetExecutors, at most MaxMovesPerCycle of them.
+	Moves []plan.Move
+	// Emptying are the executors consolidation moves the shards off. The shards the move cap leaves on them
+	// are moved in later cycles.
+	Emptying []string
+	// ScaleDown are the executors of Emptying that Moves leave without shards, which can be scaled down.
+	ScaleDown []string
+}
+
+// consolidate packs shards onto the minimum number of executors when the total shard load of the
+// namespace is below the configured fraction of its capacity, e.g. during off-peak hours.
+// Pinned shards and shards still in their per-shard cooldown are not moved, so their executors are always kept, and at
+// least the configured minimum number of active executors keep their shards. A positive MaxMovesPerCycle caps
+// the moves, the heaviest shards are moved first.
+// The returned plan is empty when consolidation is disabled or not needed; currentAssignments is not modified.
+func (p *namespaceProcessor) consolidate(
+	sdConfig *config.Config,
+	nsConfig config.NamespaceConfig,
+	namespaceState *store.NamespaceState,
+	currentAssignments map[string][]string,
+) consolidationPlan {
+	executorCapacity, threshold, ok := sdConfig.GetConsolidationSettings(p.namespaceCfg.Name)
+	if !ok || len(currentAssignments) <= 1 {
+		return consolidationPlan{}
+	}
+
+	shardLoads := shardLoadsFromStats(namespaceState)
+	executorLoads := make(map[string]float64, len(currentAssignments))
+	totalLoad := 0.0
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		executorLoads[executorID] = 0
+		for _, shardID := range currentAssignments[executorID] {
+			executorLoads[executorID] += shardLoads[shardID]
+		}
+		totalLoad += executorLoads[executorID]
+	}
+	executorIDs := plan.SortExecutorsByLoad(executorLoads, false)
+
+	if totalLoad >= threshold*executorCapacity*float64(len(executorIDs)) {
+		return consolidationPlan{}
+	}
+
+	now := p.timeSource.Now()
+	cooldown := nsConfig.PerShardCooldown
+	pinnedShards := loadbalancer.PinnedShards(sdConfig, p.namespaceCfg.Name, namespaceState, now)
+	unmovable := func(shardID string) bool {
+		if _, pinned := pinnedShards[shardID]; pinned {
+			return true
+		}
+		stats, ok := namespaceState.ShardStats[shardID]
+		return ok && plan.InCooldown(stats.LastMoveTime, now, cooldown)
+	}
+
+	// Executors owning pinned shards or shards in cooldown must be kept, the rest are kept by descending load
+	// so the heaviest executors stay and the fewest shards move.
+	pinned := make(map[string]bool)
+	for _, executorID := range executorIDs {
+		pinned[executorID] = slices.ContainsFunc(currentAssignments[executorID], unmovable)
+	}
+	slices.SortStableFunc(executorIDs, func(a, b string) int {
+		if pinned[a] != pinned[b] {
+			if pinned[a] {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	required := max(1, int(math.Ceil(totalLoad/executorCapacity)), nsConfig.MinActiveExecutors)
+	keep := 0
+	for keep < len(executorIDs) && (keep < required || pinned[executorIDs[keep]]) {
+		keep++
+	}
+	if keep == len(executorIDs) {
+		return consolidationPlan{}
+	}
+
+	targets := slices.Clone(executorIDs[:keep])
+	emptying := slices.Clone(executorIDs[keep:])
+	slices.Sort(targets)
+	slices.Sort(emptying)
+
+	type shardToMove struct {
+		shardID string
+		from    string
+	}
+	var shardsToMove []shardToMove
+	for _, executorID := range emptying {
+		for _, shardID := range currentAssignments[executorID] {
+			shardsToMove = append(shardsToMove, shardToMove{shardID: shardID, from: executorID})
+		}
+	}
+	// Place the heaviest shards first, each onto the least loaded target.
+	slices.SortFunc(shardsToMove, func(a, b shardToMove) int {
+		if shardLoads[a.shardID] > shardLoads[b.shardID] {
+			return -1
+		}
+		if shardLoads[a.shardID] < shardLoads[b.shardID] {
+			return 1
+		}
+		return strings.Compare(a.shardID, b.shardID)
+	})
+
+	moves := make([]plan.Move, 0, len(shardsToMove))
+	for _, shard := range shardsToMove {
+		destination := targets[0]
+		for _, executorID := range targets[1:] {
+			if executorLoads[executorID] < executorLoads[destination] {
+				destination = executorID
+			}
+		}
+		executorLoads[destination] += shardLoads[shard.shardID]
+		moves = append(moves, plan.Move{ShardID: shard.shardID, From: shard.from, To: destination})
+	}
+	if maxMoves := nsConfig.MaxMovesPerCycle; maxMoves > 0 && len(moves) > maxMoves {
+		moves = moves[:maxMoves]
+	}
+
+	// An executor is only left without shards when all of its shards made it into the capped moves.
+	shardsLeft := make(map[string]int, len(emptying))
+	for _, executorID := range emptying {
+		shardsLeft[executorID] = len(currentAssignments[executorID])
+	}
+	for _, move := range moves {
+		shardsLeft[move.From]--
+	}
+	scaleDown := slices.DeleteFunc(slices.Clone(emptying), func(executorID string) bool {
+		return shardsLeft[executorID] > 0
+	})
+
+	return consolidationPlan{
+		TargetExecutors: targets,
+		Moves:           moves,
+		Emptying:        emptying,
+		ScaleDown:       scaleDown,
+	}
+}
+
+// shardLoadsFromStats returns the load of every shard with statistics in the namespace.
+func shardLoadsFromStats(namespaceState *store.NamespaceState) map[string]float64 {
+	shardLoads := make(map[string]float64, len(namespaceState.ShardStats))
+	for shardID, stats := range namespaceState.ShardStats {
+		shardLoads[shardID] = stats.Load()
+	}
+	return shardLoads
+}
+
 func getShards(cfg config.Namespace, namespaceState *store.NamespaceState, deletedShards map[string]store.ShardState) []string {
 	if cfg.Type == config.NamespaceTypeFixed {
 		return makeShards(cfg.ShardNum)
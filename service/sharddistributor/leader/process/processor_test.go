@@ -3,14 +3,17 @@ package process
 import (
 	"context"
 	"errors"
+	"maps"
 	"slices"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
 
@@ -23,7 +26,11 @@ import (
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/config/configtest"
+	"github.com/uber/cadence/service/sharddistributor/events"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/strategy/consistenthash"
+	"github.com/uber/cadence/service/sharddistributor/rebalancetrace"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -35,6 +42,29 @@ type testDependencies struct {
 	factory    Factory
 	cfg        config.Namespace
 	sdConfig   *config.Config
+	events     *recordingExecutorEvents
+	traces     *recordingTraceSink
+}
+
+type recordingExecutorEvents struct {
+	registered   []events.ExecutorEvent
+	deregistered []events.ExecutorEvent
+}
+
+func (r *recordingExecutorEvents) ExecutorRegistered(event events.ExecutorEvent) {
+	r.registered = append(r.registered, event)
+}
+
+func (r *recordingExecutorEvents) ExecutorDeregistered(event events.ExecutorEvent) {
+	r.deregistered = append(r.deregistered, event)
+}
+
+type recordingTraceSink struct {
+	traces []rebalancetrace.Trace
+}
+
+func (r *recordingTraceSink) RecordRebalance(trace rebalancetrace.Trace) {
+	r.traces = append(r.traces, trace)
 }
 
 func setupProcessorTest(t *testing.T, namespaceType string) *testDependencies {
@@ -66,6 +96,8 @@ func setupProcessorTestWithMigrationConfig(t *testing.T, namespaceType string, m
 		},
 		MigrationMode: migrationConfig.MigrationMode,
 	}
+	deps.events = &recordingExecutorEvents{}
+	deps.traces = &recordingTraceSink{}
 
 	deps.factory = NewProcessorFactory(
 		testlogger.New(t),
@@ -78,6 +110,8 @@ func setupProcessorTestWithMigrationConfig(t *testing.T, namespaceType string, m
 			},
 		},
 		deps.sdConfig,
+		deps.events,
+		deps.traces,
 	)
 	return deps
 }
@@ -92,10 +126,20 @@ func TestRunAndTerminate(t *testing.T) {
 
 	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{}, nil).AnyTimes()
 	mocks.store.EXPECT().SubscribeToExecutorStatusChanges(gomock.Any(), mocks.cfg.Name).Return(make(chan int64), nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+
+	_, ok := mocks.factory.LeaderGuard(mocks.cfg.Name)
+	assert.False(t, ok, "no guard before the processor runs")
+	_, ok = mocks.factory.UnassignedReason(mocks.cfg.Name, "0")
+	assert.False(t, ok, "no unassigned reason before the processor runs")
 
 	err := processor.Run(ctx)
 	require.NoError(t, err)
 
+	guard, ok := mocks.factory.LeaderGuard(mocks.cfg.Name)
+	assert.True(t, ok)
+	assert.NotNil(t, guard)
+
 	err = processor.Run(ctx)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "processor is already running")
@@ -103,6 +147,11 @@ func TestRunAndTerminate(t *testing.T) {
 	err = processor.Terminate(context.Background())
 	require.NoError(t, err)
 
+	_, ok = mocks.factory.LeaderGuard(mocks.cfg.Name)
+	assert.False(t, ok, "no guard once the processor terminated")
+	_, ok = mocks.factory.UnassignedReason(mocks.cfg.Name, "0")
+	assert.False(t, ok, "no unassigned reason once the processor terminated")
+
 	err = processor.Terminate(context.Background())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "processor has not been started")
@@ -139,6 +188,262 @@ func TestRebalanceShards_InitialDistribution(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRebalanceShards_AllExecutorsDraining(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	logger, logs := testlogger.NewObserved(t)
+	processor.logger = logger
+
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusDRAINING, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusDRAINING, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		},
+	}, nil)
+	// No AssignShards expectation: the assignments must be held as they are.
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	unassignedReason, _, err := processor.rebalanceShardsImpl(context.Background(), metricsScope)
+	require.NoError(t, err)
+	assert.Equal(t, plan.UnassignedReasonAllExecutorsDraining, unassignedReason)
+
+	assert.Equal(t, 1, logs.FilterMessage("All executors are draining, holding shard assignments until an executor is active").Len())
+	assert.Equal(t, 0, logs.FilterMessage("No active executors found. Cannot assign shards.").Len())
+	counter, ok := testScope.Snapshot().Counters()["test.shard_distributor_shard_assign_all_executors_draining+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, int64(1), counter.Value())
+}
+
+func TestRebalanceShards_Paused(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	paused := *mocks.sdConfig
+	paused.RebalancePaused = func(namespace string) bool { return true }
+	processor.SetConfig(&paused)
+
+	// exec-1 holds every shard while exec-2 holds none.
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{
+				"0": {Status: types.AssignmentStatusREADY},
+				"1": {Status: types.AssignmentStatusREADY},
+			}},
+		},
+	}, nil).AnyTimes()
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	// No AssignShards expectation: a paused namespace produces no moves.
+	unassignedReason, _, err := processor.rebalanceShardsImpl(context.Background(), metricsScope)
+	require.NoError(t, err)
+	assert.Equal(t, plan.UnassignedReasonRebalancePaused, unassignedReason)
+	counter, ok := testScope.Snapshot().Counters()["test.shard_distributor_shard_assign_paused+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, int64(1), counter.Value())
+
+	// Unpausing resumes rebalancing.
+	processor.SetConfig(mocks.sdConfig)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Len(t, request.NewState.ShardAssignments["exec-1"].AssignedShards, 1)
+			assert.Len(t, request.NewState.ShardAssignments["exec-2"].AssignedShards, 1)
+			return nil
+		},
+	)
+	unassignedReason, _, err = processor.rebalanceShardsImpl(context.Background(), metricsScope)
+	require.NoError(t, err)
+	assert.Equal(t, plan.UnassignedReasonPending, unassignedReason)
+}
+
+func TestRebalanceShards_StaticHashFallback(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// The shards are balanced, but at least one of them is not on its owner in the hash ring.
+	ring := consistenthash.NewRing([]string{"exec-1", "exec-2"})
+	owner0, _ := ring.Owner("0")
+	owner1, _ := ring.Owner("1")
+	other := func(executorID string) string {
+		if executorID == "exec-1" {
+			return "exec-2"
+		}
+		return "exec-1"
+	}
+	executorOf0 := owner0
+	if owner0 != owner1 {
+		executorOf0 = other(owner0)
+	}
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			executorOf0: {AssignedShards: map[string]*types.ShardAssignment{
+				"0": {Status: types.AssignmentStatusREADY},
+			}},
+			other(owner1): {AssignedShards: map[string]*types.ShardAssignment{
+				"1": {Status: types.AssignmentStatusREADY},
+			}},
+		},
+	}, nil).AnyTimes()
+
+	// No AssignShards expectation: the load balancing mode keeps the balanced assignment.
+	_, _, err := processor.rebalanceShardsImpl(context.Background(), metrics.NoopScope)
+	require.NoError(t, err)
+
+	// Flipping the fallback assigns the shards to their owners in the hash ring on the next cycle.
+	fallback := *mocks.sdConfig
+	fallback.StaticHashFallback = func(namespace string) bool { return true }
+	processor.SetConfig(&fallback)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Contains(t, request.NewState.ShardAssignments[owner0].AssignedShards, "0")
+			assert.Contains(t, request.NewState.ShardAssignments[owner1].AssignedShards, "1")
+			return nil
+		},
+	)
+	_, _, err = processor.rebalanceShardsImpl(context.Background(), metrics.NoopScope)
+	require.NoError(t, err)
+}
+
+func TestEmitZombieShards(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	logger, logs := testlogger.NewObserved(t)
+	processor.logger = logger
+
+	now := mocks.timeSource.Now()
+	namespaceState := &store.NamespaceState{
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"0": {LastUpdateTime: now.Add(-time.Hour)},
+			"1": {LastUpdateTime: now},
+		},
+	}
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	// Disabled by default.
+	processor.emitZombieShards(mocks.sdConfig, namespaceState, metricsScope)
+	assert.NotContains(t, testScope.Snapshot().Gauges(), "test.shard_distributor_shard_assign_zombie_shards+operation=ShardAssignLoop")
+
+	cfg := *mocks.sdConfig
+	cfg.ZombieShardAge = func(namespace string) time.Duration { return 10 * time.Minute }
+	processor.emitZombieShards(&cfg, namespaceState, metricsScope)
+	gauge, ok := testScope.Snapshot().Gauges()["test.shard_distributor_shard_assign_zombie_shards+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, float64(1), gauge.Value())
+	assert.Equal(t, 1, logs.FilterMessage("Assigned shards without a recent load report").Len())
+}
+
+func TestEmitOverBudgetShards(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	logger, logs := testlogger.NewObserved(t)
+	processor.logger = logger
+
+	namespaceState := &store.NamespaceState{
+		ShardStats: map[string]store.ShardStatistics{
+			"0": {SmoothedLoad: 50},
+			"1": {SmoothedLoad: 5},
+		},
+	}
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	// Disabled by default.
+	processor.emitOverBudgetShards(mocks.sdConfig, namespaceState, metricsScope)
+	assert.NotContains(t, testScope.Snapshot().Gauges(), "test.shard_distributor_shard_assign_over_budget_shards+operation=ShardAssignLoop")
+
+	cfg := *mocks.sdConfig
+	cfg.ShardLoadBudget = func(namespace string) float64 { return 10 }
+	processor.emitOverBudgetShards(&cfg, namespaceState, metricsScope)
+	gauge, ok := testScope.Snapshot().Gauges()["test.shard_distributor_shard_assign_over_budget_shards+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, float64(1), gauge.Value())
+	assert.Equal(t, 1, logs.FilterMessage("Shards over the shard load budget").Len())
+}
+
+func TestAllExecutorsDraining(t *testing.T) {
+	draining := store.HeartbeatState{Status: types.ExecutorStatusDRAINING}
+	active := store.HeartbeatState{Status: types.ExecutorStatusACTIVE}
+
+	assert.False(t, allExecutorsDraining(&store.NamespaceState{}, nil), "no executors")
+	assert.True(t, allExecutorsDraining(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": draining, "exec-2": active},
+	}, map[string]int64{"exec-2": 0}), "stale executors are ignored")
+	assert.False(t, allExecutorsDraining(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": draining, "exec-2": active},
+	}, nil))
+	assert.False(t, allExecutorsDraining(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": draining},
+	}, map[string]int64{"exec-1": 0}), "only stale executors")
+}
+
+func TestRebalanceShards_ReassignsShardsOfUnknownExecutors(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	logger, logs := testlogger.NewObserved(t)
+	processor.logger = logger
+
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}}},
+			// exec-phantom has no heartbeat, e.g. it was deleted while its assignments were kept.
+			"exec-phantom": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		},
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Len(t, request.NewState.ShardAssignments["exec-1"].AssignedShards, 2)
+			assert.NotContains(t, request.NewState.ShardAssignments, "exec-phantom")
+			return nil
+		},
+	)
+
+	err := processor.rebalanceShards(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, logs.FilterMessage("Reassigning shards assigned to unknown executors").Len())
+}
+
 func TestRebalanceShards_ExecutorRemoved(t *testing.T) {
 	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
 	defer mocks.ctrl.Finish()
@@ -221,6 +526,65 @@ func TestRebalanceShards_ExecutorStale(t *testing.T) {
 
 	err := processor.rebalanceShards(context.Background())
 	require.NoError(t, err)
+	assert.Equal(t, []events.ExecutorEvent{{Namespace: mocks.cfg.Name, ExecutorID: "exec-2", Time: now.UTC()}}, mocks.events.deregistered)
+	assert.Empty(t, mocks.events.registered)
+}
+
+func TestRebalanceShards_ZeroLoadShardsOfStaleExecutorAreReassigned(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeEphemeral)
+	defer mocks.ctrl.Finish()
+	// Heaviest-first donor selection prefers heavy shards, the zero load shards of the stale executor
+	// must be reassigned anyway.
+	mocks.sdConfig.EmptyExecutorDonorSelection = func(namespace string) string {
+		return config.DonorSelectionHeaviestFirst
+	}
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	heartbeats := map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, ReportedShards: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 50},
+		}},
+		"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now.Add(-2 * time.Second)},
+		"exec-3": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+	}
+	assignments := map[string]store.AssignedState{
+		"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}}, ModRevision: 1},
+		// The shards of the stale executor have no statistics, so their load is zero.
+		"exec-2": {AssignedShards: map[string]*types.ShardAssignment{
+			"shard-2": {Status: types.AssignmentStatusREADY},
+			"shard-3": {Status: types.AssignmentStatusREADY},
+		}, ModRevision: 1},
+	}
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors:        heartbeats,
+		ShardAssignments: assignments,
+		ShardStats: map[string]store.ShardStatistics{
+			"shard-1": {SmoothedLoad: 50, LastUpdateTime: now},
+		},
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(&store.ShardOwner{}, nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Equal(t, map[string]int64{"exec-2": 1}, request.ExecutorsToDelete)
+			assert.NotContains(t, request.NewState.ShardAssignments, "exec-2")
+
+			owners := make(map[string]string)
+			for executorID, assignedState := range request.NewState.ShardAssignments {
+				for shardID := range assignedState.AssignedShards {
+					owners[shardID] = executorID
+				}
+			}
+			assert.Len(t, owners, 3)
+			for _, shardID := range []string{"shard-2", "shard-3"} {
+				assert.Contains(t, []string{"exec-1", "exec-3"}, owners[shardID], "zero load shard %s must be reassigned", shardID)
+			}
+			return nil
+		},
+	)
+
+	require.NoError(t, processor.rebalanceShards(context.Background()))
 }
 
 func TestRebalanceShards_NoActiveExecutors(t *testing.T) {
@@ -238,6 +602,213 @@ func TestRebalanceShards_NoActiveExecutors(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRebalanceShards_NilState(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// A namespace where no executor heartbeated yet may have no state at all.
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(nil, nil)
+
+	err := processor.rebalanceShards(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRebalanceShards_NoExecutors(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	logger, logs := testlogger.NewObserved(t)
+	processor.logger = logger
+
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{}, nil)
+	// No AssignShards or DeleteExecutors expectation: an empty namespace must not be written.
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	unassignedReason, _, err := processor.rebalanceShardsImpl(context.Background(), metricsScope)
+	require.NoError(t, err)
+	assert.Equal(t, plan.UnassignedReasonNoExecutors, unassignedReason)
+
+	assert.Equal(t, 1, logs.FilterMessage("Namespace has no executors, shards remain unassigned").Len())
+	gauge, ok := testScope.Snapshot().Gauges()["test.shard_distributor_shard_assign_pending_shards+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, float64(mocks.cfg.ShardNum), gauge.Value())
+}
+
+func TestRebalanceShards_RecordsTrace(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.cfg.ShardNum = 3
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	// exec-2 owns no shards and shard "2" has no owner.
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {
+				AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}},
+				ModRevision:    7,
+			},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"0": {SmoothedLoad: 2, LastUpdateTime: now},
+		},
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).Return(nil)
+
+	_, _, err := processor.rebalanceShardsImpl(context.Background(), metrics.NoopScope)
+	require.NoError(t, err)
+
+	require.Len(t, mocks.traces.traces, 1)
+	trace := mocks.traces.traces[0]
+	assert.Equal(t, mocks.cfg.Name, trace.Namespace)
+	assert.Equal(t, int64(1), trace.Epoch)
+	assert.Equal(t, map[string]float64{"exec-1": 2}, trace.ExecutorLoads)
+	assert.Equal(t, map[string]time.Time{"0": now}, trace.ShardStatsUpdated)
+	assert.Equal(t, map[string]int64{"exec-1": 7}, trace.AssignmentRevisions)
+
+	require.Len(t, trace.Steps, 2)
+	assert.Equal(t, rebalancetrace.Step{
+		Phase:      rebalancetrace.PhaseFillEmptyExecutor,
+		ShardID:    "0",
+		From:       "exec-1",
+		Candidates: []string{"exec-2"},
+		To:         "exec-2",
+		Reason:     "executor owned no shards",
+	}, trace.Steps[0])
+
+	reassign := trace.Steps[1]
+	assert.Equal(t, rebalancetrace.PhaseReassign, reassign.Phase)
+	assert.Equal(t, "2", reassign.ShardID)
+	assert.Empty(t, reassign.From)
+	assert.ElementsMatch(t, []string{"exec-1", "exec-2"}, reassign.Candidates)
+	assert.Equal(t, reassign.Candidates[0], reassign.To)
+
+	assert.Contains(t, trace.Plan["exec-2"], "0")
+	assert.Contains(t, trace.Plan[reassign.To], "2")
+}
+
+// TestRebalanceShards_SameEpochReplaysRandomChoices verifies that the random choices of a pass only depend
+// on the namespace and the epoch of the pass, so a pass replays on another processor.
+func TestRebalanceShards_SameEpochReplaysRandomChoices(t *testing.T) {
+	runPass := func() rebalancetrace.Trace {
+		mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+		defer mocks.ctrl.Finish()
+		mocks.cfg.ShardNum = 8
+		processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+		now := mocks.timeSource.Now()
+		executors := make(map[string]store.HeartbeatState)
+		for i := 0; i < 8; i++ {
+			executors["exec-"+strconv.Itoa(i)] = store.HeartbeatState{Status: types.ExecutorStatusACTIVE, LastHeartbeat: now}
+		}
+		mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{Executors: executors}, nil)
+		mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, nil).AnyTimes()
+		mocks.election.EXPECT().Guard().Return(store.NopGuard())
+		mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).Return(nil)
+
+		_, _, err := processor.rebalanceShardsImpl(context.Background(), metrics.NoopScope)
+		require.NoError(t, err)
+		require.Len(t, mocks.traces.traces, 1)
+		return mocks.traces.traces[0]
+	}
+
+	first, replayed := runPass(), runPass()
+	assert.Equal(t, int64(1), first.Epoch)
+	assert.Equal(t, first.Epoch, replayed.Epoch)
+	assert.Equal(t, first.Plan, replayed.Plan)
+}
+
+func TestSetConfig_RebalanceUsesConsistentSnapshot(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		},
+	}, nil).AnyTimes()
+
+	// Every configuration records its name whenever the rebalance reads it.
+	var (
+		readsMu sync.Mutex
+		reads   []string
+	)
+	newNamedConfig := func(name string) *config.Config {
+		record := func() {
+			readsMu.Lock()
+			defer readsMu.Unlock()
+			reads = append(reads, name)
+		}
+		return &config.Config{
+			LoadBalancingMode: func(string) string {
+				record()
+				return config.LoadBalancingModeNAIVE
+			},
+			EmptyExecutorDonorSelection: func(string) string {
+				record()
+				return config.DonorSelectionHeaviestFirst
+			},
+			MigrationMode: func(string) string {
+				record()
+				return config.MigrationModeONBOARDED
+			},
+			LoadBalancingNaive: config.LoadBalancingNaiveConfig{
+				MaxDeviation: func(string) float64 { return 2.0 },
+			},
+		}
+	}
+	configs := []*config.Config{newNamedConfig("a"), newNamedConfig("b")}
+	processor.SetConfig(configs[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ctx.Err() == nil; i++ {
+			processor.SetConfig(configs[i%len(configs)])
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		readsMu.Lock()
+		reads = nil
+		readsMu.Unlock()
+
+		require.NoError(t, processor.rebalanceShards(context.Background()))
+
+		readsMu.Lock()
+		require.NotEmpty(t, reads)
+		for _, name := range reads {
+			require.Equal(t, reads[0], name, "rebalance %d read more than one configuration", i)
+		}
+		readsMu.Unlock()
+	}
+	cancel()
+	wg.Wait()
+
+	processor.SetConfig(configs[1])
+	assert.Same(t, configs[1], processor.Config())
+}
+
 func TestRebalanceShards_NoActiveExecutors_WithStaleExecutors(t *testing.T) {
 	t.Run("one stale executor", func(t *testing.T) {
 		mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
@@ -447,23 +1018,74 @@ func TestRunLoop_ContextCancellation(t *testing.T) {
 	processor.wg.Wait()
 }
 
-func TestRebalanceShards_WithUnassignedShardsButMigrationModeNotOnboarded(t *testing.T) {
-	migrationConfig := configtest.NewTestMigrationConfig(t, configtest.ConfigEntry{
-		Key:   dynamicproperties.ShardDistributorMigrationMode,
-		Value: config.MigrationModeLOCALPASSTHROUGH})
-	mocks := setupProcessorTestWithMigrationConfig(t, config.NamespaceTypeFixed, migrationConfig)
+func TestRunLoop_RecordsUnassignedReason(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
 	defer mocks.ctrl.Finish()
 	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	ctx, cancel := context.WithCancel(context.Background())
 
-	now := mocks.timeSource.Now()
-	heartbeats := map[string]store.HeartbeatState{
-		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+	var passes atomic.Int32
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).DoAndReturn(
+		func(context.Context, string) (*store.NamespaceState, error) {
+			passes.Add(1)
+			return &store.NamespaceState{}, nil
+		},
+	).AnyTimes()
+	updates := make(chan int64)
+	mocks.store.EXPECT().SubscribeToExecutorStatusChanges(gomock.Any(), mocks.cfg.Name).Return(updates, nil)
+
+	processor.leaders.add(mocks.cfg.Name, mocks.election)
+	reason, ok := mocks.factory.UnassignedReason(mocks.cfg.Name, "0")
+	require.True(t, ok)
+	assert.Equal(t, plan.UnassignedReasonPending, reason, "the first pass is still to come")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		processor.runRebalancingLoop(ctx)
+	}()
+	assertReason := func(expected plan.UnassignedReason) {
+		assert.Eventually(t, func() bool {
+			reason, _ := mocks.factory.UnassignedReason(mocks.cfg.Name, "0")
+			return reason == expected
+		}, time.Second, time.Millisecond)
+	}
+
+	// The first triggered pass runs right away and starts the rebalance cooldown.
+	updates <- 1
+	require.Eventually(t, func() bool { return passes.Load() == 2 }, time.Second, time.Millisecond)
+	assertReason(plan.UnassignedReasonNoExecutors)
+
+	// The next one waits out the cooldown: the triggering loop's ticker and the cooldown are blocked on the clock.
+	updates <- 2
+	mocks.timeSource.BlockUntil(2)
+	assertReason(plan.UnassignedReasonInCooldown)
+
+	mocks.timeSource.Advance(_defaultCooldown)
+	assertReason(plan.UnassignedReasonNoExecutors)
+
+	cancel()
+	<-done
+}
+
+func TestRebalanceShards_WithUnassignedShardsButMigrationModeNotOnboarded(t *testing.T) {
+	migrationConfig := configtest.NewTestMigrationConfig(t, configtest.ConfigEntry{
+		Key:   dynamicproperties.ShardDistributorMigrationMode,
+		Value: config.MigrationModeLOCALPASSTHROUGH})
+	mocks := setupProcessorTestWithMigrationConfig(t, config.NamespaceTypeFixed, migrationConfig)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	previouslyAssignedAt := now.Add(-time.Hour).UTC()
+	heartbeats := map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
 	}
 	// Note: shard "1" is missing from assignments
 	assignments := map[string]store.AssignedState{
 		"exec-1": {
 			AssignedShards: map[string]*types.ShardAssignment{
-				"0": {Status: types.AssignmentStatusREADY},
+				"0": {Status: types.AssignmentStatusREADY, AssignedAt: previouslyAssignedAt},
 			},
 		},
 	}
@@ -478,12 +1100,112 @@ func TestRebalanceShards_WithUnassignedShardsButMigrationModeNotOnboarded(t *tes
 	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
 			assert.Len(t, request.NewState.ShardAssignments["exec-1"].AssignedShards, 2, "Both shards should now be assigned to exec-1")
+			assert.Equal(t, previouslyAssignedAt, request.NewState.ShardAssignments["exec-1"].AssignedShards["0"].AssignedAt, "Existing assignment should keep its timestamp")
+			assert.Equal(t, now.UTC(), request.NewState.ShardAssignments["exec-1"].AssignedShards["1"].AssignedAt, "New assignment should be stamped with the current time")
 			return nil
 		},
 	).Times(0)
 
-	err := processor.rebalanceShards(context.Background())
+	unassignedReason, _, err := processor.rebalanceShardsImpl(context.Background(), metrics.NoopScope)
 	require.NoError(t, err)
+	assert.Equal(t, plan.UnassignedReasonShadowMode, unassignedReason)
+}
+
+func TestGetNewAssignmentsState_StampsShardLease(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.ShardLeaseDuration = func(namespace string) time.Duration { return time.Minute }
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).Times(2)
+
+	newState := processor.getNewAssignmentsState(processor.Config(), &store.NamespaceState{}, map[string][]string{"exec-1": {"0", "1"}})
+
+	expectedLease := mocks.timeSource.Now().UTC().Add(time.Minute)
+	for _, shardID := range []string{"0", "1"} {
+		assert.Equal(t, expectedLease, newState["exec-1"].AssignedShards[shardID].LeaseExpiresAt)
+	}
+}
+
+func TestGetNewAssignmentsState_DoesNotAliasInputs(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).AnyTimes()
+
+	assignedAt := mocks.timeSource.Now().Add(-time.Hour)
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{
+				"0": {Status: types.AssignmentStatusREADY, AssignedAt: assignedAt},
+			}},
+		},
+	}
+	// Spare capacity would let an append on an aliased slice write into the input's backing array.
+	exec1Shards := make([]string, 0, 8)
+	exec1Shards = append(exec1Shards, "0")
+	currentAssignments := map[string][]string{"exec-1": exec1Shards, "exec-2": {"1"}}
+
+	_, reassignAssignments := processor.findShardsToReassign([]string{"exec-1", "exec-2"}, namespaceState, namespaceState.ShardAssignments, nil, nil)
+	reassignAssignments["exec-1"] = append(reassignAssignments["exec-1"], "mutated")
+	reassignAssignments["exec-2"] = append(reassignAssignments["exec-2"], "mutated")
+	assert.Len(t, namespaceState.ShardAssignments["exec-1"].AssignedShards, 1)
+
+	newState := processor.getNewAssignmentsState(processor.Config(), namespaceState, currentAssignments)
+	require.Contains(t, newState, "exec-1")
+	newState["exec-1"].AssignedShards["0"].Status = types.AssignmentStatusINVALID
+	newState["exec-1"].AssignedShards["2"] = &types.ShardAssignment{}
+	delete(newState["exec-2"].AssignedShards, "1")
+	newState["exec-2"].ShardHandoverStats["1"] = store.ShardHandoverStats{}
+
+	assert.Equal(t, map[string][]string{"exec-1": {"0"}, "exec-2": {"1"}}, currentAssignments)
+	assert.Equal(t, []string{"0", ""}, exec1Shards[:2], "nothing was written past the input slice")
+	assert.Equal(t, map[string]*types.ShardAssignment{
+		"0": {Status: types.AssignmentStatusREADY, AssignedAt: assignedAt},
+	}, namespaceState.ShardAssignments["exec-1"].AssignedShards)
+	assert.NotContains(t, namespaceState.ShardAssignments, "exec-2")
+}
+
+func TestFindShardsToReassign_WarmStartsFromPreviousAssignments(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE},
+		},
+	}
+	activeExecutors := []string{"exec-1", "exec-2"}
+
+	t.Run("nothing unassigned keeps the previous assignment", func(t *testing.T) {
+		previous := map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		}
+
+		shardsToReassign, currentAssignments := processor.findShardsToReassign(activeExecutors, namespaceState, previous, nil, nil)
+		changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, shardsToReassign, activeExecutors, currentAssignments, nil)
+
+		assert.Empty(t, shardsToReassign)
+		assert.False(t, changed)
+		assert.Equal(t, map[string][]string{"exec-1": {"0"}, "exec-2": {"1"}}, currentAssignments)
+	})
+
+	t.Run("only the unassigned shard is placed", func(t *testing.T) {
+		previous := map[string]store.AssignedState{
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		}
+
+		shardsToReassign, currentAssignments := processor.findShardsToReassign(activeExecutors, namespaceState, previous, nil, nil)
+
+		assert.Equal(t, []string{"0"}, shardsToReassign)
+		assert.Equal(t, map[string][]string{"exec-1": {}, "exec-2": {"1"}}, currentAssignments)
+	})
 }
 
 func TestRebalanceShards_ShadowModeWithStaleExecutors(t *testing.T) {
@@ -565,6 +1287,7 @@ func TestRebalanceShards_ShadowModeWithStaleExecutors(t *testing.T) {
 
 		err := processor.rebalanceShards(context.Background())
 		require.NoError(t, err)
+		assert.Empty(t, mocks.events.deregistered, "executors that failed to be deleted are not deregistered")
 	})
 
 	t.Run("no stale executors - delete not called in shadow mode", func(t *testing.T) {
@@ -632,6 +1355,7 @@ func TestRebalanceShards_WithUnassignedShards(t *testing.T) {
 	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
 
 	now := mocks.timeSource.Now()
+	previouslyAssignedAt := now.Add(-time.Hour).UTC()
 	heartbeats := map[string]store.HeartbeatState{
 		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
 	}
@@ -639,7 +1363,7 @@ func TestRebalanceShards_WithUnassignedShards(t *testing.T) {
 	assignments := map[string]store.AssignedState{
 		"exec-1": {
 			AssignedShards: map[string]*types.ShardAssignment{
-				"0": {Status: types.AssignmentStatusREADY},
+				"0": {Status: types.AssignmentStatusREADY, AssignedAt: previouslyAssignedAt},
 			},
 		},
 	}
@@ -653,6 +1377,46 @@ func TestRebalanceShards_WithUnassignedShards(t *testing.T) {
 	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
 			assert.Len(t, request.NewState.ShardAssignments["exec-1"].AssignedShards, 2, "Both shards should now be assigned to exec-1")
+			assert.Equal(t, previouslyAssignedAt, request.NewState.ShardAssignments["exec-1"].AssignedShards["0"].AssignedAt, "Existing assignment should keep its timestamp")
+			assert.Equal(t, now.UTC(), request.NewState.ShardAssignments["exec-1"].AssignedShards["1"].AssignedAt, "New assignment should be stamped with the current time")
+			return nil
+		},
+	)
+
+	err := processor.rebalanceShards(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRebalanceShards_HandedOffShardIsNotDeleted(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeEphemeral)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	// exec-1 finished handing shard-1 off to exec-2, and released shard-2.
+	heartbeats := map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, ReportedShards: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusDONE},
+			"shard-2": {Status: types.ShardStatusDONE},
+		}},
+		"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, ReportedShards: map[string]*types.ShardStatusReport{
+			"shard-1": {Status: types.ShardStatusREADY},
+		}},
+	}
+	assignments := map[string]store.AssignedState{
+		"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"shard-2": {Status: types.AssignmentStatusREADY}}},
+		"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}}},
+	}
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors:        heartbeats,
+		ShardAssignments: assignments,
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(&store.ShardOwner{}, nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Empty(t, request.NewState.ShardAssignments["exec-1"].AssignedShards)
+			assert.Equal(t, []string{"shard-1"}, slices.Collect(maps.Keys(request.NewState.ShardAssignments["exec-2"].AssignedShards)))
 			return nil
 		},
 	)
@@ -777,6 +1541,104 @@ func TestRebalanceShards_AppliesGreedyLoadBalancingPlan(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRebalanceShards_AppliesLoadBalancingMovesConcurrently(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeEphemeral)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.LoadBalancingMode = func(string) string { return config.LoadBalancingModeCONSISTENTHASH }
+	mocks.sdConfig.RebalanceApplyWorkers = func(string) int { return 2 }
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// Every shard starts on the executor after its owner in the hash ring, so each one is moved.
+	executorIDs := []string{"exec-1", "exec-2", "exec-3", "exec-4", "exec-5", "exec-6"}
+	ring := consistenthash.NewRing(executorIDs)
+	ringOwner := func(shardID string) string {
+		owner, ok := ring.Owner(shardID)
+		require.True(t, ok)
+		return owner
+	}
+	now := mocks.timeSource.Now()
+	heartbeats := make(map[string]store.HeartbeatState)
+	assignments := make(map[string]store.AssignedState)
+	for _, executorID := range executorIDs {
+		heartbeats[executorID] = store.HeartbeatState{Status: types.ExecutorStatusACTIVE, LastHeartbeat: now}
+		assignments[executorID] = store.AssignedState{AssignedShards: make(map[string]*types.ShardAssignment)}
+	}
+	owners := make(map[string]string)
+	for i := range 24 {
+		shardID := "shard-" + strconv.Itoa(i)
+		executorID := executorIDs[(slices.Index(executorIDs, ringOwner(shardID))+1)%len(executorIDs)]
+		assignments[executorID].AssignedShards[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+		owners[shardID] = executorID
+	}
+	for _, executorID := range executorIDs {
+		require.NotEmpty(t, assignments[executorID].AssignedShards, "no executor may be empty, so only load balancing moves are planned")
+	}
+	const failingShard = "shard-0"
+	failingShardOwner := ringOwner(failingShard)
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+		writes   int
+	)
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors:        heartbeats,
+		ShardAssignments: assignments,
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, shardID string) (*store.ShardOwner, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return &store.ShardOwner{ExecutorID: owners[shardID]}, nil
+		}).AnyTimes()
+	mocks.store.EXPECT().GetHeartbeat(gomock.Any(), mocks.cfg.Name, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, executorID string) (*store.HeartbeatState, *store.AssignedState, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			heartbeat, assigned := heartbeats[executorID], assignments[executorID]
+			return &heartbeat, &assigned, nil
+		}).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			mu.Lock()
+			writes++
+			inFlight++
+			maxSeen = max(maxSeen, inFlight)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			inFlight--
+			assert.Len(t, request.NewState.ShardAssignments, 2, "each write holds the two executors of one move")
+			if _, ok := request.NewState.ShardAssignments[failingShardOwner].AssignedShards[failingShard]; ok {
+				return errors.New("version conflict")
+			}
+			for executorID, assigned := range request.NewState.ShardAssignments {
+				assignments[executorID] = assigned
+				for shardID := range assigned.AssignedShards {
+					owners[shardID] = executorID
+				}
+			}
+			return nil
+		}).AnyTimes()
+
+	err := processor.rebalanceShards(context.Background())
+	require.ErrorContains(t, err, "1 of 24 moves not applied")
+	assert.Equal(t, 24, writes)
+	assert.LessOrEqual(t, maxSeen, 2)
+	for shardID, executorID := range owners {
+		if shardID == failingShard {
+			assert.NotEqual(t, failingShardOwner, executorID, "the failed move is left for the next cycle")
+			continue
+		}
+		assert.Equal(t, ringOwner(shardID), executorID)
+	}
+}
+
 func TestGetShards_Utility(t *testing.T) {
 	t.Run("Fixed type", func(t *testing.T) {
 		cfg := config.Namespace{Type: config.NamespaceTypeFixed, ShardNum: 5}
@@ -911,7 +1773,7 @@ func TestAssignShardsToEmptyExecutors(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			actualDistributionChanged := assignShardsToEmptyExecutors(c.inputAssignments)
+			actualDistributionChanged := assignShardsToEmptyExecutors(c.inputAssignments, nil, config.DonorSelectionHeaviestFirst, nil, nil, nil, nil, 0, 0)
 
 			assert.Equal(t, c.expectedAssignments, c.inputAssignments)
 			assert.Equal(t, c.expectedDistributonChanged, actualDistributionChanged)
@@ -919,6 +1781,153 @@ func TestAssignShardsToEmptyExecutors(t *testing.T) {
 	}
 }
 
+func TestAssignShardsToEmptyExecutors_DonorSelection(t *testing.T) {
+	shardLoads := map[string]float64{
+		"big":     10,
+		"small-1": 1,
+		"small-2": 1,
+		"other-1": 1,
+		"other-2": 2,
+		"other-3": 3,
+	}
+
+	cases := []struct {
+		name                string
+		donorSelection      string
+		pinnedShards        map[string]string
+		expectedAssignments map[string][]string
+	}{
+		{
+			name:           "heaviest first takes the big shard",
+			donorSelection: config.DonorSelectionHeaviestFirst,
+			expectedAssignments: map[string][]string{
+				"exec-1": {"small-1", "small-2"},
+				"exec-2": {"other-1", "other-2"},
+				"exec-3": {"big", "other-3"},
+			},
+		},
+		{
+			name:           "lightest first fills the empty executor with multiple small shards",
+			donorSelection: config.DonorSelectionLightestFirst,
+			expectedAssignments: map[string][]string{
+				"exec-1": {"big", "small-2"},
+				"exec-2": {"other-2", "other-3"},
+				"exec-3": {"small-1", "other-1"},
+			},
+		},
+		{
+			name:           "closest to target takes the shard closest to the remaining load",
+			donorSelection: config.DonorSelectionClosestToTarget,
+			// The target load is 18/3 = 6: "big" is 4 away, "small-1" is 5 away.
+			// After taking "big" the remaining load is -4, so "other-1" is closest.
+			expectedAssignments: map[string][]string{
+				"exec-1": {"small-1", "small-2"},
+				"exec-2": {"other-2", "other-3"},
+				"exec-3": {"big", "other-1"},
+			},
+		},
+		{
+			name:           "pinned shards are never taken",
+			donorSelection: config.DonorSelectionHeaviestFirst,
+			pinnedShards:   map[string]string{"big": "", "other-3": "exec-2"},
+			expectedAssignments: map[string][]string{
+				"exec-1": {"big", "small-2"},
+				"exec-2": {"other-1", "other-3"},
+				"exec-3": {"small-1", "other-2"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assignments := map[string][]string{
+				"exec-1": {"big", "small-1", "small-2"},
+				"exec-2": {"other-1", "other-2", "other-3"},
+				"exec-3": {},
+			}
+
+			changed := assignShardsToEmptyExecutors(assignments, shardLoads, c.donorSelection, c.pinnedShards, nil, nil, nil, 0, 0)
+
+			assert.True(t, changed)
+			assert.Equal(t, c.expectedAssignments, assignments)
+		})
+	}
+}
+
+func TestAssignShardsToEmptyExecutors_RampCap(t *testing.T) {
+	assignments := map[string][]string{
+		"exec-1": {"0", "1", "2", "3", "4", "5"},
+		"exec-2": {"6", "7", "8", "9", "10", "11"},
+		"exec-3": {},
+	}
+
+	// Without the cap the new executor would receive 4 shards in one cycle.
+	changed := assignShardsToEmptyExecutors(assignments, nil, config.DonorSelectionHeaviestFirst, nil, nil, nil, nil, 2, 0)
+
+	assert.True(t, changed)
+	assert.Len(t, assignments["exec-3"], 2)
+	assert.Len(t, append(assignments["exec-1"], assignments["exec-2"]...), 10)
+}
+
+func TestAssignShardsToEmptyExecutors_MaxMoves(t *testing.T) {
+	assignments := map[string][]string{
+		"exec-1": {"0", "1", "2", "3", "4", "5"},
+		"exec-2": {"6", "7", "8", "9", "10", "11"},
+		"exec-3": {},
+		"exec-4": {},
+	}
+
+	// Without the cap each new executor would receive 3 shards in one cycle.
+	changed := assignShardsToEmptyExecutors(assignments, nil, config.DonorSelectionHeaviestFirst, nil, nil, nil, nil, 0, 3)
+
+	assert.True(t, changed)
+	assert.Len(t, append(assignments["exec-3"], assignments["exec-4"]...), 3)
+	assert.Len(t, append(assignments["exec-1"], assignments["exec-2"]...), 9)
+}
+
+func TestAssignShardsToEmptyExecutors_SkipsStandbys(t *testing.T) {
+	assignments := map[string][]string{
+		"exec-1":  {"0", "1", "2", "3"},
+		"exec-2":  {},
+		"standby": {},
+	}
+
+	changed := assignShardsToEmptyExecutors(assignments, nil, config.DonorSelectionHeaviestFirst, nil, nil, nil, map[string]struct{}{"standby": {}}, 0, 0)
+
+	assert.True(t, changed)
+	assert.Len(t, assignments["exec-2"], 2)
+	assert.Empty(t, assignments["standby"])
+}
+
+func TestAssignShardsToEmptyExecutors_HonorsLabelSelectors(t *testing.T) {
+	assignments := map[string][]string{
+		"exec-1": {"gpu-1", "gpu-2", "0", "1"},
+		"exec-2": {},
+	}
+	selectors := map[string]map[string]string{
+		"gpu-1": {"gpu": "true"},
+		"gpu-2": {"gpu": "true"},
+	}
+
+	changed := assignShardsToEmptyExecutors(assignments, nil, config.DonorSelectionHeaviestFirst, nil, selectors, nil, nil, 0, 0)
+	assert.True(t, changed)
+	assert.Equal(t, map[string][]string{
+		"exec-1": {"gpu-1", "gpu-2"},
+		"exec-2": {"0", "1"},
+	}, assignments)
+
+	// An empty executor matching the selectors can take the shards.
+	assignments = map[string][]string{
+		"exec-1": {"gpu-1", "gpu-2", "0", "1"},
+		"exec-2": {},
+	}
+	labels := map[string]map[string]string{"exec-2": {"gpu": "true"}}
+
+	changed = assignShardsToEmptyExecutors(assignments, nil, config.DonorSelectionHeaviestFirst, nil, selectors, labels, nil, 0, 0)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"gpu-1", "gpu-2"}, assignments["exec-2"])
+}
+
 func TestApplyMoves(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -1337,6 +2346,75 @@ func TestEmitOldestExecutorHeartbeatLag(t *testing.T) {
 	}
 }
 
+func TestComputeRebalanceDelay(t *testing.T) {
+	const period = time.Second
+
+	tests := []struct {
+		name              string
+		interval          time.Duration
+		jitterCoefficient float64
+		minDelay          time.Duration
+		maxDelay          time.Duration
+	}{
+		{
+			name:     "No interval falls back to the period",
+			minDelay: period,
+			maxDelay: period,
+		},
+		{
+			name:     "Interval without jitter is exact",
+			interval: 10 * time.Second,
+			minDelay: 10 * time.Second,
+			maxDelay: 10 * time.Second,
+		},
+		{
+			name:              "Jitter stays within bounds",
+			interval:          10 * time.Second,
+			jitterCoefficient: 0.2,
+			minDelay:          8 * time.Second,
+			maxDelay:          12 * time.Second,
+		},
+		{
+			name:              "Jitter applies to the fallback period",
+			jitterCoefficient: 0.5,
+			minDelay:          period / 2,
+			maxDelay:          period * 3 / 2,
+		},
+		{
+			name:              "Jitter coefficient above one is clamped",
+			interval:          10 * time.Second,
+			jitterCoefficient: 5,
+			minDelay:          0,
+			maxDelay:          20 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				delay := computeRebalanceDelay(period, tt.interval, tt.jitterCoefficient)
+				require.GreaterOrEqual(t, delay, tt.minDelay)
+				require.LessOrEqual(t, delay, tt.maxDelay)
+			}
+		})
+	}
+}
+
+func TestNextRebalanceDelay_ConfigChangeAppliesToNextCycle(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	interval := 10 * time.Second
+	processor.SetConfig(&config.Config{
+		RebalanceInterval: func(namespace string) time.Duration { return interval },
+	})
+	assert.Equal(t, 10*time.Second, processor.nextRebalanceDelay())
+
+	interval = 20 * time.Second
+	assert.Equal(t, 20*time.Second, processor.nextRebalanceDelay())
+}
+
 func TestRunRebalanceTriggeringLoop(t *testing.T) {
 	t.Run("no events from subscribe, trigger from ticker", func(t *testing.T) {
 		mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
@@ -1368,6 +2446,40 @@ func TestRunRebalanceTriggeringLoop(t *testing.T) {
 		cancel()
 	})
 
+	t.Run("configured rebalance interval replaces the period", func(t *testing.T) {
+		mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+		defer mocks.ctrl.Finish()
+		processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+		interval := 3 * processor.cfg.Period
+		processor.SetConfig(&config.Config{
+			RebalanceInterval: func(namespace string) time.Duration { return interval },
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		updateChan := make(chan int64)
+		triggerChan := make(chan string, 1)
+
+		go processor.rebalanceTriggeringLoop(ctx, updateChan, triggerChan)
+		mocks.timeSource.BlockUntil(1)
+
+		mocks.timeSource.Advance(processor.cfg.Period)
+		select {
+		case reason := <-triggerChan:
+			t.Fatalf("unexpected trigger before the configured interval: %s", reason)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		mocks.timeSource.Advance(interval - processor.cfg.Period)
+		select {
+		case reason := <-triggerChan:
+			assert.Equal(t, "Periodic reconciliation triggered", reason)
+		case <-time.After(time.Second):
+			t.Fatal("expected trigger after the configured interval, but timed out")
+		}
+	})
+
 	t.Run("events from subscribe before period, trigger from state change", func(t *testing.T) {
 		mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
 		defer mocks.ctrl.Finish()
@@ -1481,3 +2593,610 @@ func TestRunRebalanceTriggeringLoop(t *testing.T) {
 		}
 	})
 }
+
+func TestConsolidate(t *testing.T) {
+	currentAssignments := map[string][]string{
+		"exec-1": {"a", "b"},
+		"exec-2": {"c"},
+		"exec-3": {"d"},
+		"exec-4": {"e"},
+	}
+	shardLoads := map[string]float64{"a": 3, "b": 2, "c": 4, "d": 1, "e": 1}
+
+	cases := []struct {
+		name               string
+		executorCapacity   float64
+		threshold          float64
+		minActiveExecutors int
+		maxMoves           int
+		recentlyMoved      []string
+		pinnedShards       map[string]interface{}
+		expectedPlan       consolidationPlan
+	}{
+		{
+			name:             "total load below threshold packs shards onto fewer executors",
+			executorCapacity: 10,
+			threshold:        0.5,
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1", "exec-2"},
+				Moves: []plan.Move{
+					{ShardID: "d", From: "exec-3", To: "exec-2"},
+					{ShardID: "e", From: "exec-4", To: "exec-1"},
+				},
+				Emptying:  []string{"exec-3", "exec-4"},
+				ScaleDown: []string{"exec-3", "exec-4"},
+			},
+		},
+		{
+			name:             "total load above threshold is a no-op",
+			executorCapacity: 10,
+			threshold:        0.2,
+			expectedPlan:     consolidationPlan{},
+		},
+		{
+			name:             "unknown capacity disables consolidation",
+			executorCapacity: 0,
+			threshold:        0.5,
+			expectedPlan:     consolidationPlan{},
+		},
+		{
+			name:             "executors owning shards in cooldown are kept",
+			executorCapacity: 10,
+			threshold:        0.5,
+			recentlyMoved:    []string{"e"},
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1", "exec-4"},
+				Moves: []plan.Move{
+					{ShardID: "c", From: "exec-2", To: "exec-4"},
+					{ShardID: "d", From: "exec-3", To: "exec-1"},
+				},
+				Emptying:  []string{"exec-2", "exec-3"},
+				ScaleDown: []string{"exec-2", "exec-3"},
+			},
+		},
+		{
+			name:             "executors owning pinned shards are kept",
+			executorCapacity: 10,
+			threshold:        0.5,
+			pinnedShards:     map[string]interface{}{"e": ""},
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1", "exec-4"},
+				Moves: []plan.Move{
+					{ShardID: "c", From: "exec-2", To: "exec-4"},
+					{ShardID: "d", From: "exec-3", To: "exec-1"},
+				},
+				Emptying:  []string{"exec-2", "exec-3"},
+				ScaleDown: []string{"exec-2", "exec-3"},
+			},
+		},
+		{
+			name:             "very low load packs shards onto a single executor",
+			executorCapacity: 100,
+			threshold:        0.5,
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1"},
+				Moves: []plan.Move{
+					{ShardID: "c", From: "exec-2", To: "exec-1"},
+					{ShardID: "d", From: "exec-3", To: "exec-1"},
+					{ShardID: "e", From: "exec-4", To: "exec-1"},
+				},
+				Emptying:  []string{"exec-2", "exec-3", "exec-4"},
+				ScaleDown: []string{"exec-2", "exec-3", "exec-4"},
+			},
+		},
+		{
+			name:             "move cap leaves executors with shards left out of scale-down",
+			executorCapacity: 100,
+			threshold:        0.5,
+			maxMoves:         2,
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1"},
+				Moves: []plan.Move{
+					{ShardID: "c", From: "exec-2", To: "exec-1"},
+					{ShardID: "d", From: "exec-3", To: "exec-1"},
+				},
+				Emptying:  []string{"exec-2", "exec-3", "exec-4"},
+				ScaleDown: []string{"exec-2", "exec-3"},
+			},
+		},
+		{
+			name:               "consolidation stops at the minimum number of active executors",
+			executorCapacity:   100,
+			threshold:          0.5,
+			minActiveExecutors: 3,
+			expectedPlan: consolidationPlan{
+				TargetExecutors: []string{"exec-1", "exec-2", "exec-3"},
+				Moves: []plan.Move{
+					{ShardID: "e", From: "exec-4", To: "exec-3"},
+				},
+				Emptying:  []string{"exec-4"},
+				ScaleDown: []string{"exec-4"},
+			},
+		},
+		{
+			name:               "minimum covering all executors is a no-op",
+			executorCapacity:   100,
+			threshold:          0.5,
+			minActiveExecutors: 4,
+			expectedPlan:       consolidationPlan{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+			defer mocks.ctrl.Finish()
+			mocks.sdConfig.ExecutorLoadCapacity = func(string) float64 { return tc.executorCapacity }
+			mocks.sdConfig.ConsolidationLoadThreshold = func(string) float64 { return tc.threshold }
+			mocks.sdConfig.MinActiveExecutors = func(string) int { return tc.minActiveExecutors }
+			mocks.sdConfig.MaxMovesPerCycle = func(string) int { return tc.maxMoves }
+			mocks.sdConfig.LoadBalancingGreedy.PerShardCooldown = func(string) time.Duration { return 5 * time.Minute }
+			mocks.sdConfig.PinnedShards = func(string) map[string]interface{} { return tc.pinnedShards }
+			processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+			namespaceState := &store.NamespaceState{ShardStats: make(map[string]store.ShardStatistics)}
+			for shardID, load := range shardLoads {
+				namespaceState.ShardStats[shardID] = store.ShardStatistics{SmoothedLoad: load}
+			}
+			for _, shardID := range tc.recentlyMoved {
+				stats := namespaceState.ShardStats[shardID]
+				stats.LastMoveTime = mocks.timeSource.Now().Add(-time.Minute)
+				namespaceState.ShardStats[shardID] = stats
+			}
+
+			consolidation := processor.consolidate(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, currentAssignments)
+			assert.Equal(t, tc.expectedPlan, consolidation)
+		})
+	}
+}
+
+func TestRebalanceShards_Consolidates(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.ExecutorLoadCapacity = func(string) float64 { return 10 }
+	mocks.sdConfig.ConsolidationLoadThreshold = func(string) float64 { return 0.5 }
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	now := mocks.timeSource.Now()
+	heartbeats := map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+	}
+	shardStats := map[string]store.ShardStatistics{
+		"0": {SmoothedLoad: 2},
+		"1": {SmoothedLoad: 1},
+	}
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: heartbeats,
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {Status: types.AssignmentStatusREADY}}},
+		},
+		ShardStats: shardStats,
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(nil, store.ErrShardNotFound).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Len(t, request.NewState.ShardAssignments["exec-1"].AssignedShards, 2)
+			assert.Empty(t, request.NewState.ShardAssignments["exec-2"].AssignedShards)
+			return nil
+		},
+	)
+	require.NoError(t, processor.rebalanceShards(context.Background()))
+
+	// While the load stays low, neither the empty executor fill nor the load balancer undo the consolidation.
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: heartbeats,
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {Status: types.AssignmentStatusREADY}, "1": {Status: types.AssignmentStatusREADY}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{}},
+		},
+		ShardStats: shardStats,
+	}, nil)
+	require.NoError(t, processor.rebalanceShards(context.Background()))
+}
+
+// forEachRoundRobinStart runs fn once for every executor the round robin of updateAssignments can start at.
+// The rng of the processor is seeded so its first pick is that executor.
+func forEachRoundRobinStart(processor *namespaceProcessor, executors int, fn func()) {
+	for start := range executors {
+		for epoch := int64(0); ; epoch++ {
+			if loadbalancer.NewRand(processor.namespaceCfg.Name, epoch).Intn(executors) == start {
+				processor.rng = loadbalancer.NewRand(processor.namespaceCfg.Name, epoch)
+				break
+			}
+		}
+		fn()
+	}
+}
+
+func TestUpdateAssignments_SpreadsShardGroupsAcrossZones(t *testing.T) {
+	executorInZone := func(zone string) store.HeartbeatState {
+		return store.HeartbeatState{Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataZoneKey: zone}}
+	}
+
+	cases := []struct {
+		name          string
+		executors     map[string]store.HeartbeatState
+		expectedZones map[string]int
+	}{
+		{
+			name: "group is split across two zones",
+			executors: map[string]store.HeartbeatState{
+				"exec-a1": executorInZone("zone-a"),
+				"exec-a2": executorInZone("zone-a"),
+				"exec-b1": executorInZone("zone-b"),
+			},
+			expectedZones: map[string]int{"zone-a": 1, "zone-b": 1},
+		},
+		{
+			name: "shards are still assigned when every zone is full",
+			executors: map[string]store.HeartbeatState{
+				"exec-a1": executorInZone("zone-a"),
+				"exec-a2": executorInZone("zone-a"),
+			},
+			expectedZones: map[string]int{"zone-a": 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+			defer mocks.ctrl.Finish()
+			mocks.sdConfig.ShardGroups = func(string) map[string]interface{} {
+				return map[string]interface{}{"0": "group", "1": "group"}
+			}
+			mocks.sdConfig.MaxGroupShardsPerZone = func(string) int { return 1 }
+			processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+			namespaceState := &store.NamespaceState{Executors: tc.executors}
+			activeExecutors := plan.SortedExecutorIDs(tc.executors)
+
+			forEachRoundRobinStart(processor, len(activeExecutors), func() {
+				currentAssignments := make(map[string][]string)
+				changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"0", "1"}, activeExecutors, currentAssignments, nil)
+				require.True(t, changed)
+
+				zones := make(map[string]int)
+				for executorID, shards := range currentAssignments {
+					zones[tc.executors[executorID].Zone()] += len(shards)
+				}
+				assert.Equal(t, tc.expectedZones, zones)
+			})
+		})
+	}
+}
+
+func TestUpdateAssignments_PlacesPinnedShardsOnRequiredExecutor(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.PinnedShards = func(string) map[string]interface{} {
+		// Shard "1" requires an executor that is not active, so it is placed like any other shard.
+		return map[string]interface{}{"0": "exec-2", "1": "exec-gone"}
+	}
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE},
+		"exec-2": {Status: types.ExecutorStatusACTIVE},
+		"exec-3": {Status: types.ExecutorStatusACTIVE},
+	}}
+	activeExecutors := []string{"exec-1", "exec-2", "exec-3"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := make(map[string][]string)
+		changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"0", "1"}, activeExecutors, currentAssignments, nil)
+		require.True(t, changed)
+
+		assert.Contains(t, currentAssignments["exec-2"], "0")
+		placed := 0
+		for _, shards := range currentAssignments {
+			placed += len(shards)
+		}
+		assert.Equal(t, 2, placed)
+	})
+}
+
+func TestUpdateAssignments_HonorsLabelSelectors(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.ShardLabelSelectors = func(string) map[string]interface{} {
+		// No executor matches the selector of shard "1", so it is left unassigned.
+		return map[string]interface{}{"0": "gpu=true", "1": "tpu=true"}
+	}
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE},
+		"exec-2": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"}},
+		"exec-3": {Status: types.ExecutorStatusACTIVE},
+	}}
+	activeExecutors := []string{"exec-1", "exec-2", "exec-3"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := make(map[string][]string)
+		changed, unplaced := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"0", "1"}, activeExecutors, currentAssignments, nil)
+		require.True(t, changed)
+
+		assert.Equal(t, map[string][]string{"exec-2": {"0"}}, currentAssignments)
+		assert.Equal(t, map[string]plan.UnassignedReason{"1": plan.UnassignedReasonNoMatchingExecutor}, unplaced)
+		assert.NoError(t, plan.ValidateAssignment(currentAssignments, []string{"0", "1"}, unplaced))
+	})
+
+	t.Run("no shard placed", func(t *testing.T) {
+		currentAssignments := map[string][]string{"exec-1": {"0"}}
+		changed, unplaced := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"1"}, activeExecutors, currentAssignments, nil)
+		assert.False(t, changed, "a new shard no executor matches does not change the distribution")
+		assert.Equal(t, map[string][]string{"exec-1": {"0"}}, currentAssignments)
+		assert.Equal(t, map[string]plan.UnassignedReason{"1": plan.UnassignedReasonNoMatchingExecutor}, unplaced)
+	})
+}
+
+func TestUpdateAssignments_TemporaryPinExpires(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE},
+			"exec-3": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"0": {PinnedExecutor: "exec-2", PinExpiresAt: mocks.timeSource.Now().Add(time.Hour)},
+		},
+	}
+	activeExecutors := []string{"exec-1", "exec-2", "exec-3"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := make(map[string][]string)
+		processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"0"}, activeExecutors, currentAssignments, nil)
+		assert.Equal(t, []string{"0"}, currentAssignments["exec-2"])
+	})
+
+	// Once the pin expired the shard is placed like any other shard.
+	mocks.timeSource.Advance(time.Hour)
+	owners := make(map[string]struct{})
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := make(map[string][]string)
+		processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"0"}, activeExecutors, currentAssignments, nil)
+		for executorID, shards := range currentAssignments {
+			if len(shards) > 0 {
+				owners[executorID] = struct{}{}
+			}
+		}
+	})
+	assert.Len(t, owners, len(activeExecutors))
+}
+
+func TestUpdateAssignments_RespectsExecutorShardCap(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.ShardOvercommitFactor = func(string) float64 { return 1 }
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+		"exec-1": {Status: types.ExecutorStatusACTIVE},
+		"exec-2": {Status: types.ExecutorStatusACTIVE},
+		"exec-3": {Status: types.ExecutorStatusACTIVE},
+	}}
+	activeExecutors := []string{"exec-1", "exec-2", "exec-3"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		// 6 shards over 3 executors caps every executor at 2 shards, exec-1 is already above it.
+		currentAssignments := map[string][]string{"exec-1": {"0", "1", "2"}}
+		changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"3", "4", "5"}, activeExecutors, currentAssignments, nil)
+		require.True(t, changed)
+
+		assert.Equal(t, []string{"0", "1", "2"}, currentAssignments["exec-1"])
+		assert.LessOrEqual(t, len(currentAssignments["exec-2"]), 2)
+		assert.LessOrEqual(t, len(currentAssignments["exec-3"]), 2)
+		assert.Len(t, append(currentAssignments["exec-2"], currentAssignments["exec-3"]...), 3)
+	})
+}
+
+func TestUpdateAssignments_RespectsAssignmentRampCap(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.AssignmentRampCap = func(string) int { return 1 }
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// exec-2 was just added and already received shard "1" from an existing executor in this cycle.
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+		},
+	}
+	activeExecutors := []string{"exec-1", "exec-2"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := map[string][]string{"exec-1": {"0"}, "exec-2": {"1"}}
+		changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"2"}, activeExecutors, currentAssignments, nil)
+		require.True(t, changed)
+
+		assert.Equal(t, []string{"1"}, currentAssignments["exec-2"])
+		assert.Equal(t, []string{"0", "2"}, currentAssignments["exec-1"])
+	})
+}
+
+func TestUpdateAssignments_WarmStandby(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	standbyRole := map[string]string{store.ExecutorMetadataRoleKey: types.ExecutorRoleSTANDBY.String()}
+	// exec-3 is gone, its shard "2" is failed over. Shard "3" is new.
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1":  {Status: types.ExecutorStatusACTIVE},
+			"exec-2":  {Status: types.ExecutorStatusACTIVE},
+			"standby": {Status: types.ExecutorStatusACTIVE, Metadata: standbyRole},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {}}},
+			"exec-3": {AssignedShards: map[string]*types.ShardAssignment{"2": {}}},
+		},
+	}
+	activeExecutors := []string{"exec-1", "exec-2", "standby"}
+
+	forEachRoundRobinStart(processor, len(activeExecutors), func() {
+		currentAssignments := map[string][]string{"exec-1": {"0"}, "exec-2": {"1"}, "standby": {}}
+		changed, _ := processor.updateAssignments(processor.Config(), processor.Config().NamespaceConfig(mocks.cfg.Name), namespaceState, []string{"2", "3"}, activeExecutors, currentAssignments, nil)
+		require.True(t, changed)
+
+		assert.Equal(t, []string{"2"}, currentAssignments["standby"], "the failed over shard goes to the standby")
+		assert.Len(t, append(currentAssignments["exec-1"], currentAssignments["exec-2"]...), 3, "the new shard does not")
+	})
+}
+
+func TestRepairDuplicateAssignments(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// Shard "1" is owned by all three executors. exec-1 is the least loaded but does not report the shard,
+	// exec-3 reports it READY but is stale, so exec-2 is the only healthy owner.
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, ReportedShards: map[string]*types.ShardStatusReport{
+				"1": {Status: types.ShardStatusREADY},
+			}},
+			"exec-3": {Status: types.ExecutorStatusACTIVE, ReportedShards: map[string]*types.ShardStatusReport{
+				"1": {Status: types.ShardStatusREADY},
+			}},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"1": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+			"exec-3": {AssignedShards: map[string]*types.ShardAssignment{"1": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"0": {SmoothedLoad: 10},
+		},
+	}
+	staleExecutors := map[string]int64{"exec-3": 0}
+
+	testScope := tally.NewTestScope("test", nil)
+	metricsScope := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{}).
+		Scope(metrics.ShardDistributorAssignLoopScope)
+
+	repaired, changed := processor.repairDuplicateAssignments(namespaceState, staleExecutors, metricsScope)
+	assert.True(t, changed)
+
+	assert.Empty(t, repaired["exec-1"].AssignedShards)
+	assert.ElementsMatch(t, []string{"0", "1"}, slices.Collect(maps.Keys(repaired["exec-2"].AssignedShards)))
+	assert.Empty(t, repaired["exec-3"].AssignedShards)
+	assert.Len(t, namespaceState.ShardAssignments["exec-1"].AssignedShards, 1, "the stored assignments are not modified")
+
+	counter, ok := testScope.Snapshot().Counters()["test.shard_distributor_shard_assign_duplicate_shards+operation=ShardAssignLoop"]
+	require.True(t, ok)
+	assert.Equal(t, int64(2), counter.Value())
+
+	shardsToReassign, currentAssignments := processor.findShardsToReassign([]string{"exec-1", "exec-2"}, namespaceState, repaired, nil, staleExecutors)
+	assert.Empty(t, shardsToReassign)
+	assert.NoError(t, plan.ValidateAssignment(currentAssignments, []string{"0", "1"}, nil))
+}
+
+func TestRebalanceShards_WritesDuplicateRepair(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// The two shards are balanced over the two executors, the only change is dropping the duplicate owner of shard 1.
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, ReportedShards: map[string]*types.ShardStatusReport{
+				"0": {Status: types.ShardStatusREADY},
+			}},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, ReportedShards: map[string]*types.ShardStatusReport{
+				"1": {Status: types.ShardStatusREADY},
+			}},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {}}},
+		},
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(&store.ShardOwner{}, nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Equal(t, []string{"0"}, slices.Collect(maps.Keys(request.NewState.ShardAssignments["exec-1"].AssignedShards)))
+			assert.Equal(t, []string{"1"}, slices.Collect(maps.Keys(request.NewState.ShardAssignments["exec-2"].AssignedShards)))
+			return nil
+		},
+	)
+
+	err := processor.rebalanceShards(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRebalanceShards_LeavesShardsWithoutMatchingExecutorUnassigned(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	mocks.sdConfig.ShardLabelSelectors = func(string) map[string]interface{} {
+		return map[string]interface{}{"1": "tpu=true"}
+	}
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	// exec-2 owned shard 1 and is stale, no remaining executor matches the selector of shard 1.
+	now := mocks.timeSource.Now()
+	mocks.store.EXPECT().GetState(gomock.Any(), mocks.cfg.Name).Return(&store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now.Add(-time.Hour)},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"1": {}}},
+		},
+	}, nil)
+	mocks.store.EXPECT().GetShardOwner(gomock.Any(), mocks.cfg.Name, gomock.Any()).Return(&store.ShardOwner{}, nil).AnyTimes()
+	mocks.election.EXPECT().Guard().Return(store.NopGuard())
+	mocks.store.EXPECT().AssignShards(gomock.Any(), mocks.cfg.Name, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, request store.AssignShardsRequest, _ store.GuardFunc) error {
+			assert.Equal(t, []string{"0"}, slices.Collect(maps.Keys(request.NewState.ShardAssignments["exec-1"].AssignedShards)))
+			assert.NotContains(t, request.NewState.ShardAssignments, "exec-2")
+			assert.Contains(t, request.ExecutorsToDelete, "exec-2")
+			return nil
+		},
+	)
+
+	processor.leaders.add(mocks.cfg.Name, mocks.election)
+	err := processor.rebalanceShards(context.Background())
+	require.NoError(t, err)
+
+	// The leader records the reason of the shard it left out, the other shards get the outcome of the pass.
+	reason, ok := mocks.factory.UnassignedReason(mocks.cfg.Name, "1")
+	require.True(t, ok)
+	assert.Equal(t, plan.UnassignedReasonNoMatchingExecutor, reason)
+	reason, _ = mocks.factory.UnassignedReason(mocks.cfg.Name, "0")
+	assert.Equal(t, plan.UnassignedReasonPending, reason)
+}
+
+func TestRepairDuplicateAssignments_NoDuplicates(t *testing.T) {
+	mocks := setupProcessorTest(t, config.NamespaceTypeFixed)
+	defer mocks.ctrl.Finish()
+	processor := mocks.factory.CreateProcessor(mocks.cfg, mocks.store, mocks.election).(*namespaceProcessor)
+
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{"exec-1": {Status: types.ExecutorStatusACTIVE}},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+		},
+	}
+
+	repaired, changed := processor.repairDuplicateAssignments(namespaceState, nil, metrics.NoopScope)
+
+	assert.False(t, changed)
+	assert.Equal(t, namespaceState.ShardAssignments, repaired)
+}
@@ -0,0 +1,105 @@
+package process
+
+import (
+	"maps"
+	"time"
+
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// maxStabilityCycles bounds the number of rebalance cycles kept in the stability window, so a short
+// rebalance interval with a long window does not grow it without limit.
+const maxStabilityCycles = 4096
+
+// stabilityCycle is the number of shards moved by one rebalance cycle.
+type stabilityCycle struct {
+	time  time.Time
+	moves int
+}
+
+// stabilityWindow accounts the shard moves of the rebalance cycles within a sliding window. It keeps one
+// entry per cycle and the last move time of every shard that moved within the window, so its memory is
+// bounded by the cycles and the shards of the namespace. It is not safe for concurrent use.
+type stabilityWindow struct {
+	cycles    []stabilityCycle
+	lastMoved map[string]time.Time
+}
+
+func newStabilityWindow() *stabilityWindow {
+	return &stabilityWindow{lastMoved: make(map[string]time.Time)}
+}
+
+// record adds a cycle that moved movedShards at now, and forgets what happened before the window.
+func (w *stabilityWindow) record(now time.Time, movedShards []string, window time.Duration) {
+	w.cycles = append(w.cycles, stabilityCycle{time: now, moves: len(movedShards)})
+	for _, shardID := range movedShards {
+		w.lastMoved[shardID] = now
+	}
+
+	windowStart := now.Add(-window)
+	evicted := 0
+	for evicted < len(w.cycles) && (!w.cycles[evicted].time.After(windowStart) || len(w.cycles)-evicted > maxStabilityCycles) {
+		evicted++
+	}
+	w.cycles = w.cycles[evicted:]
+	maps.DeleteFunc(w.lastMoved, func(_ string, movedAt time.Time) bool {
+		return !movedAt.After(windowStart)
+	})
+}
+
+// movesPerCycle returns the average number of shards moved by the cycles within the window.
+func (w *stabilityWindow) movesPerCycle() float64 {
+	if len(w.cycles) == 0 {
+		return 0
+	}
+	total := 0
+	for _, cycle := range w.cycles {
+		total += cycle.moves
+	}
+	return float64(total) / float64(len(w.cycles))
+}
+
+// stableRatio returns the fraction of the totalShards shards that did not move within the window.
+func (w *stabilityWindow) stableRatio(totalShards int) float64 {
+	if totalShards <= 0 {
+		return 1
+	}
+	return max(1-float64(len(w.lastMoved))/float64(totalShards), 0)
+}
+
+// emitAssignmentStability records the shards the pass moved from their previous owner into the stability
+// window, and reports the average moves per cycle and the fraction of stable shards over the window.
+func (p *namespaceProcessor) emitAssignmentStability(
+	sdConfig *config.Config,
+	previousAssignments map[string]store.AssignedState,
+	currentAssignments map[string][]string,
+	metricsLoopScope metrics.Scope,
+) {
+	window := sdConfig.GetStabilityWindow(p.namespaceCfg.Name)
+	if window <= 0 {
+		return
+	}
+
+	owners := make(map[string]string)
+	for executorID, assigned := range previousAssignments {
+		for shardID := range assigned.AssignedShards {
+			owners[shardID] = executorID
+		}
+	}
+	var movedShards []string
+	totalShards := 0
+	for executorID, shardIDs := range currentAssignments {
+		totalShards += len(shardIDs)
+		for _, shardID := range shardIDs {
+			if owner, ok := owners[shardID]; ok && owner != executorID {
+				movedShards = append(movedShards, shardID)
+			}
+		}
+	}
+
+	p.stability.record(p.timeSource.Now(), movedShards, window)
+	metricsLoopScope.UpdateGauge(metrics.ShardDistributorAssignmentMovesPerCycle, p.stability.movesPerCycle())
+	metricsLoopScope.UpdateGauge(metrics.ShardDistributorAssignmentStableShardRatio, p.stability.stableRatio(totalShards))
+}
@@ -0,0 +1,71 @@
+package process
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+)
+
+func TestStabilityWindow_StableAndChurnyPeriods(t *testing.T) {
+	const (
+		totalShards = 100
+		window      = 10 * time.Minute
+	)
+	timeSource := clock.NewMockedTimeSource()
+	stability := newStabilityWindow()
+
+	// A stable period: one shard moves every few cycles.
+	for i := 0; i < 10; i++ {
+		var moved []string
+		if i%5 == 0 {
+			moved = []string{"shard-" + strconv.Itoa(i)}
+		}
+		stability.record(timeSource.Now(), moved, window)
+		timeSource.Advance(time.Minute)
+	}
+	assert.InDelta(t, 0.2, stability.movesPerCycle(), 1e-9)
+	assert.InDelta(t, 0.98, stability.stableRatio(totalShards), 1e-9)
+
+	// A churny period: most shards move every cycle. Once it fills the window the stable period is forgotten.
+	for i := 0; i < 10; i++ {
+		moved := make([]string, 0, 60)
+		for shard := 0; shard < 60; shard++ {
+			moved = append(moved, "shard-"+strconv.Itoa((i*7+shard)%totalShards))
+		}
+		stability.record(timeSource.Now(), moved, window)
+		timeSource.Advance(time.Minute)
+	}
+	assert.InDelta(t, 60, stability.movesPerCycle(), 1e-9)
+	assert.Less(t, stability.stableRatio(totalShards), 0.2)
+}
+
+func TestStabilityWindow_ForgetsMovesBeforeTheWindow(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	stability := newStabilityWindow()
+
+	stability.record(timeSource.Now(), []string{"shard-1", "shard-2"}, time.Minute)
+	assert.InDelta(t, 0.8, stability.stableRatio(10), 1e-9)
+
+	timeSource.Advance(2 * time.Minute)
+	stability.record(timeSource.Now(), nil, time.Minute)
+	assert.Len(t, stability.cycles, 1)
+	assert.Empty(t, stability.lastMoved)
+	assert.Zero(t, stability.movesPerCycle())
+	assert.Equal(t, 1.0, stability.stableRatio(10))
+}
+
+func TestStabilityWindow_BoundsTheCycles(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	stability := newStabilityWindow()
+
+	for i := 0; i < maxStabilityCycles+10; i++ {
+		stability.record(timeSource.Now(), nil, 24*time.Hour)
+		timeSource.Advance(time.Second)
+	}
+	assert.Len(t, stability.cycles, maxStabilityCycles)
+	assert.Equal(t, 1.0, stability.stableRatio(0))
+}
@@ -0,0 +1,67 @@
+package loadbalancer
+
+import (
+	"math"
+
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// LoadSummary describes the load of a namespace in the terms an autoscaler of the executor pool needs.
+type LoadSummary struct {
+	// TotalLoad is the sum of the loads of the shards owned by the executors that can own shards.
+	TotalLoad float64
+	// ExecutorCount is the number of executors that can own shards.
+	ExecutorCount int
+	// MeanLoad is TotalLoad spread evenly over ExecutorCount, 0 when there are no such executors.
+	MeanLoad float64
+	// RecommendedExecutorCount is the number of executors that keeps MeanLoad at or below the target.
+	RecommendedExecutorCount int
+	// LoadCV is the coefficient of variation of the loads of the executors that can own shards.
+	LoadCV float64
+	// ShardCountCV is the coefficient of variation of the shard counts of the executors that can own shards.
+	ShardCountCV float64
+}
+
+// HealthScore returns the health score of the namespace, see HealthScore.
+func (s LoadSummary) HealthScore(loadWeight, shardCountWeight float64) float64 {
+	return HealthScore(s.LoadCV, s.ShardCountCV, loadWeight, shardCountWeight)
+}
+
+// SummarizeLoad computes the load summary of state. The recommended executor count is
+// ceil(TotalLoad / targetMeanLoad), clamped to [minExecutors, maxExecutors]; a maxExecutors of 0 means no
+// upper bound. Without a positive target the current executor count is recommended, within the same bounds.
+// Shard loads are the smoothed loads, or the weight overrides when set.
+func SummarizeLoad(state *store.NamespaceState, targetMeanLoad float64, minExecutors, maxExecutors int) LoadSummary {
+	var summary LoadSummary
+	var executorLoads, shardCounts []float64
+	if state != nil {
+		for executorID, executor := range state.Executors {
+			if !executor.CanOwnShards() {
+				continue
+			}
+			summary.ExecutorCount++
+			executorLoad := 0.0
+			assignedShards := state.ShardAssignments[executorID].AssignedShards
+			for shardID := range assignedShards {
+				executorLoad += state.ShardStats[shardID].Load()
+			}
+			summary.TotalLoad += executorLoad
+			executorLoads = append(executorLoads, executorLoad)
+			shardCounts = append(shardCounts, float64(len(assignedShards)))
+		}
+	}
+	summary.MeanLoad = plan.SafeDivide(summary.TotalLoad, float64(summary.ExecutorCount), 0)
+	summary.LoadCV = coefficientOfVariation(executorLoads)
+	summary.ShardCountCV = coefficientOfVariation(shardCounts)
+
+	recommended := summary.ExecutorCount
+	if targetMeanLoad > 0 {
+		recommended = int(math.Ceil(summary.TotalLoad / targetMeanLoad))
+	}
+	if maxExecutors > 0 {
+		recommended = min(recommended, maxExecutors)
+	}
+	summary.RecommendedExecutorCount = max(recommended, minExecutors, 0)
+	return summary
+}
@@ -0,0 +1,77 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestSummarizeLoad(t *testing.T) {
+	stateWithLoad := func(shardLoad float64) *store.NamespaceState {
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"exec-1": {Status: types.ExecutorStatusACTIVE},
+				"exec-2": {Status: types.ExecutorStatusACTIVE},
+				"exec-3": {Status: types.ExecutorStatusDRAINING},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"0": {}, "1": {}}},
+				"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"2": {}}},
+				"exec-3": {AssignedShards: map[string]*types.ShardAssignment{"3": {}}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"0": {SmoothedLoad: shardLoad},
+				"1": {SmoothedLoad: shardLoad},
+				"2": {SmoothedLoad: shardLoad, WeightOverride: 2 * shardLoad},
+				"3": {SmoothedLoad: 100},
+			},
+		}
+	}
+
+	t.Run("summarizes the executors that can own shards", func(t *testing.T) {
+		summary := SummarizeLoad(stateWithLoad(10), 15, 1, 0)
+
+		assert.Equal(t, LoadSummary{
+			TotalLoad:                40,
+			ExecutorCount:            2,
+			MeanLoad:                 20,
+			RecommendedExecutorCount: 3,
+			LoadCV:                   0,
+			ShardCountCV:             1.0 / 3.0,
+		}, summary)
+	})
+
+	t.Run("shard count imbalance lowers the health score of a load balanced namespace", func(t *testing.T) {
+		summary := SummarizeLoad(stateWithLoad(10), 15, 1, 0)
+
+		assert.Zero(t, summary.LoadCV)
+		assert.Less(t, summary.HealthScore(1, 1), 1.0)
+		assert.Equal(t, 1.0, summary.HealthScore(1, 0), "only the load counts without a shard count weight")
+	})
+
+	t.Run("rising load increases the recommended count", func(t *testing.T) {
+		previous := 0
+		for _, shardLoad := range []float64{5, 10, 20, 40} {
+			recommended := SummarizeLoad(stateWithLoad(shardLoad), 15, 1, 0).RecommendedExecutorCount
+			assert.Greater(t, recommended, previous)
+			previous = recommended
+		}
+	})
+
+	t.Run("recommendation is clamped to the bounds", func(t *testing.T) {
+		assert.Equal(t, 2, SummarizeLoad(stateWithLoad(10), 15, 1, 2).RecommendedExecutorCount)
+		assert.Equal(t, 3, SummarizeLoad(stateWithLoad(100), 15, 1, 3).RecommendedExecutorCount)
+		assert.Equal(t, 4, SummarizeLoad(stateWithLoad(1), 15, 4, 10).RecommendedExecutorCount)
+	})
+
+	t.Run("without a target the current count is recommended", func(t *testing.T) {
+		assert.Equal(t, 2, SummarizeLoad(stateWithLoad(10), 0, 1, 0).RecommendedExecutorCount)
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		assert.Equal(t, LoadSummary{RecommendedExecutorCount: 1}, SummarizeLoad(nil, 15, 1, 0))
+	})
+}
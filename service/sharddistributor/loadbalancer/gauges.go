@@ -0,0 +1,75 @@
+package loadbalancer
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// Names of the gauges rendered by BalanceGauges.
+const (
+	GaugeExecutorLoad       = "shard_distributor_executor_load"
+	GaugeExecutorShardCount = "shard_distributor_executor_shard_count"
+	GaugeShardSmoothedLoad  = "shard_distributor_shard_smoothed_load"
+)
+
+// Labels of the gauges rendered by BalanceGauges.
+const (
+	GaugeLabelNamespace = "namespace"
+	GaugeLabelExecutor  = "executor"
+	GaugeLabelShard     = "shard"
+)
+
+// GaugeSample is one value of a gauge with its labels, in the shape a Prometheus collector exports.
+type GaugeSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// BalanceGauges renders the balance state of a namespace as gauge samples: the load and shard count of every
+// executor, and the smoothed load of every assigned shard that has statistics. Executor loads are the sums of
+// the loads of their shards, the weight overrides when set. Samples are ordered by executor ID, then shard ID,
+// so consecutive scrapes list them the same way.
+func BalanceGauges(namespace string, state *store.NamespaceState) []GaugeSample {
+	if state == nil {
+		return nil
+	}
+
+	executorIDs := slices.Collect(maps.Keys(state.Executors))
+	for executorID := range state.ShardAssignments {
+		if _, ok := state.Executors[executorID]; !ok {
+			executorIDs = append(executorIDs, executorID)
+		}
+	}
+	slices.Sort(executorIDs)
+
+	var samples []GaugeSample
+	for _, executorID := range executorIDs {
+		executorLabels := map[string]string{GaugeLabelNamespace: namespace, GaugeLabelExecutor: executorID}
+		shardIDs := slices.Sorted(maps.Keys(state.ShardAssignments[executorID].AssignedShards))
+
+		executorLoad := 0.0
+		var shardSamples []GaugeSample
+		for _, shardID := range shardIDs {
+			stats, ok := state.ShardStats[shardID]
+			if !ok {
+				continue
+			}
+			executorLoad += stats.Load()
+			shardSamples = append(shardSamples, GaugeSample{
+				Name:   GaugeShardSmoothedLoad,
+				Labels: map[string]string{GaugeLabelNamespace: namespace, GaugeLabelExecutor: executorID, GaugeLabelShard: shardID},
+				Value:  stats.SmoothedLoad,
+			})
+		}
+
+		samples = append(samples,
+			GaugeSample{Name: GaugeExecutorLoad, Labels: executorLabels, Value: executorLoad},
+			GaugeSample{Name: GaugeExecutorShardCount, Labels: maps.Clone(executorLabels), Value: float64(len(shardIDs))},
+		)
+		samples = append(samples, shardSamples...)
+	}
+	return samples
+}
@@ -0,0 +1,47 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestBalanceGauges(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a": {Status: types.ExecutorStatusACTIVE},
+			"exec-b": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-a": {AssignedShards: map[string]*types.ShardAssignment{"shard-2": {}, "shard-1": {}}},
+			// exec-c no longer heartbeats but still owns a shard.
+			"exec-c": {AssignedShards: map[string]*types.ShardAssignment{"shard-3": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"shard-1": {SmoothedLoad: 2},
+			"shard-2": {SmoothedLoad: 3, WeightOverride: 5},
+		},
+	}
+	executorLabels := func(executorID string) map[string]string {
+		return map[string]string{GaugeLabelNamespace: "test-namespace", GaugeLabelExecutor: executorID}
+	}
+	shardLabels := func(executorID, shardID string) map[string]string {
+		return map[string]string{GaugeLabelNamespace: "test-namespace", GaugeLabelExecutor: executorID, GaugeLabelShard: shardID}
+	}
+
+	assert.Equal(t, []GaugeSample{
+		{Name: GaugeExecutorLoad, Labels: executorLabels("exec-a"), Value: 7},
+		{Name: GaugeExecutorShardCount, Labels: executorLabels("exec-a"), Value: 2},
+		{Name: GaugeShardSmoothedLoad, Labels: shardLabels("exec-a", "shard-1"), Value: 2},
+		{Name: GaugeShardSmoothedLoad, Labels: shardLabels("exec-a", "shard-2"), Value: 3},
+		{Name: GaugeExecutorLoad, Labels: executorLabels("exec-b"), Value: 0},
+		{Name: GaugeExecutorShardCount, Labels: executorLabels("exec-b"), Value: 0},
+		{Name: GaugeExecutorLoad, Labels: executorLabels("exec-c"), Value: 0},
+		{Name: GaugeExecutorShardCount, Labels: executorLabels("exec-c"), Value: 1},
+	}, BalanceGauges("test-namespace", state))
+
+	assert.Nil(t, BalanceGauges("test-namespace", nil))
+}
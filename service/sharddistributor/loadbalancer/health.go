@@ -0,0 +1,17 @@
+package loadbalancer
+
+import "github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+
+// HealthScore blends the coefficients of variation of the executor loads and shard counts of a namespace
+// into a score in (0, 1]: 1 for a perfectly balanced assignment, decreasing towards 0 as the weighted
+// coefficient of variation grows. An executor owning disproportionately many shards is at risk even when
+// the load is balanced, so shard count imbalance alone lowers the score.
+// Without positive weights only the load coefficient of variation counts.
+func HealthScore(loadCV, shardCountCV, loadWeight, shardCountWeight float64) float64 {
+	loadWeight, shardCountWeight = max(loadWeight, 0), max(shardCountWeight, 0)
+	if loadWeight+shardCountWeight == 0 {
+		loadWeight = 1
+	}
+	weightedCV := plan.SafeDivide(loadWeight*loadCV+shardCountWeight*shardCountCV, loadWeight+shardCountWeight, 0)
+	return 1 / (1 + max(weightedCV, 0))
+}
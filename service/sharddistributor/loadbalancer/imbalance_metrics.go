@@ -6,6 +6,7 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
@@ -24,6 +25,7 @@ func EmitAssignmentImbalanceMetrics(
 
 	emitSmoothedLoadMetrics := cfg.GetLoadBalancingMode(namespace) == types.LoadBalancingModeGREEDY
 	reportedLoads := make([]float64, 0, len(assignments))
+	shardCounts := make([]float64, 0, len(assignments))
 	var smoothedLoads []float64
 	if emitSmoothedLoadMetrics {
 		smoothedLoads = make([]float64, 0, len(assignments))
@@ -62,13 +64,18 @@ func EmitAssignmentImbalanceMetrics(
 		}
 
 		reportedLoads = append(reportedLoads, reportedLoad)
+		shardCounts = append(shardCounts, float64(len(shards)))
 		if emitSmoothedLoadMetrics {
 			smoothedLoads = append(smoothedLoads, smoothedLoad)
 		}
 	}
 
 	metricsScope.UpdateGauge(metrics.ShardDistributorAssignmentLoadMaxOverMean, maxOverMean(reportedLoads))
-	metricsScope.UpdateGauge(metrics.ShardDistributorAssignmentLoadCV, coefficientOfVariation(reportedLoads))
+	loadCV, shardCountCV := coefficientOfVariation(reportedLoads), coefficientOfVariation(shardCounts)
+	metricsScope.UpdateGauge(metrics.ShardDistributorAssignmentLoadCV, loadCV)
+	metricsScope.UpdateGauge(metrics.ShardDistributorAssignmentShardCountCV, shardCountCV)
+	loadWeight, shardCountWeight := cfg.GetHealthScoreWeights(namespace)
+	metricsScope.UpdateGauge(metrics.ShardDistributorAssignmentHealthScore, HealthScore(loadCV, shardCountCV, loadWeight, shardCountWeight))
 	if !emitSmoothedLoadMetrics {
 		return
 	}
@@ -97,11 +104,8 @@ func maxOverMean(values []float64) float64 {
 			maxValue = value
 		}
 	}
-	mean := total / float64(len(values))
-	if mean == 0 {
-		return 0
-	}
-	return maxValue / mean
+	mean := plan.SafeDivide(total, float64(len(values)), 0)
+	return plan.SafeDivide(maxValue, mean, 0)
 }
 
 func coefficientOfVariation(values []float64) float64 {
@@ -113,7 +117,7 @@ func coefficientOfVariation(values []float64) float64 {
 	for _, value := range values {
 		total += value
 	}
-	mean := total / float64(len(values))
+	mean := plan.SafeDivide(total, float64(len(values)), 0)
 	if mean == 0 {
 		return 0
 	}
@@ -125,5 +129,5 @@ func coefficientOfVariation(values []float64) float64 {
 	}
 	variance /= float64(len(values))
 
-	return math.Sqrt(variance) / mean
+	return plan.SafeDivide(math.Sqrt(variance), mean, 0)
 }
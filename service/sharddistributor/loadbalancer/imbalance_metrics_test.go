@@ -4,6 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
 	"github.com/uber/cadence/common/metrics"
 	metricmocks "github.com/uber/cadence/common/metrics/mocks"
 	"github.com/uber/cadence/common/types"
@@ -19,6 +22,8 @@ func TestEmitAssignmentImbalanceMetrics_NaiveEmitsReportedLoadOnly(t *testing.T)
 
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadMaxOverMean, 1.5).Once()
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadCV, 0.5).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentShardCountCV, 1.0/3.0).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentHealthScore, HealthScore(0.5, 1.0/3.0, 1, 1)).Once()
 
 	EmitAssignmentImbalanceMetrics(cfg, testNamespace, metricsScope, testAssignments(), testNamespaceState(time.Now()))
 
@@ -33,6 +38,8 @@ func TestEmitAssignmentImbalanceMetrics_GreedyEmitsSmoothedLoadMetrics(t *testin
 
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadMaxOverMean, 1.5).Once()
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadCV, 0.5).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentShardCountCV, 1.0/3.0).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentHealthScore, HealthScore(0.5, 1.0/3.0, 1, 1)).Once()
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentSmoothedLoadMaxOverMean, 1.5).Once()
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentSmoothedLoadCV, 0.5).Once()
 	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentSmoothedLoadMissingRatio, 1.0/3.0).Once()
@@ -42,6 +49,49 @@ func TestEmitAssignmentImbalanceMetrics_GreedyEmitsSmoothedLoadMetrics(t *testin
 	metricsScope.AssertExpectations(t)
 }
 
+func TestEmitAssignmentImbalanceMetrics_ShardCountImbalanceLowersHealthScore(t *testing.T) {
+	cfg := loadBalancingModeConfig(config.LoadBalancingModeNAIVE)
+	metricsScope := &metricmocks.Scope{}
+
+	// Both executors report a load of 30, but exec-1 owns three times as many shards.
+	assignments := map[string][]string{
+		"exec-1": {"shard-1", "shard-2", "shard-3"},
+		"exec-2": {"shard-4"},
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-1": {ReportedShards: map[string]*types.ShardStatusReport{
+				"shard-1": {ShardLoad: 10}, "shard-2": {ShardLoad: 10}, "shard-3": {ShardLoad: 10},
+			}},
+			"exec-2": {ReportedShards: map[string]*types.ShardStatusReport{
+				"shard-4": {ShardLoad: 30},
+			}},
+		},
+	}
+
+	var healthScore float64
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadMaxOverMean, 1.0).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentLoadCV, 0.0).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentShardCountCV, 0.5).Once()
+	metricsScope.On("UpdateGauge", metrics.ShardDistributorAssignmentHealthScore, mock.Anything).
+		Run(func(args mock.Arguments) { healthScore = args.Get(1).(float64) }).Once()
+
+	EmitAssignmentImbalanceMetrics(cfg, testNamespace, metricsScope, assignments, state)
+
+	metricsScope.AssertExpectations(t)
+	assert.Less(t, healthScore, 1.0)
+	assert.InDelta(t, 0.8, healthScore, 1e-9)
+}
+
+func TestHealthScore(t *testing.T) {
+	assert.Equal(t, 1.0, HealthScore(0, 0, 1, 1))
+	assert.Equal(t, 0.5, HealthScore(1, 1, 1, 1))
+	assert.Equal(t, 0.5, HealthScore(0, 2, 1, 1), "shard count imbalance alone lowers the score")
+	assert.Equal(t, 1.0, HealthScore(0, 2, 1, 0), "the shard count is ignored without a weight")
+	assert.Equal(t, 0.5, HealthScore(1, 2, 0, 0), "without weights only the load counts")
+	assert.Less(t, HealthScore(0, 1, 1, 3), HealthScore(0, 1, 1, 1), "a higher shard count weight lowers the score further")
+}
+
 func loadBalancingModeConfig(mode string) *config.Config {
 	return &config.Config{
 		LoadBalancingMode: func(namespace string) string {
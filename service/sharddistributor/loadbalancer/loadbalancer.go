@@ -1,7 +1,11 @@
 package loadbalancer
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
 	"time"
 
 	"github.com/uber/cadence/common/log"
@@ -9,6 +13,7 @@ import (
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/strategy/consistenthash"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/strategy/greedy"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/strategy/naive"
 	"github.com/uber/cadence/service/sharddistributor/store"
@@ -19,41 +24,287 @@ import (
 // would still need to switch implementations dynamically based on the current
 // value of the dynamic config.
 
-// PlanInitialPlacement returns planned placements for a batch of unassigned shards.
+// PlanInitialPlacement returns planned placements for a batch of unassigned shards at now, and why each shard
+// left out of the placements is not placed.
+// Shards with a strict SLA tier are placed before best-effort shards, so they get the least loaded executors.
+// Warm standby executors are only used when no other executor can own shards.
+// A pinned shard is placed on the executor it is pinned to, or left out when that executor cannot own shards.
+// Executors whose shards carry the executor load capacity are not used, so the shards are left out when every
+// executor does.
 func PlanInitialPlacement(
 	cfg *config.Config,
 	namespace string,
 	state *store.NamespaceState,
 	shardIDs []string,
-) ([]plan.Placement, error) {
-	mode := cfg.GetLoadBalancingMode(namespace)
+	now time.Time,
+) ([]plan.Placement, map[string]plan.UnassignedReason, error) {
+	shardIDs = orderBySLATier(shardIDs, cfg.GetShardSLATiers(namespace))
+	pinned, shardIDs, unplaced := placePinnedShards(state, shardIDs, PinnedShards(cfg, namespace, state, now))
+	state = withoutStandbys(state)
+	state, atCapacity := withoutExecutorsAtCapacity(state, cfg.GetExecutorLoadCapacity(namespace))
+	if atCapacity {
+		for _, shardID := range shardIDs {
+			unplaced[shardID] = plan.UnassignedReasonAtCapacity
+		}
+		return pinned, unplaced, nil
+	}
+
+	var (
+		placements []plan.Placement
+		notPlaced  map[string]plan.UnassignedReason
+		err        error
+	)
+	mode := cfg.GetAssignmentMode(namespace)
 	switch mode {
 	case types.LoadBalancingModeNAIVE:
-		return naive.PlanInitialPlacement(state, shardIDs)
+		placements, notPlaced, err = naive.PlanInitialPlacement(state, shardIDs, cfg.GetShardLabelSelectors(namespace))
 	case types.LoadBalancingModeGREEDY:
-		return greedy.PlanInitialPlacement(state, shardIDs)
+		tieBreak := cfg.GetPlacementTieBreak(namespace)
+		var rng *rand.Rand
+		if tieBreak == config.PlacementTieBreakRandom {
+			// Placements happen outside of the rebalance passes, so the time of the placement is its epoch.
+			rng = NewRand(namespace, now.UnixNano())
+		}
+		placements, notPlaced, err = greedy.PlanInitialPlacement(
+			state,
+			shardIDs,
+			cfg.GetShardLoadFloor(namespace),
+			cfg.GetShardOverhead(namespace),
+			cfg.GetShardLabelSelectors(namespace),
+			tieBreak,
+			rng,
+		)
+	case types.LoadBalancingModeCONSISTENTHASH:
+		placements, notPlaced, err = consistenthash.PlanInitialPlacement(state, shardIDs, cfg.GetShardLabelSelectors(namespace))
 	default:
-		return nil, fmt.Errorf("unsupported load balancing mode: %s", mode)
+		return nil, nil, fmt.Errorf("unsupported load balancing mode: %s", mode)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	maps.Copy(unplaced, notPlaced)
+	return append(pinned, placements...), unplaced, nil
+}
+
+// placePinnedShards places the shards of shardIDs that are pinned on their executor. It returns their
+// placements, the shards that are not pinned, and the pinned shards whose executor cannot own shards.
+func placePinnedShards(
+	state *store.NamespaceState,
+	shardIDs []string,
+	pinnedShards map[string]string,
+) (placements []plan.Placement, notPinned []string, unplaced map[string]plan.UnassignedReason) {
+	unplaced = make(map[string]plan.UnassignedReason)
+	if len(pinnedShards) == 0 {
+		return nil, shardIDs, unplaced
+	}
+	notPinned = make([]string, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		executorID, ok := pinnedShards[shardID]
+		switch {
+		case !ok:
+			notPinned = append(notPinned, shardID)
+		case state != nil && state.Executors[executorID].CanOwnShards():
+			placements = append(placements, plan.Placement{ShardID: shardID, ExecutorID: executorID})
+		default:
+			unplaced[shardID] = plan.UnassignedReasonPinned
+		}
+	}
+	return placements, notPinned, unplaced
+}
+
+// withoutExecutorsAtCapacity returns a copy of state without the executors whose load reached capacity: the
+// total load they report, or else the load of their shards. state is returned as is when capacity is not
+// positive or no executor reached it. atCapacity reports whether every executor that can own shards reached it.
+func withoutExecutorsAtCapacity(state *store.NamespaceState, capacity float64) (_ *store.NamespaceState, atCapacity bool) {
+	if state == nil || capacity <= 0 {
+		return state, false
+	}
+	executors := make(map[string]store.HeartbeatState, len(state.Executors))
+	canOwnShards, full := 0, 0
+	for executorID, executor := range state.Executors {
+		if executor.CanOwnShards() {
+			canOwnShards++
+			if executorLoad(state, executorID) >= capacity {
+				full++
+				continue
+			}
+		}
+		executors[executorID] = executor
+	}
+	if full == 0 {
+		return state, false
+	}
+	filtered := *state
+	filtered.Executors = executors
+	return &filtered, full == canOwnShards
+}
+
+// executorLoad returns the total load executorID reports, or the sum of the loads of its shards when it
+// does not report one.
+func executorLoad(state *store.NamespaceState, executorID string) float64 {
+	if load, ok := state.Executors[executorID].ExecutorLoad(); ok {
+		return load
+	}
+	load := 0.0
+	for shardID := range state.ShardAssignments[executorID].AssignedShards {
+		load += state.ShardStats[shardID].Load()
+	}
+	return load
+}
+
+// withoutStandbys returns a copy of state without its warm standby executors, so they are not used for
+// placement during normal operation. state is returned as is when it has no standbys, or when only
+// standbys can own shards.
+func withoutStandbys(state *store.NamespaceState) *store.NamespaceState {
+	if state == nil {
+		return nil
 	}
+	executors := make(map[string]store.HeartbeatState, len(state.Executors))
+	hasStandby, canOwnShards := false, false
+	for executorID, executor := range state.Executors {
+		if executor.IsStandby() {
+			hasStandby = true
+			continue
+		}
+		executors[executorID] = executor
+		canOwnShards = canOwnShards || executor.CanOwnShards()
+	}
+	if !hasStandby || !canOwnShards {
+		return state
+	}
+	filtered := *state
+	filtered.Executors = executors
+	return &filtered
+}
+
+// orderBySLATier returns the shard IDs with the strict SLA tier shards first, keeping the order within each tier.
+// The input is not modified.
+func orderBySLATier(shardIDs []string, tiers map[string]string) []string {
+	if len(tiers) == 0 {
+		return shardIDs
+	}
+	ordered := slices.Clone(shardIDs)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return cmp.Compare(slaTierRank(tiers[a]), slaTierRank(tiers[b]))
+	})
+	return ordered
+}
+
+func slaTierRank(tier string) int {
+	if tier == config.SLATierStrict {
+		return 0
+	}
+	return 1
 }
 
 // PlanRebalance returns planned shard moves for the current assignment state.
+// Pinned shards are never moved, and shards are only moved to executors matching their label selector.
+// rng is the randomness source for the weighted-random shed selection. A positive maxMoves caps the number
+// of moves. Moves are planned one at a time, each one the most beneficial given the moves before it, so the
+// cap keeps the highest-benefit moves and defers the rest.
 func PlanRebalance(
 	cfg *config.Config,
 	namespace string,
 	state *store.NamespaceState,
 	currentAssignments map[string][]string,
+	maxMoves int,
+	rng *rand.Rand,
 	now time.Time,
 	logger log.Logger,
 	metricsScope metrics.Scope,
 ) ([]plan.Move, error) {
-	mode := cfg.GetLoadBalancingMode(namespace)
+	var (
+		moves []plan.Move
+		err   error
+	)
+	mode := cfg.GetAssignmentMode(namespace)
+	pinnedShards := PinnedShards(cfg, namespace, state, now)
+	selectors := cfg.GetShardLabelSelectors(namespace)
 	switch mode {
 	case types.LoadBalancingModeNAIVE:
-		return naive.PlanRebalance(cfg.LoadBalancingNaive, namespace, state, currentAssignments, logger, metricsScope)
+		moves, err = naive.PlanRebalance(cfg.LoadBalancingNaive, namespace, state, currentAssignments, pinnedShards, selectors, logger, metricsScope)
 	case types.LoadBalancingModeGREEDY:
-		return greedy.PlanRebalance(cfg.LoadBalancingGreedy, namespace, state, currentAssignments, now, logger, metricsScope)
+		var shedRand *rand.Rand
+		if cfg.GetShedSelectionMode(namespace) == config.ShedSelectionWeightedRandom {
+			shedRand = rng
+		}
+		moves, err = greedy.PlanRebalance(cfg.LoadBalancingGreedy, namespace, state, currentAssignments, pinnedShards, selectors, shedRand, now, logger, metricsScope)
+	case types.LoadBalancingModeCONSISTENTHASH:
+		moves, err = consistenthash.PlanRebalance(state, currentAssignments, pinnedShards, selectors)
 	default:
 		return nil, fmt.Errorf("unsupported load balancing mode: %s", mode)
 	}
+	if err != nil {
+		return nil, err
+	}
+	moves = withoutMovesToStandbys(moves, state)
+	moves = keepMinActiveExecutors(moves, currentAssignments, cfg.GetMinActiveExecutors(namespace))
+	if maxMoves > 0 && len(moves) > maxMoves {
+		moves = moves[:maxMoves]
+	}
+	return moves, nil
+}
+
+// PinnedShards returns the shards that must not be moved automatically, mapped to the executor they are
+// required on: the pins of the dynamic config, and the operator pins stored in the shard statistics that
+// are still active at now. A shard pinned in the dynamic config keeps that pin.
+func PinnedShards(cfg *config.Config, namespace string, state *store.NamespaceState, now time.Time) map[string]string {
+	pinnedShards := cfg.GetPinnedShards(namespace)
+	if state == nil {
+		return pinnedShards
+	}
+	for shardID, stats := range state.ShardStats {
+		executorID, ok := stats.ActivePin(now)
+		if !ok {
+			continue
+		}
+		if pinnedShards == nil {
+			pinnedShards = make(map[string]string)
+		}
+		if _, pinned := pinnedShards[shardID]; !pinned {
+			pinnedShards[shardID] = executorID
+		}
+	}
+	return pinnedShards
+}
+
+// withoutMovesToStandbys drops the moves onto warm standby executors, which only receive shards on failover.
+func withoutMovesToStandbys(moves []plan.Move, state *store.NamespaceState) []plan.Move {
+	return slices.DeleteFunc(moves, func(move plan.Move) bool {
+		return state.Executors[move.To].IsStandby()
+	})
+}
+
+// keepMinActiveExecutors drops the moves that would empty an executor while no more than minActiveExecutors
+// executors own shards. Moves are applied in order, so a dropped move leaves its shard where it was.
+func keepMinActiveExecutors(moves []plan.Move, currentAssignments map[string][]string, minActiveExecutors int) []plan.Move {
+	if minActiveExecutors <= 0 || len(moves) == 0 {
+		return moves
+	}
+
+	shardCounts := make(map[string]int, len(currentAssignments))
+	executorsWithShards := 0
+	for executorID, shardIDs := range currentAssignments {
+		shardCounts[executorID] = len(shardIDs)
+		if len(shardIDs) > 0 {
+			executorsWithShards++
+		}
+	}
+
+	kept := make([]plan.Move, 0, len(moves))
+	for _, move := range moves {
+		if shardCounts[move.From] == 1 && shardCounts[move.To] > 0 && executorsWithShards <= minActiveExecutors {
+			continue
+		}
+		if shardCounts[move.From] == 1 {
+			executorsWithShards--
+		}
+		if shardCounts[move.To] == 0 {
+			executorsWithShards++
+		}
+		shardCounts[move.From]--
+		shardCounts[move.To]++
+		kept = append(kept, move)
+	}
+	return kept
 }
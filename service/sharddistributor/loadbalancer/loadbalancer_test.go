@@ -1,14 +1,18 @@
 package loadbalancer
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/uber/cadence/common/log/testlogger"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
@@ -22,6 +26,7 @@ func TestPlanInitialPlacement(t *testing.T) {
 	}{
 		{name: "naive", mode: config.LoadBalancingModeNAIVE},
 		{name: "greedy", mode: config.LoadBalancingModeGREEDY},
+		{name: "consistent hash", mode: config.LoadBalancingModeCONSISTENTHASH},
 		{name: "invalid", mode: config.LoadBalancingModeINVALID, wantErr: true},
 	}
 	for _, tt := range tests {
@@ -31,7 +36,7 @@ func TestPlanInitialPlacement(t *testing.T) {
 					return tt.mode
 				},
 			}
-			placements, err := PlanInitialPlacement(cfg, "test-namespace", &store.NamespaceState{}, nil)
+			placements, _, err := PlanInitialPlacement(cfg, "test-namespace", &store.NamespaceState{}, nil, time.Now())
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Nil(t, placements)
@@ -50,18 +55,448 @@ func TestPlanInitialPlacement_NoActiveExecutors(t *testing.T) {
 		},
 	}
 
-	_, err := PlanInitialPlacement(cfg, "test-namespace", &store.NamespaceState{}, []string{"shard-1"})
+	_, _, err := PlanInitialPlacement(cfg, "test-namespace", &store.NamespaceState{}, []string{"shard-1"}, time.Now())
 	assert.True(t, errors.Is(err, plan.ErrNoActiveExecutors))
 }
 
+func TestPlanInitialPlacement_StrictSLAShardsFirst(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a": {Status: types.ExecutorStatusACTIVE},
+			"exec-b": {Status: types.ExecutorStatusACTIVE},
+			"exec-c": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-a": {AssignedShards: map[string]*types.ShardAssignment{"a": {}}},
+			"exec-b": {AssignedShards: map[string]*types.ShardAssignment{"b": {}}},
+			"exec-c": {AssignedShards: map[string]*types.ShardAssignment{"c": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"a": {SmoothedLoad: 1},
+			"b": {SmoothedLoad: 2},
+			"c": {SmoothedLoad: 10},
+		},
+	}
+	newCfg := func(tiers map[string]interface{}) *config.Config {
+		return &config.Config{
+			LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+			ShardSLATiers:     func(string) map[string]interface{} { return tiers },
+		}
+	}
+	shardIDs := []string{"best-effort", "strict"}
+
+	placements, _, err := PlanInitialPlacement(newCfg(nil), "test-namespace", state, shardIDs, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{
+		{ShardID: "best-effort", ExecutorID: "exec-a"},
+		{ShardID: "strict", ExecutorID: "exec-b"},
+	}, placements, "without tiers shards are placed in the given order")
+
+	placements, _, err = PlanInitialPlacement(newCfg(map[string]interface{}{"strict": config.SLATierStrict}), "test-namespace", state, shardIDs, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{
+		{ShardID: "strict", ExecutorID: "exec-a"},
+		{ShardID: "best-effort", ExecutorID: "exec-b"},
+	}, placements, "the strict shard gets the least loaded executor")
+	assert.Equal(t, []string{"best-effort", "strict"}, shardIDs)
+}
+
+func TestPlanInitialPlacement_SkipsStandbys(t *testing.T) {
+	standbyRole := map[string]string{store.ExecutorMetadataRoleKey: types.ExecutorRoleSTANDBY.String()}
+	cfg := &config.Config{
+		LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+	}
+
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a":  {Status: types.ExecutorStatusACTIVE},
+			"standby": {Status: types.ExecutorStatusACTIVE, Metadata: standbyRole},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-a": {AssignedShards: map[string]*types.ShardAssignment{"a": {}}},
+		},
+	}
+	placements, _, err := PlanInitialPlacement(cfg, "test-namespace", state, []string{"new"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{{ShardID: "new", ExecutorID: "exec-a"}}, placements)
+	assert.Contains(t, state.Executors, "standby", "the state is not modified")
+
+	// Standbys are used when nothing else can own shards.
+	state.Executors["exec-a"] = store.HeartbeatState{Status: types.ExecutorStatusDRAINING}
+	placements, _, err = PlanInitialPlacement(cfg, "test-namespace", state, []string{"new"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{{ShardID: "new", ExecutorID: "standby"}}, placements)
+}
+
+func TestPlanInitialPlacement_PinnedShards(t *testing.T) {
+	cfg := &config.Config{
+		LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+		PinnedShards: func(string) map[string]interface{} {
+			return map[string]interface{}{"pinned": "exec-b", "pinned-to-draining": "exec-c", "pinned-to-gone": "exec-d"}
+		},
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a": {Status: types.ExecutorStatusACTIVE},
+			"exec-b": {Status: types.ExecutorStatusACTIVE},
+			"exec-c": {Status: types.ExecutorStatusDRAINING},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-b": {AssignedShards: map[string]*types.ShardAssignment{"b": {}}},
+		},
+	}
+
+	placements, unplaced, err := PlanInitialPlacement(cfg, "test-namespace", state, []string{"new", "pinned", "pinned-to-draining", "pinned-to-gone"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{
+		{ShardID: "pinned", ExecutorID: "exec-b"},
+		{ShardID: "new", ExecutorID: "exec-a"},
+	}, placements, "the pinned shard goes to its executor even though exec-a is less loaded")
+	assert.Equal(t, map[string]plan.UnassignedReason{
+		"pinned-to-draining": plan.UnassignedReasonPinned,
+		"pinned-to-gone":     plan.UnassignedReasonPinned,
+	}, unplaced)
+}
+
+func TestPlanInitialPlacement_AtCapacity(t *testing.T) {
+	cfg := &config.Config{
+		LoadBalancingMode:    func(string) string { return config.LoadBalancingModeGREEDY },
+		ExecutorLoadCapacity: func(string) float64 { return 10 },
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a": {Status: types.ExecutorStatusACTIVE},
+			"exec-b": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLoadKey: "10"}},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-a": {AssignedShards: map[string]*types.ShardAssignment{"a": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"a": {SmoothedLoad: 4},
+		},
+	}
+
+	placements, unplaced, err := PlanInitialPlacement(cfg, "test-namespace", state, []string{"new"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{{ShardID: "new", ExecutorID: "exec-a"}}, placements, "exec-b reports the capacity as its load")
+	assert.Empty(t, unplaced)
+
+	state.ShardStats["a"] = store.ShardStatistics{SmoothedLoad: 12}
+	placements, unplaced, err = PlanInitialPlacement(cfg, "test-namespace", state, []string{"new"}, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, placements)
+	assert.Equal(t, map[string]plan.UnassignedReason{"new": plan.UnassignedReasonAtCapacity}, unplaced)
+}
+
+func TestWithoutMovesToStandbys(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a":  {Status: types.ExecutorStatusACTIVE},
+			"standby": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataRoleKey: types.ExecutorRoleSTANDBY.String()}},
+		},
+	}
+	moves := []plan.Move{
+		{ShardID: "1", From: "exec-b", To: "standby"},
+		{ShardID: "2", From: "standby", To: "exec-a"},
+	}
+
+	assert.Equal(t, []plan.Move{{ShardID: "2", From: "standby", To: "exec-a"}}, withoutMovesToStandbys(moves, state))
+}
+
+func TestPinnedShards(t *testing.T) {
+	now := time.Date(2025, 11, 18, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		PinnedShards: func(namespace string) map[string]interface{} {
+			return map[string]interface{}{"1": "exec-config"}
+		},
+	}
+	state := &store.NamespaceState{
+		ShardStats: map[string]store.ShardStatistics{
+			"1": {PinnedExecutor: "exec-a", PinExpiresAt: now.Add(time.Hour)},
+			"2": {PinnedExecutor: "exec-b", PinExpiresAt: now.Add(time.Hour)},
+			"3": {PinnedExecutor: "exec-c", PinExpiresAt: now.Add(-time.Second)},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"1": "exec-config", "2": "exec-b"}, PinnedShards(cfg, "test-namespace", state, now))
+	assert.Equal(t, map[string]string{"1": "exec-config"}, PinnedShards(cfg, "test-namespace", state, now.Add(time.Hour)),
+		"expired pins release the shards")
+	assert.Equal(t, map[string]string{"1": "exec-config"}, PinnedShards(cfg, "test-namespace", nil, now))
+}
+
 func TestPlanRebalance(t *testing.T) {
 	cfg := &config.Config{
 		LoadBalancingMode: func(namespace string) string {
 			return config.LoadBalancingModeINVALID
 		},
 	}
-	moves, err := PlanRebalance(cfg, "test-namespace", &store.NamespaceState{}, nil, time.Time{}, nil, metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, "test-namespace", &store.NamespaceState{}, nil, 0, nil, time.Time{}, nil, metrics.NoopScope)
 	require.Error(t, err)
 	assert.Nil(t, moves)
 	assert.ErrorContains(t, err, "unsupported load balancing mode")
 }
+
+func TestPlanRebalance_MaxMoves(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors:        make(map[string]store.HeartbeatState),
+		ShardAssignments: make(map[string]store.AssignedState),
+		ShardStats:       make(map[string]store.ShardStatistics),
+	}
+	currentAssignments := make(map[string][]string)
+	// One hot executor with shards of increasing load and three idle executors.
+	for e := 0; e < 4; e++ {
+		executorID := fmt.Sprintf("exec-%d", e)
+		state.Executors[executorID] = store.HeartbeatState{Status: types.ExecutorStatusACTIVE}
+		currentAssignments[executorID] = []string{}
+	}
+	for s := 0; s < 8; s++ {
+		shardID := fmt.Sprintf("shard-%d", s)
+		state.ShardStats[shardID] = store.ShardStatistics{SmoothedLoad: float64(s + 1)}
+		currentAssignments["exec-0"] = append(currentAssignments["exec-0"], shardID)
+	}
+	currentAssignments["exec-1"] = []string{"idle-1"}
+	currentAssignments["exec-2"] = []string{"idle-2"}
+	currentAssignments["exec-3"] = []string{"idle-3"}
+	for _, shardID := range []string{"idle-1", "idle-2", "idle-3"} {
+		state.ShardStats[shardID] = store.ShardStatistics{SmoothedLoad: 0.1}
+	}
+	cfg := &config.Config{
+		LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+		LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+			PerShardCooldown:     func(string) time.Duration { return 0 },
+			MoveBudgetProportion: func(string) float64 { return 1 },
+			HysteresisUpperBand:  func(string) float64 { return 1.15 },
+			HysteresisLowerBand:  func(string) float64 { return 0.9 },
+			SevereImbalanceRatio: func(string) float64 { return 1.3 },
+		},
+	}
+
+	uncapped, err := PlanRebalance(cfg, "test-namespace", state, currentAssignments, 0, nil, time.Now(), testlogger.New(t), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Greater(t, len(uncapped), 2)
+
+	capped, err := PlanRebalance(cfg, "test-namespace", state, currentAssignments, 2, nil, time.Now(), testlogger.New(t), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Equal(t, uncapped[:2], capped, "the cap keeps the moves planned first, which have the highest benefit")
+	assert.Equal(t, []string{"shard-7", "shard-6"}, []string{capped[0].ShardID, capped[1].ShardID})
+}
+
+// TestPlansAreDeterministic runs the planners repeatedly over the same input, with executors
+// tied on load, and checks that map iteration order never changes the resulting plan.
+func TestPlansAreDeterministic(t *testing.T) {
+	const runs = 50
+
+	state := &store.NamespaceState{
+		Executors:        make(map[string]store.HeartbeatState),
+		ShardAssignments: make(map[string]store.AssignedState),
+		ShardStats:       make(map[string]store.ShardStatistics),
+	}
+	currentAssignments := make(map[string][]string)
+	for e := 0; e < 8; e++ {
+		executorID := fmt.Sprintf("exec-%d", e)
+		reported := make(map[string]*types.ShardStatusReport)
+		assigned := make(map[string]*types.ShardAssignment)
+		currentAssignments[executorID] = []string{}
+		// Half of the executors are hot and the other half cold, with identical loads within each half.
+		numShards := 1
+		if e < 4 {
+			numShards = 5
+		}
+		for s := 0; s < numShards; s++ {
+			shardID := fmt.Sprintf("shard-%d-%d", e, s)
+			reported[shardID] = &types.ShardStatusReport{Status: types.ShardStatusREADY, ShardLoad: 1}
+			assigned[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+			state.ShardStats[shardID] = store.ShardStatistics{SmoothedLoad: 1}
+			currentAssignments[executorID] = append(currentAssignments[executorID], shardID)
+		}
+		state.Executors[executorID] = store.HeartbeatState{Status: types.ExecutorStatusACTIVE, ReportedShards: reported}
+		state.ShardAssignments[executorID] = store.AssignedState{AssignedShards: assigned}
+	}
+	newShards := []string{"new-1", "new-2", "new-3", "new-4", "new-5"}
+
+	for _, mode := range []string{config.LoadBalancingModeNAIVE, config.LoadBalancingModeGREEDY} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := &config.Config{
+				LoadBalancingMode: func(string) string { return mode },
+				LoadBalancingNaive: config.LoadBalancingNaiveConfig{
+					MaxDeviation: func(string) float64 { return 1.1 },
+				},
+				LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+					PerShardCooldown:     func(string) time.Duration { return 0 },
+					MoveBudgetProportion: func(string) float64 { return 0.5 },
+					HysteresisUpperBand:  func(string) float64 { return 1.15 },
+					HysteresisLowerBand:  func(string) float64 { return 0.9 },
+					SevereImbalanceRatio: func(string) float64 { return 1.3 },
+				},
+			}
+
+			planOnce := func() []byte {
+				placements, _, err := PlanInitialPlacement(cfg, "test-namespace", state, newShards, time.Now())
+				require.NoError(t, err)
+				moves, err := PlanRebalance(cfg, "test-namespace", state, currentAssignments, 0, nil, time.Now(), testlogger.New(t), metrics.NoopScope)
+				require.NoError(t, err)
+				require.NotEmpty(t, moves)
+
+				encoded, err := json.Marshal(struct {
+					Placements []plan.Placement
+					Moves      []plan.Move
+				}{placements, moves})
+				require.NoError(t, err)
+				return encoded
+			}
+
+			expected := planOnce()
+			for i := 0; i < runs; i++ {
+				require.Equal(t, string(expected), string(planOnce()), "run %d", i)
+			}
+		})
+	}
+}
+
+func TestPlanExecutorRemoval(t *testing.T) {
+	now := time.Now()
+	newState := func() *store.NamespaceState {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"exec-0": {Status: types.ExecutorStatusACTIVE},
+				"exec-1": {Status: types.ExecutorStatusACTIVE},
+				"exec-2": {Status: types.ExecutorStatusACTIVE},
+				"exec-3": {Status: types.ExecutorStatusDRAINING},
+			},
+			ShardAssignments: make(map[string]store.AssignedState),
+			ShardStats:       make(map[string]store.ShardStatistics),
+		}
+		for e, loads := range map[string][]float64{
+			"exec-0": {4, 3, 2, 1},
+			"exec-1": {1},
+			"exec-2": {2},
+		} {
+			assigned := make(map[string]*types.ShardAssignment)
+			for i, load := range loads {
+				shardID := fmt.Sprintf("%s-shard-%d", e, i)
+				assigned[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+				// Every shard has just moved; the removal plan must not be held back by cooldown.
+				state.ShardStats[shardID] = store.ShardStatistics{SmoothedLoad: load, LastMoveTime: now}
+			}
+			state.ShardAssignments[e] = store.AssignedState{AssignedShards: assigned}
+		}
+		return state
+	}
+
+	for _, mode := range []string{config.LoadBalancingModeNAIVE, config.LoadBalancingModeGREEDY} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := &config.Config{
+				LoadBalancingMode: func(string) string { return mode },
+			}
+			state := newState()
+
+			moves, err := PlanExecutorRemoval(cfg, "test-namespace", state, "exec-0", time.Now())
+			require.NoError(t, err)
+
+			var movedShards []string
+			targets := make(map[string]int)
+			for _, move := range moves {
+				assert.Equal(t, "exec-0", move.From)
+				assert.NotEqual(t, "exec-0", move.To)
+				assert.NotEqual(t, "exec-3", move.To, "draining executors must not receive shards")
+				movedShards = append(movedShards, move.ShardID)
+				targets[move.To]++
+			}
+			assert.ElementsMatch(t, []string{"exec-0-shard-0", "exec-0-shard-1", "exec-0-shard-2", "exec-0-shard-3"}, movedShards)
+			assert.Len(t, targets, 2, "shards should be spread over the remaining executors")
+
+			// The plan is a preview only.
+			assert.Equal(t, newState(), state)
+		})
+	}
+}
+
+func TestPlanExecutorRemoval_Errors(t *testing.T) {
+	cfg := &config.Config{
+		LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-0": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-0": {AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}}},
+		},
+	}
+
+	_, err := PlanExecutorRemoval(cfg, "test-namespace", state, "unknown", time.Now())
+	assert.ErrorIs(t, err, store.ErrExecutorNotFound)
+
+	_, err = PlanExecutorRemoval(cfg, "test-namespace", nil, "exec-0", time.Now())
+	assert.ErrorIs(t, err, store.ErrExecutorNotFound)
+
+	_, err = PlanExecutorRemoval(cfg, "test-namespace", state, "exec-0", time.Now())
+	assert.ErrorIs(t, err, plan.ErrNoActiveExecutors)
+}
+
+func TestKeepMinActiveExecutors(t *testing.T) {
+	currentAssignments := map[string][]string{
+		"exec-1": {"a"},
+		"exec-2": {"b"},
+		"exec-3": {"c", "d"},
+		"exec-4": {},
+	}
+
+	tests := []struct {
+		name               string
+		minActiveExecutors int
+		moves              []plan.Move
+		expected           []plan.Move
+	}{
+		{
+			name:               "disabled keeps every move",
+			minActiveExecutors: 0,
+			moves: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-3"},
+				{ShardID: "b", From: "exec-2", To: "exec-3"},
+			},
+			expected: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-3"},
+				{ShardID: "b", From: "exec-2", To: "exec-3"},
+			},
+		},
+		{
+			name:               "stops emptying executors at the minimum",
+			minActiveExecutors: 2,
+			moves: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-3"},
+				{ShardID: "b", From: "exec-2", To: "exec-3"},
+			},
+			expected: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-3"},
+			},
+		},
+		{
+			name:               "moving the last shard onto an empty executor is allowed",
+			minActiveExecutors: 3,
+			moves: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-4"},
+			},
+			expected: []plan.Move{
+				{ShardID: "a", From: "exec-1", To: "exec-4"},
+			},
+		},
+		{
+			name:               "moves that do not empty an executor are kept",
+			minActiveExecutors: 3,
+			moves: []plan.Move{
+				{ShardID: "c", From: "exec-3", To: "exec-1"},
+				{ShardID: "a", From: "exec-1", To: "exec-2"},
+			},
+			expected: []plan.Move{
+				{ShardID: "c", From: "exec-3", To: "exec-1"},
+				{ShardID: "a", From: "exec-1", To: "exec-2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, keepMinActiveExecutors(tt.moves, currentAssignments, tt.minActiveExecutors))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package plan
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// ExecutorAssignment is the shards owned by one executor in an AssignmentSnapshot.
+type ExecutorAssignment struct {
+	ExecutorID string   `json:"executorID"`
+	ShardIDs   []string `json:"shardIDs"`
+}
+
+// AssignmentSnapshot is a canonical form of an executor to shards assignment. Executors and shards are
+// sorted and executors without shards are left out, so equal assignments have equal snapshots. It is
+// meant for diffing the output of different strategies and for storing baselines.
+type AssignmentSnapshot struct {
+	Executors []ExecutorAssignment `json:"executors"`
+}
+
+// NewAssignmentSnapshot returns the canonical form of assignments. The input is not modified.
+func NewAssignmentSnapshot(assignments map[string][]string) AssignmentSnapshot {
+	snapshot := AssignmentSnapshot{Executors: make([]ExecutorAssignment, 0, len(assignments))}
+	for _, executorID := range SortedExecutorIDs(assignments) {
+		if len(assignments[executorID]) == 0 {
+			continue
+		}
+		shardIDs := slices.Clone(assignments[executorID])
+		slices.Sort(shardIDs)
+		snapshot.Executors = append(snapshot.Executors, ExecutorAssignment{
+			ExecutorID: executorID,
+			ShardIDs:   shardIDs,
+		})
+	}
+	return snapshot
+}
+
+// MarshalAssignments serializes assignments to JSON in canonical form. Equal assignments always
+// serialize to the same bytes, regardless of map iteration order or the order of shards.
+func MarshalAssignments(assignments map[string][]string) ([]byte, error) {
+	return json.Marshal(NewAssignmentSnapshot(assignments))
+}
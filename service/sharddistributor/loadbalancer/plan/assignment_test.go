@@ -0,0 +1,52 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAssignmentSnapshot(t *testing.T) {
+	assignments := map[string][]string{
+		"exec-b": {"3", "1"},
+		"exec-a": {"2"},
+		"exec-c": {},
+	}
+
+	snapshot := NewAssignmentSnapshot(assignments)
+
+	assert.Equal(t, AssignmentSnapshot{Executors: []ExecutorAssignment{
+		{ExecutorID: "exec-a", ShardIDs: []string{"2"}},
+		{ExecutorID: "exec-b", ShardIDs: []string{"1", "3"}},
+	}}, snapshot)
+	assert.Equal(t, []string{"3", "1"}, assignments["exec-b"], "input is not modified")
+}
+
+func TestMarshalAssignments(t *testing.T) {
+	first := map[string][]string{
+		"exec-a": {"1", "2", "3"},
+		"exec-b": {"4", "5"},
+	}
+	second := map[string][]string{
+		"exec-b": {"5", "4"},
+		"exec-a": {"3", "1", "2"},
+		"exec-c": nil,
+	}
+
+	firstBytes, err := MarshalAssignments(first)
+	require.NoError(t, err)
+	secondBytes, err := MarshalAssignments(second)
+	require.NoError(t, err)
+
+	assert.Equal(t, firstBytes, secondBytes)
+	assert.JSONEq(t, `{"executors":[{"executorID":"exec-a","shardIDs":["1","2","3"]},{"executorID":"exec-b","shardIDs":["4","5"]}]}`, string(firstBytes))
+
+	different, err := MarshalAssignments(map[string][]string{"exec-a": {"1", "2"}, "exec-b": {"3", "4", "5"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstBytes, different)
+
+	empty, err := MarshalAssignments(nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"executors":[]}`, string(empty))
+}
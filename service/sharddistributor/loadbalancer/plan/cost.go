@@ -0,0 +1,73 @@
+package plan
+
+// moveBaseCost is the cost of moving a shard regardless of its load, so moving a shard
+// without load is not free: it still causes a handover.
+const moveBaseCost = 1
+
+// MoveCost returns the cost of applying moves. Each move costs a fixed amount plus the load
+// of the moved shard, so plans that move heavy shards cost more than plans that move light ones.
+func MoveCost(moves []Move, shardLoads map[string]float64) float64 {
+	cost := 0.0
+	for _, move := range moves {
+		cost += moveBaseCost + max(shardLoads[move.ShardID], 0)
+	}
+	return cost
+}
+
+// Imbalance returns the max over mean executor load after applying moves to currentAssignments.
+// Every executor in currentAssignments counts towards the mean, including executors without shards.
+// It returns 0 when there is no load.
+func Imbalance(currentAssignments map[string][]string, moves []Move, shardLoads map[string]float64) float64 {
+	executorLoads := make(map[string]float64, len(currentAssignments))
+	for executorID, shardIDs := range currentAssignments {
+		executorLoads[executorID] = 0
+		for _, shardID := range shardIDs {
+			executorLoads[executorID] += shardLoads[shardID]
+		}
+	}
+	for _, move := range moves {
+		executorLoads[move.From] -= shardLoads[move.ShardID]
+		executorLoads[move.To] += shardLoads[move.ShardID]
+	}
+
+	total, maxLoad := 0.0, 0.0
+	for _, load := range executorLoads {
+		total += load
+		maxLoad = max(maxLoad, load)
+	}
+	return SafeDivide(maxLoad, SafeDivide(total, float64(len(executorLoads)), 0), 0)
+}
+
+// SelectCheapestPlan returns the index of the candidate plan with the lowest MoveCost among the
+// candidates whose Imbalance is within tolerance of the best one. Ties go to the earlier candidate.
+// It returns false when there are no candidates.
+func SelectCheapestPlan(
+	candidates [][]Move,
+	currentAssignments map[string][]string,
+	shardLoads map[string]float64,
+	tolerance float64,
+) (int, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	imbalances := make([]float64, len(candidates))
+	bestImbalance := 0.0
+	for i, moves := range candidates {
+		imbalances[i] = Imbalance(currentAssignments, moves, shardLoads)
+		if i == 0 || imbalances[i] < bestImbalance {
+			bestImbalance = imbalances[i]
+		}
+	}
+
+	selected, selectedCost := -1, 0.0
+	for i, moves := range candidates {
+		if imbalances[i] > bestImbalance+max(tolerance, 0) {
+			continue
+		}
+		if cost := MoveCost(moves, shardLoads); selected == -1 || cost < selectedCost {
+			selected, selectedCost = i, cost
+		}
+	}
+	return selected, true
+}
@@ -0,0 +1,66 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveCost(t *testing.T) {
+	shardLoads := map[string]float64{"heavy": 4, "light": 1, "negative": -2}
+
+	assert.Zero(t, MoveCost(nil, shardLoads))
+	assert.Equal(t, 5.0, MoveCost([]Move{{ShardID: "heavy"}}, shardLoads))
+	assert.Equal(t, 2.0, MoveCost([]Move{{ShardID: "light"}}, shardLoads))
+	assert.Equal(t, 1.0, MoveCost([]Move{{ShardID: "no-stats"}}, shardLoads), "a shard without load still costs a move")
+	assert.Equal(t, 1.0, MoveCost([]Move{{ShardID: "negative"}}, shardLoads))
+}
+
+func TestImbalance(t *testing.T) {
+	currentAssignments := map[string][]string{
+		"exec-a": {"s1", "s2"},
+		"exec-b": {},
+	}
+	shardLoads := map[string]float64{"s1": 3, "s2": 1}
+
+	assert.Equal(t, 2.0, Imbalance(currentAssignments, nil, shardLoads))
+	assert.Equal(t, 1.5, Imbalance(currentAssignments, []Move{{ShardID: "s2", From: "exec-a", To: "exec-b"}}, shardLoads))
+	assert.Zero(t, Imbalance(currentAssignments, nil, nil), "no load")
+}
+
+func TestSelectCheapestPlan(t *testing.T) {
+	currentAssignments := map[string][]string{
+		"exec-a": {"heavy", "l1", "l2", "l3", "l4"},
+		"exec-b": {},
+	}
+	shardLoads := map[string]float64{"heavy": 4, "l1": 1, "l2": 1, "l3": 1, "l4": 1}
+
+	moveLightShards := []Move{
+		{ShardID: "l1", From: "exec-a", To: "exec-b"},
+		{ShardID: "l2", From: "exec-a", To: "exec-b"},
+		{ShardID: "l3", From: "exec-a", To: "exec-b"},
+		{ShardID: "l4", From: "exec-a", To: "exec-b"},
+	}
+	moveHeavyShard := []Move{{ShardID: "heavy", From: "exec-a", To: "exec-b"}}
+	moveOneLightShard := []Move{{ShardID: "l1", From: "exec-a", To: "exec-b"}}
+	candidates := [][]Move{moveLightShards, moveHeavyShard, moveOneLightShard}
+
+	// Both balancing plans end at 4/4, moving the heavy shard costs 5 and moving the light shards 8.
+	// Moving a single light shard is the cheapest but leaves the namespace imbalanced.
+	selected, ok := SelectCheapestPlan(candidates, currentAssignments, shardLoads, 0.1)
+	assert.True(t, ok)
+	assert.Equal(t, 1, selected)
+
+	// With a tolerance wide enough to accept the worse balance, the cheapest plan wins.
+	selected, ok = SelectCheapestPlan(candidates, currentAssignments, shardLoads, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, selected)
+
+	// Equal cost plans resolve to the earlier candidate.
+	selected, ok = SelectCheapestPlan([][]Move{moveHeavyShard, moveHeavyShard}, currentAssignments, shardLoads, 0)
+	assert.True(t, ok)
+	assert.Equal(t, 0, selected)
+
+	_, ok = SelectCheapestPlan(nil, currentAssignments, shardLoads, 0)
+	assert.False(t, ok)
+}
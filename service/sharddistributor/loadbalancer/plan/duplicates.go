@@ -0,0 +1,77 @@
+package plan
+
+import (
+	"maps"
+	"slices"
+)
+
+// RepairDuplicateAssignments resolves shards that are assigned to more than one executor, which can be
+// left behind by a partially applied write or a bug in an earlier leader. Of the owners of a duplicated
+// shard it keeps the one for which isHealthy reports true, then the one with the lowest load in
+// executorLoads, then the one with the lowest ID, so every leader repairs the same way. isHealthy may be nil.
+//
+// It returns the repaired assignments and, per dropped owner, a move From that owner To the kept one.
+// The input is not modified.
+func RepairDuplicateAssignments(
+	assignments map[string][]string,
+	isHealthy func(executorID, shardID string) bool,
+	executorLoads map[string]float64,
+) (map[string][]string, []Move) {
+	owners := make(map[string][]string)
+	for _, executorID := range SortedExecutorIDs(assignments) {
+		for _, shardID := range slices.Sorted(slices.Values(assignments[executorID])) {
+			owners[shardID] = append(owners[shardID], executorID)
+		}
+	}
+
+	dropped := make(map[string]map[string]struct{})
+	var moves []Move
+	for _, shardID := range slices.Sorted(maps.Keys(owners)) {
+		candidates := slices.Compact(owners[shardID])
+		if len(candidates) < 2 {
+			continue
+		}
+		slices.SortStableFunc(candidates, func(a, b string) int {
+			if healthyA, healthyB := isHealthy != nil && isHealthy(a, shardID), isHealthy != nil && isHealthy(b, shardID); healthyA != healthyB {
+				if healthyA {
+					return -1
+				}
+				return 1
+			}
+			if executorLoads[a] < executorLoads[b] {
+				return -1
+			}
+			if executorLoads[a] > executorLoads[b] {
+				return 1
+			}
+			return 0
+		})
+		keep := candidates[0]
+		for _, executorID := range candidates[1:] {
+			if dropped[executorID] == nil {
+				dropped[executorID] = make(map[string]struct{})
+			}
+			dropped[executorID][shardID] = struct{}{}
+			moves = append(moves, Move{ShardID: shardID, From: executorID, To: keep})
+		}
+	}
+
+	repaired := make(map[string][]string, len(assignments))
+	for executorID, shardIDs := range assignments {
+		kept := make([]string, 0, len(shardIDs))
+		seen := make(map[string]struct{}, len(shardIDs))
+		for _, shardID := range shardIDs {
+			if _, ok := dropped[executorID][shardID]; ok {
+				continue
+			}
+			if _, ok := seen[shardID]; ok {
+				// The shard is listed twice for the same executor, which is a duplicate as well.
+				continue
+			}
+			seen[shardID] = struct{}{}
+			kept = append(kept, shardID)
+		}
+		repaired[executorID] = kept
+	}
+	return repaired, moves
+}
@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairDuplicateAssignments(t *testing.T) {
+	tests := []struct {
+		name             string
+		assignments      map[string][]string
+		healthy          map[string]string
+		executorLoads    map[string]float64
+		expectedRepaired map[string][]string
+		expectedMoves    []Move
+	}{
+		{
+			name:             "No duplicates",
+			assignments:      map[string][]string{"exec-a": {"0", "2"}, "exec-b": {"1"}},
+			expectedRepaired: map[string][]string{"exec-a": {"0", "2"}, "exec-b": {"1"}},
+		},
+		{
+			name:             "Healthy owner is kept",
+			assignments:      map[string][]string{"exec-a": {"0", "1"}, "exec-b": {"1"}},
+			healthy:          map[string]string{"1": "exec-b"},
+			executorLoads:    map[string]float64{"exec-a": 1, "exec-b": 5},
+			expectedRepaired: map[string][]string{"exec-a": {"0"}, "exec-b": {"1"}},
+			expectedMoves:    []Move{{ShardID: "1", From: "exec-a", To: "exec-b"}},
+		},
+		{
+			name:             "Least loaded owner is kept",
+			assignments:      map[string][]string{"exec-a": {"0", "1"}, "exec-b": {"1"}, "exec-c": {"1"}},
+			executorLoads:    map[string]float64{"exec-a": 5, "exec-b": 3, "exec-c": 1},
+			expectedRepaired: map[string][]string{"exec-a": {"0"}, "exec-b": {}, "exec-c": {"1"}},
+			expectedMoves: []Move{
+				{ShardID: "1", From: "exec-b", To: "exec-c"},
+				{ShardID: "1", From: "exec-a", To: "exec-c"},
+			},
+		},
+		{
+			name:             "Ties keep the lowest executor ID",
+			assignments:      map[string][]string{"exec-b": {"1"}, "exec-a": {"1"}},
+			expectedRepaired: map[string][]string{"exec-a": {"1"}, "exec-b": {}},
+			expectedMoves:    []Move{{ShardID: "1", From: "exec-b", To: "exec-a"}},
+		},
+		{
+			name:             "Shard listed twice on one executor",
+			assignments:      map[string][]string{"exec-a": {"1", "1"}},
+			expectedRepaired: map[string][]string{"exec-a": {"1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isHealthy := func(executorID, shardID string) bool {
+				return tt.healthy[shardID] == executorID
+			}
+
+			repaired, moves := RepairDuplicateAssignments(tt.assignments, isHealthy, tt.executorLoads)
+
+			assert.Equal(t, tt.expectedRepaired, repaired)
+			assert.Equal(t, tt.expectedMoves, moves)
+			require.NoError(t, ValidateAssignment(repaired, nil, nil))
+		})
+	}
+}
+
+func TestRepairDuplicateAssignments_DoesNotModifyInput(t *testing.T) {
+	assignments := map[string][]string{"exec-a": {"1"}, "exec-b": {"1"}}
+
+	_, _ = RepairDuplicateAssignments(assignments, nil, nil)
+
+	assert.Equal(t, map[string][]string{"exec-a": {"1"}, "exec-b": {"1"}}, assignments)
+}
@@ -0,0 +1,57 @@
+package plan
+
+import (
+	"maps"
+	"slices"
+)
+
+// FlapDetector keeps a bounded history of assignment snapshots, one per assignment cycle, and finds
+// shards that keep changing owner within it. The leader can pin such shards for a while instead of
+// moving them again. It is not safe for concurrent use.
+type FlapDetector struct {
+	capacity  int
+	snapshots []AssignmentSnapshot
+}
+
+// NewFlapDetector returns a detector that keeps the last capacity snapshots.
+func NewFlapDetector(capacity int) *FlapDetector {
+	return &FlapDetector{capacity: max(capacity, 0)}
+}
+
+// Record adds the assignment of a cycle to the history, evicting the oldest snapshot when full.
+// The input is not modified.
+func (d *FlapDetector) Record(assignments map[string][]string) {
+	if d.capacity == 0 {
+		return
+	}
+	if len(d.snapshots) == d.capacity {
+		d.snapshots = slices.Delete(d.snapshots, 0, 1)
+	}
+	d.snapshots = append(d.snapshots, NewAssignmentSnapshot(assignments))
+}
+
+// FlappingShards returns the sorted IDs of shards whose owner changed more than maxOwnerChanges
+// times across the recorded snapshots. Only changes between two owners count: a shard that is
+// missing from a snapshot, e.g. while it is unassigned, does not add a change.
+func (d *FlapDetector) FlappingShards(maxOwnerChanges int) []string {
+	lastOwners := make(map[string]string)
+	ownerChanges := make(map[string]int)
+	for _, snapshot := range d.snapshots {
+		for _, executor := range snapshot.Executors {
+			for _, shardID := range executor.ShardIDs {
+				if previous, ok := lastOwners[shardID]; ok && previous != executor.ExecutorID {
+					ownerChanges[shardID]++
+				}
+				lastOwners[shardID] = executor.ExecutorID
+			}
+		}
+	}
+
+	var flapping []string
+	for _, shardID := range slices.Sorted(maps.Keys(ownerChanges)) {
+		if ownerChanges[shardID] > maxOwnerChanges {
+			flapping = append(flapping, shardID)
+		}
+	}
+	return flapping
+}
@@ -0,0 +1,52 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlapDetector(t *testing.T) {
+	t.Run("flags a shard moving back and forth but not a stable one", func(t *testing.T) {
+		detector := NewFlapDetector(5)
+		for cycle := 0; cycle < 5; cycle++ {
+			if cycle%2 == 0 {
+				detector.Record(map[string][]string{"exec-a": {"stable", "flapping"}, "exec-b": {}})
+			} else {
+				detector.Record(map[string][]string{"exec-a": {"stable"}, "exec-b": {"flapping"}})
+			}
+		}
+
+		assert.Equal(t, []string{"flapping"}, detector.FlappingShards(2))
+		assert.Empty(t, detector.FlappingShards(4))
+	})
+
+	t.Run("only the bounded history is considered", func(t *testing.T) {
+		detector := NewFlapDetector(3)
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		detector.Record(map[string][]string{"exec-b": {"1"}})
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		assert.Equal(t, []string{"1"}, detector.FlappingShards(1))
+
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		assert.Empty(t, detector.FlappingShards(0))
+	})
+
+	t.Run("unassigned cycles do not count as owner changes", func(t *testing.T) {
+		detector := NewFlapDetector(4)
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		detector.Record(map[string][]string{"exec-a": {}})
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+
+		assert.Empty(t, detector.FlappingShards(0))
+	})
+
+	t.Run("zero capacity keeps no history", func(t *testing.T) {
+		detector := NewFlapDetector(0)
+		detector.Record(map[string][]string{"exec-a": {"1"}})
+		detector.Record(map[string][]string{"exec-b": {"1"}})
+
+		assert.Empty(t, detector.FlappingShards(0))
+	})
+}
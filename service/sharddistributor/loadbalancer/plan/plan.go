@@ -1,6 +1,13 @@
 package plan
 
-import "errors"
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
 
 var ErrNoActiveExecutors = errors.New("no active executors available")
 
@@ -14,3 +21,83 @@ type Move struct {
 	From    string
 	To      string
 }
+
+// SortedExecutorIDs returns the keys of an executor-keyed map in ascending order.
+// Planning iterates executors through it so that ties resolve the same way on every run.
+func SortedExecutorIDs[V any](executors map[string]V) []string {
+	return slices.Sorted(maps.Keys(executors))
+}
+
+// SortExecutorsByLoad returns the keys of loads ordered by load, ascending or descending.
+// Executors with the same load are in ascending ID order, so the order is the same on every run.
+func SortExecutorsByLoad(loads map[string]float64, ascending bool) []string {
+	executorIDs := SortedExecutorIDs(loads)
+	slices.SortStableFunc(executorIDs, func(a, b string) int {
+		if ascending {
+			return cmp.Compare(loads[a], loads[b])
+		}
+		return cmp.Compare(loads[b], loads[a])
+	})
+	return executorIDs
+}
+
+// CooldownCheck reports whether a shard that last moved at lastMoveTime is still in its cooldown at now.
+type CooldownCheck func(lastMoveTime, now time.Time, cooldown time.Duration) bool
+
+// InCooldown is the default CooldownCheck. lastMoveTime may have been written by a node with a skewed clock,
+// so a lastMoveTime in the future is treated as a move that just happened. A lastMoveTime more than one
+// cooldown in the future cannot be trusted and is treated as eligible, so the shard is not pinned until the
+// clocks catch up. A lastMoveTime far in the past is always eligible.
+func InCooldown(lastMoveTime, now time.Time, cooldown time.Duration) bool {
+	if cooldown <= 0 || lastMoveTime.IsZero() {
+		return false
+	}
+
+	elapsed := now.Sub(lastMoveTime)
+	if elapsed < 0 {
+		if -elapsed > cooldown {
+			return false
+		}
+		elapsed = 0
+	}
+	return elapsed < cooldown
+}
+
+// MoveEligibility scores how willing the balancer is to move a shard that last moved at lastMoveTime,
+// from 0 right after the move to 1 once agingWindow has passed, growing linearly in between. Without an
+// aging window or a recorded move the shard is fully eligible. A skewed lastMoveTime in the future is
+// treated like InCooldown treats it.
+func MoveEligibility(lastMoveTime, now time.Time, agingWindow time.Duration) float64 {
+	if agingWindow <= 0 || lastMoveTime.IsZero() {
+		return 1
+	}
+
+	elapsed := now.Sub(lastMoveTime)
+	if elapsed < 0 {
+		if -elapsed > agingWindow {
+			return 1
+		}
+		elapsed = 0
+	}
+	return min(float64(elapsed)/float64(agingWindow), 1)
+}
+
+// MatchesSelector reports whether labels hold every key of selector with the same value.
+// An empty selector matches any labels.
+func MatchesSelector(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SafeDivide returns num / den, or fallback when den is zero or not finite.
+// Load ratios use it because executor counts and total loads can be zero.
+func SafeDivide(num, den, fallback float64) float64 {
+	if den == 0 || math.IsNaN(den) || math.IsInf(den, 0) {
+		return fallback
+	}
+	return num / den
+}
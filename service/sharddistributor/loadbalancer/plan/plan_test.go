@@ -0,0 +1,148 @@
+package plan
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInCooldown(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := time.Minute
+
+	tests := []struct {
+		name         string
+		lastMoveTime time.Time
+		cooldown     time.Duration
+		expected     bool
+	}{
+		{
+			name:         "never moved",
+			lastMoveTime: time.Time{},
+			cooldown:     cooldown,
+			expected:     false,
+		},
+		{
+			name:         "cooldown disabled",
+			lastMoveTime: now,
+			cooldown:     0,
+			expected:     false,
+		},
+		{
+			name:         "moved within cooldown",
+			lastMoveTime: now.Add(-30 * time.Second),
+			cooldown:     cooldown,
+			expected:     true,
+		},
+		{
+			name:         "moved before cooldown",
+			lastMoveTime: now.Add(-2 * time.Minute),
+			cooldown:     cooldown,
+			expected:     false,
+		},
+		{
+			name:         "slightly future move time is treated as just moved",
+			lastMoveTime: now.Add(30 * time.Second),
+			cooldown:     cooldown,
+			expected:     true,
+		},
+		{
+			name:         "far future move time is treated as eligible",
+			lastMoveTime: now.Add(24 * time.Hour),
+			cooldown:     cooldown,
+			expected:     false,
+		},
+		{
+			name:         "far past move time is eligible",
+			lastMoveTime: time.Unix(0, 0),
+			cooldown:     cooldown,
+			expected:     false,
+		},
+		{
+			name:         "move time beyond the duration range is eligible",
+			lastMoveTime: time.Date(1, 1, 1, 0, 0, 1, 0, time.UTC),
+			cooldown:     cooldown,
+			expected:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InCooldown(tt.lastMoveTime, now, tt.cooldown))
+		})
+	}
+}
+
+func TestMoveEligibility(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Minute
+
+	t.Run("increases monotonically with time since the last move", func(t *testing.T) {
+		previous := -1.0
+		for elapsed := time.Duration(0); elapsed <= 2*window; elapsed += time.Minute {
+			score := MoveEligibility(now.Add(-elapsed), now, window)
+			assert.GreaterOrEqual(t, score, previous, "elapsed %v", elapsed)
+			assert.True(t, score >= 0 && score <= 1, "elapsed %v", elapsed)
+			previous = score
+		}
+		assert.Equal(t, 0.0, MoveEligibility(now, now, window))
+		assert.Equal(t, 0.5, MoveEligibility(now.Add(-window/2), now, window))
+		assert.Equal(t, 1.0, MoveEligibility(now.Add(-window), now, window))
+	})
+
+	t.Run("fully eligible without aging or a recorded move", func(t *testing.T) {
+		assert.Equal(t, 1.0, MoveEligibility(now, now, 0))
+		assert.Equal(t, 1.0, MoveEligibility(time.Time{}, now, window))
+	})
+
+	t.Run("skewed move times", func(t *testing.T) {
+		assert.Equal(t, 0.0, MoveEligibility(now.Add(time.Minute), now, window))
+		assert.Equal(t, 1.0, MoveEligibility(now.Add(24*time.Hour), now, window))
+	})
+}
+
+func TestMatchesSelector(t *testing.T) {
+	labels := map[string]string{"gpu": "true", "region": "us-east"}
+
+	assert.True(t, MatchesSelector(labels, nil))
+	assert.True(t, MatchesSelector(labels, map[string]string{"gpu": "true"}))
+	assert.True(t, MatchesSelector(labels, map[string]string{"gpu": "true", "region": "us-east"}))
+	assert.False(t, MatchesSelector(labels, map[string]string{"gpu": "false"}))
+	assert.False(t, MatchesSelector(labels, map[string]string{"tpu": "true"}))
+	assert.False(t, MatchesSelector(nil, map[string]string{"gpu": "true"}))
+}
+
+func TestSortedExecutorIDs(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, SortedExecutorIDs(map[string]int{"c": 1, "a": 2, "b": 3}))
+}
+
+func TestSortExecutorsByLoad(t *testing.T) {
+	loads := map[string]float64{"d": 2, "b": 1, "c": 3, "a": 2, "e": 1}
+
+	assert.Equal(t, []string{"b", "e", "a", "d", "c"}, SortExecutorsByLoad(loads, true))
+	assert.Equal(t, []string{"c", "a", "d", "b", "e"}, SortExecutorsByLoad(loads, false))
+	assert.Empty(t, SortExecutorsByLoad(nil, true))
+}
+
+func TestSafeDivide(t *testing.T) {
+	tests := []struct {
+		name     string
+		num      float64
+		den      float64
+		expected float64
+	}{
+		{name: "normal division", num: 6, den: 3, expected: 2},
+		{name: "negative denominator", num: 6, den: -3, expected: -2},
+		{name: "zero numerator", num: 0, den: 3, expected: 0},
+		{name: "zero denominator", num: 6, den: 0, expected: -1},
+		{name: "NaN denominator", num: 6, den: math.NaN(), expected: -1},
+		{name: "infinite denominator", num: 6, den: math.Inf(1), expected: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SafeDivide(tt.num, tt.den, -1))
+		})
+	}
+}
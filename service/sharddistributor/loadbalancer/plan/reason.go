@@ -0,0 +1,34 @@
+package plan
+
+// UnassignedReason tells why a shard is not assigned to any executor. Placement reports it for the shards
+// it leaves out, and the leader records it as the outcome of each rebalance pass.
+type UnassignedReason string
+
+const (
+	// UnassignedReasonRebalancePaused is recorded while rebalancing of a fixed namespace is paused.
+	UnassignedReasonRebalancePaused UnassignedReason = "rebalance-paused"
+	// UnassignedReasonNoExecutors is recorded while no executor has heartbeated for the namespace.
+	UnassignedReasonNoExecutors UnassignedReason = "no-executors"
+	// UnassignedReasonAllExecutorsDraining is recorded while every live executor is draining, e.g. during
+	// a full rolling restart.
+	UnassignedReasonAllExecutorsDraining UnassignedReason = "all-executors-draining"
+	// UnassignedReasonNoActiveExecutors is recorded while no live executor can own shards.
+	UnassignedReasonNoActiveExecutors UnassignedReason = "no-active-executors"
+	// UnassignedReasonNoMatchingExecutor is reported when no executor that can own shards matches the
+	// label selector of the shard.
+	UnassignedReasonNoMatchingExecutor UnassignedReason = "no-matching-executor"
+	// UnassignedReasonAtCapacity is reported when every executor that can own shards holds the executor
+	// load capacity.
+	UnassignedReasonAtCapacity UnassignedReason = "at-capacity"
+	// UnassignedReasonPinned is reported when the shard is pinned to an executor that cannot own it.
+	UnassignedReasonPinned UnassignedReason = "pinned"
+	// UnassignedReasonInCooldown is recorded while the leader waits out the rebalance cooldown of a fixed
+	// namespace before its next pass.
+	UnassignedReasonInCooldown UnassignedReason = "in-cooldown"
+	// UnassignedReasonShadowMode is recorded while a fixed namespace is not onboarded, so the leader
+	// plans assignments without writing them.
+	UnassignedReasonShadowMode UnassignedReason = "shadow-mode"
+	// UnassignedReasonPending is recorded when nothing holds the shard back: a fixed namespace assigns it
+	// in the next rebalance pass, an ephemeral namespace on the next lookup of its owner.
+	UnassignedReasonPending UnassignedReason = "pending"
+)
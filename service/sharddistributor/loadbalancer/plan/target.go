@@ -0,0 +1,47 @@
+package plan
+
+// ExecutorTarget is the ideal load of an executor and how far its current load is from it.
+type ExecutorTarget struct {
+	// TargetLoad is the share of the total load the executor would hold in a perfectly balanced namespace.
+	TargetLoad float64
+	// Load is the current load of the executor.
+	Load float64
+	// Deviation is Load - TargetLoad, positive when the executor holds more than its share.
+	Deviation float64
+}
+
+// IdealLoads returns the target load of every executor in executorLoads, which should only hold the
+// executors that can own shards. The total load is split evenly, or in proportion to capacities when
+// every executor has a positive capacity, since weighting only some executors would make the targets
+// incomparable. It returns an empty map when there are no executors.
+func IdealLoads(executorLoads map[string]float64, capacities map[string]float64) map[string]ExecutorTarget {
+	targets := make(map[string]ExecutorTarget, len(executorLoads))
+	if len(executorLoads) == 0 {
+		return targets
+	}
+
+	totalLoad, totalCapacity := 0.0, 0.0
+	useCapacity := len(capacities) > 0
+	for executorID, load := range executorLoads {
+		totalLoad += load
+		capacity := capacities[executorID]
+		if capacity <= 0 {
+			useCapacity = false
+		}
+		totalCapacity += capacity
+	}
+
+	for executorID, load := range executorLoads {
+		share := 1 / float64(len(executorLoads))
+		if useCapacity {
+			share = SafeDivide(capacities[executorID], totalCapacity, share)
+		}
+		target := totalLoad * share
+		targets[executorID] = ExecutorTarget{
+			TargetLoad: target,
+			Load:       load,
+			Deviation:  load - target,
+		}
+	}
+	return targets
+}
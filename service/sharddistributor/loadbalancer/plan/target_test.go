@@ -0,0 +1,62 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdealLoads(t *testing.T) {
+	tests := []struct {
+		name          string
+		executorLoads map[string]float64
+		capacities    map[string]float64
+		expected      map[string]ExecutorTarget
+	}{
+		{
+			name:     "No executors",
+			expected: map[string]ExecutorTarget{},
+		},
+		{
+			name:          "Uniform",
+			executorLoads: map[string]float64{"exec-a": 9, "exec-b": 3, "exec-c": 0},
+			expected: map[string]ExecutorTarget{
+				"exec-a": {TargetLoad: 4, Load: 9, Deviation: 5},
+				"exec-b": {TargetLoad: 4, Load: 3, Deviation: -1},
+				"exec-c": {TargetLoad: 4, Load: 0, Deviation: -4},
+			},
+		},
+		{
+			name:          "Capacity weighted",
+			executorLoads: map[string]float64{"exec-a": 6, "exec-b": 6},
+			capacities:    map[string]float64{"exec-a": 1, "exec-b": 3},
+			expected: map[string]ExecutorTarget{
+				"exec-a": {TargetLoad: 3, Load: 6, Deviation: 3},
+				"exec-b": {TargetLoad: 9, Load: 6, Deviation: -3},
+			},
+		},
+		{
+			name:          "Missing capacity falls back to uniform",
+			executorLoads: map[string]float64{"exec-a": 6, "exec-b": 6},
+			capacities:    map[string]float64{"exec-a": 1},
+			expected: map[string]ExecutorTarget{
+				"exec-a": {TargetLoad: 6, Load: 6, Deviation: 0},
+				"exec-b": {TargetLoad: 6, Load: 6, Deviation: 0},
+			},
+		},
+		{
+			name:          "No load",
+			executorLoads: map[string]float64{"exec-a": 0, "exec-b": 0},
+			expected: map[string]ExecutorTarget{
+				"exec-a": {},
+				"exec-b": {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IdealLoads(tt.executorLoads, tt.capacities))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidAssignment is returned by ValidateAssignment when an assignment breaks an invariant.
+var ErrInvalidAssignment = errors.New("invalid assignment")
+
+// ValidateAssignment checks the invariants of an assignment of shards to executors:
+// no shard is assigned to an empty executor ID, no shard is assigned more than once,
+// and every shard in knownShards is assigned, except the shards placement left out in
+// unplaced, which must not be assigned. The returned error wraps ErrInvalidAssignment
+// and lists every violation, so it can be used both as a guard before an assignment is
+// written and as an oracle in tests.
+func ValidateAssignment(assignments map[string][]string, knownShards []string, unplaced map[string]UnassignedReason) error {
+	var violations []string
+
+	owners := make(map[string][]string)
+	for _, executorID := range SortedExecutorIDs(assignments) {
+		if executorID == "" && len(assignments[executorID]) > 0 {
+			violations = append(violations, fmt.Sprintf("shards %v assigned to an empty executor ID", assignments[executorID]))
+		}
+		for _, shardID := range assignments[executorID] {
+			owners[shardID] = append(owners[shardID], executorID)
+		}
+	}
+
+	for _, shardID := range slices.Sorted(maps.Keys(owners)) {
+		if len(owners[shardID]) > 1 {
+			violations = append(violations, fmt.Sprintf("shard %s assigned more than once, to executors %v", shardID, owners[shardID]))
+		}
+	}
+
+	for _, shardID := range slices.Sorted(maps.Keys(unplaced)) {
+		if _, ok := owners[shardID]; ok {
+			violations = append(violations, fmt.Sprintf("shard %s assigned but left unplaced as %s", shardID, unplaced[shardID]))
+		}
+	}
+
+	var missing []string
+	for _, shardID := range knownShards {
+		_, isUnplaced := unplaced[shardID]
+		if _, ok := owners[shardID]; !ok && !isUnplaced {
+			missing = append(missing, shardID)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		violations = append(violations, fmt.Sprintf("shards %v not assigned", slices.Compact(missing)))
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAssignment, strings.Join(violations, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAssignment(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments map[string][]string
+		knownShards []string
+		unplaced    map[string]UnassignedReason
+		expectedErr string
+	}{
+		{
+			name:        "Valid",
+			assignments: map[string][]string{"exec-a": {"0", "2"}, "exec-b": {"1"}, "exec-c": {}},
+			knownShards: []string{"0", "1", "2"},
+		},
+		{
+			name:        "No shards",
+			assignments: map[string][]string{"exec-a": {}},
+		},
+		{
+			name:        "Shard on two executors",
+			assignments: map[string][]string{"exec-a": {"0", "1"}, "exec-b": {"1"}},
+			knownShards: []string{"0", "1"},
+			expectedErr: "invalid assignment: shard 1 assigned more than once, to executors [exec-a exec-b]",
+		},
+		{
+			name:        "Shard twice on the same executor",
+			assignments: map[string][]string{"exec-a": {"0", "0"}},
+			knownShards: []string{"0"},
+			expectedErr: "invalid assignment: shard 0 assigned more than once, to executors [exec-a exec-a]",
+		},
+		{
+			name:        "Missing shards",
+			assignments: map[string][]string{"exec-a": {"1"}},
+			knownShards: []string{"2", "0", "1"},
+			expectedErr: "invalid assignment: shards [0 2] not assigned",
+		},
+		{
+			name:        "Unplaced shards may be missing",
+			assignments: map[string][]string{"exec-a": {"1"}},
+			knownShards: []string{"0", "1"},
+			unplaced:    map[string]UnassignedReason{"0": UnassignedReasonNoMatchingExecutor},
+		},
+		{
+			name:        "Unplaced shard is assigned",
+			assignments: map[string][]string{"exec-a": {"0", "1"}},
+			knownShards: []string{"0", "1"},
+			unplaced:    map[string]UnassignedReason{"0": UnassignedReasonNoMatchingExecutor},
+			expectedErr: "invalid assignment: shard 0 assigned but left unplaced as no-matching-executor",
+		},
+		{
+			name:        "Empty executor ID",
+			assignments: map[string][]string{"": {"0"}, "exec-a": {"1"}},
+			knownShards: []string{"0", "1"},
+			expectedErr: "invalid assignment: shards [0] assigned to an empty executor ID",
+		},
+		{
+			name:        "All violations are listed",
+			assignments: map[string][]string{"": {"0"}, "exec-a": {"0"}},
+			knownShards: []string{"0", "1"},
+			expectedErr: "invalid assignment: shards [0] assigned to an empty executor ID; " +
+				"shard 0 assigned more than once, to executors [ exec-a]; shards [1] not assigned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAssignment(tt.assignments, tt.knownShards, tt.unplaced)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidAssignment)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
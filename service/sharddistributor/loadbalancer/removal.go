@@ -0,0 +1,65 @@
+package loadbalancer
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// PlanExecutorRemoval returns the moves that would drain executorID, placing each of its shards onto the
+// remaining executors with the namespace's load balancing mode. Shards are placed heaviest first so that the
+// big ones land on the least loaded executors. The drain is deliberate, so shard cooldowns are not consulted,
+// but shards the placement leaves out at now, e.g. the ones pinned to executorID, have no move.
+// The plan is not applied and state is not modified.
+func PlanExecutorRemoval(
+	cfg *config.Config,
+	namespace string,
+	state *store.NamespaceState,
+	executorID string,
+	now time.Time,
+) ([]plan.Move, error) {
+	if state == nil {
+		return nil, fmt.Errorf("executor %q: %w", executorID, store.ErrExecutorNotFound)
+	}
+	if _, ok := state.Executors[executorID]; !ok {
+		return nil, fmt.Errorf("executor %q: %w", executorID, store.ErrExecutorNotFound)
+	}
+
+	shardIDs := slices.Collect(maps.Keys(state.ShardAssignments[executorID].AssignedShards))
+	if len(shardIDs) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(shardIDs, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(state.ShardStats[b].SmoothedLoad, state.ShardStats[a].SmoothedLoad),
+			cmp.Compare(a, b),
+		)
+	})
+
+	remaining := *state
+	remaining.Executors = maps.Clone(state.Executors)
+	delete(remaining.Executors, executorID)
+	remaining.ShardAssignments = maps.Clone(state.ShardAssignments)
+	delete(remaining.ShardAssignments, executorID)
+
+	placements, _, err := PlanInitialPlacement(cfg, namespace, &remaining, shardIDs, now)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := make([]plan.Move, 0, len(placements))
+	for _, placement := range placements {
+		moves = append(moves, plan.Move{
+			ShardID: placement.ShardID,
+			From:    executorID,
+			To:      placement.ExecutorID,
+		})
+	}
+	return moves, nil
+}
@@ -0,0 +1,23 @@
+package loadbalancer
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	farm "github.com/dgryski/go-farm"
+)
+
+// NewRand returns the randomness source of the rebalance of namespace at epoch. The seed is derived
+// from both, so a rebalance draws the same random sequence whenever it is replayed with the same inputs,
+// while consecutive epochs of a namespace, and namespaces at the same epoch, draw different ones.
+func NewRand(namespace string, epoch int64) *rand.Rand {
+	return rand.New(rand.NewSource(Seed(namespace, epoch)))
+}
+
+// Seed returns the seed NewRand uses for the rebalance of namespace at epoch.
+func Seed(namespace string, epoch int64) int64 {
+	buf := make([]byte, 0, len(namespace)+8)
+	buf = append(buf, namespace...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(epoch))
+	return int64(farm.Fingerprint64(buf))
+}
@@ -0,0 +1,22 @@
+package loadbalancer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRand_SameNamespaceAndEpochReplays(t *testing.T) {
+	draw := func(rng *rand.Rand) []int64 {
+		sequence := make([]int64, 16)
+		for i := range sequence {
+			sequence[i] = rng.Int63()
+		}
+		return sequence
+	}
+
+	assert.Equal(t, draw(NewRand("test-namespace", 7)), draw(NewRand("test-namespace", 7)))
+	assert.NotEqual(t, draw(NewRand("test-namespace", 7)), draw(NewRand("test-namespace", 8)))
+	assert.NotEqual(t, draw(NewRand("test-namespace", 7)), draw(NewRand("other-namespace", 7)))
+}
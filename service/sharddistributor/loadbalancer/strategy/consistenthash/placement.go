@@ -0,0 +1,42 @@
+package consistenthash
+
+import (
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// PlanInitialPlacement returns planned placements for a batch of unassigned shards.
+// Each shard is placed on its owner in a ring of the executors that can own shards. A shard with a label
+// selector is placed on the first executor on the ring whose labels match it, and left out of the
+// placements and returned in unplaced when none does.
+func PlanInitialPlacement(
+	state *store.NamespaceState,
+	shardIDs []string,
+	selectors map[string]map[string]string,
+) (placements []plan.Placement, unplaced map[string]plan.UnassignedReason, err error) {
+	activeExecutors := make([]string, 0, len(state.Executors))
+	for _, executorID := range plan.SortedExecutorIDs(state.Executors) {
+		if state.Executors[executorID].CanOwnShards() {
+			activeExecutors = append(activeExecutors, executorID)
+		}
+	}
+	ring := NewRing(activeExecutors)
+
+	placements = make([]plan.Placement, 0, len(shardIDs))
+	unplaced = make(map[string]plan.UnassignedReason)
+	for _, shardID := range shardIDs {
+		executorID, ok := ring.OwnerMatching(shardID, selectorMatcher(state, selectors[shardID]))
+		if !ok {
+			if len(activeExecutors) == 0 {
+				return nil, nil, plan.ErrNoActiveExecutors
+			}
+			unplaced[shardID] = plan.UnassignedReasonNoMatchingExecutor
+			continue
+		}
+		placements = append(placements, plan.Placement{
+			ShardID:    shardID,
+			ExecutorID: executorID,
+		})
+	}
+	return placements, unplaced, nil
+}
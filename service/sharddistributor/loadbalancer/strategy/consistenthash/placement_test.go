@@ -0,0 +1,63 @@
+package consistenthash
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestPlanInitialPlacement(t *testing.T) {
+	t.Run("places shards on their ring owner among active executors", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"a": {Status: types.ExecutorStatusACTIVE},
+				"b": {Status: types.ExecutorStatusACTIVE},
+				"c": {Status: types.ExecutorStatusDRAINING},
+			},
+		}
+		ring := NewRing([]string{"a", "b"})
+
+		placements, _, err := PlanInitialPlacement(state, []string{"s1", "s2", "s3", "s4"}, nil)
+		require.NoError(t, err)
+		require.Len(t, placements, 4)
+		for _, placement := range placements {
+			owner, _ := ring.Owner(placement.ShardID)
+			assert.Equal(t, owner, placement.ExecutorID)
+		}
+	})
+
+	t.Run("shards with a label selector are only placed on matching executors", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"a": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"}},
+				"b": {Status: types.ExecutorStatusACTIVE},
+			},
+		}
+		shardIDs := []string{"s1", "s2", "s3", "s4", "tpu"}
+		selectors := map[string]map[string]string{
+			"s1": {"gpu": "true"}, "s2": {"gpu": "true"}, "s3": {"gpu": "true"}, "s4": {"gpu": "true"},
+			"tpu": {"tpu": "true"},
+		}
+
+		placements, unplaced, err := PlanInitialPlacement(state, shardIDs, selectors)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Placement{
+			{ShardID: "s1", ExecutorID: "a"},
+			{ShardID: "s2", ExecutorID: "a"},
+			{ShardID: "s3", ExecutorID: "a"},
+			{ShardID: "s4", ExecutorID: "a"},
+		}, placements)
+		assert.Equal(t, map[string]plan.UnassignedReason{"tpu": plan.UnassignedReasonNoMatchingExecutor}, unplaced)
+	})
+
+	t.Run("no active executors", func(t *testing.T) {
+		_, _, err := PlanInitialPlacement(&store.NamespaceState{}, []string{"s1"}, nil)
+		assert.True(t, errors.Is(err, plan.ErrNoActiveExecutors))
+	})
+}
@@ -0,0 +1,45 @@
+package consistenthash
+
+import (
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// PlanRebalance returns the moves that put every shard on its owner in a ring of the executors
+// in currentAssignments. Load is not considered, so the placement only changes when executors
+// join or leave. Shards in pinnedShards are never moved. A shard with a label selector is owned by
+// the first executor on the ring whose labels match it, and stays where it is when none does.
+func PlanRebalance(
+	state *store.NamespaceState,
+	currentAssignments map[string][]string,
+	pinnedShards map[string]string,
+	selectors map[string]map[string]string,
+) ([]plan.Move, error) {
+	ring := NewRing(plan.SortedExecutorIDs(currentAssignments))
+
+	var moves []plan.Move
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		for _, shardID := range currentAssignments[executorID] {
+			if _, pinned := pinnedShards[shardID]; pinned {
+				continue
+			}
+			owner, ok := ring.OwnerMatching(shardID, selectorMatcher(state, selectors[shardID]))
+			if !ok || owner == executorID {
+				continue
+			}
+			moves = append(moves, plan.Move{ShardID: shardID, From: executorID, To: owner})
+		}
+	}
+	return moves, nil
+}
+
+// selectorMatcher returns a function reporting whether an executor of state matches selector, or nil
+// when the selector is empty and every executor matches.
+func selectorMatcher(state *store.NamespaceState, selector map[string]string) func(executorID string) bool {
+	if len(selector) == 0 || state == nil {
+		return nil
+	}
+	return func(executorID string) bool {
+		return plan.MatchesSelector(state.Executors[executorID].Labels(), selector)
+	}
+}
@@ -0,0 +1,74 @@
+package consistenthash
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	executors := []string{"exec-1", "exec-2", "exec-3"}
+	ring := NewRing(executors)
+	currentAssignments := make(map[string][]string)
+	for i := range 100 {
+		shardID := fmt.Sprintf("shard-%d", i)
+		owner, _ := ring.Owner(shardID)
+		currentAssignments[owner] = append(currentAssignments[owner], shardID)
+	}
+
+	t.Run("no moves when every shard is on its owner", func(t *testing.T) {
+		moves, err := PlanRebalance(nil, currentAssignments, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+	})
+
+	t.Run("shards not on their owner are moved, except pinned ones", func(t *testing.T) {
+		// exec-3 left, its shards were placed on exec-1 without considering the ring.
+		assignments := map[string][]string{
+			"exec-1": append(append([]string{}, currentAssignments["exec-1"]...), currentAssignments["exec-3"]...),
+			"exec-2": currentAssignments["exec-2"],
+		}
+		pinnedShard := currentAssignments["exec-3"][0]
+
+		moves, err := PlanRebalance(nil, assignments, map[string]string{pinnedShard: ""}, nil)
+		require.NoError(t, err)
+
+		remainingRing := NewRing([]string{"exec-1", "exec-2"})
+		moved := make(map[string]bool)
+		for _, move := range moves {
+			assert.Equal(t, "exec-1", move.From)
+			assert.Equal(t, "exec-2", move.To)
+			owner, _ := remainingRing.Owner(move.ShardID)
+			assert.Equal(t, owner, move.To)
+			moved[move.ShardID] = true
+		}
+		assert.False(t, moved[pinnedShard])
+		for _, shardID := range currentAssignments["exec-1"] {
+			assert.False(t, moved[shardID], "shard %s stays on its owner", shardID)
+		}
+	})
+	t.Run("shards with a label selector move to the first matching executor on the ring", func(t *testing.T) {
+		state := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+			"exec-1": {Status: types.ExecutorStatusACTIVE},
+			"exec-2": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"}},
+			"exec-3": {Status: types.ExecutorStatusACTIVE},
+		}}
+		gpuShard := currentAssignments["exec-1"][0]
+		tpuShard := currentAssignments["exec-3"][0]
+		selectors := map[string]map[string]string{
+			gpuShard: {"gpu": "true"},
+			tpuShard: {"tpu": "true"},
+		}
+
+		moves, err := PlanRebalance(state, currentAssignments, nil, selectors)
+		require.NoError(t, err)
+		// No executor matches the selector of tpuShard, so it stays where it is.
+		assert.Equal(t, []plan.Move{{ShardID: gpuShard, From: "exec-1", To: "exec-2"}}, moves)
+	})
+}
@@ -0,0 +1,67 @@
+package consistenthash
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	farm "github.com/dgryski/go-farm"
+)
+
+// virtualNodesPerExecutor is the number of points each executor gets on the ring.
+// More points spread the shards more evenly over the executors.
+const virtualNodesPerExecutor = 128
+
+type ringPoint struct {
+	hash       uint32
+	executorID string
+}
+
+// Ring maps shard IDs onto executors by hashing both onto the same ring. A shard is owned by
+// the first executor point at or after its hash, so adding or removing an executor only changes
+// the owner of the shards between its points and the points before them.
+type Ring struct {
+	points []ringPoint
+}
+
+// NewRing returns a ring of the given executors.
+func NewRing(executorIDs []string) *Ring {
+	points := make([]ringPoint, 0, len(executorIDs)*virtualNodesPerExecutor)
+	for _, executorID := range executorIDs {
+		for i := range virtualNodesPerExecutor {
+			points = append(points, ringPoint{
+				hash:       farm.Hash32([]byte(executorID + "#" + strconv.Itoa(i))),
+				executorID: executorID,
+			})
+		}
+	}
+	// Ties are broken by executor ID so the ring does not depend on the order of executorIDs.
+	slices.SortFunc(points, func(a, b ringPoint) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.executorID, b.executorID))
+	})
+	return &Ring{points: points}
+}
+
+// Owner returns the executor owning shardID, or false if the ring has no executors.
+func (r *Ring) Owner(shardID string) (string, bool) {
+	return r.OwnerMatching(shardID, nil)
+}
+
+// OwnerMatching returns the first executor at or after the hash of shardID for which matches returns true,
+// or false if no executor on the ring matches. A nil matches accepts every executor.
+func (r *Ring) OwnerMatching(shardID string, matches func(executorID string) bool) (string, bool) {
+	if len(r.points) == 0 {
+		return "", false
+	}
+	hash := farm.Hash32([]byte(shardID))
+	start, _ := slices.BinarySearchFunc(r.points, hash, func(point ringPoint, target uint32) int {
+		return cmp.Compare(point.hash, target)
+	})
+	for offset := range r.points {
+		point := r.points[(start+offset)%len(r.points)]
+		if matches == nil || matches(point.executorID) {
+			return point.executorID, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,49 @@
+package consistenthash
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOwners(t *testing.T, ring *Ring, numShards int) map[string]string {
+	owners := make(map[string]string, numShards)
+	for i := range numShards {
+		shardID := fmt.Sprintf("shard-%d", i)
+		owner, ok := ring.Owner(shardID)
+		require.True(t, ok)
+		owners[shardID] = owner
+	}
+	return owners
+}
+
+func TestRing_RemovingAnExecutorOnlyMovesItsShards(t *testing.T) {
+	const numShards = 1000
+	before := testOwners(t, NewRing([]string{"exec-1", "exec-2", "exec-3", "exec-4", "exec-5"}), numShards)
+	after := testOwners(t, NewRing([]string{"exec-1", "exec-2", "exec-4", "exec-5"}), numShards)
+
+	moved := 0
+	for shardID, owner := range before {
+		if owner == "exec-3" {
+			assert.NotEqual(t, "exec-3", after[shardID])
+			moved++
+			continue
+		}
+		assert.Equal(t, owner, after[shardID], "shard %s did not belong to the removed executor", shardID)
+	}
+	// Roughly a fifth of the shards belonged to the removed executor.
+	assert.InDelta(t, numShards/5, moved, numShards/10)
+}
+
+func TestRing_OwnerIsIndependentOfExecutorOrder(t *testing.T) {
+	a := testOwners(t, NewRing([]string{"exec-1", "exec-2", "exec-3"}), 100)
+	b := testOwners(t, NewRing([]string{"exec-3", "exec-1", "exec-2"}), 100)
+	assert.Equal(t, a, b)
+}
+
+func TestRing_Empty(t *testing.T) {
+	_, ok := NewRing(nil).Owner("shard-1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,211 @@
+package greedy
+
+import (
+	"maps"
+	"math/rand"
+	"time"
+
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// planMinMovementMoves plans the fewest moves it finds that bring every executor within the hysteresis bands
+// around meanLoad, rather than moving towards the best balance. It plans the moves both incrementally and
+// within the bands, and keeps the plan with the fewest moves among those that get every executor within the
+// bands. When neither does, the incremental plan is kept, as it gets closer to the balance target. The
+// maps are not modified.
+func planMinMovementMoves(
+	cfg config.LoadBalancingGreedyConfig,
+	namespace string,
+	namespaceState *store.NamespaceState,
+	workingAssignments map[string][]string,
+	loads map[string]float64,
+	meanLoad float64,
+	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
+	shardOverhead float64,
+	moveBudget int,
+	shedRand *rand.Rand,
+	now time.Time,
+) ([]plan.Move, error) {
+	incrementalLoads := maps.Clone(loads)
+	incremental, err := planIncrementalMoves(cfg, namespace, namespaceState, cloneAssignments(workingAssignments), incrementalLoads, meanLoad, maps.Clone(movedShards), selectors, shardOverhead, moveBudget, shedRand, now)
+	if err != nil {
+		return nil, err
+	}
+	bandedLoads := maps.Clone(loads)
+	banded, err := planBandedMoves(cfg, namespace, namespaceState, cloneAssignments(workingAssignments), bandedLoads, meanLoad, maps.Clone(movedShards), selectors, shardOverhead, moveBudget, now)
+	if err != nil {
+		return nil, err
+	}
+
+	upper := meanLoad * cfg.HysteresisUpperBand(namespace)
+	lower := meanLoad * cfg.HysteresisLowerBand(namespace)
+	if withinBands(namespaceState, bandedLoads, lower, upper) &&
+		(len(banded) < len(incremental) || !withinBands(namespaceState, incrementalLoads, lower, upper)) {
+		return banded, nil
+	}
+	return incremental, nil
+}
+
+// planBandedMoves plans moves that never push an executor out of the hysteresis bands, so shards that do not
+// need to move stay in place. Overloaded executors first shed shards until they are under the upper band, each
+// to the least loaded executor. Executors still under the lower band then take shards from the executors with
+// the most load. Every move takes the heaviest shard that fits. Shards in movedShards, in cooldown or without
+// statistics are not moved, and shards are only moved to executors matching their label selector. The moves are applied to workingAssignments, loads and movedShards.
+func planBandedMoves(
+	cfg config.LoadBalancingGreedyConfig,
+	namespace string,
+	namespaceState *store.NamespaceState,
+	workingAssignments map[string][]string,
+	loads map[string]float64,
+	meanLoad float64,
+	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
+	shardOverhead float64,
+	moveBudget int,
+	now time.Time,
+) ([]plan.Move, error) {
+	upper := meanLoad * cfg.HysteresisUpperBand(namespace)
+	lower := meanLoad * cfg.HysteresisLowerBand(namespace)
+	perShardCooldown := cfg.PerShardCooldown(namespace)
+
+	var destinations []string
+	for _, executorID := range plan.SortedExecutorIDs(loads) {
+		if executor := namespaceState.Executors[executorID]; executor.CanOwnShards() && !executor.IsStandby() {
+			destinations = append(destinations, executorID)
+		}
+	}
+
+	var moves []plan.Move
+	// tryMove moves the heaviest shard of from that keeps both executors within the bands to to.
+	tryMove := func(from, to string) (bool, error) {
+		maxWeight := min(loads[from]-lower, upper-loads[to])
+		shardID, idx, found := findHeaviestFittingShard(workingAssignments[from], namespaceState, to, movedShards, selectors, maxWeight, now, perShardCooldown, shardOverhead)
+		if !found {
+			return false, nil
+		}
+		if err := applyMoveCandidate(workingAssignments, moveCandidate{shardID: shardID, from: from, to: to, assignmentIndex: idx}); err != nil {
+			return false, err
+		}
+		movedShards[shardID] = struct{}{}
+		updateExecutorLoadsAfterMove(namespaceState, from, to, loads, shardID, shardOverhead)
+		moves = append(moves, plan.Move{ShardID: shardID, From: from, To: to})
+		return true, nil
+	}
+
+	var sources []string
+	for _, executorID := range plan.SortExecutorsByLoad(loads, false) {
+		if loads[executorID] > upper {
+			sources = append(sources, executorID)
+		}
+	}
+	for _, source := range sources {
+		for len(moves) < moveBudget && loads[source] > upper {
+			destination, ok := findBestDestination(without(destinations, source), loads)
+			if !ok {
+				break
+			}
+			moved, err := tryMove(source, destination)
+			if err != nil {
+				return nil, err
+			}
+			if !moved {
+				break
+			}
+		}
+	}
+
+	for _, destination := range destinations {
+		for len(moves) < moveBudget && loads[destination] < lower {
+			donors := without(plan.SortExecutorsByLoad(loads, false), destination)
+			moved := false
+			for _, donor := range donors {
+				if loads[donor] <= lower {
+					break
+				}
+				var err error
+				if moved, err = tryMove(donor, destination); err != nil {
+					return nil, err
+				}
+				if moved {
+					break
+				}
+			}
+			if !moved {
+				break
+			}
+		}
+	}
+	return moves, nil
+}
+
+// withinBands reports whether no executor is over the upper band, and no executor that can receive shards
+// is under the lower band.
+func withinBands(namespaceState *store.NamespaceState, loads map[string]float64, lower, upper float64) bool {
+	for executorID, load := range loads {
+		if load > upper {
+			return false
+		}
+		if executor := namespaceState.Executors[executorID]; executor.CanOwnShards() && !executor.IsStandby() && load < lower {
+			return false
+		}
+	}
+	return true
+}
+
+// findHeaviestFittingShard returns the heaviest shard of shardIDs that can move to destination and weighs at most
+// maxWeight. Moving the heaviest shard that keeps both executors within the bands moves the most load per move, so
+// the executors get within the bands in the fewest moves.
+func findHeaviestFittingShard(
+	shardIDs []string,
+	state *store.NamespaceState,
+	destination string,
+	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
+	maxWeight float64,
+	now time.Time,
+	perShardCooldown time.Duration,
+	shardOverhead float64,
+) (string, int, bool) {
+	heaviest := -1
+	heaviestWeight := 0.0
+	destinationLabels := state.Executors[destination].Labels()
+	for i, shardID := range shardIDs {
+		if _, ok := movedShards[shardID]; ok {
+			continue
+		}
+		if !plan.MatchesSelector(destinationLabels, selectors[shardID]) {
+			continue
+		}
+		// A shard without statistics weighs only the per-shard overhead.
+		stats := state.ShardStats[shardID]
+		if plan.InCooldown(stats.LastMoveTime, now, perShardCooldown) {
+			continue
+		}
+		if weight := shardWeight(stats, shardOverhead); weight > heaviestWeight && weight <= maxWeight {
+			heaviest, heaviestWeight = i, weight
+		}
+	}
+	if heaviest < 0 {
+		return "", -1, false
+	}
+	return shardIDs[heaviest], heaviest, true
+}
+
+// without returns the executors other than executorID.
+func without(executors []string, executorID string) []string {
+	filtered := make([]string, 0, len(executors))
+	for _, e := range executors {
+		if e != executorID {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// useMinMovement returns whether rebalances plan the fewest moves that bring the executors within the bands.
+func useMinMovement(cfg config.LoadBalancingGreedyConfig, namespace string) bool {
+	return cfg.RebalanceStrategy != nil && cfg.RebalanceStrategy(namespace) == config.RebalanceStrategyMinMovement
+}
@@ -0,0 +1,145 @@
+package greedy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+// executorLoadsAfter returns the load of every executor once moves are applied to currentAssignments.
+func executorLoadsAfter(state *store.NamespaceState, currentAssignments map[string][]string, moves []plan.Move) map[string]float64 {
+	loads := make(map[string]float64, len(currentAssignments))
+	for executorID, shardIDs := range currentAssignments {
+		for _, shardID := range shardIDs {
+			loads[executorID] += state.ShardStats[shardID].SmoothedLoad
+		}
+	}
+	for _, move := range moves {
+		loads[move.From] -= state.ShardStats[move.ShardID].SmoothedLoad
+		loads[move.To] += state.ShardStats[move.ShardID].SmoothedLoad
+	}
+	return loads
+}
+
+func TestLoadBalance_MinMovementMovesFewerShardsThanIncremental(t *testing.T) {
+	execA, execB, execC := "exec-A", "exec-B", "exec-C"
+	now := time.Now().UTC()
+	currentAssignments := map[string][]string{
+		execA: {"a-8", "a-4", "a-5", "a-1"},
+		execB: {"b-1", "b-3"},
+		execC: {"c-4"},
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execC: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"a-8": {SmoothedLoad: 8}, "a-4": {SmoothedLoad: 4}, "a-5": {SmoothedLoad: 5}, "a-1": {SmoothedLoad: 1},
+			"b-1": {SmoothedLoad: 1}, "b-3": {SmoothedLoad: 3},
+			"c-4": {SmoothedLoad: 4},
+		},
+	}
+
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(namespace string) float64 { return 1 }
+	meanLoad := 26.0 / 3
+	lower, upper := meanLoad*cfg.HysteresisLowerBand(testNamespace), meanLoad*cfg.HysteresisUpperBand(testNamespace)
+
+	// The incremental strategy moves the shard that best balances exec-A with exec-B, which overshoots
+	// exec-B, so exec-B has to shed a shard again.
+	incremental, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Len(t, incremental, 3)
+
+	cfg.RebalanceStrategy = func(namespace string) string { return config.RebalanceStrategyMinMovement }
+	minMovement, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Less(t, len(minMovement), len(incremental))
+	assert.ElementsMatch(t, []plan.Move{
+		{ShardID: "a-5", From: execA, To: execB},
+		{ShardID: "a-4", From: execA, To: execC},
+	}, minMovement)
+	for executorID, load := range executorLoadsAfter(state, currentAssignments, minMovement) {
+		assert.GreaterOrEqual(t, load, lower, executorID)
+		assert.LessOrEqual(t, load, upper, executorID)
+	}
+}
+
+func TestLoadBalance_MinMovementKeepsIncrementalPlanOutsideTheBands(t *testing.T) {
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+	// A single hot shard cannot be placed anywhere within the bands, so the incremental plan is kept.
+	currentAssignments := map[string][]string{
+		execA: {"hot", "a-1"},
+		execB: {"b-1"},
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"hot": {SmoothedLoad: 20}, "a-1": {SmoothedLoad: 1}, "b-1": {SmoothedLoad: 1},
+		},
+	}
+
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(namespace string) float64 { return 1 }
+	incremental, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+
+	cfg.RebalanceStrategy = func(namespace string) string { return config.RebalanceStrategyMinMovement }
+	minMovement, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Equal(t, incremental, minMovement)
+}
+
+func TestPlanBandedMoves_SkipsFrozenShards(t *testing.T) {
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+	workingAssignments := map[string][]string{
+		execA: {"pinned", "cooling-down", "movable"},
+		execB: {"b-1"},
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"pinned":       {SmoothedLoad: 4},
+			"cooling-down": {SmoothedLoad: 4, LastMoveTime: now.Add(-time.Second)},
+			"movable":      {SmoothedLoad: 3},
+			"b-1":          {SmoothedLoad: 1},
+		},
+	}
+	loads := map[string]float64{execA: 11, execB: 1}
+	movedShards := map[string]struct{}{"pinned": {}}
+
+	moves, err := planBandedMoves(testGreedyConfig(), testNamespace, state, workingAssignments, loads, 6, movedShards, nil, 0, 10, now)
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Move{{ShardID: "movable", From: execA, To: execB}}, moves)
+	assert.Equal(t, map[string]float64{execA: 8, execB: 4}, loads)
+}
+
+func TestWithinBands(t *testing.T) {
+	state := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+		"active":   {Status: types.ExecutorStatusACTIVE},
+		"draining": {Status: types.ExecutorStatusDRAINING},
+	}}
+
+	assert.True(t, withinBands(state, map[string]float64{"active": 10, "draining": 0}, 9, 11), "a draining executor does not need load")
+	assert.False(t, withinBands(state, map[string]float64{"active": 8}, 9, 11))
+	assert.False(t, withinBands(state, map[string]float64{"active": 10, "draining": 12}, 9, 11))
+}
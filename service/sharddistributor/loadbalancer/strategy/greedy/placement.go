@@ -3,9 +3,10 @@ package greedy
 import (
 	"cmp"
 	"maps"
+	"math/rand"
 	"slices"
 
-	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
@@ -13,68 +14,259 @@ import (
 type executorLoad struct {
 	shardCount   int
 	smoothedLoad float64
+	// capacity normalizes the load so executors with more headroom are preferred.
+	capacity float64
 }
 
 // PlanInitialPlacement returns planned placements for a batch of unassigned shards.
-func PlanInitialPlacement(state *store.NamespaceState, shardIDs []string) ([]plan.Placement, error) {
-	loads, averageShardLoad := executorLoads(state)
-	placements := make([]plan.Placement, 0, len(shardIDs))
+// When every ACTIVE executor reports headroom, loads are compared relative to each executor's headroom.
+// On a cold start, when no shard has statistics yet, shards are spread evenly by count.
+// Every shard is assumed to carry at least shardLoadFloor load, so shards without statistics do not
+// make their executor look idle, and shardOverhead is added to it so shard count contributes to load.
+// A shard with a label selector is only placed on executors whose labels match it. Shards no active
+// executor matches are left out of the placements and returned in unplaced.
+// Executors with the same load are told apart by the tieBreak strategy, one of the config.PlacementTieBreak
+// values; rng is only used by config.PlacementTieBreakRandom.
+// The total load an executor reports takes precedence over the loads of its shards.
+func PlanInitialPlacement(
+	state *store.NamespaceState,
+	shardIDs []string,
+	shardLoadFloor, shardOverhead float64,
+	selectors map[string]map[string]string,
+	tieBreak string,
+	rng *rand.Rand,
+) (placements []plan.Placement, unplaced map[string]plan.UnassignedReason, err error) {
+	state = withReportedExecutorLoads(state, assignedShardIDs(state))
+	loads, averageShardLoad := executorLoads(state, shardLoadFloor, shardOverhead)
+	averageShardLoad = max(averageShardLoad, shardLoadFloor+shardOverhead)
+	breakTie := newTieBreaker(state, tieBreak, rng)
+	choose := func(loads map[string]executorLoad, averageShardLoad float64) (string, error) {
+		return chooseExecutorAndUpdateLoads(loads, averageShardLoad, breakTie)
+	}
+	if len(state.ShardStats) == 0 {
+		choose = func(loads map[string]executorLoad, _ float64) (string, error) {
+			return chooseColdStartExecutorAndUpdateLoads(loads, breakTie)
+		}
+	}
+	placements = make([]plan.Placement, 0, len(shardIDs))
+	unplaced = make(map[string]plan.UnassignedReason)
 	for _, shardID := range shardIDs {
-		executorID, err := chooseExecutorAndUpdateLoads(loads, averageShardLoad)
+		candidates := loads
+		if selector := selectors[shardID]; len(selector) > 0 && len(loads) > 0 {
+			candidates = matchingExecutorLoads(state, loads, selector)
+			if len(candidates) == 0 {
+				unplaced[shardID] = plan.UnassignedReasonNoMatchingExecutor
+				continue
+			}
+		}
+		executorID, err := choose(candidates, averageShardLoad)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		// choose updated the load of the chosen executor in candidates, which may be a subset of loads.
+		loads[executorID] = candidates[executorID]
 		placements = append(placements, plan.Placement{
 			ShardID:    shardID,
 			ExecutorID: executorID,
 		})
 	}
-	return placements, nil
+	return placements, unplaced, nil
 }
 
-func executorLoads(state *store.NamespaceState) (map[string]executorLoad, float64) {
+// matchingExecutorLoads returns the loads of the executors whose labels match selector.
+func matchingExecutorLoads(state *store.NamespaceState, loads map[string]executorLoad, selector map[string]string) map[string]executorLoad {
+	matching := make(map[string]executorLoad)
+	for executorID, load := range loads {
+		if plan.MatchesSelector(state.Executors[executorID].Labels(), selector) {
+			matching[executorID] = load
+		}
+	}
+	return matching
+}
+
+func executorLoads(state *store.NamespaceState, shardLoadFloor, shardOverhead float64) (map[string]executorLoad, float64) {
 	loads := make(map[string]executorLoad, len(state.Executors))
 	totalSmoothedLoad := 0.0
 	totalShardCount := 0
+	useHeadroom := allActiveExecutorsReportHeadroom(state)
 
-	for executorID, executorState := range state.Executors {
-		if executorState.Status != types.ExecutorStatusACTIVE {
+	for _, executorID := range plan.SortedExecutorIDs(state.Executors) {
+		if !state.Executors[executorID].CanOwnShards() {
 			continue
 		}
-		var load executorLoad
-		for shardID := range state.ShardAssignments[executorID].AssignedShards {
+		load := executorLoad{capacity: 1}
+		if useHeadroom {
+			load.capacity = state.Executors[executorID].Headroom()
+		}
+		for _, shardID := range slices.Sorted(maps.Keys(state.ShardAssignments[executorID].AssignedShards)) {
 			load.shardCount++
-			if stats, ok := state.ShardStats[shardID]; ok {
-				load.smoothedLoad += stats.SmoothedLoad
-			}
+			load.smoothedLoad += shardLoad(state, shardID, shardLoadFloor, shardOverhead)
 		}
 		totalShardCount += load.shardCount
 		totalSmoothedLoad += load.smoothedLoad
 		loads[executorID] = load
 	}
 
-	var averageShardLoad float64
-	if totalShardCount > 0 {
-		averageShardLoad = totalSmoothedLoad / float64(totalShardCount)
+	return loads, plan.SafeDivide(totalSmoothedLoad, float64(totalShardCount), 0)
+}
+
+// shardLoad returns the load of a shard, raised to shardLoadFloor, plus the fixed shardOverhead.
+// An operator's weight override takes precedence over the smoothed load. Shards without statistics
+// have no load of their own.
+func shardLoad(state *store.NamespaceState, shardID string, shardLoadFloor, shardOverhead float64) float64 {
+	return max(state.ShardStats[shardID].Load(), shardLoadFloor) + shardOverhead
+}
+
+// shardWeight returns the weight of a shard when rebalancing: its load plus the fixed shardOverhead.
+func shardWeight(stats store.ShardStatistics, shardOverhead float64) float64 {
+	return stats.Load() + shardOverhead
+}
+
+// withReportedExecutorLoads returns state with the statistics of the shards in assignments replaced for the
+// executors that report their total load, so the loads of their shards add up to the reported load. The reported
+// load is split across the shards in proportion to their own load, or evenly when none of them has one, which
+// keeps the moves of the balancer meaningful when the executor cannot attribute its load. state is not modified.
+func withReportedExecutorLoads(state *store.NamespaceState, assignments map[string][]string) *store.NamespaceState {
+	var shardStats map[string]store.ShardStatistics
+	for _, executorID := range plan.SortedExecutorIDs(assignments) {
+		reportedLoad, ok := state.Executors[executorID].ExecutorLoad()
+		shardIDs := assignments[executorID]
+		if !ok || len(shardIDs) == 0 {
+			continue
+		}
+		if shardStats == nil {
+			shardStats = make(map[string]store.ShardStatistics, len(state.ShardStats))
+			maps.Copy(shardStats, state.ShardStats)
+		}
+
+		shardLoadSum := 0.0
+		for _, shardID := range shardIDs {
+			shardLoadSum += state.ShardStats[shardID].Load()
+		}
+		for _, shardID := range shardIDs {
+			share := 1 / float64(len(shardIDs))
+			if shardLoadSum > 0 {
+				share = state.ShardStats[shardID].Load() / shardLoadSum
+			}
+			stats := shardStats[shardID]
+			stats.SmoothedLoad = reportedLoad * share
+			stats.WeightOverride = 0
+			shardStats[shardID] = stats
+		}
+	}
+	if shardStats == nil {
+		return state
+	}
+
+	adjusted := *state
+	adjusted.ShardStats = shardStats
+	return &adjusted
+}
+
+// assignedShardIDs returns the shards assigned to each executor in state.
+func assignedShardIDs(state *store.NamespaceState) map[string][]string {
+	assignments := make(map[string][]string, len(state.ShardAssignments))
+	for executorID, assigned := range state.ShardAssignments {
+		assignments[executorID] = slices.Sorted(maps.Keys(assigned.AssignedShards))
 	}
-	return loads, averageShardLoad
+	return assignments
 }
 
-func chooseExecutorAndUpdateLoads(loads map[string]executorLoad, averageShardLoad float64) (string, error) {
+// allActiveExecutorsReportHeadroom reports whether headroom can be used as executor capacity.
+// Normalizing only some executors would make their loads incomparable with the rest.
+func allActiveExecutorsReportHeadroom(state *store.NamespaceState) bool {
+	found := false
+	for _, executor := range state.Executors {
+		if !executor.CanOwnShards() {
+			continue
+		}
+		if executor.Headroom() <= 0 {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func chooseExecutorAndUpdateLoads(loads map[string]executorLoad, averageShardLoad float64, breakTie tieBreaker) (string, error) {
 	if len(loads) == 0 {
 		return "", plan.ErrNoActiveExecutors
 	}
-	chosen := slices.MinFunc(slices.Collect(maps.Keys(loads)), func(a, b string) int {
-		la, lb := loads[a], loads[b]
-		return cmp.Or(
-			cmp.Compare(la.smoothedLoad, lb.smoothedLoad),
-			cmp.Compare(la.shardCount, lb.shardCount),
-			cmp.Compare(a, b),
-		)
-	})
+	chosen := breakTie(loads, leastBy(loads, func(load executorLoad) float64 {
+		return load.smoothedLoad / load.capacity
+	}))
 	load := loads[chosen]
 	load.shardCount++
 	load.smoothedLoad += averageShardLoad
 	loads[chosen] = load
 	return chosen, nil
 }
+
+// chooseColdStartExecutorAndUpdateLoads picks the executor with the fewest shards relative to its
+// capacity. Without statistics smoothed loads carry no signal, so they are ignored.
+func chooseColdStartExecutorAndUpdateLoads(loads map[string]executorLoad, breakTie tieBreaker) (string, error) {
+	if len(loads) == 0 {
+		return "", plan.ErrNoActiveExecutors
+	}
+	chosen := breakTie(loads, leastBy(loads, func(load executorLoad) float64 {
+		return float64(load.shardCount) / load.capacity
+	}))
+	load := loads[chosen]
+	load.shardCount++
+	loads[chosen] = load
+	return chosen, nil
+}
+
+// leastBy returns the sorted IDs of the executors with the lowest key.
+func leastBy(loads map[string]executorLoad, key func(executorLoad) float64) []string {
+	var least []string
+	var leastKey float64
+	for _, executorID := range plan.SortedExecutorIDs(loads) {
+		switch k := key(loads[executorID]); {
+		case len(least) == 0 || k < leastKey:
+			least, leastKey = []string{executorID}, k
+		case k == leastKey:
+			least = append(least, executorID)
+		}
+	}
+	return least
+}
+
+// tieBreaker picks one of the tied executors, which are sorted by ID and never empty.
+type tieBreaker func(loads map[string]executorLoad, tied []string) string
+
+// newTieBreaker returns the tie breaker of the given config.PlacementTieBreak strategy.
+// Unknown strategies, and the random strategy without rng, fall back to the shard count strategy.
+func newTieBreaker(state *store.NamespaceState, strategy string, rng *rand.Rand) tieBreaker {
+	switch {
+	case strategy == config.PlacementTieBreakRandom && rng != nil:
+		return func(_ map[string]executorLoad, tied []string) string {
+			return tied[rng.Intn(len(tied))]
+		}
+	case strategy == config.PlacementTieBreakMostRecentlyEmptied:
+		return func(loads map[string]executorLoad, tied []string) string {
+			return slices.MinFunc(tied, func(a, b string) int {
+				la, lb := loads[a], loads[b]
+				return cmp.Or(
+					// Executors that currently own no shards come first, the most recently emptied one first.
+					cmp.Compare(min(la.shardCount, 1), min(lb.shardCount, 1)),
+					state.ShardAssignments[b].LastUpdated.Compare(state.ShardAssignments[a].LastUpdated),
+					compareByShardCount(loads, a, b),
+				)
+			})
+		}
+	default:
+		return func(loads map[string]executorLoad, tied []string) string {
+			return slices.MinFunc(tied, func(a, b string) int { return compareByShardCount(loads, a, b) })
+		}
+	}
+}
+
+// compareByShardCount orders executors by their shard count relative to their capacity, then by ID.
+func compareByShardCount(loads map[string]executorLoad, a, b string) int {
+	la, lb := loads[a], loads[b]
+	return cmp.Or(
+		cmp.Compare(float64(la.shardCount)/la.capacity, float64(lb.shardCount)/lb.capacity),
+		cmp.Compare(a, b),
+	)
+}
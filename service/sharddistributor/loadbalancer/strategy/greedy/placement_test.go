@@ -2,12 +2,16 @@ package greedy
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
@@ -34,7 +38,7 @@ func TestPlanInitialPlacement(t *testing.T) {
 			},
 		}
 
-		placements, err := PlanInitialPlacement(state, []string{"new-1", "new-2"})
+		placements, _, err := PlanInitialPlacement(state, []string{"new-1", "new-2"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
 		require.NoError(t, err)
 
 		// cold has the lowest smoothed load. After bumping cold by the
@@ -57,26 +61,332 @@ func TestPlanInitialPlacement(t *testing.T) {
 			},
 		}
 
-		placements, err := PlanInitialPlacement(state, []string{"new-1"})
+		placements, _, err := PlanInitialPlacement(state, []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
 		require.NoError(t, err)
 
 		// All shard stats are missing, so smoothed loads tie and shard count breaks the tie.
 		assert.Equal(t, []plan.Placement{{ShardID: "new-1", ExecutorID: "few"}}, placements)
 	})
 
+	t.Run("prefers the executor with more headroom", func(t *testing.T) {
+		headroom := func(value string) store.HeartbeatState {
+			return store.HeartbeatState{Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataHeadroomKey: value}}
+		}
+		newState := func(small store.HeartbeatState) *store.NamespaceState {
+			return &store.NamespaceState{
+				Executors: map[string]store.HeartbeatState{
+					"big":   headroom("4"),
+					"small": small,
+				},
+				ShardAssignments: map[string]store.AssignedState{
+					"big":   {AssignedShards: map[string]*types.ShardAssignment{"s1": {}, "s2": {}}},
+					"small": {AssignedShards: map[string]*types.ShardAssignment{"s3": {}}},
+				},
+				ShardStats: map[string]store.ShardStatistics{
+					"s1": {SmoothedLoad: 5},
+					"s2": {SmoothedLoad: 5},
+					"s3": {SmoothedLoad: 5},
+				},
+			}
+		}
+
+		// big carries twice the load of small but has four times its headroom.
+		placements, _, err := PlanInitialPlacement(newState(headroom("1")), []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Placement{{ShardID: "new-1", ExecutorID: "big"}}, placements)
+
+		// Without headroom from every executor the raw loads are compared.
+		placements, _, err = PlanInitialPlacement(newState(store.HeartbeatState{Status: types.ExecutorStatusACTIVE}), []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Placement{{ShardID: "new-1", ExecutorID: "small"}}, placements)
+	})
+
+	t.Run("never picks an observer even with the lowest load", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"a":        {Status: types.ExecutorStatusACTIVE},
+				"observer": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataRoleKey: types.ExecutorRoleOBSERVER.String()}},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"a": {AssignedShards: map[string]*types.ShardAssignment{"s1": {}}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"s1": {SmoothedLoad: 10},
+			},
+		}
+
+		placements, _, err := PlanInitialPlacement(state, []string{"new-1", "new-2"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Placement{
+			{ShardID: "new-1", ExecutorID: "a"},
+			{ShardID: "new-2", ExecutorID: "a"},
+		}, placements)
+	})
+
 	t.Run("includes active executors with no assignments", func(t *testing.T) {
 		state := &store.NamespaceState{
 			Executors:        map[string]store.HeartbeatState{"new": {Status: types.ExecutorStatusACTIVE}},
 			ShardAssignments: map[string]store.AssignedState{},
 		}
 
-		placements, err := PlanInitialPlacement(state, []string{"new-1"})
+		placements, _, err := PlanInitialPlacement(state, []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
 		require.NoError(t, err)
 		assert.Equal(t, []plan.Placement{{ShardID: "new-1", ExecutorID: "new"}}, placements)
 	})
 
+	t.Run("cold start spreads shards evenly by count", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"exec-a":   {Status: types.ExecutorStatusACTIVE},
+				"exec-b":   {Status: types.ExecutorStatusACTIVE},
+				"exec-c":   {Status: types.ExecutorStatusACTIVE},
+				"draining": {Status: types.ExecutorStatusDRAINING},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"exec-b": {AssignedShards: map[string]*types.ShardAssignment{"s1": {}}},
+				"exec-c": {AssignedShards: map[string]*types.ShardAssignment{"s2": {}, "s3": {}}},
+			},
+		}
+		shardIDs := []string{"new-1", "new-2", "new-3", "new-4", "new-5", "new-6"}
+
+		placements, _, err := PlanInitialPlacement(state, shardIDs, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+
+		// Every executor ends with 3 shards. Equal counts are broken by executor ID.
+		assert.Equal(t, []plan.Placement{
+			{ShardID: "new-1", ExecutorID: "exec-a"},
+			{ShardID: "new-2", ExecutorID: "exec-a"},
+			{ShardID: "new-3", ExecutorID: "exec-b"},
+			{ShardID: "new-4", ExecutorID: "exec-a"},
+			{ShardID: "new-5", ExecutorID: "exec-b"},
+			{ShardID: "new-6", ExecutorID: "exec-c"},
+		}, placements)
+
+		again, _, err := PlanInitialPlacement(state, shardIDs, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, placements, again, "cold start placement is deterministic")
+	})
+
+	t.Run("load floor spreads shards without statistics", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"exec-a": {Status: types.ExecutorStatusACTIVE},
+				"exec-b": {Status: types.ExecutorStatusACTIVE},
+				"exec-c": {Status: types.ExecutorStatusACTIVE},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"exec-a": {AssignedShards: map[string]*types.ShardAssignment{
+					"z1": {}, "z2": {}, "z3": {}, "z4": {}, "z5": {}, "z6": {},
+				}},
+				"exec-b": {AssignedShards: map[string]*types.ShardAssignment{"s1": {}}},
+				"exec-c": {AssignedShards: map[string]*types.ShardAssignment{"s2": {}}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"s1": {SmoothedLoad: 1},
+				"s2": {SmoothedLoad: 1},
+			},
+		}
+		shardIDs := []string{"new-1", "new-2", "new-3", "new-4", "new-5", "new-6"}
+		placedPerExecutor := func(placements []plan.Placement) map[string]int {
+			counts := make(map[string]int)
+			for _, placement := range placements {
+				counts[placement.ExecutorID]++
+			}
+			return counts
+		}
+
+		// Without a floor the shards without statistics make exec-a look idle.
+		placements, _, err := PlanInitialPlacement(state, shardIDs, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 4, placedPerExecutor(placements)["exec-a"])
+
+		placements, _, err = PlanInitialPlacement(state, shardIDs, 1, 0, nil, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"exec-b": 3, "exec-c": 3}, placedPerExecutor(placements))
+	})
+
+	t.Run("shards with a label selector are only placed on matching executors", func(t *testing.T) {
+		gpu := map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"}
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"cpu-1": {Status: types.ExecutorStatusACTIVE},
+				"cpu-2": {Status: types.ExecutorStatusACTIVE},
+				"gpu-1": {Status: types.ExecutorStatusACTIVE, Metadata: gpu},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"gpu-1": {AssignedShards: map[string]*types.ShardAssignment{"busy": {}}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"busy": {SmoothedLoad: 100},
+			},
+		}
+		selectors := map[string]map[string]string{
+			"needs-gpu": {"gpu": "true"},
+			"needs-tpu": {"tpu": "true"},
+		}
+
+		placements, unplaced, err := PlanInitialPlacement(state, []string{"needs-gpu", "needs-tpu", "any"}, 0, 0, selectors, config.PlacementTieBreakShardCount, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Placement{
+			{ShardID: "needs-gpu", ExecutorID: "gpu-1"},
+			{ShardID: "any", ExecutorID: "cpu-1"},
+		}, placements, "the GPU shard skips the idle CPU executors and the unmatched shard is not placed")
+		assert.Equal(t, map[string]plan.UnassignedReason{"needs-tpu": plan.UnassignedReasonNoMatchingExecutor}, unplaced)
+	})
+
 	t.Run("empty active executors returns error", func(t *testing.T) {
-		_, err := PlanInitialPlacement(&store.NamespaceState{}, []string{"new-1"})
+		_, _, err := PlanInitialPlacement(&store.NamespaceState{}, []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
 		assert.True(t, errors.Is(err, plan.ErrNoActiveExecutors))
 	})
 }
+
+func TestPlanInitialPlacement_ExecutorLoadOverridesShardLoads(t *testing.T) {
+	// "busy" reports little load per shard but a high total load it cannot attribute to its shards.
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"busy":  {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLoadKey: "50"}},
+			"other": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"busy":  {AssignedShards: map[string]*types.ShardAssignment{"s1": {}, "s2": {}}},
+			"other": {AssignedShards: map[string]*types.ShardAssignment{"s3": {}, "s4": {}}},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"s1": {SmoothedLoad: 1},
+			"s2": {SmoothedLoad: 1},
+			"s3": {SmoothedLoad: 10},
+			"s4": {SmoothedLoad: 10},
+		},
+	}
+
+	placements, _, err := PlanInitialPlacement(state, []string{"new-1"}, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Placement{{ShardID: "new-1", ExecutorID: "other"}}, placements)
+	assert.Equal(t, 1.0, state.ShardStats["s1"].SmoothedLoad, "the state is not modified")
+}
+
+func TestWithReportedExecutorLoads(t *testing.T) {
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"weighted": {Metadata: map[string]string{store.ExecutorMetadataLoadKey: "30"}},
+			"unknown":  {Metadata: map[string]string{store.ExecutorMetadataLoadKey: "8"}},
+			"shards":   {},
+		},
+		ShardStats: map[string]store.ShardStatistics{
+			"s1": {SmoothedLoad: 1},
+			"s2": {SmoothedLoad: 1, WeightOverride: 2},
+			"s5": {SmoothedLoad: 7},
+		},
+	}
+	assignments := map[string][]string{"weighted": {"s1", "s2"}, "unknown": {"s3", "s4"}, "shards": {"s5"}}
+
+	adjusted := withReportedExecutorLoads(state, assignments)
+
+	// The reported load is split by the loads of the shards, evenly when they have none.
+	assert.Equal(t, 10.0, adjusted.ShardStats["s1"].Load())
+	assert.Equal(t, 20.0, adjusted.ShardStats["s2"].Load())
+	assert.Equal(t, 4.0, adjusted.ShardStats["s3"].Load())
+	assert.Equal(t, 4.0, adjusted.ShardStats["s4"].Load())
+	assert.Equal(t, 7.0, adjusted.ShardStats["s5"].Load())
+	assert.Len(t, state.ShardStats, 3, "the state is not modified")
+
+	assert.Same(t, state, withReportedExecutorLoads(state, map[string][]string{"shards": {"s5"}}))
+}
+
+func TestShardLoad(t *testing.T) {
+	state := &store.NamespaceState{
+		ShardStats: map[string]store.ShardStatistics{
+			"reported":   {SmoothedLoad: 3},
+			"overridden": {SmoothedLoad: 3, WeightOverride: 50},
+			"cleared":    {SmoothedLoad: 3, WeightOverride: 0},
+		},
+	}
+
+	assert.Equal(t, 3.0, shardLoad(state, "reported", 0, 0))
+	assert.Equal(t, 50.0, shardLoad(state, "overridden", 0, 0))
+	assert.Equal(t, 3.0, shardLoad(state, "cleared", 0, 0))
+	assert.Equal(t, 1.0, shardLoad(state, "unknown", 1, 0))
+	assert.Equal(t, 3.5, shardLoad(state, "reported", 0, 0.5))
+	assert.Equal(t, 1.5, shardLoad(state, "unknown", 1, 0.5))
+}
+
+func TestPlanInitialPlacement_ShardOverheadSpreadsIdleShards(t *testing.T) {
+	idleShards := make(map[string]*types.ShardAssignment)
+	shardStats := map[string]store.ShardStatistics{"busy": {SmoothedLoad: 2}}
+	for i := range 10 {
+		shardID := fmt.Sprintf("idle-%d", i)
+		idleShards[shardID] = &types.ShardAssignment{}
+		shardStats[shardID] = store.ShardStatistics{}
+	}
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"a": {Status: types.ExecutorStatusACTIVE},
+			"b": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"a": {AssignedShards: idleShards},
+			"b": {AssignedShards: map[string]*types.ShardAssignment{"busy": {}}},
+		},
+		ShardStats: shardStats,
+	}
+	shardIDs := []string{"new-1", "new-2", "new-3"}
+
+	// Without overhead the ten idle shards look free, so new shards pile onto the same executor.
+	placements, _, err := PlanInitialPlacement(state, shardIDs, 0, 0, nil, config.PlacementTieBreakShardCount, nil)
+	require.NoError(t, err)
+	for _, placement := range placements {
+		assert.Equal(t, "a", placement.ExecutorID)
+	}
+
+	placements, _, err = PlanInitialPlacement(state, shardIDs, 0, 1, nil, config.PlacementTieBreakShardCount, nil)
+	require.NoError(t, err)
+	for _, placement := range placements {
+		assert.Equal(t, "b", placement.ExecutorID)
+	}
+}
+
+func TestPlanInitialPlacement_TieBreak(t *testing.T) {
+	now := time.Now()
+	// Every executor has no load: exec-a owns an idle shard, exec-b was emptied before exec-c.
+	state := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-a": {Status: types.ExecutorStatusACTIVE},
+			"exec-b": {Status: types.ExecutorStatusACTIVE},
+			"exec-c": {Status: types.ExecutorStatusACTIVE},
+		},
+		ShardAssignments: map[string]store.AssignedState{
+			"exec-a": {AssignedShards: map[string]*types.ShardAssignment{"idle": {}}, LastUpdated: now},
+			"exec-b": {LastUpdated: now.Add(-time.Hour)},
+			"exec-c": {LastUpdated: now.Add(-time.Minute)},
+		},
+		ShardStats: map[string]store.ShardStatistics{"idle": {}},
+	}
+	place := func(tieBreak string, rng *rand.Rand) string {
+		placements, _, err := PlanInitialPlacement(state, []string{"new"}, 0, 0, nil, tieBreak, rng)
+		require.NoError(t, err)
+		require.Len(t, placements, 1)
+		return placements[0].ExecutorID
+	}
+
+	t.Run("shard count then executor ID", func(t *testing.T) {
+		assert.Equal(t, "exec-b", place(config.PlacementTieBreakShardCount, nil))
+	})
+
+	t.Run("most recently emptied", func(t *testing.T) {
+		assert.Equal(t, "exec-c", place(config.PlacementTieBreakMostRecentlyEmptied, nil))
+	})
+
+	t.Run("random is reproducible with a seed", func(t *testing.T) {
+		chosen := make(map[string]struct{})
+		for seed := range int64(20) {
+			executorID := place(config.PlacementTieBreakRandom, rand.New(rand.NewSource(seed)))
+			assert.Equal(t, executorID, place(config.PlacementTieBreakRandom, rand.New(rand.NewSource(seed))))
+			chosen[executorID] = struct{}{}
+		}
+		assert.Len(t, chosen, 3, "every tied executor can be chosen")
+	})
+
+	t.Run("random without a source falls back to shard count", func(t *testing.T) {
+		assert.Equal(t, "exec-b", place(config.PlacementTieBreakRandom, nil))
+	})
+}
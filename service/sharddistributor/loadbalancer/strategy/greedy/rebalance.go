@@ -3,14 +3,15 @@ package greedy
 import (
 	"cmp"
 	"fmt"
+	"maps"
 	"math"
+	"math/rand"
 	"slices"
 	"time"
 
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
-	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
@@ -24,18 +25,34 @@ type moveCandidate struct {
 }
 
 // PlanRebalance returns planned shard moves for the current assignment state.
+// Shards in pinnedShards are never moved. When shedRand is not nil, the shard shed from a source
+// executor is picked at random with a probability proportional to its load instead of always
+// taking the shard that improves the balance the most, so a hot shard does not bounce between
+// the same two executors. Shards their executor reports as draining are shed first, regardless of how
+// recently they moved. In the least-headroom shed selection mode the overloaded executors reporting the least
+// headroom are relieved first. Executors in their cold start are balanced with a neutral estimate of their load
+// and their shards are not shed, so their unreliable first reports do not drive moves. The total load an
+// executor reports takes precedence over the loads of its shards. In the min-movement rebalance strategy the
+// pass plans the fewest moves that bring every executor within the hysteresis bands instead. A shard with a
+// label selector is only moved to executors whose labels match it.
 func PlanRebalance(
 	cfg config.LoadBalancingGreedyConfig,
 	namespace string,
 	namespaceState *store.NamespaceState,
 	currentAssignments map[string][]string,
+	pinnedShards map[string]string,
+	selectors map[string]map[string]string,
+	shedRand *rand.Rand,
 	now time.Time,
 	logger log.Logger,
 	metricsScope metrics.Scope,
 ) ([]plan.Move, error) {
 	now = now.UTC()
+	namespaceState = withReportedExecutorLoads(namespaceState, currentAssignments)
+	overhead := shardOverhead(cfg, namespace)
 	workingAssignments := cloneAssignments(currentAssignments)
-	loads, meanLoad, ok := computeExecutorLoads(workingAssignments, namespaceState)
+	coldStart := coldStartExecutors(namespaceState, workingAssignments, now, coldStartGracePeriod(cfg, namespace))
+	loads, meanLoad, ok := computeExecutorLoads(workingAssignments, namespaceState, overhead, coldStart)
 	if !ok {
 		return nil, nil
 	}
@@ -48,27 +65,36 @@ func PlanRebalance(
 	if moveBudget <= 0 {
 		return nil, nil
 	}
-	moves := make([]plan.Move, 0, moveBudget)
-	movedShards := make(map[string]struct{})
-
-	// Plan multiple moves per cycle (within budget), recomputing eligibility after each move.
-	// Stop early once sources/destinations are empty, i.e. imbalance is within hysteresis bands.
-	for moveBudget > 0 {
-		move, moved, err := planAndApplyNextMove(cfg, namespace, namespaceState, workingAssignments, loads, meanLoad, movedShards, now)
-		if err != nil {
-			return nil, err
-		}
-		if !moved {
-			break
+	// Pinned shards are excluded the same way as shards already moved in this pass.
+	movedShards := make(map[string]struct{}, len(pinnedShards))
+	for shardID := range pinnedShards {
+		movedShards[shardID] = struct{}{}
+	}
+	for executorID := range coldStart {
+		for _, shardID := range currentAssignments[executorID] {
+			movedShards[shardID] = struct{}{}
 		}
+	}
 
-		moves = append(moves, move)
-		shardLoad := namespaceState.ShardStats[move.ShardID].SmoothedLoad
-		logGreedyMove(logger, loads, move, shardLoad)
+	initialLoads := maps.Clone(loads)
+	var moves []plan.Move
+	var err error
+	if useMinMovement(cfg, namespace) {
+		moves, err = planMinMovementMoves(cfg, namespace, namespaceState, workingAssignments, loads, meanLoad, movedShards, selectors, overhead, moveBudget, shedRand, now)
+	} else {
+		moves, err = planIncrementalMoves(cfg, namespace, namespaceState, workingAssignments, loads, meanLoad, movedShards, selectors, overhead, moveBudget, shedRand, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, move := range moves {
+		updateExecutorLoadsAfterMove(namespaceState, move.From, move.To, initialLoads, move.ShardID, overhead)
+		shardLoad := shardWeight(namespaceState.ShardStats[move.ShardID], overhead)
+		logGreedyMove(logger, initialLoads, move, shardLoad)
 		if metricsScope != nil {
 			metricsScope.UpdateGauge(metrics.ShardDistributorAssignLoopMovedShardLoad, shardLoad)
 		}
-		moveBudget--
 	}
 	if len(moves) > 0 && metricsScope != nil {
 		metricsScope.AddCounter(metrics.ShardDistributorAssignLoopLoadBasedMoves, int64(len(moves)))
@@ -76,6 +102,37 @@ func PlanRebalance(
 	return moves, nil
 }
 
+// planIncrementalMoves plans multiple moves per cycle (within budget), recomputing eligibility after each move.
+// It stops early once sources/destinations are empty, i.e. imbalance is within hysteresis bands.
+// The moves are applied to workingAssignments, loads and movedShards.
+func planIncrementalMoves(
+	cfg config.LoadBalancingGreedyConfig,
+	namespace string,
+	namespaceState *store.NamespaceState,
+	workingAssignments map[string][]string,
+	loads map[string]float64,
+	meanLoad float64,
+	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
+	shardOverhead float64,
+	moveBudget int,
+	shedRand *rand.Rand,
+	now time.Time,
+) ([]plan.Move, error) {
+	moves := make([]plan.Move, 0, moveBudget)
+	for len(moves) < moveBudget {
+		move, moved, err := planAndApplyNextMove(cfg, namespace, namespaceState, workingAssignments, loads, meanLoad, movedShards, selectors, shardOverhead, shedRand, now)
+		if err != nil {
+			return nil, err
+		}
+		if !moved {
+			break
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
 func cloneAssignments(assignments map[string][]string) map[string][]string {
 	cloned := make(map[string][]string, len(assignments))
 	for executorID, shardIDs := range assignments {
@@ -86,19 +143,51 @@ func cloneAssignments(assignments map[string][]string) map[string][]string {
 	return cloned
 }
 
-func computeExecutorLoads(currentAssignments map[string][]string, state *store.NamespaceState) (map[string]float64, float64, bool) {
+// computeExecutorLoads returns the load of every executor owning a shard with statistics, and their mean.
+// With a positive shardOverhead, shards without statistics weigh the overhead alone.
+// Every shard of an executor in coldStart weighs the average weight of the shards of the other executors
+// instead of its own. When no other executor has a shard to estimate from, the reported loads are used.
+func computeExecutorLoads(
+	currentAssignments map[string][]string,
+	state *store.NamespaceState,
+	shardOverhead float64,
+	coldStart map[string]struct{},
+) (map[string]float64, float64, bool) {
 	loads := make(map[string]float64, len(currentAssignments))
 	total := 0.0
+	weighedShards := 0
 
-	for executorID, shards := range currentAssignments {
-		for _, shardID := range shards {
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		if _, ok := coldStart[executorID]; ok {
+			continue
+		}
+		for _, shardID := range currentAssignments[executorID] {
 			stats, ok := state.ShardStats[shardID]
-			if ok {
-				loads[executorID] += stats.SmoothedLoad
-				total += stats.SmoothedLoad
+			if ok || shardOverhead > 0 {
+				weight := shardWeight(stats, shardOverhead)
+				loads[executorID] += weight
+				total += weight
+				weighedShards++
 			}
 		}
 	}
+
+	for _, executorID := range plan.SortedExecutorIDs(coldStart) {
+		for _, shardID := range currentAssignments[executorID] {
+			stats, ok := state.ShardStats[shardID]
+			if !ok && shardOverhead <= 0 {
+				continue
+			}
+			weight := shardWeight(stats, shardOverhead)
+			if weighedShards > 0 {
+				weight = total / float64(weighedShards)
+			}
+			loads[executorID] += weight
+		}
+	}
+	for _, executorID := range plan.SortedExecutorIDs(coldStart) {
+		total += loads[executorID]
+	}
 	if len(loads) == 0 {
 		return loads, 0, false
 	}
@@ -126,6 +215,9 @@ func planAndApplyNextMove(
 	loads map[string]float64,
 	meanLoad float64,
 	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
+	shardOverhead float64,
+	shedRand *rand.Rand,
 	now time.Time,
 ) (plan.Move, bool, error) {
 	sourceExecutors, destinationExecutors := classifySourcesAndDestinations(
@@ -158,8 +250,15 @@ func planAndApplyNextMove(
 		namespaceState,
 		loads,
 		movedShards,
+		selectors,
 		now,
 		cfg.PerShardCooldown(namespace),
+		plan.InCooldown,
+		moveAgingWindow(cfg, namespace),
+		preferDrainingShards(cfg, namespace),
+		relieveLeastHeadroomFirst(cfg, namespace),
+		shardOverhead,
+		shedRand,
 	)
 	if !found {
 		return plan.Move{}, false, nil
@@ -170,7 +269,7 @@ func planAndApplyNextMove(
 	}
 
 	movedShards[candidate.shardID] = struct{}{}
-	updateExecutorLoadsAfterMove(namespaceState, candidate.from, candidate.to, loads, candidate.shardID)
+	updateExecutorLoadsAfterMove(namespaceState, candidate.from, candidate.to, loads, candidate.shardID, shardOverhead)
 
 	return plan.Move{
 		ShardID: candidate.shardID,
@@ -195,8 +294,8 @@ func selectDestinationExecutor(
 			return "", false
 		}
 		allActiveExecutors := make([]string, 0, len(workingAssignments))
-		for executorID := range workingAssignments {
-			if namespaceState.Executors[executorID].Status == types.ExecutorStatusACTIVE {
+		for _, executorID := range plan.SortedExecutorIDs(workingAssignments) {
+			if executor := namespaceState.Executors[executorID]; executor.CanOwnShards() && !executor.IsStandby() {
 				allActiveExecutors = append(allActiveExecutors, executorID)
 			}
 		}
@@ -209,8 +308,8 @@ func selectDestinationExecutor(
 	return findBestDestination(destinationExecutors, loads)
 }
 
-// findNextMoveCandidate searches sources by descending load and returns the
-// first eligible source/shard pair for the destination.
+// findNextMoveCandidate searches sources by descending load, or by ascending headroom with
+// leastHeadroomFirst, and returns the first eligible source/shard pair for the destination.
 func findNextMoveCandidate(
 	sourceExecutors []string,
 	destinationExecutor string,
@@ -218,10 +317,21 @@ func findNextMoveCandidate(
 	namespaceState *store.NamespaceState,
 	loads map[string]float64,
 	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
 	now time.Time,
 	perShardCooldown time.Duration,
+	inCooldown plan.CooldownCheck,
+	agingWindow time.Duration,
+	preferDraining bool,
+	leastHeadroomFirst bool,
+	shardOverhead float64,
+	shedRand *rand.Rand,
 ) (moveCandidate, bool) {
-	sortByDescendingLoad(sourceExecutors, loads)
+	if leastHeadroomFirst {
+		sortByAscendingHeadroom(sourceExecutors, namespaceState, loads)
+	} else {
+		sortByDescendingLoad(sourceExecutors, loads)
+	}
 	for _, sourceExecutor := range sourceExecutors {
 		if sourceExecutor == destinationExecutor {
 			continue
@@ -233,8 +343,14 @@ func findNextMoveCandidate(
 			destinationExecutor,
 			loads,
 			movedShards,
+			selectors,
 			now,
 			perShardCooldown,
+			inCooldown,
+			agingWindow,
+			preferDraining,
+			shardOverhead,
+			shedRand,
 		)
 		if !found {
 			// No eligible shard for this source+destination (cooldown, or no beneficial move), try the next source.
@@ -262,12 +378,14 @@ func classifySourcesAndDestinations(
 	sources := make([]string, 0)
 	destinations := make([]string, 0)
 
-	for executorID, load := range executorLoads {
+	for _, executorID := range plan.SortedExecutorIDs(executorLoads) {
+		load := executorLoads[executorID]
 		executor := state.Executors[executorID]
-		// Intentionally allow DRAINING executors as sources so they can shed shards
+		// Intentionally allow DRAINING executors as sources so they can shed shards.
+		// Warm standbys only receive shards on failover, so they are never destinations.
 		if load > meanLoad*upperBand {
 			sources = append(sources, executorID)
-		} else if executor.Status == types.ExecutorStatusACTIVE && load < meanLoad*lowerBand {
+		} else if executor.CanOwnShards() && !executor.IsStandby() && load < meanLoad*lowerBand {
 			destinations = append(destinations, executorID)
 		}
 	}
@@ -306,7 +424,28 @@ func findBestDestination(destinationExecutors []string, executorLoads map[string
 
 func sortByDescendingLoad(executors []string, executorLoads map[string]float64) {
 	slices.SortFunc(executors, func(a, b string) int {
-		return cmp.Compare(executorLoads[b], executorLoads[a])
+		return cmp.Or(
+			cmp.Compare(executorLoads[b], executorLoads[a]),
+			cmp.Compare(a, b),
+		)
+	})
+}
+
+// sortByAscendingHeadroom orders the executors reporting headroom by ascending headroom, followed by the
+// executors that report none by descending load.
+func sortByAscendingHeadroom(executors []string, state *store.NamespaceState, executorLoads map[string]float64) {
+	headroom := func(executorID string) float64 {
+		if headroom := state.Executors[executorID].Headroom(); headroom > 0 {
+			return headroom
+		}
+		return math.Inf(1)
+	}
+	slices.SortFunc(executors, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(headroom(a), headroom(b)),
+			cmp.Compare(executorLoads[b], executorLoads[a]),
+			cmp.Compare(a, b),
+		)
 	})
 }
 
@@ -317,35 +456,63 @@ func findBestShardForMove(
 	destination string,
 	executorLoads map[string]float64,
 	movedShards map[string]struct{},
+	selectors map[string]map[string]string,
 	now time.Time,
 	perShardCooldown time.Duration,
+	inCooldown plan.CooldownCheck,
+	agingWindow time.Duration,
+	preferDraining bool,
+	shardOverhead float64,
+	shedRand *rand.Rand,
 ) (string, int, bool) {
 	bestShard := ""
+	bestDrainingShard := ""
+	drainingIdx := -1
+	bestDrainingBenefit := 0.0
+	sourceState := state.Executors[source]
+	destinationLabels := state.Executors[destination].Labels()
 
 	sourceLoad := executorLoads[source]
 	destLoad := executorLoads[destination]
 	idx := -1
 
 	bestBenefit := 0.0
+	var beneficial []int
 	for i, shard := range currentAssignments[source] {
 		if _, ok := movedShards[shard]; ok {
 			continue
 		}
+		if !plan.MatchesSelector(destinationLabels, selectors[shard]) {
+			continue
+		}
 
+		// A shard that has not reported statistics yet weighs only the per-shard overhead, like in computeExecutorLoads.
 		stats, ok := state.ShardStats[shard]
-		if !ok {
+		if !ok && shardOverhead <= 0 {
 			continue
 		}
-		if perShardCooldown > 0 && !stats.LastMoveTime.IsZero() && now.Sub(stats.LastMoveTime) < perShardCooldown {
+		if inCooldown(stats.LastMoveTime, now, perShardCooldown) {
 			continue
 		}
 
-		load := stats.SmoothedLoad
+		load := shardWeight(stats, shardOverhead)
 
-		benefit := computeBenefitOfMove(sourceLoad, destLoad, load)
+		// A draining shard is on its way out of the source, so handing it off is not held back by its last move.
+		if preferDraining && sourceState.IsShardDraining(shard) {
+			if benefit := computeBenefitOfMove(sourceLoad, destLoad, load); benefit > bestDrainingBenefit {
+				bestDrainingBenefit = benefit
+				bestDrainingShard = shard
+				drainingIdx = i
+			}
+			continue
+		}
+
+		// Recently moved shards are less willing to move again, so shards that have been stable longer are preferred.
+		benefit := computeBenefitOfMove(sourceLoad, destLoad, load) * plan.MoveEligibility(stats.LastMoveTime, now, agingWindow)
 		if benefit <= 0 {
 			continue
 		}
+		beneficial = append(beneficial, i)
 		if benefit > bestBenefit {
 			bestBenefit = benefit
 			bestShard = shard
@@ -353,9 +520,87 @@ func findBestShardForMove(
 		}
 	}
 
+	if bestDrainingShard != "" {
+		return bestDrainingShard, drainingIdx, true
+	}
+	if shedRand != nil {
+		if i, ok := pickWeightedByLoad(shedRand, state, currentAssignments[source], beneficial, shardOverhead); ok {
+			return currentAssignments[source][i], i, true
+		}
+	}
 	return bestShard, idx, bestShard != ""
 }
 
+// shardOverhead returns the configured fixed per-shard load of the namespace, or zero when it is not configured.
+func shardOverhead(cfg config.LoadBalancingGreedyConfig, namespace string) float64 {
+	if cfg.ShardOverhead == nil {
+		return 0
+	}
+	return max(cfg.ShardOverhead(namespace), 0)
+}
+
+// coldStartGracePeriod returns the configured cold start grace period of the namespace, or zero when it is not configured.
+func coldStartGracePeriod(cfg config.LoadBalancingGreedyConfig, namespace string) time.Duration {
+	if cfg.ColdStartGracePeriod == nil {
+		return 0
+	}
+	return max(cfg.ColdStartGracePeriod(namespace), 0)
+}
+
+// coldStartExecutors returns the executors of currentAssignments that registered less than grace before now.
+func coldStartExecutors(state *store.NamespaceState, currentAssignments map[string][]string, now time.Time, grace time.Duration) map[string]struct{} {
+	coldStart := make(map[string]struct{})
+	for executorID := range currentAssignments {
+		if state.Executors[executorID].InColdStart(now, grace) {
+			coldStart[executorID] = struct{}{}
+		}
+	}
+	return coldStart
+}
+
+// moveAgingWindow returns the configured move aging window of the namespace, or zero when it is not configured.
+func moveAgingWindow(cfg config.LoadBalancingGreedyConfig, namespace string) time.Duration {
+	if cfg.MoveAgingWindow == nil {
+		return 0
+	}
+	return max(cfg.MoveAgingWindow(namespace), 0)
+}
+
+// preferDrainingShards returns whether shards reported as draining are shed first, which is the default.
+func preferDrainingShards(cfg config.LoadBalancingGreedyConfig, namespace string) bool {
+	if cfg.ExcludeDrainingShardLoad == nil {
+		return true
+	}
+	return cfg.ExcludeDrainingShardLoad(namespace)
+}
+
+// relieveLeastHeadroomFirst returns whether the overloaded executors with the least headroom are relieved first.
+func relieveLeastHeadroomFirst(cfg config.LoadBalancingGreedyConfig, namespace string) bool {
+	return cfg.ShedSelectionMode != nil && cfg.ShedSelectionMode(namespace) == config.ShedSelectionLeastHeadroom
+}
+
+// pickWeightedByLoad picks one of the candidate indexes into shardIDs with a probability proportional
+// to the load of the shard. It returns false when the candidates have no load to weigh them by.
+func pickWeightedByLoad(rng *rand.Rand, state *store.NamespaceState, shardIDs []string, candidates []int, shardOverhead float64) (int, bool) {
+	totalLoad := 0.0
+	for _, i := range candidates {
+		totalLoad += shardWeight(state.ShardStats[shardIDs[i]], shardOverhead)
+	}
+	if totalLoad <= 0 {
+		return 0, false
+	}
+
+	target := rng.Float64() * totalLoad
+	for _, i := range candidates {
+		target -= shardWeight(state.ShardStats[shardIDs[i]], shardOverhead)
+		if target < 0 {
+			return i, true
+		}
+	}
+	// Rounding can leave a tiny remainder, the last candidate takes it.
+	return candidates[len(candidates)-1], true
+}
+
 // computeBenefitOfMove returns the reduction in squared executor load from
 // moving shardLoad from source to destination. Positive values mean the move
 // improves balance between the two executors.
@@ -388,13 +633,14 @@ func updateExecutorLoadsAfterMove(
 	destination string,
 	executorLoads map[string]float64,
 	shardID string,
+	shardOverhead float64,
 ) {
 	stats, ok := state.ShardStats[shardID]
-	if !ok {
+	if !ok && shardOverhead <= 0 {
 		return
 	}
-	executorLoads[source] -= stats.SmoothedLoad
-	executorLoads[destination] += stats.SmoothedLoad
+	executorLoads[source] -= shardWeight(stats, shardOverhead)
+	executorLoads[destination] += shardWeight(stats, shardOverhead)
 }
 
 func logGreedyMove(logger log.Logger, loads map[string]float64, move plan.Move, shardLoad float64) {
@@ -2,6 +2,7 @@ package greedy
 
 import (
 	"fmt"
+	"math/rand"
 	"slices"
 	"testing"
 	"time"
@@ -77,7 +78,7 @@ func TestLoadBalance_Convergence(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -85,6 +86,68 @@ func TestLoadBalance_Convergence(t *testing.T) {
 	assert.Greater(t, len(currentAssignments[execB]), 50, "Underloaded executor should receive shards")
 }
 
+// TestLoadBalance_ShardOverheadSpreadsIdleShards verifies that with a per-shard overhead, idle shards count towards load.
+func TestLoadBalance_ShardOverheadSpreadsIdleShards(t *testing.T) {
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(string) float64 { return 0.5 }
+
+	execA, execB := "exec-A", "exec-B"
+	currentAssignments := map[string][]string{execA: {}, execB: {"B-0", "B-1"}}
+	shardStats := map[string]store.ShardStatistics{"B-0": {}, "B-1": {}}
+	for i := range 20 {
+		sID := fmt.Sprintf("A-%d", i)
+		currentAssignments[execA] = append(currentAssignments[execA], sID)
+		shardStats[sID] = store.ShardStatistics{}
+	}
+	now := time.Now().UTC()
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardStats: shardStats,
+	}
+
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Empty(t, moves, "idle shards are free without an overhead")
+
+	cfg.ShardOverhead = func(string) float64 { return 1 }
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	applyMoves(t, currentAssignments, moves)
+	assert.InDelta(t, len(currentAssignments[execA]), len(currentAssignments[execB]), 4)
+}
+
+// TestLoadBalance_ShardOverheadMovesShardsWithoutStats verifies that shards that never reported statistics
+// weigh the per-shard overhead and are moved like idle shards with statistics.
+func TestLoadBalance_ShardOverheadMovesShardsWithoutStats(t *testing.T) {
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(string) float64 { return 0.5 }
+	cfg.ShardOverhead = func(string) float64 { return 1 }
+
+	execA, execB := "exec-A", "exec-B"
+	currentAssignments := map[string][]string{execA: {}, execB: {"B-0", "B-1"}}
+	for i := range 20 {
+		currentAssignments[execA] = append(currentAssignments[execA], fmt.Sprintf("A-%d", i))
+	}
+	now := time.Now().UTC()
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+		},
+		ShardStats: map[string]store.ShardStatistics{},
+	}
+
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	applyMoves(t, currentAssignments, moves)
+	assert.InDelta(t, len(currentAssignments[execA]), len(currentAssignments[execB]), 4)
+}
+
 // TestLoadBalance_SkipsNonBeneficialHotShard verifies we skip hot shards that would not improve balance.
 func TestLoadBalance_SkipsNonBeneficialHotShard(t *testing.T) {
 	cfg := testGreedyConfig()
@@ -115,7 +178,7 @@ func TestLoadBalance_SkipsNonBeneficialHotShard(t *testing.T) {
 		},
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -123,6 +186,169 @@ func TestLoadBalance_SkipsNonBeneficialHotShard(t *testing.T) {
 	assert.False(t, slices.Contains(currentAssignments[execB], "hot"))
 }
 
+// TestLoadBalance_WeightedRandomShedSelection verifies the weighted-random shed picks among the beneficial
+// shards in proportion to their load, and is reproducible for a seeded source.
+func TestLoadBalance_WeightedRandomShedSelection(t *testing.T) {
+	cfg := testGreedyConfig()
+
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+	newState := func() (*store.NamespaceState, map[string][]string) {
+		currentAssignments := map[string][]string{
+			execA: {"s-0", "s-1", "s-2", "s-3", "s-4"},
+			execB: {"b-1"},
+		}
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+				execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"s-0": {SmoothedLoad: 0, LastUpdateTime: now},
+				"s-1": {SmoothedLoad: 1, LastUpdateTime: now},
+				"s-2": {SmoothedLoad: 2, LastUpdateTime: now},
+				"s-3": {SmoothedLoad: 3, LastUpdateTime: now},
+				"s-4": {SmoothedLoad: 4, LastUpdateTime: now},
+				"b-1": {SmoothedLoad: 0.5, LastUpdateTime: now},
+			},
+		}, currentAssignments
+	}
+	planWithSeed := func(seed int64) []plan.Move {
+		state, currentAssignments := newState()
+		moves, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, rand.New(rand.NewSource(seed)), now, log.NewNoop(), metrics.NoopScope)
+		require.NoError(t, err)
+		require.Len(t, moves, 1)
+		return moves
+	}
+
+	t.Run("without a source the heaviest shard is shed", func(t *testing.T) {
+		state, currentAssignments := newState()
+		moves, err := PlanRebalance(cfg, testNamespace, state, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Move{{ShardID: "s-4", From: execA, To: execB}}, moves)
+	})
+
+	t.Run("same seed gives the same moves", func(t *testing.T) {
+		assert.Equal(t, planWithSeed(42), planWithSeed(42))
+	})
+
+	t.Run("picks are weighted by load", func(t *testing.T) {
+		picks := make(map[string]int)
+		for seed := range int64(400) {
+			moves := planWithSeed(seed)
+			assert.Equal(t, execA, moves[0].From)
+			assert.Equal(t, execB, moves[0].To)
+			picks[moves[0].ShardID]++
+		}
+
+		assert.Zero(t, picks["s-0"], "a shard without load is never picked")
+		for _, shardID := range []string{"s-1", "s-2", "s-3", "s-4"} {
+			assert.Positive(t, picks[shardID], "every loaded shard can be picked: %s", shardID)
+		}
+		assert.Greater(t, picks["s-4"], picks["s-1"])
+	})
+}
+
+// TestLoadBalance_NeverMovesPinnedShards verifies pinned shards are never donated, even when moving them would help.
+func TestLoadBalance_NeverMovesPinnedShards(t *testing.T) {
+	cfg := testGreedyConfig()
+
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+
+	newState := func() (*store.NamespaceState, map[string][]string) {
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+				execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				execA: {AssignedShards: map[string]*types.ShardAssignment{"warm-1": {}, "warm-2": {}, "pinned": {}}},
+				execB: {AssignedShards: map[string]*types.ShardAssignment{"b-1": {}}},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"warm-1": {SmoothedLoad: 2, LastUpdateTime: now},
+				"warm-2": {SmoothedLoad: 2, LastUpdateTime: now},
+				"pinned": {SmoothedLoad: 4, LastUpdateTime: now},
+				"b-1":    {SmoothedLoad: 1, LastUpdateTime: now},
+			},
+		}, map[string][]string{
+			execA: {"warm-1", "warm-2", "pinned"},
+			execB: {"b-1"},
+		}
+	}
+
+	// Without pinning the heaviest shard is the most beneficial move.
+	namespaceState, currentAssignments := newState()
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Equal(t, []plan.Move{{ShardID: "pinned", From: execA, To: execB}}, moves)
+
+	namespaceState, currentAssignments = newState()
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, map[string]string{"pinned": ""}, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	for _, move := range moves {
+		assert.NotEqual(t, "pinned", move.ShardID)
+	}
+
+	// Pinning every shard of the overloaded executor leaves nothing to move.
+	namespaceState, currentAssignments = newState()
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, map[string]string{"pinned": "", "warm-1": execA, "warm-2": ""}, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Empty(t, moves)
+}
+
+// TestLoadBalance_HonorsLabelSelectors verifies shards are only moved to executors matching their label selector.
+func TestLoadBalance_HonorsLabelSelectors(t *testing.T) {
+	cfg := testGreedyConfig()
+
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+	selectors := map[string]map[string]string{"gpu": {"gpu": "true"}}
+
+	newState := func(execBMetadata map[string]string) (*store.NamespaceState, map[string][]string) {
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+				execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: execBMetadata},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"warm-1": {SmoothedLoad: 2, LastUpdateTime: now},
+				"warm-2": {SmoothedLoad: 2, LastUpdateTime: now},
+				"gpu":    {SmoothedLoad: 4, LastUpdateTime: now},
+				"b-1":    {SmoothedLoad: 1, LastUpdateTime: now},
+			},
+		}, map[string][]string{
+			execA: {"warm-1", "warm-2", "gpu"},
+			execB: {"b-1"},
+		}
+	}
+
+	// The heaviest shard is the most beneficial move, but exec-B does not match its selector.
+	namespaceState, currentAssignments := newState(nil)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, selectors, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	for _, move := range moves {
+		assert.NotEqual(t, "gpu", move.ShardID)
+	}
+
+	namespaceState, currentAssignments = newState(map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"})
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, selectors, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Move{{ShardID: "gpu", From: execA, To: execB}}, moves)
+
+	// The min-movement strategy honors the selectors too.
+	cfg.RebalanceStrategy = func(namespace string) string { return config.RebalanceStrategyMinMovement }
+	namespaceState, currentAssignments = newState(nil)
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, selectors, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	for _, move := range moves {
+		assert.NotEqual(t, "gpu", move.ShardID)
+	}
+}
+
 // TestLoadBalance_NoMoveNeeded verifies the balancer does nothing when already within hysteresis bands.
 func TestLoadBalance_NoMoveNeeded(t *testing.T) {
 	cfg := testGreedyConfig()
@@ -161,7 +387,7 @@ func TestLoadBalance_NoMoveNeeded(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.Empty(t, moves)
 	assert.Len(t, currentAssignments[execA], 51)
@@ -229,7 +455,7 @@ func TestLoadBalance_SevereImbalance_AllowsMoveWithoutDestinations(t *testing.T)
 	initialOther := len(currentAssignments[execB]) + len(currentAssignments[execC]) + len(currentAssignments[execD]) + len(currentAssignments[execE])
 	expectedBudget := computeMoveBudget(len(shardStats), cfg.MoveBudgetProportion(testNamespace))
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -239,6 +465,87 @@ func TestLoadBalance_SevereImbalance_AllowsMoveWithoutDestinations(t *testing.T)
 	assert.Equal(t, initialOther+expectedBudget, totalOther, "Destinations should gain budgeted shards")
 }
 
+// TestLoadBalance_NeverMovesToStandbys verifies warm standbys are not used as destinations, even when they are
+// the coldest executors.
+func TestLoadBalance_NeverMovesToStandbys(t *testing.T) {
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(string) float64 { return 0.5 }
+	now := time.Now().UTC()
+	standbyRole := map[string]string{store.ExecutorMetadataRoleKey: types.ExecutorRoleSTANDBY.String()}
+
+	currentAssignments := map[string][]string{
+		"exec-A":  {},
+		"exec-B":  {"b-1"},
+		"standby": {"s-1"},
+	}
+	shardStats := map[string]store.ShardStatistics{
+		"b-1": {SmoothedLoad: 5, LastUpdateTime: now},
+		"s-1": {SmoothedLoad: 1, LastUpdateTime: now},
+	}
+	for i := range 6 {
+		shardID := fmt.Sprintf("a-%d", i)
+		currentAssignments["exec-A"] = append(currentAssignments["exec-A"], shardID)
+		shardStats[shardID] = store.ShardStatistics{SmoothedLoad: 10, LastUpdateTime: now}
+	}
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-A":  {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-B":  {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"standby": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: standbyRole},
+		},
+		ShardStats: shardStats,
+	}
+
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	for _, move := range moves {
+		assert.Equal(t, "exec-B", move.To)
+	}
+}
+
+func TestLoadBalance_ColdStartExecutorLoadIsProvisional(t *testing.T) {
+	cfg := testGreedyConfig()
+	cfg.MoveBudgetProportion = func(string) float64 { return 0.5 }
+	cfg.ColdStartGracePeriod = func(string) time.Duration { return 5 * time.Minute }
+	now := time.Now().UTC()
+	registered := map[string]string{store.ExecutorMetadataRegisteredAtKey: now.Add(-time.Minute).Format(time.RFC3339Nano)}
+
+	// exec-new registered a minute ago and its cold caches inflate the loads it reports.
+	currentAssignments := map[string][]string{"exec-A": {}, "exec-B": {}, "exec-new": {}}
+	shardStats := make(map[string]store.ShardStatistics)
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		for i := range 3 {
+			shardID := fmt.Sprintf("%s-%d", executorID, i)
+			currentAssignments[executorID] = append(currentAssignments[executorID], shardID)
+			shardStats[shardID] = store.ShardStatistics{SmoothedLoad: 10, LastUpdateTime: now}
+			if executorID == "exec-new" {
+				shardStats[shardID] = store.ShardStatistics{SmoothedLoad: 100, LastUpdateTime: now}
+			}
+		}
+	}
+	namespaceState := &store.NamespaceState{
+		Executors: map[string]store.HeartbeatState{
+			"exec-A":   {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-B":   {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			"exec-new": {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: registered},
+		},
+		ShardStats: shardStats,
+	}
+
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Empty(t, moves, "the reports of an executor in its cold start do not drive moves")
+
+	// Once the grace period is over its reports are trusted and it sheds load.
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now.Add(5*time.Minute), log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.NotEmpty(t, moves)
+	for _, move := range moves {
+		assert.Equal(t, "exec-new", move.From)
+	}
+}
+
 // TestLoadBalance_NoDestinations_NotSevere verifies we do not relax destinations without severe imbalance.
 func TestLoadBalance_NoDestinations_NotSevere(t *testing.T) {
 	cfg := testGreedyConfig()
@@ -280,7 +587,7 @@ func TestLoadBalance_NoDestinations_NotSevere(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.Empty(t, moves)
 	assert.Len(t, currentAssignments[execA], 10)
@@ -351,7 +658,7 @@ func TestLoadBalance_BudgetConstraint(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -405,7 +712,7 @@ func TestLoadBalance_MultiMovePerCycle(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -455,7 +762,7 @@ func TestLoadBalance_PerShardCooldownSkipsHotShard(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
@@ -463,6 +770,232 @@ func TestLoadBalance_PerShardCooldownSkipsHotShard(t *testing.T) {
 	assert.False(t, slices.Contains(currentAssignments[execB], "hot-1"), "recently moved shard should not move")
 }
 
+// TestLoadBalance_MoveAgingPrefersStableShards verifies that with a move aging window a recently moved
+// shard is less willing to move, so a shard that has been stable longer is shed instead.
+func TestLoadBalance_MoveAgingPrefersStableShards(t *testing.T) {
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+
+	newState := func() (*store.NamespaceState, map[string][]string) {
+		currentAssignments := map[string][]string{
+			execA: {"hot-1", "hot-2", "a-1", "a-2", "a-3"},
+			execB: {"b-1", "b-2", "b-3", "b-4", "b-5"},
+		}
+		shardStats := map[string]store.ShardStatistics{
+			"hot-1": {SmoothedLoad: 10.0, LastUpdateTime: now, LastMoveTime: now.Add(-time.Minute)},
+			"hot-2": {SmoothedLoad: 9.0, LastUpdateTime: now},
+			"a-1":   {SmoothedLoad: 1.0, LastUpdateTime: now},
+			"a-2":   {SmoothedLoad: 1.0, LastUpdateTime: now},
+			"a-3":   {SmoothedLoad: 1.0, LastUpdateTime: now},
+			"b-1":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-2":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-3":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-4":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-5":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+		}
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+				execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			},
+			ShardStats: shardStats,
+		}, currentAssignments
+	}
+
+	cfg := testGreedyConfig()
+	cfg.PerShardCooldown = func(namespace string) time.Duration { return 0 }
+
+	// Without aging the hottest shard gives the largest benefit.
+	namespaceState, currentAssignments := newState()
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "hot-1", moves[0].ShardID)
+
+	cfg.MoveAgingWindow = func(namespace string) time.Duration { return 10 * time.Minute }
+	namespaceState, currentAssignments = newState()
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "hot-2", moves[0].ShardID)
+}
+
+// TestLoadBalance_DrainingShardsAreShedFirst verifies that a shard reported as draining is handed off
+// ahead of shards with a larger benefit, even when it moved recently, unless the preference is disabled.
+func TestLoadBalance_DrainingShardsAreShedFirst(t *testing.T) {
+	execA, execB := "exec-A", "exec-B"
+	now := time.Now().UTC()
+
+	newState := func() (*store.NamespaceState, map[string][]string) {
+		currentAssignments := map[string][]string{
+			execA: {"hot-1", "draining", "a-1", "a-2"},
+			execB: {"b-1", "b-2", "b-3", "b-4"},
+		}
+		shardStats := map[string]store.ShardStatistics{
+			"hot-1":    {SmoothedLoad: 10.0, LastUpdateTime: now},
+			"draining": {SmoothedLoad: 5.0, LastUpdateTime: now, LastMoveTime: now.Add(-time.Minute)},
+			"a-1":      {SmoothedLoad: 1.0, LastUpdateTime: now},
+			"a-2":      {SmoothedLoad: 1.0, LastUpdateTime: now},
+			"b-1":      {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-2":      {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-3":      {SmoothedLoad: 0.1, LastUpdateTime: now},
+			"b-4":      {SmoothedLoad: 0.1, LastUpdateTime: now},
+		}
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execA: {
+					Status:        types.ExecutorStatusACTIVE,
+					LastHeartbeat: now,
+					ReportedShards: map[string]*types.ShardStatusReport{
+						"hot-1":    {Status: types.ShardStatusREADY},
+						"draining": {Status: types.ShardStatusDRAINING},
+					},
+				},
+				execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+			},
+			ShardStats: shardStats,
+		}, currentAssignments
+	}
+
+	cfg := testGreedyConfig()
+	cfg.PerShardCooldown = func(namespace string) time.Duration { return 0 }
+	cfg.MoveAgingWindow = func(namespace string) time.Duration { return 10 * time.Minute }
+
+	namespaceState, currentAssignments := newState()
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "draining", moves[0].ShardID)
+
+	cfg.ExcludeDrainingShardLoad = func(namespace string) bool { return false }
+	namespaceState, currentAssignments = newState()
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "hot-1", moves[0].ShardID)
+}
+
+// TestLoadBalance_LeastHeadroomShedSelection verifies that the least-headroom shed selection mode relieves an
+// overloaded executor close to its limit before a more loaded one that still has headroom.
+func TestLoadBalance_LeastHeadroomShedSelection(t *testing.T) {
+	execLow, execHigh, execIdle := "exec-low-headroom", "exec-high-headroom", "exec-idle"
+	now := time.Now().UTC()
+	headroom := func(value string) map[string]string {
+		return map[string]string{store.ExecutorMetadataHeadroomKey: value}
+	}
+
+	newState := func() (*store.NamespaceState, map[string][]string) {
+		currentAssignments := map[string][]string{
+			execLow:  {"low-1", "low-2"},
+			execHigh: {"high-1", "high-2"},
+			execIdle: {"idle-1"},
+		}
+		return &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				execLow:  {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: headroom("0.05")},
+				execHigh: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: headroom("0.6")},
+				execIdle: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now, Metadata: headroom("0.9")},
+			},
+			ShardStats: map[string]store.ShardStatistics{
+				"low-1":  {SmoothedLoad: 6.0, LastUpdateTime: now},
+				"low-2":  {SmoothedLoad: 6.0, LastUpdateTime: now},
+				"high-1": {SmoothedLoad: 8.0, LastUpdateTime: now},
+				"high-2": {SmoothedLoad: 7.0, LastUpdateTime: now},
+				"idle-1": {SmoothedLoad: 3.0, LastUpdateTime: now},
+			},
+		}, currentAssignments
+	}
+
+	cfg := testGreedyConfig()
+	cfg.PerShardCooldown = func(namespace string) time.Duration { return 0 }
+
+	// By default the most loaded executor is relieved first.
+	namespaceState, currentAssignments := newState()
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, execHigh, moves[0].From)
+
+	cfg.ShedSelectionMode = func(namespace string) string { return config.ShedSelectionLeastHeadroom }
+	namespaceState, currentAssignments = newState()
+	moves, err = PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, execLow, moves[0].From)
+	assert.Equal(t, execIdle, moves[0].To)
+}
+
+func TestSortByAscendingHeadroom(t *testing.T) {
+	state := &store.NamespaceState{Executors: map[string]store.HeartbeatState{
+		"a": {Metadata: map[string]string{store.ExecutorMetadataHeadroomKey: "0.5"}},
+		"b": {Metadata: map[string]string{store.ExecutorMetadataHeadroomKey: "0.1"}},
+		"c": {},
+		"d": {},
+	}}
+	executors := []string{"a", "b", "c", "d"}
+
+	sortByAscendingHeadroom(executors, state, map[string]float64{"a": 1, "b": 1, "c": 2, "d": 5})
+	assert.Equal(t, []string{"b", "a", "d", "c"}, executors)
+}
+
+// TestLoadBalance_CooldownToleratesClockSkew verifies that a move time written by a node with a skewed
+// clock neither lets a shard move early nor pins it forever.
+func TestLoadBalance_CooldownToleratesClockSkew(t *testing.T) {
+	cfg := testGreedyConfig()
+	cooldown := cfg.PerShardCooldown(testNamespace)
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name         string
+		lastMoveTime time.Time
+		expectMoved  bool
+	}{
+		{
+			name:         "move time slightly in the future is treated as just moved",
+			lastMoveTime: now.Add(cooldown / 2),
+			expectMoved:  false,
+		},
+		{
+			name:         "move time far in the future is treated as eligible",
+			lastMoveTime: now.Add(100 * cooldown),
+			expectMoved:  true,
+		},
+		{
+			name:         "move time far in the past is eligible",
+			lastMoveTime: time.Unix(0, 0).UTC(),
+			expectMoved:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execA, execB := "exec-A", "exec-B"
+			currentAssignments := map[string][]string{
+				execA: {"hot-1", "a-1", "a-2"},
+				execB: {"b-1", "b-2"},
+			}
+			namespaceState := &store.NamespaceState{
+				Executors: map[string]store.HeartbeatState{
+					execA: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+					execB: {Status: types.ExecutorStatusACTIVE, LastHeartbeat: now},
+				},
+				ShardStats: map[string]store.ShardStatistics{
+					"hot-1": {SmoothedLoad: 10.0, LastUpdateTime: now, LastMoveTime: tt.lastMoveTime},
+					"a-1":   {SmoothedLoad: 1.0, LastUpdateTime: now},
+					"a-2":   {SmoothedLoad: 1.0, LastUpdateTime: now},
+					"b-1":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+					"b-2":   {SmoothedLoad: 0.1, LastUpdateTime: now},
+				},
+			}
+
+			moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
+			require.NoError(t, err)
+			movedHot := slices.ContainsFunc(moves, func(move plan.Move) bool { return move.ShardID == "hot-1" })
+			assert.Equal(t, tt.expectMoved, movedHot)
+		})
+	}
+}
+
 // TestLoadBalance_NoDestinations verifies no moves are made when no executor is eligible as a destination.
 func TestLoadBalance_NoDestinations(t *testing.T) {
 	cfg := testGreedyConfig()
@@ -507,7 +1040,7 @@ func TestLoadBalance_NoDestinations(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.Empty(t, moves)
 	assert.Equal(t, []string{"s1"}, currentAssignments[execA])
@@ -563,7 +1096,7 @@ func TestLoadBalance_ExecutorRemovedFromDestination(t *testing.T) {
 		ShardStats:       shardStats,
 	}
 
-	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, now, log.NewNoop(), metrics.NoopScope)
+	moves, err := PlanRebalance(cfg, testNamespace, namespaceState, currentAssignments, nil, nil, nil, now, log.NewNoop(), metrics.NoopScope)
 	require.NoError(t, err)
 	require.NotEmpty(t, moves)
 	applyMoves(t, currentAssignments, moves)
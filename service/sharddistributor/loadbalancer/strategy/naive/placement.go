@@ -5,32 +5,59 @@ import (
 	"maps"
 	"slices"
 
-	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
 // PlanInitialPlacement returns planned placements for a batch of unassigned shards.
-func PlanInitialPlacement(state *store.NamespaceState, shardIDs []string) ([]plan.Placement, error) {
+// A shard with a label selector is only placed on executors whose labels match it. Shards no active
+// executor matches are left out of the placements and returned in unplaced.
+func PlanInitialPlacement(
+	state *store.NamespaceState,
+	shardIDs []string,
+	selectors map[string]map[string]string,
+) (placements []plan.Placement, unplaced map[string]plan.UnassignedReason, err error) {
 	counts := assignmentCounts(state)
-	placements := make([]plan.Placement, 0, len(shardIDs))
+	placements = make([]plan.Placement, 0, len(shardIDs))
+	unplaced = make(map[string]plan.UnassignedReason)
 	for _, shardID := range shardIDs {
-		executorID, err := chooseExecutorAndUpdateCounts(counts)
+		candidates := counts
+		if selector := selectors[shardID]; len(selector) > 0 && len(counts) > 0 {
+			candidates = matchingExecutorCounts(state, counts, selector)
+			if len(candidates) == 0 {
+				unplaced[shardID] = plan.UnassignedReasonNoMatchingExecutor
+				continue
+			}
+		}
+		executorID, err := chooseExecutorAndUpdateCounts(candidates)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		// chooseExecutorAndUpdateCounts updated the count of the chosen executor in candidates, which may be a subset of counts.
+		counts[executorID] = candidates[executorID]
 		placements = append(placements, plan.Placement{
 			ShardID:    shardID,
 			ExecutorID: executorID,
 		})
 	}
-	return placements, nil
+	return placements, unplaced, nil
+}
+
+// matchingExecutorCounts returns the counts of the executors whose labels match selector.
+func matchingExecutorCounts(state *store.NamespaceState, counts map[string]int, selector map[string]string) map[string]int {
+	matching := make(map[string]int)
+	for executorID, count := range counts {
+		if plan.MatchesSelector(state.Executors[executorID].Labels(), selector) {
+			matching[executorID] = count
+		}
+	}
+	return matching
 }
 
 func assignmentCounts(state *store.NamespaceState) map[string]int {
 	counts := make(map[string]int, len(state.Executors))
 	for executorID, executorState := range state.Executors {
-		if executorState.Status != types.ExecutorStatusACTIVE {
+		if !executorState.CanOwnShards() {
 			continue
 		}
 		counts[executorID] = len(state.ShardAssignments[executorID].AssignedShards)
@@ -27,7 +27,7 @@ func TestPlanInitialPlacement(t *testing.T) {
 			},
 		}
 
-		placements, err := PlanInitialPlacement(state, []string{"new-1", "new-2", "new-3"})
+		placements, _, err := PlanInitialPlacement(state, []string{"new-1", "new-2", "new-3"}, nil)
 		require.NoError(t, err)
 
 		// b has fewer shards, so the first new shard goes there.
@@ -40,9 +40,34 @@ func TestPlanInitialPlacement(t *testing.T) {
 	})
 
 	t.Run("empty active executors returns error", func(t *testing.T) {
-		_, err := PlanInitialPlacement(&store.NamespaceState{
+		_, _, err := PlanInitialPlacement(&store.NamespaceState{
 			Executors: map[string]store.HeartbeatState{"a": {Status: types.ExecutorStatusDRAINING}},
-		}, []string{"new-1"})
+		}, []string{"new-1"}, nil)
 		assert.True(t, errors.Is(err, plan.ErrNoActiveExecutors))
 	})
+
+	t.Run("shards with a label selector are only placed on matching executors", func(t *testing.T) {
+		state := &store.NamespaceState{
+			Executors: map[string]store.HeartbeatState{
+				"a": {Status: types.ExecutorStatusACTIVE, Metadata: map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"}},
+				"b": {Status: types.ExecutorStatusACTIVE},
+			},
+			ShardAssignments: map[string]store.AssignedState{
+				"a": {AssignedShards: map[string]*types.ShardAssignment{"s1": {}, "s2": {}}},
+			},
+		}
+		selectors := map[string]map[string]string{
+			"gpu-1": {"gpu": "true"},
+			"tpu-1": {"tpu": "true"},
+		}
+
+		placements, unplaced, err := PlanInitialPlacement(state, []string{"gpu-1", "new-1", "tpu-1"}, selectors)
+		require.NoError(t, err)
+		// b has fewer shards, but only a matches gpu-1. No executor matches tpu-1.
+		assert.Equal(t, []plan.Placement{
+			{ShardID: "gpu-1", ExecutorID: "a"},
+			{ShardID: "new-1", ExecutorID: "b"},
+		}, placements)
+		assert.Equal(t, map[string]plan.UnassignedReason{"tpu-1": plan.UnassignedReasonNoMatchingExecutor}, unplaced)
+	})
 }
@@ -13,11 +13,14 @@ import (
 )
 
 // PlanRebalance returns planned shard moves for the current assignment state.
+// Shards in pinnedShards are never moved, and shards are only moved to executors matching their label selector.
 func PlanRebalance(
 	cfg config.LoadBalancingNaiveConfig,
 	namespace string,
 	state *store.NamespaceState,
 	currentAssignments map[string][]string,
+	pinnedShards map[string]string,
+	selectors map[string]map[string]string,
 	logger log.Logger,
 	metricsScope metrics.Scope,
 ) ([]plan.Move, error) {
@@ -39,9 +42,10 @@ func PlanRebalance(
 		coldestExecutorID   = ""
 	)
 
-	// finding loads of hottest, coldest executors and hottest shard
+	// finding loads of hottest and coldest executors
 	executorLoad := make(map[string]float64)
-	for executorID, shardIDs := range currentAssignments {
+	for _, executorID := range plan.SortedExecutorIDs(currentAssignments) {
+		shardIDs := currentAssignments[executorID]
 		for _, shardID := range shardIDs {
 			executorLoad[executorID] += shardLoad[shardID]
 		}
@@ -54,15 +58,21 @@ func PlanRebalance(
 		if executorLoad[executorID] >= hottestExecutorLoad {
 			hottestExecutorLoad = executorLoad[executorID]
 			hottestExecutorID = executorID
+		}
+	}
 
-			var maxShardLoad = float64(0)
-			for _, shardID := range shardIDs {
-				if shardLoad[shardID] >= maxShardLoad {
-					hottestShardID = shardID
-					maxShardLoad = shardLoad[shardID]
-				}
-			}
-			hottestShardLoad = maxShardLoad
+	// The hottest shard is picked among those the coldest executor can take.
+	coldestLabels := state.Executors[coldestExecutorID].Labels()
+	for _, shardID := range currentAssignments[hottestExecutorID] {
+		if _, pinned := pinnedShards[shardID]; pinned {
+			continue
+		}
+		if !plan.MatchesSelector(coldestLabels, selectors[shardID]) {
+			continue
+		}
+		if shardLoad[shardID] >= hottestShardLoad {
+			hottestShardID = shardID
+			hottestShardLoad = shardLoad[shardID]
 		}
 	}
 
@@ -71,6 +81,11 @@ func PlanRebalance(
 		return nil, nil
 	}
 
+	// no rebalance if no shard of the hottest executor can move to the coldest one
+	if hottestShardID == "" {
+		return nil, nil
+	}
+
 	// no rebalance if coldest executor becomes a hottest
 	if coldestExecutorLoad+hottestShardLoad >= hottestExecutorLoad {
 		return nil, nil
@@ -101,8 +116,8 @@ func PlanRebalance(
 // calcShardLoad returns a map of shardID to its load based on the latest reported shard loads from executors
 func calcShardLoad(namespaceState *store.NamespaceState) map[string]float64 {
 	shardLoad := make(map[string]float64)
-	for _, state := range namespaceState.Executors {
-		for shardID, report := range state.ReportedShards {
+	for _, executorID := range plan.SortedExecutorIDs(namespaceState.Executors) {
+		for shardID, report := range namespaceState.Executors[executorID].ReportedShards {
 			if report != nil {
 				shardLoad[shardID] = report.ShardLoad
 			}
@@ -45,6 +45,7 @@ func TestPlanRebalanceNaiveByReportedLoad(t *testing.T) {
 		name                       string
 		shardLoad                  map[string]float64
 		currentAssignments         map[string][]string
+		pinnedShards               map[string]string
 		maxDeviation               float64
 		expectedDistributionChange bool
 		expectedMoves              []plan.Move
@@ -222,6 +223,37 @@ func TestPlanRebalanceNaiveByReportedLoad(t *testing.T) {
 			// Planned move would leave exec-1 at 10.0, exec-2 at 32.0, exec-3 at 25.0, and exec-4 at 30.0.
 			expectedMoves: []plan.Move{{ShardID: "shard-6", From: "exec-3", To: "exec-2"}},
 		},
+		{
+			name: "pinned hottest shard - next hottest shard moves",
+			shardLoad: map[string]float64{
+				"shard-1": 10.0,
+				"shard-2": 30.0,
+				"shard-3": 20.0,
+			},
+			currentAssignments: map[string][]string{
+				"exec-1": {"shard-1"},            // 10.0
+				"exec-2": {"shard-2", "shard-3"}, // 50.0
+			},
+			pinnedShards:               map[string]string{"shard-2": ""},
+			maxDeviation:               2.0,
+			expectedDistributionChange: true,
+			expectedMoves:              []plan.Move{{ShardID: "shard-3", From: "exec-2", To: "exec-1"}},
+		},
+		{
+			name: "all shards of hottest executor pinned - no rebalance",
+			shardLoad: map[string]float64{
+				"shard-1": 10.0,
+				"shard-2": 30.0,
+				"shard-3": 20.0,
+			},
+			currentAssignments: map[string][]string{
+				"exec-1": {"shard-1"},            // 10.0
+				"exec-2": {"shard-2", "shard-3"}, // 50.0
+			},
+			pinnedShards:               map[string]string{"shard-2": "", "shard-3": "exec-2"},
+			maxDeviation:               2.0,
+			expectedDistributionChange: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -233,6 +265,8 @@ func TestPlanRebalanceNaiveByReportedLoad(t *testing.T) {
 				testNamespace,
 				testNamespaceState(tc.shardLoad),
 				tc.currentAssignments,
+				tc.pinnedShards,
+				nil,
 				log.NewNoop(),
 				metrics.NoopScope,
 			)
@@ -245,6 +279,28 @@ func TestPlanRebalanceNaiveByReportedLoad(t *testing.T) {
 	}
 }
 
+func TestPlanRebalanceNaiveHonorsLabelSelectors(t *testing.T) {
+	currentAssignments := map[string][]string{
+		"exec-1": {"shard-1"},            // 10.0
+		"exec-2": {"shard-2", "shard-3"}, // 50.0
+	}
+	state := testNamespaceState(map[string]float64{"shard-1": 10, "shard-2": 30, "shard-3": 20})
+	selectors := map[string]map[string]string{"shard-2": {"gpu": "true"}}
+
+	// shard-2 is the hottest shard, but exec-1 does not match its selector.
+	moves, err := PlanRebalance(testNaiveConfig(2), testNamespace, state, currentAssignments, nil, selectors, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Move{{ShardID: "shard-3", From: "exec-2", To: "exec-1"}}, moves)
+
+	state.Executors["exec-1"] = store.HeartbeatState{
+		Status:   types.ExecutorStatusACTIVE,
+		Metadata: map[string]string{store.ExecutorMetadataLabelPrefix + "gpu": "true"},
+	}
+	moves, err = PlanRebalance(testNaiveConfig(2), testNamespace, state, currentAssignments, nil, selectors, log.NewNoop(), metrics.NoopScope)
+	require.NoError(t, err)
+	assert.Equal(t, []plan.Move{{ShardID: "shard-2", From: "exec-2", To: "exec-1"}}, moves)
+}
+
 func cloneAssignments(assignments map[string][]string) map[string][]string {
 	cloned := make(map[string][]string, len(assignments))
 	for executorID, shardIDs := range assignments {
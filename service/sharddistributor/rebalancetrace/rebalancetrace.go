@@ -0,0 +1,86 @@
+package rebalancetrace
+
+import (
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Module provides the no-op rebalance trace sink. Applications that want to inspect rebalance
+// decisions replace it with fx.Decorate.
+var Module = fx.Module(
+	"sharddistributor-rebalancetrace",
+	fx.Provide(NewNoop),
+)
+
+// Phases of a rebalance pass a step can be taken in.
+const (
+	// PhaseConsolidate packs shards onto fewer executors while the namespace load is low.
+	PhaseConsolidate = "consolidate"
+	// PhaseFillEmptyExecutor moves shards onto executors that own none.
+	PhaseFillEmptyExecutor = "fill-empty-executor"
+	// PhaseReassign places shards that have no usable owner.
+	PhaseReassign = "reassign"
+	// PhaseLoadBalance moves shards planned by the load balancing mode of the namespace.
+	PhaseLoadBalance = "load-balance"
+)
+
+// Trace is the structured record of one rebalance pass of a namespace: the inputs it worked from,
+// every decision it took and the plan it ended with.
+type Trace struct {
+	Namespace string
+	// Epoch numbers the passes of the leader, the randomness of the pass is seeded from it and the namespace.
+	Epoch int64
+	// Time is when the pass started.
+	Time time.Time
+
+	// ExecutorLoads is the load of the shards each executor owned before the pass.
+	ExecutorLoads map[string]float64
+	// ShardStatsUpdated is when the statistics of each shard were last updated, which identifies
+	// the version of the statistics the pass used.
+	ShardStatsUpdated map[string]time.Time
+	// AssignmentRevisions is the store revision of the assignment of each executor the pass read.
+	AssignmentRevisions map[string]int64
+
+	// Steps are the decisions of the pass, in the order they were taken.
+	Steps []Step
+	// Plan is the assignment the pass ended with, keyed by executor ID.
+	Plan map[string][]string
+}
+
+// Step is one decision of a rebalance pass: a shard assigned to an executor.
+type Step struct {
+	// Phase is one of the Phase constants.
+	Phase   string
+	ShardID string
+	// From is the executor the shard was taken from, or "" if it had no usable owner.
+	From string
+	// Candidates are the executors that were eligible for the shard, if the phase considers any.
+	Candidates []string
+	// To is the executor the shard was assigned to, or "" if it was left unassigned.
+	To     string
+	Reason string
+}
+
+// AddStep appends step to the trace. It is a no-op on a nil trace, so callers can skip tracing by passing nil.
+func (t *Trace) AddStep(step Step) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, step)
+}
+
+// Sink receives the trace of every rebalance pass that computed a plan.
+// Implementations are called synchronously on the rebalance path, so they must not block.
+type Sink interface {
+	RecordRebalance(trace Trace)
+}
+
+type noop struct{}
+
+// NewNoop returns a Sink that drops all traces.
+func NewNoop() Sink {
+	return noop{}
+}
+
+func (noop) RecordRebalance(Trace) {}
@@ -0,0 +1,193 @@
+// Package reportcodec implements the compact binary encoding of shard status reports that executors
+// with many shards can send instead of the ShardStatusReports map of a heartbeat.
+//
+// The encoding starts with a version byte and the number of reports, followed by the reports in
+// ascending shard ID order. Each report is the shard ID, a flags byte, and for a non-nil report its
+// status, its load, its load dimensions and, when flagged, its report time in Unix nanoseconds.
+// Lengths, counts and statuses are uvarints, report times are varints, strings are length-prefixed
+// and loads are little-endian IEEE 754 doubles.
+package reportcodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	version1 byte = 1
+
+	flagNilReport  byte = 1 << 0
+	flagReportTime byte = 1 << 1
+)
+
+var errTruncated = errors.New("truncated encoding")
+
+// Encode encodes reports. Nil and empty maps both encode to a header without reports.
+func Encode(reports map[string]*types.ShardStatusReport) []byte {
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64+len(reports)*16)
+	buf = append(buf, version1)
+	buf = binary.AppendUvarint(buf, uint64(len(reports)))
+	for _, shardID := range slices.Sorted(maps.Keys(reports)) {
+		buf = appendString(buf, shardID)
+		report := reports[shardID]
+		if report == nil {
+			buf = append(buf, flagNilReport)
+			continue
+		}
+		var flags byte
+		if !report.ReportTime.IsZero() {
+			flags |= flagReportTime
+		}
+		buf = append(buf, flags)
+		buf = binary.AppendUvarint(buf, uint64(report.Status))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(report.ShardLoad))
+		buf = binary.AppendUvarint(buf, uint64(len(report.ShardLoads)))
+		for _, dimension := range slices.Sorted(maps.Keys(report.ShardLoads)) {
+			buf = appendString(buf, dimension)
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(report.ShardLoads[dimension]))
+		}
+		if flags&flagReportTime != 0 {
+			buf = binary.AppendVarint(buf, report.ReportTime.UnixNano())
+		}
+	}
+	return buf
+}
+
+// Decode reconstructs the reports encoded by Encode.
+func Decode(data []byte) (map[string]*types.ShardStatusReport, error) {
+	d := decoder{data: data}
+	if v := d.readByte(); d.err == nil && v != version1 {
+		return nil, fmt.Errorf("unsupported shard status report encoding version %d", v)
+	}
+	count := d.readCount()
+	reports := make(map[string]*types.ShardStatusReport, count)
+	for range count {
+		shardID := d.readString()
+		flags := d.readByte()
+		if d.err != nil {
+			break
+		}
+		if _, ok := reports[shardID]; ok {
+			return nil, fmt.Errorf("duplicate report for shard %q", shardID)
+		}
+		if flags&flagNilReport != 0 {
+			reports[shardID] = nil
+			continue
+		}
+
+		report := &types.ShardStatusReport{
+			Status:    types.ShardStatus(d.readUvarint()),
+			ShardLoad: d.readFloat64(),
+		}
+		if dimensions := d.readCount(); dimensions > 0 {
+			report.ShardLoads = make(map[string]float64, dimensions)
+			for range dimensions {
+				dimension := d.readString()
+				report.ShardLoads[dimension] = d.readFloat64()
+			}
+		}
+		if flags&flagReportTime != 0 {
+			report.ReportTime = time.Unix(0, d.readVarint()).UTC()
+		}
+		reports[shardID] = report
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("decode shard status reports: %w", d.err)
+	}
+	if len(d.data) > 0 {
+		return nil, fmt.Errorf("decode shard status reports: %d trailing bytes", len(d.data))
+	}
+	return reports, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// decoder consumes data. After the first error every read returns the zero value, so a decode
+// checks the error once at the end.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) < 1 {
+		d.err = errTruncated
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) readUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) readVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// readCount reads a count of encoded items. Every item takes at least one byte, so a count larger
+// than the remaining data is corrupt and is rejected before anything is allocated for it.
+func (d *decoder) readCount() int {
+	v := d.readUvarint()
+	if d.err == nil && v > uint64(len(d.data)) {
+		d.err = errTruncated
+		return 0
+	}
+	return int(v)
+}
+
+func (d *decoder) readString() string {
+	n := d.readCount()
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *decoder) readFloat64() float64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) < 8 {
+		d.err = errTruncated
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+	d.data = d.data[8:]
+	return v
+}
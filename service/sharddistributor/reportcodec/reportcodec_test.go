@@ -0,0 +1,133 @@
+package reportcodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	large := make(map[string]*types.ShardStatusReport, 10000)
+	for i := range 10000 {
+		large[fmt.Sprintf("shard-%d", i)] = &types.ShardStatusReport{Status: types.ShardStatusREADY, ShardLoad: float64(i) / 7}
+	}
+
+	tests := []struct {
+		name     string
+		reports  map[string]*types.ShardStatusReport
+		expected map[string]*types.ShardStatusReport
+	}{
+		{
+			name:     "Nil",
+			expected: map[string]*types.ShardStatusReport{},
+		},
+		{
+			name:     "Empty",
+			reports:  map[string]*types.ShardStatusReport{},
+			expected: map[string]*types.ShardStatusReport{},
+		},
+		{
+			name: "Mixed reports",
+			reports: map[string]*types.ShardStatusReport{
+				"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 1.5},
+				"shard-2": {Status: types.ShardStatusDONE},
+				"shard-3": {Status: types.ShardStatusREADY, ShardLoad: 0.25, ShardLoads: map[string]float64{"cpu": 0.5, "memory": 2}},
+				"shard-4": nil,
+				"":        {Status: types.ShardStatusINVALID, ShardLoad: -1},
+				"shard-5": {Status: types.ShardStatusREADY, ShardLoad: 3, ReportTime: time.Date(2025, 11, 18, 16, 0, 0, 5, time.UTC)},
+			},
+		},
+		{
+			name:    "Large",
+			reports: large,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = tt.reports
+			}
+
+			decoded, err := Decode(Encode(tt.reports))
+			require.NoError(t, err)
+			assert.Equal(t, expected, decoded)
+		})
+	}
+}
+
+func TestEncode_IsSmallerThanJSON(t *testing.T) {
+	reports := make(map[string]*types.ShardStatusReport, 1000)
+	for i := range 1000 {
+		reports[fmt.Sprintf("%d", i)] = &types.ShardStatusReport{Status: types.ShardStatusREADY, ShardLoad: float64(i)}
+	}
+	jsonReports, err := json.Marshal(reports)
+	require.NoError(t, err)
+
+	assert.Less(t, len(Encode(reports))*3, len(jsonReports))
+}
+
+func TestEncode_IsDeterministic(t *testing.T) {
+	reports := map[string]*types.ShardStatusReport{
+		"b": {Status: types.ShardStatusREADY, ShardLoads: map[string]float64{"y": 1, "x": 2}},
+		"a": {Status: types.ShardStatusDONE},
+	}
+	assert.Equal(t, Encode(reports), Encode(reports))
+}
+
+func TestDecode_Errors(t *testing.T) {
+	valid := Encode(map[string]*types.ShardStatusReport{
+		"shard-1": {Status: types.ShardStatusREADY, ShardLoad: 1, ShardLoads: map[string]float64{"cpu": 1}},
+	})
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expectedErr string
+	}{
+		{
+			name:        "Empty",
+			data:        nil,
+			expectedErr: "decode shard status reports: truncated encoding",
+		},
+		{
+			name:        "Unsupported version",
+			data:        []byte{2, 0},
+			expectedErr: "unsupported shard status report encoding version 2",
+		},
+		{
+			name:        "Truncated",
+			data:        valid[:len(valid)-1],
+			expectedErr: "decode shard status reports: truncated encoding",
+		},
+		{
+			name:        "Count larger than the data",
+			data:        []byte{version1, 100, 0},
+			expectedErr: "decode shard status reports: truncated encoding",
+		},
+		{
+			name:        "Trailing bytes",
+			data:        append(append([]byte{}, valid...), 0),
+			expectedErr: "decode shard status reports: 1 trailing bytes",
+		},
+		{
+			name:        "Duplicate shard",
+			data:        []byte{version1, 2, 1, 'a', flagNilReport, 1, 'a', flagNilReport},
+			expectedErr: `duplicate report for shard "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -30,10 +30,12 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/rpc"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/events"
 	"github.com/uber/cadence/service/sharddistributor/handler"
 	"github.com/uber/cadence/service/sharddistributor/leader/election"
 	"github.com/uber/cadence/service/sharddistributor/leader/namespace"
 	"github.com/uber/cadence/service/sharddistributor/leader/process"
+	"github.com/uber/cadence/service/sharddistributor/rebalancetrace"
 	"github.com/uber/cadence/service/sharddistributor/store"
 	meteredStore "github.com/uber/cadence/service/sharddistributor/store/wrappers/metered"
 	"github.com/uber/cadence/service/sharddistributor/wrappers/grpc"
@@ -44,6 +46,8 @@ var Module = fx.Module("sharddistributor",
 	namespace.Module,
 	election.Module,
 	process.Module,
+	events.Module,
+	rebalancetrace.Module,
 	fx.Provide(config.NewConfig),
 	fx.Decorate(func(s store.Store, metricsClient metrics.Client, logger log.Logger, timeSource clock.TimeSource) store.Store {
 		return meteredStore.NewStore(s, metricsClient, logger, timeSource)
@@ -60,8 +64,10 @@ type registerHandlersParams struct {
 	RPCFactory    rpc.Factory
 	Config        *config.Config
 
-	TimeSource clock.TimeSource
-	Store      store.Store
+	TimeSource     clock.TimeSource
+	Store          store.Store
+	ExecutorEvents events.ExecutorEvents
+	ProcessFactory process.Factory
 
 	Lifecycle fx.Lifecycle
 }
@@ -69,10 +75,10 @@ type registerHandlersParams struct {
 func registerHandlers(params registerHandlersParams) error {
 	dispatcher := params.RPCFactory.GetDispatcher()
 
-	rawHandler := handler.NewHandler(params.Logger, params.TimeSource, params.ShardDistributionCfg, params.Config, params.Store)
+	rawHandler := handler.NewHandler(params.Logger, params.TimeSource, params.ShardDistributionCfg, params.Config, params.Store, params.MetricsClient, params.ProcessFactory)
 	wrappedHandler := metered.NewMetricsHandler(rawHandler, params.Logger, params.MetricsClient)
 
-	executorHandler := handler.NewExecutorHandler(params.Logger, params.Store, params.TimeSource, params.ShardDistributionCfg, params.Config, params.MetricsClient)
+	executorHandler := handler.NewExecutorHandler(params.Logger, params.Store, params.TimeSource, params.ShardDistributionCfg, params.Config, params.MetricsClient, params.ExecutorEvents)
 	wrappedExecutor := metered.NewExecutorMetricsExecutor(executorHandler, params.Logger, params.MetricsClient)
 
 	grpcHandler := grpc.NewGRPCHandler(wrappedHandler)
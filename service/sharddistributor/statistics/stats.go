@@ -3,11 +3,20 @@ package statistics
 import (
 	"fmt"
 	"math"
+	"slices"
 	"time"
+
+	"github.com/uber/cadence/common/types"
 )
 
 const DefaultLoadSmoothingTimeConstant = time.Minute
 
+// DefaultLoadHighWatermarkDecay is the time constant with which the load high watermark decays.
+const DefaultLoadHighWatermarkDecay = 5 * time.Minute
+
+// DefaultLoadDimension is the dimension a report's scalar ShardLoad is accounted under.
+const DefaultLoadDimension = "default"
+
 func CalculateSmoothedLoad(prev, current float64, lastUpdate, now time.Time, smoothingTimeConstant time.Duration) (float64, error) {
 	if math.IsNaN(current) || math.IsInf(current, 0) {
 		return 0, fmt.Errorf("current load is NaN or Inf: %f", current)
@@ -26,3 +35,126 @@ func CalculateSmoothedLoad(prev, current float64, lastUpdate, now time.Time, smo
 	alpha := 1 - math.Exp(-dt.Seconds()/tau.Seconds())
 	return (1-alpha)*prev + alpha*current, nil
 }
+
+// CalculateLoadHighWatermark returns the new decaying maximum of a shard's load. The previous watermark
+// decays exponentially towards zero with the given time constant and is raised to the current load if that
+// is higher. Without a previous update, or with a non-positive time constant, the watermark is the current load.
+func CalculateLoadHighWatermark(prev, current float64, lastUpdate, now time.Time, decayTimeConstant time.Duration) float64 {
+	if lastUpdate.IsZero() || decayTimeConstant <= 0 || math.IsNaN(prev) || math.IsInf(prev, 0) {
+		return current
+	}
+	decayed := prev
+	if now.After(lastUpdate) {
+		decayed = prev * math.Exp(-now.Sub(lastUpdate).Seconds()/decayTimeConstant.Seconds())
+	}
+	return math.Max(decayed, current)
+}
+
+// ReportedLoads returns the per-dimension loads of a shard report.
+// Reports without named dimensions map their ShardLoad to DefaultLoadDimension.
+func ReportedLoads(report *types.ShardStatusReport) map[string]float64 {
+	if len(report.GetShardLoads()) == 0 {
+		return map[string]float64{DefaultLoadDimension: report.GetShardLoad()}
+	}
+	loads := make(map[string]float64, len(report.GetShardLoads()))
+	for dimension, load := range report.GetShardLoads() {
+		loads[dimension] = load
+	}
+	return loads
+}
+
+// LoadSamples accumulates the per-dimension loads reported for a shard within one window,
+// so they can be smoothed as a single aggregated sample.
+type LoadSamples struct {
+	counts map[string]int
+	sums   map[string]float64
+	maxes  map[string]float64
+	last   map[string]float64
+}
+
+// Add records the loads of one report.
+func (s *LoadSamples) Add(loads map[string]float64) {
+	if s.counts == nil {
+		s.counts = make(map[string]int, len(loads))
+		s.sums = make(map[string]float64, len(loads))
+		s.maxes = make(map[string]float64, len(loads))
+	}
+	for dimension, load := range loads {
+		if s.counts[dimension] == 0 || load > s.maxes[dimension] {
+			s.maxes[dimension] = load
+		}
+		s.counts[dimension]++
+		s.sums[dimension] += load
+	}
+	s.last = loads
+}
+
+// Last returns the loads of the most recent report.
+func (s *LoadSamples) Last() map[string]float64 {
+	return s.last
+}
+
+// Mean returns the mean load of every dimension over the reports that carried it.
+func (s *LoadSamples) Mean() map[string]float64 {
+	mean := make(map[string]float64, len(s.sums))
+	for dimension, sum := range s.sums {
+		mean[dimension] = sum / float64(s.counts[dimension])
+	}
+	return mean
+}
+
+// Max returns the highest load of every dimension.
+func (s *LoadSamples) Max() map[string]float64 {
+	maxes := make(map[string]float64, len(s.maxes))
+	for dimension, load := range s.maxes {
+		maxes[dimension] = load
+	}
+	return maxes
+}
+
+// CalculateSmoothedLoads smooths every reported dimension independently.
+// prevScalar is used as the previous value of DefaultLoadDimension when no per-dimension
+// history exists, so statistics written before dimensions were introduced keep smoothing.
+func CalculateSmoothedLoads(prev map[string]float64, prevScalar float64, current map[string]float64, lastUpdate, now time.Time, smoothingTimeConstant time.Duration) (map[string]float64, error) {
+	smoothed := make(map[string]float64, len(current))
+	for dimension, load := range current {
+		prevLoad, ok := prev[dimension]
+		dimensionLastUpdate := lastUpdate
+		if !ok {
+			if len(prev) == 0 && dimension == DefaultLoadDimension {
+				prevLoad = prevScalar
+			} else {
+				// A dimension seen for the first time starts from its current value.
+				dimensionLastUpdate = time.Time{}
+			}
+		}
+
+		value, err := CalculateSmoothedLoad(prevLoad, load, dimensionLastUpdate, now, smoothingTimeConstant)
+		if err != nil {
+			return nil, fmt.Errorf("dimension %q: %w", dimension, err)
+		}
+		smoothed[dimension] = value
+	}
+	return smoothed, nil
+}
+
+// CombineLoads combines per-dimension loads into a single load using the given weights.
+// Dimensions without a weight are combined with a weight of 1.
+func CombineLoads(loads map[string]float64, weights map[string]float64) float64 {
+	dimensions := make([]string, 0, len(loads))
+	for dimension := range loads {
+		dimensions = append(dimensions, dimension)
+	}
+	// Sort to keep the floating point sum independent of map iteration order.
+	slices.Sort(dimensions)
+
+	combined := 0.0
+	for _, dimension := range dimensions {
+		weight, ok := weights[dimension]
+		if !ok {
+			weight = 1
+		}
+		combined += weight * loads[dimension]
+	}
+	return combined
+}
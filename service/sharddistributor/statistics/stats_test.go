@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/types"
 )
 
 func TestCalculateSmoothedLoad(t *testing.T) {
@@ -140,3 +141,130 @@ func TestCalculateSmoothedLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateLoadHighWatermark(t *testing.T) {
+	lastUpdate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	decay := time.Minute
+
+	tests := []struct {
+		name    string
+		prev    float64
+		current float64
+		last    time.Time
+		now     time.Time
+		decay   time.Duration
+		want    float64
+	}{
+		{name: "first update returns current", prev: 0, current: 5, now: lastUpdate, decay: decay, want: 5},
+		{name: "spike raises the watermark", prev: 2, current: 10, last: lastUpdate, now: lastUpdate.Add(time.Second), decay: decay, want: 10},
+		{name: "low report decays the watermark", prev: 10, current: 1, last: lastUpdate, now: lastUpdate.Add(decay), decay: decay, want: 10 * math.Exp(-1)},
+		{name: "decayed watermark never drops below current", prev: 10, current: 5, last: lastUpdate, now: lastUpdate.Add(10 * decay), decay: decay, want: 5},
+		{name: "now before lastUpdate does not decay", prev: 10, current: 1, last: lastUpdate, now: lastUpdate.Add(-time.Second), decay: decay, want: 10},
+		{name: "disabled decay follows current", prev: 10, current: 1, last: lastUpdate, now: lastUpdate.Add(time.Second), decay: 0, want: 1},
+		{name: "invalid previous watermark is reset", prev: math.NaN(), current: 3, last: lastUpdate, now: lastUpdate.Add(time.Second), decay: decay, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, CalculateLoadHighWatermark(tt.prev, tt.current, tt.last, tt.now, tt.decay), 1e-9)
+		})
+	}
+}
+
+func TestReportedLoads(t *testing.T) {
+	assert.Equal(t,
+		map[string]float64{DefaultLoadDimension: 3},
+		ReportedLoads(&types.ShardStatusReport{ShardLoad: 3}),
+	)
+	assert.Equal(t,
+		map[string]float64{"cpu": 1, "memory": 2},
+		ReportedLoads(&types.ShardStatusReport{ShardLoad: 3, ShardLoads: map[string]float64{"cpu": 1, "memory": 2}}),
+	)
+}
+
+func TestLoadSamples(t *testing.T) {
+	var samples LoadSamples
+	samples.Add(map[string]float64{"cpu": 2, "memory": 10})
+	samples.Add(map[string]float64{"cpu": 8})
+	samples.Add(map[string]float64{"cpu": 5, "memory": 4})
+
+	assert.Equal(t, map[string]float64{"cpu": 5, "memory": 4}, samples.Last())
+	assert.Equal(t, map[string]float64{"cpu": 5, "memory": 7}, samples.Mean(), "a dimension is averaged over the reports carrying it")
+	assert.Equal(t, map[string]float64{"cpu": 8, "memory": 10}, samples.Max())
+
+	var negative LoadSamples
+	negative.Add(map[string]float64{"cpu": -3})
+	negative.Add(map[string]float64{"cpu": -1})
+	assert.Equal(t, map[string]float64{"cpu": -1}, negative.Max())
+
+	var empty LoadSamples
+	assert.Empty(t, empty.Mean())
+	assert.Empty(t, empty.Max())
+	assert.Nil(t, empty.Last())
+}
+
+func TestCalculateSmoothedLoads(t *testing.T) {
+	ts := clock.NewMockedTimeSource()
+	lastUpdate := ts.Now()
+	now := lastUpdate.Add(DefaultLoadSmoothingTimeConstant)
+	alpha := 1 - math.Exp(-1)
+
+	tests := []struct {
+		name       string
+		prev       map[string]float64
+		prevScalar float64
+		current    map[string]float64
+		want       map[string]float64
+		wantErr    bool
+	}{
+		{
+			name:    "each dimension is smoothed independently",
+			prev:    map[string]float64{"cpu": 10, "memory": 100},
+			current: map[string]float64{"cpu": 20, "memory": 0},
+			want: map[string]float64{
+				"cpu":    (1-alpha)*10 + alpha*20,
+				"memory": (1 - alpha) * 100,
+			},
+		},
+		{
+			name:    "new dimension starts from its current value",
+			prev:    map[string]float64{"cpu": 10},
+			current: map[string]float64{"cpu": 10, "memory": 50},
+			want:    map[string]float64{"cpu": 10, "memory": 50},
+		},
+		{
+			name:       "scalar history seeds the default dimension",
+			prevScalar: 10,
+			current:    map[string]float64{DefaultLoadDimension: 20},
+			want:       map[string]float64{DefaultLoadDimension: (1-alpha)*10 + alpha*20},
+		},
+		{
+			name:    "invalid dimension fails",
+			prev:    map[string]float64{"cpu": 10},
+			current: map[string]float64{"cpu": math.NaN()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateSmoothedLoads(tt.prev, tt.prevScalar, tt.current, lastUpdate, now, DefaultLoadSmoothingTimeConstant)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.want))
+			for dimension, want := range tt.want {
+				assert.InDelta(t, want, got[dimension], 1e-9, dimension)
+			}
+		})
+	}
+}
+
+func TestCombineLoads(t *testing.T) {
+	loads := map[string]float64{"cpu": 2, "memory": 4, "rps": 10}
+
+	assert.Equal(t, 16.0, CombineLoads(loads, nil))
+	assert.Equal(t, 4.0+2.0+1.0, CombineLoads(loads, map[string]float64{"cpu": 2, "memory": 0.5, "rps": 0.1}))
+	assert.Equal(t, 0.0, CombineLoads(nil, map[string]float64{"cpu": 2}))
+}
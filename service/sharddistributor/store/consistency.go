@@ -0,0 +1,32 @@
+package store
+
+import "context"
+
+// ConsistencyToken identifies a write to the store. A store whose reads are only eventually consistent can
+// use it to serve a later read from a state that reflects the write. The format is specific to the store,
+// the empty token requests no particular consistency.
+type ConsistencyToken string
+
+// HeartbeatTokenRecorder is optionally implemented by stores that return a consistency token for a recorded
+// heartbeat. Stores whose reads always reflect their writes do not need to implement it.
+type HeartbeatTokenRecorder interface {
+	// RecordHeartbeatWithToken records the heartbeat like RecordHeartbeat and returns the token of the write.
+	RecordHeartbeatWithToken(ctx context.Context, namespace, executorID string, state HeartbeatState) (ConsistencyToken, error)
+}
+
+type consistencyTokenKey struct{}
+
+// WithConsistencyToken returns a context asking the store reads made with it to reflect the write identified
+// by token. The empty token leaves ctx unchanged.
+func WithConsistencyToken(ctx context.Context, token ConsistencyToken) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, consistencyTokenKey{}, token)
+}
+
+// ConsistencyTokenFromContext returns the token set with WithConsistencyToken, if any.
+func ConsistencyTokenFromContext(ctx context.Context) (ConsistencyToken, bool) {
+	token, ok := ctx.Value(consistencyTokenKey{}).(ConsistencyToken)
+	return token, ok
+}
@@ -1,6 +1,8 @@
 package etcdtypes
 
 import (
+	"time"
+
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
@@ -71,9 +73,96 @@ func ToShardHandoverStats(src *ShardHandoverStats) *store.ShardHandoverStats {
 }
 
 type ShardStatistics struct {
-	SmoothedLoad   float64 `json:"smoothed_load"`
-	LastUpdateTime Time    `json:"last_update_time"`
-	LastMoveTime   Time    `json:"last_move_time"`
+	SmoothedLoad      float64            `json:"smoothed_load"`
+	SmoothedLoads     map[string]float64 `json:"smoothed_loads,omitempty"`
+	LoadHighWatermark float64            `json:"load_high_watermark,omitempty"`
+	LastUpdateTime    Time               `json:"last_update_time"`
+	LastReportTime    *Time              `json:"last_report_time,omitempty"`
+	LastMoveTime      Time               `json:"last_move_time"`
+	AssignmentHistory []ShardOwnerChange `json:"assignment_history,omitempty"`
+	RecentLoads       []LoadSample       `json:"recent_loads,omitempty"`
+	WindowedLoad      float64            `json:"windowed_load,omitempty"`
+	LoadHistory       []LoadSample       `json:"load_history,omitempty"`
+	WeightOverride    float64            `json:"weight_override,omitempty"`
+	PinnedExecutor    string             `json:"pinned_executor,omitempty"`
+	PinExpiresAt      *Time              `json:"pin_expires_at,omitempty"`
+}
+
+// RecordOwnerChange appends the assignment of the shard to executorID to its assignment history,
+// evicting the oldest entries beyond store.AssignmentHistorySize.
+func (s *ShardStatistics) RecordOwnerChange(executorID string, assignedAt time.Time) {
+	history := toAssignmentHistory(s.AssignmentHistory).Append(
+		store.ShardOwnerChange{ExecutorID: executorID, AssignedAt: assignedAt},
+		store.AssignmentHistorySize,
+	)
+	s.AssignmentHistory = fromAssignmentHistory(history)
+}
+
+// HasLoadSample reports whether a load report was applied to the statistics. The statistics created
+// when a shard is assigned only carry its move and update times, not a load.
+func (s *ShardStatistics) HasLoadSample() bool {
+	return len(s.SmoothedLoads) > 0 || s.SmoothedLoad != 0
+}
+
+// RecordMove records that the shard moved to executorID at movedAt, refreshing LastMoveTime so the
+// per-shard cooldown starts from the move.
+func (s *ShardStatistics) RecordMove(executorID string, movedAt time.Time) {
+	s.LastMoveTime = Time(movedAt)
+	s.RecordOwnerChange(executorID, movedAt)
+}
+
+// ScaleSmoothedLoad keeps retention of the smoothed load of the shard. With a retention of zero the smoothed
+// load is dropped, so the next load report is taken as is instead of being smoothed against it.
+func (s *ShardStatistics) ScaleSmoothedLoad(retention float64) {
+	if retention == 0 {
+		s.SmoothedLoad = 0
+		s.SmoothedLoads = nil
+		return
+	}
+
+	s.SmoothedLoad *= retention
+	// The map may be shared with the cached statistics, so it is replaced rather than updated.
+	smoothedLoads := make(map[string]float64, len(s.SmoothedLoads))
+	for dimension, load := range s.SmoothedLoads {
+		smoothedLoads[dimension] = load * retention
+	}
+	if len(smoothedLoads) == 0 {
+		smoothedLoads = nil
+	}
+	s.SmoothedLoads = smoothedLoads
+}
+
+// RecordLoad adds the combined load reported at reportedAt to the recent loads of the shard, dropping
+// the loads reported more than window before it, and updates the windowed load.
+func (s *ShardStatistics) RecordLoad(load float64, reportedAt time.Time, window time.Duration) {
+	samples := toLoadSamples(s.RecentLoads).Append(
+		store.LoadSample{Load: load, ReportedAt: reportedAt},
+		window,
+		store.LoadSamplesSize,
+	)
+	s.RecentLoads = fromLoadSamples(samples)
+	s.WindowedLoad = samples.Average()
+}
+
+// RecordLoadHistory adds the smoothed load at reportedAt to the load history of the shard, keeping one
+// sample per resolution interval and dropping the samples older than retention.
+func (s *ShardStatistics) RecordLoadHistory(reportedAt time.Time, resolution, retention time.Duration) {
+	history := toLoadSamples(s.LoadHistory).Downsample(
+		store.LoadSample{Load: s.SmoothedLoad, ReportedAt: reportedAt},
+		resolution,
+		retention,
+	)
+	s.LoadHistory = fromLoadSamples(history)
+}
+
+type ShardOwnerChange struct {
+	ExecutorID string `json:"executor_id"`
+	AssignedAt Time   `json:"assigned_at"`
+}
+
+type LoadSample struct {
+	Load       float64 `json:"load"`
+	ReportedAt Time    `json:"reported_at"`
 }
 
 // ToShardStatistics converts the current ShardStatistics to store.ShardStatistics.
@@ -83,9 +172,19 @@ func (s *ShardStatistics) ToShardStatistics() *store.ShardStatistics {
 	}
 
 	return &store.ShardStatistics{
-		SmoothedLoad:   s.SmoothedLoad,
-		LastUpdateTime: s.LastUpdateTime.ToTime(),
-		LastMoveTime:   s.LastMoveTime.ToTime(),
+		SmoothedLoad:      s.SmoothedLoad,
+		SmoothedLoads:     s.SmoothedLoads,
+		LoadHighWatermark: s.LoadHighWatermark,
+		LastUpdateTime:    s.LastUpdateTime.ToTime(),
+		LastReportTime:    toOptionalTime(s.LastReportTime),
+		LastMoveTime:      s.LastMoveTime.ToTime(),
+		AssignmentHistory: toAssignmentHistory(s.AssignmentHistory),
+		RecentLoads:       toLoadSamples(s.RecentLoads),
+		WindowedLoad:      s.WindowedLoad,
+		LoadHistory:       toLoadSamples(s.LoadHistory),
+		WeightOverride:    s.WeightOverride,
+		PinnedExecutor:    s.PinnedExecutor,
+		PinExpiresAt:      toOptionalTime(s.PinExpiresAt),
 	}
 }
 
@@ -96,10 +195,79 @@ func FromShardStatistics(src *store.ShardStatistics) *ShardStatistics {
 	}
 
 	return &ShardStatistics{
-		SmoothedLoad:   src.SmoothedLoad,
-		LastUpdateTime: Time(src.LastUpdateTime),
-		LastMoveTime:   Time(src.LastMoveTime),
+		SmoothedLoad:      src.SmoothedLoad,
+		SmoothedLoads:     src.SmoothedLoads,
+		LoadHighWatermark: src.LoadHighWatermark,
+		LastUpdateTime:    Time(src.LastUpdateTime),
+		LastReportTime:    fromOptionalTime(src.LastReportTime),
+		LastMoveTime:      Time(src.LastMoveTime),
+		AssignmentHistory: fromAssignmentHistory(src.AssignmentHistory),
+		RecentLoads:       fromLoadSamples(src.RecentLoads),
+		WindowedLoad:      src.WindowedLoad,
+		LoadHistory:       fromLoadSamples(src.LoadHistory),
+		WeightOverride:    src.WeightOverride,
+		PinnedExecutor:    src.PinnedExecutor,
+		PinExpiresAt:      fromOptionalTime(src.PinExpiresAt),
+	}
+}
+
+func toOptionalTime(src *Time) time.Time {
+	if src == nil {
+		return time.Time{}
+	}
+	return src.ToTime()
+}
+
+// fromOptionalTime leaves unset times, like the expiry of shards without a pin, out of the stored statistics.
+func fromOptionalTime(src time.Time) *Time {
+	if src.IsZero() {
+		return nil
 	}
+	return ToTimePtr(&src)
+}
+
+func toAssignmentHistory(src []ShardOwnerChange) store.AssignmentHistory {
+	if src == nil {
+		return nil
+	}
+	dst := make(store.AssignmentHistory, 0, len(src))
+	for _, change := range src {
+		dst = append(dst, store.ShardOwnerChange{ExecutorID: change.ExecutorID, AssignedAt: change.AssignedAt.ToTime()})
+	}
+	return dst
+}
+
+func fromAssignmentHistory(src store.AssignmentHistory) []ShardOwnerChange {
+	if src == nil {
+		return nil
+	}
+	dst := make([]ShardOwnerChange, 0, len(src))
+	for _, change := range src {
+		dst = append(dst, ShardOwnerChange{ExecutorID: change.ExecutorID, AssignedAt: Time(change.AssignedAt)})
+	}
+	return dst
+}
+
+func toLoadSamples(src []LoadSample) store.LoadSamples {
+	if src == nil {
+		return nil
+	}
+	dst := make(store.LoadSamples, 0, len(src))
+	for _, sample := range src {
+		dst = append(dst, store.LoadSample{Load: sample.Load, ReportedAt: sample.ReportedAt.ToTime()})
+	}
+	return dst
+}
+
+func fromLoadSamples(src store.LoadSamples) []LoadSample {
+	if src == nil {
+		return nil
+	}
+	dst := make([]LoadSample, 0, len(src))
+	for _, sample := range src {
+		dst = append(dst, LoadSample{Load: sample.Load, ReportedAt: Time(sample.ReportedAt)})
+	}
+	return dst
 }
 
 // ConvertMap converts a map[K]SrcType to map[K]DstType using a provided converter function.
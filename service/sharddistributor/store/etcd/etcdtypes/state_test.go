@@ -2,12 +2,14 @@ package etcdtypes
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/store"
 )
@@ -242,14 +244,34 @@ func TestShardStatistics_ToShardStatistics(t *testing.T) {
 		},
 		"success": {
 			input: &ShardStatistics{
-				SmoothedLoad:   12.34,
-				LastUpdateTime: Time(time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC)),
-				LastMoveTime:   Time(time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC)),
+				SmoothedLoad:      12.34,
+				LoadHighWatermark: 20.5,
+				LastUpdateTime:    Time(time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC)),
+				LastMoveTime:      Time(time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC)),
+				AssignmentHistory: []ShardOwnerChange{
+					{ExecutorID: "exec-1", AssignedAt: Time(time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC))},
+				},
+				RecentLoads:    []LoadSample{{Load: 11, ReportedAt: Time(time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC))}},
+				WindowedLoad:   11,
+				LoadHistory:    []LoadSample{{Load: 12, ReportedAt: Time(time.Date(2025, 11, 18, 14, 0, 0, 0, time.UTC))}},
+				WeightOverride: 40,
+				PinnedExecutor: "exec-1",
+				PinExpiresAt:   ToTimePtr(common.TimePtr(time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC))),
 			},
 			expect: &store.ShardStatistics{
-				SmoothedLoad:   12.34,
-				LastUpdateTime: time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC),
-				LastMoveTime:   time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC),
+				SmoothedLoad:      12.34,
+				LoadHighWatermark: 20.5,
+				LastUpdateTime:    time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC),
+				LastMoveTime:      time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC),
+				AssignmentHistory: store.AssignmentHistory{
+					{ExecutorID: "exec-1", AssignedAt: time.Date(2025, 11, 18, 15, 0, 0, 222222222, time.UTC)},
+				},
+				RecentLoads:    store.LoadSamples{{Load: 11, ReportedAt: time.Date(2025, 11, 18, 14, 0, 0, 111111111, time.UTC)}},
+				WindowedLoad:   11,
+				LoadHistory:    store.LoadSamples{{Load: 12, ReportedAt: time.Date(2025, 11, 18, 14, 0, 0, 0, time.UTC)}},
+				WeightOverride: 40,
+				PinnedExecutor: "exec-1",
+				PinExpiresAt:   time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC),
 			},
 		},
 	}
@@ -263,6 +285,14 @@ func TestShardStatistics_ToShardStatistics(t *testing.T) {
 			}
 			require.NotNil(t, got)
 			require.Equal(t, c.input.SmoothedLoad, got.SmoothedLoad)
+			require.Equal(t, c.input.LoadHighWatermark, got.LoadHighWatermark)
+			require.Equal(t, c.expect.AssignmentHistory, got.AssignmentHistory)
+			require.Equal(t, c.expect.RecentLoads, got.RecentLoads)
+			require.Equal(t, c.expect.WindowedLoad, got.WindowedLoad)
+			require.Equal(t, c.expect.LoadHistory, got.LoadHistory)
+			require.Equal(t, c.expect.WeightOverride, got.WeightOverride)
+			require.Equal(t, c.expect.PinnedExecutor, got.PinnedExecutor)
+			require.Equal(t, c.expect.PinExpiresAt.UnixNano(), got.PinExpiresAt.UnixNano())
 			require.Equal(t, time.Time(c.input.LastUpdateTime).UnixNano(), got.LastUpdateTime.UnixNano())
 			require.Equal(t, time.Time(c.input.LastMoveTime).UnixNano(), got.LastMoveTime.UnixNano())
 		})
@@ -280,14 +310,34 @@ func TestShardStatistics_FromShardStatistics(t *testing.T) {
 		},
 		"success": {
 			input: &store.ShardStatistics{
-				SmoothedLoad:   99.01,
-				LastUpdateTime: time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC),
-				LastMoveTime:   time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC),
+				SmoothedLoad:      99.01,
+				LoadHighWatermark: 120,
+				LastUpdateTime:    time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC),
+				LastMoveTime:      time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC),
+				AssignmentHistory: store.AssignmentHistory{
+					{ExecutorID: "exec-2", AssignedAt: time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC)},
+				},
+				RecentLoads:    store.LoadSamples{{Load: 98, ReportedAt: time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC)}},
+				WindowedLoad:   98,
+				LoadHistory:    store.LoadSamples{{Load: 99, ReportedAt: time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC)}},
+				WeightOverride: 40,
+				PinnedExecutor: "exec-2",
+				PinExpiresAt:   time.Date(2025, 11, 18, 18, 0, 0, 0, time.UTC),
 			},
 			expect: &ShardStatistics{
-				SmoothedLoad:   99.01,
-				LastUpdateTime: Time(time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC)),
-				LastMoveTime:   Time(time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC)),
+				SmoothedLoad:      99.01,
+				LoadHighWatermark: 120,
+				LastUpdateTime:    Time(time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC)),
+				LastMoveTime:      Time(time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC)),
+				AssignmentHistory: []ShardOwnerChange{
+					{ExecutorID: "exec-2", AssignedAt: Time(time.Date(2025, 11, 18, 17, 0, 0, 444444444, time.UTC))},
+				},
+				RecentLoads:    []LoadSample{{Load: 98, ReportedAt: Time(time.Date(2025, 11, 18, 16, 0, 0, 333333333, time.UTC))}},
+				WindowedLoad:   98,
+				LoadHistory:    []LoadSample{{Load: 99, ReportedAt: Time(time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC))}},
+				WeightOverride: 40,
+				PinnedExecutor: "exec-2",
+				PinExpiresAt:   ToTimePtr(common.TimePtr(time.Date(2025, 11, 18, 18, 0, 0, 0, time.UTC))),
 			},
 		},
 	}
@@ -301,12 +351,107 @@ func TestShardStatistics_FromShardStatistics(t *testing.T) {
 			}
 			require.NotNil(t, got)
 			require.InDelta(t, c.input.SmoothedLoad, got.SmoothedLoad, 0.0000001)
+			require.InDelta(t, c.input.LoadHighWatermark, got.LoadHighWatermark, 0.0000001)
+			require.Equal(t, c.expect.AssignmentHistory, got.AssignmentHistory)
+			require.Equal(t, c.expect.RecentLoads, got.RecentLoads)
+			require.Equal(t, c.expect.WindowedLoad, got.WindowedLoad)
+			require.Equal(t, c.expect.LoadHistory, got.LoadHistory)
+			require.Equal(t, c.expect.WeightOverride, got.WeightOverride)
+			require.Equal(t, c.expect.PinnedExecutor, got.PinnedExecutor)
+			require.Equal(t, c.input.PinExpiresAt.UnixNano(), got.PinExpiresAt.ToTime().UnixNano())
 			require.Equal(t, c.input.LastUpdateTime.UnixNano(), time.Time(got.LastUpdateTime).UnixNano())
 			require.Equal(t, c.input.LastMoveTime.UnixNano(), time.Time(got.LastMoveTime).UnixNano())
 		})
 	}
 }
 
+func TestShardStatistics_RecordOwnerChange(t *testing.T) {
+	start := time.Date(2025, 11, 18, 14, 0, 0, 0, time.UTC)
+	var stats ShardStatistics
+	for i := 0; i <= store.AssignmentHistorySize; i++ {
+		stats.RecordOwnerChange(fmt.Sprintf("exec-%d", i), start.Add(time.Duration(i)*time.Minute))
+	}
+
+	// The first owner was evicted to keep the history bounded.
+	require.Len(t, stats.AssignmentHistory, store.AssignmentHistorySize)
+	require.Equal(t, ShardOwnerChange{ExecutorID: "exec-1", AssignedAt: Time(start.Add(time.Minute))}, stats.AssignmentHistory[0])
+	last := store.AssignmentHistorySize
+	require.Equal(t, ShardOwnerChange{ExecutorID: fmt.Sprintf("exec-%d", last), AssignedAt: Time(start.Add(time.Duration(last) * time.Minute))}, stats.AssignmentHistory[last-1])
+}
+
+func TestShardStatistics_HasLoadSample(t *testing.T) {
+	now := time.Date(2025, 11, 18, 14, 0, 0, 0, time.UTC)
+	require.False(t, (&ShardStatistics{LastUpdateTime: Time(now), LastMoveTime: Time(now)}).HasLoadSample())
+	require.True(t, (&ShardStatistics{SmoothedLoads: map[string]float64{"default": 0}}).HasLoadSample())
+	require.True(t, (&ShardStatistics{SmoothedLoad: 2}).HasLoadSample(), "statistics written before load dimensions")
+}
+
+func TestShardStatistics_RecordMove(t *testing.T) {
+	previousMove := time.Date(2025, 11, 18, 14, 0, 0, 0, time.UTC)
+	now := previousMove.Add(time.Hour)
+	stats := ShardStatistics{SmoothedLoad: 3, LastMoveTime: Time(previousMove)}
+
+	stats.RecordMove("exec-2", now)
+
+	require.Equal(t, Time(now), stats.LastMoveTime)
+	require.Equal(t, 3.0, stats.SmoothedLoad, "the load is carried over")
+	require.Equal(t, []ShardOwnerChange{{ExecutorID: "exec-2", AssignedAt: Time(now)}}, stats.AssignmentHistory)
+	require.Equal(t, now, stats.ToShardStatistics().LastMoveTime)
+}
+
+func TestShardStatistics_ScaleSmoothedLoad(t *testing.T) {
+	newStats := func() ShardStatistics {
+		return ShardStatistics{SmoothedLoad: 8, SmoothedLoads: map[string]float64{"cpu": 8, "memory": 2}}
+	}
+
+	kept := newStats()
+	kept.ScaleSmoothedLoad(1)
+	require.Equal(t, newStats(), kept)
+
+	halved := newStats()
+	shared := halved.SmoothedLoads
+	halved.ScaleSmoothedLoad(0.5)
+	require.Equal(t, 4.0, halved.SmoothedLoad)
+	require.Equal(t, map[string]float64{"cpu": 4, "memory": 1}, halved.SmoothedLoads)
+	require.Equal(t, map[string]float64{"cpu": 8, "memory": 2}, shared, "the previous map is left untouched")
+
+	reset := newStats()
+	reset.ScaleSmoothedLoad(0)
+	require.Zero(t, reset.SmoothedLoad)
+	require.Nil(t, reset.SmoothedLoads)
+	require.False(t, reset.HasLoadSample(), "the next report is taken as is")
+}
+
+func TestShardStatistics_RecordLoad(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var stats ShardStatistics
+	stats.RecordLoad(2, start, time.Minute)
+	stats.RecordLoad(4, start.Add(30*time.Second), time.Minute)
+	require.Len(t, stats.RecentLoads, 2)
+	require.Equal(t, 3.0, stats.WindowedLoad)
+
+	// The first load falls out of the window.
+	stats.RecordLoad(6, start.Add(90*time.Second), time.Minute)
+	require.Equal(t, []LoadSample{
+		{Load: 4, ReportedAt: Time(start.Add(30 * time.Second))},
+		{Load: 6, ReportedAt: Time(start.Add(90 * time.Second))},
+	}, stats.RecentLoads)
+	require.Equal(t, 5.0, stats.WindowedLoad)
+}
+
+func TestShardStatistics_RecordLoadHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := ShardStatistics{SmoothedLoad: 2}
+	stats.RecordLoadHistory(start.Add(10*time.Second), time.Minute, time.Hour)
+	stats.SmoothedLoad = 3
+	stats.RecordLoadHistory(start.Add(70*time.Second), time.Minute, time.Hour)
+
+	require.Equal(t, []LoadSample{
+		{Load: 2, ReportedAt: Time(start)},
+		{Load: 3, ReportedAt: Time(start.Add(time.Minute))},
+	}, stats.LoadHistory)
+}
+
 func TestShardStatistics_JSONMarshalling(t *testing.T) {
 	const jsonStr = `{"smoothed_load":12.34,"last_update_time":"2025-11-18T14:00:00.111111111Z","last_move_time":"2025-11-18T15:00:00.222222222Z"}`
 
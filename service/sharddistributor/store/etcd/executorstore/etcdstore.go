@@ -7,8 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/fx"
 
@@ -37,14 +40,15 @@ var (
 )
 
 type executorStoreImpl struct {
-	client        etcdclient.Client
-	prefix        string
-	logger        log.Logger
-	shardCache    *shardcache.ShardToExecutorCache
-	timeSource    clock.TimeSource
-	recordWriter  *common.RecordWriter
-	cfg           *config.Config
-	metricsClient metrics.Client
+	client         etcdclient.Client
+	prefix         string
+	logger         log.Logger
+	shardCache     *shardcache.ShardToExecutorCache
+	timeSource     clock.TimeSource
+	recordWriter   *common.RecordWriter
+	cfg            *config.Config
+	metricsClient  metrics.Client
+	statsCoalescer *statisticsCoalescer
 }
 
 // shardStatisticsUpdate holds the staged statistics for a shard so we can write them
@@ -82,14 +86,15 @@ func NewStore(p ExecutorStoreParams) (store.Store, error) {
 	}
 
 	store := &executorStoreImpl{
-		client:        p.Client,
-		prefix:        p.ETCDConfig.Prefix,
-		logger:        p.Logger,
-		shardCache:    shardCache,
-		timeSource:    timeSource,
-		recordWriter:  recordWriter,
-		cfg:           p.Config,
-		metricsClient: p.MetricsClient,
+		client:         p.Client,
+		prefix:         p.ETCDConfig.Prefix,
+		logger:         p.Logger,
+		shardCache:     shardCache,
+		timeSource:     timeSource,
+		recordWriter:   recordWriter,
+		cfg:            p.Config,
+		metricsClient:  p.MetricsClient,
+		statsCoalescer: newStatisticsCoalescer(),
 	}
 
 	p.Lifecycle.Append(fx.StartStopHook(store.Start, store.Stop))
@@ -107,6 +112,15 @@ func (s *executorStoreImpl) Stop() {
 
 // --- HeartbeatStore Implementation ---
 
+// classifyWriteError marks write errors caused by etcd rejecting writes with store.ErrReadOnly.
+// etcd raises a space quota alarm when its database is full and only serves reads until it is cleared.
+func classifyWriteError(err error) error {
+	if errors.Is(err, rpctypes.ErrNoSpace) {
+		return fmt.Errorf("%w: %v", store.ErrReadOnly, err)
+	}
+	return err
+}
+
 func (s *executorStoreImpl) RecordHeartbeat(ctx context.Context, namespace, executorID string, request store.HeartbeatState) error {
 	heartbeatKey := etcdkeys.BuildExecutorKey(s.prefix, namespace, executorID, etcdkeys.ExecutorHeartbeatKey)
 	stateKey := etcdkeys.BuildExecutorKey(s.prefix, namespace, executorID, etcdkeys.ExecutorStatusKey)
@@ -133,12 +147,24 @@ func (s *executorStoreImpl) RecordHeartbeat(ctx context.Context, namespace, exec
 		return fmt.Errorf("compress assigned state: %w", err)
 	}
 
+	// The metadata of a heartbeat replaces the previous one, so keys no longer reported are deleted.
+	metadataPrefix := etcdkeys.BuildMetadataKey(s.prefix, namespace, executorID, "")
+	metadataResp, err := s.client.Get(ctx, metadataPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return fmt.Errorf("get executor metadata keys: %w", err)
+	}
+
 	// Build all operations including metadata
 	ops := []clientv3.Op{
 		clientv3.OpPut(heartbeatKey, etcdtypes.FormatTime(request.LastHeartbeat)),
 		clientv3.OpPut(stateKey, string(compressedState)),
 		clientv3.OpPut(reportedShardsKey, string(compressedReportedShards)),
 	}
+	for _, kv := range metadataResp.Kvs {
+		if _, ok := request.Metadata[strings.TrimPrefix(string(kv.Key), metadataPrefix)]; !ok {
+			ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+		}
+	}
 	for key, value := range request.Metadata {
 		metadataKey := etcdkeys.BuildMetadataKey(s.prefix, namespace, executorID, key)
 		ops = append(ops, clientv3.OpPut(metadataKey, value))
@@ -148,15 +174,45 @@ func (s *executorStoreImpl) RecordHeartbeat(ctx context.Context, namespace, exec
 	_, err = s.client.Txn(ctx).Then(ops...).Commit()
 
 	if err != nil {
-		return fmt.Errorf("record heartbeat: %w", err)
+		return fmt.Errorf("record heartbeat: %w", classifyWriteError(err))
 	}
-	if s.cfg.GetLoadBalancingMode(namespace) == types.LoadBalancingModeGREEDY {
+	// A heartbeat without shard reports only carries the executor status, there are no loads to smooth.
+	// Skipping it avoids reading the statistics of the executor and keeps the statistics staged within
+	// the flush interval.
+	if len(request.ReportedShards) > 0 && s.cfg.GetLoadBalancingMode(namespace) == types.LoadBalancingModeGREEDY {
+		start := s.timeSource.Now()
+		defer func() {
+			s.metricsClient.Scope(metrics.ShardDistributorStoreRecordHeartbeatScope).
+				Tagged(metrics.NamespaceTag(namespace)).
+				RecordHistogramDuration(metrics.ShardDistributorShardStatisticsUpdateLatency, s.timeSource.Now().Sub(start))
+		}()
+
 		statsUpdates, err := s.calcUpdatedStatistics(ctx, namespace, executorID, request.ReportedShards)
 		if err != nil {
-			return fmt.Errorf("calculate shard statistics updates: %w", err)
+			return &store.ErrHeartbeatPartiallyApplied{
+				Namespace:  namespace,
+				ExecutorID: executorID,
+				Err:        fmt.Errorf("calculate shard statistics updates: %w", err),
+			}
+		}
+
+		// With a flush interval the statistics are kept in memory and only written once the
+		// interval passed, while the heartbeat itself is always recorded above.
+		flushInterval := jitteredFlushInterval(executorID, s.statisticsFlushInterval(namespace), s.statisticsFlushJitter(namespace))
+		key := coalescerKey(namespace, executorID)
+		now := s.timeSource.Now().UTC()
+		if flushInterval > 0 && !s.statsCoalescer.stage(key, request.Status, executorStatistics(statsUpdates), now, flushInterval) {
+			return nil
 		}
 		if err := s.applyShardStatisticsUpdates(ctx, namespace, statsUpdates); err != nil {
-			return fmt.Errorf("apply shard statistics updates: %w", err)
+			return &store.ErrHeartbeatPartiallyApplied{
+				Namespace:  namespace,
+				ExecutorID: executorID,
+				Err:        fmt.Errorf("apply shard statistics updates: %w", err),
+			}
+		}
+		if flushInterval > 0 {
+			s.statsCoalescer.flushed(key, now)
 		}
 	}
 
@@ -181,6 +237,15 @@ func (s *executorStoreImpl) calcUpdatedStatistics(ctx context.Context, namespace
 		}
 	}
 
+	// When the reports of a flush interval are aggregated, the aggregate is smoothed from the stored
+	// statistics once per interval, instead of every report being smoothed on top of the previous one.
+	key := coalescerKey(namespace, executorID)
+	aggregationMode := s.cfg.GetLoadAggregationMode(namespace)
+	aggregate := aggregationMode != config.LoadAggregationLast && s.statisticsFlushInterval(namespace) > 0
+	if !aggregate {
+		oldStats = s.statsCoalescer.overlay(key, oldStats)
+	}
+
 	now := s.timeSource.Now().UTC()
 	for shardID, report := range reported {
 		if report == nil {
@@ -202,26 +267,66 @@ func (s *executorStoreImpl) calcUpdatedStatistics(ctx context.Context, namespace
 		if shardOwner.ExecutorID != executorID {
 			continue
 		}
+		// A draining shard is winding down, its load says little about what the shard costs once it is
+		// moved, so the stored statistics are written back as they were before the drain started.
+		if report.Status == types.ShardStatusDRAINING && s.cfg.ShouldExcludeDrainingShardLoad(namespace) {
+			if prevStats, ok := oldStats[shardID]; ok {
+				statsUpdate.stats[shardID] = prevStats
+			}
+			continue
+		}
+		// A resent or delayed report is older than the one the statistics already reflect, so they are
+		// written back unchanged.
+		if prevStats, ok := oldStats[shardID]; ok && isStaleReport(report, prevStats) {
+			s.logger.Warn("stale report; skipping smoothed load update",
+				tag.ShardNamespace(namespace),
+				tag.ShardExecutor(executorID),
+				tag.ShardKey(shardID),
+			)
+			statsUpdate.stats[shardID] = prevStats
+			continue
+		}
 
-		statsUpdate.stats[shardID] = s.updateShardStatistic(namespace, executorID, shardID, report.ShardLoad, now, oldStats)
+		loads := statistics.ReportedLoads(report)
+		if aggregate {
+			loads = s.statsCoalescer.aggregate(key, shardID, loads, aggregationMode)
+		}
+		statsUpdate.stats[shardID] = s.updateShardStatistic(namespace, executorID, shardID, loads, report.GetReportTime(), now, oldStats)
 	}
 
 	return []shardStatisticsUpdate{statsUpdate}, nil
 }
 
-func (s *executorStoreImpl) updateShardStatistic(namespace, executorID, shardID string, shardLoad float64, now time.Time, oldStats map[string]etcdtypes.ShardStatistics) etcdtypes.ShardStatistics {
+// isStaleReport reports whether the report is timestamped before the last report applied to stats.
+// Reports without a timestamp are never stale.
+func isStaleReport(report *types.ShardStatusReport, stats etcdtypes.ShardStatistics) bool {
+	reportTime := report.GetReportTime()
+	return !reportTime.IsZero() && stats.LastReportTime != nil && reportTime.Before(stats.LastReportTime.ToTime())
+}
+
+func (s *executorStoreImpl) updateShardStatistic(namespace, executorID, shardID string, shardLoads map[string]float64, reportTime, now time.Time, oldStats map[string]etcdtypes.ShardStatistics) etcdtypes.ShardStatistics {
 	var stats etcdtypes.ShardStatistics
 
 	prevStats, ok := oldStats[shardID]
 	if ok {
+		stats.LastReportTime = prevStats.LastReportTime
 		stats.LastMoveTime = prevStats.LastMoveTime
+		stats.AssignmentHistory = prevStats.AssignmentHistory
+		stats.WeightOverride = prevStats.WeightOverride
+		stats.PinnedExecutor = prevStats.PinnedExecutor
+		stats.PinExpiresAt = prevStats.PinExpiresAt
 	}
 
-	prevSmoothed := prevStats.SmoothedLoad
 	prevUpdate := prevStats.LastUpdateTime.ToTime()
-	newSmoothed, err := statistics.CalculateSmoothedLoad(
-		prevSmoothed,
-		shardLoad,
+	if !prevStats.HasLoadSample() {
+		// The first report of a shard is taken as is instead of being blended with the zero load
+		// of the statistics created when the shard was assigned, which would under-report it.
+		prevUpdate = time.Time{}
+	}
+	newSmoothedLoads, err := statistics.CalculateSmoothedLoads(
+		prevStats.SmoothedLoads,
+		prevStats.SmoothedLoad,
+		shardLoads,
 		prevUpdate,
 		now,
 		s.loadSmoothingTimeConstant(namespace),
@@ -231,12 +336,41 @@ func (s *executorStoreImpl) updateShardStatistic(namespace, executorID, shardID
 			tag.ShardNamespace(namespace),
 			tag.ShardExecutor(executorID),
 			tag.ShardKey(shardID),
+			tag.Error(err),
 		)
-		return etcdtypes.ShardStatistics{LastMoveTime: stats.LastMoveTime}
+		return etcdtypes.ShardStatistics{
+			LastReportTime:    stats.LastReportTime,
+			LastMoveTime:      stats.LastMoveTime,
+			AssignmentHistory: stats.AssignmentHistory,
+			WeightOverride:    stats.WeightOverride,
+			PinnedExecutor:    stats.PinnedExecutor,
+			PinExpiresAt:      stats.PinExpiresAt,
+		}
 	}
 
-	stats.SmoothedLoad = newSmoothed
+	weights := s.cfg.GetLoadDimensionWeights(namespace)
+	combinedLoad := statistics.CombineLoads(shardLoads, weights)
+	stats.SmoothedLoads = newSmoothedLoads
+	stats.SmoothedLoad = statistics.CombineLoads(newSmoothedLoads, weights)
+	stats.LoadHighWatermark = statistics.CalculateLoadHighWatermark(
+		prevStats.LoadHighWatermark,
+		combinedLoad,
+		prevUpdate,
+		now,
+		s.loadHighWatermarkDecay(namespace),
+	)
+	if window := s.loadWindow(namespace); window > 0 {
+		stats.RecentLoads = prevStats.RecentLoads
+		stats.RecordLoad(combinedLoad, now, window)
+	}
+	if resolution, retention, ok := s.cfg.GetLoadHistorySettings(namespace); ok {
+		stats.LoadHistory = prevStats.LoadHistory
+		stats.RecordLoadHistory(now, resolution, retention)
+	}
 	stats.LastUpdateTime = etcdtypes.Time(now)
+	if !reportTime.IsZero() {
+		stats.LastReportTime = etcdtypes.ToTimePtr(&reportTime)
+	}
 
 	return stats
 }
@@ -248,6 +382,54 @@ func (s *executorStoreImpl) loadSmoothingTimeConstant(namespace string) time.Dur
 	return s.cfg.LoadBalancingGreedy.LoadSmoothingTimeConstant(namespace)
 }
 
+// moveLoadRetention returns the share of the smoothed load a shard keeps when it moves to another executor.
+func (s *executorStoreImpl) moveLoadRetention(namespace string) float64 {
+	switch s.cfg.GetMoveLoadReset(namespace) {
+	case config.MoveLoadResetFull:
+		return 0
+	case config.MoveLoadResetPartial:
+		return 0.5
+	default:
+		return 1
+	}
+}
+
+func (s *executorStoreImpl) loadWindow(namespace string) time.Duration {
+	if s.cfg == nil || s.cfg.LoadBalancingGreedy.LoadWindow == nil {
+		return 0
+	}
+	return s.cfg.LoadBalancingGreedy.LoadWindow(namespace)
+}
+
+func (s *executorStoreImpl) statisticsFlushInterval(namespace string) time.Duration {
+	if s.cfg == nil || s.cfg.LoadBalancingGreedy.StatisticsFlushInterval == nil {
+		return 0
+	}
+	return s.cfg.LoadBalancingGreedy.StatisticsFlushInterval(namespace)
+}
+
+func (s *executorStoreImpl) statisticsFlushJitter(namespace string) float64 {
+	if s.cfg == nil || s.cfg.LoadBalancingGreedy.StatisticsFlushJitter == nil {
+		return 0
+	}
+	return s.cfg.LoadBalancingGreedy.StatisticsFlushJitter(namespace)
+}
+
+// executorStatistics returns the statistics of the single executor updated on heartbeat.
+func executorStatistics(updates []shardStatisticsUpdate) map[string]etcdtypes.ShardStatistics {
+	if len(updates) == 0 {
+		return nil
+	}
+	return updates[0].stats
+}
+
+func (s *executorStoreImpl) loadHighWatermarkDecay(namespace string) time.Duration {
+	if s.cfg == nil || s.cfg.LoadBalancingGreedy.LoadHighWatermarkDecay == nil {
+		return statistics.DefaultLoadHighWatermarkDecay
+	}
+	return s.cfg.LoadBalancingGreedy.LoadHighWatermarkDecay(namespace)
+}
+
 // GetHeartbeat retrieves the last known heartbeat state for a single executor.
 func (s *executorStoreImpl) GetHeartbeat(ctx context.Context, namespace string, executorID string) (*store.HeartbeatState, *store.AssignedState, error) {
 	// The prefix for all keys related to a single executor.
@@ -331,6 +513,36 @@ func (s *executorStoreImpl) GetState(ctx context.Context, namespace string) (*st
 	}, nil
 }
 
+// GetShardStats reads the statistics of the executors owning the requested shards from the shard cache,
+// instead of the whole namespace.
+func (s *executorStoreImpl) GetShardStats(ctx context.Context, namespace string, shardIDs []string) (map[string]store.ShardStatistics, error) {
+	owners, err := s.shardCache.GetShardOwners(ctx, namespace, shardIDs)
+	if err != nil {
+		return nil, fmt.Errorf("lookup shard owners: %w", err)
+	}
+	shardsByOwner := make(map[string][]string)
+	for shardID, owner := range owners {
+		shardsByOwner[owner.ExecutorID] = append(shardsByOwner[owner.ExecutorID], shardID)
+	}
+
+	shardStats := make(map[string]store.ShardStatistics, len(shardIDs))
+	for executorID, ownedShards := range shardsByOwner {
+		executorStats, err := s.shardCache.GetExecutorStatistics(ctx, namespace, executorID)
+		if err != nil {
+			if errors.Is(err, store.ErrExecutorNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("get shard statistics for executor %s: %w", executorID, err)
+		}
+		for _, shardID := range ownedShards {
+			if stat, ok := executorStats[shardID]; ok {
+				shardStats[shardID] = *stat.ToShardStatistics()
+			}
+		}
+	}
+	return shardStats, nil
+}
+
 func (s *executorStoreImpl) SubscribeToAssignmentChanges(ctx context.Context, namespace string) (<-chan map[*store.ShardOwner][]string, func(), error) {
 	return s.shardCache.Subscribe(ctx, namespace)
 }
@@ -502,7 +714,7 @@ func (s *executorStoreImpl) AssignShards(ctx context.Context, namespace string,
 	// 6. Check the results of both the outer and nested transactions.
 	if !txnResp.Succeeded {
 		// This means the guard's condition (e.g., leadership) failed.
-		return fmt.Errorf("%w: transaction failed, leadership may have changed", store.ErrVersionConflict)
+		return fmt.Errorf("%w: %w: transaction failed, leadership may have changed", store.ErrVersionConflict, store.ErrLeadershipLost)
 	}
 
 	// The guard's condition passed. Now check if our nested transaction succeeded.
@@ -577,12 +789,12 @@ func (s *executorStoreImpl) AssignShard(ctx context.Context, namespace, shardID,
 		statusModRev := statusResp.Kvs[0].ModRevision
 
 		// 3. Modify the state in memory, adding the new shard if it's not already there.
+		now := s.timeSource.Now().UTC()
 		if _, alreadyAssigned := state.AssignedShards[shardID]; !alreadyAssigned {
-			state.AssignedShards[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+			state.AssignedShards[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY, AssignedAt: now}
 		}
 
 		// Update the last updated timestamp.
-		now := s.timeSource.Now().UTC()
 		state.LastUpdated = etcdtypes.Time(now)
 
 		// Compress new state value
@@ -639,7 +851,7 @@ func (s *executorStoreImpl) AssignShard(ctx context.Context, namespace, shardID,
 				shardStats.SmoothedLoad = 0
 				shardStats.LastUpdateTime = etcdtypes.Time(now)
 			}
-			shardStats.LastMoveTime = etcdtypes.Time(now)
+			shardStats.RecordMove(executorID, now)
 			executorShardStats[shardID] = shardStats
 
 			newStatsValue, err := json.Marshal(executorShardStats)
@@ -685,6 +897,159 @@ func (s *executorStoreImpl) AssignShard(ctx context.Context, namespace, shardID,
 	}
 }
 
+func (s *executorStoreImpl) ReleaseShards(ctx context.Context, namespace, executorID string, shardIDs []string) error {
+	if len(shardIDs) == 0 {
+		return nil
+	}
+	assignedStateKey := etcdkeys.BuildExecutorKey(s.prefix, namespace, executorID, etcdkeys.ExecutorAssignedStateKey)
+
+	// Use a read-modify-write loop to handle concurrent updates safely.
+	for {
+		resp, err := s.client.Get(ctx, assignedStateKey)
+		if err != nil {
+			return fmt.Errorf("get executor assigned state: %w", err)
+		}
+		if len(resp.Kvs) == 0 {
+			// Nothing is assigned to the executor, so the shards are already released.
+			return nil
+		}
+
+		var state etcdtypes.AssignedState
+		if err := common.DecompressAndUnmarshal(resp.Kvs[0].Value, &state); err != nil {
+			return fmt.Errorf("parse assigned state: %w", err)
+		}
+
+		released := 0
+		for _, shardID := range shardIDs {
+			if _, ok := state.AssignedShards[shardID]; ok {
+				delete(state.AssignedShards, shardID)
+				released++
+			}
+		}
+		if released == 0 {
+			return nil
+		}
+		state.LastUpdated = etcdtypes.Time(s.timeSource.Now().UTC())
+
+		newStateValue, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("marshal new assigned state: %w", err)
+		}
+		compressedStateValue, err := s.recordWriter.Write(newStateValue)
+		if err != nil {
+			return fmt.Errorf("compress new assigned state: %w", err)
+		}
+
+		txnResp, err := s.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(assignedStateKey), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(assignedStateKey, string(compressedStateValue))).
+			Commit()
+		if err != nil {
+			return fmt.Errorf("release shards transaction: %w", err)
+		}
+		if txnResp.Succeeded {
+			return nil
+		}
+
+		s.logger.Info("Release shards transaction failed due to a conflict. Retrying...", tag.ShardNamespace(namespace), tag.ShardExecutor(executorID))
+	}
+}
+
+// SetShardWeightOverride sets the weight override of the shard in the statistics of its owner.
+func (s *executorStoreImpl) SetShardWeightOverride(ctx context.Context, namespace, shardID string, weight float64) error {
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("invalid weight override %v: must be a finite non-negative number", weight)
+	}
+	return s.updateShardStatistics(ctx, namespace, shardID, store.NopGuard(), func(stats *etcdtypes.ShardStatistics) {
+		stats.WeightOverride = weight
+	})
+}
+
+// SetShardPin pins the shard to the executor until expiresAt, or clears the pin when executorID is empty.
+func (s *executorStoreImpl) SetShardPin(ctx context.Context, namespace, shardID, executorID string, expiresAt time.Time, guard store.GuardFunc) error {
+	if executorID != "" && expiresAt.IsZero() {
+		return fmt.Errorf("invalid pin of shard %s: the pin must expire", shardID)
+	}
+	return s.updateShardStatistics(ctx, namespace, shardID, guard, func(stats *etcdtypes.ShardStatistics) {
+		stats.PinnedExecutor = executorID
+		stats.PinExpiresAt = nil
+		if executorID != "" {
+			pinExpiresAt := etcdtypes.Time(expiresAt)
+			stats.PinExpiresAt = &pinExpiresAt
+		}
+	})
+}
+
+// updateShardStatistics applies update to the statistics of the shard, stored with the statistics of its owner.
+// Statistics are created for a shard that has none yet. The write is fenced by guard.
+func (s *executorStoreImpl) updateShardStatistics(ctx context.Context, namespace, shardID string, guard store.GuardFunc, update func(*etcdtypes.ShardStatistics)) error {
+	owner, err := s.shardCache.GetShardOwner(ctx, namespace, shardID)
+	if err != nil {
+		return fmt.Errorf("lookup shard owner: %w", err)
+	}
+	statsKey := etcdkeys.BuildExecutorKey(s.prefix, namespace, owner.ExecutorID, etcdkeys.ExecutorShardStatisticsKey)
+
+	// Use a read-modify-write loop, so statistics written by a concurrent heartbeat are not lost.
+	for {
+		resp, err := s.client.Get(ctx, statsKey)
+		if err != nil {
+			return fmt.Errorf("get executor shard statistics: %w", err)
+		}
+
+		executorStats := make(map[string]etcdtypes.ShardStatistics)
+		var modRevision int64
+		if len(resp.Kvs) > 0 {
+			if err := common.DecompressAndUnmarshal(resp.Kvs[0].Value, &executorStats); err != nil {
+				return fmt.Errorf("parse executor shard statistics: %w", err)
+			}
+			modRevision = resp.Kvs[0].ModRevision
+		}
+
+		stats := executorStats[shardID]
+		update(&stats)
+		executorStats[shardID] = stats
+
+		payload, err := json.Marshal(executorStats)
+		if err != nil {
+			return fmt.Errorf("marshal executor shard statistics: %w", err)
+		}
+		compressedPayload, err := s.recordWriter.Write(payload)
+		if err != nil {
+			return fmt.Errorf("compress executor shard statistics: %w", err)
+		}
+
+		guardedTxn, err := guard(s.client.Txn(ctx))
+		if err != nil {
+			return fmt.Errorf("apply transaction guard: %w", err)
+		}
+		etcdGuardedTxn, ok := guardedTxn.(clientv3.Txn)
+		if !ok {
+			return fmt.Errorf("guard function returned invalid transaction type")
+		}
+
+		// The revision check is nested, so a conflict can be told apart from a failed guard.
+		txnResp, err := etcdGuardedTxn.Then(clientv3.OpTxn(
+			[]clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(statsKey), "=", modRevision)},
+			[]clientv3.Op{clientv3.OpPut(statsKey, string(compressedPayload))},
+			nil,
+		)).Commit()
+		if err != nil {
+			return fmt.Errorf("update shard statistics transaction: %w", err)
+		}
+		if !txnResp.Succeeded {
+			return fmt.Errorf("%w: transaction failed, leadership may have changed", store.ErrLeadershipLost)
+		}
+		if len(txnResp.Responses) == 0 {
+			return fmt.Errorf("unexpected empty response from transaction")
+		}
+		if txnResp.Responses[0].GetResponseTxn().Succeeded {
+			return nil
+		}
+
+		s.logger.Info("Update shard statistics transaction failed due to a conflict. Retrying...", tag.ShardNamespace(namespace), tag.ShardKey(shardID))
+	}
+}
+
 // commitGuardedOps commits the given operations in batches to stay within etcd's per-transaction operation limit.
 // Each batch creates a new guarded transaction. If any batch fails, the function returns immediately
 // with the error
@@ -722,7 +1087,7 @@ func (s *executorStoreImpl) commitGuardedOps(ctx context.Context, ops []clientv3
 			return fmt.Errorf("commit batch: %w", err)
 		}
 		if !resp.Succeeded {
-			return fmt.Errorf("transaction failed, leadership may have changed")
+			return fmt.Errorf("%w: transaction failed, leadership may have changed", store.ErrLeadershipLost)
 		}
 	}
 	return nil
@@ -744,6 +1109,9 @@ func (s *executorStoreImpl) DeleteExecutors(ctx context.Context, namespace strin
 	if err := s.commitGuardedOps(ctx, ops, guard); err != nil {
 		return fmt.Errorf("delete executors: %w", err)
 	}
+	for _, executorID := range executorIDs {
+		s.statsCoalescer.forget(coalescerKey(namespace, executorID))
+	}
 	return nil
 }
 
@@ -848,16 +1216,23 @@ func (s *executorStoreImpl) GetShardOwner(ctx context.Context, namespace, shardI
 	return s.shardCache.GetShardOwner(ctx, namespace, shardID)
 }
 
+func (s *executorStoreImpl) GetShardOwners(ctx context.Context, namespace string, shardIDs []string) (map[string]*store.ShardOwner, error) {
+	return s.shardCache.GetShardOwners(ctx, namespace, shardIDs)
+}
+
 func (s *executorStoreImpl) GetExecutor(ctx context.Context, namespace string, executorID string) (*store.ShardOwner, error) {
 	return s.shardCache.GetExecutor(ctx, namespace, executorID)
 }
 
 // prepareShardStatisticsUpdates calculates the necessary changes to shard statistics based on a new shard assignment plan.
 // It determines which shards have moved between executors, which are new, and prepares a list of updates
-// that remove a moved shard's stats from its old owner and add them to its new owner, recording the time of the move.
+// that remove a moved shard's stats from its old owner and add them to its new owner, recording the time of the move
+// and the new owner in the shard's assignment history. The smoothed load of a moved shard is reset per the
+// namespace's move load reset policy.
 func (s *executorStoreImpl) prepareShardStatisticsUpdates(ctx context.Context, namespace string, newAssignments map[string]store.AssignedState) ([]shardStatisticsUpdate, error) {
 	// statsUpdatesByExecutor contains per-executor stats maps that will be written back.
 	statsUpdatesByExecutor := make(map[string]map[string]etcdtypes.ShardStatistics)
+	loadRetention := s.moveLoadRetention(namespace)
 
 	for newOwnerID, state := range newAssignments {
 		for shardID := range state.AssignedShards {
@@ -883,6 +1258,7 @@ func (s *executorStoreImpl) prepareShardStatisticsUpdates(ctx context.Context, n
 				LastUpdateTime: etcdtypes.Time(now),
 			}
 
+			moved := false
 			if oldOwner != nil {
 				previousStats, ok := statsUpdatesByExecutor[oldOwner.ExecutorID]
 				if !ok {
@@ -895,10 +1271,16 @@ func (s *executorStoreImpl) prepareShardStatisticsUpdates(ctx context.Context, n
 				if previousStatForShard, ok := previousStats[shardID]; ok {
 					// Carry over the accumulated load and update the move time.
 					newStatForShard = previousStatForShard
-					newStatForShard.LastMoveTime = etcdtypes.Time(now)
+					moved = true
 					delete(previousStats, shardID)
 				}
 			}
+			if moved {
+				newStatForShard.ScaleSmoothedLoad(loadRetention)
+				newStatForShard.RecordMove(newOwnerID, now)
+			} else {
+				newStatForShard.RecordOwnerChange(newOwnerID, now)
+			}
 
 			newOwnerStats, ok := statsUpdatesByExecutor[newOwnerID]
 			if !ok {
@@ -949,11 +1331,17 @@ func (s *executorStoreImpl) applyShardStatisticsUpdates(ctx context.Context, nam
 			continue
 		}
 
-		payload, err := json.Marshal(update.stats)
+		payload, failedShards, err := marshalShardStatistics(update.stats)
 		if err != nil {
 			multiError = errors.Join(multiError, fmt.Errorf("failed to marshal executor shard statistics: %w", err))
 			continue
 		}
+		if len(failedShards) > 0 {
+			s.logger.Warn("Dropping shard statistics that cannot be marshaled",
+				tag.ShardNamespace(namespace),
+				tag.ShardExecutor(update.executorID),
+				tag.Dynamic("failed-shards", failedShards))
+		}
 
 		compressedPayload, err := s.recordWriter.Write(payload)
 		if err != nil {
@@ -967,3 +1355,33 @@ func (s *executorStoreImpl) applyShardStatisticsUpdates(ctx context.Context, nam
 	}
 	return multiError
 }
+
+// marshalShardStatistics marshals the statistics of the shards of an executor, which are stored under one key.
+// When they cannot be marshaled together, e.g. because the load of a shard is NaN, each shard is marshaled on
+// its own and the failing ones are left out and returned, so one bad shard does not lose the statistics of the rest.
+func marshalShardStatistics(stats map[string]etcdtypes.ShardStatistics) ([]byte, map[string]error, error) {
+	payload, err := json.Marshal(stats)
+	if err == nil {
+		return payload, nil, nil
+	}
+
+	valid := make(map[string]etcdtypes.ShardStatistics, len(stats))
+	failedShards := make(map[string]error)
+	for shardID, shardStats := range stats {
+		if _, shardErr := json.Marshal(shardStats); shardErr != nil {
+			failedShards[shardID] = shardErr
+			continue
+		}
+		valid[shardID] = shardStats
+	}
+	if len(failedShards) == 0 {
+		// The failure cannot be attributed to a shard.
+		return nil, nil, err
+	}
+
+	payload, err = json.Marshal(valid)
+	if err != nil {
+		return nil, failedShards, err
+	}
+	return payload, failedShards, nil
+}
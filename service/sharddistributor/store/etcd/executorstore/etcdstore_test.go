@@ -3,13 +3,18 @@ package executorstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/fx/fxtest"
 	"go.uber.org/mock/gomock"
@@ -21,12 +26,14 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer"
 	"github.com/uber/cadence/service/sharddistributor/statistics"
 	"github.com/uber/cadence/service/sharddistributor/store"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/etcdclient"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/etcdkeys"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/etcdtypes"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/executorstore/common"
+	"github.com/uber/cadence/service/sharddistributor/store/etcd/executorstore/shardcache"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/leaderstore"
 	"github.com/uber/cadence/service/sharddistributor/store/etcd/testhelper"
 )
@@ -156,6 +163,66 @@ func TestRecordHeartbeat_NoCompression(t *testing.T) {
 	assert.Equal(t, string(reportedJSON), string(reportedResp.Kvs[0].Value))
 }
 
+func TestRecordHeartbeatLabelsRoundTrip(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-labels"
+	labels := map[string]string{"version": "v2", "region": "us-east"}
+	req := store.HeartbeatState{
+		LastHeartbeat: time.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		Metadata: map[string]string{
+			"key-1": "value-1",
+			store.ExecutorMetadataLabelPrefix + "version": "v2",
+			store.ExecutorMetadataLabelPrefix + "region":  "us-east",
+		},
+	}
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, req))
+
+	nsState, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	require.Contains(t, nsState.Executors, executorID)
+	assert.Equal(t, labels, nsState.Executors[executorID].Labels())
+	assert.Equal(t, "value-1", nsState.Executors[executorID].Metadata["key-1"])
+}
+
+func TestRecordHeartbeatDeletesUnreportedMetadata(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-metadata"
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: time.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		Metadata: map[string]string{
+			store.ExecutorMetadataZoneKey:              "zone-a",
+			store.ExecutorMetadataRoleKey:              types.ExecutorRoleSTANDBY.String(),
+			store.ExecutorMetadataHeadroomKey:          "0.5",
+			store.ExecutorMetadataLabelPrefix + "tier": "gold",
+		},
+	}))
+
+	// The executor is promoted to a worker, stops reporting its headroom and drops its label.
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: time.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		Metadata:      map[string]string{store.ExecutorMetadataZoneKey: "zone-a"},
+	}))
+
+	heartbeat, _, err := executorStore.GetHeartbeat(ctx, tc.Namespace, executorID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{store.ExecutorMetadataZoneKey: "zone-a"}, heartbeat.Metadata)
+	assert.Equal(t, types.ExecutorRoleWORKER, heartbeat.Role())
+	assert.Empty(t, heartbeat.Labels())
+}
+
 func TestRecordHeartbeatUpdatesShardStatistics(t *testing.T) {
 	tc := testhelper.SetupStoreTestCluster(t)
 	executorStore := createStore(t, tc)
@@ -183,6 +250,8 @@ func TestRecordHeartbeatUpdatesShardStatistics(t *testing.T) {
 	require.True(t, ok)
 
 	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	testScope := tally.NewTestScope("test", nil)
+	impl.metricsClient = metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
 
 	req := store.HeartbeatState{
 		LastHeartbeat: impl.timeSource.Now().UTC(),
@@ -197,6 +266,14 @@ func TestRecordHeartbeatUpdatesShardStatistics(t *testing.T) {
 
 	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, req))
 
+	snapshot := testScope.Snapshot()
+	latencyRecorded := false
+	for _, histogram := range snapshot.Histograms() {
+		latencyRecorded = latencyRecorded || histogram.Name() == "test.shard_distributor_shard_statistics_update_latency"
+	}
+	assert.True(t, latencyRecorded, "the statistics update latency is recorded as a histogram")
+	assert.Empty(t, snapshot.Timers())
+
 	nsState, err := executorStore.GetState(ctx, tc.Namespace)
 	require.NoError(t, err)
 
@@ -265,6 +342,286 @@ func TestRecordHeartbeatSkipsShardStatisticsWithNilReport(t *testing.T) {
 	assert.NotContains(t, nsState.ShardStats, skippedShardID)
 }
 
+// TestRecordHeartbeatDrainingShardKeepsSmoothedLoad verifies that the load reported for a draining shard
+// does not update its smoothed load.
+func TestRecordHeartbeatDrainingShardKeepsSmoothedLoad(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	setLoadBalancingMode(executorStore, config.LoadBalancingModeGREEDY)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-draining-shard"
+	shardID := "shard-draining"
+
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+
+	impl := executorStore.(*executorStoreImpl)
+	assert.Eventually(t, func() bool {
+		owner, err := impl.shardCache.GetShardOwner(ctx, tc.Namespace, shardID)
+		return err == nil && owner.ExecutorID == executorID
+	}, 5*time.Second, 50*time.Millisecond)
+
+	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: impl.timeSource.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{
+			shardID: {Status: types.ShardStatusREADY, ShardLoad: 10},
+		},
+	}))
+
+	stateBeforeDrain, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	beforeStats, ok := stateBeforeDrain.ShardStats[shardID]
+	require.True(t, ok)
+
+	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: impl.timeSource.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{
+			shardID: {Status: types.ShardStatusDRAINING, ShardLoad: 500},
+		},
+	}))
+
+	nsState, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	afterStats, ok := nsState.ShardStats[shardID]
+	require.True(t, ok)
+	assert.InDelta(t, beforeStats.SmoothedLoad, afterStats.SmoothedLoad, 1e-9)
+	assert.Equal(t, beforeStats.LastUpdateTime, afterStats.LastUpdateTime)
+}
+
+func TestRecordHeartbeatStaleReportKeepsSmoothedLoad(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	setLoadBalancingMode(executorStore, config.LoadBalancingModeGREEDY)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-stale-report"
+	shardID := "shard-stale-report"
+
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+
+	impl := executorStore.(*executorStoreImpl)
+	assert.Eventually(t, func() bool {
+		owner, err := impl.shardCache.GetShardOwner(ctx, tc.Namespace, shardID)
+		return err == nil && owner.ExecutorID == executorID
+	}, 5*time.Second, 50*time.Millisecond)
+
+	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	reportTime := impl.timeSource.Now().UTC()
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: impl.timeSource.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{
+			shardID: {Status: types.ShardStatusREADY, ShardLoad: 10, ReportTime: reportTime},
+		},
+	}))
+
+	stateBeforeStaleReport, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	beforeStats, ok := stateBeforeStaleReport.ShardStats[shardID]
+	require.True(t, ok)
+	assert.Equal(t, reportTime.UnixNano(), beforeStats.LastReportTime.UnixNano())
+
+	// The executor resends a report taken before the one already applied.
+	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat: impl.timeSource.Now().UTC(),
+		Status:        types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{
+			shardID: {Status: types.ShardStatusREADY, ShardLoad: 500, ReportTime: reportTime.Add(-time.Second)},
+		},
+	}))
+
+	nsState, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	afterStats, ok := nsState.ShardStats[shardID]
+	require.True(t, ok)
+	assert.InDelta(t, beforeStats.SmoothedLoad, afterStats.SmoothedLoad, 1e-9)
+	assert.Equal(t, beforeStats.LastUpdateTime, afterStats.LastUpdateTime)
+}
+
+// TestCalcUpdatedStatisticsKeepsSkippedShards verifies that the statistics of a shard whose report is not
+// applied are written back unchanged, since the statistics of all shards of an executor share one key.
+func TestCalcUpdatedStatisticsKeepsSkippedShards(t *testing.T) {
+	now := time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC)
+	lastReport := now.Add(-time.Minute)
+	pinExpiry := now.Add(time.Hour)
+	stored := etcdtypes.ShardStatistics{
+		SmoothedLoad:      10,
+		LastUpdateTime:    etcdtypes.Time(lastReport),
+		LastReportTime:    etcdtypes.ToTimePtr(&lastReport),
+		LastMoveTime:      etcdtypes.Time(now.Add(-time.Hour)),
+		AssignmentHistory: []etcdtypes.ShardOwnerChange{{ExecutorID: "executor-1", AssignedAt: etcdtypes.Time(now.Add(-time.Hour))}},
+		WeightOverride:    2,
+		PinnedExecutor:    "executor-1",
+		PinExpiresAt:      etcdtypes.ToTimePtr(&pinExpiry),
+	}
+
+	tests := []struct {
+		name   string
+		report *types.ShardStatusReport
+	}{
+		{
+			name:   "draining shard",
+			report: &types.ShardStatusReport{Status: types.ShardStatusDRAINING, ShardLoad: 500},
+		},
+		{
+			name:   "stale report",
+			report: &types.ShardStatusReport{Status: types.ShardStatusREADY, ShardLoad: 500, ReportTime: lastReport.Add(-time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, written := newStoreWithShardStatistics(t, now, "executor-1", map[string]etcdtypes.ShardStatistics{"shard-1": stored})
+
+			updates, err := s.calcUpdatedStatistics(context.Background(), "test-ns", "executor-1", map[string]*types.ShardStatusReport{"shard-1": tt.report})
+			require.NoError(t, err)
+			require.NoError(t, s.applyShardStatisticsUpdates(context.Background(), "test-ns", updates))
+
+			require.Contains(t, *written, "shard-1")
+			expected, err := json.Marshal(stored)
+			require.NoError(t, err)
+			actual, err := json.Marshal((*written)["shard-1"])
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+// newStoreWithShardStatistics returns a store whose etcd client serves executorID owning the shards of stats
+// with those statistics. The statistics the store writes for executorID are decoded into the returned map.
+func newStoreWithShardStatistics(t *testing.T, now time.Time, executorID string, stats map[string]etcdtypes.ShardStatistics) (*executorStoreImpl, *map[string]etcdtypes.ShardStatistics) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockClient := etcdclient.NewMockClient(ctrl)
+	recordWriter, err := common.NewRecordWriter("")
+	require.NoError(t, err)
+
+	assignedState := etcdtypes.AssignedState{AssignedShards: make(map[string]*types.ShardAssignment)}
+	for shardID := range stats {
+		assignedState.AssignedShards[shardID] = &types.ShardAssignment{Status: types.AssignmentStatusREADY}
+	}
+	assignedStateValue, err := json.Marshal(assignedState)
+	require.NoError(t, err)
+	statsValue, err := json.Marshal(stats)
+	require.NoError(t, err)
+
+	assignedStateKey := etcdkeys.BuildExecutorKey("/test", "test-ns", executorID, etcdkeys.ExecutorAssignedStateKey)
+	statsKey := etcdkeys.BuildExecutorKey("/test", "test-ns", executorID, etcdkeys.ExecutorShardStatisticsKey)
+	kvs := []*mvccpb.KeyValue{
+		{Key: []byte(assignedStateKey), Value: assignedStateValue, ModRevision: 1},
+		{Key: []byte(statsKey), Value: statsValue, ModRevision: 1},
+	}
+	mockClient.EXPECT().Watch(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan clientv3.WatchResponse)).AnyTimes()
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+			if key == statsKey {
+				return &clientv3.GetResponse{Kvs: kvs[1:]}, nil
+			}
+			return &clientv3.GetResponse{Kvs: kvs}, nil
+		},
+	).AnyTimes()
+
+	written := make(map[string]etcdtypes.ShardStatistics)
+	mockClient.EXPECT().Put(gomock.Any(), statsKey, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, value string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+			written = make(map[string]etcdtypes.ShardStatistics)
+			return &clientv3.PutResponse{}, common.DecompressAndUnmarshal([]byte(value), &written)
+		},
+	).AnyTimes()
+
+	timeSource := clock.NewMockedTimeSourceAt(now)
+	shardCache := shardcache.NewShardToExecutorCache("/test", mockClient, testlogger.New(t), timeSource, metrics.NewNoopMetricsClient())
+	t.Cleanup(shardCache.Stop)
+
+	s := &executorStoreImpl{
+		client:        mockClient,
+		prefix:        "/test",
+		logger:        testlogger.New(t),
+		shardCache:    shardCache,
+		timeSource:    timeSource,
+		recordWriter:  recordWriter,
+		metricsClient: metrics.NewNoopMetricsClient(),
+		cfg: &config.Config{
+			LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return statistics.DefaultLoadSmoothingTimeConstant },
+			},
+		},
+		statsCoalescer: newStatisticsCoalescer(),
+	}
+	return s, &written
+}
+
+func TestIsStaleReport(t *testing.T) {
+	now := time.Date(2025, 11, 18, 16, 0, 0, 0, time.UTC)
+	applied := etcdtypes.ShardStatistics{LastReportTime: etcdtypes.ToTimePtr(&now)}
+
+	assert.True(t, isStaleReport(&types.ShardStatusReport{ReportTime: now.Add(-time.Second)}, applied))
+	assert.False(t, isStaleReport(&types.ShardStatusReport{ReportTime: now}, applied))
+	assert.False(t, isStaleReport(&types.ShardStatusReport{ReportTime: now.Add(time.Second)}, applied))
+	assert.False(t, isStaleReport(&types.ShardStatusReport{}, applied), "a report without a timestamp is never stale")
+	assert.False(t, isStaleReport(&types.ShardStatusReport{ReportTime: now}, etcdtypes.ShardStatistics{}), "no report was timestamped before")
+}
+
+// TestRecordHeartbeatStatusOnlySkipsShardStatistics verifies that a heartbeat without shard reports only
+// records the heartbeat, without reading or writing shard statistics.
+func TestRecordHeartbeatStatusOnlySkipsShardStatistics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := etcdclient.NewMockClient(ctrl)
+	recordWriter, err := common.NewRecordWriter("")
+	require.NoError(t, err)
+
+	var heartbeatOps int
+	mockClient.EXPECT().Get(gomock.Any(), "/test/test-ns/executors/executor-1/metadata/", gomock.Any()).Return(&clientv3.GetResponse{}, nil)
+	mockClient.EXPECT().Txn(gomock.Any()).Return(&trackingTxn{
+		commitFn: func(numOps int) (*clientv3.TxnResponse, error) {
+			heartbeatOps = numOps
+			return &clientv3.TxnResponse{Succeeded: true}, nil
+		},
+	})
+
+	now := time.Now().UTC()
+	coalescer := newStatisticsCoalescer()
+	staged := map[string]etcdtypes.ShardStatistics{"shard-1": {SmoothedLoad: 4}}
+	require.True(t, coalescer.stage(coalescerKey("test-ns", "executor-1"), types.ExecutorStatusACTIVE, staged, now, time.Minute))
+
+	// The shard cache is left nil, reading statistics through it would panic.
+	s := &executorStoreImpl{
+		client:        mockClient,
+		prefix:        "/test",
+		logger:        testlogger.New(t),
+		timeSource:    clock.NewMockedTimeSourceAt(now),
+		recordWriter:  recordWriter,
+		metricsClient: metrics.NewNoopMetricsClient(),
+		cfg: &config.Config{
+			LoadBalancingMode: func(string) string { return config.LoadBalancingModeGREEDY },
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				StatisticsFlushInterval: func(string) time.Duration { return time.Minute },
+			},
+		},
+		statsCoalescer: coalescer,
+	}
+
+	err = s.RecordHeartbeat(context.Background(), "test-ns", "executor-1", store.HeartbeatState{
+		LastHeartbeat: now,
+		Status:        types.ExecutorStatusDRAINING,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, heartbeatOps, "heartbeat, status and reported shards are written")
+	assert.Equal(t, staged, coalescer.pending[coalescerKey("test-ns", "executor-1")].stats, "staged statistics are kept")
+}
+
 func TestGetHeartbeat(t *testing.T) {
 	tc := testhelper.SetupStoreTestCluster(t)
 	executorStore := createStore(t, tc)
@@ -469,6 +826,105 @@ func TestAssignShards_WithRevisions(t *testing.T) {
 	})
 }
 
+// TestAssignShards_RecordsAssignmentHistory verifies that moving a shard appends its new owner to the shard's assignment history.
+func TestAssignShards_RecordsAssignmentHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	setLoadBalancingMode(executorStore, config.LoadBalancingModeGREEDY)
+	recordHeartbeats(ctx, t, executorStore, tc.Namespace, "exec-1", "exec-2")
+
+	assign := func(executorID string) {
+		state, err := executorStore.GetState(ctx, tc.Namespace)
+		require.NoError(t, err)
+		newAssignments := make(map[string]store.AssignedState)
+		for id, assigned := range state.ShardAssignments {
+			newAssignments[id] = store.AssignedState{ModRevision: assigned.ModRevision}
+		}
+		newAssignments[executorID] = store.AssignedState{
+			AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}},
+			ModRevision:    state.ShardAssignments[executorID].ModRevision,
+		}
+		state.ShardAssignments = newAssignments
+		require.NoError(t, executorStore.AssignShards(ctx, tc.Namespace, store.AssignShardsRequest{NewState: state}, store.NopGuard()))
+		require.Eventually(t, func() bool {
+			owner, err := executorStore.GetShardOwner(ctx, tc.Namespace, "shard-1")
+			return err == nil && owner.ExecutorID == executorID
+		}, time.Second, time.Millisecond)
+	}
+
+	assign("exec-1")
+	assign("exec-2")
+
+	state, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	history := state.ShardStats["shard-1"].AssignmentHistory
+	require.Len(t, history, 2)
+	assert.Equal(t, "exec-1", history[0].ExecutorID)
+	assert.Equal(t, "exec-2", history[1].ExecutorID)
+	assert.Equal(t, 2, history.MovesSince(history[0].AssignedAt))
+}
+
+func TestAssignShards_ResetsSmoothedLoadOfMovedShard(t *testing.T) {
+	tests := []struct {
+		reset        string
+		expectedLoad float64
+	}{
+		{reset: config.MoveLoadResetNone, expectedLoad: 8},
+		{reset: config.MoveLoadResetPartial, expectedLoad: 4},
+		{reset: config.MoveLoadResetFull, expectedLoad: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reset, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			tc := testhelper.SetupStoreTestCluster(t)
+			executorStore := createStore(t, tc)
+			setLoadBalancingMode(executorStore, config.LoadBalancingModeGREEDY)
+			executorStore.(*executorStoreImpl).cfg.LoadBalancingGreedy.MoveLoadReset = func(string) string { return tt.reset }
+			recordHeartbeats(ctx, t, executorStore, tc.Namespace, "exec-1", "exec-2")
+
+			// Seed the statistics of the shard under its current owner.
+			payload, err := json.Marshal(map[string]etcdtypes.ShardStatistics{"shard-1": {SmoothedLoad: 8}})
+			require.NoError(t, err)
+			writer, err := common.NewRecordWriter(tc.Compression)
+			require.NoError(t, err)
+			compressedPayload, err := writer.Write(payload)
+			require.NoError(t, err)
+			statsKey := etcdkeys.BuildExecutorKey(tc.EtcdPrefix, tc.Namespace, "exec-1", etcdkeys.ExecutorShardStatisticsKey)
+			_, err = tc.Client.Put(ctx, statsKey, string(compressedPayload))
+			require.NoError(t, err)
+
+			require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, "shard-1", "exec-1"))
+			require.Eventually(t, func() bool {
+				owner, err := executorStore.GetShardOwner(ctx, tc.Namespace, "shard-1")
+				return err == nil && owner.ExecutorID == "exec-1"
+			}, time.Second, time.Millisecond)
+
+			state, err := executorStore.GetState(ctx, tc.Namespace)
+			require.NoError(t, err)
+			require.Equal(t, 8.0, state.ShardStats["shard-1"].SmoothedLoad)
+			state.ShardAssignments = map[string]store.AssignedState{
+				"exec-1": {ModRevision: state.ShardAssignments["exec-1"].ModRevision},
+				"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"shard-1": {Status: types.AssignmentStatusREADY}}},
+			}
+			require.NoError(t, executorStore.AssignShards(ctx, tc.Namespace, store.AssignShardsRequest{NewState: state}, store.NopGuard()))
+			require.Eventually(t, func() bool {
+				owner, err := executorStore.GetShardOwner(ctx, tc.Namespace, "shard-1")
+				return err == nil && owner.ExecutorID == "exec-2"
+			}, time.Second, time.Millisecond)
+
+			state, err = executorStore.GetState(ctx, tc.Namespace)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLoad, state.ShardStats["shard-1"].SmoothedLoad)
+		})
+	}
+}
+
 // TestGuardedOperations verifies that AssignShards and DeleteExecutors respect the leader guard.
 func TestGuardedOperations(t *testing.T) {
 	tc := testhelper.SetupStoreTestCluster(t)
@@ -758,6 +1214,125 @@ func TestAssignShardErrors(t *testing.T) {
 	assert.ErrorIs(t, err, store.ErrVersionConflict, "Error should be ErrVersionConflict for non-active executor")
 }
 
+func TestReleaseShards(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-release"
+	recordHeartbeats(ctx, t, executorStore, tc.Namespace, executorID)
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, "shard-done", executorID))
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, "shard-running", executorID))
+
+	require.NoError(t, executorStore.ReleaseShards(ctx, tc.Namespace, executorID, []string{"shard-done", "shard-unknown"}))
+
+	state, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	assert.NotContains(t, state.ShardAssignments[executorID].AssignedShards, "shard-done")
+	assert.Contains(t, state.ShardAssignments[executorID].AssignedShards, "shard-running")
+
+	// Releasing again, or releasing from an executor without assignments, is a no-op.
+	require.NoError(t, executorStore.ReleaseShards(ctx, tc.Namespace, executorID, []string{"shard-done"}))
+	require.NoError(t, executorStore.ReleaseShards(ctx, tc.Namespace, "executor-without-assignments", []string{"shard-done"}))
+}
+
+func TestSetShardWeightOverride(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-weight-override"
+	shardID := "shard-heavy"
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+
+	impl := executorStore.(*executorStoreImpl)
+	require.Eventually(t, func() bool {
+		owner, err := impl.shardCache.GetShardOwner(ctx, tc.Namespace, shardID)
+		return err == nil && owner.ExecutorID == executorID
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, executorStore.SetShardWeightOverride(ctx, tc.Namespace, shardID, 80))
+	require.Error(t, executorStore.SetShardWeightOverride(ctx, tc.Namespace, shardID, -1))
+	assert.ErrorIs(t, executorStore.SetShardWeightOverride(ctx, tc.Namespace, "shard-unknown", 80), store.ErrShardNotFound)
+
+	// The override survives a heartbeat reporting the load of the shard.
+	require.Eventually(t, func() bool {
+		stats, err := impl.shardCache.GetExecutorStatistics(ctx, tc.Namespace, executorID)
+		return err == nil && stats[shardID].WeightOverride == 80
+	}, 5*time.Second, 50*time.Millisecond)
+	impl.timeSource.(clock.MockedTimeSource).Advance(5 * time.Second)
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{
+		LastHeartbeat:  impl.timeSource.Now().UTC(),
+		Status:         types.ExecutorStatusACTIVE,
+		ReportedShards: map[string]*types.ShardStatusReport{shardID: {Status: types.ShardStatusREADY, ShardLoad: 3}},
+	}))
+
+	state, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, state.ShardStats[shardID].WeightOverride)
+	assert.Equal(t, 80.0, state.ShardStats[shardID].Load())
+	assert.Equal(t, 3.0, state.ShardStats[shardID].SmoothedLoad)
+
+	// A zero weight clears the override.
+	require.NoError(t, executorStore.SetShardWeightOverride(ctx, tc.Namespace, shardID, 0))
+	state, err = executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	assert.Zero(t, state.ShardStats[shardID].WeightOverride)
+	assert.Equal(t, 3.0, state.ShardStats[shardID].Load())
+}
+
+func TestSetShardPin(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	executorID := "executor-pin"
+	shardID := "shard-pinned"
+	require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+	require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+
+	impl := executorStore.(*executorStoreImpl)
+	require.Eventually(t, func() bool {
+		owner, err := impl.shardCache.GetShardOwner(ctx, tc.Namespace, shardID)
+		return err == nil && owner.ExecutorID == executorID
+	}, 5*time.Second, 50*time.Millisecond)
+
+	now := impl.timeSource.Now().UTC()
+	expiresAt := now.Add(time.Hour)
+
+	// A guard that fails, as the guard of a leader that lost its leadership does, rejects the pin.
+	lostGuard := func(txn store.Txn) (store.Txn, error) {
+		return txn.(clientv3.Txn).If(clientv3.Compare(clientv3.CreateRevision("missing-leader-key"), ">", 0)), nil
+	}
+	assert.ErrorIs(t, executorStore.SetShardPin(ctx, tc.Namespace, shardID, "executor-debug", expiresAt, lostGuard), store.ErrLeadershipLost)
+	require.Error(t, executorStore.SetShardPin(ctx, tc.Namespace, shardID, "executor-debug", time.Time{}, store.NopGuard()))
+	assert.ErrorIs(t, executorStore.SetShardPin(ctx, tc.Namespace, "shard-unknown", "executor-debug", expiresAt, store.NopGuard()), store.ErrShardNotFound)
+
+	state, err := executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	_, pinned := state.ShardStats[shardID].ActivePin(now)
+	assert.False(t, pinned)
+
+	require.NoError(t, executorStore.SetShardPin(ctx, tc.Namespace, shardID, "executor-debug", expiresAt, store.NopGuard()))
+
+	state, err = executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	// The balancers keep the shard on the pinned executor until the pin expires.
+	assert.Equal(t, map[string]string{shardID: "executor-debug"}, loadbalancer.PinnedShards(&config.Config{}, tc.Namespace, state, now))
+	assert.Empty(t, loadbalancer.PinnedShards(&config.Config{}, tc.Namespace, state, expiresAt), "the pin is not honored once it expired")
+
+	// Clearing the pin releases the shard.
+	require.NoError(t, executorStore.SetShardPin(ctx, tc.Namespace, shardID, "", time.Time{}, store.NopGuard()))
+	state, err = executorStore.GetState(ctx, tc.Namespace)
+	require.NoError(t, err)
+	_, pinned = state.ShardStats[shardID].ActivePin(now)
+	assert.False(t, pinned)
+}
+
 // TestShardStatisticsPersistence verifies that shard statistics are preserved on assignment
 // when they already exist, and that GetState exposes them.
 func TestShardStatisticsPersistence(t *testing.T) {
@@ -826,6 +1401,66 @@ func TestGetShardStatisticsForMissingShard(t *testing.T) {
 	assert.NotContains(t, st.ShardStats, "unknown")
 }
 
+// TestGetShardStatsReturnsRequestedShardsOnly verifies that GetShardStats only returns the statistics
+// of the requested shards, across executors, and leaves out shards it knows nothing about.
+func TestGetShardStatsReturnsRequestedShardsOnly(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	writer, err := common.NewRecordWriter(tc.Compression)
+	require.NoError(t, err)
+	owned := map[string][]string{
+		"exec-1": {"shard-1", "shard-2"},
+		"exec-2": {"shard-3"},
+	}
+	for executorID, shardIDs := range owned {
+		require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+		executorStats := make(map[string]etcdtypes.ShardStatistics, len(shardIDs))
+		for i, shardID := range shardIDs {
+			executorStats[shardID] = *etcdtypes.FromShardStatistics(&store.ShardStatistics{SmoothedLoad: float64(i + 1)})
+		}
+		payload, err := json.Marshal(executorStats)
+		require.NoError(t, err)
+		compressedPayload, err := writer.Write(payload)
+		require.NoError(t, err)
+		statsKey := etcdkeys.BuildExecutorKey(tc.EtcdPrefix, tc.Namespace, executorID, etcdkeys.ExecutorShardStatisticsKey)
+		_, err = tc.Client.Put(ctx, statsKey, string(compressedPayload))
+		require.NoError(t, err)
+		for _, shardID := range shardIDs {
+			require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+		}
+	}
+
+	shardStats, err := executorStore.GetShardStats(ctx, tc.Namespace, []string{"shard-1", "shard-3", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, shardStats, 2)
+	assert.Equal(t, 1.0, shardStats["shard-1"].SmoothedLoad)
+	assert.Equal(t, 1.0, shardStats["shard-3"].SmoothedLoad)
+	assert.NotContains(t, shardStats, "shard-2")
+}
+
+// TestGetShardOwnersReturnsOwnedShardsOnly verifies that the owners of the requested shards are returned in one call.
+func TestGetShardOwnersReturnsOwnedShardsOnly(t *testing.T) {
+	tc := testhelper.SetupStoreTestCluster(t)
+	executorStore := createStore(t, tc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for shardID, executorID := range map[string]string{"shard-1": "exec-1", "shard-2": "exec-2"} {
+		require.NoError(t, executorStore.RecordHeartbeat(ctx, tc.Namespace, executorID, store.HeartbeatState{Status: types.ExecutorStatusACTIVE}))
+		require.NoError(t, executorStore.AssignShard(ctx, tc.Namespace, shardID, executorID))
+	}
+
+	owners, err := executorStore.GetShardOwners(ctx, tc.Namespace, []string{"shard-1", "shard-2", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, owners, 2)
+	assert.Equal(t, "exec-1", owners["shard-1"].ExecutorID)
+	assert.Equal(t, "exec-2", owners["shard-2"].ExecutorID)
+	assert.NotContains(t, owners, "unknown")
+}
+
 // TestDeleteShardStatsDeletesAllStats verifies that shard statistics are correctly deleted.
 func TestDeleteShardStatsDeletesAllStats(t *testing.T) {
 	tc := testhelper.SetupStoreTestCluster(t)
@@ -1077,6 +1712,7 @@ func TestCommitGuardedOps_LeadershipLost_StopsEarly(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "leadership may have changed")
+	assert.ErrorIs(t, err, store.ErrLeadershipLost)
 	assert.Equal(t, 1, commitCount, "should stop after first leadership failure")
 }
 
@@ -1131,3 +1767,232 @@ func createStore(t *testing.T, tc *testhelper.StoreTestCluster) store.Store {
 	require.NoError(t, err)
 	return store
 }
+
+func TestUpdateShardStatistic_MultipleDimensions(t *testing.T) {
+	now := time.Now().UTC()
+	s := &executorStoreImpl{
+		logger: testlogger.New(t),
+		cfg: &config.Config{
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return time.Minute },
+				LoadDimensionWeights: func(string) map[string]interface{} {
+					return map[string]interface{}{"cpu": 2.0, "memory": 0.5}
+				},
+			},
+		},
+	}
+
+	oldStats := map[string]etcdtypes.ShardStatistics{
+		"shard-1": {
+			SmoothedLoad:   30,
+			SmoothedLoads:  map[string]float64{"cpu": 10, "memory": 20},
+			LastUpdateTime: etcdtypes.Time(now.Add(-time.Minute)),
+			LastMoveTime:   etcdtypes.Time(now.Add(-time.Hour)),
+			AssignmentHistory: []etcdtypes.ShardOwnerChange{
+				{ExecutorID: "executor-1", AssignedAt: etcdtypes.Time(now.Add(-time.Hour))},
+			},
+		},
+	}
+
+	stats := s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{"cpu": 20, "memory": 40}, time.Time{}, now, oldStats)
+
+	expectedCPU, err := statistics.CalculateSmoothedLoad(10, 20, now.Add(-time.Minute), now, time.Minute)
+	require.NoError(t, err)
+	expectedMemory, err := statistics.CalculateSmoothedLoad(20, 40, now.Add(-time.Minute), now, time.Minute)
+	require.NoError(t, err)
+
+	assert.InDelta(t, expectedCPU, stats.SmoothedLoads["cpu"], 1e-9)
+	assert.InDelta(t, expectedMemory, stats.SmoothedLoads["memory"], 1e-9)
+	assert.InDelta(t, 2*expectedCPU+0.5*expectedMemory, stats.SmoothedLoad, 1e-9)
+	assert.Equal(t, etcdtypes.Time(now), stats.LastUpdateTime)
+	assert.Equal(t, oldStats["shard-1"].LastMoveTime, stats.LastMoveTime)
+	assert.Equal(t, oldStats["shard-1"].AssignmentHistory, stats.AssignmentHistory)
+}
+
+func TestUpdateShardStatistic_FirstSampleIsNotSmoothed(t *testing.T) {
+	now := time.Now().UTC()
+	s := &executorStoreImpl{
+		logger: testlogger.New(t),
+		cfg: &config.Config{
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return time.Minute },
+			},
+		},
+	}
+	// Statistics as created when the shard was assigned, before its first report.
+	pinExpiresAt := etcdtypes.Time(now.Add(time.Hour))
+	assigned := map[string]etcdtypes.ShardStatistics{
+		"shard-1": {
+			LastUpdateTime: etcdtypes.Time(now.Add(-10 * time.Second)),
+			LastMoveTime:   etcdtypes.Time(now.Add(-10 * time.Second)),
+			PinnedExecutor: "executor-1",
+			PinExpiresAt:   &pinExpiresAt,
+		},
+	}
+
+	stats := s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: 7}, time.Time{}, now, assigned)
+	assert.Equal(t, 7.0, stats.SmoothedLoad)
+	assert.Equal(t, 7.0, stats.LoadHighWatermark)
+	assert.Equal(t, assigned["shard-1"].LastMoveTime, stats.LastMoveTime)
+	assert.Equal(t, "executor-1", stats.PinnedExecutor, "load reports keep the pin")
+	assert.Equal(t, assigned["shard-1"].PinExpiresAt, stats.PinExpiresAt)
+
+	// Later reports are smoothed again.
+	stats = s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: 1}, time.Time{}, now.Add(time.Second), map[string]etcdtypes.ShardStatistics{"shard-1": stats})
+	assert.Greater(t, stats.SmoothedLoad, 6.0)
+}
+
+func TestUpdateShardStatistic_KeepsWeightOverride(t *testing.T) {
+	now := time.Now().UTC()
+	s := &executorStoreImpl{
+		logger: testlogger.New(t),
+		cfg: &config.Config{
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return time.Minute },
+			},
+		},
+	}
+	oldStats := map[string]etcdtypes.ShardStatistics{
+		"shard-1": {SmoothedLoad: 1, LastUpdateTime: etcdtypes.Time(now.Add(-time.Minute)), WeightOverride: 50},
+	}
+
+	stats := s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: 2}, time.Time{}, now, oldStats)
+	assert.Equal(t, 50.0, stats.WeightOverride)
+
+	// A load that cannot be smoothed keeps the statistics set by an operator as well.
+	stats = s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: math.NaN()}, time.Time{}, now, oldStats)
+	assert.Equal(t, 50.0, stats.WeightOverride)
+}
+
+func TestUpdateShardStatistic_LoadHighWatermark(t *testing.T) {
+	start := time.Now().UTC()
+	s := &executorStoreImpl{
+		logger: testlogger.New(t),
+		cfg: &config.Config{
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return time.Minute },
+				LoadHighWatermarkDecay:    func(string) time.Duration { return time.Minute },
+			},
+		},
+	}
+
+	report := func(now time.Time, load float64, oldStats map[string]etcdtypes.ShardStatistics) map[string]etcdtypes.ShardStatistics {
+		stats := s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: load}, time.Time{}, now, oldStats)
+		return map[string]etcdtypes.ShardStatistics{"shard-1": stats}
+	}
+
+	stats := report(start, 1, nil)
+	assert.Equal(t, 1.0, stats["shard-1"].LoadHighWatermark)
+
+	// A single spike raises the watermark to the spike while the smoothed load barely moves.
+	now := start.Add(time.Second)
+	stats = report(now, 50, stats)
+	assert.Equal(t, 50.0, stats["shard-1"].LoadHighWatermark)
+	assert.Less(t, stats["shard-1"].SmoothedLoad, 5.0)
+
+	// Subsequent low reports decay the watermark towards the current load.
+	previous := stats["shard-1"].LoadHighWatermark
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Minute)
+		stats = report(now, 1, stats)
+		assert.Less(t, stats["shard-1"].LoadHighWatermark, previous)
+		previous = stats["shard-1"].LoadHighWatermark
+	}
+	assert.InDelta(t, 50*math.Exp(-3), stats["shard-1"].LoadHighWatermark, 1e-9)
+
+	// Once the spike has decayed away the watermark follows the current load.
+	stats = report(now.Add(10*time.Minute), 1, stats)
+	assert.Equal(t, 1.0, stats["shard-1"].LoadHighWatermark)
+}
+
+func TestUpdateShardStatistic_WindowedLoad(t *testing.T) {
+	start := time.Now().UTC()
+	window := 2 * time.Minute
+	s := &executorStoreImpl{
+		logger: testlogger.New(t),
+		cfg: &config.Config{
+			LoadBalancingGreedy: config.LoadBalancingGreedyConfig{
+				LoadSmoothingTimeConstant: func(string) time.Duration { return time.Minute },
+				LoadWindow:                func(string) time.Duration { return window },
+			},
+		},
+	}
+
+	report := func(now time.Time, load float64, oldStats map[string]etcdtypes.ShardStatistics) map[string]etcdtypes.ShardStatistics {
+		stats := s.updateShardStatistic("test-ns", "executor-1", "shard-1", map[string]float64{statistics.DefaultLoadDimension: load}, time.Time{}, now, oldStats)
+		return map[string]etcdtypes.ShardStatistics{"shard-1": stats}
+	}
+
+	// Sustained load of 10 reported every 10 seconds.
+	var stats map[string]etcdtypes.ShardStatistics
+	now := start
+	for i := 0; i < 12; i++ {
+		stats = report(now, 10, stats)
+		now = now.Add(10 * time.Second)
+	}
+	assert.Equal(t, 10.0, stats["shard-1"].WindowedLoad)
+
+	// An isolated spike barely raises the windowed load, much less than the smoothed load.
+	stats = report(now, 100, stats)
+	windowed, smoothed := stats["shard-1"].WindowedLoad, stats["shard-1"].SmoothedLoad
+	assert.InDelta(t, (12*10.0+100)/13, windowed, 1e-9)
+	assert.Less(t, windowed-10, smoothed-10)
+	assert.LessOrEqual(t, len(stats["shard-1"].RecentLoads), store.LoadSamplesSize)
+
+	// Once the spike left the window the windowed load is back to the sustained load.
+	for i := 0; i < 13; i++ {
+		now = now.Add(10 * time.Second)
+		stats = report(now, 10, stats)
+	}
+	assert.Equal(t, 10.0, stats["shard-1"].WindowedLoad)
+
+	// Without a window no samples are kept.
+	s.cfg.LoadBalancingGreedy.LoadWindow = func(string) time.Duration { return 0 }
+	stats = report(now.Add(10*time.Second), 10, stats)
+	assert.Empty(t, stats["shard-1"].RecentLoads)
+	assert.Zero(t, stats["shard-1"].WindowedLoad)
+}
+
+func TestMarshalShardStatistics(t *testing.T) {
+	now := time.Now().UTC()
+
+	t.Run("all shards are marshaled together", func(t *testing.T) {
+		stats := map[string]etcdtypes.ShardStatistics{
+			"shard-1": {SmoothedLoad: 1, LastUpdateTime: etcdtypes.Time(now)},
+			"shard-2": {SmoothedLoad: 2, LastUpdateTime: etcdtypes.Time(now)},
+		}
+
+		payload, failedShards, err := marshalShardStatistics(stats)
+		require.NoError(t, err)
+		assert.Empty(t, failedShards)
+
+		var written map[string]etcdtypes.ShardStatistics
+		require.NoError(t, json.Unmarshal(payload, &written))
+		assert.Len(t, written, 2)
+	})
+
+	t.Run("a shard that fails is left out", func(t *testing.T) {
+		stats := map[string]etcdtypes.ShardStatistics{
+			"shard-1": {SmoothedLoad: 1, LastUpdateTime: etcdtypes.Time(now)},
+			"shard-2": {SmoothedLoad: math.NaN(), LastUpdateTime: etcdtypes.Time(now)},
+			"shard-3": {SmoothedLoad: 3, LastUpdateTime: etcdtypes.Time(now)},
+		}
+
+		payload, failedShards, err := marshalShardStatistics(stats)
+		require.NoError(t, err)
+		require.Len(t, failedShards, 1)
+		assert.Contains(t, failedShards, "shard-2")
+
+		var written map[string]etcdtypes.ShardStatistics
+		require.NoError(t, json.Unmarshal(payload, &written))
+		assert.Equal(t, 1.0, written["shard-1"].SmoothedLoad)
+		assert.Equal(t, 3.0, written["shard-3"].SmoothedLoad)
+		assert.NotContains(t, written, "shard-2")
+	})
+}
+
+func TestClassifyWriteError(t *testing.T) {
+	assert.ErrorIs(t, classifyWriteError(rpctypes.ErrNoSpace), store.ErrReadOnly)
+	assert.NotErrorIs(t, classifyWriteError(errors.New("connection reset")), store.ErrReadOnly)
+	assert.NoError(t, classifyWriteError(nil))
+}
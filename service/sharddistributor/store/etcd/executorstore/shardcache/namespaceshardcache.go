@@ -95,6 +95,37 @@ func (n *namespaceShardToExecutor) GetShardOwner(ctx context.Context, shardID st
 	return nil, store.ErrShardNotFound
 }
 
+// GetShardOwners returns the owners of the given shards, leaving out the shards without an owner.
+// Unlike GetShardOwner it refreshes the cache at most once, however many shards are missing.
+func (n *namespaceShardToExecutor) GetShardOwners(ctx context.Context, shardIDs []string) (map[string]*store.ShardOwner, error) {
+	owners, complete := n.lookupShardOwners(shardIDs)
+	if complete {
+		return owners, nil
+	}
+
+	// Force refresh the cache
+	if err := n.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("refresh for namespace %s: %w", n.namespace, err)
+	}
+
+	owners, _ = n.lookupShardOwners(shardIDs)
+	return owners, nil
+}
+
+// lookupShardOwners reads the owners of the given shards from the cache, and reports whether all were found.
+func (n *namespaceShardToExecutor) lookupShardOwners(shardIDs []string) (map[string]*store.ShardOwner, bool) {
+	n.RLock()
+	defer n.RUnlock()
+
+	owners := make(map[string]*store.ShardOwner, len(shardIDs))
+	for _, shardID := range shardIDs {
+		if owner, ok := n.shardToExecutor[shardID]; ok {
+			owners[shardID] = owner
+		}
+	}
+	return owners, len(owners) == len(shardIDs)
+}
+
 func (n *namespaceShardToExecutor) GetExecutor(ctx context.Context, executorID string) (*store.ShardOwner, error) {
 	shardOwner, err := n.getShardOwnerInMap(ctx, &n.shardOwners, executorID)
 	if err != nil {
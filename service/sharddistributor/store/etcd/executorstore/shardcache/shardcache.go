@@ -64,6 +64,14 @@ func (s *ShardToExecutorCache) GetShardOwner(ctx context.Context, namespace, sha
 	return namespaceShardToExecutor.GetShardOwner(ctx, shardID)
 }
 
+func (s *ShardToExecutorCache) GetShardOwners(ctx context.Context, namespace string, shardIDs []string) (map[string]*store.ShardOwner, error) {
+	namespaceShardToExecutor, err := s.getNamespaceShardToExecutor(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("get namespace shard to executor: %w", err)
+	}
+	return namespaceShardToExecutor.GetShardOwners(ctx, shardIDs)
+}
+
 func (s *ShardToExecutorCache) GetExecutorStatistics(ctx context.Context, namespace, executorID string) (map[string]etcdtypes.ShardStatistics, error) {
 	namespaceShardToExecutor, err := s.getNamespaceShardToExecutor(namespace)
 	if err != nil {
@@ -0,0 +1,157 @@
+package executorstore
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	farm "github.com/dgryski/go-farm"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/statistics"
+	"github.com/uber/cadence/service/sharddistributor/store/etcd/etcdtypes"
+)
+
+// statisticsCoalescer keeps the shard statistics calculated on heartbeat in memory and lets them
+// be written to the store at most once per flush interval for each executor.
+type statisticsCoalescer struct {
+	mu      sync.Mutex
+	pending map[string]*pendingStatistics
+	// samples holds the loads reported for each shard of an executor since its last flush,
+	// for load aggregation modes other than last.
+	samples map[string]map[string]*statistics.LoadSamples
+}
+
+type pendingStatistics struct {
+	// stats holds the last calculated statistics of the executor, it is nil once flushed.
+	stats     map[string]etcdtypes.ShardStatistics
+	status    types.ExecutorStatus
+	lastFlush time.Time
+}
+
+func newStatisticsCoalescer() *statisticsCoalescer {
+	return &statisticsCoalescer{
+		pending: make(map[string]*pendingStatistics),
+		samples: make(map[string]map[string]*statistics.LoadSamples),
+	}
+}
+
+func coalescerKey(namespace, executorID string) string {
+	return namespace + "/" + executorID
+}
+
+// overlay returns stored with the load of the not yet flushed statistics of the executor applied,
+// so smoothing continues from the last calculated values instead of the last written ones.
+// Load is only taken from pending statistics newer than the stored ones, and move related fields
+// are always taken from stored.
+func (c *statisticsCoalescer) overlay(key string, stored map[string]etcdtypes.ShardStatistics) map[string]etcdtypes.ShardStatistics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.pending[key]
+	if !ok || len(entry.stats) == 0 {
+		return stored
+	}
+
+	merged := make(map[string]etcdtypes.ShardStatistics, len(stored)+len(entry.stats))
+	for shardID, stats := range stored {
+		merged[shardID] = stats
+	}
+	for shardID, pending := range entry.stats {
+		stats := merged[shardID]
+		if !pending.LastUpdateTime.ToTime().After(stats.LastUpdateTime.ToTime()) {
+			continue
+		}
+		stats.SmoothedLoad = pending.SmoothedLoad
+		stats.SmoothedLoads = pending.SmoothedLoads
+		stats.LoadHighWatermark = pending.LoadHighWatermark
+		stats.RecentLoads = pending.RecentLoads
+		stats.WindowedLoad = pending.WindowedLoad
+		stats.LoadHistory = pending.LoadHistory
+		stats.LastUpdateTime = pending.LastUpdateTime
+		merged[shardID] = stats
+	}
+	return merged
+}
+
+// aggregate records loads as reported for the shard since the last flush of the executor and returns
+// the aggregate of every load recorded so far according to mode.
+func (c *statisticsCoalescer) aggregate(key, shardID string, loads map[string]float64, mode string) map[string]float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	executorSamples, ok := c.samples[key]
+	if !ok {
+		executorSamples = make(map[string]*statistics.LoadSamples)
+		c.samples[key] = executorSamples
+	}
+	shardSamples, ok := executorSamples[shardID]
+	if !ok {
+		shardSamples = &statistics.LoadSamples{}
+		executorSamples[shardID] = shardSamples
+	}
+	shardSamples.Add(loads)
+
+	switch mode {
+	case config.LoadAggregationMean:
+		return shardSamples.Mean()
+	case config.LoadAggregationMax:
+		return shardSamples.Max()
+	default:
+		return shardSamples.Last()
+	}
+}
+
+// jitteredFlushInterval returns the flush interval of the executor, interval stretched or shrunk by up to
+// +/- jitterCoefficient * interval. The jitter is derived from the executor ID, so an executor keeps its
+// interval while executors started together spread their writes. The coefficient is clamped to [0, 1].
+func jitteredFlushInterval(executorID string, interval time.Duration, jitterCoefficient float64) time.Duration {
+	jitterCoefficient = math.Min(math.Max(jitterCoefficient, 0), 1)
+	if interval <= 0 || jitterCoefficient == 0 {
+		return interval
+	}
+	// A fraction in [-1, 1] of the maximum jitter.
+	fraction := float64(farm.Fingerprint32([]byte(executorID)))/math.MaxUint32*2 - 1
+	return interval + time.Duration(fraction*jitterCoefficient*float64(interval))
+}
+
+// stage records stats as the latest statistics of the executor and reports whether they should be
+// written now. They are written on the first heartbeat of the executor, when its status changed,
+// and once interval passed since the last write.
+func (c *statisticsCoalescer) stage(key string, status types.ExecutorStatus, stats map[string]etcdtypes.ShardStatistics, now time.Time, interval time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.pending[key]
+	if !ok {
+		c.pending[key] = &pendingStatistics{stats: stats, status: status}
+		return true
+	}
+
+	statusChanged := entry.status != status
+	entry.stats = stats
+	entry.status = status
+	return statusChanged || now.Sub(entry.lastFlush) >= interval
+}
+
+// flushed records that the statistics of the executor were written at now.
+func (c *statisticsCoalescer) flushed(key string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.pending[key]; ok {
+		entry.stats = nil
+		entry.lastFlush = now
+	}
+	delete(c.samples, key)
+}
+
+// forget drops the statistics kept for the executor.
+func (c *statisticsCoalescer) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, key)
+	delete(c.samples, key)
+}
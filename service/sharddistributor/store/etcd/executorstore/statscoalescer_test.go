@@ -0,0 +1,162 @@
+package executorstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/sharddistributor/config"
+	"github.com/uber/cadence/service/sharddistributor/store/etcd/etcdtypes"
+)
+
+func TestStatisticsCoalescer_FlushesAfterInterval(t *testing.T) {
+	const interval = 10 * time.Second
+	now := time.Now().UTC()
+	key := coalescerKey("test-ns", "exec-1")
+	stats := map[string]etcdtypes.ShardStatistics{"shard-1": {SmoothedLoad: 1, LastUpdateTime: etcdtypes.Time(now)}}
+
+	c := newStatisticsCoalescer()
+
+	// The first statistics of an executor are written immediately.
+	require.True(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now, interval))
+	c.flushed(key, now)
+
+	assert.False(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now.Add(time.Second), interval))
+	assert.False(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now.Add(interval-time.Second), interval))
+	assert.True(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now.Add(interval), interval))
+
+	// Until the write succeeded the interval keeps counting from the last flush.
+	assert.True(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now.Add(interval+time.Second), interval))
+	c.flushed(key, now.Add(interval+time.Second))
+	assert.False(t, c.stage(key, types.ExecutorStatusACTIVE, stats, now.Add(interval+2*time.Second), interval))
+}
+
+func TestJitteredFlushInterval(t *testing.T) {
+	const interval = 10 * time.Second
+	const jitterCoefficient = 0.1
+
+	exec1 := jitteredFlushInterval("exec-1", interval, jitterCoefficient)
+	exec2 := jitteredFlushInterval("exec-2", interval, jitterCoefficient)
+	assert.NotEqual(t, exec1, exec2, "executors with different IDs have different flush windows")
+	for _, jittered := range []time.Duration{exec1, exec2} {
+		assert.GreaterOrEqual(t, jittered, interval-time.Second)
+		assert.LessOrEqual(t, jittered, interval+time.Second)
+	}
+	assert.Equal(t, exec1, jitteredFlushInterval("exec-1", interval, jitterCoefficient), "the jitter is deterministic")
+
+	assert.Equal(t, interval, jitteredFlushInterval("exec-1", interval, 0), "no jitter")
+	assert.Equal(t, interval, jitteredFlushInterval("exec-1", interval, -1), "negative coefficient is clamped")
+	assert.Equal(t, time.Duration(0), jitteredFlushInterval("exec-1", 0, jitterCoefficient), "coalescing disabled")
+
+	// The jittered window gates the flush of each executor.
+	now := time.Now().UTC()
+	c := newStatisticsCoalescer()
+	shorter, longer := "exec-1", "exec-2"
+	if exec1 > exec2 {
+		shorter, longer = longer, shorter
+	}
+	for _, executorID := range []string{shorter, longer} {
+		key := coalescerKey("test-ns", executorID)
+		require.True(t, c.stage(key, types.ExecutorStatusACTIVE, nil, now, jitteredFlushInterval(executorID, interval, jitterCoefficient)))
+		c.flushed(key, now)
+	}
+	between := now.Add(min(exec1, exec2))
+	assert.True(t, c.stage(coalescerKey("test-ns", shorter), types.ExecutorStatusACTIVE, nil, between, jitteredFlushInterval(shorter, interval, jitterCoefficient)))
+	assert.False(t, c.stage(coalescerKey("test-ns", longer), types.ExecutorStatusACTIVE, nil, between, jitteredFlushInterval(longer, interval, jitterCoefficient)))
+}
+
+func TestStatisticsCoalescer_FlushesOnStatusChange(t *testing.T) {
+	const interval = time.Minute
+	now := time.Now().UTC()
+	key := coalescerKey("test-ns", "exec-1")
+
+	c := newStatisticsCoalescer()
+	require.True(t, c.stage(key, types.ExecutorStatusACTIVE, nil, now, interval))
+	c.flushed(key, now)
+
+	assert.True(t, c.stage(key, types.ExecutorStatusDRAINING, nil, now.Add(time.Second), interval))
+	c.flushed(key, now.Add(time.Second))
+	assert.False(t, c.stage(key, types.ExecutorStatusDRAINING, nil, now.Add(2*time.Second), interval))
+
+	// A forgotten executor is written immediately again.
+	c.forget(key)
+	assert.True(t, c.stage(key, types.ExecutorStatusDRAINING, nil, now.Add(3*time.Second), interval))
+}
+
+func TestStatisticsCoalescer_Overlay(t *testing.T) {
+	now := time.Now().UTC()
+	moveTime := now.Add(-time.Hour)
+	key := coalescerKey("test-ns", "exec-1")
+
+	stored := map[string]etcdtypes.ShardStatistics{
+		"shard-1": {SmoothedLoad: 1, LastUpdateTime: etcdtypes.Time(now.Add(-time.Minute)), LastMoveTime: etcdtypes.Time(moveTime)},
+		"shard-2": {SmoothedLoad: 2, LastUpdateTime: etcdtypes.Time(now)},
+	}
+
+	c := newStatisticsCoalescer()
+	assert.Equal(t, stored, c.overlay(key, stored), "nothing pending")
+
+	c.stage(key, types.ExecutorStatusACTIVE, map[string]etcdtypes.ShardStatistics{
+		// newer than stored, its load is used
+		"shard-1": {SmoothedLoad: 10, SmoothedLoads: map[string]float64{"cpu": 10}, LoadHighWatermark: 12, LastUpdateTime: etcdtypes.Time(now)},
+		// older than stored, e.g. written by another instance in between
+		"shard-2": {SmoothedLoad: 20, LastUpdateTime: etcdtypes.Time(now.Add(-time.Minute))},
+		// not stored yet
+		"shard-3": {SmoothedLoad: 30, LastUpdateTime: etcdtypes.Time(now)},
+	}, now, time.Minute)
+
+	merged := c.overlay(key, stored)
+	assert.Equal(t, etcdtypes.ShardStatistics{
+		SmoothedLoad:      10,
+		SmoothedLoads:     map[string]float64{"cpu": 10},
+		LoadHighWatermark: 12,
+		LastUpdateTime:    etcdtypes.Time(now),
+		LastMoveTime:      etcdtypes.Time(moveTime),
+	}, merged["shard-1"])
+	assert.Equal(t, stored["shard-2"], merged["shard-2"])
+	assert.Equal(t, 30.0, merged["shard-3"].SmoothedLoad)
+	assert.Equal(t, 1.0, stored["shard-1"].SmoothedLoad, "stored statistics are not modified")
+
+	c.flushed(key, now)
+	assert.Equal(t, stored, c.overlay(key, stored), "nothing pending after flush")
+}
+
+func TestStatisticsCoalescer_Aggregate(t *testing.T) {
+	key := coalescerKey("test-ns", "exec-1")
+	reports := []map[string]float64{{"default": 2}, {"default": 10}, {"default": 3}}
+
+	tests := []struct {
+		mode     string
+		expected map[string]float64
+	}{
+		{mode: config.LoadAggregationLast, expected: map[string]float64{"default": 3}},
+		{mode: config.LoadAggregationMean, expected: map[string]float64{"default": 5}},
+		{mode: config.LoadAggregationMax, expected: map[string]float64{"default": 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			c := newStatisticsCoalescer()
+			var aggregated map[string]float64
+			for _, loads := range reports {
+				aggregated = c.aggregate(key, "shard-1", loads, tt.mode)
+			}
+			assert.Equal(t, tt.expected, aggregated)
+
+			// Shards are aggregated independently.
+			assert.Equal(t, map[string]float64{"default": 1}, c.aggregate(key, "shard-2", map[string]float64{"default": 1}, tt.mode))
+		})
+	}
+
+	t.Run("a flush starts a new window", func(t *testing.T) {
+		c := newStatisticsCoalescer()
+		c.aggregate(key, "shard-1", map[string]float64{"default": 10}, config.LoadAggregationMax)
+		c.flushed(key, time.Now())
+		assert.Equal(t, map[string]float64{"default": 1}, c.aggregate(key, "shard-1", map[string]float64{"default": 1}, config.LoadAggregationMax))
+
+		c.forget(key)
+		assert.Equal(t, map[string]float64{"default": 2}, c.aggregate(key, "shard-1", map[string]float64{"default": 2}, config.LoadAggregationMean))
+	})
+}
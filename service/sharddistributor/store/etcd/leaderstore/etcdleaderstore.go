@@ -2,9 +2,12 @@ package leaderstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.uber.org/fx"
@@ -63,6 +66,99 @@ func (ls *LeaderStore) CreateElection(ctx context.Context, namespace string) (el
 	return &election{election: etcdElection, session: session, prefix: namespacePrefix}, nil
 }
 
+// AcquireLeadership creates the leadership key of the namespace under a new etcd lease, unless the key exists.
+// The revision the key was created at is the fencing token of the lease.
+func (ls *LeaderStore) AcquireLeadership(ctx context.Context, namespace, candidateID string, ttl time.Duration) (*store.LeadershipLease, error) {
+	grant, err := ls.client.Grant(ctx, max(int64(math.Ceil(ttl.Seconds())), 1))
+	if err != nil {
+		return nil, fmt.Errorf("grant lease: %w", err)
+	}
+
+	key := ls.leadershipKey(namespace)
+	resp, err := ls.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, candidateID, clientv3.WithLease(grant.ID))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		ls.revokeLease(ctx, grant.ID)
+		return nil, fmt.Errorf("claim leadership: %w", err)
+	}
+	if !resp.Succeeded {
+		ls.revokeLease(ctx, grant.ID)
+		holder := ""
+		if kvs := resp.Responses[0].GetResponseRange().GetKvs(); len(kvs) > 0 {
+			holder = string(kvs[0].Value)
+		}
+		return nil, fmt.Errorf("%w: namespace %s is led by %s", store.ErrLeadershipHeld, namespace, holder)
+	}
+
+	return &store.LeadershipLease{
+		Namespace:   namespace,
+		CandidateID: candidateID,
+		Token:       resp.Header.Revision,
+		LeaseID:     int64(grant.ID),
+	}, nil
+}
+
+// RenewLeadership keeps the etcd lease alive and checks the leadership key was not replaced in the meantime.
+func (ls *LeaderStore) RenewLeadership(ctx context.Context, lease *store.LeadershipLease) error {
+	if _, err := ls.client.KeepAliveOnce(ctx, clientv3.LeaseID(lease.LeaseID)); err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return store.ErrLeadershipLost
+		}
+		return fmt.Errorf("keep lease alive: %w", err)
+	}
+
+	resp, err := ls.client.Txn(ctx).If(ls.holdsLeadership(lease)).Commit()
+	if err != nil {
+		return fmt.Errorf("check leadership: %w", err)
+	}
+	if !resp.Succeeded {
+		return store.ErrLeadershipLost
+	}
+	return nil
+}
+
+// ReleaseLeadership deletes the leadership key if the lease still holds it and revokes the etcd lease.
+func (ls *LeaderStore) ReleaseLeadership(ctx context.Context, lease *store.LeadershipLease) error {
+	key := ls.leadershipKey(lease.Namespace)
+	if _, err := ls.client.Txn(ctx).If(ls.holdsLeadership(lease)).Then(clientv3.OpDelete(key)).Commit(); err != nil {
+		return fmt.Errorf("release leadership: %w", err)
+	}
+	if _, err := ls.client.Revoke(ctx, clientv3.LeaseID(lease.LeaseID)); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return fmt.Errorf("revoke lease: %w", err)
+	}
+	return nil
+}
+
+// LeadershipGuard returns a guard that only lets the transaction through while the lease holds leadership.
+func (ls *LeaderStore) LeadershipGuard(lease *store.LeadershipLease) store.GuardFunc {
+	return func(txn store.Txn) (store.Txn, error) {
+		etcdTxn, ok := txn.(clientv3.Txn)
+		if !ok {
+			return nil, fmt.Errorf("invalid transaction type for etcd guard: expected clientv3.Txn, got %T", txn)
+		}
+		return etcdTxn.If(ls.holdsLeadership(lease)), nil
+	}
+}
+
+func (ls *LeaderStore) leadershipKey(namespace string) string {
+	return fmt.Sprintf("%s/%s/leadership", ls.config.Prefix, namespace)
+}
+
+// holdsLeadership compares the leadership key to the lease. The key is only written when it is created,
+// so its modification revision is the token of the lease that created it.
+func (ls *LeaderStore) holdsLeadership(lease *store.LeadershipLease) clientv3.Cmp {
+	return clientv3.Compare(clientv3.ModRevision(ls.leadershipKey(lease.Namespace)), "=", lease.Token)
+}
+
+// revokeLease revokes a lease that was granted for a failed acquisition. It is best effort, the lease
+// expires on its own otherwise.
+func (ls *LeaderStore) revokeLease(ctx context.Context, leaseID clientv3.LeaseID) {
+	_, _ = ls.client.Revoke(ctx, leaseID)
+}
+
 // election is a wrapper around etcd.concurrency.Election to abstract implementation from etcd types.
 type election struct {
 	session  *concurrency.Session
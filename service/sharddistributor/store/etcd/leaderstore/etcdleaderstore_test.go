@@ -194,6 +194,77 @@ func TestSessionDone(t *testing.T) {
 	}
 }
 
+// TestAcquireLeadership_Contention tests that only one candidate holds leadership of a namespace at a time
+func TestAcquireLeadership_Contention(t *testing.T) {
+	tc := setupETCDCluster(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	namespace := "test-namespace-lease-contention"
+	lease, err := tc.store.AcquireLeadership(ctx, namespace, "host-1", 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "host-1", lease.CandidateID)
+
+	_, err = tc.store.AcquireLeadership(ctx, namespace, "host-2", 5*time.Second)
+	require.ErrorIs(t, err, store.ErrLeadershipHeld)
+
+	require.NoError(t, tc.store.RenewLeadership(ctx, lease))
+
+	// Once released, the second candidate acquires leadership with a higher token.
+	require.NoError(t, tc.store.ReleaseLeadership(ctx, lease))
+	lease2, err := tc.store.AcquireLeadership(ctx, namespace, "host-2", 5*time.Second)
+	require.NoError(t, err)
+	require.Greater(t, lease2.Token, lease.Token)
+	require.NoError(t, tc.store.ReleaseLeadership(ctx, lease2))
+}
+
+// TestAcquireLeadership_Expiry tests that leadership is lost when the lease is not renewed
+func TestAcquireLeadership_Expiry(t *testing.T) {
+	tc := setupETCDCluster(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   tc.endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	namespace := "test-namespace-lease-expiry"
+	lease, err := tc.store.AcquireLeadership(ctx, namespace, "host-1", time.Second)
+	require.NoError(t, err)
+
+	var lease2 *store.LeadershipLease
+	require.Eventually(t, func() bool {
+		lease2, err = tc.store.AcquireLeadership(ctx, namespace, "host-2", 5*time.Second)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond, "second candidate should acquire leadership after the lease expired")
+
+	require.ErrorIs(t, tc.store.RenewLeadership(ctx, lease), store.ErrLeadershipLost)
+
+	// Writes guarded by the expired lease are fenced off, writes of the new leader go through.
+	key := fmt.Sprintf("%s/%s/fenced", tc.storeConfig.Prefix, namespace)
+	txn, err := tc.store.LeadershipGuard(lease)(client.Txn(ctx))
+	require.NoError(t, err)
+	resp, err := txn.(clientv3.Txn).Then(clientv3.OpPut(key, "stale")).Commit()
+	require.NoError(t, err)
+	require.False(t, resp.Succeeded)
+
+	txn, err = tc.store.LeadershipGuard(lease2)(client.Txn(ctx))
+	require.NoError(t, err)
+	resp, err = txn.(clientv3.Txn).Then(clientv3.OpPut(key, "current")).Commit()
+	require.NoError(t, err)
+	require.True(t, resp.Succeeded)
+
+	// Releasing the expired lease does not affect the new leader.
+	require.NoError(t, tc.store.ReleaseLeadership(ctx, lease))
+	require.NoError(t, tc.store.RenewLeadership(ctx, lease2))
+	require.NoError(t, tc.store.ReleaseLeadership(ctx, lease2))
+}
+
 // testCluster represents a test etcd cluster with its resources
 type testCluster struct {
 	store       store.Elector
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 )
 
 //go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination=leaderstore_mock.go Elector,Election
@@ -10,6 +11,29 @@ import (
 // It establishes connection and a session and provides Election to run for leader.
 type Elector interface {
 	CreateElection(ctx context.Context, namespace string) (Election, error)
+
+	// AcquireLeadership claims leadership of the namespace for candidateID for the duration of ttl.
+	// It returns ErrLeadershipHeld if leadership is held under another lease, even by the same candidate.
+	AcquireLeadership(ctx context.Context, namespace, candidateID string, ttl time.Duration) (*LeadershipLease, error)
+	// RenewLeadership extends the lease by its ttl. It returns ErrLeadershipLost once the lease expired or
+	// was released, leadership has to be acquired again then.
+	RenewLeadership(ctx context.Context, lease *LeadershipLease) error
+	// ReleaseLeadership gives up leadership so another candidate can acquire it right away.
+	// Releasing a lease that already expired is a no-op.
+	ReleaseLeadership(ctx context.Context, lease *LeadershipLease) error
+	// LeadershipGuard returns a transaction guard that fails unless the lease still holds leadership,
+	// so writes of a leader whose lease expired are fenced off.
+	LeadershipGuard(lease *LeadershipLease) GuardFunc
+}
+
+// LeadershipLease is the proof of leadership of a namespace returned by AcquireLeadership.
+type LeadershipLease struct {
+	Namespace   string
+	CandidateID string
+	// Token identifies the leadership term. Every acquisition gets a higher token than the previous ones.
+	Token int64
+	// LeaseID identifies the storage lease that keeps the leadership alive.
+	LeaseID int64
 }
 
 // Election is an interface that establishes leader campaign.
@@ -12,6 +12,7 @@ package store
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +41,21 @@ func (m *MockElector) EXPECT() *MockElectorMockRecorder {
 	return m.recorder
 }
 
+// AcquireLeadership mocks base method.
+func (m *MockElector) AcquireLeadership(ctx context.Context, namespace, candidateID string, ttl time.Duration) (*LeadershipLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLeadership", ctx, namespace, candidateID, ttl)
+	ret0, _ := ret[0].(*LeadershipLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLeadership indicates an expected call of AcquireLeadership.
+func (mr *MockElectorMockRecorder) AcquireLeadership(ctx, namespace, candidateID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLeadership", reflect.TypeOf((*MockElector)(nil).AcquireLeadership), ctx, namespace, candidateID, ttl)
+}
+
 // CreateElection mocks base method.
 func (m *MockElector) CreateElection(ctx context.Context, namespace string) (Election, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +71,48 @@ func (mr *MockElectorMockRecorder) CreateElection(ctx, namespace any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateElection", reflect.TypeOf((*MockElector)(nil).CreateElection), ctx, namespace)
 }
 
+// LeadershipGuard mocks base method.
+func (m *MockElector) LeadershipGuard(lease *LeadershipLease) GuardFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeadershipGuard", lease)
+	ret0, _ := ret[0].(GuardFunc)
+	return ret0
+}
+
+// LeadershipGuard indicates an expected call of LeadershipGuard.
+func (mr *MockElectorMockRecorder) LeadershipGuard(lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeadershipGuard", reflect.TypeOf((*MockElector)(nil).LeadershipGuard), lease)
+}
+
+// ReleaseLeadership mocks base method.
+func (m *MockElector) ReleaseLeadership(ctx context.Context, lease *LeadershipLease) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLeadership", ctx, lease)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLeadership indicates an expected call of ReleaseLeadership.
+func (mr *MockElectorMockRecorder) ReleaseLeadership(ctx, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLeadership", reflect.TypeOf((*MockElector)(nil).ReleaseLeadership), ctx, lease)
+}
+
+// RenewLeadership mocks base method.
+func (m *MockElector) RenewLeadership(ctx context.Context, lease *LeadershipLease) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLeadership", ctx, lease)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewLeadership indicates an expected call of RenewLeadership.
+func (mr *MockElectorMockRecorder) RenewLeadership(ctx, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLeadership", reflect.TypeOf((*MockElector)(nil).RenewLeadership), ctx, lease)
+}
+
 // MockElection is a mock of Election interface.
 type MockElection struct {
 	ctrl     *gomock.Controller
@@ -1,11 +1,46 @@
 package store
 
 import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/uber/cadence/common/types"
 )
 
+// ExecutorMetadataZoneKey is the executor metadata key holding the failure zone the executor runs in.
+const ExecutorMetadataZoneKey = "zone"
+
+// ExecutorMetadataHeadroomKey is the executor metadata key holding the last headroom the executor reported.
+const ExecutorMetadataHeadroomKey = "headroom"
+
+// ExecutorMetadataLoadKey is the executor metadata key holding the last total load the executor reported.
+const ExecutorMetadataLoadKey = "executor-load"
+
+// ExecutorMetadataRoleKey is the executor metadata key holding the role the executor heartbeats with.
+const ExecutorMetadataRoleKey = "role"
+
+// ExecutorMetadataLabelPrefix prefixes the executor metadata keys holding the labels the executor reported.
+const ExecutorMetadataLabelPrefix = "label."
+
+// ExecutorMetadataSequenceKey is the executor metadata key holding the sequence number of the last processed heartbeat.
+const ExecutorMetadataSequenceKey = "heartbeat-sequence"
+
+// ExecutorMetadataIncarnationKey is the executor metadata key holding the incarnation ID of the last processed heartbeat.
+const ExecutorMetadataIncarnationKey = "incarnation"
+
+// ExecutorMetadataRegisteredAtKey is the executor metadata key holding the time of the first heartbeat of the executor.
+const ExecutorMetadataRegisteredAtKey = "registered-at"
+
+// AssignmentHistorySize is the number of owners kept in a shard's AssignmentHistory.
+const AssignmentHistorySize = 10
+
+// LoadSamplesSize is the maximum number of reports kept in a shard's RecentLoads.
+const LoadSamplesSize = 16
+
 type HeartbeatState struct {
 	// LastHeartbeat is the time of the last heartbeat received from the executor
 	LastHeartbeat  time.Time
@@ -14,6 +49,102 @@ type HeartbeatState struct {
 	Metadata       map[string]string
 }
 
+// Zone returns the failure zone the executor reported in its metadata, or "" if it reported none.
+func (h HeartbeatState) Zone() string {
+	return h.Metadata[ExecutorMetadataZoneKey]
+}
+
+// Headroom returns the headroom the executor reported, or 0 if it reported none or an invalid value.
+func (h HeartbeatState) Headroom() float64 {
+	headroom, err := strconv.ParseFloat(h.Metadata[ExecutorMetadataHeadroomKey], 64)
+	if err != nil || math.IsNaN(headroom) || math.IsInf(headroom, 0) || headroom <= 0 {
+		return 0
+	}
+	return headroom
+}
+
+// ExecutorLoad returns the last total load the executor reported, and false when it does not report one.
+func (h HeartbeatState) ExecutorLoad() (float64, bool) {
+	load, err := strconv.ParseFloat(h.Metadata[ExecutorMetadataLoadKey], 64)
+	if err != nil || math.IsNaN(load) || math.IsInf(load, 0) || load < 0 {
+		return 0, false
+	}
+	return load, true
+}
+
+// IsShardDraining reports whether the executor reported the shard as draining in its last heartbeat.
+func (h HeartbeatState) IsShardDraining(shardID string) bool {
+	report := h.ReportedShards[shardID]
+	return report != nil && report.Status == types.ShardStatusDRAINING
+}
+
+// Role returns the role the executor reported, executors that reported none or an invalid one are workers.
+func (h HeartbeatState) Role() types.ExecutorRole {
+	role, err := types.ExecutorRoleString(h.Metadata[ExecutorMetadataRoleKey])
+	if err != nil {
+		return types.ExecutorRoleWORKER
+	}
+	return role
+}
+
+// Labels returns the labels the executor reported, or nil if it reported none.
+func (h HeartbeatState) Labels() map[string]string {
+	var labels map[string]string
+	for key, value := range h.Metadata {
+		name, ok := strings.CutPrefix(key, ExecutorMetadataLabelPrefix)
+		if !ok {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[name] = value
+	}
+	return labels
+}
+
+// SequenceNumber returns the sequence number of the last processed heartbeat, or 0 if the executor sent none.
+func (h HeartbeatState) SequenceNumber() int64 {
+	sequence, err := strconv.ParseInt(h.Metadata[ExecutorMetadataSequenceKey], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return sequence
+}
+
+// IncarnationID returns the incarnation ID of the last processed heartbeat, or "" if the executor sent none.
+func (h HeartbeatState) IncarnationID() string {
+	return h.Metadata[ExecutorMetadataIncarnationKey]
+}
+
+// RegisteredAt returns the time of the first heartbeat of the executor, or the zero time when it is not known,
+// e.g. for executors that registered before it was recorded.
+func (h HeartbeatState) RegisteredAt() time.Time {
+	registeredAt, err := time.Parse(time.RFC3339Nano, h.Metadata[ExecutorMetadataRegisteredAtKey])
+	if err != nil {
+		return time.Time{}
+	}
+	return registeredAt
+}
+
+// InColdStart reports whether the executor registered less than grace before now. The loads it reports
+// during its cold start are unreliable, e.g. because its caches are still cold.
+func (h HeartbeatState) InColdStart(now time.Time, grace time.Duration) bool {
+	registeredAt := h.RegisteredAt()
+	return grace > 0 && !registeredAt.IsZero() && now.Before(registeredAt.Add(grace))
+}
+
+// CanOwnShards reports whether shards may be assigned to the executor, that is it is ACTIVE and not an observer.
+func (h HeartbeatState) CanOwnShards() bool {
+	return h.Status == types.ExecutorStatusACTIVE && h.Role() != types.ExecutorRoleOBSERVER
+}
+
+// IsStandby reports whether the executor is a warm standby, which is kept empty during normal operation
+// and only receives shards taken over from executors that are gone.
+func (h HeartbeatState) IsStandby() bool {
+	return h.Role() == types.ExecutorRoleSTANDBY
+}
+
 type AssignedState struct {
 	// AssignedShards holds the current assignment of shards to this executor
 	// Key: ShardID
@@ -64,11 +195,151 @@ type ShardStatistics struct {
 	// Exponential weighted moving average of shard load that persists across executor changes
 	SmoothedLoad float64
 
+	// SmoothedLoads is the exponential weighted moving average of each load dimension.
+	// SmoothedLoad is the weighted combination of these values.
+	SmoothedLoads map[string]float64
+
+	// LoadHighWatermark is a decaying maximum of the combined reported load. It jumps to a load spike
+	// and then decays towards the current load, so it exposes spiky shards that SmoothedLoad hides.
+	LoadHighWatermark float64
+
 	// LastUpdateTime is the heartbeat timestamp that last updated the smoothed load
 	LastUpdateTime time.Time
 
+	// LastReportTime is the report timestamp of the last report that updated the smoothed load.
+	// It is zero when the executor does not timestamp its reports.
+	LastReportTime time.Time
+
 	// LastMoveTime is the timestamp when this shard was last reassigned
 	LastMoveTime time.Time
+
+	// AssignmentHistory holds the last owners of the shard, oldest first
+	AssignmentHistory AssignmentHistory
+
+	// RecentLoads holds the combined loads reported within the load window, oldest first.
+	// It is only kept when a load window is configured.
+	RecentLoads LoadSamples
+
+	// WindowedLoad is the average of RecentLoads. Unlike SmoothedLoad it is not moved much
+	// by a single spike, so it reflects the sustained load over the window.
+	WindowedLoad float64
+
+	// LoadHistory holds SmoothedLoad downsampled to one sample per resolution interval, oldest first,
+	// for capacity planning. It is only kept when a load history retention is configured.
+	LoadHistory LoadSamples
+
+	// WeightOverride is a load set by an operator for a shard known to be heavy before it reports.
+	// When positive it is used instead of SmoothedLoad; set it to zero to clear the override.
+	WeightOverride float64
+
+	// PinnedExecutor is the executor an operator temporarily pinned the shard to, e.g. for a debug session.
+	// While the pin is active the shard is kept on, or placed on, that executor. The pin expires at PinExpiresAt.
+	PinnedExecutor string
+	PinExpiresAt   time.Time
+}
+
+// Load returns the load used to balance the shard: the operator's weight override when set,
+// the smoothed load otherwise.
+func (s ShardStatistics) Load() float64 {
+	if s.WeightOverride > 0 {
+		return s.WeightOverride
+	}
+	return s.SmoothedLoad
+}
+
+// ActivePin returns the executor the shard is pinned to and whether the pin is still active at now.
+// Once the pin expired the shard is balanced normally again.
+func (s ShardStatistics) ActivePin(now time.Time) (string, bool) {
+	if s.PinnedExecutor == "" || !now.Before(s.PinExpiresAt) {
+		return "", false
+	}
+	return s.PinnedExecutor, true
+}
+
+// LoadSample is a combined shard load reported at a point in time.
+type LoadSample struct {
+	Load       float64
+	ReportedAt time.Time
+}
+
+// LoadSamples is a bounded window of reported shard loads, oldest first.
+type LoadSamples []LoadSample
+
+// Append returns the samples with sample added and the samples reported more than window before it
+// removed. At most capacity samples are kept, evicting the oldest. The receiver is not modified.
+func (s LoadSamples) Append(sample LoadSample, window time.Duration, capacity int) LoadSamples {
+	if capacity <= 0 {
+		return nil
+	}
+	cutoff := sample.ReportedAt.Add(-window)
+	appended := make(LoadSamples, 0, min(len(s)+1, capacity))
+	for _, existing := range s[max(0, len(s)-capacity+1):] {
+		if existing.ReportedAt.Before(cutoff) {
+			continue
+		}
+		appended = append(appended, existing)
+	}
+	return append(appended, sample)
+}
+
+// Average returns the mean of the sampled loads, or 0 without samples.
+func (s LoadSamples) Average() float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, sample := range s {
+		total += sample.Load
+	}
+	return total / float64(len(s))
+}
+
+// Downsample returns the samples with sample added at the start of its resolution interval. A sample
+// in the same interval as the newest one replaces it, so each interval keeps its latest load. Samples
+// older than retention before sample are removed. The receiver is not modified.
+func (s LoadSamples) Downsample(sample LoadSample, resolution, retention time.Duration) LoadSamples {
+	sample.ReportedAt = sample.ReportedAt.Truncate(resolution)
+	cutoff := sample.ReportedAt.Add(-retention)
+	downsampled := make(LoadSamples, 0, len(s)+1)
+	for _, existing := range s {
+		if existing.ReportedAt.Before(cutoff) || !existing.ReportedAt.Before(sample.ReportedAt) {
+			continue
+		}
+		downsampled = append(downsampled, existing)
+	}
+	return append(downsampled, sample)
+}
+
+// ShardOwnerChange records that a shard was assigned to an executor.
+type ShardOwnerChange struct {
+	ExecutorID string
+	AssignedAt time.Time
+}
+
+// AssignmentHistory is a bounded history of the owners of a shard, oldest first.
+type AssignmentHistory []ShardOwnerChange
+
+// Append returns the history with change added. When the history already holds capacity
+// entries the oldest ones are evicted. The receiver is not modified.
+func (h AssignmentHistory) Append(change ShardOwnerChange, capacity int) AssignmentHistory {
+	if capacity <= 0 {
+		return nil
+	}
+	keep := h[max(0, len(h)-capacity+1):]
+	appended := make(AssignmentHistory, 0, len(keep)+1)
+	appended = append(appended, keep...)
+	return append(appended, change)
+}
+
+// MovesSince returns the number of owner changes at or after since.
+func (h AssignmentHistory) MovesSince(since time.Time) int {
+	moves := 0
+	for _, change := range h {
+		if !change.AssignedAt.Before(since) {
+			moves++
+		}
+	}
+	return moves
 }
 
 type ShardOwner struct {
@@ -84,3 +355,79 @@ func (ns *NamespaceState) CountExecutorsByStatus() map[types.ExecutorStatus]int
 	}
 	return counts
 }
+
+// DeletedShards returns the shards an executor reported DONE, keyed by shard ID, with the ExecutorID of
+// the reporting executor. A shard reported DONE by an executor it is no longer assigned to, while another
+// executor owns it, finished a handoff to that executor and is not deleted.
+func (ns *NamespaceState) DeletedShards() map[string]ShardState {
+	assigned := make(map[string]struct{})
+	for _, assignedState := range ns.ShardAssignments {
+		for shardID := range assignedState.AssignedShards {
+			assigned[shardID] = struct{}{}
+		}
+	}
+
+	deletedShards := make(map[string]ShardState)
+	for executorID, executor := range ns.Executors {
+		for shardID, shardState := range executor.ReportedShards {
+			if shardState.Status != types.ShardStatusDONE {
+				continue
+			}
+			_, assignedToAny := assigned[shardID]
+			_, assignedToReporter := ns.ShardAssignments[executorID].AssignedShards[shardID]
+			if assignedToAny && !assignedToReporter {
+				continue
+			}
+			deletedShards[shardID] = ShardState{ExecutorID: executorID}
+		}
+	}
+	return deletedShards
+}
+
+// ZombieShards returns the assigned shards whose statistics were last updated more than maxAge before now,
+// sorted by shard ID. No executor reported a load for such a shard for a long time although it is assigned,
+// so it may be stuck and is a candidate for a forced reassignment. Shards without statistics are skipped,
+// since statistics are only kept for greedy namespaces. A non-positive maxAge disables the detection.
+func (ns *NamespaceState) ZombieShards(now time.Time, maxAge time.Duration) []string {
+	if maxAge <= 0 {
+		return nil
+	}
+
+	var zombies []string
+	for _, assignedState := range ns.ShardAssignments {
+		for shardID := range assignedState.AssignedShards {
+			stats, ok := ns.ShardStats[shardID]
+			if !ok || stats.LastUpdateTime.IsZero() {
+				continue
+			}
+			if now.Sub(stats.LastUpdateTime) > maxAge {
+				zombies = append(zombies, shardID)
+			}
+		}
+	}
+	slices.Sort(zombies)
+	return zombies
+}
+
+// OverBudgetShards returns the shards whose smoothed load exceeds budget, sorted by descending smoothed load
+// and then by shard ID. Such shards are too hot for a single executor to carry comfortably and are candidates
+// for splitting upstream. A non-positive budget disables the detection.
+func OverBudgetShards(stats map[string]ShardStatistics, budget float64) []string {
+	if budget <= 0 {
+		return nil
+	}
+
+	var overBudget []string
+	for shardID, shardStats := range stats {
+		if shardStats.SmoothedLoad > budget {
+			overBudget = append(overBudget, shardID)
+		}
+	}
+	slices.SortFunc(overBudget, func(a, b string) int {
+		if c := cmp.Compare(stats[b].SmoothedLoad, stats[a].SmoothedLoad); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return overBudget
+}
@@ -1,10 +1,13 @@
 package store
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -66,3 +69,285 @@ func TestNamespaceState_CountExecutorsByStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignmentHistory_Append(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	change := func(i int) ShardOwnerChange {
+		return ShardOwnerChange{ExecutorID: fmt.Sprintf("exec-%d", i), AssignedAt: start.Add(time.Duration(i) * time.Minute)}
+	}
+
+	var history AssignmentHistory
+	for i := 0; i < 3; i++ {
+		history = history.Append(change(i), 3)
+	}
+	assert.Equal(t, AssignmentHistory{change(0), change(1), change(2)}, history)
+
+	// A full history evicts its oldest entry and leaves the receiver untouched.
+	appended := history.Append(change(3), 3)
+	assert.Equal(t, AssignmentHistory{change(1), change(2), change(3)}, appended)
+	assert.Equal(t, AssignmentHistory{change(0), change(1), change(2)}, history)
+
+	// Shrinking the capacity evicts every entry beyond it.
+	assert.Equal(t, AssignmentHistory{change(3), change(4)}, appended.Append(change(4), 2))
+
+	assert.Nil(t, history.Append(change(3), 0))
+}
+
+func TestAssignmentHistory_MovesSince(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := AssignmentHistory{
+		{ExecutorID: "exec-1", AssignedAt: start},
+		{ExecutorID: "exec-2", AssignedAt: start.Add(5 * time.Minute)},
+		{ExecutorID: "exec-1", AssignedAt: start.Add(10 * time.Minute)},
+	}
+
+	assert.Equal(t, 3, history.MovesSince(start))
+	assert.Equal(t, 2, history.MovesSince(start.Add(5*time.Minute)))
+	assert.Equal(t, 0, history.MovesSince(start.Add(time.Hour)))
+	assert.Equal(t, 0, AssignmentHistory(nil).MovesSince(start))
+}
+
+func TestLoadSamples_Append(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(seconds int) LoadSample {
+		return LoadSample{Load: float64(seconds), ReportedAt: start.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	var samples LoadSamples
+	for _, seconds := range []int{0, 10, 20} {
+		samples = samples.Append(sample(seconds), time.Minute, 3)
+	}
+	assert.Equal(t, LoadSamples{sample(0), sample(10), sample(20)}, samples)
+
+	// A full window evicts its oldest sample and leaves the receiver untouched.
+	appended := samples.Append(sample(30), time.Minute, 3)
+	assert.Equal(t, LoadSamples{sample(10), sample(20), sample(30)}, appended)
+	assert.Equal(t, LoadSamples{sample(0), sample(10), sample(20)}, samples)
+
+	// Samples reported before the window are dropped.
+	assert.Equal(t, LoadSamples{sample(30), sample(75)}, appended.Append(sample(75), 45*time.Second, 3))
+
+	assert.Nil(t, samples.Append(sample(30), time.Minute, 0))
+}
+
+func TestLoadSamples_Average(t *testing.T) {
+	assert.Equal(t, 0.0, LoadSamples(nil).Average())
+	assert.Equal(t, 2.0, LoadSamples{{Load: 1}, {Load: 2}, {Load: 3}}.Average())
+}
+
+func TestLoadSamples_Downsample(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	var history LoadSamples
+	history = history.Downsample(LoadSample{Load: 1, ReportedAt: at(10 * time.Second)}, time.Minute, 3*time.Minute)
+	history = history.Downsample(LoadSample{Load: 2, ReportedAt: at(50 * time.Second)}, time.Minute, 3*time.Minute)
+	assert.Equal(t, LoadSamples{{Load: 2, ReportedAt: at(0)}}, history, "the latest load of an interval is kept at its start")
+
+	history = history.Downsample(LoadSample{Load: 3, ReportedAt: at(time.Minute + 5*time.Second)}, time.Minute, 3*time.Minute)
+	history = history.Downsample(LoadSample{Load: 4, ReportedAt: at(2*time.Minute + 5*time.Second)}, time.Minute, 3*time.Minute)
+	assert.Equal(t, LoadSamples{
+		{Load: 2, ReportedAt: at(0)},
+		{Load: 3, ReportedAt: at(time.Minute)},
+		{Load: 4, ReportedAt: at(2 * time.Minute)},
+	}, history)
+
+	// Samples older than the retention are evicted, the receiver is left untouched.
+	evicted := history.Downsample(LoadSample{Load: 5, ReportedAt: at(4*time.Minute + 30*time.Second)}, time.Minute, 3*time.Minute)
+	assert.Equal(t, LoadSamples{
+		{Load: 3, ReportedAt: at(time.Minute)},
+		{Load: 4, ReportedAt: at(2 * time.Minute)},
+		{Load: 5, ReportedAt: at(4 * time.Minute)},
+	}, evicted)
+	assert.Len(t, history, 3)
+}
+
+func TestHeartbeatState_Headroom(t *testing.T) {
+	tests := map[string]struct {
+		metadata map[string]string
+		expected float64
+	}{
+		"reported":    {metadata: map[string]string{ExecutorMetadataHeadroomKey: "2.5"}, expected: 2.5},
+		"not present": {metadata: nil, expected: 0},
+		"invalid":     {metadata: map[string]string{ExecutorMetadataHeadroomKey: "lots"}, expected: 0},
+		"negative":    {metadata: map[string]string{ExecutorMetadataHeadroomKey: "-1"}, expected: 0},
+		"infinite":    {metadata: map[string]string{ExecutorMetadataHeadroomKey: "+Inf"}, expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HeartbeatState{Metadata: tt.metadata}.Headroom())
+		})
+	}
+}
+
+func TestHeartbeatState_ExecutorLoad(t *testing.T) {
+	for value, expected := range map[string]float64{"12.5": 12.5, "0": 0} {
+		load, ok := HeartbeatState{Metadata: map[string]string{ExecutorMetadataLoadKey: value}}.ExecutorLoad()
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, load, value)
+	}
+	for _, value := range []string{"", "-1", "NaN", "+Inf", "not-a-number"} {
+		_, ok := HeartbeatState{Metadata: map[string]string{ExecutorMetadataLoadKey: value}}.ExecutorLoad()
+		assert.False(t, ok, value)
+	}
+}
+
+func TestHeartbeatState_Role(t *testing.T) {
+	assert.Equal(t, types.ExecutorRoleWORKER, HeartbeatState{}.Role())
+	assert.Equal(t, types.ExecutorRoleWORKER, HeartbeatState{Metadata: map[string]string{ExecutorMetadataRoleKey: "janitor"}}.Role())
+	assert.Equal(t, types.ExecutorRoleOBSERVER, HeartbeatState{Metadata: map[string]string{ExecutorMetadataRoleKey: types.ExecutorRoleOBSERVER.String()}}.Role())
+}
+
+func TestHeartbeatState_IsShardDraining(t *testing.T) {
+	executor := HeartbeatState{ReportedShards: map[string]*types.ShardStatusReport{
+		"draining": {Status: types.ShardStatusDRAINING},
+		"ready":    {Status: types.ShardStatusREADY},
+		"nil":      nil,
+	}}
+
+	assert.True(t, executor.IsShardDraining("draining"))
+	assert.False(t, executor.IsShardDraining("ready"))
+	assert.False(t, executor.IsShardDraining("nil"))
+	assert.False(t, executor.IsShardDraining("unreported"))
+}
+
+func TestHeartbeatState_Labels(t *testing.T) {
+	assert.Nil(t, HeartbeatState{}.Labels())
+	assert.Nil(t, HeartbeatState{Metadata: map[string]string{ExecutorMetadataZoneKey: "zone-a"}}.Labels())
+	assert.Equal(t, map[string]string{"version": "v2"}, HeartbeatState{Metadata: map[string]string{
+		ExecutorMetadataZoneKey:                 "zone-a",
+		ExecutorMetadataLabelPrefix + "version": "v2",
+	}}.Labels())
+}
+
+func TestHeartbeatState_SequenceNumber(t *testing.T) {
+	assert.Zero(t, HeartbeatState{}.SequenceNumber())
+	assert.Zero(t, HeartbeatState{Metadata: map[string]string{ExecutorMetadataSequenceKey: "not-a-number"}}.SequenceNumber())
+	assert.Equal(t, int64(42), HeartbeatState{Metadata: map[string]string{ExecutorMetadataSequenceKey: "42"}}.SequenceNumber())
+}
+
+func TestHeartbeatState_IncarnationID(t *testing.T) {
+	assert.Empty(t, HeartbeatState{}.IncarnationID())
+	assert.Equal(t, "run-1", HeartbeatState{Metadata: map[string]string{ExecutorMetadataIncarnationKey: "run-1"}}.IncarnationID())
+}
+
+func TestHeartbeatState_InColdStart(t *testing.T) {
+	now := time.Date(2025, 11, 18, 12, 0, 0, 0, time.UTC)
+	executor := HeartbeatState{Metadata: map[string]string{ExecutorMetadataRegisteredAtKey: now.Format(time.RFC3339Nano)}}
+
+	assert.Equal(t, now, executor.RegisteredAt())
+	assert.True(t, executor.InColdStart(now.Add(time.Minute), 5*time.Minute))
+	assert.False(t, executor.InColdStart(now.Add(5*time.Minute), 5*time.Minute))
+	assert.False(t, executor.InColdStart(now, 0), "zero grace disables the cold start")
+	assert.False(t, HeartbeatState{}.InColdStart(now, 5*time.Minute), "executors without a registration time are not in cold start")
+}
+
+func TestHeartbeatState_CanOwnShards(t *testing.T) {
+	observer := map[string]string{ExecutorMetadataRoleKey: types.ExecutorRoleOBSERVER.String()}
+
+	assert.True(t, HeartbeatState{Status: types.ExecutorStatusACTIVE}.CanOwnShards())
+	assert.False(t, HeartbeatState{Status: types.ExecutorStatusDRAINING}.CanOwnShards())
+	assert.False(t, HeartbeatState{Status: types.ExecutorStatusACTIVE, Metadata: observer}.CanOwnShards())
+}
+
+func TestHeartbeatState_IsStandby(t *testing.T) {
+	standby := HeartbeatState{
+		Status:   types.ExecutorStatusACTIVE,
+		Metadata: map[string]string{ExecutorMetadataRoleKey: types.ExecutorRoleSTANDBY.String()},
+	}
+
+	assert.True(t, standby.IsStandby())
+	assert.True(t, standby.CanOwnShards(), "standbys own the shards they take over")
+	assert.False(t, HeartbeatState{Status: types.ExecutorStatusACTIVE}.IsStandby())
+}
+
+func TestShardStatistics_ActivePin(t *testing.T) {
+	now := time.Date(2025, 11, 18, 12, 0, 0, 0, time.UTC)
+	stats := ShardStatistics{PinnedExecutor: "exec-1", PinExpiresAt: now.Add(time.Hour)}
+
+	executorID, ok := stats.ActivePin(now)
+	assert.True(t, ok)
+	assert.Equal(t, "exec-1", executorID)
+
+	_, ok = stats.ActivePin(now.Add(time.Hour))
+	assert.False(t, ok, "the pin is released when it expires")
+	_, ok = ShardStatistics{PinExpiresAt: now.Add(time.Hour)}.ActivePin(now)
+	assert.False(t, ok, "no executor means no pin")
+}
+
+func TestNamespaceState_DeletedShards(t *testing.T) {
+	done := &types.ShardStatusReport{Status: types.ShardStatusDONE}
+	state := &NamespaceState{
+		Executors: map[string]HeartbeatState{
+			"exec-1": {ReportedShards: map[string]*types.ShardStatusReport{
+				"shard-released":   done,
+				"shard-unassigned": done,
+				"shard-handed-off": done,
+				"shard-ready":      {Status: types.ShardStatusREADY},
+			}},
+			"exec-2": {ReportedShards: map[string]*types.ShardStatusReport{
+				"shard-handed-off": {Status: types.ShardStatusREADY},
+			}},
+		},
+		ShardAssignments: map[string]AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"shard-released": {}, "shard-ready": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"shard-handed-off": {}}},
+		},
+	}
+
+	assert.Equal(t, map[string]ShardState{
+		"shard-released":   {ExecutorID: "exec-1"},
+		"shard-unassigned": {ExecutorID: "exec-1"},
+	}, state.DeletedShards(), "a shard handed off to exec-2 is not deleted")
+}
+
+func TestNamespaceState_ZombieShards(t *testing.T) {
+	timeSource := clock.NewMockedTimeSource()
+	lastUpdate := timeSource.Now()
+	state := &NamespaceState{
+		ShardAssignments: map[string]AssignedState{
+			"exec-1": {AssignedShards: map[string]*types.ShardAssignment{"shard-b": {}, "shard-fresh": {}, "shard-no-stats": {}}},
+			"exec-2": {AssignedShards: map[string]*types.ShardAssignment{"shard-a": {}, "shard-zero": {}}},
+		},
+		ShardStats: map[string]ShardStatistics{
+			"shard-a":     {LastUpdateTime: lastUpdate},
+			"shard-b":     {LastUpdateTime: lastUpdate},
+			"shard-fresh": {LastUpdateTime: lastUpdate.Add(time.Minute)},
+			"shard-zero":  {},
+			// Statistics of a shard that is no longer assigned are ignored.
+			"shard-unassigned": {LastUpdateTime: lastUpdate},
+		},
+	}
+	const maxAge = 10 * time.Minute
+
+	timeSource.Advance(maxAge - time.Second)
+	assert.Empty(t, state.ZombieShards(timeSource.Now(), maxAge), "younger than the max age")
+
+	timeSource.Advance(time.Second)
+	assert.Empty(t, state.ZombieShards(timeSource.Now(), maxAge), "exactly the max age")
+
+	timeSource.Advance(time.Nanosecond)
+	assert.Equal(t, []string{"shard-a", "shard-b"}, state.ZombieShards(timeSource.Now(), maxAge))
+
+	timeSource.Advance(time.Minute)
+	assert.Equal(t, []string{"shard-a", "shard-b", "shard-fresh"}, state.ZombieShards(timeSource.Now(), maxAge))
+
+	assert.Nil(t, state.ZombieShards(timeSource.Now(), 0), "disabled")
+}
+
+func TestOverBudgetShards(t *testing.T) {
+	stats := map[string]ShardStatistics{
+		"shard-a":    {SmoothedLoad: 12},
+		"shard-b":    {SmoothedLoad: 30},
+		"shard-c":    {SmoothedLoad: 12},
+		"shard-cold": {SmoothedLoad: 2},
+		// A shard exactly at the budget is not over it.
+		"shard-at-budget": {SmoothedLoad: 10},
+	}
+
+	assert.Equal(t, []string{"shard-b", "shard-a", "shard-c"}, OverBudgetShards(stats, 10))
+	assert.Empty(t, OverBudgetShards(stats, 100), "no shard above the budget")
+	assert.Empty(t, OverBudgetShards(nil, 10), "no statistics")
+	assert.Empty(t, OverBudgetShards(map[string]ShardStatistics{}, 10), "no statistics")
+	assert.Nil(t, OverBudgetShards(stats, 0), "disabled")
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 //go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination=store_mock.go Store
@@ -20,6 +21,17 @@ var (
 
 	// ErrExecutorNotRunning is an error that is returned when shard is attempted to be assigned to a not running executor.
 	ErrExecutorNotRunning = fmt.Errorf("executor not running")
+
+	// ErrReadOnly is an error that is returned when a write is rejected because the storage only serves reads,
+	// e.g. during maintenance. Reads are expected to keep working.
+	ErrReadOnly = fmt.Errorf("store is read-only")
+
+	// ErrLeadershipHeld is an error that is returned when leadership of a namespace is acquired while another lease holds it.
+	ErrLeadershipHeld = fmt.Errorf("leadership held by another lease")
+
+	// ErrLeadershipLost is an error that is returned when a leadership lease is renewed after it expired or was released,
+	// and when a guarded write is fenced off because the leadership it was guarded by changed.
+	ErrLeadershipLost = fmt.Errorf("leadership lost")
 )
 
 type ErrShardAlreadyAssigned struct {
@@ -32,6 +44,23 @@ func (e *ErrShardAlreadyAssigned) Error() string {
 	return fmt.Sprintf("shard %s is already assigned to %s", e.ShardID, e.AssignedTo)
 }
 
+// ErrHeartbeatPartiallyApplied is an error that is returned when a heartbeat was recorded, but the shard
+// statistics derived from its reports could not be updated, so the stored heartbeat and statistics
+// disagree until the heartbeat is recorded again.
+type ErrHeartbeatPartiallyApplied struct {
+	Namespace  string
+	ExecutorID string
+	Err        error
+}
+
+func (e *ErrHeartbeatPartiallyApplied) Error() string {
+	return fmt.Sprintf("heartbeat of executor %s in namespace %s partially applied: %v", e.ExecutorID, e.Namespace, e.Err)
+}
+
+func (e *ErrHeartbeatPartiallyApplied) Unwrap() error {
+	return e.Err
+}
+
 // Txn represents a generic, backend-agnostic transaction.
 // It is used as a vehicle for the GuardFunc to operate on.
 type Txn interface{}
@@ -63,14 +92,25 @@ type Store interface {
 	// shard statistics, and shard assignments.
 	GetState(ctx context.Context, namespace string) (*NamespaceState, error)
 
+	// GetShardStats retrieves the statistics of the given shards only, keyed by shard ID. It is cheaper than
+	// GetState for callers that need a few shards of a large namespace. Shards without an owner or without
+	// statistics are left out of the result.
+	GetShardStats(ctx context.Context, namespace string, shardIDs []string) (map[string]ShardStatistics, error)
+
 	// AssignShards assigns multiple shards to executors within a namespace.
 	// It also updates shard statistics and deletes specified executors
 	// The operation is atomic and guarded by the provided GuardFunc.
+	// Shards that change owner must have their LastMoveTime set to the time of the assignment in the
+	// same operation, the per-shard move cooldown of the load balancer relies on it.
 	AssignShards(ctx context.Context, namespace string, request AssignShardsRequest, guard GuardFunc) error
 
 	// AssignShard assigns a single shard to an executor within a namespace.
 	AssignShard(ctx context.Context, namespace string, shardID string, executorID string) error
 
+	// ReleaseShards removes shards from the assigned state of an executor, so the leader can reassign or
+	// retire them. Shards that are not assigned to the executor are ignored, releasing them again is a no-op.
+	ReleaseShards(ctx context.Context, namespace string, executorID string, shardIDs []string) error
+
 	// SubscribeToExecutorStatusChanges subscribes to changes of executors' status key within a namespace.
 	SubscribeToExecutorStatusChanges(ctx context.Context, namespace string) (<-chan int64, error)
 	DeleteExecutors(ctx context.Context, namespace string, executorIDs []string, guard GuardFunc) error
@@ -81,6 +121,10 @@ type Store interface {
 	// GetShardOwner retrieves the owner of a specific shard within a namespace.
 	// It returns ErrShardNotFound if the shard does not exist.
 	GetShardOwner(ctx context.Context, namespace, shardID string) (*ShardOwner, error)
+
+	// GetShardOwners retrieves the owners of the given shards, keyed by shard ID. Unlike calling GetShardOwner
+	// per shard, it refreshes its view of the namespace at most once. Shards without an owner are left out.
+	GetShardOwners(ctx context.Context, namespace string, shardIDs []string) (map[string]*ShardOwner, error)
 	SubscribeToAssignmentChanges(ctx context.Context, namespace string) (<-chan map[*ShardOwner][]string, func(), error)
 
 	// GetExecutor retrieves an executor within a namespace.
@@ -90,4 +134,13 @@ type Store interface {
 	RecordHeartbeat(ctx context.Context, namespace, executorID string, state HeartbeatState) error
 
 	DeleteShardStats(ctx context.Context, namespace string, shardIDs []string, guard GuardFunc) error
+
+	// SetShardWeightOverride sets the load the shard is balanced with instead of its reported load, e.g. for a
+	// shard known to be heavy before it reports. A zero weight clears the override. It returns ErrShardNotFound
+	// when the shard is not assigned.
+	SetShardWeightOverride(ctx context.Context, namespace, shardID string, weight float64) error
+
+	// SetShardPin pins the shard to the executor until expiresAt, or clears the pin when executorID is empty.
+	// The write is fenced by guard. It returns ErrShardNotFound when the shard is not assigned.
+	SetShardPin(ctx context.Context, namespace, shardID, executorID string, expiresAt time.Time, guard GuardFunc) error
 }
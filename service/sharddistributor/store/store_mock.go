@@ -12,6 +12,7 @@ package store
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -180,6 +181,36 @@ func (mr *MockStoreMockRecorder) GetShardOwner(ctx, namespace, shardID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardOwner", reflect.TypeOf((*MockStore)(nil).GetShardOwner), ctx, namespace, shardID)
 }
 
+// GetShardOwners mocks base method.
+func (m *MockStore) GetShardOwners(ctx context.Context, namespace string, shardIDs []string) (map[string]*ShardOwner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardOwners", ctx, namespace, shardIDs)
+	ret0, _ := ret[0].(map[string]*ShardOwner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShardOwners indicates an expected call of GetShardOwners.
+func (mr *MockStoreMockRecorder) GetShardOwners(ctx, namespace, shardIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardOwners", reflect.TypeOf((*MockStore)(nil).GetShardOwners), ctx, namespace, shardIDs)
+}
+
+// GetShardStats mocks base method.
+func (m *MockStore) GetShardStats(ctx context.Context, namespace string, shardIDs []string) (map[string]ShardStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardStats", ctx, namespace, shardIDs)
+	ret0, _ := ret[0].(map[string]ShardStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShardStats indicates an expected call of GetShardStats.
+func (mr *MockStoreMockRecorder) GetShardStats(ctx, namespace, shardIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardStats", reflect.TypeOf((*MockStore)(nil).GetShardStats), ctx, namespace, shardIDs)
+}
+
 // GetState mocks base method.
 func (m *MockStore) GetState(ctx context.Context, namespace string) (*NamespaceState, error) {
 	m.ctrl.T.Helper()
@@ -209,6 +240,48 @@ func (mr *MockStoreMockRecorder) RecordHeartbeat(ctx, namespace, executorID, sta
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHeartbeat", reflect.TypeOf((*MockStore)(nil).RecordHeartbeat), ctx, namespace, executorID, state)
 }
 
+// ReleaseShards mocks base method.
+func (m *MockStore) ReleaseShards(ctx context.Context, namespace, executorID string, shardIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseShards", ctx, namespace, executorID, shardIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseShards indicates an expected call of ReleaseShards.
+func (mr *MockStoreMockRecorder) ReleaseShards(ctx, namespace, executorID, shardIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseShards", reflect.TypeOf((*MockStore)(nil).ReleaseShards), ctx, namespace, executorID, shardIDs)
+}
+
+// SetShardPin mocks base method.
+func (m *MockStore) SetShardPin(ctx context.Context, namespace, shardID, executorID string, expiresAt time.Time, guard GuardFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShardPin", ctx, namespace, shardID, executorID, expiresAt, guard)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetShardPin indicates an expected call of SetShardPin.
+func (mr *MockStoreMockRecorder) SetShardPin(ctx, namespace, shardID, executorID, expiresAt, guard any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShardPin", reflect.TypeOf((*MockStore)(nil).SetShardPin), ctx, namespace, shardID, executorID, expiresAt, guard)
+}
+
+// SetShardWeightOverride mocks base method.
+func (m *MockStore) SetShardWeightOverride(ctx context.Context, namespace, shardID string, weight float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShardWeightOverride", ctx, namespace, shardID, weight)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetShardWeightOverride indicates an expected call of SetShardWeightOverride.
+func (mr *MockStoreMockRecorder) SetShardWeightOverride(ctx, namespace, shardID, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShardWeightOverride", reflect.TypeOf((*MockStore)(nil).SetShardWeightOverride), ctx, namespace, shardID, weight)
+}
+
 // SubscribeToAssignmentChanges mocks base method.
 func (m *MockStore) SubscribeToAssignmentChanges(ctx context.Context, namespace string) (<-chan map[*ShardOwner][]string, func(), error) {
 	m.ctrl.T.Helper()
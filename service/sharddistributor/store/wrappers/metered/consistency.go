@@ -0,0 +1,26 @@
+package metered
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/service/sharddistributor/store"
+)
+
+var _ store.HeartbeatTokenRecorder = (*meteredStore)(nil)
+
+// RecordHeartbeatWithToken forwards to the wrapped store when it returns consistency tokens. Otherwise it
+// records the heartbeat with RecordHeartbeat and returns the empty token.
+func (c *meteredStore) RecordHeartbeatWithToken(ctx context.Context, namespace string, executorID string, state store.HeartbeatState) (token store.ConsistencyToken, err error) {
+	op := func() error {
+		if recorder, ok := c.wrapped.(store.HeartbeatTokenRecorder); ok {
+			token, err = recorder.RecordHeartbeatWithToken(ctx, namespace, executorID, state)
+			return err
+		}
+		err = c.wrapped.RecordHeartbeat(ctx, namespace, executorID, state)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreRecordHeartbeatScope, op, metrics.NamespaceTag(namespace))
+	return
+}
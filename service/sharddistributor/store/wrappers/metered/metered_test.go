@@ -83,3 +83,50 @@ func TestMeteredStore_GetHeartbeat(t *testing.T) {
 		})
 	}
 }
+
+// tokenStore is a store that returns consistency tokens for recorded heartbeats.
+type tokenStore struct {
+	*store.MockStore
+}
+
+func (s *tokenStore) RecordHeartbeatWithToken(ctx context.Context, namespace, executorID string, state store.HeartbeatState) (store.ConsistencyToken, error) {
+	return "revision-42", s.RecordHeartbeat(ctx, namespace, executorID, state)
+}
+
+func TestMeteredStore_RecordHeartbeatWithToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		newStore      func(*store.MockStore) store.Store
+		expectedToken store.ConsistencyToken
+	}{
+		{
+			name:          "Forwards to a store returning tokens",
+			newStore:      func(mockStore *store.MockStore) store.Store { return &tokenStore{MockStore: mockStore} },
+			expectedToken: "revision-42",
+		},
+		{
+			name:     "Falls back to RecordHeartbeat",
+			newStore: func(mockStore *store.MockStore) store.Store { return mockStore },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			testScope := tally.NewTestScope("test", nil)
+			metricsClient := metrics.NewClient(testScope, metrics.ShardDistributor, metrics.MigrationConfig{})
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().RecordHeartbeat(gomock.Any(), _testNamespace, _testExecutorID, store.HeartbeatState{}).Return(nil)
+
+			wrapped := NewStore(tt.newStore(mockStore), metricsClient, log.NewNoop(), clock.NewMockedTimeSource()).(store.HeartbeatTokenRecorder)
+			token, err := wrapped.RecordHeartbeatWithToken(context.Background(), _testNamespace, _testExecutorID, store.HeartbeatState{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, token)
+
+			requests, ok := testScope.Snapshot().Counters()["test.shard_distributor_store_requests_per_namespace+namespace=test_namespace,operation=StoreRecordHeartbeat"]
+			if assert.True(t, ok) {
+				assert.Equal(t, int64(1), requests.Value())
+			}
+		})
+	}
+}
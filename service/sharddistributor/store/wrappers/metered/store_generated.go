@@ -6,6 +6,7 @@ package metered
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log"
@@ -119,6 +120,26 @@ func (c *meteredStore) GetShardOwner(ctx context.Context, namespace string, shar
 	return
 }
 
+func (c *meteredStore) GetShardOwners(ctx context.Context, namespace string, shardIDs []string) (m1 map[string]*store.ShardOwner, err error) {
+	op := func() error {
+		m1, err = c.wrapped.GetShardOwners(ctx, namespace, shardIDs)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreGetShardOwnersScope, op, metrics.NamespaceTag(namespace))
+	return
+}
+
+func (c *meteredStore) GetShardStats(ctx context.Context, namespace string, shardIDs []string) (m1 map[string]store.ShardStatistics, err error) {
+	op := func() error {
+		m1, err = c.wrapped.GetShardStats(ctx, namespace, shardIDs)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreGetShardStatsScope, op, metrics.NamespaceTag(namespace))
+	return
+}
+
 func (c *meteredStore) GetState(ctx context.Context, namespace string) (np1 *store.NamespaceState, err error) {
 	op := func() error {
 		np1, err = c.wrapped.GetState(ctx, namespace)
@@ -139,6 +160,36 @@ func (c *meteredStore) RecordHeartbeat(ctx context.Context, namespace string, ex
 	return
 }
 
+func (c *meteredStore) ReleaseShards(ctx context.Context, namespace string, executorID string, shardIDs []string) (err error) {
+	op := func() error {
+		err = c.wrapped.ReleaseShards(ctx, namespace, executorID, shardIDs)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreReleaseShardsScope, op, metrics.NamespaceTag(namespace))
+	return
+}
+
+func (c *meteredStore) SetShardPin(ctx context.Context, namespace string, shardID string, executorID string, expiresAt time.Time, guard store.GuardFunc) (err error) {
+	op := func() error {
+		err = c.wrapped.SetShardPin(ctx, namespace, shardID, executorID, expiresAt, guard)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreSetShardPinScope, op, metrics.NamespaceTag(namespace))
+	return
+}
+
+func (c *meteredStore) SetShardWeightOverride(ctx context.Context, namespace string, shardID string, weight float64) (err error) {
+	op := func() error {
+		err = c.wrapped.SetShardWeightOverride(ctx, namespace, shardID, weight)
+		return err
+	}
+
+	err = c.call(metrics.ShardDistributorStoreSetShardWeightOverrideScope, op, metrics.NamespaceTag(namespace))
+	return
+}
+
 func (c *meteredStore) SubscribeToAssignmentChanges(ctx context.Context, namespace string) (ch1 <-chan map[*store.ShardOwner][]string, f1 func(), err error) {
 	op := func() error {
 		ch1, f1, err = c.wrapped.SubscribeToAssignmentChanges(ctx, namespace)
@@ -12,6 +12,8 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/sharddistributor/handler"
+	"github.com/uber/cadence/service/sharddistributor/loadbalancer/plan"
+	"github.com/uber/cadence/service/sharddistributor/store"
 )
 
 type metricsHandler struct {
@@ -28,6 +30,63 @@ func NewMetricsHandler(handler handler.Handler, logger log.Logger, metricsClient
 	}
 }
 
+func (h *metricsHandler) ExplainUnassigned(ctx context.Context, namespace string, shardID string) (u1 plan.UnassignedReason, err error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &err) }()
+
+	scope := h.metricsClient.Scope(metrics.ShardDistributorExplainUnassignedScope)
+	scope = scope.Tagged(metrics.NamespaceTag(namespace))
+	scope.IncCounter(metrics.ShardDistributorRequests)
+	sw := scope.StartTimer(metrics.ShardDistributorLatency)
+	defer sw.Stop()
+	logger := h.logger.WithTags(tag.ShardNamespace(namespace))
+
+	u1, err = h.handler.ExplainUnassigned(ctx, namespace, shardID)
+
+	if err != nil {
+		handleErr(err, scope, logger)
+	}
+
+	return u1, err
+}
+
+func (h *metricsHandler) ForceAssign(ctx context.Context, namespace string, assignments map[string]string) (err error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &err) }()
+
+	scope := h.metricsClient.Scope(metrics.ShardDistributorForceAssignScope)
+	scope = scope.Tagged(metrics.NamespaceTag(namespace))
+	scope.IncCounter(metrics.ShardDistributorRequests)
+	sw := scope.StartTimer(metrics.ShardDistributorLatency)
+	defer sw.Stop()
+	logger := h.logger.WithTags(tag.ShardNamespace(namespace))
+
+	err = h.handler.ForceAssign(ctx, namespace, assignments)
+
+	if err != nil {
+		handleErr(err, scope, logger)
+	}
+
+	return err
+}
+
+func (h *metricsHandler) GetShardLoadHistory(ctx context.Context, namespace string, shardID string) (l1 store.LoadSamples, err error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &err) }()
+
+	scope := h.metricsClient.Scope(metrics.ShardDistributorGetShardLoadHistoryScope)
+	scope = scope.Tagged(metrics.NamespaceTag(namespace))
+	scope.IncCounter(metrics.ShardDistributorRequests)
+	sw := scope.StartTimer(metrics.ShardDistributorLatency)
+	defer sw.Stop()
+	logger := h.logger.WithTags(tag.ShardNamespace(namespace))
+
+	l1, err = h.handler.GetShardLoadHistory(ctx, namespace, shardID)
+
+	if err != nil {
+		handleErr(err, scope, logger)
+	}
+
+	return l1, err
+}
+
 func (h *metricsHandler) GetShardOwner(ctx context.Context, gp1 *types.GetShardOwnerRequest) (gp2 *types.GetShardOwnerResponse, err error) {
 	defer func() { log.CapturePanic(recover(), h.logger, &err) }()
 
@@ -51,6 +110,25 @@ func (h *metricsHandler) Health(ctx context.Context) (hp1 *types.HealthStatus, e
 	return h.handler.Health(ctx)
 }
 
+func (h *metricsHandler) PlanExecutorRemoval(ctx context.Context, namespace string, executorID string) (ma1 []plan.Move, err error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &err) }()
+
+	scope := h.metricsClient.Scope(metrics.ShardDistributorPlanExecutorRemovalScope)
+	scope = scope.Tagged(metrics.NamespaceTag(namespace))
+	scope.IncCounter(metrics.ShardDistributorRequests)
+	sw := scope.StartTimer(metrics.ShardDistributorLatency)
+	defer sw.Stop()
+	logger := h.logger.WithTags(tag.ShardNamespace(namespace))
+
+	ma1, err = h.handler.PlanExecutorRemoval(ctx, namespace, executorID)
+
+	if err != nil {
+		handleErr(err, scope, logger)
+	}
+
+	return ma1, err
+}
+
 func (h *metricsHandler) Start() {
 	h.handler.Start()
 	return